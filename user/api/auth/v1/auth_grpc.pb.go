@@ -19,11 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AuthService_SendRegisterCode_FullMethodName = "/auth.v1.AuthService/SendRegisterCode"
-	AuthService_Register_FullMethodName         = "/auth.v1.AuthService/Register"
-	AuthService_Login_FullMethodName            = "/auth.v1.AuthService/Login"
-	AuthService_RefreshToken_FullMethodName     = "/auth.v1.AuthService/RefreshToken"
-	AuthService_Logout_FullMethodName           = "/auth.v1.AuthService/Logout"
+	AuthService_SendRegisterCode_FullMethodName      = "/auth.v1.AuthService/SendRegisterCode"
+	AuthService_GetRegisterCodeStatus_FullMethodName = "/auth.v1.AuthService/GetRegisterCodeStatus"
+	AuthService_ExtendRegisterCode_FullMethodName    = "/auth.v1.AuthService/ExtendRegisterCode"
+	AuthService_Register_FullMethodName              = "/auth.v1.AuthService/Register"
+	AuthService_Login_FullMethodName                 = "/auth.v1.AuthService/Login"
+	AuthService_RefreshToken_FullMethodName          = "/auth.v1.AuthService/RefreshToken"
+	AuthService_Logout_FullMethodName                = "/auth.v1.AuthService/Logout"
+	AuthService_GetTokenExpiry_FullMethodName        = "/auth.v1.AuthService/GetTokenExpiry"
 )
 
 // AuthServiceClient is the client API for AuthService service.
@@ -34,6 +37,10 @@ const (
 type AuthServiceClient interface {
 	// 发送注册邮箱验证码
 	SendRegisterCode(ctx context.Context, in *SendRegisterCodeRequest, opts ...grpc.CallOption) (*SendRegisterCodeResponse, error)
+	// 查询注册验证码状态（是否存在及剩余有效秒数），用于前端倒计时
+	GetRegisterCodeStatus(ctx context.Context, in *GetRegisterCodeStatusRequest, opts ...grpc.CallOption) (*GetRegisterCodeStatusResponse, error)
+	// 延长注册验证码有效期，验证码本身不变，用于用户输入验证码前即将超时的场景
+	ExtendRegisterCode(ctx context.Context, in *ExtendRegisterCodeRequest, opts ...grpc.CallOption) (*ExtendRegisterCodeResponse, error)
 	// 用户注册
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 	// 用户登录
@@ -42,6 +49,8 @@ type AuthServiceClient interface {
 	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
 	// 用户登出
 	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	// 查询访问令牌剩余有效期及是否应提前刷新，供移动端主动刷新令牌
+	GetTokenExpiry(ctx context.Context, in *GetTokenExpiryRequest, opts ...grpc.CallOption) (*GetTokenExpiryResponse, error)
 }
 
 type authServiceClient struct {
@@ -62,6 +71,26 @@ func (c *authServiceClient) SendRegisterCode(ctx context.Context, in *SendRegist
 	return out, nil
 }
 
+func (c *authServiceClient) GetRegisterCodeStatus(ctx context.Context, in *GetRegisterCodeStatusRequest, opts ...grpc.CallOption) (*GetRegisterCodeStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRegisterCodeStatusResponse)
+	err := c.cc.Invoke(ctx, AuthService_GetRegisterCodeStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ExtendRegisterCode(ctx context.Context, in *ExtendRegisterCodeRequest, opts ...grpc.CallOption) (*ExtendRegisterCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtendRegisterCodeResponse)
+	err := c.cc.Invoke(ctx, AuthService_ExtendRegisterCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authServiceClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterResponse)
@@ -102,6 +131,16 @@ func (c *authServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts
 	return out, nil
 }
 
+func (c *authServiceClient) GetTokenExpiry(ctx context.Context, in *GetTokenExpiryRequest, opts ...grpc.CallOption) (*GetTokenExpiryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTokenExpiryResponse)
+	err := c.cc.Invoke(ctx, AuthService_GetTokenExpiry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
@@ -110,6 +149,10 @@ func (c *authServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts
 type AuthServiceServer interface {
 	// 发送注册邮箱验证码
 	SendRegisterCode(context.Context, *SendRegisterCodeRequest) (*SendRegisterCodeResponse, error)
+	// 查询注册验证码状态（是否存在及剩余有效秒数），用于前端倒计时
+	GetRegisterCodeStatus(context.Context, *GetRegisterCodeStatusRequest) (*GetRegisterCodeStatusResponse, error)
+	// 延长注册验证码有效期，验证码本身不变，用于用户输入验证码前即将超时的场景
+	ExtendRegisterCode(context.Context, *ExtendRegisterCodeRequest) (*ExtendRegisterCodeResponse, error)
 	// 用户注册
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	// 用户登录
@@ -118,6 +161,8 @@ type AuthServiceServer interface {
 	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
 	// 用户登出
 	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	// 查询访问令牌剩余有效期及是否应提前刷新，供移动端主动刷新令牌
+	GetTokenExpiry(context.Context, *GetTokenExpiryRequest) (*GetTokenExpiryResponse, error)
 	mustEmbedUnimplementedAuthServiceServer()
 }
 
@@ -131,6 +176,12 @@ type UnimplementedAuthServiceServer struct{}
 func (UnimplementedAuthServiceServer) SendRegisterCode(context.Context, *SendRegisterCodeRequest) (*SendRegisterCodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendRegisterCode not implemented")
 }
+func (UnimplementedAuthServiceServer) GetRegisterCodeStatus(context.Context, *GetRegisterCodeStatusRequest) (*GetRegisterCodeStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegisterCodeStatus not implemented")
+}
+func (UnimplementedAuthServiceServer) ExtendRegisterCode(context.Context, *ExtendRegisterCodeRequest) (*ExtendRegisterCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendRegisterCode not implemented")
+}
 func (UnimplementedAuthServiceServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
@@ -143,6 +194,9 @@ func (UnimplementedAuthServiceServer) RefreshToken(context.Context, *RefreshToke
 func (UnimplementedAuthServiceServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
 }
+func (UnimplementedAuthServiceServer) GetTokenExpiry(context.Context, *GetTokenExpiryRequest) (*GetTokenExpiryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenExpiry not implemented")
+}
 func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
 func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
 
@@ -182,6 +236,42 @@ func _AuthService_SendRegisterCode_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_GetRegisterCodeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegisterCodeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).GetRegisterCodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_GetRegisterCodeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).GetRegisterCodeStatus(ctx, req.(*GetRegisterCodeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ExtendRegisterCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendRegisterCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ExtendRegisterCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ExtendRegisterCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ExtendRegisterCode(ctx, req.(*ExtendRegisterCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthService_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterRequest)
 	if err := dec(in); err != nil {
@@ -254,6 +344,24 @@ func _AuthService_Logout_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_GetTokenExpiry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenExpiryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).GetTokenExpiry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_GetTokenExpiry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).GetTokenExpiry(ctx, req.(*GetTokenExpiryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -265,6 +373,14 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendRegisterCode",
 			Handler:    _AuthService_SendRegisterCode_Handler,
 		},
+		{
+			MethodName: "GetRegisterCodeStatus",
+			Handler:    _AuthService_GetRegisterCodeStatus_Handler,
+		},
+		{
+			MethodName: "ExtendRegisterCode",
+			Handler:    _AuthService_ExtendRegisterCode_Handler,
+		},
 		{
 			MethodName: "Register",
 			Handler:    _AuthService_Register_Handler,
@@ -281,6 +397,10 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Logout",
 			Handler:    _AuthService_Logout_Handler,
 		},
+		{
+			MethodName: "GetTokenExpiry",
+			Handler:    _AuthService_GetTokenExpiry_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth/v1/auth.proto",
@@ -19,6 +19,9 @@ var _ = binding.EncodeURL
 
 const _ = http.SupportPackageIsVersion1
 
+const OperationAuthServiceExtendRegisterCode = "/auth.v1.AuthService/ExtendRegisterCode"
+const OperationAuthServiceGetRegisterCodeStatus = "/auth.v1.AuthService/GetRegisterCodeStatus"
+const OperationAuthServiceGetTokenExpiry = "/auth.v1.AuthService/GetTokenExpiry"
 const OperationAuthServiceLogin = "/auth.v1.AuthService/Login"
 const OperationAuthServiceLogout = "/auth.v1.AuthService/Logout"
 const OperationAuthServiceRefreshToken = "/auth.v1.AuthService/RefreshToken"
@@ -26,6 +29,12 @@ const OperationAuthServiceRegister = "/auth.v1.AuthService/Register"
 const OperationAuthServiceSendRegisterCode = "/auth.v1.AuthService/SendRegisterCode"
 
 type AuthServiceHTTPServer interface {
+	// ExtendRegisterCode 延长注册验证码有效期，验证码本身不变，用于用户输入验证码前即将超时的场景
+	ExtendRegisterCode(context.Context, *ExtendRegisterCodeRequest) (*ExtendRegisterCodeResponse, error)
+	// GetRegisterCodeStatus 查询注册验证码状态（是否存在及剩余有效秒数），用于前端倒计时
+	GetRegisterCodeStatus(context.Context, *GetRegisterCodeStatusRequest) (*GetRegisterCodeStatusResponse, error)
+	// GetTokenExpiry 查询访问令牌剩余有效期及是否应提前刷新，供移动端主动刷新令牌
+	GetTokenExpiry(context.Context, *GetTokenExpiryRequest) (*GetTokenExpiryResponse, error)
 	// Login 用户登录
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
 	// Logout 用户登出
@@ -41,10 +50,13 @@ type AuthServiceHTTPServer interface {
 func RegisterAuthServiceHTTPServer(s *http.Server, srv AuthServiceHTTPServer) {
 	r := s.Route("/")
 	r.POST("/v1/auth/send-code", _AuthService_SendRegisterCode0_HTTP_Handler(srv))
+	r.GET("/v1/auth/code-status", _AuthService_GetRegisterCodeStatus0_HTTP_Handler(srv))
+	r.POST("/v1/auth/code-status/extend", _AuthService_ExtendRegisterCode0_HTTP_Handler(srv))
 	r.POST("/v1/auth/register", _AuthService_Register0_HTTP_Handler(srv))
 	r.POST("/v1/auth/login", _AuthService_Login0_HTTP_Handler(srv))
 	r.POST("/v1/auth/refresh", _AuthService_RefreshToken0_HTTP_Handler(srv))
 	r.POST("/v1/auth/logout", _AuthService_Logout0_HTTP_Handler(srv))
+	r.POST("/v1/auth/token-expiry", _AuthService_GetTokenExpiry0_HTTP_Handler(srv))
 }
 
 func _AuthService_SendRegisterCode0_HTTP_Handler(srv AuthServiceHTTPServer) func(ctx http.Context) error {
@@ -69,6 +81,47 @@ func _AuthService_SendRegisterCode0_HTTP_Handler(srv AuthServiceHTTPServer) func
 	}
 }
 
+func _AuthService_GetRegisterCodeStatus0_HTTP_Handler(srv AuthServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetRegisterCodeStatusRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationAuthServiceGetRegisterCodeStatus)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetRegisterCodeStatus(ctx, req.(*GetRegisterCodeStatusRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*GetRegisterCodeStatusResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _AuthService_ExtendRegisterCode0_HTTP_Handler(srv AuthServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ExtendRegisterCodeRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationAuthServiceExtendRegisterCode)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ExtendRegisterCode(ctx, req.(*ExtendRegisterCodeRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ExtendRegisterCodeResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
 func _AuthService_Register0_HTTP_Handler(srv AuthServiceHTTPServer) func(ctx http.Context) error {
 	return func(ctx http.Context) error {
 		var in RegisterRequest
@@ -157,7 +210,35 @@ func _AuthService_Logout0_HTTP_Handler(srv AuthServiceHTTPServer) func(ctx http.
 	}
 }
 
+func _AuthService_GetTokenExpiry0_HTTP_Handler(srv AuthServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetTokenExpiryRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationAuthServiceGetTokenExpiry)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetTokenExpiry(ctx, req.(*GetTokenExpiryRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*GetTokenExpiryResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
 type AuthServiceHTTPClient interface {
+	// ExtendRegisterCode 延长注册验证码有效期，验证码本身不变，用于用户输入验证码前即将超时的场景
+	ExtendRegisterCode(ctx context.Context, req *ExtendRegisterCodeRequest, opts ...http.CallOption) (rsp *ExtendRegisterCodeResponse, err error)
+	// GetRegisterCodeStatus 查询注册验证码状态（是否存在及剩余有效秒数），用于前端倒计时
+	GetRegisterCodeStatus(ctx context.Context, req *GetRegisterCodeStatusRequest, opts ...http.CallOption) (rsp *GetRegisterCodeStatusResponse, err error)
+	// GetTokenExpiry 查询访问令牌剩余有效期及是否应提前刷新，供移动端主动刷新令牌
+	GetTokenExpiry(ctx context.Context, req *GetTokenExpiryRequest, opts ...http.CallOption) (rsp *GetTokenExpiryResponse, err error)
 	// Login 用户登录
 	Login(ctx context.Context, req *LoginRequest, opts ...http.CallOption) (rsp *LoginResponse, err error)
 	// Logout 用户登出
@@ -178,6 +259,48 @@ func NewAuthServiceHTTPClient(client *http.Client) AuthServiceHTTPClient {
 	return &AuthServiceHTTPClientImpl{client}
 }
 
+// ExtendRegisterCode 延长注册验证码有效期，验证码本身不变，用于用户输入验证码前即将超时的场景
+func (c *AuthServiceHTTPClientImpl) ExtendRegisterCode(ctx context.Context, in *ExtendRegisterCodeRequest, opts ...http.CallOption) (*ExtendRegisterCodeResponse, error) {
+	var out ExtendRegisterCodeResponse
+	pattern := "/v1/auth/code-status/extend"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationAuthServiceExtendRegisterCode))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRegisterCodeStatus 查询注册验证码状态（是否存在及剩余有效秒数），用于前端倒计时
+func (c *AuthServiceHTTPClientImpl) GetRegisterCodeStatus(ctx context.Context, in *GetRegisterCodeStatusRequest, opts ...http.CallOption) (*GetRegisterCodeStatusResponse, error) {
+	var out GetRegisterCodeStatusResponse
+	pattern := "/v1/auth/code-status"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation(OperationAuthServiceGetRegisterCodeStatus))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTokenExpiry 查询访问令牌剩余有效期及是否应提前刷新，供移动端主动刷新令牌
+func (c *AuthServiceHTTPClientImpl) GetTokenExpiry(ctx context.Context, in *GetTokenExpiryRequest, opts ...http.CallOption) (*GetTokenExpiryResponse, error) {
+	var out GetTokenExpiryResponse
+	pattern := "/v1/auth/token-expiry"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationAuthServiceGetTokenExpiry))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // Login 用户登录
 func (c *AuthServiceHTTPClientImpl) Login(ctx context.Context, in *LoginRequest, opts ...http.CallOption) (*LoginResponse, error) {
 	var out LoginResponse
@@ -56,6 +56,21 @@ const (
 	UserErrorReason_USER_DATABASE_ERROR      UserErrorReason = 15
 	UserErrorReason_USER_INTERNAL_ERROR      UserErrorReason = 16
 	UserErrorReason_USER_SERVICE_UNAVAILABLE UserErrorReason = 17
+	// 权限不足 (403)
+	// 用户无权访问该资源
+	UserErrorReason_USER_PERMISSION_DENIED UserErrorReason = 18
+	// 需要人机验证 (403)
+	// 注册请求过多时缺少或未通过人机验证
+	UserErrorReason_USER_CAPTCHA_REQUIRED UserErrorReason = 19
+	// 邮箱未验证 (403)
+	// 开启登录前邮箱验证时，邮箱未验证的账号不能登录
+	UserErrorReason_USER_EMAIL_NOT_VERIFIED UserErrorReason = 20
+	// 个人访问令牌数已达上限 (409)
+	// 用户未撤销的个人访问令牌数达到 auth.max_personal_tokens 时不能再创建
+	UserErrorReason_USER_PERSONAL_TOKEN_LIMIT_EXCEEDED UserErrorReason = 21
+	// 会话未能全部注销 (500)
+	// 密码已重置，但部分刷新令牌未能吊销，这些会话可能仍然有效
+	UserErrorReason_USER_SESSION_REVOKE_INCOMPLETE UserErrorReason = 22
 )
 
 // Enum value maps for UserErrorReason.
@@ -79,26 +94,36 @@ var (
 		15: "USER_DATABASE_ERROR",
 		16: "USER_INTERNAL_ERROR",
 		17: "USER_SERVICE_UNAVAILABLE",
+		18: "USER_PERMISSION_DENIED",
+		19: "USER_CAPTCHA_REQUIRED",
+		20: "USER_EMAIL_NOT_VERIFIED",
+		21: "USER_PERSONAL_TOKEN_LIMIT_EXCEEDED",
+		22: "USER_SESSION_REVOKE_INCOMPLETE",
 	}
 	UserErrorReason_value = map[string]int32{
-		"USER_INVALID_TOKEN":             0,
-		"USER_TOKEN_EXPIRED":             1,
-		"USER_INVALID_CREDENTIALS":       2,
-		"USER_REFRESH_TOKEN_INVALID":     3,
-		"USER_INVALID_EMAIL":             4,
-		"USER_INVALID_VERIFICATION_CODE": 5,
-		"USER_VERIFICATION_CODE_EXPIRED": 6,
-		"USER_INVALID_REQUEST":           7,
-		"USER_INVALID_NICKNAME":          8,
-		"USER_EMAIL_ALREADY_EXISTS":      9,
-		"USER_NICKNAME_ALREADY_EXISTS":   10,
-		"USER_NOT_FOUND":                 11,
-		"USER_PROFILE_NOT_FOUND":         12,
-		"USER_TOO_MANY_REQUESTS":         13,
-		"USER_LOGIN_TOO_MANY":            14,
-		"USER_DATABASE_ERROR":            15,
-		"USER_INTERNAL_ERROR":            16,
-		"USER_SERVICE_UNAVAILABLE":       17,
+		"USER_INVALID_TOKEN":                 0,
+		"USER_TOKEN_EXPIRED":                 1,
+		"USER_INVALID_CREDENTIALS":           2,
+		"USER_REFRESH_TOKEN_INVALID":         3,
+		"USER_INVALID_EMAIL":                 4,
+		"USER_INVALID_VERIFICATION_CODE":     5,
+		"USER_VERIFICATION_CODE_EXPIRED":     6,
+		"USER_INVALID_REQUEST":               7,
+		"USER_INVALID_NICKNAME":              8,
+		"USER_EMAIL_ALREADY_EXISTS":          9,
+		"USER_NICKNAME_ALREADY_EXISTS":       10,
+		"USER_NOT_FOUND":                     11,
+		"USER_PROFILE_NOT_FOUND":             12,
+		"USER_TOO_MANY_REQUESTS":             13,
+		"USER_LOGIN_TOO_MANY":                14,
+		"USER_DATABASE_ERROR":                15,
+		"USER_INTERNAL_ERROR":                16,
+		"USER_SERVICE_UNAVAILABLE":           17,
+		"USER_PERMISSION_DENIED":             18,
+		"USER_CAPTCHA_REQUIRED":              19,
+		"USER_EMAIL_NOT_VERIFIED":            20,
+		"USER_PERSONAL_TOKEN_LIMIT_EXCEEDED": 21,
+		"USER_SESSION_REVOKE_INCOMPLETE":     22,
 	}
 )
 
@@ -283,7 +308,7 @@ var File_error_reason_error_reason_proto protoreflect.FileDescriptor
 
 const file_error_reason_error_reason_proto_rawDesc = "" +
 	"\n" +
-	"\x1ferror_reason/error_reason.proto\x12\auser.v1\x1a\x13errors/errors.proto*\xfc\x04\n" +
+	"\x1ferror_reason/error_reason.proto\x12\auser.v1\x1a\x13errors/errors.proto*\xba\x06\n" +
 	"\x0fUserErrorReason\x12\x1c\n" +
 	"\x12USER_INVALID_TOKEN\x10\x00\x1a\x04\xa8E\x91\x03\x12\x1c\n" +
 	"\x12USER_TOKEN_EXPIRED\x10\x01\x1a\x04\xa8E\x91\x03\x12\"\n" +
@@ -303,7 +328,12 @@ const file_error_reason_error_reason_proto_rawDesc = "" +
 	"\x13USER_LOGIN_TOO_MANY\x10\x0e\x1a\x04\xa8E\xad\x03\x12\x1d\n" +
 	"\x13USER_DATABASE_ERROR\x10\x0f\x1a\x04\xa8E\xf4\x03\x12\x1d\n" +
 	"\x13USER_INTERNAL_ERROR\x10\x10\x1a\x04\xa8E\xf4\x03\x12\"\n" +
-	"\x18USER_SERVICE_UNAVAILABLE\x10\x11\x1a\x04\xa8E\xf7\x03\x1a\x04\xa0E\xf4\x03*\xb6\x03\n" +
+	"\x18USER_SERVICE_UNAVAILABLE\x10\x11\x1a\x04\xa8E\xf7\x03\x12 \n" +
+	"\x16USER_PERMISSION_DENIED\x10\x12\x1a\x04\xa8E\x93\x03\x12\x1f\n" +
+	"\x15USER_CAPTCHA_REQUIRED\x10\x13\x1a\x04\xa8E\x93\x03\x12!\n" +
+	"\x17USER_EMAIL_NOT_VERIFIED\x10\x14\x1a\x04\xa8E\x93\x03\x12,\n" +
+	"\"USER_PERSONAL_TOKEN_LIMIT_EXCEEDED\x10\x15\x1a\x04\xa8E\x99\x03\x12(\n" +
+	"\x1eUSER_SESSION_REVOKE_INCOMPLETE\x10\x16\x1a\x04\xa8E\xf4\x03\x1a\x04\xa0E\xf4\x03*\xb6\x03\n" +
 	"\x0fAuthErrorReason\x12\"\n" +
 	"\x18AUTH_INVALID_CREDENTIALS\x10\x00\x1a\x04\xa8E\x91\x03\x12\x1c\n" +
 	"\x12AUTH_TOKEN_INVALID\x10\x01\x1a\x04\xa8E\x91\x03\x12\x1c\n" +
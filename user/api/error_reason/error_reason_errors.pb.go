@@ -251,6 +251,86 @@ func ErrorUserServiceUnavailable(format string, args ...interface{}) *errors.Err
 	return errors.New(503, UserErrorReason_USER_SERVICE_UNAVAILABLE.String(), fmt.Sprintf(format, args...))
 }
 
+// 权限不足 (403)
+// 用户无权访问该资源
+func IsUserPermissionDenied(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == UserErrorReason_USER_PERMISSION_DENIED.String() && e.Code == 403
+}
+
+// 权限不足 (403)
+// 用户无权访问该资源
+func ErrorUserPermissionDenied(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, UserErrorReason_USER_PERMISSION_DENIED.String(), fmt.Sprintf(format, args...))
+}
+
+// 需要人机验证 (403)
+// 注册请求过多时缺少或未通过人机验证
+func IsUserCaptchaRequired(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == UserErrorReason_USER_CAPTCHA_REQUIRED.String() && e.Code == 403
+}
+
+// 需要人机验证 (403)
+// 注册请求过多时缺少或未通过人机验证
+func ErrorUserCaptchaRequired(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, UserErrorReason_USER_CAPTCHA_REQUIRED.String(), fmt.Sprintf(format, args...))
+}
+
+// 邮箱未验证 (403)
+// 开启登录前邮箱验证时，邮箱未验证的账号不能登录
+func IsUserEmailNotVerified(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == UserErrorReason_USER_EMAIL_NOT_VERIFIED.String() && e.Code == 403
+}
+
+// 邮箱未验证 (403)
+// 开启登录前邮箱验证时，邮箱未验证的账号不能登录
+func ErrorUserEmailNotVerified(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, UserErrorReason_USER_EMAIL_NOT_VERIFIED.String(), fmt.Sprintf(format, args...))
+}
+
+// 个人访问令牌数已达上限 (409)
+// 用户未撤销的个人访问令牌数达到 auth.max_personal_tokens 时不能再创建
+func IsUserPersonalTokenLimitExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == UserErrorReason_USER_PERSONAL_TOKEN_LIMIT_EXCEEDED.String() && e.Code == 409
+}
+
+// 个人访问令牌数已达上限 (409)
+// 用户未撤销的个人访问令牌数达到 auth.max_personal_tokens 时不能再创建
+func ErrorUserPersonalTokenLimitExceeded(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, UserErrorReason_USER_PERSONAL_TOKEN_LIMIT_EXCEEDED.String(), fmt.Sprintf(format, args...))
+}
+
+// 会话未能全部注销 (500)
+// 密码已重置，但部分刷新令牌未能吊销，这些会话可能仍然有效
+func IsUserSessionRevokeIncomplete(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == UserErrorReason_USER_SESSION_REVOKE_INCOMPLETE.String() && e.Code == 500
+}
+
+// 会话未能全部注销 (500)
+// 密码已重置，但部分刷新令牌未能吊销，这些会话可能仍然有效
+func ErrorUserSessionRevokeIncomplete(format string, args ...interface{}) *errors.Error {
+	return errors.New(500, UserErrorReason_USER_SESSION_REVOKE_INCOMPLETE.String(), fmt.Sprintf(format, args...))
+}
+
 // 认证相关错误 (401)
 func IsAuthInvalidCredentials(err error) bool {
 	if err == nil {
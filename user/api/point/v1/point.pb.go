@@ -0,0 +1,760 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: point/v1/point.proto
+
+package v1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 分页信息
+type Pagination struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,4,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	HasNext       bool                   `protobuf:"varint,5,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	HasPrev       bool                   `protobuf:"varint,6,opt,name=has_prev,json=hasPrev,proto3" json:"has_prev,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	mi := &file_point_v1_point_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pagination) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Pagination) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *Pagination) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Pagination) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *Pagination) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *Pagination) GetHasPrev() bool {
+	if x != nil {
+		return x.HasPrev
+	}
+	return false
+}
+
+// 点数流水
+type PointTransaction struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 交易类型: CONSUME-消耗, RECHARGE-充值
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Amount        uint32                 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	RelatedBookId int64                  `protobuf:"varint,4,opt,name=related_book_id,json=relatedBookId,proto3" json:"related_book_id,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointTransaction) Reset() {
+	*x = PointTransaction{}
+	mi := &file_point_v1_point_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointTransaction) ProtoMessage() {}
+
+func (x *PointTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointTransaction.ProtoReflect.Descriptor instead.
+func (*PointTransaction) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PointTransaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PointTransaction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PointTransaction) GetAmount() uint32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PointTransaction) GetRelatedBookId() int64 {
+	if x != nil {
+		return x.RelatedBookId
+	}
+	return 0
+}
+
+func (x *PointTransaction) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PointTransaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// 获取点数流水请求
+type ListTransactionsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Page     int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// 逗号分隔的流水返回字段（如 id,amount），未知字段名会被忽略；为空时返回全部字段
+	Fields        string `protobuf:"bytes,3,opt,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_point_v1_point_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListTransactionsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetFields() string {
+	if x != nil {
+		return x.Fields
+	}
+	return ""
+}
+
+// 按绘本查询点数流水请求
+type ListBookTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        int64                  `protobuf:"varint,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookTransactionsRequest) Reset() {
+	*x = ListBookTransactionsRequest{}
+	mi := &file_point_v1_point_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookTransactionsRequest) ProtoMessage() {}
+
+func (x *ListBookTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListBookTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListBookTransactionsRequest) GetBookId() int64 {
+	if x != nil {
+		return x.BookId
+	}
+	return 0
+}
+
+func (x *ListBookTransactionsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListBookTransactionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// 按流水来源查询点数流水请求
+type ListSourceTransactionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 流水元数据中的 source 字段，如 account_deletion
+	Source        string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Page          int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSourceTransactionsRequest) Reset() {
+	*x = ListSourceTransactionsRequest{}
+	mi := &file_point_v1_point_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSourceTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSourceTransactionsRequest) ProtoMessage() {}
+
+func (x *ListSourceTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSourceTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSourceTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSourceTransactionsRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ListSourceTransactionsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSourceTransactionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// 获取点数流水响应
+type ListTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transactions  []*PointTransaction    `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_point_v1_point_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*PointTransaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *ListTransactionsResponse) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+// 调整用户点数余额请求
+type AdjustPointsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// 调整量，正数增加、负数扣减，不能为0
+	Delta int32 `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	// 流水描述，说明调整原因
+	Description   string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustPointsRequest) Reset() {
+	*x = AdjustPointsRequest{}
+	mi := &file_point_v1_point_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustPointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustPointsRequest) ProtoMessage() {}
+
+func (x *AdjustPointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustPointsRequest.ProtoReflect.Descriptor instead.
+func (*AdjustPointsRequest) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AdjustPointsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AdjustPointsRequest) GetDelta() int32 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *AdjustPointsRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// 调整用户点数余额响应
+type AdjustPointsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 调整后的点数余额
+	CurrentPoints uint32 `protobuf:"varint,1,opt,name=current_points,json=currentPoints,proto3" json:"current_points,omitempty"`
+	TotalConsumed uint32 `protobuf:"varint,2,opt,name=total_consumed,json=totalConsumed,proto3" json:"total_consumed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustPointsResponse) Reset() {
+	*x = AdjustPointsResponse{}
+	mi := &file_point_v1_point_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustPointsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustPointsResponse) ProtoMessage() {}
+
+func (x *AdjustPointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustPointsResponse.ProtoReflect.Descriptor instead.
+func (*AdjustPointsResponse) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AdjustPointsResponse) GetCurrentPoints() uint32 {
+	if x != nil {
+		return x.CurrentPoints
+	}
+	return 0
+}
+
+func (x *AdjustPointsResponse) GetTotalConsumed() uint32 {
+	if x != nil {
+		return x.TotalConsumed
+	}
+	return 0
+}
+
+// 归档点数流水请求
+type ArchiveTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveTransactionsRequest) Reset() {
+	*x = ArchiveTransactionsRequest{}
+	mi := &file_point_v1_point_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveTransactionsRequest) ProtoMessage() {}
+
+func (x *ArchiveTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{8}
+}
+
+// 归档点数流水响应
+type ArchiveTransactionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 本次移入归档表的流水条数
+	Archived      int64 `protobuf:"varint,1,opt,name=archived,proto3" json:"archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveTransactionsResponse) Reset() {
+	*x = ArchiveTransactionsResponse{}
+	mi := &file_point_v1_point_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveTransactionsResponse) ProtoMessage() {}
+
+func (x *ArchiveTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_point_v1_point_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_point_v1_point_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ArchiveTransactionsResponse) GetArchived() int64 {
+	if x != nil {
+		return x.Archived
+	}
+	return 0
+}
+
+var File_point_v1_point_proto protoreflect.FileDescriptor
+
+const file_point_v1_point_proto_rawDesc = "" +
+	"\n" +
+	"\x14point/v1/point.proto\x12\bpoint.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xaa\x01\n" +
+	"\n" +
+	"Pagination\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12\x1f\n" +
+	"\vtotal_pages\x18\x04 \x01(\x05R\n" +
+	"totalPages\x12\x19\n" +
+	"\bhas_next\x18\x05 \x01(\bR\ahasNext\x12\x19\n" +
+	"\bhas_prev\x18\x06 \x01(\bR\ahasPrev\"\xd3\x01\n" +
+	"\x10PointTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\rR\x06amount\x12&\n" +
+	"\x0frelated_book_id\x18\x04 \x01(\x03R\rrelatedBookId\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"b\n" +
+	"\x17ListTransactionsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06fields\x18\x03 \x01(\tR\x06fields\"g\n" +
+	"\x1bListBookTransactionsRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\x03R\x06bookId\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"h\n" +
+	"\x1dListSourceTransactionsRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"\x90\x01\n" +
+	"\x18ListTransactionsResponse\x12>\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x1a.point.v1.PointTransactionR\ftransactions\x124\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x14.point.v1.PaginationR\n" +
+	"pagination\"f\n" +
+	"\x13AdjustPointsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x05R\x05delta\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"d\n" +
+	"\x14AdjustPointsResponse\x12%\n" +
+	"\x0ecurrent_points\x18\x01 \x01(\rR\rcurrentPoints\x12%\n" +
+	"\x0etotal_consumed\x18\x02 \x01(\rR\rtotalConsumed\"\x1c\n" +
+	"\x1aArchiveTransactionsRequest\"9\n" +
+	"\x1bArchiveTransactionsResponse\x12\x1a\n" +
+	"\barchived\x18\x01 \x01(\x03R\barchived2\xcf\x05\n" +
+	"\fPointService\x12z\n" +
+	"\x10ListTransactions\x12!.point.v1.ListTransactionsRequest\x1a\".point.v1.ListTransactionsResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/points/transactions\x12\x98\x01\n" +
+	"\x14ListBookTransactions\x12%.point.v1.ListBookTransactionsRequest\x1a\".point.v1.ListTransactionsResponse\"5\x82\xd3\xe4\x93\x02/\x12-/v1/admin/points/books/{book_id}/transactions\x12\x8c\x01\n" +
+	"\x16ListSourceTransactions\x12'.point.v1.ListSourceTransactionsRequest\x1a\".point.v1.ListTransactionsResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/admin/points/transactions\x12\x81\x01\n" +
+	"\fAdjustPoints\x12\x1d.point.v1.AdjustPointsRequest\x1a\x1e.point.v1.AdjustPointsResponse\"2\x82\xd3\xe4\x93\x02,:\x01*\"'/v1/admin/points/users/{user_id}/adjust\x12\x94\x01\n" +
+	"\x13ArchiveTransactions\x12$.point.v1.ArchiveTransactionsRequest\x1a%.point.v1.ArchiveTransactionsResponse\"0\x82\xd3\xe4\x93\x02*:\x01*\"%/v1/admin/points/transactions/archiveB\x16Z\x14user/api/point/v1;v1b\x06proto3"
+
+var (
+	file_point_v1_point_proto_rawDescOnce sync.Once
+	file_point_v1_point_proto_rawDescData []byte
+)
+
+func file_point_v1_point_proto_rawDescGZIP() []byte {
+	file_point_v1_point_proto_rawDescOnce.Do(func() {
+		file_point_v1_point_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_point_v1_point_proto_rawDesc), len(file_point_v1_point_proto_rawDesc)))
+	})
+	return file_point_v1_point_proto_rawDescData
+}
+
+var file_point_v1_point_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_point_v1_point_proto_goTypes = []any{
+	(*Pagination)(nil),                    // 0: point.v1.Pagination
+	(*PointTransaction)(nil),              // 1: point.v1.PointTransaction
+	(*ListTransactionsRequest)(nil),       // 2: point.v1.ListTransactionsRequest
+	(*ListBookTransactionsRequest)(nil),   // 3: point.v1.ListBookTransactionsRequest
+	(*ListSourceTransactionsRequest)(nil), // 4: point.v1.ListSourceTransactionsRequest
+	(*ListTransactionsResponse)(nil),      // 5: point.v1.ListTransactionsResponse
+	(*AdjustPointsRequest)(nil),           // 6: point.v1.AdjustPointsRequest
+	(*AdjustPointsResponse)(nil),          // 7: point.v1.AdjustPointsResponse
+	(*ArchiveTransactionsRequest)(nil),    // 8: point.v1.ArchiveTransactionsRequest
+	(*ArchiveTransactionsResponse)(nil),   // 9: point.v1.ArchiveTransactionsResponse
+	(*timestamppb.Timestamp)(nil),         // 10: google.protobuf.Timestamp
+}
+var file_point_v1_point_proto_depIdxs = []int32{
+	10, // 0: point.v1.PointTransaction.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: point.v1.ListTransactionsResponse.transactions:type_name -> point.v1.PointTransaction
+	0,  // 2: point.v1.ListTransactionsResponse.pagination:type_name -> point.v1.Pagination
+	2,  // 3: point.v1.PointService.ListTransactions:input_type -> point.v1.ListTransactionsRequest
+	3,  // 4: point.v1.PointService.ListBookTransactions:input_type -> point.v1.ListBookTransactionsRequest
+	4,  // 5: point.v1.PointService.ListSourceTransactions:input_type -> point.v1.ListSourceTransactionsRequest
+	6,  // 6: point.v1.PointService.AdjustPoints:input_type -> point.v1.AdjustPointsRequest
+	8,  // 7: point.v1.PointService.ArchiveTransactions:input_type -> point.v1.ArchiveTransactionsRequest
+	5,  // 8: point.v1.PointService.ListTransactions:output_type -> point.v1.ListTransactionsResponse
+	5,  // 9: point.v1.PointService.ListBookTransactions:output_type -> point.v1.ListTransactionsResponse
+	5,  // 10: point.v1.PointService.ListSourceTransactions:output_type -> point.v1.ListTransactionsResponse
+	7,  // 11: point.v1.PointService.AdjustPoints:output_type -> point.v1.AdjustPointsResponse
+	9,  // 12: point.v1.PointService.ArchiveTransactions:output_type -> point.v1.ArchiveTransactionsResponse
+	8,  // [8:13] is the sub-list for method output_type
+	3,  // [3:8] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_point_v1_point_proto_init() }
+func file_point_v1_point_proto_init() {
+	if File_point_v1_point_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_point_v1_point_proto_rawDesc), len(file_point_v1_point_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_point_v1_point_proto_goTypes,
+		DependencyIndexes: file_point_v1_point_proto_depIdxs,
+		MessageInfos:      file_point_v1_point_proto_msgTypes,
+	}.Build()
+	File_point_v1_point_proto = out.File
+	file_point_v1_point_proto_goTypes = nil
+	file_point_v1_point_proto_depIdxs = nil
+}
@@ -0,0 +1,287 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: point/v1/point.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PointService_ListTransactions_FullMethodName       = "/point.v1.PointService/ListTransactions"
+	PointService_ListBookTransactions_FullMethodName   = "/point.v1.PointService/ListBookTransactions"
+	PointService_ListSourceTransactions_FullMethodName = "/point.v1.PointService/ListSourceTransactions"
+	PointService_AdjustPoints_FullMethodName           = "/point.v1.PointService/AdjustPoints"
+	PointService_ArchiveTransactions_FullMethodName    = "/point.v1.PointService/ArchiveTransactions"
+)
+
+// PointServiceClient is the client API for PointService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 点数服务
+type PointServiceClient interface {
+	// 获取当前用户点数流水
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	// 按绘本查询点数流水（跨用户，仅管理员）
+	ListBookTransactions(ctx context.Context, in *ListBookTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	// 按流水来源查询点数流水（跨用户，仅管理员）
+	ListSourceTransactions(ctx context.Context, in *ListSourceTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	// 调整用户点数余额（仅管理员）
+	AdjustPoints(ctx context.Context, in *AdjustPointsRequest, opts ...grpc.CallOption) (*AdjustPointsResponse, error)
+	// 立即归档超过保留期的点数流水（仅管理员）
+	ArchiveTransactions(ctx context.Context, in *ArchiveTransactionsRequest, opts ...grpc.CallOption) (*ArchiveTransactionsResponse, error)
+}
+
+type pointServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPointServiceClient(cc grpc.ClientConnInterface) PointServiceClient {
+	return &pointServiceClient{cc}
+}
+
+func (c *pointServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, PointService_ListTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointServiceClient) ListBookTransactions(ctx context.Context, in *ListBookTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, PointService_ListBookTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointServiceClient) ListSourceTransactions(ctx context.Context, in *ListSourceTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, PointService_ListSourceTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointServiceClient) AdjustPoints(ctx context.Context, in *AdjustPointsRequest, opts ...grpc.CallOption) (*AdjustPointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdjustPointsResponse)
+	err := c.cc.Invoke(ctx, PointService_AdjustPoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointServiceClient) ArchiveTransactions(ctx context.Context, in *ArchiveTransactionsRequest, opts ...grpc.CallOption) (*ArchiveTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveTransactionsResponse)
+	err := c.cc.Invoke(ctx, PointService_ArchiveTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PointServiceServer is the server API for PointService service.
+// All implementations must embed UnimplementedPointServiceServer
+// for forward compatibility.
+//
+// 点数服务
+type PointServiceServer interface {
+	// 获取当前用户点数流水
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	// 按绘本查询点数流水（跨用户，仅管理员）
+	ListBookTransactions(context.Context, *ListBookTransactionsRequest) (*ListTransactionsResponse, error)
+	// 按流水来源查询点数流水（跨用户，仅管理员）
+	ListSourceTransactions(context.Context, *ListSourceTransactionsRequest) (*ListTransactionsResponse, error)
+	// 调整用户点数余额（仅管理员）
+	AdjustPoints(context.Context, *AdjustPointsRequest) (*AdjustPointsResponse, error)
+	// 立即归档超过保留期的点数流水（仅管理员）
+	ArchiveTransactions(context.Context, *ArchiveTransactionsRequest) (*ArchiveTransactionsResponse, error)
+	mustEmbedUnimplementedPointServiceServer()
+}
+
+// UnimplementedPointServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPointServiceServer struct{}
+
+func (UnimplementedPointServiceServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedPointServiceServer) ListBookTransactions(context.Context, *ListBookTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBookTransactions not implemented")
+}
+func (UnimplementedPointServiceServer) ListSourceTransactions(context.Context, *ListSourceTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSourceTransactions not implemented")
+}
+func (UnimplementedPointServiceServer) AdjustPoints(context.Context, *AdjustPointsRequest) (*AdjustPointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustPoints not implemented")
+}
+func (UnimplementedPointServiceServer) ArchiveTransactions(context.Context, *ArchiveTransactionsRequest) (*ArchiveTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveTransactions not implemented")
+}
+func (UnimplementedPointServiceServer) mustEmbedUnimplementedPointServiceServer() {}
+func (UnimplementedPointServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafePointServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PointServiceServer will
+// result in compilation errors.
+type UnsafePointServiceServer interface {
+	mustEmbedUnimplementedPointServiceServer()
+}
+
+func RegisterPointServiceServer(s grpc.ServiceRegistrar, srv PointServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPointServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PointService_ServiceDesc, srv)
+}
+
+func _PointService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PointService_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointServiceServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointService_ListBookTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBookTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointServiceServer).ListBookTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PointService_ListBookTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointServiceServer).ListBookTransactions(ctx, req.(*ListBookTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointService_ListSourceTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSourceTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointServiceServer).ListSourceTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PointService_ListSourceTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointServiceServer).ListSourceTransactions(ctx, req.(*ListSourceTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointService_AdjustPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustPointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointServiceServer).AdjustPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PointService_AdjustPoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointServiceServer).AdjustPoints(ctx, req.(*AdjustPointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointService_ArchiveTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointServiceServer).ArchiveTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PointService_ArchiveTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointServiceServer).ArchiveTransactions(ctx, req.(*ArchiveTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PointService_ServiceDesc is the grpc.ServiceDesc for PointService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PointService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "point.v1.PointService",
+	HandlerType: (*PointServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTransactions",
+			Handler:    _PointService_ListTransactions_Handler,
+		},
+		{
+			MethodName: "ListBookTransactions",
+			Handler:    _PointService_ListBookTransactions_Handler,
+		},
+		{
+			MethodName: "ListSourceTransactions",
+			Handler:    _PointService_ListSourceTransactions_Handler,
+		},
+		{
+			MethodName: "AdjustPoints",
+			Handler:    _PointService_AdjustPoints_Handler,
+		},
+		{
+			MethodName: "ArchiveTransactions",
+			Handler:    _PointService_ArchiveTransactions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "point/v1/point.proto",
+}
@@ -25,7 +25,9 @@ const (
 
 // 获取当前用户请求
 type GetCurrentUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 逗号分隔的返回字段（如 id,nickname），未知字段名会被忽略；为空时返回全部字段
+	Fields        string `protobuf:"bytes,1,opt,name=fields,proto3" json:"fields,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -60,16 +62,25 @@ func (*GetCurrentUserRequest) Descriptor() ([]byte, []int) {
 	return file_user_v1_user_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *GetCurrentUserRequest) GetFields() string {
+	if x != nil {
+		return x.Fields
+	}
+	return ""
+}
+
 // 获取当前用户响应
 type GetCurrentUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Nickname      string                 `protobuf:"bytes,3,opt,name=nickname,proto3" json:"nickname,omitempty"`
-	AvatarUrl     string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
-	IsPremium     bool                   `protobuf:"varint,5,opt,name=is_premium,json=isPremium,proto3" json:"is_premium,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email     string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Nickname  string                 `protobuf:"bytes,3,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	AvatarUrl string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	IsPremium bool                   `protobuf:"varint,5,opt,name=is_premium,json=isPremium,proto3" json:"is_premium,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// 编码后的对外用户ID（usr_ 前缀），server.account_id_format 为 prefixed 时返回，此时 id 为 0
+	PublicId      string `protobuf:"bytes,8,opt,name=public_id,json=publicId,proto3" json:"public_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -153,6 +164,13 @@ func (x *GetCurrentUserResponse) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *GetCurrentUserResponse) GetPublicId() string {
+	if x != nil {
+		return x.PublicId
+	}
+	return ""
+}
+
 // 更新当前用户请求
 type UpdateCurrentUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -208,14 +226,16 @@ func (x *UpdateCurrentUserRequest) GetAvatarUrl() string {
 
 // 更新当前用户响应
 type UpdateCurrentUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Nickname      string                 `protobuf:"bytes,3,opt,name=nickname,proto3" json:"nickname,omitempty"`
-	AvatarUrl     string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
-	IsPremium     bool                   `protobuf:"varint,5,opt,name=is_premium,json=isPremium,proto3" json:"is_premium,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email     string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Nickname  string                 `protobuf:"bytes,3,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	AvatarUrl string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	IsPremium bool                   `protobuf:"varint,5,opt,name=is_premium,json=isPremium,proto3" json:"is_premium,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// 编码后的对外用户ID（usr_ 前缀），server.account_id_format 为 prefixed 时返回，此时 id 为 0
+	PublicId      string `protobuf:"bytes,8,opt,name=public_id,json=publicId,proto3" json:"public_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -299,43 +319,871 @@ func (x *UpdateCurrentUserResponse) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-var File_user_v1_user_proto protoreflect.FileDescriptor
+func (x *UpdateCurrentUserResponse) GetPublicId() string {
+	if x != nil {
+		return x.PublicId
+	}
+	return ""
+}
 
-const file_user_v1_user_proto_rawDesc = "" +
-	"\n" +
-	"\x12user/v1/user.proto\x12\auser.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x17\n" +
-	"\x15GetCurrentUserRequest\"\x8e\x02\n" +
-	"\x16GetCurrentUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
-	"\bnickname\x18\x03 \x01(\tR\bnickname\x12\x1d\n" +
-	"\n" +
-	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1d\n" +
-	"\n" +
-	"is_premium\x18\x05 \x01(\bR\tisPremium\x129\n" +
-	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"U\n" +
-	"\x18UpdateCurrentUserRequest\x12\x1a\n" +
-	"\bnickname\x18\x01 \x01(\tR\bnickname\x12\x1d\n" +
-	"\n" +
-	"avatar_url\x18\x02 \x01(\tR\tavatarUrl\"\x91\x02\n" +
-	"\x19UpdateCurrentUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
-	"\bnickname\x18\x03 \x01(\tR\bnickname\x12\x1d\n" +
-	"\n" +
-	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1d\n" +
-	"\n" +
-	"is_premium\x18\x05 \x01(\bR\tisPremium\x129\n" +
-	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+// 列出错误原因请求
+type ListErrorReasonsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListErrorReasonsRequest) Reset() {
+	*x = ListErrorReasonsRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListErrorReasonsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListErrorReasonsRequest) ProtoMessage() {}
+
+func (x *ListErrorReasonsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListErrorReasonsRequest.ProtoReflect.Descriptor instead.
+func (*ListErrorReasonsRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{4}
+}
+
+// 错误原因及其映射
+type ErrorReasonInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 错误原因，如 USER_INVALID_TOKEN
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// HTTP 状态码
+	HttpStatus int32 `protobuf:"varint,2,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	// 业务错误码，如 USER_40101，没有对应业务错误码时为空
+	BusinessCode string `protobuf:"bytes,3,opt,name=business_code,json=businessCode,proto3" json:"business_code,omitempty"`
+	// 默认的用户友好错误消息
+	DefaultMessage string `protobuf:"bytes,4,opt,name=default_message,json=defaultMessage,proto3" json:"default_message,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ErrorReasonInfo) Reset() {
+	*x = ErrorReasonInfo{}
+	mi := &file_user_v1_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorReasonInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorReasonInfo) ProtoMessage() {}
+
+func (x *ErrorReasonInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorReasonInfo.ProtoReflect.Descriptor instead.
+func (*ErrorReasonInfo) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ErrorReasonInfo) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ErrorReasonInfo) GetHttpStatus() int32 {
+	if x != nil {
+		return x.HttpStatus
+	}
+	return 0
+}
+
+func (x *ErrorReasonInfo) GetBusinessCode() string {
+	if x != nil {
+		return x.BusinessCode
+	}
+	return ""
+}
+
+func (x *ErrorReasonInfo) GetDefaultMessage() string {
+	if x != nil {
+		return x.DefaultMessage
+	}
+	return ""
+}
+
+// 列出错误原因响应
+type ListErrorReasonsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reasons       []*ErrorReasonInfo     `protobuf:"bytes,1,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListErrorReasonsResponse) Reset() {
+	*x = ListErrorReasonsResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListErrorReasonsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListErrorReasonsResponse) ProtoMessage() {}
+
+func (x *ListErrorReasonsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListErrorReasonsResponse.ProtoReflect.Descriptor instead.
+func (*ListErrorReasonsResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListErrorReasonsResponse) GetReasons() []*ErrorReasonInfo {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+// 按 IP 撤销会话请求
+type RevokeSessionsByIPRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 会话签发时的客户端 IP，支持 IPv4 和 IPv6
+	Ip            string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionsByIPRequest) Reset() {
+	*x = RevokeSessionsByIPRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionsByIPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionsByIPRequest) ProtoMessage() {}
+
+func (x *RevokeSessionsByIPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionsByIPRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionsByIPRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RevokeSessionsByIPRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+// 按 IP 撤销会话响应
+type RevokeSessionsByIPResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 撤销的会话（刷新令牌）数量
+	Revoked       int32 `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionsByIPResponse) Reset() {
+	*x = RevokeSessionsByIPResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionsByIPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionsByIPResponse) ProtoMessage() {}
+
+func (x *RevokeSessionsByIPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionsByIPResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionsByIPResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RevokeSessionsByIPResponse) GetRevoked() int32 {
+	if x != nil {
+		return x.Revoked
+	}
+	return 0
+}
+
+// 全局撤销令牌请求
+type RevokeAllTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAllTokensRequest) Reset() {
+	*x = RevokeAllTokensRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllTokensRequest) ProtoMessage() {}
+
+func (x *RevokeAllTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllTokensRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAllTokensRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{9}
+}
+
+// 全局撤销令牌响应
+type RevokeAllTokensResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 令牌失效时间点，签发时间早于该时间的令牌均被拒绝
+	TokensValidAfter *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=tokens_valid_after,json=tokensValidAfter,proto3" json:"tokens_valid_after,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RevokeAllTokensResponse) Reset() {
+	*x = RevokeAllTokensResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllTokensResponse) ProtoMessage() {}
+
+func (x *RevokeAllTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllTokensResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAllTokensResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RevokeAllTokensResponse) GetTokensValidAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TokensValidAfter
+	}
+	return nil
+}
+
+// 个人访问令牌
+type PersonalToken struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// 权限范围: user:read, user:write, points:read
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	LastUsedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PersonalToken) Reset() {
+	*x = PersonalToken{}
+	mi := &file_user_v1_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PersonalToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PersonalToken) ProtoMessage() {}
+
+func (x *PersonalToken) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PersonalToken.ProtoReflect.Descriptor instead.
+func (*PersonalToken) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PersonalToken) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PersonalToken) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PersonalToken) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *PersonalToken) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *PersonalToken) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// 创建个人访问令牌请求
+type CreatePersonalTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 令牌名称，最多64个字符
+	Name          string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePersonalTokenRequest) Reset() {
+	*x = CreatePersonalTokenRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePersonalTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePersonalTokenRequest) ProtoMessage() {}
+
+func (x *CreatePersonalTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePersonalTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreatePersonalTokenRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreatePersonalTokenRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreatePersonalTokenRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+// 创建个人访问令牌响应
+type CreatePersonalTokenResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token *PersonalToken         `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// 令牌明文，只在此时返回一次，服务端只保存其哈希
+	Plaintext     string `protobuf:"bytes,2,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePersonalTokenResponse) Reset() {
+	*x = CreatePersonalTokenResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePersonalTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePersonalTokenResponse) ProtoMessage() {}
+
+func (x *CreatePersonalTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePersonalTokenResponse.ProtoReflect.Descriptor instead.
+func (*CreatePersonalTokenResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreatePersonalTokenResponse) GetToken() *PersonalToken {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *CreatePersonalTokenResponse) GetPlaintext() string {
+	if x != nil {
+		return x.Plaintext
+	}
+	return ""
+}
+
+// 列出个人访问令牌请求
+type ListPersonalTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPersonalTokensRequest) Reset() {
+	*x = ListPersonalTokensRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPersonalTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPersonalTokensRequest) ProtoMessage() {}
+
+func (x *ListPersonalTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPersonalTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListPersonalTokensRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{14}
+}
+
+// 列出个人访问令牌响应
+type ListPersonalTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*PersonalToken       `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPersonalTokensResponse) Reset() {
+	*x = ListPersonalTokensResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPersonalTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPersonalTokensResponse) ProtoMessage() {}
+
+func (x *ListPersonalTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPersonalTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListPersonalTokensResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListPersonalTokensResponse) GetTokens() []*PersonalToken {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// 撤销个人访问令牌请求
+type RevokePersonalTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokePersonalTokenRequest) Reset() {
+	*x = RevokePersonalTokenRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokePersonalTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokePersonalTokenRequest) ProtoMessage() {}
+
+func (x *RevokePersonalTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokePersonalTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokePersonalTokenRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RevokePersonalTokenRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// 撤销个人访问令牌响应
+type RevokePersonalTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokePersonalTokenResponse) Reset() {
+	*x = RevokePersonalTokenResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokePersonalTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokePersonalTokenResponse) ProtoMessage() {}
+
+func (x *RevokePersonalTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokePersonalTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokePersonalTokenResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{17}
+}
+
+// 重新生成个人访问令牌请求
+type RotatePersonalTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotatePersonalTokenRequest) Reset() {
+	*x = RotatePersonalTokenRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatePersonalTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatePersonalTokenRequest) ProtoMessage() {}
+
+func (x *RotatePersonalTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatePersonalTokenRequest.ProtoReflect.Descriptor instead.
+func (*RotatePersonalTokenRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RotatePersonalTokenRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// 重新生成个人访问令牌响应
+type RotatePersonalTokenResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 新的令牌明文，只在此时返回一次
+	Plaintext     string `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotatePersonalTokenResponse) Reset() {
+	*x = RotatePersonalTokenResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatePersonalTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatePersonalTokenResponse) ProtoMessage() {}
+
+func (x *RotatePersonalTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatePersonalTokenResponse.ProtoReflect.Descriptor instead.
+func (*RotatePersonalTokenResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RotatePersonalTokenResponse) GetPlaintext() string {
+	if x != nil {
+		return x.Plaintext
+	}
+	return ""
+}
+
+var File_user_v1_user_proto protoreflect.FileDescriptor
+
+const file_user_v1_user_proto_rawDesc = "" +
+	"\n" +
+	"\x12user/v1/user.proto\x12\auser.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"/\n" +
+	"\x15GetCurrentUserRequest\x12\x16\n" +
+	"\x06fields\x18\x01 \x01(\tR\x06fields\"\xab\x02\n" +
+	"\x16GetCurrentUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bnickname\x18\x03 \x01(\tR\bnickname\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1d\n" +
+	"\n" +
+	"is_premium\x18\x05 \x01(\bR\tisPremium\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1b\n" +
+	"\tpublic_id\x18\b \x01(\tR\bpublicId\"U\n" +
+	"\x18UpdateCurrentUserRequest\x12\x1a\n" +
+	"\bnickname\x18\x01 \x01(\tR\bnickname\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x02 \x01(\tR\tavatarUrl\"\xae\x02\n" +
+	"\x19UpdateCurrentUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bnickname\x18\x03 \x01(\tR\bnickname\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1d\n" +
+	"\n" +
+	"is_premium\x18\x05 \x01(\bR\tisPremium\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1b\n" +
+	"\tpublic_id\x18\b \x01(\tR\bpublicId\"\x19\n" +
+	"\x17ListErrorReasonsRequest\"\x98\x01\n" +
+	"\x0fErrorReasonInfo\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vhttp_status\x18\x02 \x01(\x05R\n" +
+	"httpStatus\x12#\n" +
+	"\rbusiness_code\x18\x03 \x01(\tR\fbusinessCode\x12'\n" +
+	"\x0fdefault_message\x18\x04 \x01(\tR\x0edefaultMessage\"N\n" +
+	"\x18ListErrorReasonsResponse\x122\n" +
+	"\areasons\x18\x01 \x03(\v2\x18.user.v1.ErrorReasonInfoR\areasons\"+\n" +
+	"\x19RevokeSessionsByIPRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"6\n" +
+	"\x1aRevokeSessionsByIPResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\x05R\arevoked\"\x18\n" +
+	"\x16RevokeAllTokensRequest\"c\n" +
+	"\x17RevokeAllTokensResponse\x12H\n" +
+	"\x12tokens_valid_after\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x10tokensValidAfter\"\xc4\x01\n" +
+	"\rPersonalToken\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12<\n" +
+	"\flast_used_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt2\xf3\x01\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"H\n" +
+	"\x1aCreatePersonalTokenRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\"i\n" +
+	"\x1bCreatePersonalTokenResponse\x12,\n" +
+	"\x05token\x18\x01 \x01(\v2\x16.user.v1.PersonalTokenR\x05token\x12\x1c\n" +
+	"\tplaintext\x18\x02 \x01(\tR\tplaintext\"\x1b\n" +
+	"\x19ListPersonalTokensRequest\"L\n" +
+	"\x1aListPersonalTokensResponse\x12.\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x16.user.v1.PersonalTokenR\x06tokens\",\n" +
+	"\x1aRevokePersonalTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x1d\n" +
+	"\x1bRevokePersonalTokenResponse\",\n" +
+	"\x1aRotatePersonalTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\";\n" +
+	"\x1bRotatePersonalTokenResponse\x12\x1c\n" +
+	"\tplaintext\x18\x01 \x01(\tR\tplaintext2\xf8\b\n" +
 	"\vUserService\x12k\n" +
 	"\x0eGetCurrentUser\x12\x1e.user.v1.GetCurrentUserRequest\x1a\x1f.user.v1.GetCurrentUserResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/user/profile\x12w\n" +
-	"\x11UpdateCurrentUser\x12!.user.v1.UpdateCurrentUserRequest\x1a\".user.v1.UpdateCurrentUserResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\x1a\x10/v1/user/profileB\x15Z\x13user/api/user/v1;v1b\x06proto3"
+	"\x11UpdateCurrentUser\x12!.user.v1.UpdateCurrentUserRequest\x1a\".user.v1.UpdateCurrentUserResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\x1a\x10/v1/user/profile\x12x\n" +
+	"\x10ListErrorReasons\x12 .user.v1.ListErrorReasonsRequest\x1a!.user.v1.ListErrorReasonsResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/admin/error-reasons\x12\x89\x01\n" +
+	"\x12RevokeSessionsByIP\x12\".user.v1.RevokeSessionsByIPRequest\x1a#.user.v1.RevokeSessionsByIPResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/admin/sessions/revoke-by-ip\x12|\n" +
+	"\x0fRevokeAllTokens\x12\x1f.user.v1.RevokeAllTokensRequest\x1a .user.v1.RevokeAllTokensResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/admin/tokens/revoke-all\x12|\n" +
+	"\x13CreatePersonalToken\x12#.user.v1.CreatePersonalTokenRequest\x1a$.user.v1.CreatePersonalTokenResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/user/tokens\x12v\n" +
+	"\x12ListPersonalTokens\x12\".user.v1.ListPersonalTokensRequest\x1a#.user.v1.ListPersonalTokensResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/user/tokens\x12~\n" +
+	"\x13RevokePersonalToken\x12#.user.v1.RevokePersonalTokenRequest\x1a$.user.v1.RevokePersonalTokenResponse\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/v1/user/tokens/{id}\x12\x88\x01\n" +
+	"\x13RotatePersonalToken\x12#.user.v1.RotatePersonalTokenRequest\x1a$.user.v1.RotatePersonalTokenResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/user/tokens/{id}/rotateB\x15Z\x13user/api/user/v1;v1b\x06proto3"
 
 var (
 	file_user_v1_user_proto_rawDescOnce sync.Once
@@ -349,28 +1197,64 @@ func file_user_v1_user_proto_rawDescGZIP() []byte {
 	return file_user_v1_user_proto_rawDescData
 }
 
-var file_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_user_v1_user_proto_goTypes = []any{
-	(*GetCurrentUserRequest)(nil),     // 0: user.v1.GetCurrentUserRequest
-	(*GetCurrentUserResponse)(nil),    // 1: user.v1.GetCurrentUserResponse
-	(*UpdateCurrentUserRequest)(nil),  // 2: user.v1.UpdateCurrentUserRequest
-	(*UpdateCurrentUserResponse)(nil), // 3: user.v1.UpdateCurrentUserResponse
-	(*timestamppb.Timestamp)(nil),     // 4: google.protobuf.Timestamp
+	(*GetCurrentUserRequest)(nil),       // 0: user.v1.GetCurrentUserRequest
+	(*GetCurrentUserResponse)(nil),      // 1: user.v1.GetCurrentUserResponse
+	(*UpdateCurrentUserRequest)(nil),    // 2: user.v1.UpdateCurrentUserRequest
+	(*UpdateCurrentUserResponse)(nil),   // 3: user.v1.UpdateCurrentUserResponse
+	(*ListErrorReasonsRequest)(nil),     // 4: user.v1.ListErrorReasonsRequest
+	(*ErrorReasonInfo)(nil),             // 5: user.v1.ErrorReasonInfo
+	(*ListErrorReasonsResponse)(nil),    // 6: user.v1.ListErrorReasonsResponse
+	(*RevokeSessionsByIPRequest)(nil),   // 7: user.v1.RevokeSessionsByIPRequest
+	(*RevokeSessionsByIPResponse)(nil),  // 8: user.v1.RevokeSessionsByIPResponse
+	(*RevokeAllTokensRequest)(nil),      // 9: user.v1.RevokeAllTokensRequest
+	(*RevokeAllTokensResponse)(nil),     // 10: user.v1.RevokeAllTokensResponse
+	(*PersonalToken)(nil),               // 11: user.v1.PersonalToken
+	(*CreatePersonalTokenRequest)(nil),  // 12: user.v1.CreatePersonalTokenRequest
+	(*CreatePersonalTokenResponse)(nil), // 13: user.v1.CreatePersonalTokenResponse
+	(*ListPersonalTokensRequest)(nil),   // 14: user.v1.ListPersonalTokensRequest
+	(*ListPersonalTokensResponse)(nil),  // 15: user.v1.ListPersonalTokensResponse
+	(*RevokePersonalTokenRequest)(nil),  // 16: user.v1.RevokePersonalTokenRequest
+	(*RevokePersonalTokenResponse)(nil), // 17: user.v1.RevokePersonalTokenResponse
+	(*RotatePersonalTokenRequest)(nil),  // 18: user.v1.RotatePersonalTokenRequest
+	(*RotatePersonalTokenResponse)(nil), // 19: user.v1.RotatePersonalTokenResponse
+	(*timestamppb.Timestamp)(nil),       // 20: google.protobuf.Timestamp
 }
 var file_user_v1_user_proto_depIdxs = []int32{
-	4, // 0: user.v1.GetCurrentUserResponse.created_at:type_name -> google.protobuf.Timestamp
-	4, // 1: user.v1.GetCurrentUserResponse.updated_at:type_name -> google.protobuf.Timestamp
-	4, // 2: user.v1.UpdateCurrentUserResponse.created_at:type_name -> google.protobuf.Timestamp
-	4, // 3: user.v1.UpdateCurrentUserResponse.updated_at:type_name -> google.protobuf.Timestamp
-	0, // 4: user.v1.UserService.GetCurrentUser:input_type -> user.v1.GetCurrentUserRequest
-	2, // 5: user.v1.UserService.UpdateCurrentUser:input_type -> user.v1.UpdateCurrentUserRequest
-	1, // 6: user.v1.UserService.GetCurrentUser:output_type -> user.v1.GetCurrentUserResponse
-	3, // 7: user.v1.UserService.UpdateCurrentUser:output_type -> user.v1.UpdateCurrentUserResponse
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	20, // 0: user.v1.GetCurrentUserResponse.created_at:type_name -> google.protobuf.Timestamp
+	20, // 1: user.v1.GetCurrentUserResponse.updated_at:type_name -> google.protobuf.Timestamp
+	20, // 2: user.v1.UpdateCurrentUserResponse.created_at:type_name -> google.protobuf.Timestamp
+	20, // 3: user.v1.UpdateCurrentUserResponse.updated_at:type_name -> google.protobuf.Timestamp
+	5,  // 4: user.v1.ListErrorReasonsResponse.reasons:type_name -> user.v1.ErrorReasonInfo
+	20, // 5: user.v1.RevokeAllTokensResponse.tokens_valid_after:type_name -> google.protobuf.Timestamp
+	20, // 6: user.v1.PersonalToken.last_used_at:type_name -> google.protobuf.Timestamp
+	20, // 7: user.v1.PersonalToken.created_at:type_name -> google.protobuf.Timestamp
+	11, // 8: user.v1.CreatePersonalTokenResponse.token:type_name -> user.v1.PersonalToken
+	11, // 9: user.v1.ListPersonalTokensResponse.tokens:type_name -> user.v1.PersonalToken
+	0,  // 10: user.v1.UserService.GetCurrentUser:input_type -> user.v1.GetCurrentUserRequest
+	2,  // 11: user.v1.UserService.UpdateCurrentUser:input_type -> user.v1.UpdateCurrentUserRequest
+	4,  // 12: user.v1.UserService.ListErrorReasons:input_type -> user.v1.ListErrorReasonsRequest
+	7,  // 13: user.v1.UserService.RevokeSessionsByIP:input_type -> user.v1.RevokeSessionsByIPRequest
+	9,  // 14: user.v1.UserService.RevokeAllTokens:input_type -> user.v1.RevokeAllTokensRequest
+	12, // 15: user.v1.UserService.CreatePersonalToken:input_type -> user.v1.CreatePersonalTokenRequest
+	14, // 16: user.v1.UserService.ListPersonalTokens:input_type -> user.v1.ListPersonalTokensRequest
+	16, // 17: user.v1.UserService.RevokePersonalToken:input_type -> user.v1.RevokePersonalTokenRequest
+	18, // 18: user.v1.UserService.RotatePersonalToken:input_type -> user.v1.RotatePersonalTokenRequest
+	1,  // 19: user.v1.UserService.GetCurrentUser:output_type -> user.v1.GetCurrentUserResponse
+	3,  // 20: user.v1.UserService.UpdateCurrentUser:output_type -> user.v1.UpdateCurrentUserResponse
+	6,  // 21: user.v1.UserService.ListErrorReasons:output_type -> user.v1.ListErrorReasonsResponse
+	8,  // 22: user.v1.UserService.RevokeSessionsByIP:output_type -> user.v1.RevokeSessionsByIPResponse
+	10, // 23: user.v1.UserService.RevokeAllTokens:output_type -> user.v1.RevokeAllTokensResponse
+	13, // 24: user.v1.UserService.CreatePersonalToken:output_type -> user.v1.CreatePersonalTokenResponse
+	15, // 25: user.v1.UserService.ListPersonalTokens:output_type -> user.v1.ListPersonalTokensResponse
+	17, // 26: user.v1.UserService.RevokePersonalToken:output_type -> user.v1.RevokePersonalTokenResponse
+	19, // 27: user.v1.UserService.RotatePersonalToken:output_type -> user.v1.RotatePersonalTokenResponse
+	19, // [19:28] is the sub-list for method output_type
+	10, // [10:19] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_user_v1_user_proto_init() }
@@ -384,7 +1268,7 @@ func file_user_v1_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_v1_user_proto_rawDesc), len(file_user_v1_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,8 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetCurrentUser_FullMethodName    = "/user.v1.UserService/GetCurrentUser"
-	UserService_UpdateCurrentUser_FullMethodName = "/user.v1.UserService/UpdateCurrentUser"
+	UserService_GetCurrentUser_FullMethodName      = "/user.v1.UserService/GetCurrentUser"
+	UserService_UpdateCurrentUser_FullMethodName   = "/user.v1.UserService/UpdateCurrentUser"
+	UserService_ListErrorReasons_FullMethodName    = "/user.v1.UserService/ListErrorReasons"
+	UserService_RevokeSessionsByIP_FullMethodName  = "/user.v1.UserService/RevokeSessionsByIP"
+	UserService_RevokeAllTokens_FullMethodName     = "/user.v1.UserService/RevokeAllTokens"
+	UserService_CreatePersonalToken_FullMethodName = "/user.v1.UserService/CreatePersonalToken"
+	UserService_ListPersonalTokens_FullMethodName  = "/user.v1.UserService/ListPersonalTokens"
+	UserService_RevokePersonalToken_FullMethodName = "/user.v1.UserService/RevokePersonalToken"
+	UserService_RotatePersonalToken_FullMethodName = "/user.v1.UserService/RotatePersonalToken"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -33,6 +40,20 @@ type UserServiceClient interface {
 	GetCurrentUser(ctx context.Context, in *GetCurrentUserRequest, opts ...grpc.CallOption) (*GetCurrentUserResponse, error)
 	// 更新当前用户资料
 	UpdateCurrentUser(ctx context.Context, in *UpdateCurrentUserRequest, opts ...grpc.CallOption) (*UpdateCurrentUserResponse, error)
+	// 列出所有错误原因及其 HTTP 状态码、业务错误码映射（仅管理员），用于生成客户端 SDK 和文档
+	ListErrorReasons(ctx context.Context, in *ListErrorReasonsRequest, opts ...grpc.CallOption) (*ListErrorReasonsResponse, error)
+	// 撤销所有用户中来源 IP 为指定地址的会话（仅管理员），用于处置被盗用或异常的来源
+	RevokeSessionsByIP(ctx context.Context, in *RevokeSessionsByIPRequest, opts ...grpc.CallOption) (*RevokeSessionsByIPResponse, error)
+	// 使当前时间之前签发的所有访问令牌和刷新令牌失效（仅管理员），用于泄露等事件后的全局登出，需开启 auth.enforce_tokens_valid_after
+	RevokeAllTokens(ctx context.Context, in *RevokeAllTokensRequest, opts ...grpc.CallOption) (*RevokeAllTokensResponse, error)
+	// 创建个人访问令牌，令牌明文只在响应中返回一次
+	CreatePersonalToken(ctx context.Context, in *CreatePersonalTokenRequest, opts ...grpc.CallOption) (*CreatePersonalTokenResponse, error)
+	// 列出当前用户未撤销的个人访问令牌，不包含令牌明文
+	ListPersonalTokens(ctx context.Context, in *ListPersonalTokensRequest, opts ...grpc.CallOption) (*ListPersonalTokensResponse, error)
+	// 撤销个人访问令牌
+	RevokePersonalToken(ctx context.Context, in *RevokePersonalTokenRequest, opts ...grpc.CallOption) (*RevokePersonalTokenResponse, error)
+	// 重新生成个人访问令牌，名称和权限范围不变，旧令牌立即失效
+	RotatePersonalToken(ctx context.Context, in *RotatePersonalTokenRequest, opts ...grpc.CallOption) (*RotatePersonalTokenResponse, error)
 }
 
 type userServiceClient struct {
@@ -63,6 +84,76 @@ func (c *userServiceClient) UpdateCurrentUser(ctx context.Context, in *UpdateCur
 	return out, nil
 }
 
+func (c *userServiceClient) ListErrorReasons(ctx context.Context, in *ListErrorReasonsRequest, opts ...grpc.CallOption) (*ListErrorReasonsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListErrorReasonsResponse)
+	err := c.cc.Invoke(ctx, UserService_ListErrorReasons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeSessionsByIP(ctx context.Context, in *RevokeSessionsByIPRequest, opts ...grpc.CallOption) (*RevokeSessionsByIPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionsByIPResponse)
+	err := c.cc.Invoke(ctx, UserService_RevokeSessionsByIP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeAllTokens(ctx context.Context, in *RevokeAllTokensRequest, opts ...grpc.CallOption) (*RevokeAllTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAllTokensResponse)
+	err := c.cc.Invoke(ctx, UserService_RevokeAllTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreatePersonalToken(ctx context.Context, in *CreatePersonalTokenRequest, opts ...grpc.CallOption) (*CreatePersonalTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePersonalTokenResponse)
+	err := c.cc.Invoke(ctx, UserService_CreatePersonalToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListPersonalTokens(ctx context.Context, in *ListPersonalTokensRequest, opts ...grpc.CallOption) (*ListPersonalTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPersonalTokensResponse)
+	err := c.cc.Invoke(ctx, UserService_ListPersonalTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokePersonalToken(ctx context.Context, in *RevokePersonalTokenRequest, opts ...grpc.CallOption) (*RevokePersonalTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokePersonalTokenResponse)
+	err := c.cc.Invoke(ctx, UserService_RevokePersonalToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RotatePersonalToken(ctx context.Context, in *RotatePersonalTokenRequest, opts ...grpc.CallOption) (*RotatePersonalTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotatePersonalTokenResponse)
+	err := c.cc.Invoke(ctx, UserService_RotatePersonalToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -73,6 +164,20 @@ type UserServiceServer interface {
 	GetCurrentUser(context.Context, *GetCurrentUserRequest) (*GetCurrentUserResponse, error)
 	// 更新当前用户资料
 	UpdateCurrentUser(context.Context, *UpdateCurrentUserRequest) (*UpdateCurrentUserResponse, error)
+	// 列出所有错误原因及其 HTTP 状态码、业务错误码映射（仅管理员），用于生成客户端 SDK 和文档
+	ListErrorReasons(context.Context, *ListErrorReasonsRequest) (*ListErrorReasonsResponse, error)
+	// 撤销所有用户中来源 IP 为指定地址的会话（仅管理员），用于处置被盗用或异常的来源
+	RevokeSessionsByIP(context.Context, *RevokeSessionsByIPRequest) (*RevokeSessionsByIPResponse, error)
+	// 使当前时间之前签发的所有访问令牌和刷新令牌失效（仅管理员），用于泄露等事件后的全局登出，需开启 auth.enforce_tokens_valid_after
+	RevokeAllTokens(context.Context, *RevokeAllTokensRequest) (*RevokeAllTokensResponse, error)
+	// 创建个人访问令牌，令牌明文只在响应中返回一次
+	CreatePersonalToken(context.Context, *CreatePersonalTokenRequest) (*CreatePersonalTokenResponse, error)
+	// 列出当前用户未撤销的个人访问令牌，不包含令牌明文
+	ListPersonalTokens(context.Context, *ListPersonalTokensRequest) (*ListPersonalTokensResponse, error)
+	// 撤销个人访问令牌
+	RevokePersonalToken(context.Context, *RevokePersonalTokenRequest) (*RevokePersonalTokenResponse, error)
+	// 重新生成个人访问令牌，名称和权限范围不变，旧令牌立即失效
+	RotatePersonalToken(context.Context, *RotatePersonalTokenRequest) (*RotatePersonalTokenResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -89,6 +194,27 @@ func (UnimplementedUserServiceServer) GetCurrentUser(context.Context, *GetCurren
 func (UnimplementedUserServiceServer) UpdateCurrentUser(context.Context, *UpdateCurrentUserRequest) (*UpdateCurrentUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateCurrentUser not implemented")
 }
+func (UnimplementedUserServiceServer) ListErrorReasons(context.Context, *ListErrorReasonsRequest) (*ListErrorReasonsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListErrorReasons not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeSessionsByIP(context.Context, *RevokeSessionsByIPRequest) (*RevokeSessionsByIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSessionsByIP not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeAllTokens(context.Context, *RevokeAllTokensRequest) (*RevokeAllTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAllTokens not implemented")
+}
+func (UnimplementedUserServiceServer) CreatePersonalToken(context.Context, *CreatePersonalTokenRequest) (*CreatePersonalTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePersonalToken not implemented")
+}
+func (UnimplementedUserServiceServer) ListPersonalTokens(context.Context, *ListPersonalTokensRequest) (*ListPersonalTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPersonalTokens not implemented")
+}
+func (UnimplementedUserServiceServer) RevokePersonalToken(context.Context, *RevokePersonalTokenRequest) (*RevokePersonalTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokePersonalToken not implemented")
+}
+func (UnimplementedUserServiceServer) RotatePersonalToken(context.Context, *RotatePersonalTokenRequest) (*RotatePersonalTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotatePersonalToken not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -146,6 +272,132 @@ func _UserService_UpdateCurrentUser_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_ListErrorReasons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListErrorReasonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListErrorReasons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListErrorReasons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListErrorReasons(ctx, req.(*ListErrorReasonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeSessionsByIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionsByIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeSessionsByIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RevokeSessionsByIP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeSessionsByIP(ctx, req.(*RevokeSessionsByIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeAllTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeAllTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RevokeAllTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeAllTokens(ctx, req.(*RevokeAllTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreatePersonalToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePersonalTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreatePersonalToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreatePersonalToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreatePersonalToken(ctx, req.(*CreatePersonalTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListPersonalTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPersonalTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListPersonalTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListPersonalTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListPersonalTokens(ctx, req.(*ListPersonalTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokePersonalToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokePersonalTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokePersonalToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RevokePersonalToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokePersonalToken(ctx, req.(*RevokePersonalTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RotatePersonalToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotatePersonalTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RotatePersonalToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RotatePersonalToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RotatePersonalToken(ctx, req.(*RotatePersonalTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,6 +413,34 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateCurrentUser",
 			Handler:    _UserService_UpdateCurrentUser_Handler,
 		},
+		{
+			MethodName: "ListErrorReasons",
+			Handler:    _UserService_ListErrorReasons_Handler,
+		},
+		{
+			MethodName: "RevokeSessionsByIP",
+			Handler:    _UserService_RevokeSessionsByIP_Handler,
+		},
+		{
+			MethodName: "RevokeAllTokens",
+			Handler:    _UserService_RevokeAllTokens_Handler,
+		},
+		{
+			MethodName: "CreatePersonalToken",
+			Handler:    _UserService_CreatePersonalToken_Handler,
+		},
+		{
+			MethodName: "ListPersonalTokens",
+			Handler:    _UserService_ListPersonalTokens_Handler,
+		},
+		{
+			MethodName: "RevokePersonalToken",
+			Handler:    _UserService_RevokePersonalToken_Handler,
+		},
+		{
+			MethodName: "RotatePersonalToken",
+			Handler:    _UserService_RotatePersonalToken_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user/v1/user.proto",
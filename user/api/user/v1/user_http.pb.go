@@ -19,12 +19,33 @@ var _ = binding.EncodeURL
 
 const _ = http.SupportPackageIsVersion1
 
+const OperationUserServiceCreatePersonalToken = "/user.v1.UserService/CreatePersonalToken"
 const OperationUserServiceGetCurrentUser = "/user.v1.UserService/GetCurrentUser"
+const OperationUserServiceListErrorReasons = "/user.v1.UserService/ListErrorReasons"
+const OperationUserServiceListPersonalTokens = "/user.v1.UserService/ListPersonalTokens"
+const OperationUserServiceRevokeAllTokens = "/user.v1.UserService/RevokeAllTokens"
+const OperationUserServiceRevokePersonalToken = "/user.v1.UserService/RevokePersonalToken"
+const OperationUserServiceRevokeSessionsByIP = "/user.v1.UserService/RevokeSessionsByIP"
+const OperationUserServiceRotatePersonalToken = "/user.v1.UserService/RotatePersonalToken"
 const OperationUserServiceUpdateCurrentUser = "/user.v1.UserService/UpdateCurrentUser"
 
 type UserServiceHTTPServer interface {
+	// CreatePersonalToken 创建个人访问令牌，令牌明文只在响应中返回一次
+	CreatePersonalToken(context.Context, *CreatePersonalTokenRequest) (*CreatePersonalTokenResponse, error)
 	// GetCurrentUser 获取当前用户资料
 	GetCurrentUser(context.Context, *GetCurrentUserRequest) (*GetCurrentUserResponse, error)
+	// ListErrorReasons 列出所有错误原因及其 HTTP 状态码、业务错误码映射（仅管理员），用于生成客户端 SDK 和文档
+	ListErrorReasons(context.Context, *ListErrorReasonsRequest) (*ListErrorReasonsResponse, error)
+	// ListPersonalTokens 列出当前用户未撤销的个人访问令牌，不包含令牌明文
+	ListPersonalTokens(context.Context, *ListPersonalTokensRequest) (*ListPersonalTokensResponse, error)
+	// RevokeAllTokens 使当前时间之前签发的所有访问令牌和刷新令牌失效（仅管理员），用于泄露等事件后的全局登出，需开启 auth.enforce_tokens_valid_after
+	RevokeAllTokens(context.Context, *RevokeAllTokensRequest) (*RevokeAllTokensResponse, error)
+	// RevokePersonalToken 撤销个人访问令牌
+	RevokePersonalToken(context.Context, *RevokePersonalTokenRequest) (*RevokePersonalTokenResponse, error)
+	// RevokeSessionsByIP 撤销所有用户中来源 IP 为指定地址的会话（仅管理员），用于处置被盗用或异常的来源
+	RevokeSessionsByIP(context.Context, *RevokeSessionsByIPRequest) (*RevokeSessionsByIPResponse, error)
+	// RotatePersonalToken 重新生成个人访问令牌，名称和权限范围不变，旧令牌立即失效
+	RotatePersonalToken(context.Context, *RotatePersonalTokenRequest) (*RotatePersonalTokenResponse, error)
 	// UpdateCurrentUser 更新当前用户资料
 	UpdateCurrentUser(context.Context, *UpdateCurrentUserRequest) (*UpdateCurrentUserResponse, error)
 }
@@ -33,6 +54,13 @@ func RegisterUserServiceHTTPServer(s *http.Server, srv UserServiceHTTPServer) {
 	r := s.Route("/")
 	r.GET("/v1/user/profile", _UserService_GetCurrentUser0_HTTP_Handler(srv))
 	r.PUT("/v1/user/profile", _UserService_UpdateCurrentUser0_HTTP_Handler(srv))
+	r.GET("/v1/admin/error-reasons", _UserService_ListErrorReasons0_HTTP_Handler(srv))
+	r.POST("/v1/admin/sessions/revoke-by-ip", _UserService_RevokeSessionsByIP0_HTTP_Handler(srv))
+	r.POST("/v1/admin/tokens/revoke-all", _UserService_RevokeAllTokens0_HTTP_Handler(srv))
+	r.POST("/v1/user/tokens", _UserService_CreatePersonalToken0_HTTP_Handler(srv))
+	r.GET("/v1/user/tokens", _UserService_ListPersonalTokens0_HTTP_Handler(srv))
+	r.DELETE("/v1/user/tokens/{id}", _UserService_RevokePersonalToken0_HTTP_Handler(srv))
+	r.POST("/v1/user/tokens/{id}/rotate", _UserService_RotatePersonalToken0_HTTP_Handler(srv))
 }
 
 func _UserService_GetCurrentUser0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
@@ -76,9 +104,174 @@ func _UserService_UpdateCurrentUser0_HTTP_Handler(srv UserServiceHTTPServer) fun
 	}
 }
 
+func _UserService_ListErrorReasons0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListErrorReasonsRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationUserServiceListErrorReasons)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListErrorReasons(ctx, req.(*ListErrorReasonsRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListErrorReasonsResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _UserService_RevokeSessionsByIP0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in RevokeSessionsByIPRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationUserServiceRevokeSessionsByIP)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.RevokeSessionsByIP(ctx, req.(*RevokeSessionsByIPRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*RevokeSessionsByIPResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _UserService_RevokeAllTokens0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in RevokeAllTokensRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationUserServiceRevokeAllTokens)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.RevokeAllTokens(ctx, req.(*RevokeAllTokensRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*RevokeAllTokensResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _UserService_CreatePersonalToken0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in CreatePersonalTokenRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationUserServiceCreatePersonalToken)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.CreatePersonalToken(ctx, req.(*CreatePersonalTokenRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*CreatePersonalTokenResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _UserService_ListPersonalTokens0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListPersonalTokensRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationUserServiceListPersonalTokens)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListPersonalTokens(ctx, req.(*ListPersonalTokensRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListPersonalTokensResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _UserService_RevokePersonalToken0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in RevokePersonalTokenRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationUserServiceRevokePersonalToken)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.RevokePersonalToken(ctx, req.(*RevokePersonalTokenRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*RevokePersonalTokenResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _UserService_RotatePersonalToken0_HTTP_Handler(srv UserServiceHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in RotatePersonalTokenRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationUserServiceRotatePersonalToken)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.RotatePersonalToken(ctx, req.(*RotatePersonalTokenRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*RotatePersonalTokenResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
 type UserServiceHTTPClient interface {
+	// CreatePersonalToken 创建个人访问令牌，令牌明文只在响应中返回一次
+	CreatePersonalToken(ctx context.Context, req *CreatePersonalTokenRequest, opts ...http.CallOption) (rsp *CreatePersonalTokenResponse, err error)
 	// GetCurrentUser 获取当前用户资料
 	GetCurrentUser(ctx context.Context, req *GetCurrentUserRequest, opts ...http.CallOption) (rsp *GetCurrentUserResponse, err error)
+	// ListErrorReasons 列出所有错误原因及其 HTTP 状态码、业务错误码映射（仅管理员），用于生成客户端 SDK 和文档
+	ListErrorReasons(ctx context.Context, req *ListErrorReasonsRequest, opts ...http.CallOption) (rsp *ListErrorReasonsResponse, err error)
+	// ListPersonalTokens 列出当前用户未撤销的个人访问令牌，不包含令牌明文
+	ListPersonalTokens(ctx context.Context, req *ListPersonalTokensRequest, opts ...http.CallOption) (rsp *ListPersonalTokensResponse, err error)
+	// RevokeAllTokens 使当前时间之前签发的所有访问令牌和刷新令牌失效（仅管理员），用于泄露等事件后的全局登出，需开启 auth.enforce_tokens_valid_after
+	RevokeAllTokens(ctx context.Context, req *RevokeAllTokensRequest, opts ...http.CallOption) (rsp *RevokeAllTokensResponse, err error)
+	// RevokePersonalToken 撤销个人访问令牌
+	RevokePersonalToken(ctx context.Context, req *RevokePersonalTokenRequest, opts ...http.CallOption) (rsp *RevokePersonalTokenResponse, err error)
+	// RevokeSessionsByIP 撤销所有用户中来源 IP 为指定地址的会话（仅管理员），用于处置被盗用或异常的来源
+	RevokeSessionsByIP(ctx context.Context, req *RevokeSessionsByIPRequest, opts ...http.CallOption) (rsp *RevokeSessionsByIPResponse, err error)
+	// RotatePersonalToken 重新生成个人访问令牌，名称和权限范围不变，旧令牌立即失效
+	RotatePersonalToken(ctx context.Context, req *RotatePersonalTokenRequest, opts ...http.CallOption) (rsp *RotatePersonalTokenResponse, err error)
 	// UpdateCurrentUser 更新当前用户资料
 	UpdateCurrentUser(ctx context.Context, req *UpdateCurrentUserRequest, opts ...http.CallOption) (rsp *UpdateCurrentUserResponse, err error)
 }
@@ -91,6 +284,20 @@ func NewUserServiceHTTPClient(client *http.Client) UserServiceHTTPClient {
 	return &UserServiceHTTPClientImpl{client}
 }
 
+// CreatePersonalToken 创建个人访问令牌，令牌明文只在响应中返回一次
+func (c *UserServiceHTTPClientImpl) CreatePersonalToken(ctx context.Context, in *CreatePersonalTokenRequest, opts ...http.CallOption) (*CreatePersonalTokenResponse, error) {
+	var out CreatePersonalTokenResponse
+	pattern := "/v1/user/tokens"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationUserServiceCreatePersonalToken))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // GetCurrentUser 获取当前用户资料
 func (c *UserServiceHTTPClientImpl) GetCurrentUser(ctx context.Context, in *GetCurrentUserRequest, opts ...http.CallOption) (*GetCurrentUserResponse, error) {
 	var out GetCurrentUserResponse
@@ -105,6 +312,90 @@ func (c *UserServiceHTTPClientImpl) GetCurrentUser(ctx context.Context, in *GetC
 	return &out, nil
 }
 
+// ListErrorReasons 列出所有错误原因及其 HTTP 状态码、业务错误码映射（仅管理员），用于生成客户端 SDK 和文档
+func (c *UserServiceHTTPClientImpl) ListErrorReasons(ctx context.Context, in *ListErrorReasonsRequest, opts ...http.CallOption) (*ListErrorReasonsResponse, error) {
+	var out ListErrorReasonsResponse
+	pattern := "/v1/admin/error-reasons"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation(OperationUserServiceListErrorReasons))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPersonalTokens 列出当前用户未撤销的个人访问令牌，不包含令牌明文
+func (c *UserServiceHTTPClientImpl) ListPersonalTokens(ctx context.Context, in *ListPersonalTokensRequest, opts ...http.CallOption) (*ListPersonalTokensResponse, error) {
+	var out ListPersonalTokensResponse
+	pattern := "/v1/user/tokens"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation(OperationUserServiceListPersonalTokens))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeAllTokens 使当前时间之前签发的所有访问令牌和刷新令牌失效（仅管理员），用于泄露等事件后的全局登出，需开启 auth.enforce_tokens_valid_after
+func (c *UserServiceHTTPClientImpl) RevokeAllTokens(ctx context.Context, in *RevokeAllTokensRequest, opts ...http.CallOption) (*RevokeAllTokensResponse, error) {
+	var out RevokeAllTokensResponse
+	pattern := "/v1/admin/tokens/revoke-all"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationUserServiceRevokeAllTokens))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokePersonalToken 撤销个人访问令牌
+func (c *UserServiceHTTPClientImpl) RevokePersonalToken(ctx context.Context, in *RevokePersonalTokenRequest, opts ...http.CallOption) (*RevokePersonalTokenResponse, error) {
+	var out RevokePersonalTokenResponse
+	pattern := "/v1/user/tokens/{id}"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation(OperationUserServiceRevokePersonalToken))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "DELETE", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeSessionsByIP 撤销所有用户中来源 IP 为指定地址的会话（仅管理员），用于处置被盗用或异常的来源
+func (c *UserServiceHTTPClientImpl) RevokeSessionsByIP(ctx context.Context, in *RevokeSessionsByIPRequest, opts ...http.CallOption) (*RevokeSessionsByIPResponse, error) {
+	var out RevokeSessionsByIPResponse
+	pattern := "/v1/admin/sessions/revoke-by-ip"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationUserServiceRevokeSessionsByIP))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RotatePersonalToken 重新生成个人访问令牌，名称和权限范围不变，旧令牌立即失效
+func (c *UserServiceHTTPClientImpl) RotatePersonalToken(ctx context.Context, in *RotatePersonalTokenRequest, opts ...http.CallOption) (*RotatePersonalTokenResponse, error) {
+	var out RotatePersonalTokenResponse
+	pattern := "/v1/user/tokens/{id}/rotate"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationUserServiceRotatePersonalToken))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // UpdateCurrentUser 更新当前用户资料
 func (c *UserServiceHTTPClientImpl) UpdateCurrentUser(ctx context.Context, in *UpdateCurrentUserRequest, opts ...http.CallOption) (*UpdateCurrentUserResponse, error) {
 	var out UpdateCurrentUserResponse
@@ -6,7 +6,9 @@ import (
 	"os"
 
 	"user/internal/conf"
+	"user/internal/pkg/logsample"
 	"user/internal/pkg/tracing"
+	"user/internal/server"
 
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/config"
@@ -36,7 +38,7 @@ func init() {
 	flag.StringVar(&flagconf, "conf", "../../configs", "config path, eg: -conf config.yaml")
 }
 
-func newApp(logger log.Logger, gs *grpc.Server, hs *http.Server) *kratos.App {
+func newApp(logger log.Logger, gs *grpc.Server, hs *http.Server, archiveJob *server.PointArchiveJob, statsSampler *server.UserStatsSampler) *kratos.App {
 	return kratos.New(
 		kratos.ID(id),
 		kratos.Name(Name),
@@ -46,21 +48,14 @@ func newApp(logger log.Logger, gs *grpc.Server, hs *http.Server) *kratos.App {
 		kratos.Server(
 			gs,
 			hs,
+			archiveJob,
+			statsSampler,
 		),
 	)
 }
 
 func main() {
 	flag.Parse()
-	logger := log.With(log.NewStdLogger(os.Stdout),
-		"ts", log.DefaultTimestamp,
-		"caller", log.DefaultCaller,
-		"service.id", id,
-		"service.name", Name,
-		"service.version", Version,
-		"trace.id", kratostracing.TraceID(),
-		"span.id", kratostracing.SpanID(),
-	)
 	c := config.New(
 		config.WithSource(
 			file.NewSource(flagconf),
@@ -76,6 +71,24 @@ func main() {
 	if err := c.Scan(&bc); err != nil {
 		panic(err)
 	}
+	conf.ApplyEnvSecrets(&bc)
+	if err := conf.Validate(&bc); err != nil {
+		panic(err)
+	}
+	if err := conf.ValidateSigningKeys(&bc); err != nil {
+		panic(err)
+	}
+
+	// 采样包在最内层，caller 等字段仍指向实际调用处
+	logger := log.With(logsample.NewLogger(log.NewStdLogger(os.Stdout), int(bc.Log.GetInfoSampleRate())),
+		"ts", log.DefaultTimestamp,
+		"caller", log.DefaultCaller,
+		"service.id", id,
+		"service.name", Name,
+		"service.version", Version,
+		"trace.id", kratostracing.TraceID(),
+		"span.id", kratostracing.SpanID(),
+	)
 
 	// Initialize tracing
 	var tp *sdktrace.TracerProvider
@@ -93,7 +106,7 @@ func main() {
 		}
 	}
 
-	app, cleanup, err := wireApp(bc.Server, bc.Data, bc.Email, logger)
+	app, cleanup, err := wireApp(bc.Server, bc.Data, bc.Email, bc.Auth, bc.Pagination, bc.Biz, logger)
 	if err != nil {
 		panic(err)
 	}
@@ -24,30 +24,57 @@ import (
 // Injectors from wire.go:
 
 // wireApp init kratos application.
-func wireApp(confServer *conf.Server, confData *conf.Data, email *conf.Email, logger log.Logger) (*kratos.App, func(), error) {
+func wireApp(confServer *conf.Server, confData *conf.Data, email *conf.Email, auth *conf.Auth, pagination *conf.Pagination, confBiz *conf.Biz, logger log.Logger) (*kratos.App, func(), error) {
 	dataData, cleanup, err := data.NewData(confData, logger)
 	if err != nil {
 		return nil, nil, err
 	}
-	authRepository := data.NewAuthRepository(dataData, logger)
-	authUsecase := biz.NewAuthUsecase(authRepository, logger)
 	db := data.NewDB(dataData)
 	userRepository := data.NewUserRepository(db, logger)
-	codeRepository := data.NewCodeRepository(dataData, logger)
+	authRepository, cleanup2 := data.NewAuthRepositoryFromConfig(confData, dataData, logger)
+	authConfig := biz.NewAuthConfig(auth)
+	clock := biz.NewSystemClock()
+	slowOperationConfig := biz.NewSlowOperationConfig(confBiz)
+	slowOperationLogger := biz.NewSlowOperationLogger(clock, slowOperationConfig, logger)
+	authUsecase := biz.NewAuthUsecase(userRepository, authRepository, authConfig, slowOperationLogger, logger)
+	codeRepository, cleanup3 := data.NewCodeRepositoryFromConfig(confData, dataData, logger)
 	snowflakeConfig := snowflake.DefaultSnowflakeConfig()
 	snowflakeGenerator, err := snowflake.NewSnowflakeGenerator(snowflakeConfig, logger)
 	if err != nil {
+		cleanup3()
+		cleanup2()
 		cleanup()
 		return nil, nil, err
 	}
+	emailSender := data.NewEmailSender(email, logger)
+	emailLogRepository := data.NewEmailLogRepository(db, logger)
 	emailConfig := biz.NewEmailConfig(email)
-	userUsecase := biz.NewUserUsecase(userRepository, codeRepository, authRepository, snowflakeGenerator, emailConfig, logger)
-	authService := service.NewAuthService(authUsecase, userUsecase, logger)
-	userService := service.NewUserService(userUsecase, logger)
-	grpcServer := server.NewGRPCServer(confServer, authService, userService, logger)
-	httpServer := server.NewHTTPServer(confServer, authService, userService, logger)
-	app := newApp(logger, grpcServer, httpServer)
+	codeHasher := biz.NewCodeHasher(auth)
+	captchaVerifier := biz.NewCaptchaVerifier()
+	userUsecase := biz.NewUserUsecase(userRepository, codeRepository, authRepository, snowflakeGenerator, emailSender, emailLogRepository, emailConfig, authConfig, codeHasher, captchaVerifier, slowOperationLogger, logger)
+	accountIDFormatter := service.NewAccountIDFormatter(confServer)
+	authService := service.NewAuthService(authUsecase, userUsecase, accountIDFormatter, logger)
+	userPointRepository := data.NewUserPointRepository(db, logger)
+	pointTransactionRepository := data.NewPointTransactionRepository(db, confData, logger)
+	transaction := data.NewTransaction(db)
+	bizPagination := biz.NewPagination(pagination)
+	pointConfig := biz.NewPointConfig(confBiz)
+	pointUsecase := biz.NewPointUsecase(userPointRepository, pointTransactionRepository, transaction, bizPagination, pointConfig, slowOperationLogger, logger)
+	userDataExportUsecase := biz.NewUserDataExportUsecase(userUsecase, pointUsecase, logger)
+	personalTokenRepository := data.NewPersonalTokenRepository(db, logger)
+	personalTokenUsecase := biz.NewPersonalTokenUsecase(personalTokenRepository, clock, authConfig, logger)
+	accountDeletionConfig := biz.NewAccountDeletionConfig(confBiz)
+	accountMaintenanceUsecase := biz.NewAccountMaintenanceUsecase(userRepository, authRepository, userPointRepository, pointTransactionRepository, transaction, accountDeletionConfig, logger)
+	userService := service.NewUserService(userUsecase, userDataExportUsecase, personalTokenUsecase, accountMaintenanceUsecase, accountIDFormatter, authConfig, logger)
+	pointService := service.NewPointService(pointUsecase, authConfig, logger)
+	grpcServer := server.NewGRPCServer(confServer, authService, userService, pointService, logger)
+	httpServer := server.NewHTTPServer(confServer, authService, userService, pointService, logger)
+	pointArchiveJob := server.NewPointArchiveJob(pointUsecase, logger)
+	userStatsSampler := server.NewUserStatsSampler(userUsecase, logger)
+	app := newApp(logger, grpcServer, httpServer, pointArchiveJob, userStatsSampler)
 	return app, func() {
+		cleanup3()
+		cleanup2()
 		cleanup()
 	}, nil
 }
@@ -0,0 +1,218 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+// DuplicateEmailGroup 规范化后邮箱相同的一组账号，UserIDs 按ID升序
+type DuplicateEmailGroup struct {
+	NormalizedEmail string
+	UserIDs         []int64
+}
+
+// 删除账号时点数的处理策略
+const (
+	// PointDeletionPolicyRetain 保留点数余额和流水
+	PointDeletionPolicyRetain = "retain"
+	// PointDeletionPolicyZeroOut 清零点数余额，并写入一条消耗流水作为审计记录
+	PointDeletionPolicyZeroOut = "zero_out"
+	// PointDeletionPolicyArchive 将点数记录和流水移入归档表
+	PointDeletionPolicyArchive = "archive"
+)
+
+// TransactionSourceAccountDeletion 删除账号清零点数时写入的流水来源
+const TransactionSourceAccountDeletion = "account_deletion"
+
+// AccountDeletionConfig 账号删除配置
+type AccountDeletionConfig struct {
+	// PointPolicy 删除账号时点数的处理策略（retain、zero_out、archive），为空时保留
+	PointPolicy string
+}
+
+// AccountMaintenanceUsecase 账号数据维护，供管理员处理历史数据
+type AccountMaintenanceUsecase struct {
+	userRepo  UserRepository
+	authRepo  AuthRepository
+	pointRepo UserPointRepository
+	txnRepo   PointTransactionRepository
+	tx        Transaction
+	deletion  AccountDeletionConfig
+	log       *log.Helper
+}
+
+// NewAccountMaintenanceUsecase 创建账号数据维护实例
+func NewAccountMaintenanceUsecase(userRepo UserRepository, authRepo AuthRepository, pointRepo UserPointRepository, txnRepo PointTransactionRepository, tx Transaction, deletion AccountDeletionConfig, logger log.Logger) *AccountMaintenanceUsecase {
+	return &AccountMaintenanceUsecase{
+		userRepo:  userRepo,
+		authRepo:  authRepo,
+		pointRepo: pointRepo,
+		txnRepo:   txnRepo,
+		tx:        tx,
+		deletion:  deletion,
+		log:       log.NewHelper(logger),
+	}
+}
+
+// FindDuplicateEmails 查找邮箱仅大小写不同的重复账号
+func (uc *AccountMaintenanceUsecase) FindDuplicateEmails(ctx context.Context) ([]*DuplicateEmailGroup, error) {
+	ctx, span := tracing.StartSpan(ctx, "AccountMaintenanceUsecase.FindDuplicateEmails")
+	defer span.End()
+
+	groups, err := uc.userRepo.FindDuplicateEmails(ctx)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to find duplicate emails, error_reason: %v", err)
+		return nil, error_reason.ErrorUserDatabaseError("查询重复邮箱失败")
+	}
+
+	uc.log.WithContext(ctx).Infof("Found %d duplicate email groups", len(groups))
+	return groups, nil
+}
+
+// MergeDuplicateAccounts 将 removeID 账号的点数和流水合并到 keepID 账号，并软删除 removeID 账号
+// 两个账号必须不同、均未删除且邮箱规范化后相同；合并在同一个事务中完成，完成后吊销被合并账号的所有刷新令牌
+func (uc *AccountMaintenanceUsecase) MergeDuplicateAccounts(ctx context.Context, keepID, removeID int64) error {
+	ctx, span := tracing.StartSpan(ctx, "AccountMaintenanceUsecase.MergeDuplicateAccounts")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "merge_duplicate_accounts",
+		"keep_id":   keepID,
+		"remove_id": removeID,
+	})
+
+	if keepID == removeID {
+		uc.log.WithContext(ctx).Warnf("Refusing to merge account into itself, user id: %d", keepID)
+		return error_reason.ErrorUserInvalidRequest("不能将账号合并到自身")
+	}
+
+	keep, err := uc.getUser(ctx, keepID)
+	if err != nil {
+		return err
+	}
+	remove, err := uc.getUser(ctx, removeID)
+	if err != nil {
+		return err
+	}
+
+	if NormalizeEmail(keep.Email) != NormalizeEmail(remove.Email) {
+		uc.log.WithContext(ctx).Warnf("Refusing to merge accounts with different emails, keep id: %d, remove id: %d", keepID, removeID)
+		return error_reason.ErrorUserInvalidRequest("只能合并邮箱相同的账号")
+	}
+
+	var reassigned int64
+	err = uc.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := uc.pointRepo.MergeInto(ctx, removeID, keepID); err != nil {
+			return err
+		}
+		n, err := uc.txnRepo.ReassignUser(ctx, removeID, keepID)
+		if err != nil {
+			return err
+		}
+		reassigned = n
+		return uc.userRepo.SoftDelete(ctx, removeID)
+	})
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to merge account %d into %d, error_reason: %v", removeID, keepID, err)
+		return error_reason.ErrorUserDatabaseError("合并账号失败")
+	}
+
+	// 令牌存储不在数据库事务内，吊销失败不影响合并结果
+	if err := uc.authRepo.DeleteAllRefreshTokens(ctx, removeID); err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to revoke refresh tokens of merged account: %d, error_reason: %v", removeID, err)
+	}
+
+	uc.log.WithContext(ctx).Infof("Merged account %d into %d, reassigned transactions: %d", removeID, keepID, reassigned)
+	return nil
+}
+
+// DeleteUser 软删除用户，并在同一事务中按配置的策略处理其点数和流水，完成后吊销该用户的所有刷新令牌
+func (uc *AccountMaintenanceUsecase) DeleteUser(ctx context.Context, userID int64) error {
+	ctx, span := tracing.StartSpan(ctx, "AccountMaintenanceUsecase.DeleteUser")
+	defer span.End()
+
+	policy := uc.deletion.PointPolicy
+	if policy == "" {
+		policy = PointDeletionPolicyRetain
+	}
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":    "delete_user",
+		"user_id":      userID,
+		"point_policy": policy,
+	})
+
+	err := uc.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.SoftDelete(ctx, userID); err != nil {
+			return err
+		}
+		return uc.applyPointDeletionPolicy(ctx, userID, policy)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			uc.log.WithContext(ctx).Warnf("User not found or already deleted, id: %d", userID)
+			return error_reason.ErrorUserNotFound("用户不存在")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to delete user: %d, point policy: %s, error_reason: %v", userID, policy, err)
+		return error_reason.ErrorUserDatabaseError("删除账号失败")
+	}
+
+	// 令牌存储不在数据库事务内，吊销失败不影响删除结果
+	if err := uc.authRepo.DeleteAllRefreshTokens(ctx, userID); err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to revoke refresh tokens of deleted user: %d, error_reason: %v", userID, err)
+	}
+
+	uc.log.WithContext(ctx).Infof("Deleted user: %d, point policy: %s", userID, policy)
+	return nil
+}
+
+// applyPointDeletionPolicy 在删除账号的事务中按策略处理用户的点数和流水
+func (uc *AccountMaintenanceUsecase) applyPointDeletionPolicy(ctx context.Context, userID int64, policy string) error {
+	switch policy {
+	case PointDeletionPolicyZeroOut:
+		cleared, err := uc.pointRepo.ZeroOut(ctx, userID)
+		if err != nil || cleared == 0 {
+			return err
+		}
+		metadata, err := json.Marshal(map[string]interface{}{TransactionMetadataSource: TransactionSourceAccountDeletion})
+		if err != nil {
+			return err
+		}
+		return uc.txnRepo.CreateBatch(ctx, []*PointTransaction{{
+			UserID:      userID,
+			Type:        TransactionTypeConsume,
+			Amount:      cleared,
+			Description: "账号删除，点数清零",
+			Metadata:    datatypes.JSON(metadata),
+		}})
+	case PointDeletionPolicyArchive:
+		if err := uc.pointRepo.Archive(ctx, userID); err != nil {
+			return err
+		}
+		_, err := uc.txnRepo.Archive(ctx, userID)
+		return err
+	default:
+		return nil
+	}
+}
+
+// getUser 获取未删除的用户，不存在时返回 USER_NOT_FOUND
+func (uc *AccountMaintenanceUsecase) getUser(ctx context.Context, id int64) (*User, error) {
+	user, err := uc.userRepo.GetByIDPublic(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			uc.log.WithContext(ctx).Warnf("User not found with id: %d", id)
+			return nil, error_reason.ErrorUserNotFound("用户不存在")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to get user with id: %d, error_reason: %v", id, err)
+		return nil, error_reason.ErrorUserDatabaseError("用户查询失败")
+	}
+	return user, nil
+}
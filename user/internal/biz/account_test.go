@@ -0,0 +1,101 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	error_reason "user/api/error_reason"
+)
+
+// TestAccountMaintenanceUsecase_MergeDuplicateAccounts 测试合并重复账号的前置校验和事务流程
+func TestAccountMaintenanceUsecase_MergeDuplicateAccounts(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepID     int64
+		removeID   int64
+		setupMocks func(*MockUserRepository, *MockAuthRepository, *MockUserPointRepository, *MockPointTransactionRepository)
+		checkErr   func(error) bool
+	}{
+		{
+			name:     "合并成功",
+			keepID:   1,
+			removeID: 2,
+			setupMocks: func(userRepo *MockUserRepository, authRepo *MockAuthRepository, pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				userRepo.On("GetByIDPublic", mock.Anything, int64(1)).Return(&User{ID: 1, Email: "a@example.com"}, nil)
+				userRepo.On("GetByIDPublic", mock.Anything, int64(2)).Return(&User{ID: 2, Email: "A@Example.com"}, nil)
+				pointRepo.On("MergeInto", mock.Anything, int64(2), int64(1)).Return(nil)
+				txnRepo.On("ReassignUser", mock.Anything, int64(2), int64(1)).Return(int64(3), nil)
+				userRepo.On("SoftDelete", mock.Anything, int64(2)).Return(nil)
+				authRepo.On("DeleteAllRefreshTokens", mock.Anything, int64(2)).Return(nil)
+			},
+		},
+		{
+			name:     "不能合并到自身",
+			keepID:   1,
+			removeID: 1,
+			setupMocks: func(*MockUserRepository, *MockAuthRepository, *MockUserPointRepository, *MockPointTransactionRepository) {
+			},
+			checkErr: error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:     "邮箱不同拒绝合并",
+			keepID:   1,
+			removeID: 2,
+			setupMocks: func(userRepo *MockUserRepository, authRepo *MockAuthRepository, pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				userRepo.On("GetByIDPublic", mock.Anything, int64(1)).Return(&User{ID: 1, Email: "a@example.com"}, nil)
+				userRepo.On("GetByIDPublic", mock.Anything, int64(2)).Return(&User{ID: 2, Email: "b@example.com"}, nil)
+			},
+			checkErr: error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:     "被合并账号不存在",
+			keepID:   1,
+			removeID: 2,
+			setupMocks: func(userRepo *MockUserRepository, authRepo *MockAuthRepository, pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				userRepo.On("GetByIDPublic", mock.Anything, int64(1)).Return(&User{ID: 1, Email: "a@example.com"}, nil)
+				userRepo.On("GetByIDPublic", mock.Anything, int64(2)).Return((*User)(nil), gorm.ErrRecordNotFound)
+			},
+			checkErr: error_reason.IsUserNotFound,
+		},
+		{
+			name:     "事务内失败不吊销令牌",
+			keepID:   1,
+			removeID: 2,
+			setupMocks: func(userRepo *MockUserRepository, authRepo *MockAuthRepository, pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				userRepo.On("GetByIDPublic", mock.Anything, int64(1)).Return(&User{ID: 1, Email: "a@example.com"}, nil)
+				userRepo.On("GetByIDPublic", mock.Anything, int64(2)).Return(&User{ID: 2, Email: "A@example.com"}, nil)
+				pointRepo.On("MergeInto", mock.Anything, int64(2), int64(1)).Return(nil)
+				txnRepo.On("ReassignUser", mock.Anything, int64(2), int64(1)).Return(int64(0), errors.New("database error_reason"))
+			},
+			checkErr: error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			pointRepo := new(MockUserPointRepository)
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(userRepo, authRepo, pointRepo, txnRepo)
+
+			uc := NewAccountMaintenanceUsecase(userRepo, authRepo, pointRepo, txnRepo, &MockTransaction{}, AccountDeletionConfig{}, getTestLogger())
+			err := uc.MergeDuplicateAccounts(context.Background(), tt.keepID, tt.removeID)
+
+			if tt.checkErr != nil {
+				assert.True(t, tt.checkErr(err), "unexpected error: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+			authRepo.AssertExpectations(t)
+			pointRepo.AssertExpectations(t)
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
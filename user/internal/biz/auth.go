@@ -2,12 +2,18 @@ package biz
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 	error_reason "user/api/error_reason"
 	"user/internal/pkg/tracing"
@@ -20,31 +26,303 @@ var (
 
 	// ErrTokenExpired 当令牌过期时返回
 	ErrTokenExpired = errors.New("token expired")
+
+	// ErrTokenNotFound 当刷新令牌在存储中不存在（已过期、已登出或被轮换）时返回
+	ErrTokenNotFound = errors.New("refresh token not found")
+
+	// ErrRevokeIncomplete 批量吊销刷新令牌时部分令牌未能判断归属或未能删除，这些令牌可能仍然有效，调用方可重试
+	ErrRevokeIncomplete = errors.New("refresh token revocation incomplete")
 )
 
+// RevokeIncompleteError 批量吊销刷新令牌的部分失败结果，可用 errors.Is(err, ErrRevokeIncomplete) 判断
+// FailedKeys 含令牌原文，只用于重试，不要写入日志
+type RevokeIncompleteError struct {
+	// FailedKeys 未能判断归属或未能删除的令牌 key
+	FailedKeys []string
+	// Deleted 已成功删除的令牌数
+	Deleted int64
+	// Cause 第一个失败的原因
+	Cause error
+}
+
+func (e *RevokeIncompleteError) Error() string {
+	return fmt.Sprintf("%v: %d keys failed, %d deleted: %v", ErrRevokeIncomplete, len(e.FailedKeys), e.Deleted, e.Cause)
+}
+
+// Is 使 errors.Is(err, ErrRevokeIncomplete) 成立
+func (e *RevokeIncompleteError) Is(target error) bool {
+	return target == ErrRevokeIncomplete
+}
+
+func (e *RevokeIncompleteError) Unwrap() error {
+	return e.Cause
+}
+
 // TokenPair 令牌对，包含访问令牌和刷新令牌
 type TokenPair struct {
 	AccessToken      string
 	AccessExpiresIn  int32
 	RefreshToken     string
 	RefreshExpiresIn int32
+	// RefreshTokenRotated 刷新时是否签发了新的刷新令牌，未轮换时 RefreshToken 为原令牌
+	RefreshTokenRotated bool
+}
+
+const (
+	// defaultRefreshTokenTTL 默认刷新令牌有效期
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+	// defaultRememberMeRefreshTokenTTL 默认"记住我"刷新令牌有效期
+	defaultRememberMeRefreshTokenTTL = 30 * 24 * time.Hour
+	// defaultAccessRefreshThreshold 默认访问令牌提前刷新阈值（剩余有效期占总有效期的比例）
+	defaultAccessRefreshThreshold = 0.2
+	// validateTokensWorkers 批量校验访问令牌时的最大并发数
+	validateTokensWorkers = 8
+)
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	RefreshTokenTTL           time.Duration
+	RememberMeRefreshTokenTTL time.Duration
+	// AdminUserIDs 管理员用户ID列表
+	AdminUserIDs []int64
+	// EnrichAccessToken 访问令牌是否携带角色、权限范围等自定义声明
+	EnrichAccessToken bool
+	// MaxActiveSessions 每个用户最多同时保持的会话数，超过时踢出最早的会话，0 表示不限制
+	MaxActiveSessions int
+	// RefreshRotationThreshold 刷新令牌剩余有效期占总有效期的比例不高于该值时才轮换，0 表示每次刷新都轮换
+	RefreshRotationThreshold float64
+	// PasswordHashScheme 新密码使用的哈希算法（bcrypt 或 argon2id），为空时使用 bcrypt
+	PasswordHashScheme string
+	// BindTokenToClient 访问令牌是否绑定签发时的客户端指纹，校验时指纹不一致则拒绝
+	BindTokenToClient bool
+	// RefreshGracePeriod 刷新令牌轮换后的宽限期，期间重放旧令牌返回同一组新令牌，0 表示不启用
+	RefreshGracePeriod time.Duration
+	// RegistrationCaptchaThreshold 同一 IP 在窗口内的注册请求超过该次数后必须通过人机验证，0 表示不启用
+	RegistrationCaptchaThreshold int
+	// RegistrationCaptchaWindow 注册请求按 IP 计数的时间窗口，0 表示使用默认值 1h
+	RegistrationCaptchaWindow time.Duration
+	// AccessRefreshThreshold 访问令牌剩余有效期占总有效期的比例不高于该值时提示客户端提前刷新，0 表示使用默认值 0.2
+	AccessRefreshThreshold float64
+	// RefreshIdleTimeout 会话空闲超时，刷新令牌超过该时长未使用即视为过期，0 表示不启用
+	RefreshIdleTimeout time.Duration
+	// MaxNicknameWidth 昵称最大显示宽度，中日韩等全角字符计为 2，0 表示使用默认值
+	MaxNicknameWidth int
+	// RequireEmailVerification 开启后邮箱未验证的账号（如导入或 SSO 创建的账号）不能登录
+	RequireEmailVerification bool
+	// EnforceTokensValidAfter 开启后拒绝签发时间早于全局失效时间点的访问令牌和刷新令牌，失效时间点由管理员通过 RevokeAllTokens 推进
+	EnforceTokensValidAfter bool
+	// MaxPersonalTokens 每个用户最多持有的未撤销个人访问令牌数，0 表示使用默认值 10
+	MaxPersonalTokens int
+}
+
+// IsAdmin 判断用户是否为管理员
+func (c AuthConfig) IsAdmin(userID int64) bool {
+	for _, id := range c.AdminUserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenFingerprint 开启令牌绑定时返回当前请求的客户端指纹，否则返回空字符串（不绑定）
+func (c AuthConfig) tokenFingerprint(ctx context.Context) string {
+	if !c.BindTokenToClient {
+		return ""
+	}
+	return ClientFingerprintFromContext(ctx)
+}
+
+// shouldRotateRefreshToken 根据剩余有效期判断刷新时是否需要轮换刷新令牌
+func (c AuthConfig) shouldRotateRefreshToken(remaining, lifetime time.Duration) bool {
+	if c.RefreshRotationThreshold <= 0 || c.RefreshRotationThreshold >= 1 || lifetime <= 0 {
+		return true
+	}
+	return float64(remaining) <= c.RefreshRotationThreshold*float64(lifetime)
+}
+
+// shouldRefreshAccessToken 根据剩余有效期判断客户端是否应提前刷新访问令牌
+func (c AuthConfig) shouldRefreshAccessToken(remaining, lifetime time.Duration) bool {
+	threshold := c.AccessRefreshThreshold
+	if threshold <= 0 || threshold > 1 {
+		threshold = defaultAccessRefreshThreshold
+	}
+	if lifetime <= 0 {
+		return true
+	}
+	return float64(remaining) <= threshold*float64(lifetime)
+}
+
+// rotatedRefreshTokenTTL 返回轮换后新刷新令牌的有效期
+// 原令牌总有效期 lifetime 长于普通有效期时视为"记住我"会话，按当前配置的"记住我"有效期签发
+func (c AuthConfig) rotatedRefreshTokenTTL(lifetime time.Duration) time.Duration {
+	return c.refreshTokenTTL(lifetime > c.refreshTokenTTL(false))
+}
+
+// refreshTokenTTL 根据是否"记住我"返回刷新令牌有效期，未配置时使用默认值
+func (c AuthConfig) refreshTokenTTL(rememberMe bool) time.Duration {
+	if rememberMe {
+		if c.RememberMeRefreshTokenTTL > 0 {
+			return c.RememberMeRefreshTokenTTL
+		}
+		return defaultRememberMeRefreshTokenTTL
+	}
+	if c.RefreshTokenTTL > 0 {
+		return c.RefreshTokenTTL
+	}
+	return defaultRefreshTokenTTL
+}
+
+const (
+	// RoleUser 普通用户角色
+	RoleUser = "user"
+	// RoleAdmin 管理员角色
+	RoleAdmin = "admin"
+	// ScopePremium 付费用户权限范围
+	ScopePremium = "premium"
+)
+
+// AccessClaims 访问令牌的自定义声明
+type AccessClaims struct {
+	Roles   []string `json:"roles,omitempty"`
+	Scopes  []string `json:"scopes,omitempty"`
+	Premium bool     `json:"premium,omitempty"`
+	// RefreshTokenID 与该访问令牌一同签发（或刷新时使用）的刷新令牌的 jti
+	RefreshTokenID string `json:"rti,omitempty"`
+	// Fingerprint 令牌绑定的客户端指纹，未开启绑定时为空
+	Fingerprint string `json:"fpt,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// TokenSubject 访问令牌携带的用户身份信息，供下游鉴权使用
+type TokenSubject struct {
+	UserID  int64
+	Roles   []string
+	Scopes  []string
+	Premium bool
+	// RefreshTokenID 访问令牌配对的刷新令牌 jti，仅在解析访问令牌时填充
+	RefreshTokenID string
+	// Fingerprint 访问令牌绑定的客户端指纹，为空表示不绑定
+	Fingerprint string
+	// IssuedAt/ExpiresAt 访问令牌的签发时间和过期时间，仅在解析访问令牌时填充
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// TokenResult 批量校验中单个访问令牌的结果，校验通过时 Err 为 nil
+type TokenResult struct {
+	UserID int64
+	// Err 校验失败的原因，与 ValidateToken 返回的错误一致
+	Err error
+}
+
+// TokenExpiry 访问令牌的剩余有效期
+type TokenExpiry struct {
+	// ExpiresIn 剩余有效期（秒）
+	ExpiresIn int32
+	// ShouldRefresh 剩余有效期已低于提前刷新阈值，客户端应主动刷新
+	ShouldRefresh bool
+}
+
+// newTokenSubject 根据用户记录构建令牌身份信息，未开启 EnrichAccessToken 时只携带用户ID
+func newTokenSubject(user *User, c AuthConfig) TokenSubject {
+	subject := TokenSubject{UserID: user.ID}
+	if !c.EnrichAccessToken {
+		return subject
+	}
+
+	subject.Roles = []string{RoleUser}
+	if c.IsAdmin(user.ID) {
+		subject.Roles = append(subject.Roles, RoleAdmin)
+	}
+	if user.IsPremium == 1 {
+		subject.Premium = true
+		subject.Scopes = append(subject.Scopes, ScopePremium)
+	}
+	return subject
+}
+
+// HasRole 判断是否拥有指定角色
+func (s *TokenSubject) HasRole(role string) bool {
+	for _, r := range s.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope 判断是否拥有指定权限范围
+func (s *TokenSubject) HasScope(scope string) bool {
+	for _, sc := range s.Scopes {
+		if sc == scope {
+			return true
+		}
+	}
+	return false
 }
 
 // AuthRepository 认证数据访问接口，定义了令牌相关的数据操作方法
 type AuthRepository interface {
 	// Token相关操作
 	StoreRefreshToken(ctx context.Context, userID int64, refreshToken string, expiresAt time.Time) error
+	// GetUserIDByRefreshToken 令牌不存在时返回 ErrTokenNotFound，存储访问失败时返回包装后的底层错误
 	GetUserIDByRefreshToken(ctx context.Context, refreshToken string) (int64, error)
 	DeleteRefreshToken(ctx context.Context, refreshToken string) error
+	// DeleteAllRefreshTokens 删除用户的全部刷新令牌；部分令牌未能判断归属或未能删除时返回 *RevokeIncompleteError
 	DeleteAllRefreshTokens(ctx context.Context, userID int64) error
-	// 事务方法
-	RefreshTokenAtomically(ctx context.Context, userID int64, oldToken, newToken string, expiresAt time.Time) error
+	// VerifyAndRotate 原子地校验旧刷新令牌存在且属于 userID，删除旧令牌并存储有效期为 ttl 的新令牌
+	// 旧令牌已被撤销、轮换或属于其他用户时返回 false 且不做任何修改
+	VerifyAndRotate(ctx context.Context, oldToken, newToken string, userID int64, ttl time.Duration) (bool, error)
+	// 轮换宽限期
+	// StoreRotatedTokenPair 以旧刷新令牌为 key 缓存轮换签发的令牌对，ttl 后失效
+	StoreRotatedTokenPair(ctx context.Context, oldToken string, pair *TokenPair, ttl time.Duration) error
+	// GetRotatedTokenPair 返回旧刷新令牌在宽限期内缓存的令牌对，不存在或已过期时返回 ErrTokenNotFound
+	GetRotatedTokenPair(ctx context.Context, oldToken string) (*TokenPair, error)
+	// 会话数限制
+	// TrackSession 将刷新令牌记入用户的会话索引，createdAt 用于判断会话新旧
+	TrackSession(ctx context.Context, userID int64, refreshToken string, createdAt, expiresAt time.Time) error
+	// EvictOldestSessions 按创建时间从旧到新删除用户的会话，直到最多保留 keep 个，返回删除的数量
+	EvictOldestSessions(ctx context.Context, userID int64, keep int) (int, error)
+	// 令牌配对
+	// PairAccessToken 将访问令牌 jti 记入刷新令牌 jti 对应的令牌族，令牌族在 expiresAt（刷新令牌过期时间）时失效
+	PairAccessToken(ctx context.Context, refreshTokenID, accessTokenID string, expiresAt time.Time) error
+	// GetPairedAccessTokenIDs 返回刷新令牌 jti 对应令牌族中所有访问令牌的 jti，令牌族不存在时返回空列表
+	GetPairedAccessTokenIDs(ctx context.Context, refreshTokenID string) ([]string, error)
+	// 密码校验失败计数
+	// GetPasswordFailures 返回用户在统计窗口内的密码校验失败次数，没有记录时返回 0
+	GetPasswordFailures(ctx context.Context, userID int64) (int, error)
+	// IncrPasswordFailures 将用户的密码校验失败次数加一并返回加一后的次数，计数在最后一次失败 window 之后清零
+	IncrPasswordFailures(ctx context.Context, userID int64, window time.Duration) (int, error)
+	// ResetPasswordFailures 清除用户的密码校验失败次数
+	ResetPasswordFailures(ctx context.Context, userID int64) error
+	// 会话空闲超时
+	// TouchRefreshToken 记录刷新令牌的最近使用时间，记录在 idleTimeout 后失效
+	TouchRefreshToken(ctx context.Context, refreshToken string, usedAt time.Time, idleTimeout time.Duration) error
+	// GetRefreshTokenLastUsed 返回刷新令牌的最近使用时间，没有记录（签发后未使用或记录已失效）时返回 ErrTokenNotFound
+	GetRefreshTokenLastUsed(ctx context.Context, refreshToken string) (time.Time, error)
+	// 会话来源 IP
+	// RecordSessionIP 记录刷新令牌签发时的客户端 IP，记录在 expiresAt（刷新令牌过期时间）时失效
+	RecordSessionIP(ctx context.Context, userID int64, refreshToken, ip string, expiresAt time.Time) error
+	// RevokeSessionsByIP 删除所有用户中来源 IP 为 ip 的刷新令牌，返回删除的数量
+	RevokeSessionsByIP(ctx context.Context, ip string) (int, error)
+	// 活跃会话数
+	// CountActiveSessions 统计所有用户未过期的刷新令牌数量，用于定期采样活跃会话数指标
+	CountActiveSessions(ctx context.Context) (int64, error)
+	// 全局令牌失效时间点
+	// GetTokensValidAfter 返回全局令牌失效时间点，签发时间早于该时间的令牌均无效；从未设置时返回零值
+	GetTokensValidAfter(ctx context.Context) (time.Time, error)
+	// SetTokensValidAfter 设置全局令牌失效时间点
+	SetTokensValidAfter(ctx context.Context, validAfter time.Time) error
 }
 
 // AuthUsecase 认证业务逻辑，处理用户注册、登录、令牌刷新等认证相关操作
 type AuthUsecase struct {
-	authRepo AuthRepository // 认证数据访问接口
-	log      *log.Helper    // 日志助手
+	userRepo   UserRepository       // 用户数据访问接口
+	authRepo   AuthRepository       // 认证数据访问接口
+	authConfig AuthConfig           // 认证配置
+	slowOp     *SlowOperationLogger // 慢操作日志
+	log        *log.Helper          // 日志助手
 }
 
 // NewAuthUsecase 创建认证业务逻辑实例
@@ -52,19 +330,33 @@ type AuthUsecase struct {
 // 参数:
 //   - userRepo: 用户数据访问接口
 //   - authRepo: 认证数据访问接口
+//   - authConfig: 认证配置
+//   - slowOp: 慢操作日志记录器
 //   - logger: 日志记录器
 //
 // 返回值:
 //   - *AuthUsecase: 认证业务逻辑实例
-func NewAuthUsecase(authRepo AuthRepository, logger log.Logger) *AuthUsecase {
+func NewAuthUsecase(userRepo UserRepository, authRepo AuthRepository, authConfig AuthConfig, slowOp *SlowOperationLogger, logger log.Logger) *AuthUsecase {
 	return &AuthUsecase{
-		authRepo: authRepo,
-		log:      log.NewHelper(logger),
+		userRepo:   userRepo,
+		authRepo:   authRepo,
+		authConfig: authConfig,
+		slowOp:     slowOp,
+		log:        log.NewHelper(logger),
+	}
+}
+
+// newTokenID 生成随机的令牌ID（jti）
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(b), nil
 }
 
-// generateAccessToken 生成访问令牌（JWT）
-func generateAccessToken(userID int64) (string, int32, error) {
+// generateAccessToken 生成访问令牌（JWT），refreshTokenID 为配对的刷新令牌 jti，返回令牌、令牌 jti 和有效期（秒）
+func generateAccessToken(subject TokenSubject, refreshTokenID string) (string, string, int32, error) {
 	// 设置过期时间为1小时
 	expiresIn := int32(3600)
 	expirationTime := time.Now().Add(time.Duration(expiresIn) * time.Second)
@@ -72,15 +364,28 @@ func generateAccessToken(userID int64) (string, int32, error) {
 	// 从环境变量获取JWT访问令牌密钥
 	secret := os.Getenv("JWT_ACCESS_SECRET")
 	if secret == "" {
-		return "", 0, error_reason.ErrorAuthDatabaseError("JWT访问令牌密钥未配置")
+		return "", "", 0, error_reason.ErrorAuthDatabaseError("JWT访问令牌密钥未配置")
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", "", 0, err
 	}
 
 	// 创建声明
-	claims := &jwt.RegisteredClaims{
-		Subject:   fmt.Sprintf("%d", userID),
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		NotBefore: jwt.NewNumericDate(time.Now()),
+	claims := &AccessClaims{
+		Roles:          subject.Roles,
+		Scopes:         subject.Scopes,
+		Premium:        subject.Premium,
+		RefreshTokenID: refreshTokenID,
+		Fingerprint:    subject.Fingerprint,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   fmt.Sprintf("%d", subject.UserID),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+		},
 	}
 
 	// 创建token
@@ -89,22 +394,26 @@ func generateAccessToken(userID int64) (string, int32, error) {
 	// 签名并获得完整的编码后的字符串token
 	tokenString, err := token.SignedString([]byte(secret))
 	if err != nil {
-		return "", 0, err
+		return "", "", 0, err
 	}
 
-	return tokenString, expiresIn, nil
+	return tokenString, tokenID, expiresIn, nil
 }
 
-// generateRefreshToken 生成刷新令牌（JWT）
-func generateRefreshToken(userID int64) (string, int32, error) {
-	// 设置过期时间为7天
-	expiresIn := int32(7 * 24 * 3600)
+// generateRefreshToken 生成刷新令牌（JWT），返回令牌、令牌 jti 和有效期（秒）
+func generateRefreshToken(userID int64, ttl time.Duration) (string, string, int32, error) {
+	expiresIn := int32(ttl / time.Second)
 	expirationTime := time.Now().Add(time.Duration(expiresIn) * time.Second)
 
 	// 从环境变量获取JWT刷新令牌密钥
 	secret := os.Getenv("JWT_REFRESH_SECRET")
 	if secret == "" {
-		return "", 0, error_reason.ErrorAuthDatabaseError("JWT刷新令牌密钥未配置")
+		return "", "", 0, error_reason.ErrorAuthDatabaseError("JWT刷新令牌密钥未配置")
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", "", 0, err
 	}
 
 	// 创建声明
@@ -113,7 +422,7 @@ func generateRefreshToken(userID int64) (string, int32, error) {
 		ExpiresAt: jwt.NewNumericDate(expirationTime),
 		IssuedAt:  jwt.NewNumericDate(time.Now()),
 		NotBefore: jwt.NewNumericDate(time.Now()),
-		ID:        "refresh_" + string(rune(userID)),
+		ID:        tokenID,
 	}
 
 	// 创建token
@@ -121,17 +430,62 @@ func generateRefreshToken(userID int64) (string, int32, error) {
 
 	// 签名并获得完整的编码后的字符串token
 	tokenString, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	return tokenString, tokenID, expiresIn, nil
+}
+
+// errRefreshSecretMissing 未配置刷新令牌密钥，无法校验刷新令牌
+var errRefreshSecretMissing = errors.New("JWT_REFRESH_SECRET is not configured")
+
+// parseRefreshTokenClaims 校验刷新令牌的签名算法、签名和有效期并返回其声明
+// 令牌格式错误、签名不匹配、缺少过期时间或已过期时返回包装了 ErrInvalidToken 的错误
+func parseRefreshTokenClaims(refreshToken string) (*jwt.RegisteredClaims, error) {
+	secret := os.Getenv("JWT_REFRESH_SECRET")
+	if secret == "" {
+		return nil, errRefreshSecretMissing
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(refreshToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// refreshTokenLifetime 根据刷新令牌的签发和过期时间，返回剩余有效期和总有效期
+func refreshTokenLifetime(claims *jwt.RegisteredClaims, now time.Time) (time.Duration, time.Duration, error) {
+	if claims.IssuedAt == nil || claims.ExpiresAt == nil {
+		return 0, 0, errors.New("refresh token missing iat or exp")
+	}
+
+	return claims.ExpiresAt.Sub(now), claims.ExpiresAt.Sub(claims.IssuedAt.Time), nil
+}
+
+// issuePairedAccessToken 签发与刷新令牌配对的访问令牌，并将配对关系记入刷新令牌的令牌族
+// 配对记录写入失败只记录日志，不影响令牌签发
+func issuePairedAccessToken(ctx context.Context, authRepo AuthRepository, logger *log.Helper, subject TokenSubject, refreshTokenID string, refreshExpiresAt time.Time) (string, int32, error) {
+	accessToken, accessTokenID, accessExpiresIn, err := generateAccessToken(subject, refreshTokenID)
 	if err != nil {
 		return "", 0, err
 	}
 
-	return tokenString, expiresIn, nil
+	if err := authRepo.PairAccessToken(ctx, refreshTokenID, accessTokenID, refreshExpiresAt); err != nil {
+		logger.WithContext(ctx).Warnf("Failed to pair access token with refresh token for user id: %d, error_reason: %v", subject.UserID, err)
+	}
+	return accessToken, accessExpiresIn, nil
 }
 
-// RefreshToken 刷新访问令牌
+// RefreshToken 刷新访问令牌，配置了 RefreshRotationThreshold 时刷新令牌仅在临近过期时轮换
 func (uc *AuthUsecase) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
 	ctx, span := tracing.StartSpan(ctx, "AuthUsecase.RefreshToken")
 	defer span.End()
+	defer uc.slowOp.Track(ctx, "RefreshToken")()
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
 		"operation":    "refresh_token",
@@ -146,39 +500,171 @@ func (uc *AuthUsecase) RefreshToken(ctx context.Context, refreshToken string) (*
 		return nil, error_reason.ErrorUserRefreshTokenInvalid("刷新令牌不能为空")
 	}
 
+	// 查询存储之前先校验签名和有效期，格式错误、被篡改或已过期的令牌直接拒绝
+	claims, err := parseRefreshTokenClaims(refreshToken)
+	if err != nil {
+		if errors.Is(err, errRefreshSecretMissing) {
+			uc.log.WithContext(ctx).Error("JWT_REFRESH_SECRET environment variable is required")
+			return nil, error_reason.ErrorAuthDatabaseError("JWT刷新令牌密钥未配置")
+		}
+		uc.log.WithContext(ctx).Warnf("Refresh token verification failed, error_reason: %v", err)
+		return nil, error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效")
+	}
+
 	// 验证刷新令牌
 	userID, err := uc.authRepo.GetUserIDByRefreshToken(ctx, refreshToken)
 	if err != nil {
-		uc.log.WithContext(ctx).Warn("Invalid refresh token provided")
-		return nil, error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效")
+		if errors.Is(err, ErrTokenNotFound) {
+			// 客户端未收到上次刷新的响应而重试时，宽限期内返回上次签发的令牌对
+			if pair := uc.recentlyRotatedPair(ctx, refreshToken); pair != nil {
+				return pair, nil
+			}
+			// 签名有效但已不在存储中的令牌通常已被轮换或撤销，再次使用可能意味着令牌泄露
+			uc.reportTokenFamily(ctx, claims)
+			uc.log.WithContext(ctx).Warn("Invalid refresh token provided")
+			return nil, error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效")
+		}
+		// 存储不可用时不能断定令牌无效，返回 503 让客户端稍后重试而不是要求重新登录
+		uc.log.WithContext(ctx).Errorf("Failed to look up refresh token, error_reason: %v", err)
+		return nil, error_reason.ErrorUserServiceUnavailable("令牌服务暂不可用")
+	}
+
+	// 全局撤销之前签发的刷新令牌不能再换取新令牌，否则访问令牌的失效可以通过刷新绕过
+	if err := uc.checkTokenValidAfter(ctx, claims.IssuedAt); err != nil {
+		if !errors.Is(err, errTokenIssuedBeforeCutoff) {
+			return nil, error_reason.ErrorUserServiceUnavailable("令牌服务暂不可用")
+		}
+		if err := uc.authRepo.DeleteRefreshToken(ctx, refreshToken); err != nil {
+			uc.log.WithContext(ctx).Warnf("Failed to revoke refresh token issued before global revocation for user id: %d, error_reason: %v", userID, err)
+		}
+		uc.log.WithContext(ctx).Warnf("Refresh token issued before global revocation for user id: %d", userID)
+		return nil, error_reason.ErrorUserRefreshTokenInvalid("会话已失效，请重新登录")
+	}
+
+	// 超过空闲期未使用的会话在绝对有效期之前即失效，并撤销该刷新令牌
+	if uc.authConfig.RefreshIdleTimeout > 0 && uc.refreshTokenIdle(ctx, refreshToken) {
+		if err := uc.authRepo.DeleteRefreshToken(ctx, refreshToken); err != nil {
+			uc.log.WithContext(ctx).Warnf("Failed to revoke idle refresh token for user id: %d, error_reason: %v", userID, err)
+		}
+		uc.log.WithContext(ctx).Warnf("Refresh token idle timeout exceeded for user id: %d", userID)
+		return nil, error_reason.ErrorUserRefreshTokenInvalid("会话长时间未使用已过期，请重新登录")
 	}
 
+	// 开启自定义声明时重新读取用户信息，使新令牌反映最新的角色和付费状态
+	subject := TokenSubject{UserID: userID}
+	if uc.authConfig.EnrichAccessToken {
+		user, err := uc.userRepo.GetByIDPublic(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				uc.log.WithContext(ctx).Warnf("User not found during token refresh, user id: %d", userID)
+				return nil, error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效")
+			}
+			uc.log.WithContext(ctx).Errorf("Failed to get user during token refresh, user id: %d, error_reason: %v", userID, err)
+			return nil, error_reason.ErrorUserDatabaseError("用户查询失败")
+		}
+		subject = newTokenSubject(user, uc.authConfig)
+	}
+	subject.Fingerprint = uc.authConfig.tokenFingerprint(ctx)
+
 	// 使用事务确保令牌刷新的原子性
-	return uc.refreshTokenInTransaction(ctx, userID, refreshToken)
+	pair, err := uc.refreshTokenInTransaction(ctx, subject, refreshToken, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每次刷新重置空闲计时，记录失败时会话仍可按签发时间计算空闲期
+	if uc.authConfig.RefreshIdleTimeout > 0 {
+		if err := uc.authRepo.TouchRefreshToken(ctx, pair.RefreshToken, time.Now(), uc.authConfig.RefreshIdleTimeout); err != nil {
+			uc.log.WithContext(ctx).Warnf("Failed to record refresh token usage for user id: %d, error_reason: %v", userID, err)
+		}
+	}
+	return pair, nil
 }
 
-// refreshTokenInTransaction 在事务中刷新令牌
-func (uc *AuthUsecase) refreshTokenInTransaction(ctx context.Context, userID int64, oldRefreshToken string) (*TokenPair, error) {
-	// 生成新的令牌对
-	accessToken, accessExpiresIn, err := generateAccessToken(userID)
-	if err != nil {
-		uc.log.WithContext(ctx).Errorf("Failed to generate access token during refresh for user id: %d, error_reason: %v", userID, err)
-		return nil, error_reason.ErrorUserInternalError("访问令牌生成失败")
+// refreshTokenIdle 判断刷新令牌是否已超过空闲期未使用
+// 没有使用记录时以令牌签发时间作为最近使用时间；无法确定最近使用时间时不拦截
+func (uc *AuthUsecase) refreshTokenIdle(ctx context.Context, refreshToken string) bool {
+	lastUsed, err := uc.authRepo.GetRefreshTokenLastUsed(ctx, refreshToken)
+	if errors.Is(err, ErrTokenNotFound) {
+		claims, parseErr := parseRefreshTokenClaims(refreshToken)
+		if parseErr != nil || claims.IssuedAt == nil {
+			uc.log.WithContext(ctx).Warnf("Failed to read refresh token issue time, skipping idle check, error_reason: %v", parseErr)
+			return false
+		}
+		lastUsed = claims.IssuedAt.Time
+	} else if err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to get refresh token last used time, skipping idle check, error_reason: %v", err)
+		return false
+	}
+	return time.Since(lastUsed) > uc.authConfig.RefreshIdleTimeout
+}
+
+// refreshTokenInTransaction 在事务中刷新令牌，claims 为已校验的原刷新令牌声明
+func (uc *AuthUsecase) refreshTokenInTransaction(ctx context.Context, subject TokenSubject, oldRefreshToken string, claims *jwt.RegisteredClaims) (*TokenPair, error) {
+	userID := subject.UserID
+
+	remaining, lifetime, lifetimeErr := refreshTokenLifetime(claims, time.Now())
+	if lifetimeErr != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to read refresh token lifetime for user id: %d, using standard ttl, error_reason: %v", userID, lifetimeErr)
+	}
+
+	// 刷新令牌剩余有效期充足时沿用原令牌，只签发与原令牌配对的新访问令牌；无法解析有效期时按轮换处理
+	if uc.authConfig.RefreshRotationThreshold > 0 {
+		now := time.Now()
+		if lifetimeErr == nil && !uc.authConfig.shouldRotateRefreshToken(remaining, lifetime) {
+			accessToken, accessExpiresIn, err := issuePairedAccessToken(ctx, uc.authRepo, uc.log, subject, claims.ID, now.Add(remaining))
+			if err != nil {
+				uc.log.WithContext(ctx).Errorf("Failed to generate access token during refresh for user id: %d, error_reason: %v", userID, err)
+				return nil, error_reason.ErrorUserInternalError("访问令牌生成失败").WithCause(tracing.WithStack(err))
+			}
+			uc.log.WithContext(ctx).Infof("Token refresh without rotation for user id: %d", userID)
+			return &TokenPair{
+				AccessToken:      accessToken,
+				AccessExpiresIn:  accessExpiresIn,
+				RefreshToken:     oldRefreshToken,
+				RefreshExpiresIn: int32(remaining / time.Second),
+			}, nil
+		}
 	}
 
-	newRefreshToken, refreshExpiresIn, err := generateRefreshToken(userID)
+	// 生成新的令牌对，新刷新令牌沿用原令牌的有效期类别，"记住我"会话轮换后仍使用延长的有效期
+	newRefreshToken, newRefreshTokenID, refreshExpiresIn, err := generateRefreshToken(userID, uc.authConfig.rotatedRefreshTokenTTL(lifetime))
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to generate refresh token during refresh for user id: %d, error_reason: %v", userID, err)
-		return nil, error_reason.ErrorUserInternalError("刷新令牌生成失败")
+		return nil, error_reason.ErrorUserInternalError("刷新令牌生成失败").WithCause(tracing.WithStack(err))
 	}
 
-	// 使用原子操作刷新令牌
-	refreshTokenExpiresAt := time.Now().Add(time.Duration(refreshExpiresIn) * time.Second)
-	err = uc.authRepo.RefreshTokenAtomically(ctx, userID, oldRefreshToken, newRefreshToken, refreshTokenExpiresAt)
+	// 校验旧令牌和轮换在存储端一次完成，查询之后旧令牌被撤销或已被并发请求轮换时轮换失败
+	now := time.Now()
+	refreshTTL := time.Duration(refreshExpiresIn) * time.Second
+	refreshTokenExpiresAt := now.Add(refreshTTL)
+	rotated, err := uc.authRepo.VerifyAndRotate(ctx, oldRefreshToken, newRefreshToken, userID, refreshTTL)
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to refresh token atomically for user id: %d, error_reason: %v", userID, err)
 		return nil, error_reason.ErrorUserDatabaseError("令牌刷新失败")
 	}
+	if !rotated {
+		if pair := uc.recentlyRotatedPair(ctx, oldRefreshToken); pair != nil {
+			return pair, nil
+		}
+		uc.reportTokenFamily(ctx, claims)
+		uc.log.WithContext(ctx).Warnf("Refresh token revoked or already rotated for user id: %d", userID)
+		return nil, error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效")
+	}
+
+	accessToken, accessExpiresIn, err := issuePairedAccessToken(ctx, uc.authRepo, uc.log, subject, newRefreshTokenID, refreshTokenExpiresAt)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to generate access token during refresh for user id: %d, error_reason: %v", userID, err)
+		return nil, error_reason.ErrorUserInternalError("访问令牌生成失败").WithCause(tracing.WithStack(err))
+	}
+
+	// 轮换后的令牌以刷新时间计入会话索引，旧令牌在下次踢出会话时被清理
+	if uc.authConfig.MaxActiveSessions > 0 {
+		if err := uc.authRepo.TrackSession(ctx, userID, newRefreshToken, now, refreshTokenExpiresAt); err != nil {
+			uc.log.WithContext(ctx).Warnf("Failed to track refreshed session for user id: %d, error_reason: %v", userID, err)
+		}
+	}
+	recordSessionIP(ctx, uc.authRepo, uc.log, userID, newRefreshToken, refreshTokenExpiresAt)
 
 	uc.log.WithContext(ctx).Infof("Token refresh successful for user id: %d", userID)
 	tracing.AddSpanEvent(ctx, "token_refresh_success", map[string]interface{}{
@@ -187,12 +673,58 @@ func (uc *AuthUsecase) refreshTokenInTransaction(ctx context.Context, userID int
 		"refresh_expires_in": refreshExpiresIn,
 	})
 
-	return &TokenPair{
-		AccessToken:      accessToken,
-		AccessExpiresIn:  accessExpiresIn,
-		RefreshToken:     newRefreshToken,
-		RefreshExpiresIn: refreshExpiresIn,
-	}, nil
+	pair := &TokenPair{
+		AccessToken:         accessToken,
+		AccessExpiresIn:     accessExpiresIn,
+		RefreshToken:        newRefreshToken,
+		RefreshExpiresIn:    refreshExpiresIn,
+		RefreshTokenRotated: true,
+	}
+
+	// 缓存失败只影响重试的客户端，不影响本次刷新
+	if uc.authConfig.RefreshGracePeriod > 0 {
+		if err := uc.authRepo.StoreRotatedTokenPair(ctx, oldRefreshToken, pair, uc.authConfig.RefreshGracePeriod); err != nil {
+			uc.log.WithContext(ctx).Warnf("Failed to cache rotated token pair for user id: %d, error_reason: %v", userID, err)
+		}
+	}
+
+	return pair, nil
+}
+
+// reportTokenFamily 刷新令牌被重复使用或轮换失败时查出其令牌族中的访问令牌，记录日志和链路事件，用于识别可能泄露的令牌
+// 查询失败只记录日志，不影响刷新请求的结果
+func (uc *AuthUsecase) reportTokenFamily(ctx context.Context, claims *jwt.RegisteredClaims) {
+	if claims.ID == "" {
+		return
+	}
+	accessTokenIDs, err := uc.authRepo.GetPairedAccessTokenIDs(ctx, claims.ID)
+	if err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to look up token family for refresh token: %s, error_reason: %v", claims.ID, err)
+		return
+	}
+	uc.log.WithContext(ctx).Warnf("Refresh token reused, subject: %s, refresh token id: %s, paired access token ids: %v", claims.Subject, claims.ID, accessTokenIDs)
+	tracing.AddSpanEvent(ctx, "refresh_token_reused", map[string]interface{}{
+		"subject":            claims.Subject,
+		"refresh_token_id":   claims.ID,
+		"paired_token_ids":   strings.Join(accessTokenIDs, ","),
+		"paired_token_count": len(accessTokenIDs),
+	})
+}
+
+// recentlyRotatedPair 返回旧刷新令牌在宽限期内轮换签发的令牌对，未开启宽限期或已超出宽限期时返回 nil
+func (uc *AuthUsecase) recentlyRotatedPair(ctx context.Context, oldRefreshToken string) *TokenPair {
+	if uc.authConfig.RefreshGracePeriod <= 0 {
+		return nil
+	}
+	pair, err := uc.authRepo.GetRotatedTokenPair(ctx, oldRefreshToken)
+	if err != nil {
+		if !errors.Is(err, ErrTokenNotFound) {
+			uc.log.WithContext(ctx).Warnf("Failed to look up rotated token pair, error_reason: %v", err)
+		}
+		return nil
+	}
+	uc.log.WithContext(ctx).Info("Refresh token replayed within grace period, returning cached token pair")
+	return pair
 }
 
 // Logout 用户登出
@@ -224,7 +756,7 @@ func (uc *AuthUsecase) Logout(ctx context.Context, refreshToken string) error {
 	return nil
 }
 
-// ValidateToken 验证访问令牌（JWT版本）
+// ValidateToken 验证访问令牌（JWT版本），只返回用户ID
 func (uc *AuthUsecase) ValidateToken(ctx context.Context, accessToken string) (int64, error) {
 	ctx, span := tracing.StartSpan(ctx, "AuthUsecase.ValidateToken")
 	defer span.End()
@@ -234,53 +766,164 @@ func (uc *AuthUsecase) ValidateToken(ctx context.Context, accessToken string) (i
 		"token_length": len(accessToken),
 	})
 
+	subject, err := uc.parseAccessToken(ctx, accessToken)
+	if err != nil {
+		return 0, err
+	}
+	return subject.UserID, nil
+}
+
+// ValidateTokens 批量验证访问令牌，供网关一次校验多个令牌
+// 每个令牌独立校验，结果与 tokens 按下标一一对应，单个令牌无效不影响其他令牌
+func (uc *AuthUsecase) ValidateTokens(ctx context.Context, tokens []string) []TokenResult {
+	ctx, span := tracing.StartSpan(ctx, "AuthUsecase.ValidateTokens")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":   "validate_tokens",
+		"token_count": len(tokens),
+	})
+
+	results := make([]TokenResult, len(tokens))
+	sem := make(chan struct{}, validateTokensWorkers)
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, token string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			subject, err := uc.parseAccessToken(ctx, token)
+			if err != nil {
+				results[i] = TokenResult{Err: err}
+				return
+			}
+			results[i] = TokenResult{UserID: subject.UserID}
+		}(i, token)
+	}
+	wg.Wait()
+	return results
+}
+
+// ValidateTokenWithClaims 验证访问令牌并返回其中的角色、权限范围等身份信息
+func (uc *AuthUsecase) ValidateTokenWithClaims(ctx context.Context, accessToken string) (*TokenSubject, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthUsecase.ValidateTokenWithClaims")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":    "validate_token_with_claims",
+		"token_length": len(accessToken),
+	})
+
+	return uc.parseAccessToken(ctx, accessToken)
+}
+
+// TokenExpiry 返回访问令牌的剩余有效期，以及是否已临近过期需要客户端提前刷新
+// 已过期的令牌返回 USER_TOKEN_EXPIRED 错误
+func (uc *AuthUsecase) TokenExpiry(ctx context.Context, accessToken string) (*TokenExpiry, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthUsecase.TokenExpiry")
+	defer span.End()
+
+	subject, err := uc.ValidateTokenWithClaims(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := time.Until(subject.ExpiresAt)
+	lifetime := subject.ExpiresAt.Sub(subject.IssuedAt)
+	return &TokenExpiry{
+		ExpiresIn:     int32(remaining.Seconds()),
+		ShouldRefresh: uc.authConfig.shouldRefreshAccessToken(remaining, lifetime),
+	}, nil
+}
+
+// numericDateTime 将 JWT 时间声明转换为 time.Time，声明缺失时返回零值
+func numericDateTime(d *jwt.NumericDate) time.Time {
+	if d == nil {
+		return time.Time{}
+	}
+	return d.Time
+}
+
+// parseAccessToken 解析并校验访问令牌
+func (uc *AuthUsecase) parseAccessToken(ctx context.Context, accessToken string) (*TokenSubject, error) {
 	// 参数验证
 	if accessToken == "" {
 		uc.log.WithContext(ctx).Warn("Empty access token provided for validation")
-		return 0, error_reason.ErrorUserInvalidToken("访问令牌不能为空")
+		return nil, error_reason.ErrorUserInvalidToken("访问令牌不能为空")
 	}
 
 	// 从环境变量获取JWT访问令牌密钥
 	secret := os.Getenv("JWT_ACCESS_SECRET")
 	if secret == "" {
 		uc.log.WithContext(ctx).Error("JWT_ACCESS_SECRET environment variable is required")
-		return 0, error_reason.ErrorAuthDatabaseError("JWT访问令牌密钥未配置")
+		return nil, error_reason.ErrorAuthDatabaseError("JWT访问令牌密钥未配置")
 	}
 
 	// 解析和验证JWT令牌
-	token, err := jwt.ParseWithClaims(accessToken, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &AccessClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(secret), nil
 	})
 
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		uc.log.WithContext(ctx).Warn("Access token has expired")
+		return nil, error_reason.ErrorUserTokenExpired("访问令牌已过期")
+	}
 	if err != nil {
 		uc.log.WithContext(ctx).Warnf("Failed to parse access token, error_reason: %v", err)
-		return 0, error_reason.ErrorUserInvalidToken("访问令牌格式无效")
+		return nil, error_reason.ErrorUserInvalidToken("访问令牌格式无效")
 	}
 
 	// 验证令牌是否有效
 	if !token.Valid {
 		uc.log.WithContext(ctx).Warn("Invalid access token provided")
-		return 0, error_reason.ErrorUserInvalidToken("访问令牌无效")
+		return nil, error_reason.ErrorUserInvalidToken("访问令牌无效")
 	}
 
 	// 获取声明
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok {
+	if claims, ok := token.Claims.(*AccessClaims); ok {
 		// 检查是否过期
 		if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
 			uc.log.WithContext(ctx).Warn("Access token has expired")
-			return 0, error_reason.ErrorUserTokenExpired("访问令牌已过期")
+			return nil, error_reason.ErrorUserTokenExpired("访问令牌已过期")
 		}
 
 		// 解析用户ID
 		userID, err := strconv.ParseInt(claims.Subject, 10, 64)
 		if err != nil {
 			uc.log.WithContext(ctx).Warn("Failed to parse user id from access token")
-			return 0, error_reason.ErrorUserInvalidToken("访问令牌用户信息无效")
+			return nil, error_reason.ErrorUserInvalidToken("访问令牌用户信息无效")
+		}
+
+		// 开启绑定时校验客户端指纹；开启绑定前签发的令牌没有指纹声明，仍按原规则校验
+		if uc.authConfig.BindTokenToClient && claims.Fingerprint != "" &&
+			!hmac.Equal([]byte(claims.Fingerprint), []byte(ClientFingerprintFromContext(ctx))) {
+			uc.log.WithContext(ctx).Warnf("Access token client fingerprint mismatch for user id: %d", userID)
+			return nil, error_reason.ErrorUserInvalidToken("访问令牌与当前客户端不匹配")
 		}
+
+		if err := uc.checkTokenValidAfter(ctx, claims.IssuedAt); err != nil {
+			if errors.Is(err, errTokenIssuedBeforeCutoff) {
+				uc.log.WithContext(ctx).Warnf("Access token issued before global revocation for user id: %d", userID)
+				return nil, error_reason.ErrorUserInvalidToken("访问令牌已失效，请重新登录")
+			}
+			return nil, error_reason.ErrorUserServiceUnavailable("令牌服务暂不可用")
+		}
+
 		uc.log.WithContext(ctx).Infof("Token validation successful for user id: %d", userID)
-		return userID, nil
+		return &TokenSubject{
+			UserID:         userID,
+			Roles:          claims.Roles,
+			Scopes:         claims.Scopes,
+			Premium:        claims.Premium,
+			RefreshTokenID: claims.RefreshTokenID,
+			Fingerprint:    claims.Fingerprint,
+			IssuedAt:       numericDateTime(claims.IssuedAt),
+			ExpiresAt:      numericDateTime(claims.ExpiresAt),
+		}, nil
 	} else {
 		uc.log.WithContext(ctx).Warn("Failed to get claims from access token")
-		return 0, error_reason.ErrorUserInvalidToken("访问令牌格式无效")
+		return nil, error_reason.ErrorUserInvalidToken("访问令牌格式无效")
 	}
 }
@@ -2,15 +2,24 @@ package biz
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	kerrors "github.com/go-kratos/kratos/v2/errors"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	error_reason "user/api/error_reason"
 )
 
 // TestAuthUsecase_RefreshToken 测试令牌刷新
@@ -18,6 +27,8 @@ func TestAuthUsecase_RefreshToken(t *testing.T) {
 	setupTestEnv()
 	defer cleanupTestEnv()
 
+	validToken := signTestRefreshToken(t, time.Now(), time.Now().Add(time.Hour))
+
 	tests := []struct {
 		name         string
 		refreshToken string
@@ -27,15 +38,15 @@ func TestAuthUsecase_RefreshToken(t *testing.T) {
 	}{
 		{
 			name:         "成功刷新令牌",
-			refreshToken: "valid-refresh-token",
+			refreshToken: validToken,
 			setupMocks: func(authRepo *MockAuthRepository) {
 				// 模拟成功获取用户ID
-				authRepo.On("GetUserIDByRefreshToken", mock.Anything, "valid-refresh-token").
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, validToken).
 					Return(int64(123), nil)
 
 				// 模拟原子刷新成功
-				authRepo.On("RefreshTokenAtomically", mock.Anything, int64(123), "valid-refresh-token", mock.Anything, mock.Anything).
-					Return(nil)
+				authRepo.On("VerifyAndRotate", mock.Anything, validToken, mock.Anything, int64(123), mock.Anything).
+					Return(true, nil)
 			},
 			wantErr: false,
 		},
@@ -46,55 +57,87 @@ func TestAuthUsecase_RefreshToken(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken,
+			expectedErr: error_reason.ErrorUserRefreshTokenInvalid("刷新令牌不能为空"),
+		},
+		{
+			name:         "签名被篡改的令牌不查询存储",
+			refreshToken: tamperTestToken(t, validToken),
+			wantErr:      true,
+			expectedErr:  error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效"),
+		},
+		{
+			name:         "格式错误的令牌不查询存储",
+			refreshToken: "not-a-jwt",
+			wantErr:      true,
+			expectedErr:  error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效"),
+		},
+		{
+			name:         "已过期的令牌不查询存储",
+			refreshToken: signTestRefreshToken(t, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)),
+			wantErr:      true,
+			expectedErr:  error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效"),
 		},
 		{
 			name:         "无效的刷新令牌",
-			refreshToken: "invalid-token",
+			refreshToken: validToken,
 			setupMocks: func(authRepo *MockAuthRepository) {
-				// 模拟令牌不存在
-				authRepo.On("GetUserIDByRefreshToken", mock.Anything, "invalid-token").
-					Return(int64(0), errors.New("token not found"))
+				// 签名有效但存储中不存在
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, validToken).
+					Return(int64(0), ErrTokenNotFound)
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken,
+			expectedErr: error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效"),
 		},
 		{
 			name:         "用户ID获取失败",
-			refreshToken: "error_reason-token",
+			refreshToken: validToken,
 			setupMocks: func(authRepo *MockAuthRepository) {
-				// 模拟数据库错误
-				authRepo.On("GetUserIDByRefreshToken", mock.Anything, "error_reason-token").
-					Return(int64(0), errors.New("database error_reason"))
+				// 模拟存储不可用
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, validToken).
+					Return(int64(0), fmt.Errorf("get refresh token: %w", errors.New("connection refused")))
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken,
+			expectedErr: error_reason.ErrorUserServiceUnavailable("令牌服务暂不可用"),
 		},
 		{
 			name:         "正常刷新流程",
-			refreshToken: "normal-refresh-token",
+			refreshToken: validToken,
 			setupMocks: func(authRepo *MockAuthRepository) {
-				authRepo.On("GetUserIDByRefreshToken", mock.Anything, "normal-refresh-token").
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, validToken).
 					Return(int64(456), nil)
 
-				authRepo.On("RefreshTokenAtomically", mock.Anything, int64(456), "normal-refresh-token", mock.Anything, mock.Anything).
-					Return(nil)
+				authRepo.On("VerifyAndRotate", mock.Anything, validToken, mock.Anything, int64(456), mock.Anything).
+					Return(true, nil)
 			},
 			wantErr: false,
 		},
 		{
 			name:         "原子刷新失败",
-			refreshToken: "atomic-fail-token",
+			refreshToken: validToken,
 			setupMocks: func(authRepo *MockAuthRepository) {
-				authRepo.On("GetUserIDByRefreshToken", mock.Anything, "atomic-fail-token").
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, validToken).
 					Return(int64(123), nil)
 
 				// 模拟原子刷新失败
-				authRepo.On("RefreshTokenAtomically", mock.Anything, int64(123), "atomic-fail-token", mock.Anything, mock.Anything).
-					Return(errors.New("redis error_reason"))
+				authRepo.On("VerifyAndRotate", mock.Anything, validToken, mock.Anything, int64(123), mock.Anything).
+					Return(false, errors.New("redis error_reason"))
 			},
 			wantErr:     true,
-			expectedErr: errors.New("redis error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("令牌刷新失败"),
+		},
+		{
+			name:         "查询后令牌被撤销",
+			refreshToken: validToken,
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, validToken).
+					Return(int64(123), nil)
+
+				// 模拟查询之后、轮换之前令牌被撤销或已被并发请求轮换
+				authRepo.On("VerifyAndRotate", mock.Anything, validToken, mock.Anything, int64(123), mock.Anything).
+					Return(false, nil)
+			},
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效"),
 		},
 	}
 
@@ -102,6 +145,7 @@ func TestAuthUsecase_RefreshToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// 创建 mock
 			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
 
 			// 设置 mock 期望
 			if tt.setupMocks != nil {
@@ -109,7 +153,7 @@ func TestAuthUsecase_RefreshToken(t *testing.T) {
 			}
 
 			// 创建 usecase
-			uc := NewAuthUsecase(authRepo, getTestLogger())
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
 
 			// 执行测试
 			tokenPair, err := uc.RefreshToken(context.Background(), tt.refreshToken)
@@ -118,7 +162,7 @@ func TestAuthUsecase_RefreshToken(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 				if tt.expectedErr != nil {
-					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+					assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
 				}
 				assert.Nil(t, tokenPair)
 			} else {
@@ -146,6 +190,9 @@ func TestAuthUsecase_RefreshToken(t *testing.T) {
 
 			// 验证所有期望都被调用
 			authRepo.AssertExpectations(t)
+			if tt.setupMocks == nil {
+				authRepo.AssertNotCalled(t, "GetUserIDByRefreshToken", mock.Anything, mock.Anything)
+			}
 		})
 	}
 }
@@ -179,7 +226,7 @@ func TestAuthUsecase_Logout(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: errors.New("refresh token is required"),
+			expectedErr: error_reason.ErrorUserRefreshTokenInvalid("刷新令牌不能为空"),
 		},
 		{
 			name:         "删除刷新令牌失败",
@@ -190,7 +237,7 @@ func TestAuthUsecase_Logout(t *testing.T) {
 					Return(errors.New("redis error_reason"))
 			},
 			wantErr:     true,
-			expectedErr: errors.New("redis error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("令牌删除失败"),
 		},
 	}
 
@@ -205,7 +252,7 @@ func TestAuthUsecase_Logout(t *testing.T) {
 			}
 
 			// 创建 usecase
-			uc := NewAuthUsecase(authRepo, getTestLogger())
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
 
 			// 执行测试
 			err := uc.Logout(context.Background(), tt.refreshToken)
@@ -214,7 +261,7 @@ func TestAuthUsecase_Logout(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 				if tt.expectedErr != nil {
-					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+					assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
 				}
 			} else {
 				assert.NoError(t, err)
@@ -232,7 +279,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 	defer cleanupTestEnv()
 
 	// 生成一个有效的访问令牌用于测试
-	validAccessToken, _, err := generateAccessToken(123)
+	validAccessToken, _, _, err := generateAccessToken(TokenSubject{UserID: 123}, "")
 	require.NoError(t, err)
 
 	// 生成一个过期的访问令牌
@@ -270,7 +317,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken,
+			expectedErr: error_reason.ErrorUserInvalidToken("访问令牌无效"),
 		},
 		{
 			name:        "无效的令牌格式",
@@ -279,7 +326,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken,
+			expectedErr: error_reason.ErrorUserInvalidToken("访问令牌无效"),
 		},
 		{
 			name:        "令牌已过期",
@@ -288,7 +335,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken, // ParseWithClaims在解析过期token时会失败
+			expectedErr: error_reason.ErrorUserTokenExpired("访问令牌已过期"),
 		},
 		{
 			name:        "错误的签名",
@@ -297,7 +344,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken,
+			expectedErr: error_reason.ErrorUserInvalidToken("访问令牌无效"),
 		},
 		{
 			name: "无效的用户ID",
@@ -317,7 +364,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidToken,
+			expectedErr: error_reason.ErrorUserInvalidToken("访问令牌无效"),
 		},
 		{
 			name:        "缺少环境变量",
@@ -327,7 +374,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 				os.Unsetenv("JWT_ACCESS_SECRET")
 			},
 			wantErr:     true,
-			expectedErr: errors.New("JWT_ACCESS_SECRET environment variable is required"),
+			expectedErr: error_reason.ErrorAuthDatabaseError("JWT访问令牌密钥未配置"),
 		},
 	}
 
@@ -345,7 +392,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 			}
 
 			// 创建 usecase
-			uc := NewAuthUsecase(authRepo, getTestLogger())
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
 
 			// 执行测试
 			userID, err := uc.ValidateToken(context.Background(), tt.accessToken)
@@ -354,7 +401,7 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 				if tt.expectedErr != nil {
-					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+					assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
 				}
 				assert.Equal(t, int64(0), userID)
 			} else {
@@ -372,3 +419,889 @@ func TestAuthUsecase_ValidateToken(t *testing.T) {
 		})
 	}
 }
+
+// TestAuthUsecase_ValidateTokens 测试批量校验时每个令牌独立返回结果，无效令牌不影响其他令牌
+func TestAuthUsecase_ValidateTokens(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	now := time.Now()
+	validToken, _, _, err := generateAccessToken(TokenSubject{UserID: 123}, "")
+	require.NoError(t, err)
+	otherToken, _, _, err := generateAccessToken(TokenSubject{UserID: 456}, "")
+	require.NoError(t, err)
+	expiredToken := signTestAccessToken(t, now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	tokens := []string{validToken, expiredToken, "not-a-jwt", "", otherToken}
+	// 超过并发数的批次同样按下标返回结果
+	for i := 0; i < validateTokensWorkers; i++ {
+		tokens = append(tokens, validToken)
+	}
+
+	uc := NewAuthUsecase(new(MockUserRepository), new(MockAuthRepository), AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
+	results := uc.ValidateTokens(context.Background(), tokens)
+
+	require.Len(t, results, len(tokens))
+	assert.Equal(t, TokenResult{UserID: 123}, results[0])
+	assert.True(t, error_reason.IsUserTokenExpired(results[1].Err), "unexpected error: %v", results[1].Err)
+	assert.True(t, error_reason.IsUserInvalidToken(results[2].Err), "unexpected error: %v", results[2].Err)
+	assert.True(t, error_reason.IsUserInvalidToken(results[3].Err), "unexpected error: %v", results[3].Err)
+	assert.Equal(t, TokenResult{UserID: 456}, results[4])
+	for _, result := range results[5:] {
+		assert.Equal(t, TokenResult{UserID: 123}, result)
+	}
+	assert.Empty(t, uc.ValidateTokens(context.Background(), nil))
+}
+
+// TestAuthConfig_IsAdmin 测试管理员判断
+func TestAuthConfig_IsAdmin(t *testing.T) {
+	c := AuthConfig{AdminUserIDs: []int64{1, 42}}
+
+	assert.True(t, c.IsAdmin(42))
+	assert.False(t, c.IsAdmin(2))
+	assert.False(t, AuthConfig{}.IsAdmin(1))
+}
+
+// signTestRefreshToken 使用测试密钥签发指定签发时间和过期时间的刷新令牌
+func signTestRefreshToken(t *testing.T, issuedAt, expiresAt time.Time) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{
+		Subject:   "123",
+		IssuedAt:  jwt.NewNumericDate(issuedAt),
+		NotBefore: jwt.NewNumericDate(issuedAt),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	})
+	signed, err := token.SignedString([]byte(os.Getenv("JWT_REFRESH_SECRET")))
+	require.NoError(t, err)
+	return signed
+}
+
+// tamperTestToken 将令牌载荷中的用户ID改为 1 并保留原签名，模拟被篡改的令牌
+func tamperTestToken(t *testing.T, token string) string {
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	claims["sub"] = "1"
+	payload, err = json.Marshal(claims)
+	require.NoError(t, err)
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	return strings.Join(parts, ".")
+}
+
+// TestAuthUsecase_RefreshToken_RotationThreshold 测试刷新令牌仅在临近过期时轮换
+func TestAuthUsecase_RefreshToken_RotationThreshold(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	now := time.Now()
+	lifetime := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name        string
+		threshold   float64
+		issuedAt    time.Time
+		wantRotated bool
+	}{
+		{
+			name:        "剩余有效期充足时沿用原刷新令牌",
+			threshold:   0.2,
+			issuedAt:    now.Add(-24 * time.Hour),
+			wantRotated: false,
+		},
+		{
+			name:        "临近过期时轮换刷新令牌",
+			threshold:   0.2,
+			issuedAt:    now.Add(-6*24*time.Hour - 12*time.Hour),
+			wantRotated: true,
+		},
+		{
+			name:        "未配置阈值时每次都轮换",
+			threshold:   0,
+			issuedAt:    now.Add(-time.Hour),
+			wantRotated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshToken := signTestRefreshToken(t, tt.issuedAt, tt.issuedAt.Add(lifetime))
+
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).Return(int64(123), nil)
+			if tt.wantRotated {
+				authRepo.On("VerifyAndRotate", mock.Anything, refreshToken, mock.Anything, int64(123), mock.Anything).Return(true, nil)
+			}
+
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{RefreshRotationThreshold: tt.threshold}, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.RefreshToken(context.Background(), refreshToken)
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, tokenPair.AccessToken)
+			assert.Equal(t, tt.wantRotated, tokenPair.RefreshTokenRotated)
+			if tt.wantRotated {
+				assert.NotEqual(t, refreshToken, tokenPair.RefreshToken)
+			} else {
+				assert.Equal(t, refreshToken, tokenPair.RefreshToken)
+				// 返回原令牌的剩余有效期
+				assert.InDelta(t, (6 * 24 * time.Hour).Seconds(), float64(tokenPair.RefreshExpiresIn), 5)
+				authRepo.AssertNotCalled(t, "VerifyAndRotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_RememberMe 测试轮换后的刷新令牌沿用原令牌的有效期类别，"记住我"会话刷新后仍使用延长的有效期
+func TestAuthUsecase_RefreshToken_RememberMe(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	config := AuthConfig{RefreshTokenTTL: 7 * 24 * time.Hour, RememberMeRefreshTokenTTL: 30 * 24 * time.Hour}
+
+	tests := []struct {
+		name     string
+		lifetime time.Duration
+		wantTTL  time.Duration
+	}{
+		{
+			name:     "记住我会话",
+			lifetime: 30 * 24 * time.Hour,
+			wantTTL:  30 * 24 * time.Hour,
+		},
+		{
+			name:     "普通会话",
+			lifetime: 7 * 24 * time.Hour,
+			wantTTL:  7 * 24 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuedAt := time.Now().Add(-time.Hour)
+			refreshToken := signTestRefreshToken(t, issuedAt, issuedAt.Add(tt.lifetime))
+
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).Return(int64(123), nil)
+			// 存储端的有效期与新令牌一致
+			authRepo.On("VerifyAndRotate", mock.Anything, refreshToken, mock.Anything, int64(123), tt.wantTTL).Return(true, nil)
+
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, config, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.RefreshToken(context.Background(), refreshToken)
+
+			require.NoError(t, err)
+			assert.Equal(t, int32(tt.wantTTL/time.Second), tokenPair.RefreshExpiresIn)
+			claims, err := parseRefreshTokenClaims(tokenPair.RefreshToken)
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(tt.wantTTL), claims.ExpiresAt.Time, 5*time.Second)
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_IdleTimeout 测试超过空闲期未使用的刷新令牌在绝对有效期之前被拒绝，定期使用的令牌持续有效
+func TestAuthUsecase_RefreshToken_IdleTimeout(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	now := time.Now()
+	idleTimeout := time.Hour
+
+	tests := []struct {
+		name        string
+		issuedAt    time.Time
+		lastUsed    time.Time
+		lastUsedErr error
+		wantIdle    bool
+	}{
+		{
+			name:     "空闲期内使用过的令牌仍然有效",
+			issuedAt: now.Add(-3 * 24 * time.Hour),
+			lastUsed: now.Add(-30 * time.Minute),
+		},
+		{
+			name:        "签发后空闲期内首次使用",
+			issuedAt:    now.Add(-30 * time.Minute),
+			lastUsedErr: ErrTokenNotFound,
+		},
+		{
+			name:        "签发后超过空闲期未使用",
+			issuedAt:    now.Add(-2 * time.Hour),
+			lastUsedErr: ErrTokenNotFound,
+			wantIdle:    true,
+		},
+		{
+			name:     "最近一次使用已超过空闲期",
+			issuedAt: now.Add(-3 * 24 * time.Hour),
+			lastUsed: now.Add(-2 * time.Hour),
+			wantIdle: true,
+		},
+		{
+			name:        "读取使用记录失败时不拦截",
+			issuedAt:    now.Add(-3 * 24 * time.Hour),
+			lastUsedErr: errors.New("redis unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshToken := signTestRefreshToken(t, tt.issuedAt, tt.issuedAt.Add(7*24*time.Hour))
+
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).Return(int64(123), nil)
+			authRepo.On("GetRefreshTokenLastUsed", mock.Anything, refreshToken).Return(tt.lastUsed, tt.lastUsedErr)
+			if tt.wantIdle {
+				authRepo.On("DeleteRefreshToken", mock.Anything, refreshToken).Return(nil)
+			} else {
+				authRepo.On("VerifyAndRotate", mock.Anything, refreshToken, mock.Anything, int64(123), mock.Anything).Return(true, nil)
+				authRepo.On("TouchRefreshToken", mock.Anything, mock.MatchedBy(func(token string) bool {
+					return token != refreshToken
+				}), mock.Anything, idleTimeout).Return(nil)
+			}
+
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{RefreshIdleTimeout: idleTimeout}, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.RefreshToken(context.Background(), refreshToken)
+
+			if tt.wantIdle {
+				assert.True(t, error_reason.IsUserRefreshTokenInvalid(err), "unexpected error: %v", err)
+				assert.Nil(t, tokenPair)
+				authRepo.AssertNotCalled(t, "VerifyAndRotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.NotEmpty(t, tokenPair.AccessToken)
+			}
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_IdleTimeoutDisabled 测试未配置空闲超时时不读取也不记录使用时间
+func TestAuthUsecase_RefreshToken_IdleTimeoutDisabled(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	refreshToken := signTestRefreshToken(t, time.Now().Add(-6*24*time.Hour), time.Now().Add(24*time.Hour))
+	authRepo := new(MockAuthRepository)
+	allowTokenPairing(authRepo)
+	authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).Return(int64(123), nil)
+	authRepo.On("VerifyAndRotate", mock.Anything, refreshToken, mock.Anything, int64(123), mock.Anything).Return(true, nil)
+
+	uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
+	_, err := uc.RefreshToken(context.Background(), refreshToken)
+
+	require.NoError(t, err)
+	authRepo.AssertNotCalled(t, "GetRefreshTokenLastUsed", mock.Anything, mock.Anything)
+	authRepo.AssertNotCalled(t, "TouchRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	authRepo.AssertExpectations(t)
+}
+
+// TestAuthUsecase_RefreshToken_LookupErrorStatus 测试令牌不存在与存储不可用映射为不同的 HTTP 状态码
+func TestAuthUsecase_RefreshToken_LookupErrorStatus(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	refreshToken := signTestRefreshToken(t, time.Now(), time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name       string
+		lookupErr  error
+		wantStatus int
+	}{
+		{
+			name:       "令牌不存在返回401",
+			lookupErr:  ErrTokenNotFound,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Redis不可用返回503",
+			lookupErr:  fmt.Errorf("get refresh token: %w", errors.New("dial tcp: connection refused")),
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authRepo := new(MockAuthRepository)
+			authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).
+				Return(int64(0), tt.lookupErr)
+
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.RefreshToken(context.Background(), refreshToken)
+
+			assert.Nil(t, tokenPair)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantStatus, kerrors.Code(err))
+			authRepo.AssertNotCalled(t, "VerifyAndRotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_ReuseLooksUpTokenFamily 测试刷新令牌被重复使用或轮换失败时查询其令牌族，查询失败不影响返回结果
+func TestAuthUsecase_RefreshToken_ReuseLooksUpTokenFamily(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{
+		ID:        "refresh-jti",
+		Subject:   "123",
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	refreshToken, err := token.SignedString([]byte(os.Getenv("JWT_REFRESH_SECRET")))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		setupMocks func(*MockAuthRepository)
+	}{
+		{
+			name: "已轮换的令牌再次使用",
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).
+					Return(int64(0), ErrTokenNotFound)
+				authRepo.On("GetPairedAccessTokenIDs", mock.Anything, "refresh-jti").
+					Return([]string{"access-jti-1", "access-jti-2"}, nil).Once()
+			},
+		},
+		{
+			name: "并发轮换失败",
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).
+					Return(int64(123), nil)
+				authRepo.On("VerifyAndRotate", mock.Anything, refreshToken, mock.Anything, int64(123), mock.Anything).
+					Return(false, nil)
+				authRepo.On("GetPairedAccessTokenIDs", mock.Anything, "refresh-jti").
+					Return([]string{"access-jti-1"}, nil).Once()
+			},
+		},
+		{
+			name: "令牌族查询失败仍返回令牌无效",
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).
+					Return(int64(0), ErrTokenNotFound)
+				authRepo.On("GetPairedAccessTokenIDs", mock.Anything, "refresh-jti").
+					Return([]string(nil), errors.New("redis unavailable")).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			tt.setupMocks(authRepo)
+
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.RefreshToken(context.Background(), refreshToken)
+
+			assert.Nil(t, tokenPair)
+			assert.Equal(t, error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效"), err)
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAuthUsecase_ValidateTokenWithClaims 测试登录签发的访问令牌携带角色和权限范围，并能通过校验还原
+func TestAuthUsecase_ValidateTokenWithClaims(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	validPassword := "password123"
+	hashedPassword, _ := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
+
+	tests := []struct {
+		name        string
+		isPremium   uint8
+		authConfig  AuthConfig
+		wantSubject TokenSubject
+	}{
+		{
+			name:       "付费用户携带premium权限范围",
+			isPremium:  1,
+			authConfig: AuthConfig{EnrichAccessToken: true},
+			wantSubject: TokenSubject{
+				UserID:  1,
+				Roles:   []string{RoleUser},
+				Scopes:  []string{ScopePremium},
+				Premium: true,
+			},
+		},
+		{
+			name:       "普通用户不携带premium权限范围",
+			isPremium:  0,
+			authConfig: AuthConfig{EnrichAccessToken: true},
+			wantSubject: TokenSubject{
+				UserID: 1,
+				Roles:  []string{RoleUser},
+			},
+		},
+		{
+			name:       "管理员携带admin角色",
+			isPremium:  0,
+			authConfig: AuthConfig{EnrichAccessToken: true, AdminUserIDs: []int64{1}},
+			wantSubject: TokenSubject{
+				UserID: 1,
+				Roles:  []string{RoleUser, RoleAdmin},
+			},
+		},
+		{
+			name:        "未开启自定义声明时只携带用户ID",
+			isPremium:   1,
+			authConfig:  AuthConfig{},
+			wantSubject: TokenSubject{UserID: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+
+			userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+				Return(&User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword, IsPremium: tt.isPremium}, nil)
+			authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).
+				Return(nil)
+
+			userUc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, tt.authConfig, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+			tokenPair, err := userUc.Login(context.Background(), "test@example.com", validPassword, false)
+			require.NoError(t, err)
+
+			authUc := NewAuthUsecase(userRepo, authRepo, tt.authConfig, newTestSlowOperationLogger(), getTestLogger())
+			subject, err := authUc.ValidateTokenWithClaims(context.Background(), tokenPair.AccessToken)
+			require.NoError(t, err)
+			// 配对的刷新令牌 jti 为随机值，单独校验
+			assert.NotEmpty(t, subject.RefreshTokenID)
+			subject.RefreshTokenID = ""
+			// 签发时间和过期时间随当前时间变化，单独校验
+			assert.WithinDuration(t, subject.IssuedAt.Add(time.Hour), subject.ExpiresAt, time.Second)
+			subject.IssuedAt, subject.ExpiresAt = time.Time{}, time.Time{}
+			assert.Equal(t, tt.wantSubject, *subject)
+			assert.Equal(t, tt.wantSubject.Premium, subject.HasScope(ScopePremium))
+
+			// ValidateToken 仍然只返回用户ID
+			userID, err := authUc.ValidateToken(context.Background(), tokenPair.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), userID)
+		})
+	}
+}
+
+// signTestAccessToken 使用测试密钥签发指定签发时间和过期时间的访问令牌
+func signTestAccessToken(t *testing.T, issuedAt, expiresAt time.Time) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "123",
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signed, err := token.SignedString([]byte(os.Getenv("JWT_ACCESS_SECRET")))
+	require.NoError(t, err)
+	return signed
+}
+
+// TestAuthUsecase_TokenExpiry 测试按剩余有效期占比判断是否需要提前刷新访问令牌
+func TestAuthUsecase_TokenExpiry(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	now := time.Now()
+	tests := []struct {
+		name              string
+		threshold         float64
+		issuedAt          time.Time
+		expiresAt         time.Time
+		wantShouldRefresh bool
+		wantExpiresIn     time.Duration
+		wantErr           func(error) bool
+	}{
+		{
+			name:          "刚签发的令牌无需刷新",
+			issuedAt:      now,
+			expiresAt:     now.Add(time.Hour),
+			wantExpiresIn: time.Hour,
+		},
+		{
+			name:              "临近过期的令牌需要刷新",
+			issuedAt:          now.Add(-50 * time.Minute),
+			expiresAt:         now.Add(10 * time.Minute),
+			wantShouldRefresh: true,
+			wantExpiresIn:     10 * time.Minute,
+		},
+		{
+			name:          "按配置的阈值判断",
+			threshold:     0.1,
+			issuedAt:      now.Add(-50 * time.Minute),
+			expiresAt:     now.Add(10 * time.Minute),
+			wantExpiresIn: 10 * time.Minute,
+		},
+		{
+			name:      "已过期的令牌",
+			issuedAt:  now.Add(-2 * time.Hour),
+			expiresAt: now.Add(-time.Hour),
+			wantErr:   error_reason.IsUserTokenExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewAuthUsecase(new(MockUserRepository), new(MockAuthRepository), AuthConfig{AccessRefreshThreshold: tt.threshold}, newTestSlowOperationLogger(), getTestLogger())
+
+			expiry, err := uc.TokenExpiry(context.Background(), signTestAccessToken(t, tt.issuedAt, tt.expiresAt))
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				assert.Nil(t, expiry)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantShouldRefresh, expiry.ShouldRefresh)
+			assert.InDelta(t, tt.wantExpiresIn.Seconds(), float64(expiry.ExpiresIn), 2)
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_EnrichAccessToken 测试刷新令牌时按最新的用户信息签发自定义声明
+func TestAuthUsecase_RefreshToken_EnrichAccessToken(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	refreshToken := signTestRefreshToken(t, time.Now(), time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name        string
+		setupMocks  func(*MockUserRepository)
+		wantPremium bool
+		expectedErr error
+	}{
+		{
+			name: "用户已升级为付费用户",
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("GetByIDPublic", mock.Anything, int64(123)).
+					Return(&User{ID: 123, IsPremium: 1}, nil)
+			},
+			wantPremium: true,
+		},
+		{
+			name: "用户已不存在",
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("GetByIDPublic", mock.Anything, int64(123)).
+					Return((*User)(nil), gorm.ErrRecordNotFound)
+			},
+			expectedErr: error_reason.ErrorUserRefreshTokenInvalid("刷新令牌无效"),
+		},
+		{
+			name: "用户查询失败",
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("GetByIDPublic", mock.Anything, int64(123)).
+					Return((*User)(nil), errors.New("database error_reason"))
+			},
+			expectedErr: error_reason.ErrorUserDatabaseError("用户查询失败"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			tt.setupMocks(userRepo)
+
+			authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).
+				Return(int64(123), nil)
+			if tt.expectedErr == nil {
+				authRepo.On("VerifyAndRotate", mock.Anything, refreshToken, mock.Anything, int64(123), mock.Anything).
+					Return(true, nil)
+			}
+
+			uc := NewAuthUsecase(userRepo, authRepo, AuthConfig{EnrichAccessToken: true}, newTestSlowOperationLogger(), getTestLogger())
+			tokenPair, err := uc.RefreshToken(context.Background(), refreshToken)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
+			} else {
+				require.NoError(t, err)
+				subject, err := uc.ValidateTokenWithClaims(context.Background(), tokenPair.AccessToken)
+				require.NoError(t, err)
+				assert.Equal(t, int64(123), subject.UserID)
+				assert.Equal(t, tt.wantPremium, subject.Premium)
+				assert.True(t, subject.HasRole(RoleUser))
+			}
+
+			userRepo.AssertExpectations(t)
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// parseTestTokenClaims 不校验签名地解析令牌声明，用于断言令牌内容
+func parseTestTokenClaims(t *testing.T, token string) *AccessClaims {
+	claims := &AccessClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	require.NoError(t, err)
+	return claims
+}
+
+// TestAuthUsecase_TokenPairing 测试访问令牌携带配对刷新令牌的 jti，且配对关系被记录
+func TestAuthUsecase_TokenPairing(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	validPassword := "password123"
+	hashedPassword, err := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
+	require.NoError(t, err)
+
+	userRepo := new(MockUserRepository)
+	authRepo := new(MockAuthRepository)
+	userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+		Return(&User{ID: 123, Email: "test@example.com", PasswordHash: hashedPassword}, nil)
+	authRepo.On("StoreRefreshToken", mock.Anything, int64(123), mock.Anything, mock.Anything).Return(nil)
+
+	// 记录每次配对写入的刷新令牌 jti 和访问令牌 jti
+	pairs := map[string][]string{}
+	authRepo.On("PairAccessToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			pairs[args.String(1)] = append(pairs[args.String(1)], args.String(2))
+		}).
+		Return(nil)
+
+	authConfig := AuthConfig{RefreshTokenTTL: 7 * 24 * time.Hour}
+	userUc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+	loginPair, err := userUc.Login(context.Background(), "test@example.com", validPassword, false)
+	require.NoError(t, err)
+
+	loginRefresh := parseTestTokenClaims(t, loginPair.RefreshToken)
+	loginAccess := parseTestTokenClaims(t, loginPair.AccessToken)
+	require.NotEmpty(t, loginRefresh.ID)
+	assert.Equal(t, loginRefresh.ID, loginAccess.RefreshTokenID, "登录签发的访问令牌应携带刷新令牌的 jti")
+	assert.Equal(t, []string{loginAccess.ID}, pairs[loginRefresh.ID])
+
+	authUc := NewAuthUsecase(userRepo, authRepo, authConfig, newTestSlowOperationLogger(), getTestLogger())
+	subject, err := authUc.ValidateTokenWithClaims(context.Background(), loginPair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, loginRefresh.ID, subject.RefreshTokenID)
+
+	t.Run("轮换后访问令牌与新刷新令牌配对", func(t *testing.T) {
+		authRepo.On("GetUserIDByRefreshToken", mock.Anything, loginPair.RefreshToken).Return(int64(123), nil).Once()
+		authRepo.On("VerifyAndRotate", mock.Anything, loginPair.RefreshToken, mock.Anything, int64(123), mock.Anything).Return(true, nil).Once()
+
+		refreshed, err := authUc.RefreshToken(context.Background(), loginPair.RefreshToken)
+		require.NoError(t, err)
+
+		newRefresh := parseTestTokenClaims(t, refreshed.RefreshToken)
+		newAccess := parseTestTokenClaims(t, refreshed.AccessToken)
+		assert.NotEqual(t, loginRefresh.ID, newRefresh.ID)
+		assert.Equal(t, newRefresh.ID, newAccess.RefreshTokenID)
+		assert.Equal(t, []string{newAccess.ID}, pairs[newRefresh.ID])
+	})
+
+	t.Run("未轮换时新访问令牌加入原刷新令牌的令牌族", func(t *testing.T) {
+		authRepo.On("GetUserIDByRefreshToken", mock.Anything, loginPair.RefreshToken).Return(int64(123), nil).Once()
+
+		noRotateUc := NewAuthUsecase(userRepo, authRepo, AuthConfig{RefreshTokenTTL: 7 * 24 * time.Hour, RefreshRotationThreshold: 0.2}, newTestSlowOperationLogger(), getTestLogger())
+		refreshed, err := noRotateUc.RefreshToken(context.Background(), loginPair.RefreshToken)
+		require.NoError(t, err)
+		require.False(t, refreshed.RefreshTokenRotated)
+
+		newAccess := parseTestTokenClaims(t, refreshed.AccessToken)
+		assert.Equal(t, loginRefresh.ID, newAccess.RefreshTokenID)
+		assert.Equal(t, []string{loginAccess.ID, newAccess.ID}, pairs[loginRefresh.ID])
+	})
+
+	authRepo.AssertExpectations(t)
+}
+
+// TestAuthUsecase_ValidateToken_ClientBinding 测试开启客户端绑定后访问令牌只能在签发时的客户端上使用
+func TestAuthUsecase_ValidateToken_ClientBinding(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	fingerprint := ClientFingerprint("1.2.3.4", "test-agent")
+	boundToken, _, _, err := generateAccessToken(TokenSubject{UserID: 123, Fingerprint: fingerprint}, "")
+	require.NoError(t, err)
+	legacyToken, _, _, err := generateAccessToken(TokenSubject{UserID: 123}, "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		bind        bool
+		accessToken string
+		ip          string
+		userAgent   string
+		expectedErr error
+	}{
+		{
+			name:        "同一客户端校验通过",
+			bind:        true,
+			accessToken: boundToken,
+			ip:          "1.2.3.4",
+			userAgent:   "test-agent",
+		},
+		{
+			name:        "同一网段内切换IP校验通过",
+			bind:        true,
+			accessToken: boundToken,
+			ip:          "1.2.3.99",
+			userAgent:   "test-agent",
+		},
+		{
+			name:        "不同网段校验失败",
+			bind:        true,
+			accessToken: boundToken,
+			ip:          "5.6.7.8",
+			userAgent:   "test-agent",
+			expectedErr: error_reason.ErrorUserInvalidToken("访问令牌与当前客户端不匹配"),
+		},
+		{
+			name:        "User-Agent不同校验失败",
+			bind:        true,
+			accessToken: boundToken,
+			ip:          "1.2.3.4",
+			userAgent:   "other-agent",
+			expectedErr: error_reason.ErrorUserInvalidToken("访问令牌与当前客户端不匹配"),
+		},
+		{
+			name:        "未开启绑定时忽略指纹声明",
+			bind:        false,
+			accessToken: boundToken,
+			ip:          "5.6.7.8",
+			userAgent:   "other-agent",
+		},
+		{
+			name:        "开启绑定前签发的令牌仍可使用",
+			bind:        true,
+			accessToken: legacyToken,
+			ip:          "5.6.7.8",
+			userAgent:   "other-agent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewAuthUsecase(new(MockUserRepository), new(MockAuthRepository), AuthConfig{BindTokenToClient: tt.bind}, newTestSlowOperationLogger(), getTestLogger())
+			ctx := WithClientFingerprint(context.Background(), ClientFingerprint(tt.ip, tt.userAgent))
+
+			userID, err := uc.ValidateToken(ctx, tt.accessToken)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(123), userID)
+		})
+	}
+}
+
+// TestUserUsecase_Login_ClientBinding 测试开启客户端绑定后登录签发的访问令牌携带当前客户端指纹
+func TestUserUsecase_Login_ClientBinding(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	validPassword := "password123"
+	hashedPassword, err := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
+	require.NoError(t, err)
+
+	fingerprint := ClientFingerprint("1.2.3.4", "test-agent")
+	ctx := WithClientFingerprint(context.Background(), fingerprint)
+
+	for _, bind := range []bool{true, false} {
+		t.Run(fmt.Sprintf("bind=%v", bind), func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+				Return(&User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword}, nil)
+			authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).Return(nil)
+
+			authConfig := AuthConfig{BindTokenToClient: bind}
+			userUc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+			tokenPair, err := userUc.Login(ctx, "test@example.com", validPassword, false)
+			require.NoError(t, err)
+
+			claims := parseTestTokenClaims(t, tokenPair.AccessToken)
+			if bind {
+				assert.Equal(t, fingerprint, claims.Fingerprint)
+			} else {
+				assert.Empty(t, claims.Fingerprint, "未开启绑定时不写入指纹声明")
+			}
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_GracePeriod 测试宽限期内重放已轮换的刷新令牌返回同一组令牌，超出宽限期后失败
+func TestAuthUsecase_RefreshToken_GracePeriod(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	oldToken := signTestRefreshToken(t, time.Now(), time.Now().Add(time.Hour))
+	racingToken := signTestRefreshToken(t, time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+
+	const grace = 10 * time.Second
+
+	authRepo := new(MockAuthRepository)
+	allowTokenPairing(authRepo)
+	authRepo.On("GetUserIDByRefreshToken", mock.Anything, oldToken).Return(int64(123), nil).Once()
+	authRepo.On("VerifyAndRotate", mock.Anything, oldToken, mock.Anything, int64(123), mock.Anything).Return(true, nil).Once()
+	var cached *TokenPair
+	authRepo.On("StoreRotatedTokenPair", mock.Anything, oldToken, mock.Anything, grace).
+		Run(func(args mock.Arguments) { cached = args.Get(2).(*TokenPair) }).
+		Return(nil).Once()
+
+	uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{RefreshGracePeriod: grace}, newTestSlowOperationLogger(), getTestLogger())
+	first, err := uc.RefreshToken(context.Background(), oldToken)
+	require.NoError(t, err)
+	require.Equal(t, first, cached, "轮换结果应按旧令牌缓存")
+
+	t.Run("宽限期内重复刷新返回同一组令牌", func(t *testing.T) {
+		authRepo.On("GetUserIDByRefreshToken", mock.Anything, oldToken).Return(int64(0), ErrTokenNotFound).Once()
+		authRepo.On("GetRotatedTokenPair", mock.Anything, oldToken).Return(cached, nil).Once()
+
+		again, err := uc.RefreshToken(context.Background(), oldToken)
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	})
+
+	t.Run("超出宽限期后重复刷新失败", func(t *testing.T) {
+		authRepo.On("GetUserIDByRefreshToken", mock.Anything, oldToken).Return(int64(0), ErrTokenNotFound).Once()
+		authRepo.On("GetRotatedTokenPair", mock.Anything, oldToken).Return((*TokenPair)(nil), ErrTokenNotFound).Once()
+
+		_, err := uc.RefreshToken(context.Background(), oldToken)
+		assert.Equal(t, kerrors.Reason(error_reason.ErrorUserRefreshTokenInvalid("")), kerrors.Reason(err))
+	})
+
+	t.Run("并发轮换失败时返回先完成的轮换结果", func(t *testing.T) {
+		authRepo.On("GetUserIDByRefreshToken", mock.Anything, racingToken).Return(int64(123), nil).Once()
+		authRepo.On("VerifyAndRotate", mock.Anything, racingToken, mock.Anything, int64(123), mock.Anything).Return(false, nil).Once()
+		authRepo.On("GetRotatedTokenPair", mock.Anything, racingToken).Return(cached, nil).Once()
+
+		again, err := uc.RefreshToken(context.Background(), racingToken)
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	})
+
+	authRepo.AssertExpectations(t)
+
+	t.Run("未开启宽限期时不缓存也不查询", func(t *testing.T) {
+		authRepo := new(MockAuthRepository)
+		authRepo.On("GetUserIDByRefreshToken", mock.Anything, oldToken).Return(int64(0), ErrTokenNotFound).Once()
+
+		uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{}, newTestSlowOperationLogger(), getTestLogger())
+		_, err := uc.RefreshToken(context.Background(), oldToken)
+		assert.Equal(t, kerrors.Reason(error_reason.ErrorUserRefreshTokenInvalid("")), kerrors.Reason(err))
+		authRepo.AssertNotCalled(t, "GetRotatedTokenPair", mock.Anything, mock.Anything)
+	})
+}
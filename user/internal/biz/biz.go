@@ -1,16 +1,32 @@
 package biz
 
 import (
+	"context"
+	"errors"
+
 	"github.com/google/wire"
-	"user/internal/pkg/snowflake"
 	"user/internal/conf"
+	"user/internal/pkg/snowflake"
 )
 
 // ProviderSet is biz providers.
 var ProviderSet = wire.NewSet(
 	NewUserUsecase,
 	NewAuthUsecase,
+	NewPointUsecase,
+	NewAccountMaintenanceUsecase,
+	NewUserDataExportUsecase,
+	NewPersonalTokenUsecase,
 	NewEmailConfig,
+	NewAuthConfig,
+	NewCodeHasher,
+	NewCaptchaVerifier,
+	NewPagination,
+	NewSlowOperationConfig,
+	NewAccountDeletionConfig,
+	NewPointConfig,
+	NewSlowOperationLogger,
+	NewSystemClock,
 	wire.Bind(new(SnowflakeIDGenerator), new(*snowflake.SnowflakeGenerator)),
 	snowflake.DefaultSnowflakeConfig,
 	snowflake.NewSnowflakeGenerator,
@@ -19,11 +35,94 @@ var ProviderSet = wire.NewSet(
 // NewEmailConfig 创建邮件配置
 func NewEmailConfig(c *conf.Email) EmailConfig {
 	return EmailConfig{
-		SenderName:   c.SenderName,
-		SenderEmail:  c.SenderEmail,
-		SupportEmail: c.SupportEmail,
-		CompanyName:  c.CompanyName,
-		AppName:      c.AppName,
+		SenderName:          c.SenderName,
+		SenderEmail:         c.SenderEmail,
+		SupportEmail:        c.SupportEmail,
+		CompanyName:         c.CompanyName,
+		AppName:             c.AppName,
+		DailySendLimit:      int(c.DailySendLimit),
+		WelcomeEmailEnabled: c.WelcomeEmailEnabled,
+		Code: CodeConfig{
+			Length:  int(c.CodeLength),
+			Charset: c.CodeCharset,
+		},
+	}
+}
+
+// NewAuthConfig 创建认证配置
+func NewAuthConfig(c *conf.Auth) AuthConfig {
+	if c == nil {
+		return AuthConfig{}
+	}
+	return AuthConfig{
+		RefreshTokenTTL:           c.RefreshTokenTtl.AsDuration(),
+		RememberMeRefreshTokenTTL: c.RememberMeRefreshTokenTtl.AsDuration(),
+		AdminUserIDs:              c.AdminUserIds,
+		EnrichAccessToken:         c.EnrichAccessToken,
+		MaxActiveSessions:         int(c.MaxActiveSessions),
+		RefreshRotationThreshold:  c.RefreshRotationThreshold,
+		PasswordHashScheme:        c.PasswordHashScheme,
+		BindTokenToClient:         c.BindTokenToClient,
+		RefreshGracePeriod:        c.RefreshGracePeriod.AsDuration(),
+
+		RegistrationCaptchaThreshold: int(c.RegistrationCaptchaThreshold),
+		RegistrationCaptchaWindow:    c.RegistrationCaptchaWindow.AsDuration(),
+		AccessRefreshThreshold:       c.AccessRefreshThreshold,
+		RefreshIdleTimeout:           c.RefreshIdleTimeout.AsDuration(),
+		MaxNicknameWidth:             int(c.MaxNicknameWidth),
+		RequireEmailVerification:     c.RequireEmailVerification,
+		EnforceTokensValidAfter:      c.EnforceTokensValidAfter,
+		MaxPersonalTokens:            int(c.MaxPersonalTokens),
+	}
+}
+
+// NewCodeHasher 使用启动时校验过的密钥创建验证码哈希器
+func NewCodeHasher(c *conf.Auth) *CodeHasher {
+	if c == nil {
+		return NewCodeHasherWithSecrets("", "")
+	}
+	return NewCodeHasherWithSecrets(c.CodeHmacSecret, c.CodeHmacPreviousSecret)
+}
+
+// NewPagination 创建分页配置，未配置时使用默认值
+func NewPagination(c *conf.Pagination) Pagination {
+	if c == nil {
+		return Pagination{}
+	}
+	return Pagination{
+		DefaultPageSize: int(c.DefaultPageSize),
+		MaxPageSize:     int(c.MaxPageSize),
+	}
+}
+
+// NewSlowOperationConfig 创建慢操作日志配置
+func NewSlowOperationConfig(c *conf.Biz) SlowOperationConfig {
+	if c == nil {
+		return SlowOperationConfig{}
+	}
+	return SlowOperationConfig{
+		Threshold: c.SlowOperationThreshold.AsDuration(),
+	}
+}
+
+// NewAccountDeletionConfig 创建账号删除配置，未配置点数处理策略时保留点数
+func NewAccountDeletionConfig(c *conf.Biz) AccountDeletionConfig {
+	if c == nil || c.PointDeletionPolicy == "" {
+		return AccountDeletionConfig{PointPolicy: PointDeletionPolicyRetain}
+	}
+	return AccountDeletionConfig{PointPolicy: c.PointDeletionPolicy}
+}
+
+// NewPointConfig 创建点数配置
+func NewPointConfig(c *conf.Biz) PointConfig {
+	if c == nil {
+		return PointConfig{}
+	}
+	return PointConfig{
+		MaxDescriptionLength: int(c.MaxTransactionDescriptionLength),
+		TransactionRetention: c.PointTransactionRetention.AsDuration(),
+		ArchiveBatchSize:     int(c.PointArchiveBatchSize),
+		ArchiveInterval:      c.PointArchiveInterval.AsDuration(),
 	}
 }
 
@@ -31,3 +130,14 @@ func NewEmailConfig(c *conf.Email) EmailConfig {
 func EmailProvider(bootstrap *conf.Bootstrap) *conf.Email {
 	return bootstrap.Email
 }
+
+// ErrTxRetriesExhausted 事务因死锁或锁等待超时重试多次后仍失败
+var ErrTxRetriesExhausted = errors.New("transaction retries exhausted due to lock contention")
+
+// Transaction 事务接口，InTx 内通过 ctx 调用的 repository 方法共享同一个数据库事务
+type Transaction interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// InTxWithRetry 与 InTx 相同，但遇到死锁或锁等待超时时回滚并整体重试，fn 可能被执行多次
+	// 重试次数用尽时返回包装了 ErrTxRetriesExhausted 的错误
+	InTxWithRetry(ctx context.Context, fn func(ctx context.Context) error) error
+}
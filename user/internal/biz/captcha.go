@@ -0,0 +1,68 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	error_reason "user/api/error_reason"
+)
+
+// defaultRegistrationCaptchaWindow 未配置时注册请求按 IP 计数的时间窗口
+const defaultRegistrationCaptchaWindow = time.Hour
+
+// CaptchaVerifier 人机验证令牌校验接口，由接入的验证码服务商实现
+type CaptchaVerifier interface {
+	// Verify 校验客户端提交的人机验证令牌，clientIP 可能为空
+	Verify(ctx context.Context, token, clientIP string) (bool, error)
+}
+
+// noopCaptchaVerifier 未接入验证码服务商时使用的默认实现，接受任意非空令牌
+type noopCaptchaVerifier struct{}
+
+func (noopCaptchaVerifier) Verify(context.Context, string, string) (bool, error) {
+	return true, nil
+}
+
+// NewCaptchaVerifier 创建默认的人机验证校验器，接入服务商时替换该 provider
+func NewCaptchaVerifier() CaptchaVerifier {
+	return noopCaptchaVerifier{}
+}
+
+// checkRegistrationCaptcha 累加当前 IP 的注册请求次数，超过软阈值后要求携带并通过人机验证
+// 未开启、或无法获取客户端 IP（如进程内调用）时不做要求；阈值内人机验证为可选，不校验令牌
+func (uc *UserUsecase) checkRegistrationCaptcha(ctx context.Context, captchaToken string) error {
+	threshold := uc.authConfig.RegistrationCaptchaThreshold
+	ip := ClientIPFromContext(ctx)
+	if threshold <= 0 || ip == "" {
+		return nil
+	}
+
+	window := uc.authConfig.RegistrationCaptchaWindow
+	if window <= 0 {
+		window = defaultRegistrationCaptchaWindow
+	}
+	resetAt := time.Now().Truncate(window).Add(window)
+	ok, err := uc.codeRepo.CheckAndIncrRegistrationAttempts(ctx, ip, threshold, resetAt)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to count registration attempts for ip: %s, error_reason: %v", ip, err)
+		return error_reason.ErrorUserDatabaseError("频率限制检查失败")
+	}
+	if ok {
+		return nil
+	}
+
+	if captchaToken == "" {
+		uc.log.WithContext(ctx).Warnf("Captcha required for ip: %s", ip)
+		return error_reason.ErrorUserCaptchaRequired("请求过于频繁，请完成人机验证")
+	}
+	verified, err := uc.captcha.Verify(ctx, captchaToken, ip)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to verify captcha for ip: %s, error_reason: %v", ip, err)
+		return error_reason.ErrorUserServiceUnavailable("人机验证服务暂时不可用")
+	}
+	if !verified {
+		uc.log.WithContext(ctx).Warnf("Captcha verification failed for ip: %s", ip)
+		return error_reason.ErrorUserCaptchaRequired("人机验证未通过")
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// stubCaptchaVerifier 返回固定结果并记录收到的令牌和 IP 的测试校验器
+type stubCaptchaVerifier struct {
+	ok       bool
+	err      error
+	calls    int
+	gotIP    string
+	gotToken string
+}
+
+func (v *stubCaptchaVerifier) Verify(_ context.Context, token, clientIP string) (bool, error) {
+	v.calls++
+	v.gotToken = token
+	v.gotIP = clientIP
+	return v.ok, v.err
+}
+
+// TestUserUsecase_CheckRegistrationCaptcha 测试阈值内人机验证可选，超过阈值后必须携带并通过人机验证
+func TestUserUsecase_CheckRegistrationCaptcha(t *testing.T) {
+	countErr := errors.New("redis unavailable")
+
+	tests := []struct {
+		name         string
+		threshold    int
+		clientIP     string
+		captchaToken string
+		setupMocks   func(*MockCodeRepository)
+		verifier     *stubCaptchaVerifier
+		wantErr      func(error) bool
+		wantVerified bool
+	}{
+		{
+			name:      "未开启时不计数",
+			threshold: 0,
+			clientIP:  "1.2.3.4",
+			verifier:  &stubCaptchaVerifier{},
+		},
+		{
+			name:      "无法获取客户端IP时不计数",
+			threshold: 3,
+			verifier:  &stubCaptchaVerifier{},
+		},
+		{
+			name:      "阈值内无需人机验证",
+			threshold: 3,
+			clientIP:  "1.2.3.4",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrRegistrationAttempts", mock.Anything, "1.2.3.4", 3, mock.Anything).Return(true, nil)
+			},
+			verifier: &stubCaptchaVerifier{},
+		},
+		{
+			name:      "超过阈值且缺少令牌",
+			threshold: 3,
+			clientIP:  "1.2.3.4",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrRegistrationAttempts", mock.Anything, "1.2.3.4", 3, mock.Anything).Return(false, nil)
+			},
+			verifier: &stubCaptchaVerifier{ok: true},
+			wantErr:  error_reason.IsUserCaptchaRequired,
+		},
+		{
+			name:         "超过阈值且令牌校验通过",
+			threshold:    3,
+			clientIP:     "1.2.3.4",
+			captchaToken: "captcha-token",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrRegistrationAttempts", mock.Anything, "1.2.3.4", 3, mock.Anything).Return(false, nil)
+			},
+			verifier:     &stubCaptchaVerifier{ok: true},
+			wantVerified: true,
+		},
+		{
+			name:         "超过阈值且令牌校验未通过",
+			threshold:    3,
+			clientIP:     "1.2.3.4",
+			captchaToken: "captcha-token",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrRegistrationAttempts", mock.Anything, "1.2.3.4", 3, mock.Anything).Return(false, nil)
+			},
+			verifier:     &stubCaptchaVerifier{ok: false},
+			wantErr:      error_reason.IsUserCaptchaRequired,
+			wantVerified: true,
+		},
+		{
+			name:         "校验服务出错",
+			threshold:    3,
+			clientIP:     "1.2.3.4",
+			captchaToken: "captcha-token",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrRegistrationAttempts", mock.Anything, "1.2.3.4", 3, mock.Anything).Return(false, nil)
+			},
+			verifier:     &stubCaptchaVerifier{err: errors.New("provider timeout")},
+			wantErr:      error_reason.IsUserServiceUnavailable,
+			wantVerified: true,
+		},
+		{
+			name:      "计数失败",
+			threshold: 3,
+			clientIP:  "1.2.3.4",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrRegistrationAttempts", mock.Anything, "1.2.3.4", 3, mock.Anything).Return(false, countErr)
+			},
+			verifier: &stubCaptchaVerifier{},
+			wantErr:  error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeRepo := new(MockCodeRepository)
+			if tt.setupMocks != nil {
+				tt.setupMocks(codeRepo)
+			}
+			authConfig := AuthConfig{RegistrationCaptchaThreshold: tt.threshold}
+			uc := NewUserUsecase(new(MockUserRepository), codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), tt.verifier, newTestSlowOperationLogger(), getTestLogger())
+
+			ctx := context.Background()
+			if tt.clientIP != "" {
+				ctx = WithClientIP(ctx, tt.clientIP)
+			}
+			err := uc.checkRegistrationCaptcha(ctx, tt.captchaToken)
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantVerified {
+				assert.Equal(t, 1, tt.verifier.calls)
+				assert.Equal(t, tt.captchaToken, tt.verifier.gotToken)
+				assert.Equal(t, tt.clientIP, tt.verifier.gotIP)
+			} else {
+				assert.Zero(t, tt.verifier.calls)
+			}
+			codeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_RegistrationCaptchaRequired 测试超过阈值且缺少人机验证令牌时，发送验证码和注册在执行前被拒绝
+func TestUserUsecase_RegistrationCaptchaRequired(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(ctx context.Context, uc *UserUsecase) error
+	}{
+		{
+			name: "发送注册验证码",
+			call: func(ctx context.Context, uc *UserUsecase) error {
+				return uc.SendRegisterCode(ctx, "test@example.com", "")
+			},
+		},
+		{
+			name: "注册",
+			call: func(ctx context.Context, uc *UserUsecase) error {
+				_, err := uc.Register(ctx, "test@example.com", "password123", "123456", "tester", "")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+			userRepo.On("GetByEmailPublic", mock.Anything, "test@example.com").Return((*User)(nil), gorm.ErrRecordNotFound).Maybe()
+			codeRepo.On("CheckAndIncrRegistrationAttempts", mock.Anything, "1.2.3.4", 1, mock.MatchedBy(func(resetAt time.Time) bool {
+				return resetAt.After(time.Now())
+			})).Return(false, nil)
+
+			authConfig := AuthConfig{RegistrationCaptchaThreshold: 1}
+			uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), &stubCaptchaVerifier{ok: true}, newTestSlowOperationLogger(), getTestLogger())
+
+			err := tt.call(WithClientIP(context.Background(), "1.2.3.4"), uc)
+
+			assert.True(t, error_reason.IsUserCaptchaRequired(err), "unexpected error: %v", err)
+			// 未继续发送或消费验证码
+			codeRepo.AssertNotCalled(t, "CheckAndSetSendRateLimit", mock.Anything, mock.Anything, mock.Anything)
+			codeRepo.AssertNotCalled(t, "ConsumeIfValid", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			codeRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package biz
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// CodeHasher 使用 HMAC-SHA256 对验证码做哈希，存储中只保存哈希值
+// 密钥轮换期间同时持有旧密钥，使轮换前发出、尚未使用的验证码仍可通过校验
+type CodeHasher struct {
+	secret         []byte
+	previousSecret []byte
+}
+
+// NewCodeHasherWithSecrets 使用当前密钥和可选的旧密钥创建验证码哈希器
+func NewCodeHasherWithSecrets(secret, previousSecret string) *CodeHasher {
+	h := &CodeHasher{secret: []byte(secret)}
+	if previousSecret != "" {
+		h.previousSecret = []byte(previousSecret)
+	}
+	return h
+}
+
+// Hash 使用当前密钥计算验证码哈希，邮箱参与计算，防止同一验证码哈希在不同邮箱间复用
+func (h *CodeHasher) Hash(email, code string) string {
+	return hashCode(h.secret, email, code)
+}
+
+// Verify 校验验证码是否与存储的哈希一致，依次尝试当前密钥和旧密钥
+func (h *CodeHasher) Verify(email, code, hashed string) bool {
+	if hmac.Equal([]byte(hashCode(h.secret, email, code)), []byte(hashed)) {
+		return true
+	}
+	return h.previousSecret != nil &&
+		hmac.Equal([]byte(hashCode(h.previousSecret, email, code)), []byte(hashed))
+}
+
+// Candidates 返回使用当前密钥和旧密钥（如有）计算的验证码哈希，供存储层逐个比对
+func (h *CodeHasher) Candidates(email, code string) []string {
+	candidates := []string{hashCode(h.secret, email, code)}
+	if h.previousSecret != nil {
+		candidates = append(candidates, hashCode(h.previousSecret, email, code))
+	}
+	return candidates
+}
+
+// hashCode 计算 HMAC-SHA256(email:code) 的十六进制编码
+func hashCode(secret []byte, email, code string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(email + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,91 @@
+package biz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCodeHasher_Verify 测试验证码哈希校验及密钥轮换
+func TestCodeHasher_Verify(t *testing.T) {
+	const email = "test@example.com"
+
+	oldHasher := NewCodeHasherWithSecrets(testCodeHMACPreviousSecret, "")
+	rotatedHasher := NewCodeHasherWithSecrets(testCodeHMACSecret, testCodeHMACPreviousSecret)
+
+	tests := []struct {
+		name   string
+		hasher *CodeHasher
+		email  string
+		code   string
+		hashed string
+		want   bool
+	}{
+		{
+			name:   "当前密钥生成的哈希",
+			hasher: rotatedHasher,
+			email:  email,
+			code:   "123456",
+			hashed: rotatedHasher.Hash(email, "123456"),
+			want:   true,
+		},
+		{
+			name:   "轮换前旧密钥生成的哈希仍可校验",
+			hasher: rotatedHasher,
+			email:  email,
+			code:   "123456",
+			hashed: oldHasher.Hash(email, "123456"),
+			want:   true,
+		},
+		{
+			name:   "移除旧密钥后旧哈希失效",
+			hasher: newTestCodeHasher(),
+			email:  email,
+			code:   "123456",
+			hashed: oldHasher.Hash(email, "123456"),
+			want:   false,
+		},
+		{
+			name:   "验证码错误",
+			hasher: rotatedHasher,
+			email:  email,
+			code:   "654321",
+			hashed: rotatedHasher.Hash(email, "123456"),
+			want:   false,
+		},
+		{
+			name:   "哈希不能用于其他邮箱",
+			hasher: rotatedHasher,
+			email:  "other@example.com",
+			code:   "123456",
+			hashed: rotatedHasher.Hash(email, "123456"),
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.hasher.Verify(tt.email, tt.code, tt.hashed))
+		})
+	}
+}
+
+// TestCodeHasher_Hash 测试存储的哈希不包含明文验证码
+func TestCodeHasher_Hash(t *testing.T) {
+	hashed := newTestCodeHasher().Hash("test@example.com", "123456")
+
+	assert.NotContains(t, hashed, "123456")
+	assert.Len(t, hashed, 64)
+	assert.Equal(t, hashed, newTestCodeHasher().Hash("test@example.com", "123456"))
+}
+
+// TestCodeHasher_Candidates 测试候选哈希包含当前密钥及轮换期间旧密钥的结果
+func TestCodeHasher_Candidates(t *testing.T) {
+	const email = "test@example.com"
+
+	assert.Equal(t, []string{newTestCodeHasher().Hash(email, "123456")}, newTestCodeHasher().Candidates(email, "123456"))
+
+	rotated := NewCodeHasherWithSecrets(testCodeHMACSecret, testCodeHMACPreviousSecret)
+	oldHasher := NewCodeHasherWithSecrets(testCodeHMACPreviousSecret, "")
+	assert.Equal(t, []string{rotated.Hash(email, "123456"), oldHasher.Hash(email, "123456")}, rotated.Candidates(email, "123456"))
+}
@@ -0,0 +1,106 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"user/internal/pkg/tracing"
+)
+
+const (
+	// EmailTypeRegisterCode 注册验证码邮件
+	EmailTypeRegisterCode = "register_code"
+	// EmailTypeWelcome 注册成功后的欢迎邮件
+	EmailTypeWelcome = "welcome"
+	// EmailTypeTest 管理员触发的诊断测试邮件
+	EmailTypeTest = "test"
+	// EmailTypeResetCode 找回密码验证码邮件
+	EmailTypeResetCode = "reset_code"
+
+	// EmailLogStatusSent 邮件发送成功
+	EmailLogStatusSent = "sent"
+	// EmailLogStatusFailed 邮件发送失败
+	EmailLogStatusFailed = "failed"
+)
+
+// EmailMessage 待发送的邮件内容
+type EmailMessage struct {
+	FromName  string
+	FromEmail string
+	ToName    string
+	ToEmail   string
+	Subject   string
+	PlainText string
+	HTML      string
+}
+
+// ErrEmailSenderBusy 同时进行的邮件发送数已达上限且配置为立即失败
+var ErrEmailSenderBusy = errors.New("email sender busy")
+
+// ErrEmailRejected 邮件服务商拒绝了邮件本身（如收件人地址无效、内容被拒收），改用其他服务商发送也会被拒绝
+var ErrEmailRejected = errors.New("email rejected by provider")
+
+// EmailSender 邮件发送接口
+type EmailSender interface {
+	Send(ctx context.Context, message *EmailMessage) error
+}
+
+// EmailLog 邮件发送记录表，用于追踪邮件投递问题
+type EmailLog struct {
+	ID           int64     `gorm:"column:id;primaryKey" json:"id"`
+	EmailType    string    `gorm:"column:email_type;not null" json:"email_type"`
+	MaskedEmail  string    `gorm:"column:masked_email;not null" json:"masked_email"`
+	Status       string    `gorm:"column:status;not null" json:"status"`
+	ErrorMessage string    `gorm:"column:error_message" json:"error_message,omitempty"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (EmailLog) TableName() string {
+	return "email_log"
+}
+
+// EmailLogRepository 邮件发送记录数据访问接口
+type EmailLogRepository interface {
+	Create(ctx context.Context, emailLog *EmailLog) error
+}
+
+// maskEmail 对邮箱前缀做脱敏处理（例如：use***@example.com）
+func maskEmail(email string) string {
+	prefix, domain, found := strings.Cut(email, "@")
+	if len(prefix) > 3 {
+		prefix = prefix[:3] + strings.Repeat("*", len(prefix)-3)
+	}
+	if !found {
+		return prefix
+	}
+	return prefix + "@" + domain
+}
+
+// recordEmailLog 记录一次邮件发送结果，写入失败只打日志，不影响主流程
+func (uc *UserUsecase) recordEmailLog(ctx context.Context, emailType, email string, sendErr error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.recordEmailLog")
+	defer span.End()
+
+	emailLog := &EmailLog{
+		EmailType:   emailType,
+		MaskedEmail: maskEmail(email),
+		Status:      EmailLogStatusSent,
+		CreatedAt:   time.Now(),
+	}
+	if sendErr != nil {
+		emailLog.Status = EmailLogStatusFailed
+		emailLog.ErrorMessage = sendErr.Error()
+	}
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email_type": emailType,
+		"status":     emailLog.Status,
+	})
+
+	if err := uc.emailLogRepo.Create(ctx, emailLog); err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to record email log, type: %s, status: %s, error_reason: %v", emailType, emailLog.Status, err)
+	}
+}
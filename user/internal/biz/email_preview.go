@@ -0,0 +1,72 @@
+package biz
+
+import (
+	"context"
+	"regexp"
+
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+const (
+	// EmailLangZH 简体中文，目前唯一提供模板的语言
+	EmailLangZH = "zh"
+
+	// previewRecipient 预览邮件使用的示例收件人
+	previewRecipient = "user@example.com"
+	// previewNickname 预览欢迎邮件使用的示例昵称
+	previewNickname = "示例用户"
+	// previewCode 未指定验证码时预览使用的示例验证码
+	previewCode = "123456"
+)
+
+// previewTypeAliases 预览接口接受的邮件类型别名，映射到 EmailType* 常量
+var previewTypeAliases = map[string]string{
+	"register": EmailTypeRegisterCode,
+}
+
+// previewCodePattern 预览验证码只允许字母和数字，避免向 HTML 模板注入内容
+var previewCodePattern = regexp.MustCompile(`^[0-9A-Za-z]{1,16}$`)
+
+// PreviewEmail 按邮件类型（见 EmailType* 常量，也接受 previewTypeAliases 中的别名）渲染邮件内容但不发送，用于调整邮件模板
+// lang 为空时使用简体中文；code 只用于验证码邮件，为空时使用示例验证码
+func (uc *UserUsecase) PreviewEmail(ctx context.Context, emailType, lang, code string) (*EmailMessage, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.PreviewEmail")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":  "preview_email",
+		"email_type": emailType,
+		"lang":       lang,
+	})
+
+	if lang != "" && lang != EmailLangZH {
+		uc.log.WithContext(ctx).Warnf("Email preview requested for unsupported language: %s", lang)
+		return nil, error_reason.ErrorUserInvalidRequest("暂不支持该语言的邮件模板：%s", lang)
+	}
+
+	if alias, ok := previewTypeAliases[emailType]; ok {
+		emailType = alias
+	}
+
+	switch emailType {
+	case EmailTypeRegisterCode, EmailTypeResetCode:
+		if code == "" {
+			code = previewCode
+		}
+		if !previewCodePattern.MatchString(code) {
+			return nil, error_reason.ErrorUserInvalidRequest("验证码只能包含字母和数字，且不超过16个字符")
+		}
+		if emailType == EmailTypeResetCode {
+			return uc.buildCodeEmail(previewRecipient, code, resetCodeEmailCopy), nil
+		}
+		return uc.buildVerificationEmail(previewRecipient, code), nil
+	case EmailTypeWelcome:
+		return uc.buildWelcomeEmail(&User{Email: previewRecipient, Nickname: previewNickname}), nil
+	case EmailTypeTest:
+		return uc.buildTestEmail(previewRecipient), nil
+	default:
+		uc.log.WithContext(ctx).Warnf("Email preview requested for unknown type: %s", emailType)
+		return nil, error_reason.ErrorUserInvalidRequest("不支持的邮件类型：%s", emailType)
+	}
+}
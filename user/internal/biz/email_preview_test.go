@@ -0,0 +1,94 @@
+package biz
+
+import (
+	"context"
+	"testing"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserUsecase_PreviewEmail 测试预览的验证码邮件包含指定验证码，只支持已有模板的语言，且不发送邮件
+func TestUserUsecase_PreviewEmail(t *testing.T) {
+	tests := []struct {
+		name         string
+		emailType    string
+		lang         string
+		code         string
+		wantContains string
+		wantErr      func(error) bool
+	}{
+		{
+			name:         "验证码邮件包含指定验证码",
+			emailType:    EmailTypeRegisterCode,
+			lang:         EmailLangZH,
+			code:         "654321",
+			wantContains: "654321",
+		},
+		{
+			name:         "未指定语言和验证码时使用中文模板和示例验证码",
+			emailType:    EmailTypeRegisterCode,
+			wantContains: previewCode,
+		},
+		{
+			name:         "register 是注册验证码邮件的别名",
+			emailType:    "register",
+			lang:         EmailLangZH,
+			code:         "654321",
+			wantContains: "654321",
+		},
+		{
+			name:         "找回密码验证码邮件",
+			emailType:    EmailTypeResetCode,
+			code:         "654321",
+			wantContains: "重置密码",
+		},
+		{
+			name:         "欢迎邮件",
+			emailType:    EmailTypeWelcome,
+			wantContains: previewNickname,
+		},
+		{
+			name:      "不支持的语言",
+			emailType: EmailTypeRegisterCode,
+			lang:      "en",
+			code:      "654321",
+			wantErr:   error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:      "验证码包含 HTML",
+			emailType: EmailTypeRegisterCode,
+			code:      "<b>1</b>",
+			wantErr:   error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:      "未知邮件类型",
+			emailType: "unknown",
+			wantErr:   error_reason.IsUserInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailSender := new(MockEmailSender)
+			emailConfig := EmailConfig{AppName: "绘本", SenderEmail: "noreply@example.com"}
+			uc := NewUserUsecase(new(MockUserRepository), new(MockCodeRepository), new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, new(MockEmailLogRepository), emailConfig, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			message, err := uc.PreviewEmail(context.Background(), tt.emailType, tt.lang, tt.code)
+
+			emailSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, message.HTML, tt.wantContains)
+			assert.Contains(t, message.PlainText, tt.wantContains)
+			assert.Equal(t, "noreply@example.com", message.FromEmail)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"user/internal/pkg/tracing"
+)
+
+// UserExport 数据主体访问请求（如 GDPR 第 15 条）导出的用户数据
+type UserExport struct {
+	ExportedAt   time.Time           `json:"exported_at"`
+	Profile      UserExportProfile   `json:"profile"`
+	Points       *UserPoint          `json:"points"`
+	Transactions []*PointTransaction `json:"transactions"`
+}
+
+// UserExportProfile 导出的用户资料，只列出允许对外提供的字段，不包含密码哈希
+type UserExportProfile struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	Nickname  string    `json:"nickname"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+	IsPremium uint8     `json:"is_premium"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// newUserExportProfile 从用户记录中挑选可导出的字段
+func newUserExportProfile(user *User) UserExportProfile {
+	return UserExportProfile{
+		ID:        user.ID,
+		Email:     user.Email,
+		Nickname:  user.Nickname,
+		AvatarURL: user.AvatarURL,
+		IsPremium: user.IsPremium,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// UserDataExportUsecase 汇总用户资料、点数余额和全部流水，处理数据主体访问请求
+type UserDataExportUsecase struct {
+	users  *UserUsecase
+	points *PointUsecase
+	log    *log.Helper
+}
+
+// NewUserDataExportUsecase 创建用户数据导出业务逻辑实例
+func NewUserDataExportUsecase(users *UserUsecase, points *PointUsecase, logger log.Logger) *UserDataExportUsecase {
+	return &UserDataExportUsecase{
+		users:  users,
+		points: points,
+		log:    log.NewHelper(logger),
+	}
+}
+
+// ExportUserData 导出用户的资料、点数余额和全部点数流水，流水按创建时间倒序逐页读取
+func (uc *UserDataExportUsecase) ExportUserData(ctx context.Context, userID int64) (*UserExport, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserDataExportUsecase.ExportUserData")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "export_user_data",
+		"user_id":   userID,
+	})
+
+	// GetUserByID 不加载密码哈希
+	user, err := uc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	point, err := uc.points.GetPointBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := []*PointTransaction{}
+	err = uc.points.ExportTransactions(ctx, userID, func(txns []*PointTransaction) error {
+		transactions = append(transactions, txns...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.log.WithContext(ctx).Infof("Exported user data for user id: %d, transactions: %d", userID, len(transactions))
+	return &UserExport{
+		ExportedAt:   time.Now(),
+		Profile:      newUserExportProfile(user),
+		Points:       point,
+		Transactions: transactions,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestUserDataExportUsecase 使用 mock 仓库创建用户数据导出业务逻辑
+func newTestUserDataExportUsecase(userRepo *MockUserRepository, pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) *UserDataExportUsecase {
+	users := NewUserUsecase(userRepo, new(MockCodeRepository), new(MockAuthRepository), &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+	points := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+	return NewUserDataExportUsecase(users, points, getTestLogger())
+}
+
+// TestUserDataExportUsecase_ExportUserData 测试导出包含资料、余额和逐页读取的全部流水，且不包含密码哈希
+func TestUserDataExportUsecase_ExportUserData(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	user := &User{
+		ID:           1,
+		Email:        "test@example.com",
+		PasswordHash: "$2a$10$fixture-password-hash",
+		Nickname:     "tester",
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+
+	// 流水超过一页时分两页读取
+	firstPage := make([]*PointTransaction, exportPageSize)
+	for i := range firstPage {
+		firstPage[i] = &PointTransaction{ID: int64(exportPageSize + 1 - i), UserID: 1, Type: TransactionTypeConsume, Amount: 1}
+	}
+	secondPage := []*PointTransaction{{ID: 1, UserID: 1, Type: TransactionTypeRecharge, Amount: 600, Description: "充值"}}
+
+	userRepo := new(MockUserRepository)
+	pointRepo := new(MockUserPointRepository)
+	txnRepo := new(MockPointTransactionRepository)
+	userRepo.On("GetByIDPublic", mock.Anything, int64(1)).Return(user, nil)
+	pointRepo.On("GetOrCreate", mock.Anything, int64(1)).Return(&UserPoint{UserID: 1, CurrentPoints: 100, TotalConsumed: 500}, nil)
+	txnRepo.On("GetByUserID", mock.Anything, int64(1), 1, exportPageSize).Return(firstPage, int64(exportPageSize+1), nil).Once()
+	txnRepo.On("GetByUserID", mock.Anything, int64(1), 2, exportPageSize).Return(secondPage, int64(exportPageSize+1), nil).Once()
+
+	uc := newTestUserDataExportUsecase(userRepo, pointRepo, txnRepo)
+	export, err := uc.ExportUserData(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, UserExportProfile{ID: 1, Email: "test@example.com", Nickname: "tester", CreatedAt: createdAt, UpdatedAt: createdAt}, export.Profile)
+	assert.Equal(t, uint32(100), export.Points.CurrentPoints)
+	require.Len(t, export.Transactions, exportPageSize+1)
+	assert.Equal(t, int64(exportPageSize+1), export.Transactions[0].ID)
+	assert.Equal(t, secondPage[0], export.Transactions[exportPageSize])
+
+	encoded, err := json.Marshal(export)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), user.PasswordHash)
+	assert.NotContains(t, string(encoded), "password")
+	assert.Contains(t, string(encoded), `"description":"充值"`)
+
+	userRepo.AssertExpectations(t)
+	pointRepo.AssertExpectations(t)
+	txnRepo.AssertExpectations(t)
+}
+
+// TestUserDataExportUsecase_ExportUserData_UserNotFound 测试用户不存在时不读取点数数据
+func TestUserDataExportUsecase_ExportUserData_UserNotFound(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	pointRepo := new(MockUserPointRepository)
+	txnRepo := new(MockPointTransactionRepository)
+	userRepo.On("GetByIDPublic", mock.Anything, int64(1)).Return((*User)(nil), gorm.ErrRecordNotFound)
+
+	uc := newTestUserDataExportUsecase(userRepo, pointRepo, txnRepo)
+	export, err := uc.ExportUserData(context.Background(), 1)
+
+	assert.Nil(t, export)
+	assert.True(t, error_reason.IsUserNotFound(err), "unexpected error: %v", err)
+	pointRepo.AssertNotCalled(t, "GetOrCreate", mock.Anything, mock.Anything)
+	txnRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
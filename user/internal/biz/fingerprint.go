@@ -0,0 +1,51 @@
+package biz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+)
+
+// clientFingerprintKey 客户端指纹在 context 中的 key
+type clientFingerprintKey struct{}
+
+// WithClientFingerprint 将当前请求的客户端指纹写入 context，由传输层在处理请求前设置
+func WithClientFingerprint(ctx context.Context, fingerprint string) context.Context {
+	return context.WithValue(ctx, clientFingerprintKey{}, fingerprint)
+}
+
+// ClientFingerprintFromContext 读取当前请求的客户端指纹，未设置时返回空字符串
+func ClientFingerprintFromContext(ctx context.Context) string {
+	fingerprint, _ := ctx.Value(clientFingerprintKey{}).(string)
+	return fingerprint
+}
+
+// clientIPKey 客户端 IP 在 context 中的 key
+type clientIPKey struct{}
+
+// WithClientIP 将当前请求的客户端 IP 写入 context，由传输层在处理请求前设置
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext 读取当前请求的客户端 IP，未设置时（如进程内调用）返回空字符串
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// ClientFingerprint 根据客户端 IP 所在网段和 User-Agent 计算指纹
+// IPv4 取 /24、IPv6 取 /64 网段，同一网段内切换地址不影响指纹；令牌中只保存哈希，不暴露原始信息
+func ClientFingerprint(ip, userAgent string) string {
+	subnet := ip
+	if parsed := net.ParseIP(ip); parsed != nil {
+		if v4 := parsed.To4(); v4 != nil {
+			subnet = v4.Mask(net.CIDRMask(24, 32)).String()
+		} else {
+			subnet = parsed.Mask(net.CIDRMask(64, 128)).String()
+		}
+	}
+	sum := sha256.Sum256([]byte(subnet + "|" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,76 @@
+package biz
+
+import (
+	error_reason "user/api/error_reason"
+)
+
+const (
+	// defaultPageSize 未配置时的默认每页条数
+	defaultPageSize = 20
+	// defaultMaxPageSize 未配置时的每页最大条数
+	defaultMaxPageSize = 100
+)
+
+// Pagination 分页参数规范化配置，所有列表查询共用
+type Pagination struct {
+	DefaultPageSize int
+	MaxPageSize     int
+}
+
+// Normalize 规范化分页参数：page 未传（为0）时取1，pageSize 未传时取默认值、超过上限时截断；
+// page 或 pageSize 为负数时返回参数错误
+func (p Pagination) Normalize(page, pageSize int) (int, int, error) {
+	if page < 0 {
+		return 0, 0, error_reason.ErrorUserInvalidRequest("页码必须为正整数")
+	}
+	if pageSize < 0 {
+		return 0, 0, error_reason.ErrorUserInvalidRequest("每页条数必须为正整数")
+	}
+
+	defaultSize, maxSize := p.DefaultPageSize, p.MaxPageSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxPageSize
+	}
+	if defaultSize <= 0 {
+		defaultSize = defaultPageSize
+	}
+	if defaultSize > maxSize {
+		defaultSize = maxSize
+	}
+
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultSize
+	}
+	if pageSize > maxSize {
+		pageSize = maxSize
+	}
+	return page, pageSize, nil
+}
+
+// PageInfo 分页元信息，避免客户端自行计算页码
+type PageInfo struct {
+	Page       int
+	PageSize   int
+	Total      int64
+	TotalPages int
+	HasNext    bool
+	HasPrev    bool
+}
+
+// NewPageInfo 根据当前页、每页条数和总数构建分页元信息
+func NewPageInfo(page, pageSize int, total int64) PageInfo {
+	info := PageInfo{
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	if pageSize > 0 {
+		info.TotalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+	info.HasNext = page < info.TotalPages
+	info.HasPrev = page > 1
+	return info
+}
@@ -0,0 +1,151 @@
+package biz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	error_reason "user/api/error_reason"
+)
+
+// TestNewPageInfo 测试分页元信息计算
+func TestNewPageInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int
+		pageSize int
+		total    int64
+		want     PageInfo
+	}{
+		{
+			name:     "总数恰好为每页条数的整数倍",
+			page:     2,
+			pageSize: 10,
+			total:    30,
+			want:     PageInfo{Page: 2, PageSize: 10, Total: 30, TotalPages: 3, HasNext: true, HasPrev: true},
+		},
+		{
+			name:     "整数倍时最后一页没有下一页",
+			page:     3,
+			pageSize: 10,
+			total:    30,
+			want:     PageInfo{Page: 3, PageSize: 10, Total: 30, TotalPages: 3, HasNext: false, HasPrev: true},
+		},
+		{
+			name:     "最后一页不满",
+			page:     3,
+			pageSize: 10,
+			total:    25,
+			want:     PageInfo{Page: 3, PageSize: 10, Total: 25, TotalPages: 3, HasNext: false, HasPrev: true},
+		},
+		{
+			name:     "第一页且有下一页",
+			page:     1,
+			pageSize: 10,
+			total:    25,
+			want:     PageInfo{Page: 1, PageSize: 10, Total: 25, TotalPages: 3, HasNext: true, HasPrev: false},
+		},
+		{
+			name:     "空结果集",
+			page:     1,
+			pageSize: 10,
+			total:    0,
+			want:     PageInfo{Page: 1, PageSize: 10, Total: 0, TotalPages: 0, HasNext: false, HasPrev: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewPageInfo(tt.page, tt.pageSize, tt.total))
+		})
+	}
+}
+
+// TestPagination_Normalize 测试分页参数规范化
+func TestPagination_Normalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		pagination   Pagination
+		page         int
+		pageSize     int
+		wantPage     int
+		wantPageSize int
+		wantErr      bool
+	}{
+		{
+			name:         "未传参数使用配置的默认值",
+			pagination:   Pagination{DefaultPageSize: 15, MaxPageSize: 50},
+			page:         0,
+			pageSize:     0,
+			wantPage:     1,
+			wantPageSize: 15,
+		},
+		{
+			name:         "未配置时使用内置默认值",
+			pagination:   Pagination{},
+			page:         0,
+			pageSize:     0,
+			wantPage:     1,
+			wantPageSize: defaultPageSize,
+		},
+		{
+			name:         "合法参数保持不变",
+			pagination:   Pagination{DefaultPageSize: 15, MaxPageSize: 50},
+			page:         3,
+			pageSize:     30,
+			wantPage:     3,
+			wantPageSize: 30,
+		},
+		{
+			name:         "超过配置上限时截断",
+			pagination:   Pagination{DefaultPageSize: 15, MaxPageSize: 50},
+			page:         1,
+			pageSize:     1000,
+			wantPage:     1,
+			wantPageSize: 50,
+		},
+		{
+			name:         "未配置上限时使用内置上限",
+			pagination:   Pagination{},
+			page:         1,
+			pageSize:     1000,
+			wantPage:     1,
+			wantPageSize: defaultMaxPageSize,
+		},
+		{
+			name:         "默认值超过上限时按上限处理",
+			pagination:   Pagination{DefaultPageSize: 80, MaxPageSize: 50},
+			page:         1,
+			pageSize:     0,
+			wantPage:     1,
+			wantPageSize: 50,
+		},
+		{
+			name:       "负数页码",
+			pagination: Pagination{},
+			page:       -1,
+			pageSize:   10,
+			wantErr:    true,
+		},
+		{
+			name:       "负数每页条数",
+			pagination: Pagination{},
+			page:       1,
+			pageSize:   -5,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, pageSize, err := tt.pagination.Normalize(tt.page, tt.pageSize)
+
+			if tt.wantErr {
+				assert.True(t, error_reason.IsUserInvalidRequest(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPage, page)
+			assert.Equal(t, tt.wantPageSize, pageSize)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package biz
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 支持的密码哈希算法，对应配置项 auth.password_hash_scheme
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// PasswordHasher 密码哈希算法，哈希值自带算法前缀，便于登录时按前缀识别
+type PasswordHasher interface {
+	// Scheme 返回算法名称
+	Scheme() string
+	// Hash 对明文密码做哈希
+	Hash(password string) (string, error)
+	// Verify 校验明文密码是否与哈希值匹配
+	Verify(password, hash string) bool
+	// Owns 判断哈希值是否由该算法生成
+	Owns(hash string) bool
+}
+
+// passwordHashers 所有支持的算法，校验时按存储哈希的前缀选择，使切换算法后旧哈希仍可登录
+var passwordHashers = []PasswordHasher{
+	bcryptPasswordHasher{},
+	argon2idPasswordHasher{},
+}
+
+// newPasswordHasher 根据配置的算法名称创建密码哈希器，未配置时使用 bcrypt
+func newPasswordHasher(scheme string) PasswordHasher {
+	if scheme == PasswordHashArgon2id {
+		return argon2idPasswordHasher{}
+	}
+	return bcryptPasswordHasher{}
+}
+
+// passwordHasherFor 按哈希值前缀识别生成它的算法，无法识别时返回 nil
+func passwordHasherFor(hash string) PasswordHasher {
+	for _, h := range passwordHashers {
+		if h.Owns(hash) {
+			return h
+		}
+	}
+	return nil
+}
+
+// bcryptPasswordHasher bcrypt 实现，哈希前缀为 $2a$/$2b$/$2y$
+type bcryptPasswordHasher struct{}
+
+func (bcryptPasswordHasher) Scheme() string {
+	return PasswordHashBcrypt
+}
+
+func (bcryptPasswordHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(bytes), err
+}
+
+func (bcryptPasswordHasher) Verify(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (bcryptPasswordHasher) Owns(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// argon2id 参数，取 RFC 9106 推荐的第二组参数
+const (
+	argon2idTime    = 3
+	argon2idMemory  = 64 * 1024
+	argon2idThreads = 4
+	argon2idKeyLen  = 32
+	argon2idSaltLen = 16
+)
+
+// argon2idPasswordHasher argon2id 实现，哈希使用 PHC 格式：$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+type argon2idPasswordHasher struct{}
+
+func (argon2idPasswordHasher) Scheme() string {
+	return PasswordHashArgon2id
+}
+
+func (argon2idPasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2idTime, argon2idMemory, argon2idThreads, argon2idKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argon2idMemory, argon2idTime, argon2idThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 使用哈希中记录的参数重新计算，参数调整后旧哈希仍可校验
+func (argon2idPasswordHasher) Verify(password, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != PasswordHashArgon2id {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(computed, key) == 1
+}
+
+func (argon2idPasswordHasher) Owns(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
@@ -0,0 +1,160 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+
+	"gorm.io/gorm"
+)
+
+// resetCodeTTL 找回密码验证码的有效期，与注册验证码一致
+const resetCodeTTL = 10 * time.Minute
+
+// SendResetCode 向已注册的邮箱发送找回密码验证码
+// 与注册验证码共用发送冷却和每日发送上限，验证码按 CodePurposeResetPassword 单独存储，不会覆盖注册验证码
+func (uc *UserUsecase) SendResetCode(ctx context.Context, email string) error {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.SendResetCode")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "send_reset_code",
+		"email":     email,
+	})
+
+	uc.log.WithContext(ctx).Infof("Sending reset code to email: %s", email)
+
+	if err := ValidateEmailFormat(email); err != nil {
+		uc.log.WithContext(ctx).Warnf("Invalid email provided: %s, error_reason: %v", email, err)
+		return err
+	}
+
+	// 与注册相反，只能向已注册的邮箱发送
+	if _, err := uc.userRepo.GetByEmailPublic(ctx, email); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			uc.log.WithContext(ctx).Infof("Reset code requested for unregistered email: %s", email)
+			return error_reason.ErrorUserNotFound("该邮箱未注册")
+		}
+		uc.log.WithContext(ctx).Errorf("Database error_reason when checking email: %s, error_reason: %v", email, err)
+		return error_reason.ErrorUserDatabaseError("数据库查询失败")
+	}
+
+	// 检查发送频率限制（60秒内只能发送一次），必须在生成、存储和发送验证码之前执行
+	ok, err := uc.limiter.Allow(ctx, LimiterCodeSend, email, func(ctx context.Context) (bool, error) {
+		return uc.codeRepo.CheckAndSetSendRateLimit(ctx, email, 60*time.Second)
+	})
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to check rate limit for email: %s, error_reason: %v", email, err)
+		return error_reason.ErrorUserDatabaseError("频率限制检查失败")
+	}
+	if !ok {
+		return error_reason.ErrorUserTooManyRequests("请求过于频繁，请稍后再试")
+	}
+
+	if uc.emailConfig.DailySendLimit > 0 {
+		resetAt := truncateToDay(time.Now()).AddDate(0, 0, 1)
+		ok, err = uc.limiter.Allow(ctx, LimiterCodeSendDaily, email, func(ctx context.Context) (bool, error) {
+			return uc.codeRepo.CheckAndIncrDailySendLimit(ctx, email, uc.emailConfig.DailySendLimit, resetAt)
+		})
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to check daily send limit for email: %s, error_reason: %v", email, err)
+			return error_reason.ErrorUserDatabaseError("频率限制检查失败")
+		}
+		if !ok {
+			return error_reason.ErrorUserTooManyRequests("今日发送次数已达上限，请明天再试")
+		}
+	}
+
+	// 存储验证码哈希，明文只出现在邮件中
+	code := generateVerificationCode(uc.emailConfig.Code)
+	if err := uc.codeRepo.StoreCode(ctx, email, CodePurposeResetPassword, uc.codeHasher.Hash(email, code), time.Now().Add(resetCodeTTL)); err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to store reset code for email: %s, error_reason: %v", email, err)
+		return error_reason.ErrorUserDatabaseError("验证码存储失败")
+	}
+
+	message := uc.buildCodeEmail(email, code, resetCodeEmailCopy)
+	err = uc.emailSender.Send(ctx, message)
+	uc.recordEmailLog(ctx, EmailTypeResetCode, email, err)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to send reset code email to: %s, error_reason: %v", email, err)
+		if errors.Is(err, ErrEmailSenderBusy) {
+			return error_reason.ErrorUserServiceUnavailable("邮件服务繁忙，请稍后重试")
+		}
+		return error_reason.ErrorUserInternalError("邮件发送失败").WithCause(tracing.WithStack(err))
+	}
+
+	uc.log.WithContext(ctx).Infof("Reset code sent successfully to: %s", email)
+	return nil
+}
+
+// ResetPassword 校验找回密码验证码后设置新密码，并吊销该用户的全部刷新令牌，要求所有设备重新登录
+// 密码已更新但刷新令牌未能全部吊销时返回 USER_SESSION_REVOKE_INCOMPLETE，错误原因可用 errors.Is(err, ErrRevokeIncomplete) 判断
+func (uc *UserUsecase) ResetPassword(ctx context.Context, email, code, newPassword string) error {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.ResetPassword")
+	defer span.End()
+	defer uc.slowOp.Track(ctx, "ResetPassword")()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "reset_password",
+		"email":     email,
+	})
+
+	uc.log.WithContext(ctx).Infof("Resetting password for email: %s", email)
+
+	if email == "" || code == "" || newPassword == "" {
+		uc.log.WithContext(ctx).Warn("Missing required fields for password reset")
+		return error_reason.ErrorUserInvalidRequest("邮箱、验证码和新密码为必填项")
+	}
+	if err := ValidateEmailFormat(email); err != nil {
+		uc.log.WithContext(ctx).Warnf("Invalid email provided for password reset: %s, error_reason: %v", email, err)
+		return err
+	}
+
+	// 密码强度验证，在消费验证码之前完成，避免因密码不合规而浪费验证码
+	if len(newPassword) < 6 {
+		uc.log.WithContext(ctx).Warnf("Password too short for email: %s", email)
+		return error_reason.ErrorUserInvalidRequest("密码长度至少为6位")
+	}
+
+	if _, err := uc.consumeVerificationCode(ctx, email, CodePurposeResetPassword, code); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByEmailPublic(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			uc.log.WithContext(ctx).Warnf("User not found for password reset, email: %s", email)
+			return error_reason.ErrorUserNotFound("用户不存在")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to get user by email: %s, error_reason: %v", email, err)
+		return error_reason.ErrorUserDatabaseError("数据库查询失败")
+	}
+
+	hashedPassword, err := uc.hashPassword(newPassword)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to hash password for user: %d, error_reason: %v", user.ID, err)
+		return error_reason.ErrorUserInternalError("密码加密失败").WithCause(tracing.WithStack(err))
+	}
+	if err := uc.userRepo.UpdatePasswordHash(ctx, user.ID, hashedPassword); err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to update password for user: %d, error_reason: %v", user.ID, err)
+		return error_reason.ErrorUserDatabaseError("密码更新失败")
+	}
+
+	// 吊销不完整时重试一次，重试只会处理仍然存在的令牌
+	// 仍然失败时密码已更新，但旧会话（可能包括被盗用的会话）仍然有效，必须告知调用方而不能当作重置成功
+	err = uc.authRepo.DeleteAllRefreshTokens(ctx, user.ID)
+	if errors.Is(err, ErrRevokeIncomplete) {
+		uc.log.WithContext(ctx).Warnf("Incomplete refresh token revocation after password reset, retrying, user: %d, error_reason: %v", user.ID, err)
+		err = uc.authRepo.DeleteAllRefreshTokens(ctx, user.ID)
+	}
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Password reset but refresh tokens not fully revoked, user: %d, error_reason: %v", user.ID, err)
+		return error_reason.ErrorUserSessionRevokeIncomplete("密码已重置，但部分设备未能退出登录，请稍后重新找回密码以退出所有设备").WithCause(err)
+	}
+
+	uc.log.WithContext(ctx).Infof("Password reset successfully for user: %d", user.ID)
+	return nil
+}
@@ -0,0 +1,205 @@
+package biz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestUserUsecase_SendResetCode 测试只向已注册邮箱发送找回密码验证码，验证码按找回密码用途存储且受发送频率限制
+func TestUserUsecase_SendResetCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		email      string
+		setupMocks func(*MockUserRepository, *MockCodeRepository, *MockEmailSender)
+		wantErr    func(error) bool
+	}{
+		{
+			name:  "成功发送验证码",
+			email: "test@example.com",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, emailSender *MockEmailSender) {
+				userRepo.On("GetByEmailPublic", mock.Anything, "test@example.com").Return(&User{ID: 1, Email: "test@example.com"}, nil)
+				codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, "test@example.com", 60*time.Second).Return(true, nil)
+				codeRepo.On("StoreCode", mock.Anything, "test@example.com", CodePurposeResetPassword, mock.Anything, mock.Anything).Return(nil)
+				emailSender.On("Send", mock.Anything, mock.MatchedBy(func(message *EmailMessage) bool {
+					return message.ToEmail == "test@example.com"
+				})).Return(nil)
+			},
+		},
+		{
+			name:  "邮箱未注册",
+			email: "missing@example.com",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, emailSender *MockEmailSender) {
+				userRepo.On("GetByEmailPublic", mock.Anything, "missing@example.com").Return((*User)(nil), gorm.ErrRecordNotFound)
+			},
+			wantErr: error_reason.IsUserNotFound,
+		},
+		{
+			name:  "发送过于频繁",
+			email: "frequent@example.com",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, emailSender *MockEmailSender) {
+				userRepo.On("GetByEmailPublic", mock.Anything, "frequent@example.com").Return(&User{ID: 2, Email: "frequent@example.com"}, nil)
+				codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, "frequent@example.com", 60*time.Second).Return(false, nil)
+			},
+			wantErr: error_reason.IsUserTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+			emailSender := new(MockEmailSender)
+			emailLogRepo := new(MockEmailLogRepository)
+			emailLogRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			tt.setupMocks(userRepo, codeRepo, emailSender)
+
+			uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, emailLogRepo, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			err := uc.SendResetCode(context.Background(), tt.email)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				codeRepo.AssertNotCalled(t, "StoreCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				emailSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+			}
+			codeRepo.AssertNotCalled(t, "StoreVerificationCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			userRepo.AssertExpectations(t)
+			codeRepo.AssertExpectations(t)
+			emailSender.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_ResetPassword 测试验证码正确时更新密码并吊销全部刷新令牌（吊销不完整时重试一次，仍不完整时返回错误），验证码错误、过期或密码过短时不修改密码
+func TestUserUsecase_ResetPassword(t *testing.T) {
+	email := "test@example.com"
+	codeHash := newTestCodeHasher().Hash(email, "123456")
+
+	tests := []struct {
+		name        string
+		code        string
+		newPassword string
+		setupMocks  func(*MockUserRepository, *MockCodeRepository, *MockAuthRepository)
+		wantErr     func(error) bool
+		// wantUpdated 返回错误时密码是否已更新
+		wantUpdated bool
+	}{
+		{
+			name:        "重置成功",
+			code:        "123456",
+			newPassword: "newpassword123",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+				codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeResetPassword, codeHash).Return(true, nil)
+				userRepo.On("GetByEmailPublic", mock.Anything, email).Return(&User{ID: 1, Email: email}, nil)
+				userRepo.On("UpdatePasswordHash", mock.Anything, int64(1), mock.MatchedBy(func(hash string) bool {
+					return hash != "" && hash != "newpassword123"
+				})).Return(nil)
+				authRepo.On("DeleteAllRefreshTokens", mock.Anything, int64(1)).Return(nil)
+			},
+		},
+		{
+			name:        "吊销刷新令牌不完整时重试",
+			code:        "123456",
+			newPassword: "newpassword123",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+				codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeResetPassword, codeHash).Return(true, nil)
+				userRepo.On("GetByEmailPublic", mock.Anything, email).Return(&User{ID: 1, Email: email}, nil)
+				userRepo.On("UpdatePasswordHash", mock.Anything, int64(1), mock.Anything).Return(nil)
+				authRepo.On("DeleteAllRefreshTokens", mock.Anything, int64(1)).
+					Return(&RevokeIncompleteError{FailedKeys: []string{"refresh_token:token1"}, Cause: assert.AnError}).Once()
+				authRepo.On("DeleteAllRefreshTokens", mock.Anything, int64(1)).Return(nil).Once()
+			},
+		},
+		{
+			name:        "重试后吊销仍不完整",
+			code:        "123456",
+			newPassword: "newpassword123",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+				codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeResetPassword, codeHash).Return(true, nil)
+				userRepo.On("GetByEmailPublic", mock.Anything, email).Return(&User{ID: 1, Email: email}, nil)
+				userRepo.On("UpdatePasswordHash", mock.Anything, int64(1), mock.Anything).Return(nil)
+				authRepo.On("DeleteAllRefreshTokens", mock.Anything, int64(1)).
+					Return(&RevokeIncompleteError{FailedKeys: []string{"refresh_token:token1"}, Cause: assert.AnError}).Twice()
+			},
+			wantErr:     error_reason.IsUserSessionRevokeIncomplete,
+			wantUpdated: true,
+		},
+		{
+			name:        "用户不存在",
+			code:        "123456",
+			newPassword: "newpassword123",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+				codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeResetPassword, codeHash).Return(true, nil)
+				userRepo.On("GetByEmailPublic", mock.Anything, email).Return((*User)(nil), gorm.ErrRecordNotFound)
+			},
+			wantErr: error_reason.IsUserNotFound,
+		},
+		{
+			name:        "验证码错误",
+			code:        "000000",
+			newPassword: "newpassword123",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+				codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeResetPassword, newTestCodeHasher().Hash(email, "000000")).Return(false, nil)
+			},
+			wantErr: error_reason.IsUserInvalidVerificationCode,
+		},
+		{
+			name:        "验证码过期",
+			code:        "123456",
+			newPassword: "newpassword123",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+				codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeResetPassword, codeHash).Return(false, ErrVerificationCodeExpired)
+			},
+			wantErr: error_reason.IsUserVerificationCodeExpired,
+		},
+		{
+			name:        "新密码过短时不消费验证码",
+			code:        "123456",
+			newPassword: "12345",
+			setupMocks:  func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {},
+			wantErr:     error_reason.IsUserInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+			authRepo := new(MockAuthRepository)
+			tt.setupMocks(userRepo, codeRepo, authRepo)
+
+			uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			err := uc.ResetPassword(context.Background(), email, tt.code, tt.newPassword)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				if tt.wantUpdated {
+					var incomplete *RevokeIncompleteError
+					require.ErrorAs(t, err, &incomplete)
+					assert.Equal(t, []string{"refresh_token:token1"}, incomplete.FailedKeys)
+				} else {
+					userRepo.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+					authRepo.AssertNotCalled(t, "DeleteAllRefreshTokens", mock.Anything, mock.Anything)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+			userRepo.AssertExpectations(t)
+			codeRepo.AssertExpectations(t)
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package biz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPasswordHasher 测试各算法的哈希与校验，以及按前缀识别算法
+func TestPasswordHasher(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme string
+		prefix string
+	}{
+		{
+			name:   "bcrypt",
+			scheme: PasswordHashBcrypt,
+			prefix: "$2a$",
+		},
+		{
+			name:   "argon2id",
+			scheme: PasswordHashArgon2id,
+			prefix: "$argon2id$v=19$m=65536,t=3,p=4$",
+		},
+		{
+			name:   "未配置时使用bcrypt",
+			scheme: "",
+			prefix: "$2a$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := newPasswordHasher(tt.scheme)
+			hashed, err := hasher.Hash("password123")
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(hashed, tt.prefix), "哈希应带算法前缀: %s", hashed)
+			assert.True(t, hasher.Verify("password123", hashed))
+			assert.False(t, hasher.Verify("wrong-password", hashed))
+			assert.Equal(t, hasher.Scheme(), passwordHasherFor(hashed).Scheme())
+
+			again, err := hasher.Hash("password123")
+			require.NoError(t, err)
+			assert.NotEqual(t, hashed, again, "每次哈希应使用不同的盐")
+		})
+	}
+
+	t.Run("无法识别的哈希", func(t *testing.T) {
+		assert.Nil(t, passwordHasherFor("plain-text"))
+		assert.False(t, argon2idPasswordHasher{}.Verify("password123", "$argon2id$v=19$broken"))
+	})
+}
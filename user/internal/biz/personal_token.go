@@ -0,0 +1,319 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+const (
+	// PersonalTokenPrefix 个人访问令牌的固定前缀，便于在日志和密钥扫描中识别
+	PersonalTokenPrefix = "pat_"
+
+	// PersonalTokenScopeUserRead 读取用户资料
+	PersonalTokenScopeUserRead = "user:read"
+	// PersonalTokenScopeUserWrite 修改用户资料
+	PersonalTokenScopeUserWrite = "user:write"
+	// PersonalTokenScopePointsRead 读取点数余额和流水
+	PersonalTokenScopePointsRead = "points:read"
+
+	// maxPersonalTokenNameLength 令牌名称的最大字符数，与 personal_token.name 列长度 VARCHAR(64) 一致
+	maxPersonalTokenNameLength = 64
+	// defaultMaxPersonalTokens 未配置 auth.max_personal_tokens 时每个用户最多持有的未撤销令牌数
+	defaultMaxPersonalTokens = 10
+)
+
+// personalTokenScopes 允许授予个人访问令牌的权限范围
+var personalTokenScopes = map[string]struct{}{
+	PersonalTokenScopeUserRead:   {},
+	PersonalTokenScopeUserWrite:  {},
+	PersonalTokenScopePointsRead: {},
+}
+
+// PersonalToken 个人访问令牌表，用于程序化访问，与会话使用的 JWT 相互独立
+// 只保存令牌的 SHA-256 哈希，明文只在创建或轮换时返回一次
+type PersonalToken struct {
+	ID        int64  `gorm:"column:id;primaryKey" json:"id"`
+	UserID    int64  `gorm:"column:user_id;index;not null" json:"user_id"`
+	Name      string `gorm:"column:name;not null" json:"name"`
+	TokenHash string `gorm:"column:token_hash;uniqueIndex;not null" json:"-"`
+	// Scopes 空格分隔的权限范围，见 PersonalTokenScope* 常量
+	Scopes     string     `gorm:"column:scopes;not null" json:"scopes"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (PersonalToken) TableName() string {
+	return "personal_token"
+}
+
+// ScopeList 返回令牌的权限范围列表
+func (t *PersonalToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+// HasScope 判断令牌是否被授予 scope
+func (t *PersonalToken) HasScope(scope string) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalTokenRepository 个人访问令牌数据访问接口，查询和修改只针对未撤销的令牌
+type PersonalTokenRepository interface {
+	// Create 保存新令牌
+	Create(ctx context.Context, token *PersonalToken) error
+	// CreateWithinLimit 用户未撤销的令牌数少于 limit 时保存新令牌并返回 true，已达到上限时返回 false 且不保存
+	// 同一用户的并发创建按顺序执行，不会超过上限
+	CreateWithinLimit(ctx context.Context, token *PersonalToken, limit int) (bool, error)
+	// ListByUserID 按创建时间倒序返回用户未撤销的令牌
+	ListByUserID(ctx context.Context, userID int64) ([]*PersonalToken, error)
+	// GetByHash 按令牌哈希查询未撤销的令牌，不存在返回 gorm.ErrRecordNotFound
+	GetByHash(ctx context.Context, tokenHash string) (*PersonalToken, error)
+	// Revoke 撤销属于 userID 的令牌，令牌不存在、不属于该用户或已撤销时返回 false
+	Revoke(ctx context.Context, userID, tokenID int64, revokedAt time.Time) (bool, error)
+	// Rotate 替换属于 userID 的令牌的哈希，旧令牌立即失效；令牌不存在、不属于该用户或已撤销时返回 false
+	Rotate(ctx context.Context, userID, tokenID int64, tokenHash string) (bool, error)
+	// TouchLastUsed 更新令牌的最近使用时间
+	TouchLastUsed(ctx context.Context, tokenID int64, usedAt time.Time) error
+}
+
+// PersonalTokenUsecase 个人访问令牌业务逻辑
+type PersonalTokenUsecase struct {
+	repo       PersonalTokenRepository
+	clock      Clock
+	authConfig AuthConfig
+	log        *log.Helper
+}
+
+// NewPersonalTokenUsecase 创建个人访问令牌业务逻辑实例
+func NewPersonalTokenUsecase(repo PersonalTokenRepository, clock Clock, authConfig AuthConfig, logger log.Logger) *PersonalTokenUsecase {
+	return &PersonalTokenUsecase{
+		repo:       repo,
+		clock:      clock,
+		authConfig: authConfig,
+		log:        log.NewHelper(logger),
+	}
+}
+
+// maxPersonalTokens 返回每个用户最多持有的未撤销个人访问令牌数，未配置时使用默认值
+func (c AuthConfig) maxPersonalTokens() int {
+	if c.MaxPersonalTokens > 0 {
+		return c.MaxPersonalTokens
+	}
+	return defaultMaxPersonalTokens
+}
+
+// CreatePersonalToken 为用户创建个人访问令牌，返回令牌记录和令牌明文，明文不会被保存，只在此时可见
+// 未撤销的令牌数已达到 auth.max_personal_tokens 时返回 USER_PERSONAL_TOKEN_LIMIT_EXCEEDED，撤销令牌后即可继续创建
+func (uc *PersonalTokenUsecase) CreatePersonalToken(ctx context.Context, userID int64, name string, scopes []string) (*PersonalToken, string, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenUsecase.CreatePersonalToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "create_personal_token",
+		"user_id":   userID,
+		"scopes":    strings.Join(scopes, " "),
+	})
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", error_reason.ErrorUserInvalidRequest("令牌名称不能为空")
+	}
+	if utf8.RuneCountInString(name) > maxPersonalTokenNameLength {
+		return nil, "", error_reason.ErrorUserInvalidRequest("令牌名称最多%d个字符", maxPersonalTokenNameLength)
+	}
+	normalized, err := normalizePersonalTokenScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plaintext, err := newPersonalToken()
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to generate personal token for user id: %d, error_reason: %v", userID, err)
+		return nil, "", error_reason.ErrorUserInternalError("生成令牌失败")
+	}
+
+	token := &PersonalToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: HashPersonalToken(plaintext),
+		Scopes:    normalized,
+		// created_at 使用列默认值时 GORM 不回填，显式设置以便在响应中返回
+		CreatedAt: uc.clock.Now(),
+	}
+	limit := uc.authConfig.maxPersonalTokens()
+	created, err := uc.repo.CreateWithinLimit(ctx, token, limit)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to create personal token for user id: %d, error_reason: %v", userID, err)
+		return nil, "", error_reason.ErrorUserDatabaseError("创建令牌失败")
+	}
+	if !created {
+		uc.log.WithContext(ctx).Warnf("Personal token limit reached for user id: %d, limit: %d", userID, limit)
+		return nil, "", error_reason.ErrorUserPersonalTokenLimitExceeded("个人访问令牌最多%d个，请先撤销不再使用的令牌", limit)
+	}
+
+	uc.log.WithContext(ctx).Infof("Created personal token id: %d for user id: %d", token.ID, userID)
+	return token, plaintext, nil
+}
+
+// ListPersonalTokens 返回用户未撤销的个人访问令牌，不包含令牌明文
+func (uc *PersonalTokenUsecase) ListPersonalTokens(ctx context.Context, userID int64) ([]*PersonalToken, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenUsecase.ListPersonalTokens")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_personal_tokens",
+		"user_id":   userID,
+	})
+
+	tokens, err := uc.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to list personal tokens for user id: %d, error_reason: %v", userID, err)
+		return nil, error_reason.ErrorUserDatabaseError("查询令牌失败")
+	}
+	return tokens, nil
+}
+
+// RevokePersonalToken 撤销用户的个人访问令牌，撤销后立即失效
+func (uc *PersonalTokenUsecase) RevokePersonalToken(ctx context.Context, userID, tokenID int64) error {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenUsecase.RevokePersonalToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "revoke_personal_token",
+		"user_id":   userID,
+		"token_id":  tokenID,
+	})
+
+	revoked, err := uc.repo.Revoke(ctx, userID, tokenID, uc.clock.Now())
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to revoke personal token id: %d for user id: %d, error_reason: %v", tokenID, userID, err)
+		return error_reason.ErrorUserDatabaseError("撤销令牌失败")
+	}
+	if !revoked {
+		return error_reason.ErrorUserNotFound("令牌不存在或已撤销")
+	}
+
+	uc.log.WithContext(ctx).Infof("Revoked personal token id: %d for user id: %d", tokenID, userID)
+	return nil
+}
+
+// RotatePersonalToken 为用户的个人访问令牌重新生成明文，名称和权限范围不变，旧令牌立即失效
+func (uc *PersonalTokenUsecase) RotatePersonalToken(ctx context.Context, userID, tokenID int64) (string, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenUsecase.RotatePersonalToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "rotate_personal_token",
+		"user_id":   userID,
+		"token_id":  tokenID,
+	})
+
+	plaintext, err := newPersonalToken()
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to generate personal token for user id: %d, error_reason: %v", userID, err)
+		return "", error_reason.ErrorUserInternalError("生成令牌失败")
+	}
+
+	rotated, err := uc.repo.Rotate(ctx, userID, tokenID, HashPersonalToken(plaintext))
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to rotate personal token id: %d for user id: %d, error_reason: %v", tokenID, userID, err)
+		return "", error_reason.ErrorUserDatabaseError("轮换令牌失败")
+	}
+	if !rotated {
+		return "", error_reason.ErrorUserNotFound("令牌不存在或已撤销")
+	}
+
+	uc.log.WithContext(ctx).Infof("Rotated personal token id: %d for user id: %d", tokenID, userID)
+	return plaintext, nil
+}
+
+// ValidatePersonalToken 校验个人访问令牌的哈希和权限范围，返回令牌记录
+// 令牌不存在或已撤销返回 AuthTokenInvalid，缺少 requiredScope 返回 UserPermissionDenied；requiredScope 为空时不检查权限范围
+func (uc *PersonalTokenUsecase) ValidatePersonalToken(ctx context.Context, plaintext, requiredScope string) (*PersonalToken, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenUsecase.ValidatePersonalToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "validate_personal_token",
+		"scope":     requiredScope,
+	})
+
+	if !strings.HasPrefix(plaintext, PersonalTokenPrefix) {
+		return nil, error_reason.ErrorAuthTokenInvalid("令牌无效")
+	}
+
+	token, err := uc.repo.GetByHash(ctx, HashPersonalToken(plaintext))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, error_reason.ErrorAuthTokenInvalid("令牌无效")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to get personal token, error_reason: %v", err)
+		return nil, error_reason.ErrorAuthDatabaseError("校验令牌失败")
+	}
+	if requiredScope != "" && !token.HasScope(requiredScope) {
+		return nil, error_reason.ErrorUserPermissionDenied("令牌缺少权限：%s", requiredScope)
+	}
+
+	// 最近使用时间仅供用户识别闲置令牌，更新失败不影响本次校验
+	if err := uc.repo.TouchLastUsed(ctx, token.ID, uc.clock.Now()); err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to update last used time for personal token id: %d, error_reason: %v", token.ID, err)
+	}
+	return token, nil
+}
+
+// HashPersonalToken 计算令牌明文的 SHA-256 十六进制编码
+// 令牌本身包含 256 位随机数，无需加盐或慢哈希，按哈希即可直接查询
+func HashPersonalToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// newPersonalToken 生成带前缀的随机令牌明文
+func newPersonalToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return PersonalTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// normalizePersonalTokenScopes 校验权限范围并去重排序，返回空格分隔的存储形式
+func normalizePersonalTokenScopes(scopes []string) (string, error) {
+	seen := make(map[string]struct{}, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if _, ok := personalTokenScopes[scope]; !ok {
+			return "", error_reason.ErrorUserInvalidRequest("不支持的令牌权限：%s", scope)
+		}
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		normalized = append(normalized, scope)
+	}
+	if len(normalized) == 0 {
+		return "", error_reason.ErrorUserInvalidRequest("令牌权限不能为空")
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, " "), nil
+}
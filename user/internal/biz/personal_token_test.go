@@ -0,0 +1,371 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockPersonalTokenRepository 个人访问令牌数据访问的 mock 实现
+type MockPersonalTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPersonalTokenRepository) Create(ctx context.Context, token *PersonalToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockPersonalTokenRepository) CreateWithinLimit(ctx context.Context, token *PersonalToken, limit int) (bool, error) {
+	args := m.Called(ctx, token, limit)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPersonalTokenRepository) ListByUserID(ctx context.Context, userID int64) ([]*PersonalToken, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*PersonalToken), args.Error(1)
+}
+
+func (m *MockPersonalTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*PersonalToken, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(*PersonalToken), args.Error(1)
+}
+
+func (m *MockPersonalTokenRepository) Revoke(ctx context.Context, userID, tokenID int64, revokedAt time.Time) (bool, error) {
+	args := m.Called(ctx, userID, tokenID, revokedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPersonalTokenRepository) Rotate(ctx context.Context, userID, tokenID int64, tokenHash string) (bool, error) {
+	args := m.Called(ctx, userID, tokenID, tokenHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPersonalTokenRepository) TouchLastUsed(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	args := m.Called(ctx, tokenID, usedAt)
+	return args.Error(0)
+}
+
+func newTestPersonalTokenUsecase(repo PersonalTokenRepository) *PersonalTokenUsecase {
+	return NewPersonalTokenUsecase(repo, &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, AuthConfig{}, getTestLogger())
+}
+
+// TestPersonalTokenUsecase_CreatePersonalToken 测试创建令牌只保存哈希，明文只在返回值中出现一次
+func TestPersonalTokenUsecase_CreatePersonalToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokenName  string
+		scopes     []string
+		createErr  error
+		wantScopes string
+		wantErr    func(error) bool
+	}{
+		{
+			name:       "创建成功，权限去重排序",
+			tokenName:  " ci ",
+			scopes:     []string{"user:read", "points:read", "user:read"},
+			wantScopes: "points:read user:read",
+		},
+		{
+			name:      "名称为空",
+			tokenName: "  ",
+			scopes:    []string{"user:read"},
+			wantErr:   error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:      "名称过长",
+			tokenName: strings.Repeat("令", maxPersonalTokenNameLength+1),
+			scopes:    []string{"user:read"},
+			wantErr:   error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:      "权限为空",
+			tokenName: "ci",
+			wantErr:   error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:      "不支持的权限",
+			tokenName: "ci",
+			scopes:    []string{"admin"},
+			wantErr:   error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:      "保存失败",
+			tokenName: "ci",
+			scopes:    []string{"user:read"},
+			createErr: errors.New("connection refused"),
+			wantErr:   error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockPersonalTokenRepository)
+			var saved *PersonalToken
+			repo.On("CreateWithinLimit", mock.Anything, mock.Anything, defaultMaxPersonalTokens).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(*PersonalToken)
+			}).Return(tt.createErr == nil, tt.createErr).Maybe()
+
+			token, plaintext, err := newTestPersonalTokenUsecase(repo).CreatePersonalToken(context.Background(), 1, tt.tokenName, tt.scopes)
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				assert.Empty(t, plaintext)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(plaintext, PersonalTokenPrefix))
+			assert.Equal(t, "ci", token.Name)
+			assert.Equal(t, tt.wantScopes, token.Scopes)
+			// 存储的是明文的哈希，而不是明文本身
+			assert.Equal(t, HashPersonalToken(plaintext), saved.TokenHash)
+			assert.NotContains(t, saved.TokenHash, plaintext)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPersonalTokenUsecase_CreatePersonalToken_Unique 测试每次创建的令牌明文互不相同
+func TestPersonalTokenUsecase_CreatePersonalToken_Unique(t *testing.T) {
+	repo := new(MockPersonalTokenRepository)
+	repo.On("CreateWithinLimit", mock.Anything, mock.Anything, defaultMaxPersonalTokens).Return(true, nil)
+	uc := newTestPersonalTokenUsecase(repo)
+
+	_, first, err := uc.CreatePersonalToken(context.Background(), 1, "a", []string{"user:read"})
+	require.NoError(t, err)
+	_, second, err := uc.CreatePersonalToken(context.Background(), 1, "b", []string{"user:read"})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, HashPersonalToken(first), HashPersonalToken(second))
+}
+
+// limitedPersonalTokenRepository 按用户统计未撤销令牌数的内存令牌存储
+type limitedPersonalTokenRepository struct {
+	PersonalTokenRepository
+	tokens map[int64]*PersonalToken
+	nextID int64
+}
+
+func (r *limitedPersonalTokenRepository) CreateWithinLimit(_ context.Context, token *PersonalToken, limit int) (bool, error) {
+	active := 0
+	for _, t := range r.tokens {
+		if t.UserID == token.UserID && t.RevokedAt == nil {
+			active++
+		}
+	}
+	if active >= limit {
+		return false, nil
+	}
+	r.nextID++
+	token.ID = r.nextID
+	r.tokens[token.ID] = token
+	return true, nil
+}
+
+func (r *limitedPersonalTokenRepository) Revoke(_ context.Context, userID, tokenID int64, revokedAt time.Time) (bool, error) {
+	token, ok := r.tokens[tokenID]
+	if !ok || token.UserID != userID || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.RevokedAt = &revokedAt
+	return true, nil
+}
+
+// TestPersonalTokenUsecase_CreatePersonalToken_Limit 测试未撤销令牌数达到上限后拒绝创建，撤销一个令牌后可以再创建；上限按用户统计
+func TestPersonalTokenUsecase_CreatePersonalToken_Limit(t *testing.T) {
+	const limit = 3
+	ctx := context.Background()
+	repo := &limitedPersonalTokenRepository{tokens: make(map[int64]*PersonalToken)}
+	uc := NewPersonalTokenUsecase(repo, &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, AuthConfig{MaxPersonalTokens: limit}, getTestLogger())
+
+	var first *PersonalToken
+	for i := 0; i < limit; i++ {
+		token, _, err := uc.CreatePersonalToken(ctx, 1, "ci", []string{"user:read"})
+		require.NoError(t, err)
+		if first == nil {
+			first = token
+		}
+	}
+
+	_, plaintext, err := uc.CreatePersonalToken(ctx, 1, "ci", []string{"user:read"})
+	assert.True(t, error_reason.IsUserPersonalTokenLimitExceeded(err), "unexpected error: %v", err)
+	assert.Empty(t, plaintext)
+
+	// 其他用户不受影响
+	_, _, err = uc.CreatePersonalToken(ctx, 2, "ci", []string{"user:read"})
+	require.NoError(t, err)
+
+	require.NoError(t, uc.RevokePersonalToken(ctx, 1, first.ID))
+	_, plaintext, err = uc.CreatePersonalToken(ctx, 1, "ci", []string{"user:read"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, plaintext)
+}
+
+// TestPersonalTokenUsecase_ListPersonalTokens 测试列出的令牌序列化后不包含哈希
+func TestPersonalTokenUsecase_ListPersonalTokens(t *testing.T) {
+	repo := new(MockPersonalTokenRepository)
+	repo.On("ListByUserID", mock.Anything, int64(1)).Return([]*PersonalToken{
+		{ID: 10, UserID: 1, Name: "ci", TokenHash: HashPersonalToken("pat_secret"), Scopes: "user:read"},
+	}, nil)
+
+	tokens, err := newTestPersonalTokenUsecase(repo).ListPersonalTokens(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	data, err := json.Marshal(tokens)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), HashPersonalToken("pat_secret"))
+	assert.NotContains(t, string(data), "pat_secret")
+}
+
+// TestPersonalTokenUsecase_RevokePersonalToken 测试撤销令牌，令牌不存在或已撤销时返回未找到
+func TestPersonalTokenUsecase_RevokePersonalToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		revoked   bool
+		revokeErr error
+		wantErr   func(error) bool
+	}{
+		{
+			name:    "撤销成功",
+			revoked: true,
+		},
+		{
+			name:    "令牌不存在或已撤销",
+			wantErr: error_reason.IsUserNotFound,
+		},
+		{
+			name:      "数据库错误",
+			revokeErr: errors.New("connection refused"),
+			wantErr:   error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockPersonalTokenRepository)
+			repo.On("Revoke", mock.Anything, int64(1), int64(10), mock.Anything).Return(tt.revoked, tt.revokeErr)
+
+			err := newTestPersonalTokenUsecase(repo).RevokePersonalToken(context.Background(), 1, 10)
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPersonalTokenUsecase_RotatePersonalToken 测试轮换后存储新明文的哈希
+func TestPersonalTokenUsecase_RotatePersonalToken(t *testing.T) {
+	repo := new(MockPersonalTokenRepository)
+	var newHash string
+	repo.On("Rotate", mock.Anything, int64(1), int64(10), mock.Anything).Run(func(args mock.Arguments) {
+		newHash = args.String(3)
+	}).Return(true, nil)
+
+	plaintext, err := newTestPersonalTokenUsecase(repo).RotatePersonalToken(context.Background(), 1, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, HashPersonalToken(plaintext), newHash)
+
+	missing := new(MockPersonalTokenRepository)
+	missing.On("Rotate", mock.Anything, int64(1), int64(11), mock.Anything).Return(false, nil)
+	_, err = newTestPersonalTokenUsecase(missing).RotatePersonalToken(context.Background(), 1, 11)
+	assert.True(t, error_reason.IsUserNotFound(err), "unexpected error: %v", err)
+}
+
+// TestPersonalTokenUsecase_ValidatePersonalToken 测试按哈希查找令牌并检查权限范围
+func TestPersonalTokenUsecase_ValidatePersonalToken(t *testing.T) {
+	const plaintext = "pat_0123456789abcdef"
+	stored := &PersonalToken{ID: 10, UserID: 1, Scopes: "points:read user:read"}
+
+	tests := []struct {
+		name       string
+		plaintext  string
+		scope      string
+		setupMocks func(*MockPersonalTokenRepository)
+		wantErr    func(error) bool
+	}{
+		{
+			name:      "令牌有效且具备权限",
+			plaintext: plaintext,
+			scope:     PersonalTokenScopePointsRead,
+			setupMocks: func(repo *MockPersonalTokenRepository) {
+				repo.On("GetByHash", mock.Anything, HashPersonalToken(plaintext)).Return(stored, nil)
+				repo.On("TouchLastUsed", mock.Anything, int64(10), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:      "更新最近使用时间失败不影响校验",
+			plaintext: plaintext,
+			scope:     PersonalTokenScopeUserRead,
+			setupMocks: func(repo *MockPersonalTokenRepository) {
+				repo.On("GetByHash", mock.Anything, HashPersonalToken(plaintext)).Return(stored, nil)
+				repo.On("TouchLastUsed", mock.Anything, int64(10), mock.Anything).Return(errors.New("connection refused"))
+			},
+		},
+		{
+			name:      "缺少权限",
+			plaintext: plaintext,
+			scope:     PersonalTokenScopeUserWrite,
+			setupMocks: func(repo *MockPersonalTokenRepository) {
+				repo.On("GetByHash", mock.Anything, HashPersonalToken(plaintext)).Return(stored, nil)
+			},
+			wantErr: error_reason.IsUserPermissionDenied,
+		},
+		{
+			name:      "令牌不存在或已撤销",
+			plaintext: plaintext,
+			setupMocks: func(repo *MockPersonalTokenRepository) {
+				repo.On("GetByHash", mock.Anything, HashPersonalToken(plaintext)).Return((*PersonalToken)(nil), gorm.ErrRecordNotFound)
+			},
+			wantErr: error_reason.IsAuthTokenInvalid,
+		},
+		{
+			name:      "前缀不正确时不查询存储",
+			plaintext: "eyJhbGciOiJIUzI1NiJ9",
+			wantErr:   error_reason.IsAuthTokenInvalid,
+		},
+		{
+			name:      "数据库错误",
+			plaintext: plaintext,
+			setupMocks: func(repo *MockPersonalTokenRepository) {
+				repo.On("GetByHash", mock.Anything, HashPersonalToken(plaintext)).Return((*PersonalToken)(nil), errors.New("connection refused"))
+			},
+			wantErr: error_reason.IsAuthDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockPersonalTokenRepository)
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo)
+			}
+
+			token, err := newTestPersonalTokenUsecase(repo).ValidatePersonalToken(context.Background(), tt.plaintext, tt.scope)
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(10), token.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,722 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+const (
+	// TransactionTypeConsume 消耗点数
+	TransactionTypeConsume = "CONSUME"
+	// TransactionTypeRecharge 充值点数
+	TransactionTypeRecharge = "RECHARGE"
+
+	// bulkRechargeBatchSize 批量充值时每个事务处理的用户数
+	bulkRechargeBatchSize = 500
+	// adjustPointsMaxAttempts 管理员调整点数时因版本冲突重新开启事务的最大尝试次数
+	adjustPointsMaxAttempts = 3
+
+	// maxDailyFlowDays 单次查询每日点数变化的最大天数
+	maxDailyFlowDays = 366
+
+	// exportPageSize 导出流水时每次从数据库读取的条数
+	exportPageSize = 500
+
+	// defaultMaxDescriptionLength 默认流水描述最大字符数，与 point_transaction.description 列长度 VARCHAR(255) 一致
+	defaultMaxDescriptionLength = 255
+
+	// defaultArchiveBatchSize 归档流水时每个事务默认移动的条数
+	defaultArchiveBatchSize = 500
+
+	// defaultArchiveInterval 定时归档任务的默认执行间隔
+	defaultArchiveInterval = time.Hour
+)
+
+// ErrPointVersionConflict 乐观锁更新点数时多次重试仍发生版本冲突
+var ErrPointVersionConflict = errors.New("point balance version conflict")
+
+// ErrInsufficientPoints 用户点数余额不足以完成本次消耗
+var ErrInsufficientPoints = errors.New("insufficient points")
+
+// 流水元数据的常用字段
+const (
+	// TransactionMetadataSource 流水来源，如 bulk_recharge、book_generation、account_deletion
+	TransactionMetadataSource = "source"
+	// TransactionMetadataPromoID 关联的活动ID
+	TransactionMetadataPromoID = "promo_id"
+	// TransactionMetadataOperatorID 执行操作的管理员ID
+	TransactionMetadataOperatorID = "operator_id"
+)
+
+// TransactionSourceAdminAdjustment 管理员手动调整点数时写入的流水来源
+const TransactionSourceAdminAdjustment = "admin_adjustment"
+
+// UserPoint 用户点数表
+type UserPoint struct {
+	ID            int64     `gorm:"column:id;primaryKey" json:"id"`
+	UserID        int64     `gorm:"column:user_id;uniqueIndex;not null" json:"user_id"`
+	CurrentPoints uint32    `gorm:"column:current_points;not null;default:0" json:"current_points"`
+	TotalConsumed uint32    `gorm:"column:total_consumed;not null;default:0" json:"total_consumed"`
+	CreatedAt     time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	// Version 乐观锁版本号，新建记录时使用列默认值0，每次按版本号条件更新成功后加一
+	Version uint32 `gorm:"column:version;<-:update;not null;default:0" json:"-"`
+}
+
+// TableName 指定表名
+func (UserPoint) TableName() string {
+	return "user_point"
+}
+
+// PointTransaction 点数交易流水表
+// Description 用于展示，Metadata 保存结构化的键值（见 TransactionMetadata* 字段），用于按来源归因
+type PointTransaction struct {
+	ID            int64          `gorm:"column:id;primaryKey" json:"id"`
+	UserID        int64          `gorm:"column:user_id;index;not null" json:"user_id"`
+	Type          string         `gorm:"column:type;not null" json:"type"`
+	Amount        uint32         `gorm:"column:amount;not null" json:"amount"`
+	RelatedBookID *int64         `gorm:"column:related_book_id" json:"related_book_id,omitempty"`
+	Description   string         `gorm:"column:description" json:"description,omitempty"`
+	Metadata      datatypes.JSON `gorm:"column:metadata" json:"metadata,omitempty"`
+	CreatedAt     time.Time      `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (PointTransaction) TableName() string {
+	return "point_transaction"
+}
+
+// DailyFlow 用户某一天的点数净变化（充值减消耗），用于余额变化趋势图
+type DailyFlow struct {
+	Date      time.Time `json:"date"`
+	NetChange int64     `json:"net_change"`
+}
+
+// UserPointRepository 用户点数数据访问接口
+type UserPointRepository interface {
+	// AddPointsBatch 批量增加用户点数，用户点数记录不存在时创建
+	AddPointsBatch(ctx context.Context, points []*UserPoint) error
+	// MergeInto 将 fromUserID 的点数余额和累计消耗合并到 toUserID，并删除 fromUserID 的点数记录
+	MergeInto(ctx context.Context, fromUserID, toUserID int64) error
+	// GetOrCreate 获取用户点数记录，不存在时创建余额为0的记录，并发创建时返回已存在的记录
+	GetOrCreate(ctx context.Context, userID int64) (*UserPoint, error)
+	// UpdateWithVersion 使用乐观锁更新用户点数：读取记录及版本号，由 apply 修改余额和累计消耗后按版本号条件更新
+	// 版本冲突时重新读取并重试，重试耗尽返回 ErrPointVersionConflict；在事务中调用时只尝试一次，由调用方开启新事务重试；记录不存在返回 gorm.ErrRecordNotFound，apply 返回的错误原样返回
+	UpdateWithVersion(ctx context.Context, userID int64, apply func(point *UserPoint) error) (*UserPoint, error)
+	// Consume 在同一条更新语句中扣减余额并累加累计消耗，返回更新后的记录
+	// 余额不足时返回 ErrInsufficientPoints 且不做任何修改，记录不存在返回 gorm.ErrRecordNotFound
+	Consume(ctx context.Context, userID int64, amount uint32) (*UserPoint, error)
+	// ZeroOut 在事务中锁定并清零用户点数余额，返回清零前的余额；记录不存在时返回0
+	ZeroOut(ctx context.Context, userID int64) (uint32, error)
+	// Archive 将用户点数记录移入归档表并删除原记录，记录不存在时不做任何操作
+	Archive(ctx context.Context, userID int64) error
+}
+
+// PointTransactionRepository 点数流水数据访问接口
+// 分页查询的 pageSize 为0或超过配置的单次查询上限时按上限截断，返回条数少于剩余总数即表示结果被截断
+type PointTransactionRepository interface {
+	// GetByUserID 按创建时间倒序分页查询用户流水，同时返回总条数
+	GetByUserID(ctx context.Context, userID int64, page, pageSize int) ([]*PointTransaction, int64, error)
+	// CreateBatch 在事务中分批写入流水，任意一批失败则整体回滚
+	CreateBatch(ctx context.Context, txns []*PointTransaction) error
+	// GetByRelatedBookID 按创建时间倒序分页查询关联某绘本的流水（跨用户），同时返回总条数
+	GetByRelatedBookID(ctx context.Context, bookID int64, page, pageSize int) ([]*PointTransaction, int64, error)
+	// GetByMetadata 按创建时间倒序分页查询元数据字段 key 等于 value 的流水（跨用户），同时返回总条数
+	GetByMetadata(ctx context.Context, key string, value interface{}, page, pageSize int) ([]*PointTransaction, int64, error)
+	// ReassignUser 将 fromUserID 的所有流水改为归属 toUserID，返回受影响的条数
+	ReassignUser(ctx context.Context, fromUserID, toUserID int64) (int64, error)
+	// DailyFlow 按天汇总用户在 [from, to) 内的点数净变化，只返回有流水的日期，按日期升序
+	DailyFlow(ctx context.Context, userID int64, from, to time.Time) ([]DailyFlow, error)
+	// Archive 将用户的所有流水移入归档表并删除原记录，返回归档的条数
+	Archive(ctx context.Context, userID int64) (int64, error)
+	// ArchiveBefore 将最多 limit 条创建时间早于 before 的流水移入归档表并删除原记录，返回归档的条数；需在事务中调用
+	ArchiveBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// PointConfig 点数配置
+type PointConfig struct {
+	// MaxDescriptionLength 流水描述的最大字符数，0 表示使用默认值 255
+	MaxDescriptionLength int
+	// TransactionRetention 流水的保留期，早于该时长的流水会被移入归档表，0 表示不归档
+	TransactionRetention time.Duration
+	// ArchiveBatchSize 归档时每个事务移动的流水条数，0 表示使用默认值 500
+	ArchiveBatchSize int
+	// ArchiveInterval 定时归档任务的执行间隔，0 表示使用默认值 1h
+	ArchiveInterval time.Duration
+}
+
+// maxDescriptionLength 返回流水描述的最大字符数，未配置时使用默认值
+func (c PointConfig) maxDescriptionLength() int {
+	if c.MaxDescriptionLength > 0 {
+		return c.MaxDescriptionLength
+	}
+	return defaultMaxDescriptionLength
+}
+
+// archiveBatchSize 返回归档时每个事务移动的流水条数，未配置时使用默认值
+func (c PointConfig) archiveBatchSize() int {
+	if c.ArchiveBatchSize > 0 {
+		return c.ArchiveBatchSize
+	}
+	return defaultArchiveBatchSize
+}
+
+// archiveInterval 返回定时归档任务的执行间隔，未配置时使用默认值
+func (c PointConfig) archiveInterval() time.Duration {
+	if c.ArchiveInterval > 0 {
+		return c.ArchiveInterval
+	}
+	return defaultArchiveInterval
+}
+
+// PointUsecase 点数业务逻辑
+type PointUsecase struct {
+	pointRepo UserPointRepository
+	txnRepo   PointTransactionRepository
+	tx        Transaction
+	paging    Pagination
+	config    PointConfig
+	slowOp    *SlowOperationLogger
+	metrics   *pointMetrics
+	log       *log.Helper
+}
+
+// NewPointUsecase 创建点数业务逻辑实例
+func NewPointUsecase(pointRepo UserPointRepository, txnRepo PointTransactionRepository, tx Transaction, paging Pagination, config PointConfig, slowOp *SlowOperationLogger, logger log.Logger) *PointUsecase {
+	return &PointUsecase{
+		pointRepo: pointRepo,
+		txnRepo:   txnRepo,
+		tx:        tx,
+		paging:    paging,
+		config:    config,
+		slowOp:    slowOp,
+		metrics:   newPointMetrics(nil, logger),
+		log:       log.NewHelper(logger),
+	}
+}
+
+// GetPointBalance 获取用户点数余额，尚未有点数记录的用户会创建余额为0的记录
+func (uc *PointUsecase) GetPointBalance(ctx context.Context, userID int64) (*UserPoint, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.GetPointBalance")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "get_point_balance",
+		"user_id":   userID,
+	})
+
+	point, err := uc.pointRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to get point balance for user: %d, error_reason: %v", userID, err)
+		return nil, error_reason.ErrorUserDatabaseError("查询点数余额失败")
+	}
+
+	return point, nil
+}
+
+// ListTransactions 分页获取用户点数流水及分页元信息
+func (uc *PointUsecase) ListTransactions(ctx context.Context, userID int64, page, pageSize int) ([]*PointTransaction, PageInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.ListTransactions")
+	defer span.End()
+
+	page, pageSize, err := uc.paging.Normalize(page, pageSize)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_transactions",
+		"user_id":   userID,
+		"page":      page,
+		"page_size": pageSize,
+	})
+
+	txns, total, err := uc.txnRepo.GetByUserID(ctx, userID, page, pageSize)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to list point transactions for user: %d, error_reason: %v", userID, err)
+		return nil, PageInfo{}, error_reason.ErrorUserDatabaseError("查询点数流水失败")
+	}
+
+	return txns, NewPageInfo(page, pageSize, total), nil
+}
+
+// ExportTransactions 按创建时间倒序逐页读取用户的全部点数流水并交给 fn 处理，内存中最多只保留一页
+// fn 返回错误时停止导出并原样返回该错误
+func (uc *PointUsecase) ExportTransactions(ctx context.Context, userID int64, fn func(txns []*PointTransaction) error) error {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.ExportTransactions")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "export_transactions",
+		"user_id":   userID,
+	})
+
+	exported := 0
+	for page := 1; ; page++ {
+		txns, total, err := uc.txnRepo.GetByUserID(ctx, userID, page, exportPageSize)
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to export point transactions for user: %d, page: %d, error_reason: %v", userID, page, err)
+			return error_reason.ErrorUserDatabaseError("导出点数流水失败")
+		}
+		if len(txns) == 0 {
+			break
+		}
+		if err := fn(txns); err != nil {
+			return err
+		}
+
+		exported += len(txns)
+		if len(txns) < exportPageSize || int64(exported) >= total {
+			break
+		}
+	}
+
+	uc.log.WithContext(ctx).Infof("Exported %d point transactions for user: %d", exported, userID)
+	return nil
+}
+
+// ListTransactionsByBook 分页获取关联某绘本的点数流水（跨用户），用于运营统计
+func (uc *PointUsecase) ListTransactionsByBook(ctx context.Context, bookID int64, page, pageSize int) ([]*PointTransaction, PageInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.ListTransactionsByBook")
+	defer span.End()
+
+	page, pageSize, err := uc.paging.Normalize(page, pageSize)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_transactions_by_book",
+		"book_id":   bookID,
+		"page":      page,
+		"page_size": pageSize,
+	})
+
+	txns, total, err := uc.txnRepo.GetByRelatedBookID(ctx, bookID, page, pageSize)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to list point transactions for book: %d, error_reason: %v", bookID, err)
+		return nil, PageInfo{}, error_reason.ErrorUserDatabaseError("查询点数流水失败")
+	}
+
+	return txns, NewPageInfo(page, pageSize, total), nil
+}
+
+// ListTransactionsBySource 分页获取元数据来源为 source 的点数流水（跨用户），用于按来源追溯流水
+func (uc *PointUsecase) ListTransactionsBySource(ctx context.Context, source string, page, pageSize int) ([]*PointTransaction, PageInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.ListTransactionsBySource")
+	defer span.End()
+
+	if source == "" {
+		return nil, PageInfo{}, error_reason.ErrorUserInvalidRequest("流水来源不能为空")
+	}
+
+	page, pageSize, err := uc.paging.Normalize(page, pageSize)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_transactions_by_source",
+		"source":    source,
+		"page":      page,
+		"page_size": pageSize,
+	})
+
+	txns, total, err := uc.txnRepo.GetByMetadata(ctx, TransactionMetadataSource, source, page, pageSize)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to list point transactions for source: %s, error_reason: %v", source, err)
+		return nil, PageInfo{}, error_reason.ErrorUserDatabaseError("查询点数流水失败")
+	}
+
+	return txns, NewPageInfo(page, pageSize, total), nil
+}
+
+// DailyFlow 获取用户在 [from, to] 日期范围内每天的点数净变化，没有流水的日期补0
+func (uc *PointUsecase) DailyFlow(ctx context.Context, userID int64, from, to time.Time) ([]DailyFlow, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.DailyFlow")
+	defer span.End()
+
+	from = truncateToDay(from)
+	to = truncateToDay(to)
+	if to.Before(from) {
+		return nil, error_reason.ErrorUserInvalidRequest("结束日期不能早于开始日期")
+	}
+	// 夏令时切换日不足或超过24小时，四舍五入到整天
+	days := int(math.Round(to.Sub(from).Hours()/24)) + 1
+	if days > maxDailyFlowDays {
+		return nil, error_reason.ErrorUserInvalidRequest("查询范围不能超过366天")
+	}
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "daily_flow",
+		"user_id":   userID,
+		"days":      days,
+	})
+
+	flows, err := uc.txnRepo.DailyFlow(ctx, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to query daily point flow for user: %d, error_reason: %v", userID, err)
+		return nil, error_reason.ErrorUserDatabaseError("查询点数变化失败")
+	}
+
+	// 数据库返回的日期可能带有不同时区，按日期字符串对齐
+	changes := make(map[string]int64, len(flows))
+	for _, flow := range flows {
+		changes[flow.Date.Format(time.DateOnly)] += flow.NetChange
+	}
+
+	timeline := make([]DailyFlow, 0, days)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		timeline = append(timeline, DailyFlow{Date: day, NetChange: changes[day.Format(time.DateOnly)]})
+	}
+	return timeline, nil
+}
+
+// truncateToDay 截断到所在时区当天零点
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// validateDescription 校验流水描述长度，避免超出列长度导致写入失败
+func (uc *PointUsecase) validateDescription(ctx context.Context, description string) error {
+	maxLength := uc.config.maxDescriptionLength()
+	if length := utf8.RuneCountInString(description); length > maxLength {
+		uc.log.WithContext(ctx).Warnf("Transaction description too long, length: %d, max: %d", length, maxLength)
+		return error_reason.ErrorUserInvalidRequest("流水描述不能超过%d个字符", maxLength)
+	}
+	return nil
+}
+
+// BulkRecharge 为多个用户充值相同点数（例如运营活动），返回成功充值的用户数
+// 用户按批次处理，每批的余额更新和流水写入在同一个事务中完成；某一批失败时之前的批次已生效
+func (uc *PointUsecase) BulkRecharge(ctx context.Context, userIDs []int64, amount uint32, description string) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.BulkRecharge")
+	defer span.End()
+	defer uc.slowOp.Track(ctx, "BulkRecharge")()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":  "bulk_recharge",
+		"user_count": len(userIDs),
+		"amount":     amount,
+	})
+
+	if amount == 0 {
+		uc.log.WithContext(ctx).Warn("Bulk recharge with zero amount")
+		return 0, error_reason.ErrorUserInvalidRequest("充值点数必须大于0")
+	}
+	if err := uc.validateDescription(ctx, description); err != nil {
+		return 0, err
+	}
+
+	// 去重，避免同一用户在一次活动中被重复充值
+	seen := make(map[int64]struct{}, len(userIDs))
+	uniqueIDs := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		uniqueIDs = append(uniqueIDs, id)
+	}
+
+	credited := 0
+	for start := 0; start < len(uniqueIDs); start += bulkRechargeBatchSize {
+		// 请求被取消或超时后不再开始新的批次
+		if err := ctx.Err(); err != nil {
+			uc.log.WithContext(ctx).Warnf("Bulk recharge aborted, credited: %d, error_reason: %v", credited, err)
+			uc.metrics.recordOperation(ctx, PointOperationRecharge, err)
+			return credited, err
+		}
+
+		end := start + bulkRechargeBatchSize
+		if end > len(uniqueIDs) {
+			end = len(uniqueIDs)
+		}
+		batch := uniqueIDs[start:end]
+
+		points := make([]*UserPoint, 0, len(batch))
+		txns := make([]*PointTransaction, 0, len(batch))
+		for _, userID := range batch {
+			points = append(points, &UserPoint{UserID: userID, CurrentPoints: amount})
+			txns = append(txns, &PointTransaction{
+				UserID:      userID,
+				Type:        TransactionTypeRecharge,
+				Amount:      amount,
+				Description: description,
+			})
+		}
+
+		err := uc.tx.InTxWithRetry(ctx, func(ctx context.Context) error {
+			if err := uc.pointRepo.AddPointsBatch(ctx, points); err != nil {
+				return err
+			}
+			return uc.txnRepo.CreateBatch(ctx, txns)
+		})
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to bulk recharge batch starting at %d, credited: %d, error_reason: %v", start, credited, err)
+			uc.metrics.recordOperation(ctx, PointOperationRecharge, err)
+			if errors.Is(err, ErrTxRetriesExhausted) {
+				return credited, error_reason.ErrorUserServiceUnavailable("系统繁忙，批量充值失败，请稍后重试")
+			}
+			return credited, error_reason.ErrorUserDatabaseError("批量充值失败")
+		}
+		for range batch {
+			uc.metrics.recordAmount(ctx, PointOperationRecharge, amount)
+		}
+		credited += len(batch)
+	}
+
+	uc.metrics.recordOperation(ctx, PointOperationRecharge, nil)
+	uc.log.WithContext(ctx).Infof("Bulk recharge completed, users: %d, amount: %d", credited, amount)
+	return credited, nil
+}
+
+// ConsumePoints 扣减用户点数并写入一条消耗流水，返回更新后的点数记录
+// 余额更新和流水写入在同一个事务中完成，扣减由 UserPointRepository.Consume 以余额充足为条件的单条更新保证并发安全
+// 余额不足（含尚无点数记录）时不做任何修改，返回的错误可用 errors.Is 匹配 ErrInsufficientPoints
+func (uc *PointUsecase) ConsumePoints(ctx context.Context, userID int64, amount uint32, relatedBookID *int64, description string) (*UserPoint, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.ConsumePoints")
+	defer span.End()
+	defer uc.slowOp.Track(ctx, "ConsumePoints")()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "consume_points",
+		"user_id":   userID,
+		"amount":    amount,
+	})
+
+	if amount == 0 {
+		uc.log.WithContext(ctx).Warnf("Consume zero points for user: %d", userID)
+		return nil, error_reason.ErrorUserInvalidRequest("消耗点数必须大于0")
+	}
+	if err := uc.validateDescription(ctx, description); err != nil {
+		return nil, err
+	}
+
+	var (
+		point *UserPoint
+		txn   *PointTransaction
+	)
+	err := uc.tx.InTxWithRetry(ctx, func(ctx context.Context) error {
+		var err error
+		point, err = uc.pointRepo.Consume(ctx, userID, amount)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 尚未有点数记录的用户余额视为0
+			return ErrInsufficientPoints
+		}
+		if err != nil {
+			return err
+		}
+		txn = &PointTransaction{
+			UserID:        userID,
+			Type:          TransactionTypeConsume,
+			Amount:        amount,
+			RelatedBookID: relatedBookID,
+			Description:   description,
+		}
+		return uc.txnRepo.CreateBatch(ctx, []*PointTransaction{txn})
+	})
+	uc.metrics.recordOperation(ctx, PointOperationConsume, err)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInsufficientPoints):
+			uc.log.WithContext(ctx).Warnf("Insufficient points for user: %d, amount: %d", userID, amount)
+			return nil, error_reason.ErrorUserInvalidRequest("点数余额不足").WithCause(ErrInsufficientPoints)
+		case errors.Is(err, ErrTxRetriesExhausted):
+			uc.log.WithContext(ctx).Errorf("Failed to consume points for user: %d, error_reason: %v", userID, err)
+			return nil, error_reason.ErrorUserServiceUnavailable("系统繁忙，扣减点数失败，请稍后重试")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to consume points for user: %d, error_reason: %v", userID, err)
+		return nil, error_reason.ErrorUserDatabaseError("扣减点数失败")
+	}
+	uc.metrics.recordAmount(ctx, PointOperationConsume, amount)
+
+	// 余额和流水ID使用 int64 类型属性，便于在链路查询中按数值过滤
+	span.SetAttributes(
+		attribute.Int64("balance_before", int64(point.CurrentPoints)+int64(amount)),
+		attribute.Int64("balance_after", int64(point.CurrentPoints)),
+		attribute.Int64("transaction_id", txn.ID),
+	)
+
+	uc.log.WithContext(ctx).Infof("Consumed %d points for user: %d", amount, userID)
+	return point, nil
+}
+
+// AdjustPoints 管理员按 delta 调整用户点数余额（正数增加、负数扣减），用于人工补偿或纠错，返回调整后的点数记录
+// 每次尝试在一个短事务中通过 UserPointRepository.UpdateWithVersion 读取版本号并按版本号条件更新余额，同时写入一条带操作人的流水，
+// 余额和流水一起提交或回滚；版本冲突时回滚并开启新事务重试，不持有行锁；累计消耗不变
+func (uc *PointUsecase) AdjustPoints(ctx context.Context, operatorID, userID int64, delta int32, description string) (*UserPoint, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.AdjustPoints")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":   "adjust_points",
+		"operator_id": operatorID,
+		"user_id":     userID,
+		"delta":       delta,
+	})
+
+	if delta == 0 {
+		uc.log.WithContext(ctx).Warnf("Adjust zero points for user: %d", userID)
+		return nil, error_reason.ErrorUserInvalidRequest("调整点数不能为0")
+	}
+	if err := uc.validateDescription(ctx, description); err != nil {
+		return nil, err
+	}
+
+	txn := PointTransaction{
+		UserID:      userID,
+		Type:        TransactionTypeRecharge,
+		Amount:      uint32(delta),
+		Description: description,
+	}
+	if delta < 0 {
+		txn.Type = TransactionTypeConsume
+		txn.Amount = uint32(-int64(delta))
+	}
+	metadata, err := json.Marshal(map[string]interface{}{
+		TransactionMetadataSource:     TransactionSourceAdminAdjustment,
+		TransactionMetadataOperatorID: operatorID,
+	})
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to encode adjustment metadata for user: %d, error_reason: %v", userID, err)
+		return nil, error_reason.ErrorUserDatabaseError("调整点数失败")
+	}
+	txn.Metadata = datatypes.JSON(metadata)
+
+	if delta > 0 {
+		// 尚无点数记录的用户先创建余额为0的记录
+		if _, err := uc.pointRepo.GetOrCreate(ctx, userID); err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to get points for user: %d, error_reason: %v", userID, err)
+			uc.metrics.recordOperation(ctx, PointOperationAdjust, err)
+			return nil, error_reason.ErrorUserDatabaseError("调整点数失败")
+		}
+	}
+
+	apply := func(point *UserPoint) error {
+		balance := int64(point.CurrentPoints) + int64(delta)
+		if balance < 0 {
+			return ErrInsufficientPoints
+		}
+		if balance > math.MaxUint32 {
+			return error_reason.ErrorUserInvalidRequest("调整后点数超出上限")
+		}
+		point.CurrentPoints = uint32(balance)
+		return nil
+	}
+
+	var point *UserPoint
+	for attempt := 1; attempt <= adjustPointsMaxAttempts; attempt++ {
+		err = uc.tx.InTxWithRetry(ctx, func(ctx context.Context) error {
+			var err error
+			point, err = uc.pointRepo.UpdateWithVersion(ctx, userID, apply)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// 尚未有点数记录的用户余额视为0
+				return ErrInsufficientPoints
+			}
+			if err != nil {
+				return err
+			}
+			// 每次尝试写入新的流水副本，避免失败的尝试留下的字段影响重试
+			record := txn
+			return uc.txnRepo.CreateBatch(ctx, []*PointTransaction{&record})
+		})
+		if !errors.Is(err, ErrPointVersionConflict) {
+			break
+		}
+		uc.log.WithContext(ctx).Warnf("Point version conflict while adjusting user: %d, attempt: %d", userID, attempt)
+	}
+	uc.metrics.recordOperation(ctx, PointOperationAdjust, err)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInsufficientPoints):
+			uc.log.WithContext(ctx).Warnf("Insufficient points to adjust for user: %d, delta: %d", userID, delta)
+			return nil, error_reason.ErrorUserInvalidRequest("点数余额不足").WithCause(ErrInsufficientPoints)
+		case error_reason.IsUserInvalidRequest(err):
+			return nil, err
+		case errors.Is(err, ErrPointVersionConflict), errors.Is(err, ErrTxRetriesExhausted):
+			uc.log.WithContext(ctx).Errorf("Failed to adjust points for user: %d, error_reason: %v", userID, err)
+			return nil, error_reason.ErrorUserServiceUnavailable("系统繁忙，调整点数失败，请稍后重试")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to adjust points for user: %d, error_reason: %v", userID, err)
+		return nil, error_reason.ErrorUserDatabaseError("调整点数失败")
+	}
+	uc.metrics.recordAmount(ctx, PointOperationAdjust, txn.Amount)
+
+	uc.log.WithContext(ctx).Infof("Adjusted points for user: %d, delta: %d, operator: %d, version: %d", userID, delta, operatorID, point.Version)
+	return point, nil
+}
+
+// ArchivingEnabled 是否配置了流水保留期，未配置时不归档流水
+func (uc *PointUsecase) ArchivingEnabled() bool {
+	return uc.config.TransactionRetention > 0
+}
+
+// ArchiveInterval 返回定时归档任务的执行间隔
+func (uc *PointUsecase) ArchiveInterval() time.Duration {
+	return uc.config.archiveInterval()
+}
+
+// ArchiveExpiredTransactions 将创建时间早于保留期的流水移入归档表，返回归档的条数
+// 流水按批次移动，每批的复制和删除在同一个事务中完成；某一批失败时之前的批次已生效
+// 只移动流水记录，user_point 中的余额和累计消耗不受影响；归档后的流水不再计入流水查询和每日点数变化
+func (uc *PointUsecase) ArchiveExpiredTransactions(ctx context.Context) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointUsecase.ArchiveExpiredTransactions")
+	defer span.End()
+	defer uc.slowOp.Track(ctx, "ArchiveExpiredTransactions")()
+
+	if !uc.ArchivingEnabled() {
+		return 0, error_reason.ErrorUserInvalidRequest("未配置点数流水保留期")
+	}
+	// 截止时间在开始时确定，避免归档过程中不断有新流水到期导致任务无法结束
+	before := time.Now().Add(-uc.config.TransactionRetention)
+	batchSize := uc.config.archiveBatchSize()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":  "archive_expired_transactions",
+		"before":     before,
+		"batch_size": batchSize,
+	})
+
+	var archived int64
+	for {
+		// 请求被取消或服务关闭后不再开始新的批次
+		if err := ctx.Err(); err != nil {
+			uc.log.WithContext(ctx).Warnf("Archiving point transactions aborted, archived: %d, error_reason: %v", archived, err)
+			return archived, err
+		}
+
+		var moved int64
+		err := uc.tx.InTxWithRetry(ctx, func(ctx context.Context) error {
+			var err error
+			moved, err = uc.txnRepo.ArchiveBefore(ctx, before, batchSize)
+			return err
+		})
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to archive point transactions created before: %v, archived: %d, error_reason: %v", before, archived, err)
+			if errors.Is(err, ErrTxRetriesExhausted) {
+				return archived, error_reason.ErrorUserServiceUnavailable("系统繁忙，归档点数流水失败，请稍后重试")
+			}
+			return archived, error_reason.ErrorUserDatabaseError("归档点数流水失败")
+		}
+		archived += moved
+		if moved < int64(batchSize) {
+			break
+		}
+	}
+
+	uc.log.WithContext(ctx).Infof("Archived %d point transactions created before: %v", archived, before)
+	return archived, nil
+}
@@ -0,0 +1,90 @@
+package biz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	// pointMeterName 点数指标所属的 meter
+	pointMeterName = "user/internal/biz"
+	// pointOperationsMetric 按操作（operation 标签）和结果（outcome 标签）统计的点数操作次数指标
+	pointOperationsMetric = "point_operations_total"
+	// pointTransactionAmountMetric 成功写入的点数流水金额分布指标
+	pointTransactionAmountMetric = "point_transaction_amount"
+)
+
+// 点数操作名称，作为点数指标的 operation 标签
+const (
+	// PointOperationConsume 消耗点数
+	PointOperationConsume = "consume"
+	// PointOperationRecharge 充值点数
+	PointOperationRecharge = "recharge"
+	// PointOperationAdjust 管理员调整点数
+	PointOperationAdjust = "adjust"
+)
+
+// 点数操作结果，作为点数操作次数指标的 outcome 标签
+const (
+	// PointOutcomeSuccess 操作成功
+	PointOutcomeSuccess = "success"
+	// PointOutcomeInsufficientFunds 余额不足
+	PointOutcomeInsufficientFunds = "insufficient_funds"
+	// PointOutcomeError 其他错误
+	PointOutcomeError = "error"
+)
+
+// pointMetrics 点数操作的次数和流水金额指标，通过全局 MeterProvider 上报，未配置导出器时为空操作
+type pointMetrics struct {
+	operations metric.Int64Counter
+	amounts    metric.Int64Histogram
+}
+
+// newPointMetrics 创建点数指标，meter 为空时使用全局 MeterProvider
+func newPointMetrics(meter metric.Meter, logger log.Logger) *pointMetrics {
+	if meter == nil {
+		meter = otel.Meter(pointMeterName)
+	}
+	helper := log.NewHelper(logger)
+
+	// 指标创建失败不影响点数操作本身
+	operations, err := meter.Int64Counter(pointOperationsMetric,
+		metric.WithDescription("Number of point operations by operation and outcome"))
+	if err != nil {
+		helper.Errorf("Failed to create point operations counter, error_reason: %v", err)
+		operations = noop.Int64Counter{}
+	}
+	amounts, err := meter.Int64Histogram(pointTransactionAmountMetric,
+		metric.WithDescription("Amount of points per successful transaction by operation"))
+	if err != nil {
+		helper.Errorf("Failed to create point transaction amount histogram, error_reason: %v", err)
+		amounts = noop.Int64Histogram{}
+	}
+	return &pointMetrics{operations: operations, amounts: amounts}
+}
+
+// recordOperation 按 err 归类结果并累加一次操作次数
+func (m *pointMetrics) recordOperation(ctx context.Context, operation string, err error) {
+	outcome := PointOutcomeSuccess
+	switch {
+	case errors.Is(err, ErrInsufficientPoints):
+		outcome = PointOutcomeInsufficientFunds
+	case err != nil:
+		outcome = PointOutcomeError
+	}
+	m.operations.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("operation", operation),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// recordAmount 记录一条成功写入的流水金额
+func (m *pointMetrics) recordAmount(ctx context.Context, operation string, amount uint32) {
+	m.amounts.Record(ctx, int64(amount), metric.WithAttributes(attribute.String("operation", operation)))
+}
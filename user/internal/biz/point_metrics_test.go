@@ -0,0 +1,154 @@
+package biz
+
+import (
+	"context"
+	"testing"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// operationCounter 按 operation/outcome 标签记录累加值的测试计数器
+type operationCounter struct {
+	noop.Int64Counter
+	counts map[string]int64
+}
+
+func (c *operationCounter) Add(_ context.Context, incr int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	operation, _ := attrs.Value(attribute.Key("operation"))
+	outcome, _ := attrs.Value(attribute.Key("outcome"))
+	c.counts[operation.AsString()+"/"+outcome.AsString()] += incr
+}
+
+// amountHistogram 记录每次写入金额的测试直方图
+type amountHistogram struct {
+	noop.Int64Histogram
+	values []int64
+}
+
+func (h *amountHistogram) Record(_ context.Context, value int64, _ ...metric.RecordOption) {
+	h.values = append(h.values, value)
+}
+
+// TestPointUsecase_Metrics 测试余额不足的消耗和调整累加 insufficient_funds 计数，充值和调整成功累加 success 计数并记录每条流水的金额，失败累加 error 计数且不记录金额
+func TestPointUsecase_Metrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository)
+		run        func(uc *PointUsecase) error
+		wantErr    func(error) bool
+		wantCounts map[string]int64
+		wantAmount []int64
+	}{
+		{
+			name: "消耗时余额不足",
+			setup: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("Consume", mock.Anything, int64(1), uint32(50)).Return((*UserPoint)(nil), ErrInsufficientPoints).Once()
+			},
+			run: func(uc *PointUsecase) error {
+				_, err := uc.ConsumePoints(context.Background(), 1, 50, nil, "生成绘本")
+				return err
+			},
+			wantErr:    error_reason.IsUserInvalidRequest,
+			wantCounts: map[string]int64{"consume/insufficient_funds": 1},
+		},
+		{
+			name: "消耗成功",
+			setup: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("Consume", mock.Anything, int64(1), uint32(50)).Return(&UserPoint{UserID: 1, CurrentPoints: 50}, nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == 1 && txns[0].Type == TransactionTypeConsume && txns[0].Amount == 50
+				})).Return(nil).Once()
+			},
+			run: func(uc *PointUsecase) error {
+				_, err := uc.ConsumePoints(context.Background(), 1, 50, nil, "生成绘本")
+				return err
+			},
+			wantCounts: map[string]int64{"consume/success": 1},
+			wantAmount: []int64{50},
+		},
+		{
+			name: "充值成功",
+			setup: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("AddPointsBatch", mock.Anything, mock.Anything).Return(nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			run: func(uc *PointUsecase) error {
+				_, err := uc.BulkRecharge(context.Background(), []int64{1, 2}, 100, "活动赠送")
+				return err
+			},
+			wantCounts: map[string]int64{"recharge/success": 1},
+			wantAmount: []int64{100, 100},
+		},
+		{
+			name: "管理员扣减点数成功",
+			setup: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return(&UserPoint{UserID: 1, CurrentPoints: 100}, nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			run: func(uc *PointUsecase) error {
+				_, err := uc.AdjustPoints(context.Background(), 99, 1, -30, "重复扣费退回")
+				return err
+			},
+			wantCounts: map[string]int64{"adjust/success": 1},
+			wantAmount: []int64{30},
+		},
+		{
+			name: "管理员扣减时余额不足",
+			setup: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return(&UserPoint{UserID: 1, CurrentPoints: 10}, nil).Once()
+			},
+			run: func(uc *PointUsecase) error {
+				_, err := uc.AdjustPoints(context.Background(), 99, 1, -30, "重复扣费退回")
+				return err
+			},
+			wantErr:    error_reason.IsUserInvalidRequest,
+			wantCounts: map[string]int64{"adjust/insufficient_funds": 1},
+		},
+		{
+			name: "充值失败",
+			setup: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("AddPointsBatch", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+			},
+			run: func(uc *PointUsecase) error {
+				_, err := uc.BulkRecharge(context.Background(), []int64{1, 2}, 100, "活动赠送")
+				return err
+			},
+			wantErr:    error_reason.IsUserDatabaseError,
+			wantCounts: map[string]int64{"recharge/error": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := new(MockUserPointRepository)
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setup(pointRepo, txnRepo)
+
+			uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+			counter := &operationCounter{counts: map[string]int64{}}
+			histogram := &amountHistogram{}
+			uc.metrics = &pointMetrics{operations: counter, amounts: histogram}
+
+			err := tt.run(uc)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err))
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCounts, counter.counts)
+			assert.Equal(t, tt.wantAmount, histogram.values)
+			pointRepo.AssertExpectations(t)
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,1010 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+
+	error_reason "user/api/error_reason"
+)
+
+// 模拟 PointTransactionRepository
+type MockPointTransactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPointTransactionRepository) GetByUserID(ctx context.Context, userID int64, page, pageSize int) ([]*PointTransaction, int64, error) {
+	args := m.Called(ctx, userID, page, pageSize)
+	return args.Get(0).([]*PointTransaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPointTransactionRepository) CreateBatch(ctx context.Context, txns []*PointTransaction) error {
+	args := m.Called(ctx, txns)
+	return args.Error(0)
+}
+
+func (m *MockPointTransactionRepository) ReassignUser(ctx context.Context, fromUserID, toUserID int64) (int64, error) {
+	args := m.Called(ctx, fromUserID, toUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPointTransactionRepository) GetByRelatedBookID(ctx context.Context, bookID int64, page, pageSize int) ([]*PointTransaction, int64, error) {
+	args := m.Called(ctx, bookID, page, pageSize)
+	return args.Get(0).([]*PointTransaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPointTransactionRepository) GetByMetadata(ctx context.Context, key string, value interface{}, page, pageSize int) ([]*PointTransaction, int64, error) {
+	args := m.Called(ctx, key, value, page, pageSize)
+	return args.Get(0).([]*PointTransaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPointTransactionRepository) DailyFlow(ctx context.Context, userID int64, from, to time.Time) ([]DailyFlow, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).([]DailyFlow), args.Error(1)
+}
+
+func (m *MockPointTransactionRepository) Archive(ctx context.Context, userID int64) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPointTransactionRepository) ArchiveBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// 模拟 UserPointRepository
+type MockUserPointRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserPointRepository) AddPointsBatch(ctx context.Context, points []*UserPoint) error {
+	args := m.Called(ctx, points)
+	return args.Error(0)
+}
+
+func (m *MockUserPointRepository) MergeInto(ctx context.Context, fromUserID, toUserID int64) error {
+	args := m.Called(ctx, fromUserID, toUserID)
+	return args.Error(0)
+}
+
+func (m *MockUserPointRepository) GetOrCreate(ctx context.Context, userID int64) (*UserPoint, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*UserPoint), args.Error(1)
+}
+
+// UpdateWithVersion 将 Return 的记录视为读取到的当前记录，复制后交给 apply 修改，成功时版本号加一
+func (m *MockUserPointRepository) UpdateWithVersion(ctx context.Context, userID int64, apply func(point *UserPoint) error) (*UserPoint, error) {
+	args := m.Called(ctx, userID, apply)
+	if err := args.Error(1); err != nil {
+		return nil, err
+	}
+	point := *args.Get(0).(*UserPoint)
+	if err := apply(&point); err != nil {
+		return nil, err
+	}
+	point.Version++
+	return &point, nil
+}
+
+func (m *MockUserPointRepository) Consume(ctx context.Context, userID int64, amount uint32) (*UserPoint, error) {
+	args := m.Called(ctx, userID, amount)
+	return args.Get(0).(*UserPoint), args.Error(1)
+}
+
+func (m *MockUserPointRepository) ZeroOut(ctx context.Context, userID int64) (uint32, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(uint32), args.Error(1)
+}
+
+func (m *MockUserPointRepository) Archive(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// 模拟 Transaction，直接执行回调
+type MockTransaction struct{}
+
+func (m *MockTransaction) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *MockTransaction) InTxWithRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// TestPointUsecase_ListTransactions 测试分页获取点数流水
+func TestPointUsecase_ListTransactions(t *testing.T) {
+	tests := []struct {
+		name         string
+		page         int
+		pageSize     int
+		setupMocks   func(*MockPointTransactionRepository)
+		wantPageInfo PageInfo
+		wantErr      bool
+		wantInvalid  bool
+	}{
+		{
+			name:     "返回分页元信息",
+			page:     1,
+			pageSize: 2,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("GetByUserID", mock.Anything, int64(1), 1, 2).
+					Return([]*PointTransaction{{ID: 1}, {ID: 2}}, int64(5), nil)
+			},
+			wantPageInfo: PageInfo{Page: 1, PageSize: 2, Total: 5, TotalPages: 3, HasNext: true, HasPrev: false},
+		},
+		{
+			name:     "未传分页参数使用默认值",
+			page:     0,
+			pageSize: 0,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("GetByUserID", mock.Anything, int64(1), 1, defaultPageSize).
+					Return([]*PointTransaction{}, int64(0), nil)
+			},
+			wantPageInfo: PageInfo{Page: 1, PageSize: defaultPageSize},
+		},
+		{
+			name:     "每页条数超过上限",
+			page:     1,
+			pageSize: 1000,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("GetByUserID", mock.Anything, int64(1), 1, defaultMaxPageSize).
+					Return([]*PointTransaction{}, int64(0), nil)
+			},
+			wantPageInfo: PageInfo{Page: 1, PageSize: defaultMaxPageSize},
+		},
+		{
+			name:        "负数页码被拒绝",
+			page:        -1,
+			pageSize:    10,
+			setupMocks:  func(txnRepo *MockPointTransactionRepository) {},
+			wantInvalid: true,
+		},
+		{
+			name:     "数据库错误",
+			page:     1,
+			pageSize: 10,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("GetByUserID", mock.Anything, int64(1), 1, 10).
+					Return(([]*PointTransaction)(nil), int64(0), errors.New("database error_reason"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(txnRepo)
+
+			uc := NewPointUsecase(new(MockUserPointRepository), txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+			_, pageInfo, err := uc.ListTransactions(context.Background(), 1, tt.page, tt.pageSize)
+
+			if tt.wantInvalid {
+				assert.True(t, error_reason.IsUserInvalidRequest(err))
+			} else if tt.wantErr {
+				assert.True(t, error_reason.IsUserDatabaseError(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantPageInfo, pageInfo)
+			}
+
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_GetPointBalance 测试获取用户点数余额
+func TestPointUsecase_GetPointBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		point   *UserPoint
+		repoErr error
+		wantErr bool
+	}{
+		{
+			name:  "返回点数记录",
+			point: &UserPoint{UserID: 1, CurrentPoints: 300},
+		},
+		{
+			name:    "数据库错误",
+			point:   (*UserPoint)(nil),
+			repoErr: errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := new(MockUserPointRepository)
+			pointRepo.On("GetOrCreate", mock.Anything, int64(1)).Return(tt.point, tt.repoErr)
+
+			uc := NewPointUsecase(pointRepo, new(MockPointTransactionRepository), &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+			point, err := uc.GetPointBalance(context.Background(), 1)
+
+			if tt.wantErr {
+				assert.True(t, error_reason.IsUserDatabaseError(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.point, point)
+			}
+			pointRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_BulkRecharge_Canceled 测试上下文已取消时不再处理任何批次
+func TestPointUsecase_BulkRecharge_Canceled(t *testing.T) {
+	pointRepo := new(MockUserPointRepository)
+	txnRepo := new(MockPointTransactionRepository)
+	uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	credited, err := uc.BulkRecharge(ctx, []int64{1, 2, 3}, 100, "活动赠送")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, credited)
+	pointRepo.AssertNotCalled(t, "AddPointsBatch", mock.Anything, mock.Anything)
+	txnRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+// lockContendedTransaction 模拟锁冲突重试次数用尽的事务
+type lockContendedTransaction struct {
+	MockTransaction
+}
+
+func (m *lockContendedTransaction) InTxWithRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fmt.Errorf("%w after 3 attempts", ErrTxRetriesExhausted)
+}
+
+// TestPointUsecase_BulkRecharge_RetriesExhausted 测试锁冲突重试用尽时返回服务繁忙
+func TestPointUsecase_BulkRecharge_RetriesExhausted(t *testing.T) {
+	uc := NewPointUsecase(new(MockUserPointRepository), new(MockPointTransactionRepository), &lockContendedTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+	credited, err := uc.BulkRecharge(context.Background(), []int64{1, 2, 3}, 100, "活动赠送")
+
+	assert.Equal(t, 0, credited)
+	assert.True(t, error_reason.IsUserServiceUnavailable(err))
+}
+
+// TestPointUsecase_BulkRecharge 测试批量充值
+func TestPointUsecase_BulkRecharge(t *testing.T) {
+	// 生成超过一个批次的用户ID
+	manyUserIDs := make([]int64, bulkRechargeBatchSize+10)
+	for i := range manyUserIDs {
+		manyUserIDs[i] = int64(i + 1)
+	}
+
+	tests := []struct {
+		name         string
+		userIDs      []int64
+		amount       uint32
+		setupMocks   func(*MockUserPointRepository, *MockPointTransactionRepository)
+		wantCredited int
+		wantErr      bool
+	}{
+		{
+			name:    "重复用户只充值一次",
+			userIDs: []int64{1, 2, 2, 3},
+			amount:  100,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("AddPointsBatch", mock.Anything, mock.MatchedBy(func(points []*UserPoint) bool {
+					return len(points) == 3 && points[0].CurrentPoints == 100
+				})).Return(nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == 3 && txns[2].UserID == 3 && txns[2].Type == TransactionTypeRecharge
+				})).Return(nil).Once()
+			},
+			wantCredited: 3,
+		},
+		{
+			name:    "超过批次大小时分批处理",
+			userIDs: manyUserIDs,
+			amount:  10,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("AddPointsBatch", mock.Anything, mock.Anything).Return(nil).Twice()
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == bulkRechargeBatchSize
+				})).Return(nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == 10
+				})).Return(nil).Once()
+			},
+			wantCredited: len(manyUserIDs),
+		},
+		{
+			name:    "第二批失败时返回已充值数量",
+			userIDs: manyUserIDs,
+			amount:  10,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("AddPointsBatch", mock.Anything, mock.Anything).Return(nil).Twice()
+				txnRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(errors.New("database error_reason")).Once()
+			},
+			wantCredited: bulkRechargeBatchSize,
+			wantErr:      true,
+		},
+		{
+			name:         "充值点数为0",
+			userIDs:      []int64{1},
+			amount:       0,
+			setupMocks:   func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {},
+			wantCredited: 0,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := new(MockUserPointRepository)
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(pointRepo, txnRepo)
+
+			uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+			credited, err := uc.BulkRecharge(context.Background(), tt.userIDs, tt.amount, "活动赠送")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCredited, credited)
+
+			pointRepo.AssertExpectations(t)
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_BulkRecharge_DescriptionLength 测试流水描述超过配置的最大字符数时在写库前拒绝
+func TestPointUsecase_BulkRecharge_DescriptionLength(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxLength   int
+		description string
+		wantErr     bool
+	}{
+		{
+			name:        "描述长度在上限内",
+			maxLength:   4,
+			description: "活动赠送",
+		},
+		{
+			name:        "描述超过配置的上限",
+			maxLength:   4,
+			description: "春季活动赠送",
+			wantErr:     true,
+		},
+		{
+			name:        "未配置时按列长度校验",
+			description: strings.Repeat("a", defaultMaxDescriptionLength+1),
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := new(MockUserPointRepository)
+			txnRepo := new(MockPointTransactionRepository)
+			if !tt.wantErr {
+				pointRepo.On("AddPointsBatch", mock.Anything, mock.Anything).Return(nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == 1 && txns[0].Description == tt.description
+				})).Return(nil).Once()
+			}
+
+			uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{MaxDescriptionLength: tt.maxLength}, newTestSlowOperationLogger(), getTestLogger())
+			credited, err := uc.BulkRecharge(context.Background(), []int64{1}, 100, tt.description)
+
+			if tt.wantErr {
+				assert.True(t, error_reason.IsUserInvalidRequest(err), "unexpected error: %v", err)
+				assert.Equal(t, 0, credited)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, credited)
+			}
+			pointRepo.AssertExpectations(t)
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_ListTransactionsByBook 测试按绘本分页获取点数流水
+func TestPointUsecase_ListTransactionsByBook(t *testing.T) {
+	bookID := int64(42)
+	txnRepo := new(MockPointTransactionRepository)
+	txnRepo.On("GetByRelatedBookID", mock.Anything, bookID, 2, 10).
+		Return([]*PointTransaction{{ID: 11, RelatedBookID: &bookID}}, int64(11), nil)
+
+	uc := NewPointUsecase(new(MockUserPointRepository), txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+	txns, pageInfo, err := uc.ListTransactionsByBook(context.Background(), bookID, 2, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, txns, 1)
+	assert.Equal(t, PageInfo{Page: 2, PageSize: 10, Total: 11, TotalPages: 2, HasNext: false, HasPrev: true}, pageInfo)
+	txnRepo.AssertExpectations(t)
+}
+
+// TestPointUsecase_ListTransactionsBySource 测试按元数据来源查询流水，来源为空时不查询
+func TestPointUsecase_ListTransactionsBySource(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		setupMocks   func(*MockPointTransactionRepository)
+		wantLen      int
+		wantPageInfo PageInfo
+		wantErr      func(error) bool
+	}{
+		{
+			name:   "按来源查询",
+			source: TransactionSourceAccountDeletion,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("GetByMetadata", mock.Anything, TransactionMetadataSource, TransactionSourceAccountDeletion, 2, 10).
+					Return([]*PointTransaction{{ID: 11}}, int64(11), nil)
+			},
+			wantLen:      1,
+			wantPageInfo: PageInfo{Page: 2, PageSize: 10, Total: 11, TotalPages: 2, HasNext: false, HasPrev: true},
+		},
+		{
+			name:       "来源为空",
+			source:     "",
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {},
+			wantErr:    error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:   "查询失败",
+			source: TransactionSourceAccountDeletion,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("GetByMetadata", mock.Anything, TransactionMetadataSource, TransactionSourceAccountDeletion, 2, 10).
+					Return([]*PointTransaction(nil), int64(0), assert.AnError)
+			},
+			wantErr: error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(txnRepo)
+
+			uc := NewPointUsecase(new(MockUserPointRepository), txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+			txns, pageInfo, err := uc.ListTransactionsBySource(context.Background(), tt.source, 2, 10)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, txns, tt.wantLen)
+				assert.Equal(t, tt.wantPageInfo, pageInfo)
+			}
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_ExportTransactions 测试导出时逐页读取直到取完全部流水
+func TestPointUsecase_ExportTransactions(t *testing.T) {
+	firstPage := make([]*PointTransaction, exportPageSize)
+	for i := range firstPage {
+		firstPage[i] = &PointTransaction{ID: int64(i + 2)}
+	}
+	total := int64(exportPageSize + 1)
+
+	txnRepo := new(MockPointTransactionRepository)
+	txnRepo.On("GetByUserID", mock.Anything, int64(1), 1, exportPageSize).Return(firstPage, total, nil)
+	txnRepo.On("GetByUserID", mock.Anything, int64(1), 2, exportPageSize).Return([]*PointTransaction{{ID: 1}}, total, nil)
+
+	uc := NewPointUsecase(new(MockUserPointRepository), txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+	var pages []int
+	err := uc.ExportTransactions(context.Background(), 1, func(txns []*PointTransaction) error {
+		pages = append(pages, len(txns))
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{exportPageSize, 1}, pages)
+	txnRepo.AssertExpectations(t)
+}
+
+// TestPointUsecase_DailyFlow 测试每日点数变化按日期补齐
+func TestPointUsecase_DailyFlow(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		from        time.Time
+		to          time.Time
+		setupMocks  func(*MockPointTransactionRepository)
+		wantChanges []int64
+		wantErr     error
+	}{
+		{
+			name: "缺失的日期补0",
+			from: day(1).Add(15 * time.Hour), // 非零点也按整天处理
+			to:   day(5),
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("DailyFlow", mock.Anything, int64(1), day(1), day(6)).Return([]DailyFlow{
+					{Date: day(2), NetChange: 100},
+					{Date: day(4), NetChange: -30},
+				}, nil)
+			},
+			wantChanges: []int64{0, 100, 0, -30, 0},
+		},
+		{
+			name: "整个范围都没有流水",
+			from: day(1),
+			to:   day(3),
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("DailyFlow", mock.Anything, int64(1), day(1), day(4)).Return([]DailyFlow{}, nil)
+			},
+			wantChanges: []int64{0, 0, 0},
+		},
+		{
+			name:       "结束日期早于开始日期",
+			from:       day(5),
+			to:         day(1),
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {},
+			wantErr:    error_reason.ErrorUserInvalidRequest("结束日期不能早于开始日期"),
+		},
+		{
+			name:       "查询范围过大",
+			from:       day(1),
+			to:         day(1).AddDate(1, 1, 0),
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {},
+			wantErr:    error_reason.ErrorUserInvalidRequest("查询范围不能超过366天"),
+		},
+		{
+			name: "查询失败",
+			from: day(1),
+			to:   day(2),
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("DailyFlow", mock.Anything, int64(1), day(1), day(3)).Return([]DailyFlow(nil), errors.New("db down"))
+			},
+			wantErr: error_reason.ErrorUserDatabaseError("查询点数变化失败"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(txnRepo)
+			uc := NewPointUsecase(new(MockUserPointRepository), txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+			flows, err := uc.DailyFlow(context.Background(), 1, tt.from, tt.to)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				assert.Nil(t, flows)
+			} else {
+				assert.NoError(t, err)
+				changes := make([]int64, 0, len(flows))
+				for i, flow := range flows {
+					assert.True(t, day(1).AddDate(0, 0, i).Equal(flow.Date), "日期应连续")
+					changes = append(changes, flow.NetChange)
+				}
+				assert.Equal(t, tt.wantChanges, changes)
+			}
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_AdjustPoints 测试管理员按乐观锁调整余额并在同一事务中写入带操作人的流水，版本冲突时开启新事务重试，余额不足、重试耗尽时不写流水
+func TestPointUsecase_AdjustPoints(t *testing.T) {
+	stored := &UserPoint{UserID: 1, CurrentPoints: 50, TotalConsumed: 20, Version: 3}
+
+	tests := []struct {
+		name       string
+		delta      int32
+		setupMocks func(*MockUserPointRepository, *MockPointTransactionRepository)
+		wantPoint  *UserPoint
+		wantErr    func(error) bool
+		// wantRecorded 返回错误时是否已尝试写入流水
+		wantRecorded bool
+	}{
+		{
+			name:  "增加点数",
+			delta: 30,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("GetOrCreate", mock.Anything, int64(1)).Return(stored, nil)
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return(stored, nil)
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == 1 && txns[0].Type == TransactionTypeRecharge && txns[0].Amount == 30 &&
+						string(txns[0].Metadata) == `{"operator_id":99,"source":"admin_adjustment"}`
+				})).Return(nil)
+			},
+			wantPoint: &UserPoint{UserID: 1, CurrentPoints: 80, TotalConsumed: 20, Version: 4},
+		},
+		{
+			name:  "扣减点数不改变累计消耗",
+			delta: -50,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return(stored, nil)
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == 1 && txns[0].Type == TransactionTypeConsume && txns[0].Amount == 50
+				})).Return(nil)
+			},
+			wantPoint: &UserPoint{UserID: 1, CurrentPoints: 0, TotalConsumed: 20, Version: 4},
+		},
+		{
+			name:  "余额不足",
+			delta: -51,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return(stored, nil)
+			},
+			wantErr: func(err error) bool {
+				return error_reason.IsUserInvalidRequest(err) && errors.Is(err, ErrInsufficientPoints)
+			},
+		},
+		{
+			name:  "尚无点数记录时扣减视为余额不足",
+			delta: -1,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return((*UserPoint)(nil), gorm.ErrRecordNotFound)
+			},
+			wantErr: func(err error) bool {
+				return errors.Is(err, ErrInsufficientPoints)
+			},
+		},
+		{
+			name:  "版本冲突后开启新事务重试",
+			delta: 10,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("GetOrCreate", mock.Anything, int64(1)).Return(stored, nil)
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return((*UserPoint)(nil), ErrPointVersionConflict).Once()
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return(stored, nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantPoint: &UserPoint{UserID: 1, CurrentPoints: 60, TotalConsumed: 20, Version: 4},
+		},
+		{
+			name:  "版本冲突重试耗尽",
+			delta: 10,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("GetOrCreate", mock.Anything, int64(1)).Return(stored, nil)
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return((*UserPoint)(nil), ErrPointVersionConflict).Times(adjustPointsMaxAttempts)
+			},
+			wantErr: error_reason.IsUserServiceUnavailable,
+		},
+		{
+			name:  "写入流水失败时余额随事务回滚",
+			delta: 10,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("GetOrCreate", mock.Anything, int64(1)).Return(stored, nil)
+				pointRepo.On("UpdateWithVersion", mock.Anything, int64(1), mock.Anything).Return(stored, nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+			},
+			wantErr:      error_reason.IsUserDatabaseError,
+			wantRecorded: true,
+		},
+		{
+			name:       "调整量为0",
+			delta:      0,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {},
+			wantErr:    error_reason.IsUserInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := new(MockUserPointRepository)
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(pointRepo, txnRepo)
+
+			uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+			point, err := uc.AdjustPoints(context.Background(), 99, 1, tt.delta, "人工补偿")
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				if !tt.wantRecorded {
+					txnRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+				}
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantPoint, point)
+			}
+			assert.Equal(t, uint32(50), stored.CurrentPoints, "stored record must not be modified")
+			pointRepo.AssertExpectations(t)
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_ArchiveExpiredTransactions 测试按批次归档超过保留期的流水，不修改点数余额
+func TestPointUsecase_ArchiveExpiredTransactions(t *testing.T) {
+	config := PointConfig{TransactionRetention: 90 * 24 * time.Hour, ArchiveBatchSize: 2}
+
+	tests := []struct {
+		name         string
+		config       PointConfig
+		setupMocks   func(*MockPointTransactionRepository)
+		wantArchived int64
+		wantErr      func(error) bool
+	}{
+		{
+			name:   "批次移满时继续，直到某批不足批次大小",
+			config: config,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("ArchiveBefore", mock.Anything, mock.Anything, 2).Return(int64(2), nil).Twice()
+				txnRepo.On("ArchiveBefore", mock.Anything, mock.Anything, 2).Return(int64(1), nil).Once()
+			},
+			wantArchived: 5,
+		},
+		{
+			name:   "没有超过保留期的流水",
+			config: config,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("ArchiveBefore", mock.Anything, mock.Anything, 2).Return(int64(0), nil).Once()
+			},
+		},
+		{
+			name:   "未配置批次大小时使用默认值",
+			config: PointConfig{TransactionRetention: time.Hour},
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("ArchiveBefore", mock.Anything, mock.Anything, defaultArchiveBatchSize).Return(int64(3), nil).Once()
+			},
+			wantArchived: 3,
+		},
+		{
+			name:   "第二批失败时返回已归档数量",
+			config: config,
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {
+				txnRepo.On("ArchiveBefore", mock.Anything, mock.Anything, 2).Return(int64(2), nil).Once()
+				txnRepo.On("ArchiveBefore", mock.Anything, mock.Anything, 2).Return(int64(0), errors.New("connection refused")).Once()
+			},
+			wantArchived: 2,
+			wantErr:      error_reason.IsUserDatabaseError,
+		},
+		{
+			name:       "未配置保留期",
+			setupMocks: func(txnRepo *MockPointTransactionRepository) {},
+			wantErr:    error_reason.IsUserInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := new(MockUserPointRepository)
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(txnRepo)
+			uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, tt.config, newTestSlowOperationLogger(), getTestLogger())
+
+			start := time.Now()
+			archived, err := uc.ArchiveExpiredTransactions(context.Background())
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantArchived, archived)
+			txnRepo.AssertExpectations(t)
+			// 每批使用相同的截止时间
+			for _, call := range txnRepo.Calls {
+				before := call.Arguments.Get(1).(time.Time)
+				assert.WithinDuration(t, start.Add(-tt.config.TransactionRetention), before, time.Second)
+				assert.Equal(t, txnRepo.Calls[0].Arguments.Get(1), before)
+			}
+			// 归档只移动流水，不触碰点数余额
+			assert.Empty(t, pointRepo.Calls)
+		})
+	}
+}
+
+// TestPointUsecase_ConsumePoints 测试消耗点数时扣减余额并写入关联绘本的消耗流水，余额不足时不写流水
+func TestPointUsecase_ConsumePoints(t *testing.T) {
+	bookID := int64(42)
+
+	tests := []struct {
+		name        string
+		amount      uint32
+		setupMocks  func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository)
+		wantBalance uint32
+		wantErr     func(error) bool
+	}{
+		{
+			name:   "余额充足",
+			amount: 30,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("Consume", mock.Anything, int64(1), uint32(30)).
+					Return(&UserPoint{UserID: 1, CurrentPoints: 70, TotalConsumed: 30}, nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(txns []*PointTransaction) bool {
+					return len(txns) == 1 && txns[0].UserID == 1 && txns[0].Type == TransactionTypeConsume &&
+						txns[0].Amount == 30 && txns[0].RelatedBookID != nil && *txns[0].RelatedBookID == bookID
+				})).Return(nil).Once()
+			},
+			wantBalance: 70,
+		},
+		{
+			name:   "余额不足",
+			amount: 200,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("Consume", mock.Anything, int64(1), uint32(200)).
+					Return((*UserPoint)(nil), ErrInsufficientPoints).Once()
+			},
+			wantErr: func(err error) bool { return errors.Is(err, ErrInsufficientPoints) },
+		},
+		{
+			name:   "尚无点数记录视为余额不足",
+			amount: 30,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("Consume", mock.Anything, int64(1), uint32(30)).
+					Return((*UserPoint)(nil), gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: func(err error) bool { return errors.Is(err, ErrInsufficientPoints) },
+		},
+		{
+			name:   "写入流水失败",
+			amount: 30,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {
+				pointRepo.On("Consume", mock.Anything, int64(1), uint32(30)).
+					Return(&UserPoint{UserID: 1, CurrentPoints: 70, TotalConsumed: 30}, nil).Once()
+				txnRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(errors.New("database error")).Once()
+			},
+			wantErr: error_reason.IsUserDatabaseError,
+		},
+		{
+			name:       "消耗点数为0",
+			amount:     0,
+			setupMocks: func(pointRepo *MockUserPointRepository, txnRepo *MockPointTransactionRepository) {},
+			wantErr:    error_reason.IsUserInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := new(MockUserPointRepository)
+			txnRepo := new(MockPointTransactionRepository)
+			tt.setupMocks(pointRepo, txnRepo)
+			uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+			point, err := uc.ConsumePoints(context.Background(), 1, tt.amount, &bookID, "生成绘本")
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				assert.Nil(t, point)
+				if errors.Is(err, ErrInsufficientPoints) {
+					assert.True(t, error_reason.IsUserInvalidRequest(err))
+					txnRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+				}
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantBalance, point.CurrentPoints)
+			}
+			pointRepo.AssertExpectations(t)
+			txnRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPointUsecase_ConsumePoints_SpanAttributes 测试消耗成功后 span 上带有 int64 类型的扣减前后余额和流水ID
+func TestPointUsecase_ConsumePoints_SpanAttributes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	pointRepo := new(MockUserPointRepository)
+	txnRepo := new(MockPointTransactionRepository)
+	pointRepo.On("Consume", mock.Anything, int64(1), uint32(30)).
+		Return(&UserPoint{UserID: 1, CurrentPoints: 70, TotalConsumed: 30}, nil).Once()
+	txnRepo.On("CreateBatch", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			// 模拟数据库回填自增ID
+			args.Get(1).([]*PointTransaction)[0].ID = 9001
+		}).
+		Return(nil).Once()
+	uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+	_, err := uc.ConsumePoints(context.Background(), 1, 30, nil, "生成绘本")
+	require.NoError(t, err)
+
+	var attrs map[attribute.Key]attribute.Value
+	for _, span := range recorder.Ended() {
+		if span.Name() != "PointUsecase.ConsumePoints" {
+			continue
+		}
+		attrs = make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value
+		}
+	}
+	require.NotNil(t, attrs, "应记录 PointUsecase.ConsumePoints span")
+	for key, want := range map[attribute.Key]int64{
+		"balance_before": 100,
+		"balance_after":  70,
+		"transaction_id": 9001,
+	} {
+		value, ok := attrs[key]
+		require.True(t, ok, "缺少属性 %s", key)
+		assert.Equal(t, attribute.INT64, value.Type(), "属性 %s 应为 int64", key)
+		assert.Equal(t, want, value.AsInt64(), "属性 %s", key)
+	}
+}
+
+// atomicPointRepository 以互斥锁模拟条件更新的点数仓库，余额不足时不扣减
+type atomicPointRepository struct {
+	UserPointRepository
+	mu    sync.Mutex
+	point UserPoint
+}
+
+func (r *atomicPointRepository) Consume(ctx context.Context, userID int64, amount uint32) (*UserPoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.point.CurrentPoints < amount {
+		return nil, ErrInsufficientPoints
+	}
+	r.point.CurrentPoints -= amount
+	r.point.TotalConsumed += amount
+	point := r.point
+	return &point, nil
+}
+
+// countingTransactionRepository 统计写入流水条数的流水仓库
+type countingTransactionRepository struct {
+	PointTransactionRepository
+	created atomic.Int64
+}
+
+func (r *countingTransactionRepository) CreateBatch(ctx context.Context, txns []*PointTransaction) error {
+	r.created.Add(int64(len(txns)))
+	return nil
+}
+
+// TestPointUsecase_ConsumePoints_Concurrent 测试并发消耗时成功次数不超过余额允许的次数，余额不会被扣成负数
+func TestPointUsecase_ConsumePoints_Concurrent(t *testing.T) {
+	const (
+		workers = 20
+		amount  = 30
+	)
+	pointRepo := &atomicPointRepository{point: UserPoint{UserID: 1, CurrentPoints: 100}}
+	txnRepo := &countingTransactionRepository{}
+	uc := NewPointUsecase(pointRepo, txnRepo, &MockTransaction{}, Pagination{}, PointConfig{}, newTestSlowOperationLogger(), getTestLogger())
+
+	var succeeded, insufficient atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.ConsumePoints(context.Background(), 1, amount, nil, "生成绘本")
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case errors.Is(err, ErrInsufficientPoints):
+				insufficient.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(3), succeeded.Load(), "100 点最多消耗 3 次 30 点")
+	assert.Equal(t, int64(workers-3), insufficient.Load())
+	assert.Equal(t, uint32(10), pointRepo.point.CurrentPoints)
+	assert.Equal(t, uint32(90), pointRepo.point.TotalConsumed)
+	assert.Equal(t, int64(3), txnRepo.created.Load(), "只有成功的消耗写入流水")
+}
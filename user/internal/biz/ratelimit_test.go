@@ -0,0 +1,144 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"gorm.io/gorm"
+)
+
+// fakeCounter 按 limiter 标签记录累加值的测试计数器
+type fakeCounter struct {
+	noop.Int64Counter
+	counts map[string]int64
+}
+
+func (c *fakeCounter) Add(_ context.Context, incr int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	limiter, _ := attrs.Value(attribute.Key("limiter"))
+	c.counts[limiter.AsString()] += incr
+}
+
+// fakeMeter 返回 fakeCounter 的测试 meter
+type fakeMeter struct {
+	noop.Meter
+	counter *fakeCounter
+}
+
+func (m *fakeMeter) Int64Counter(string, ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return m.counter, nil
+}
+
+// newTestRateLimiter 创建记录到 fakeCounter 的限流器
+func newTestRateLimiter(logger log.Logger) (*rateLimiter, *fakeCounter) {
+	counter := &fakeCounter{counts: map[string]int64{}}
+	return newRateLimiter(&fakeMeter{counter: counter}, logger), counter
+}
+
+// TestRateLimiter_Allow 测试限流拒绝时累加计数并记录日志，放行和检查出错时不计数
+func TestRateLimiter_Allow(t *testing.T) {
+	checkErr := errors.New("redis unavailable")
+
+	tests := []struct {
+		name      string
+		allowed   bool
+		checkErr  error
+		wantOK    bool
+		wantCount int64
+	}{
+		{
+			name:      "拒绝时累加计数",
+			allowed:   false,
+			wantOK:    false,
+			wantCount: 1,
+		},
+		{
+			name:      "放行时不计数",
+			allowed:   true,
+			wantOK:    true,
+			wantCount: 0,
+		},
+		{
+			name:      "检查出错时不计数",
+			checkErr:  checkErr,
+			wantOK:    false,
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			limiter, counter := newTestRateLimiter(log.NewStdLogger(&buf))
+
+			ok, err := limiter.Allow(context.Background(), LimiterCodeSend, "test@example.com", func(context.Context) (bool, error) {
+				return tt.allowed, tt.checkErr
+			})
+
+			assert.ErrorIs(t, err, tt.checkErr)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantCount, counter.counts[LimiterCodeSend])
+			if tt.wantCount > 0 {
+				assert.Contains(t, buf.String(), "WARN")
+				assert.Contains(t, buf.String(), "limiter=code_send")
+				assert.Contains(t, buf.String(), "key=test@example.com")
+			} else {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
+
+// TestUserUsecase_SendRegisterCode_RateLimitMetric 测试发送验证码被限流时按限流器名称计数
+func TestUserUsecase_SendRegisterCode_RateLimitMetric(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMocks  func(*MockCodeRepository)
+		wantLimiter string
+	}{
+		{
+			name: "冷却期内发送",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, "test@example.com", 60*time.Second).Return(false, nil)
+			},
+			wantLimiter: LimiterCodeSend,
+		},
+		{
+			name: "超过每日上限",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, "test@example.com", 60*time.Second).Return(true, nil)
+				codeRepo.On("CheckAndIncrDailySendLimit", mock.Anything, "test@example.com", 3, mock.Anything).Return(false, nil)
+			},
+			wantLimiter: LimiterCodeSendDaily,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+			userRepo.On("GetByEmailPublic", mock.Anything, "test@example.com").Return((*User)(nil), gorm.ErrRecordNotFound)
+			tt.setupMocks(codeRepo)
+
+			uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{DailySendLimit: 3}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+			limiter, counter := newTestRateLimiter(getTestLogger())
+			uc.limiter = limiter
+
+			err := uc.SendRegisterCode(context.Background(), "test@example.com", "")
+			require.Error(t, err)
+
+			assert.Equal(t, map[string]int64{tt.wantLimiter: 1}, counter.counts)
+			codeRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package biz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel/baggage"
+	"user/internal/pkg/tracing"
+)
+
+// RegistrationAttemptIDKey 注册尝试ID在 span 属性和 baggage 中的 key，
+// 在链路追踪后端按该属性查询即可串起同一次注册的发送验证码、校验验证码和创建用户
+const RegistrationAttemptIDKey = "registration.attempt_id"
+
+// registrationAttemptID 由存储的验证码哈希派生注册尝试ID
+// 发送验证码和注册是两个独立的请求，不共享 trace；注册时校验通过的哈希与发送时存储的哈希相同，
+// 因此无需额外存储即可在两个请求中得到相同的ID。哈希本身含密钥，再做一次 SHA-256 截断后不会泄露验证码
+func registrationAttemptID(codeHash string) string {
+	sum := sha256.Sum256([]byte("registration_attempt:" + codeHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// withRegistrationAttempt 将注册尝试ID写入当前 span 属性，并放入 baggage 随后续的跨服务调用传播
+func withRegistrationAttempt(ctx context.Context, attemptID string) context.Context {
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		RegistrationAttemptIDKey: attemptID,
+	})
+
+	member, err := baggage.NewMember(RegistrationAttemptIDKey, attemptID)
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
@@ -0,0 +1,96 @@
+package biz
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+// spanAttribute 返回指定名称的 span 上的属性值
+func spanAttribute(t *testing.T, spans []sdktrace.ReadOnlySpan, spanName, key string) string {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name() != spanName {
+			continue
+		}
+		for _, attr := range span.Attributes() {
+			if string(attr.Key) == key {
+				return attr.Value.AsString()
+			}
+		}
+	}
+	return ""
+}
+
+// TestRegistrationAttemptID_Propagation 测试发送验证码和注册两个请求的 span 携带相同的注册尝试ID
+func TestRegistrationAttemptID_Propagation(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	email := "funnel@example.com"
+	userRepo := new(MockUserRepository)
+	codeRepo := new(MockCodeRepository)
+	emailSender := new(MockEmailSender)
+	emailLogRepo := new(MockEmailLogRepository)
+
+	var storedHash, plainText string
+	userRepo.On("GetByEmailPublic", mock.Anything, email).Return((*User)(nil), gorm.ErrRecordNotFound)
+	codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, email, 60*time.Second).Return(true, nil)
+	codeRepo.On("StoreVerificationCode", mock.Anything, email, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			storedHash = args.String(2)
+		}).
+		Return(nil)
+	emailSender.On("Send", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			plainText = args.Get(1).(*EmailMessage).PlainText
+		}).
+		Return(nil)
+	emailLogRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	userRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, emailLogRepo, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+	// 1. 发送验证码，从邮件正文中取出验证码
+	require.NoError(t, uc.SendRegisterCode(context.Background(), email, ""))
+	match := regexp.MustCompile(`验证码是：(\d+)`).FindStringSubmatch(plainText)
+	require.Len(t, match, 2)
+
+	// 2. 使用收到的验证码注册，与发送验证码不在同一个 trace 中
+	codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeRegister, storedHash).Return(true, nil)
+	_, err := uc.Register(context.Background(), email, "password123", match[1], "漏斗用户", "")
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	sendAttemptID := spanAttribute(t, spans, "UserUsecase.SendRegisterCode", RegistrationAttemptIDKey)
+	registerAttemptID := spanAttribute(t, spans, "UserUsecase.Register", RegistrationAttemptIDKey)
+	assert.NotEmpty(t, sendAttemptID)
+	assert.Equal(t, sendAttemptID, registerAttemptID)
+	// 注册尝试ID不能暴露验证码哈希
+	assert.NotContains(t, storedHash, sendAttemptID)
+}
+
+// TestWithRegistrationAttempt_Baggage 测试注册尝试ID写入 baggage，供后续跨服务调用传播
+func TestWithRegistrationAttempt_Baggage(t *testing.T) {
+	ctx := withRegistrationAttempt(context.Background(), registrationAttemptID("hash"))
+
+	member := baggage.FromContext(ctx).Member(RegistrationAttemptIDKey)
+	assert.Equal(t, registrationAttemptID("hash"), member.Value())
+	assert.Len(t, member.Value(), 16)
+}
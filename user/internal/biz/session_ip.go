@@ -0,0 +1,49 @@
+package biz
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+// recordSessionIP 记录刷新令牌签发时的客户端 IP，供管理员按 IP 撤销会话
+// 无法获取客户端 IP（如进程内调用）时不记录；记录失败只影响按 IP 撤销，不影响本次登录或刷新
+func recordSessionIP(ctx context.Context, authRepo AuthRepository, logger *log.Helper, userID int64, refreshToken string, expiresAt time.Time) {
+	ip := ClientIPFromContext(ctx)
+	if ip == "" {
+		return
+	}
+	if err := authRepo.RecordSessionIP(ctx, userID, refreshToken, ip, expiresAt); err != nil {
+		logger.WithContext(ctx).Warnf("Failed to record session ip for user id: %d, error_reason: %v", userID, err)
+	}
+}
+
+// RevokeSessionsByIP 撤销所有用户中来源 IP 为 ip 的会话（刷新令牌），返回撤销的数量
+// 已签发的访问令牌在过期前仍然有效
+func (uc *UserUsecase) RevokeSessionsByIP(ctx context.Context, ip string) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.RevokeSessionsByIP")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "revoke_sessions_by_ip",
+		"ip":        ip,
+	})
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return 0, error_reason.ErrorUserInvalidRequest("IP 地址格式不正确")
+	}
+
+	revoked, err := uc.authRepo.RevokeSessionsByIP(ctx, parsed.String())
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to revoke sessions for ip: %s, error_reason: %v", ip, err)
+		return 0, error_reason.ErrorUserDatabaseError("撤销会话失败")
+	}
+
+	uc.log.WithContext(ctx).Infof("Revoked %d sessions for ip: %s", revoked, ip)
+	return revoked, nil
+}
@@ -0,0 +1,106 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserUsecase_RevokeSessionsByIP 测试按规范化后的 IP 撤销会话，非法 IP 不访问存储
+func TestUserUsecase_RevokeSessionsByIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		ip          string
+		setupMocks  func(*MockAuthRepository)
+		wantRevoked int
+		wantErr     func(error) bool
+	}{
+		{
+			name: "撤销匹配的会话",
+			ip:   "1.2.3.4",
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("RevokeSessionsByIP", mock.Anything, "1.2.3.4").Return(2, nil)
+			},
+			wantRevoked: 2,
+		},
+		{
+			name: "IPv6 地址规范化后查询",
+			ip:   "2001:DB8:0:0::1",
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("RevokeSessionsByIP", mock.Anything, "2001:db8::1").Return(1, nil)
+			},
+			wantRevoked: 1,
+		},
+		{
+			name:    "IP 格式不正确",
+			ip:      "not-an-ip",
+			wantErr: error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:    "IP 为空",
+			ip:      "",
+			wantErr: error_reason.IsUserInvalidRequest,
+		},
+		{
+			name: "存储访问失败",
+			ip:   "1.2.3.4",
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("RevokeSessionsByIP", mock.Anything, "1.2.3.4").Return(0, errors.New("redis unavailable"))
+			},
+			wantErr: error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authRepo := new(MockAuthRepository)
+			if tt.setupMocks != nil {
+				tt.setupMocks(authRepo)
+			}
+			uc := NewUserUsecase(new(MockUserRepository), new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			revoked, err := uc.RevokeSessionsByIP(context.Background(), tt.ip)
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantRevoked, revoked)
+			}
+			if tt.setupMocks == nil {
+				authRepo.AssertNotCalled(t, "RevokeSessionsByIP", mock.Anything, mock.Anything)
+			}
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_Login_RecordsSessionIP 测试登录时记录客户端 IP，记录失败不影响登录
+func TestUserUsecase_Login_RecordsSessionIP(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	hashedPassword, _ := newPasswordHasher(PasswordHashBcrypt).Hash("password123")
+	user := &User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword}
+
+	for _, recordErr := range []error{nil, errors.New("redis unavailable")} {
+		userRepo := new(MockUserRepository)
+		authRepo := new(MockAuthRepository)
+		allowTokenPairing(authRepo)
+		userRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
+		authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).Return(nil)
+		authRepo.On("RecordSessionIP", mock.Anything, int64(1), mock.Anything, "1.2.3.4", mock.Anything).Return(recordErr)
+
+		uc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+		pair, err := uc.Login(WithClientIP(context.Background(), "1.2.3.4"), "test@example.com", "password123", false)
+
+		require.NoError(t, err)
+		authRepo.AssertCalled(t, "RecordSessionIP", mock.Anything, int64(1), pair.RefreshToken, "1.2.3.4", mock.Anything)
+	}
+}
@@ -0,0 +1,72 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// defaultSlowOperationThreshold 未配置时的慢操作阈值
+const defaultSlowOperationThreshold = 500 * time.Millisecond
+
+// Clock 时钟接口，便于在测试中控制时间
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock 使用系统时间的时钟实现
+type systemClock struct{}
+
+// NewSystemClock 创建系统时钟
+func NewSystemClock() Clock {
+	return systemClock{}
+}
+
+// Now 返回当前系统时间
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SlowOperationConfig 慢操作日志配置
+type SlowOperationConfig struct {
+	// Threshold 耗时超过该值的业务操作记录 WARN 日志
+	Threshold time.Duration
+}
+
+// SlowOperationLogger 记录耗时超过阈值的业务操作，与数据库慢查询日志互为补充
+type SlowOperationLogger struct {
+	clock     Clock
+	threshold time.Duration
+	log       *log.Helper
+}
+
+// NewSlowOperationLogger 创建慢操作日志记录器
+func NewSlowOperationLogger(clock Clock, c SlowOperationConfig, logger log.Logger) *SlowOperationLogger {
+	threshold := c.Threshold
+	if threshold <= 0 {
+		threshold = defaultSlowOperationThreshold
+	}
+	return &SlowOperationLogger{
+		clock:     clock,
+		threshold: threshold,
+		log:       log.NewHelper(logger),
+	}
+}
+
+// Track 开始计时，返回的函数在操作结束时调用，耗时超过阈值时记录 WARN 日志
+// 用法：defer uc.slowOp.Track(ctx, "Login")()
+func (s *SlowOperationLogger) Track(ctx context.Context, operation string) func() {
+	start := s.clock.Now()
+	return func() {
+		elapsed := s.clock.Now().Sub(start)
+		if elapsed >= s.threshold {
+			s.log.WithContext(ctx).Warnw(
+				"msg", "slow business operation",
+				"operation", operation,
+				"duration_ms", elapsed.Milliseconds(),
+				"threshold_ms", s.threshold.Milliseconds(),
+			)
+		}
+	}
+}
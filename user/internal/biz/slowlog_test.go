@@ -0,0 +1,87 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock 每次调用 Now 时按 step 前进的测试时钟
+type fakeClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// TestSlowOperationLogger_Track 测试耗时超过阈值时记录 WARN 日志
+func TestSlowOperationLogger_Track(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		elapsed   time.Duration
+		wantWarn  bool
+	}{
+		{
+			name:      "超过阈值记录慢操作",
+			threshold: 500 * time.Millisecond,
+			elapsed:   2 * time.Second,
+			wantWarn:  true,
+		},
+		{
+			name:      "未超过阈值不记录",
+			threshold: 500 * time.Millisecond,
+			elapsed:   100 * time.Millisecond,
+			wantWarn:  false,
+		},
+		{
+			name:      "未配置阈值时使用默认值",
+			threshold: 0,
+			elapsed:   defaultSlowOperationThreshold,
+			wantWarn:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			clock := &fakeClock{now: time.Unix(1700000000, 0), step: tt.elapsed}
+			slowOp := NewSlowOperationLogger(clock, SlowOperationConfig{Threshold: tt.threshold}, log.NewStdLogger(&buf))
+
+			slowOp.Track(context.Background(), "Login")()
+
+			if tt.wantWarn {
+				assert.Contains(t, buf.String(), "WARN")
+				assert.Contains(t, buf.String(), "operation=Login")
+				assert.Contains(t, buf.String(), fmt.Sprintf("duration_ms=%d", tt.elapsed.Milliseconds()))
+			} else {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_SlowOperation 测试慢速的刷新令牌操作会记录 WARN 日志
+func TestAuthUsecase_RefreshToken_SlowOperation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewStdLogger(&buf)
+	clock := &fakeClock{now: time.Unix(1700000000, 0), step: 3 * time.Second}
+	slowOp := NewSlowOperationLogger(clock, SlowOperationConfig{Threshold: time.Second}, logger)
+
+	uc := NewAuthUsecase(new(MockUserRepository), new(MockAuthRepository), AuthConfig{}, slowOp, logger)
+	_, err := uc.RefreshToken(context.Background(), "")
+
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "slow business operation")
+	assert.Contains(t, buf.String(), "operation=RefreshToken")
+	assert.Contains(t, buf.String(), "duration_ms=3000")
+}
@@ -0,0 +1,96 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+const (
+	// testEmailLimit 每个管理员在一个统计窗口内最多发送诊断测试邮件的次数，防止被用来向任意地址发信
+	testEmailLimit = 10
+	// testEmailWindow 诊断测试邮件发送次数的统计窗口
+	testEmailWindow = time.Hour
+)
+
+// TestEmailResult 诊断测试邮件的投递结果
+type TestEmailResult struct {
+	// Status 邮件服务商的处理结果，取值同邮件发送记录（sent/failed）
+	Status string
+	// Latency 调用 EmailSender 的耗时
+	Latency time.Duration
+	// Error 发送失败时服务商返回的错误信息
+	Error string
+}
+
+// SendTestEmail 使用实际配置的 EmailSender 向指定地址发送一封诊断测试邮件，用于排查邮件投递问题
+// 按管理员限流；服务商发送失败不作为错误返回，而是写入结果的 Status 和 Error
+func (uc *UserUsecase) SendTestEmail(ctx context.Context, adminID int64, email string) (*TestEmailResult, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.SendTestEmail")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "send_test_email",
+		"user_id":   adminID,
+	})
+
+	if err := ValidateEmailFormat(email); err != nil {
+		return nil, err
+	}
+
+	resetAt := time.Now().Truncate(testEmailWindow).Add(testEmailWindow)
+	ok, err := uc.limiter.Allow(ctx, LimiterTestEmail, fmt.Sprintf("%d", adminID), func(ctx context.Context) (bool, error) {
+		return uc.codeRepo.CheckAndIncrTestEmailSends(ctx, adminID, testEmailLimit, resetAt)
+	})
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to check test email limit for user id: %d, error_reason: %v", adminID, err)
+		return nil, error_reason.ErrorUserDatabaseError("频率限制检查失败")
+	}
+	if !ok {
+		return nil, error_reason.ErrorUserTooManyRequests("测试邮件发送过于频繁，请稍后再试")
+	}
+
+	uc.log.WithContext(ctx).Infof("Sending test email to %s requested by user id: %d", maskEmail(email), adminID)
+	start := time.Now()
+	sendErr := uc.emailSender.Send(ctx, uc.buildTestEmail(email))
+	latency := time.Since(start)
+	uc.recordEmailLog(ctx, EmailTypeTest, email, sendErr)
+
+	result := &TestEmailResult{Status: EmailLogStatusSent, Latency: latency}
+	if sendErr != nil {
+		uc.log.WithContext(ctx).Warnf("Test email to %s failed after %s, error_reason: %v", maskEmail(email), latency, sendErr)
+		result.Status = EmailLogStatusFailed
+		result.Error = sendErr.Error()
+		return result, nil
+	}
+
+	uc.log.WithContext(ctx).Infof("Test email to %s sent in %s", maskEmail(email), latency)
+	return result, nil
+}
+
+// buildTestEmail 构建诊断测试邮件，与验证码邮件使用相同的发件人信息
+func (uc *UserUsecase) buildTestEmail(email string) *EmailMessage {
+	appName := uc.emailConfig.AppName
+	sentAt := time.Now().Format(time.RFC3339)
+	plainTextContent := fmt.Sprintf(`这是一封来自%s的测试邮件，用于检查邮件发送配置。
+
+发送时间：%s
+
+收到此邮件说明邮件服务工作正常，无需回复。
+`, appName, sentAt)
+
+	return &EmailMessage{
+		FromName:  uc.emailConfig.SenderName,
+		FromEmail: uc.emailConfig.SenderEmail,
+		ToName:    strings.Split(maskEmail(email), "@")[0],
+		ToEmail:   email,
+		Subject:   fmt.Sprintf("%s 邮件发送测试", appName),
+		PlainText: plainTextContent,
+		HTML:      strings.ReplaceAll(html.EscapeString(plainTextContent), "\n", "<br>\n"),
+	}
+}
@@ -0,0 +1,95 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserUsecase_SendTestEmail 测试诊断测试邮件报告服务商结果和耗时，超过频率限制时拒绝且不发送
+func TestUserUsecase_SendTestEmail(t *testing.T) {
+	const adminID = int64(1)
+	email := "ops@example.com"
+
+	tests := []struct {
+		name       string
+		allowed    bool
+		sendErr    error
+		wantStatus string
+		wantError  string
+		wantErr    func(error) bool
+	}{
+		{
+			name:       "发送成功",
+			allowed:    true,
+			wantStatus: EmailLogStatusSent,
+		},
+		{
+			name:       "服务商发送失败时写入结果而不返回错误",
+			allowed:    true,
+			sendErr:    errors.New("sendgrid: 401 unauthorized"),
+			wantStatus: EmailLogStatusFailed,
+			wantError:  "sendgrid: 401 unauthorized",
+		},
+		{
+			name:    "超过频率限制",
+			allowed: false,
+			wantErr: error_reason.IsUserTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeRepo := new(MockCodeRepository)
+			emailSender := new(MockEmailSender)
+			emailLogRepo := new(MockEmailLogRepository)
+
+			codeRepo.On("CheckAndIncrTestEmailSends", mock.Anything, adminID, testEmailLimit, mock.Anything).Return(tt.allowed, nil)
+			var sent *EmailMessage
+			var logged *EmailLog
+			if tt.allowed {
+				emailSender.On("Send", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						sent = args.Get(1).(*EmailMessage)
+						time.Sleep(5 * time.Millisecond)
+					}).
+					Return(tt.sendErr).Once()
+				emailLogRepo.On("Create", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						logged = args.Get(1).(*EmailLog)
+					}).
+					Return(nil).Once()
+			}
+
+			emailConfig := EmailConfig{AppName: "绘本", SenderEmail: "noreply@example.com"}
+			uc := NewUserUsecase(new(MockUserRepository), codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, emailLogRepo, emailConfig, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			result, err := uc.SendTestEmail(context.Background(), adminID, email)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err))
+				emailSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantError, result.Error)
+			assert.GreaterOrEqual(t, result.Latency, 5*time.Millisecond)
+			require.NotNil(t, sent)
+			assert.Equal(t, email, sent.ToEmail)
+			assert.Equal(t, "noreply@example.com", sent.FromEmail)
+			require.NotNil(t, logged)
+			assert.Equal(t, EmailTypeTest, logged.EmailType)
+			assert.Equal(t, tt.wantStatus, logged.Status)
+			emailSender.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+// errTokenIssuedBeforeCutoff 令牌签发时间早于全局失效时间点
+var errTokenIssuedBeforeCutoff = errors.New("token issued before tokens_valid_after")
+
+// checkTokenValidAfter 开启 EnforceTokensValidAfter 时校验令牌签发时间不早于全局失效时间点
+// 令牌已失效时返回 errTokenIssuedBeforeCutoff；读取失效时间点失败时无法断定令牌有效，返回底层错误由调用方拒绝请求
+func (uc *AuthUsecase) checkTokenValidAfter(ctx context.Context, issuedAt *jwt.NumericDate) error {
+	if !uc.authConfig.EnforceTokensValidAfter {
+		return nil
+	}
+	validAfter, err := uc.authRepo.GetTokensValidAfter(ctx)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to get tokens valid after, error_reason: %v", err)
+		return err
+	}
+	if validAfter.IsZero() {
+		return nil
+	}
+	if issuedAt == nil || issuedAt.Before(validAfter) {
+		return errTokenIssuedBeforeCutoff
+	}
+	return nil
+}
+
+// RevokeAllTokens 将全局令牌失效时间点推进到当前时间，使此前签发的所有访问令牌和刷新令牌失效，返回新的失效时间点
+// 令牌签发时间只精确到秒，失效时间点取下一个整秒，与本次撤销同一秒内签发的令牌一并失效
+func (uc *UserUsecase) RevokeAllTokens(ctx context.Context) (time.Time, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.RevokeAllTokens")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "revoke_all_tokens",
+	})
+
+	if !uc.authConfig.EnforceTokensValidAfter {
+		uc.log.WithContext(ctx).Warn("RevokeAllTokens called without auth.enforce_tokens_valid_after enabled")
+		return time.Time{}, error_reason.ErrorUserInvalidRequest("未开启令牌签发时间校验，撤销不会生效")
+	}
+
+	validAfter := time.Now().Truncate(time.Second).Add(time.Second)
+	if err := uc.authRepo.SetTokensValidAfter(ctx, validAfter); err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to set tokens valid after, error_reason: %v", err)
+		return time.Time{}, error_reason.ErrorUserDatabaseError("撤销令牌失败")
+	}
+
+	uc.log.WithContext(ctx).Infof("Revoked all tokens issued before %s", validAfter.Format(time.RFC3339))
+	return validAfter, nil
+}
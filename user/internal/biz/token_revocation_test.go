@@ -0,0 +1,164 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuthUsecase_ValidateToken_TokensValidAfter 测试开启后签发时间早于全局失效时间点的访问令牌被拒绝，之后签发的令牌校验通过
+func TestAuthUsecase_ValidateToken_TokensValidAfter(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	now := time.Now()
+	cutoff := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		enforce    bool
+		issuedAt   time.Time
+		validAfter time.Time
+		getErr     error
+		wantErr    func(error) bool
+	}{
+		{
+			name:       "失效时间点之前签发的令牌被拒绝",
+			enforce:    true,
+			issuedAt:   cutoff.Add(-time.Minute),
+			validAfter: cutoff,
+			wantErr:    error_reason.IsUserInvalidToken,
+		},
+		{
+			name:       "失效时间点之后签发的令牌校验通过",
+			enforce:    true,
+			issuedAt:   cutoff.Add(time.Minute),
+			validAfter: cutoff,
+		},
+		{
+			name:     "从未撤销时校验通过",
+			enforce:  true,
+			issuedAt: cutoff.Add(-time.Minute),
+		},
+		{
+			name:     "读取失效时间点失败时拒绝",
+			enforce:  true,
+			issuedAt: cutoff.Add(time.Minute),
+			getErr:   errors.New("redis unavailable"),
+			wantErr:  error_reason.IsUserServiceUnavailable,
+		},
+		{
+			name:     "未开启时不读取失效时间点",
+			issuedAt: cutoff.Add(-time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authRepo := new(MockAuthRepository)
+			if tt.enforce {
+				authRepo.On("GetTokensValidAfter", mock.Anything).Return(tt.validAfter, tt.getErr)
+			}
+			uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{EnforceTokensValidAfter: tt.enforce}, newTestSlowOperationLogger(), getTestLogger())
+
+			userID, err := uc.ValidateToken(context.Background(), signTestAccessToken(t, tt.issuedAt, now.Add(time.Hour)))
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(123), userID)
+			}
+			if !tt.enforce {
+				authRepo.AssertNotCalled(t, "GetTokensValidAfter", mock.Anything)
+			}
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAuthUsecase_RefreshToken_TokensValidAfter 测试全局撤销之前签发的刷新令牌不能换取新令牌，并被删除
+func TestAuthUsecase_RefreshToken_TokensValidAfter(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	now := time.Now()
+	refreshToken := signTestRefreshToken(t, now.Add(-2*time.Hour), now.Add(24*time.Hour))
+
+	authRepo := new(MockAuthRepository)
+	authRepo.On("GetUserIDByRefreshToken", mock.Anything, refreshToken).Return(int64(123), nil)
+	authRepo.On("GetTokensValidAfter", mock.Anything).Return(now.Add(-time.Hour), nil)
+	authRepo.On("DeleteRefreshToken", mock.Anything, refreshToken).Return(nil)
+
+	uc := NewAuthUsecase(new(MockUserRepository), authRepo, AuthConfig{EnforceTokensValidAfter: true}, newTestSlowOperationLogger(), getTestLogger())
+	tokenPair, err := uc.RefreshToken(context.Background(), refreshToken)
+
+	assert.True(t, error_reason.IsUserRefreshTokenInvalid(err), "unexpected error: %v", err)
+	assert.Nil(t, tokenPair)
+	authRepo.AssertNotCalled(t, "VerifyAndRotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	authRepo.AssertExpectations(t)
+}
+
+// TestUserUsecase_RevokeAllTokens 测试全局撤销将失效时间点推进到当前时间之后的整秒；未开启校验时拒绝且不写入
+func TestUserUsecase_RevokeAllTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		enforce    bool
+		setupMocks func(*MockAuthRepository)
+		wantErr    func(error) bool
+	}{
+		{
+			name:    "推进失效时间点",
+			enforce: true,
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("SetTokensValidAfter", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+			},
+		},
+		{
+			name:    "未开启令牌签发时间校验",
+			wantErr: error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:    "写入失败",
+			enforce: true,
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("SetTokensValidAfter", mock.Anything, mock.Anything).Return(errors.New("redis unavailable"))
+			},
+			wantErr: error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authRepo := new(MockAuthRepository)
+			if tt.setupMocks != nil {
+				tt.setupMocks(authRepo)
+			}
+			uc := NewUserUsecase(new(MockUserRepository), new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{EnforceTokensValidAfter: tt.enforce}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			before := time.Now()
+			validAfter, err := uc.RevokeAllTokens(context.Background())
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+				// 与撤销同一秒内签发的令牌一并失效
+				assert.True(t, validAfter.After(before))
+				assert.Equal(t, time.Duration(0), validAfter.Sub(validAfter.Truncate(time.Second)))
+				authRepo.AssertCalled(t, "SetTokensValidAfter", mock.Anything, validAfter)
+			}
+			if tt.setupMocks == nil {
+				authRepo.AssertNotCalled(t, "SetTokensValidAfter", mock.Anything, mock.Anything)
+			}
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -5,20 +5,18 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
-	"os"
+	"html"
 	"strings"
 
 	"math/big"
 	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
-	"golang.org/x/crypto/bcrypt"
+	"go.opentelemetry.io/otel/metric"
 	"gorm.io/gorm"
 
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
-	"user/internal/pkg/tracing"
 	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
 )
 
 var (
@@ -47,23 +45,56 @@ func isUniqueConstraintError(err error) bool {
 		strings.Contains(errStr, "constraint failed")
 }
 
+const (
+	// CodePurposeRegister 注册验证码用途
+	CodePurposeRegister = "register"
+	// CodePurposeResetPassword 找回密码验证码用途
+	CodePurposeResetPassword = "reset_password"
+)
+
+const (
+	// codeStatusQueryLimit 每个邮箱在一个统计窗口内最多查询验证码状态的次数，防止探测
+	codeStatusQueryLimit = 20
+	// codeStatusQueryWindow 验证码状态查询次数的统计窗口
+	codeStatusQueryWindow = time.Minute
+	// registerCodeExtension 每次延长注册验证码的时长，延长后的剩余有效期不超过验证码最大有效期
+	registerCodeExtension = 5 * time.Minute
+)
+
+const (
+	// maxPasswordFailures 统计窗口内允许的密码校验失败次数，达到后暂停校验，防止暴力猜测
+	maxPasswordFailures = 5
+	// passwordFailureWindow 密码校验失败次数的统计窗口，最后一次失败后经过该时长计数清零
+	passwordFailureWindow = 15 * time.Minute
+)
+
+// CodeStatus 验证码状态，不包含验证码本身
+type CodeStatus struct {
+	Exists           bool
+	RemainingSeconds int32
+}
+
 // VerificationCode 验证码实体，用于存储和验证用户注册验证码
 type VerificationCode struct {
 	Email     string
-	Code      string
+	Code      string // 验证码的 HMAC 哈希，见 CodeHasher
 	ExpiresAt time.Time
 }
 
 // User 用户基本信息表
 type User struct {
-	ID           int64     `gorm:"column:id;primaryKey" json:"id"`
-	Email        string    `gorm:"column:email;uniqueIndex;not null" json:"email"`
-	PasswordHash string    `gorm:"column:password_hash;not null" json:"-"`
-	Nickname     string    `gorm:"column:nickname;not null;default:'新用户'" json:"nickname"`
-	AvatarURL    string    `gorm:"column:avatar_url" json:"avatar_url,omitempty"`
-	IsPremium    uint8     `gorm:"column:is_premium;not null;default:0" json:"is_premium"`
-	CreatedAt    time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID           int64  `gorm:"column:id;primaryKey" json:"id"`
+	Email        string `gorm:"column:email;uniqueIndex;not null" json:"email"`
+	PasswordHash string `gorm:"column:password_hash;not null" json:"-"`
+	Nickname     string `gorm:"column:nickname;not null;default:'新用户'" json:"nickname"`
+	AvatarURL    string `gorm:"column:avatar_url" json:"avatar_url,omitempty"`
+	IsPremium    uint8  `gorm:"column:is_premium;not null;default:0" json:"is_premium"`
+	// EmailVerified 邮箱是否已验证，通过验证码注册的账号创建时即已验证
+	EmailVerified bool      `gorm:"column:email_verified;not null;default:0" json:"email_verified"`
+	CreatedAt     time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	// DeletedAt 软删除时间，合并重复账号时被合并方会被软删除
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
 }
 
 type UpdateUserRequest struct {
@@ -84,17 +115,55 @@ type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id int64) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	// GetByIDPublic/GetByEmailPublic 只查询非敏感字段（不加载 PasswordHash），用于不需要校验密码的读路径
+	GetByIDPublic(ctx context.Context, id int64) (*User, error)
+	GetByEmailPublic(ctx context.Context, email string) (*User, error)
 	Update(ctx context.Context, id int64, req *UpdateUserRequest) error
+	// UpdateAndGet 更新用户资料并返回更新后的用户（只含非敏感字段），更新和读取在同一事务中完成
+	UpdateAndGet(ctx context.Context, id int64, req *UpdateUserRequest) (*User, error)
+	// UpdatePasswordHash 更新用户的密码哈希
+	UpdatePasswordHash(ctx context.Context, id int64, passwordHash string) error
+	// FindDuplicateEmails 查找规范化后邮箱相同的未删除账号
+	FindDuplicateEmails(ctx context.Context) ([]*DuplicateEmailGroup, error)
+	// SoftDelete 软删除用户
+	SoftDelete(ctx context.Context, id int64) error
+	// ListByCreatedBetween 按注册时间升序分页查询 [from, to] 内注册的未删除用户（只含非敏感字段），同时返回总条数
+	ListByCreatedBetween(ctx context.Context, from, to time.Time, page, pageSize int) ([]*User, int64, error)
+	// Stats 统计未删除的用户总数和付费用户数
+	Stats(ctx context.Context) (*UserStats, error)
+}
+
+// UserStats 未删除用户的数量统计
+type UserStats struct {
+	Total   int64 `json:"total"`
+	Premium int64 `json:"premium"`
 }
 
 // CodeRepository 认证数据访问接口，定义了验证码相关的数据操作方法
 type CodeRepository interface {
 	// 验证码相关操作
 	StoreVerificationCode(ctx context.Context, email, code string, expiresAt time.Time) error
+	// StoreCode 按用途存储验证码，不同用途的验证码互不覆盖；StoreVerificationCode 等价于用途为 CodePurposeRegister
+	StoreCode(ctx context.Context, email, purpose, code string, expiresAt time.Time) error
 	GetVerificationCode(ctx context.Context, email string) (*VerificationCode, error)
 	DeleteVerificationCode(ctx context.Context, email string) error
+	// ConsumeIfValid 原子地校验并消费验证码：存储的哈希与 candidate 一致时删除验证码并返回 true，
+	// 不一致时返回 false 且不删除；验证码不存在或已过期时返回 ErrVerificationCodeExpired
+	ConsumeIfValid(ctx context.Context, email, purpose, candidate string) (bool, error)
+	// ExtendVerificationCodeTTL 在不更换验证码的前提下延长其有效期，总剩余有效期有上限；验证码不存在时返回 ErrVerificationCodeExpired
+	ExtendVerificationCodeTTL(ctx context.Context, email, purpose string, extra time.Duration) error
 	// 发送频率限制
 	CheckAndSetSendRateLimit(ctx context.Context, email string, duration time.Duration) (bool, error)
+	// CheckAndIncrDailySendLimit 累加当天的发送次数，计数在 resetAt 时清零；累加后超过 limit 时返回 false
+	CheckAndIncrDailySendLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error)
+	// GetVerificationCodeTTL 返回注册验证码的剩余有效期，验证码不存在时返回 ErrVerificationCodeExpired
+	GetVerificationCodeTTL(ctx context.Context, email string) (time.Duration, error)
+	// CheckAndIncrCodeStatusLimit 累加验证码状态查询次数，计数在 resetAt 时清零；累加后超过 limit 时返回 false
+	CheckAndIncrCodeStatusLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error)
+	// CheckAndIncrRegistrationAttempts 累加客户端 IP 的注册请求次数，计数在 resetAt 时清零；累加后超过 limit 时返回 false
+	CheckAndIncrRegistrationAttempts(ctx context.Context, ip string, limit int, resetAt time.Time) (bool, error)
+	// CheckAndIncrTestEmailSends 累加管理员发送诊断测试邮件的次数，计数在 resetAt 时清零；累加后超过 limit 时返回 false
+	CheckAndIncrTestEmailSends(ctx context.Context, userID int64, limit int, resetAt time.Time) (bool, error)
 }
 
 // SnowflakeIDGenerator 雪花ID生成器接口
@@ -108,10 +177,56 @@ type UserUsecase struct {
 	codeRepo CodeRepository
 	authRepo AuthRepository
 	idGen    SnowflakeIDGenerator
+	slowOp   *SlowOperationLogger
+	limiter  *rateLimiter
 	log      *log.Helper
 
+	// 邮件发送及发送记录
+	emailSender  EmailSender
+	emailLogRepo EmailLogRepository
+	// 欢迎邮件发送结果计数
+	welcomeEmails metric.Int64Counter
+
 	// 邮件配置
 	emailConfig EmailConfig
+	// 认证配置
+	authConfig AuthConfig
+	// 验证码哈希
+	codeHasher *CodeHasher
+	// 注册请求过多时校验人机验证令牌
+	captcha CaptchaVerifier
+	// 新密码使用的哈希算法
+	passwordHasher PasswordHasher
+}
+
+// VerificationCodeLength 未配置 CodeConfig.Length 时验证码的默认位数
+const VerificationCodeLength = 6
+
+// defaultVerificationCodeCharset 未配置 CodeConfig.Charset 时验证码使用的字符集
+const defaultVerificationCodeCharset = "0123456789"
+
+// CodeConfig 验证码格式配置，零值为6位数字
+type CodeConfig struct {
+	// Length 验证码长度，0 表示使用默认值 6
+	Length int
+	// Charset 验证码字符集，每个字符等概率出现，为空表示使用数字 0-9
+	Charset string
+}
+
+// length 返回验证码长度，未配置时使用默认值
+func (c CodeConfig) length() int {
+	if c.Length > 0 {
+		return c.Length
+	}
+	return VerificationCodeLength
+}
+
+// charset 返回验证码字符集，未配置时使用数字
+func (c CodeConfig) charset() string {
+	if c.Charset != "" {
+		return c.Charset
+	}
+	return defaultVerificationCodeCharset
 }
 
 // EmailConfig 邮件配置
@@ -121,25 +236,44 @@ type EmailConfig struct {
 	SupportEmail string
 	CompanyName  string
 	AppName      string
+	// DailySendLimit 每个邮箱每天最多发送验证码的次数，0 表示不限制
+	DailySendLimit int
+	// WelcomeEmailEnabled 注册成功后是否发送欢迎邮件
+	WelcomeEmailEnabled bool
+	// Code 注册验证码的长度和字符集
+	Code CodeConfig
 }
 
 // NewUserUsecase new a User usecase.
-func NewUserUsecase(userRepo UserRepository, codeRepo CodeRepository, authRepo AuthRepository, idGen SnowflakeIDGenerator, emailConfig EmailConfig, logger log.Logger) *UserUsecase {
+func NewUserUsecase(userRepo UserRepository, codeRepo CodeRepository, authRepo AuthRepository, idGen SnowflakeIDGenerator, emailSender EmailSender, emailLogRepo EmailLogRepository, emailConfig EmailConfig, authConfig AuthConfig, codeHasher *CodeHasher, captcha CaptchaVerifier, slowOp *SlowOperationLogger, logger log.Logger) *UserUsecase {
+	if captcha == nil {
+		captcha = NewCaptchaVerifier()
+	}
 	return &UserUsecase{
-		userRepo:    userRepo,
-		codeRepo:    codeRepo,
-		authRepo:    authRepo,
-		idGen:       idGen,
-		log:         log.NewHelper(logger),
-		emailConfig: emailConfig,
+		userRepo:     userRepo,
+		codeRepo:     codeRepo,
+		authRepo:     authRepo,
+		idGen:        idGen,
+		slowOp:       slowOp,
+		limiter:      newRateLimiter(nil, logger),
+		log:          log.NewHelper(logger),
+		emailSender:  emailSender,
+		emailLogRepo: emailLogRepo,
+		emailConfig:  emailConfig,
+		authConfig:   authConfig,
+		codeHasher:   codeHasher,
+		captcha:      captcha,
+
+		welcomeEmails:  newWelcomeEmailCounter(nil, logger),
+		passwordHasher: newPasswordHasher(authConfig.PasswordHashScheme),
 	}
 }
 
 // ErrTooManyRequests 发送请求过于频繁
 var ErrTooManyRequests = errors.New("too many requests, please try again later")
 
-// SendRegisterCode 发送注册验证码
-func (uc *UserUsecase) SendRegisterCode(ctx context.Context, email string) error {
+// SendRegisterCode 发送注册验证码，captchaToken 为人机验证令牌，同一 IP 请求过多时必填
+func (uc *UserUsecase) SendRegisterCode(ctx context.Context, email, captchaToken string) error {
 	ctx, span := tracing.StartSpan(ctx, "UserUsecase.SendRegisterCode")
 	defer span.End()
 
@@ -151,13 +285,13 @@ func (uc *UserUsecase) SendRegisterCode(ctx context.Context, email string) error
 	uc.log.WithContext(ctx).Infof("Sending registration code to email: %s", email)
 
 	// 验证邮箱格式
-	if email == "" {
-		uc.log.WithContext(ctx).Warn("Empty email provided")
-		return error_reason.ErrorUserInvalidEmail("邮箱不能为空")
+	if err := ValidateEmailFormat(email); err != nil {
+		uc.log.WithContext(ctx).Warnf("Invalid email provided: %s, error_reason: %v", email, err)
+		return err
 	}
 
 	// 检查邮箱是否已注册
-	_, err := uc.userRepo.GetByEmail(ctx, email)
+	_, err := uc.userRepo.GetByEmailPublic(ctx, email)
 	if err == nil {
 		uc.log.WithContext(ctx).Infof("Email already registered: %s", email)
 		return error_reason.ErrorUserEmailAlreadyExists("该邮箱已被注册")
@@ -166,24 +300,48 @@ func (uc *UserUsecase) SendRegisterCode(ctx context.Context, email string) error
 		return error_reason.ErrorUserDatabaseError("数据库查询失败")
 	}
 
+	// 同一 IP 注册请求过多时要求人机验证
+	if err := uc.checkRegistrationCaptcha(ctx, captchaToken); err != nil {
+		return err
+	}
+
 	// 检查发送频率限制（60秒内只能发送一次）
-	// 这可以防止并发请求重复发送验证码
-	ok, err := uc.codeRepo.CheckAndSetSendRateLimit(ctx, email, 60*time.Second)
+	// 并发请求都可能通过上面的邮箱检查，SETNX 是唯一的串行化点，必须在生成、存储和发送验证码之前执行
+	ok, err := uc.limiter.Allow(ctx, LimiterCodeSend, email, func(ctx context.Context) (bool, error) {
+		return uc.codeRepo.CheckAndSetSendRateLimit(ctx, email, 60*time.Second)
+	})
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to check rate limit for email: %s, error_reason: %v", email, err)
 		return error_reason.ErrorUserDatabaseError("频率限制检查失败")
 	}
 	if !ok {
-		uc.log.WithContext(ctx).Warnf("Send verification code too frequently for email: %s", email)
 		return error_reason.ErrorUserTooManyRequests("请求过于频繁，请稍后再试")
 	}
 
+	// 检查每日发送上限，防止绕过60秒冷却的低频持续发送
+	if uc.emailConfig.DailySendLimit > 0 {
+		resetAt := truncateToDay(time.Now()).AddDate(0, 0, 1)
+		ok, err = uc.limiter.Allow(ctx, LimiterCodeSendDaily, email, func(ctx context.Context) (bool, error) {
+			return uc.codeRepo.CheckAndIncrDailySendLimit(ctx, email, uc.emailConfig.DailySendLimit, resetAt)
+		})
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to check daily send limit for email: %s, error_reason: %v", email, err)
+			return error_reason.ErrorUserDatabaseError("频率限制检查失败")
+		}
+		if !ok {
+			return error_reason.ErrorUserTooManyRequests("今日发送次数已达上限，请明天再试")
+		}
+	}
+
 	// 生成验证码
-	code := generateVerificationCode()
+	code := generateVerificationCode(uc.emailConfig.Code)
 	expiresAt := time.Now().Add(10 * time.Minute) // 10分钟过期
 
-	// 存储验证码
-	err = uc.codeRepo.StoreVerificationCode(ctx, email, code, expiresAt)
+	codeHash := uc.codeHasher.Hash(email, code)
+	ctx = withRegistrationAttempt(ctx, registrationAttemptID(codeHash))
+
+	// 存储验证码哈希，明文只出现在邮件中
+	err = uc.codeRepo.StoreVerificationCode(ctx, email, codeHash, expiresAt)
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to store verification code for email: %s, error_reason: %v", email, err)
 		return error_reason.ErrorUserDatabaseError("验证码存储失败")
@@ -194,17 +352,133 @@ func (uc *UserUsecase) SendRegisterCode(ctx context.Context, email string) error
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to send verification email to: %s, error_reason: %v", email, err)
 		// 即使邮件发送失败，也不删除验证码，用户可能需要重新发送
-		return error_reason.ErrorUserInternalError("邮件发送失败")
+		if errors.Is(err, ErrEmailSenderBusy) {
+			return error_reason.ErrorUserServiceUnavailable("邮件服务繁忙，请稍后重试")
+		}
+		return error_reason.ErrorUserInternalError("邮件发送失败").WithCause(tracing.WithStack(err))
 	}
 
 	uc.log.WithContext(ctx).Infof("Verification code sent successfully to: %s", email)
 	return nil
 }
 
-// Register 用户注册
-func (uc *UserUsecase) Register(ctx context.Context, email, password, code, nickname string) (*User, error) {
+// GetRegisterCodeStatus 查询注册验证码是否存在及剩余有效秒数，供前端展示倒计时
+// 按邮箱限制查询频率，避免被用来探测邮箱是否正在注册
+func (uc *UserUsecase) GetRegisterCodeStatus(ctx context.Context, email string) (*CodeStatus, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.GetRegisterCodeStatus")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "get_register_code_status",
+		"email":     email,
+	})
+
+	if err := ValidateEmailFormat(email); err != nil {
+		uc.log.WithContext(ctx).Warnf("Invalid email provided: %s, error_reason: %v", email, err)
+		return nil, err
+	}
+
+	resetAt := time.Now().Truncate(codeStatusQueryWindow).Add(codeStatusQueryWindow)
+	ok, err := uc.limiter.Allow(ctx, LimiterCodeStatus, email, func(ctx context.Context) (bool, error) {
+		return uc.codeRepo.CheckAndIncrCodeStatusLimit(ctx, email, codeStatusQueryLimit, resetAt)
+	})
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to check code status query limit for email: %s, error_reason: %v", email, err)
+		return nil, error_reason.ErrorUserDatabaseError("频率限制检查失败")
+	}
+	if !ok {
+		return nil, error_reason.ErrorUserTooManyRequests("请求过于频繁，请稍后再试")
+	}
+
+	ttl, err := uc.codeRepo.GetVerificationCodeTTL(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrVerificationCodeExpired) {
+			return &CodeStatus{Exists: false}, nil
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to get verification code TTL for email: %s, error_reason: %v", email, err)
+		return nil, error_reason.ErrorUserDatabaseError("验证码查询失败")
+	}
+
+	return &CodeStatus{Exists: true, RemainingSeconds: int32(ttl / time.Second)}, nil
+}
+
+// ExtendRegisterCode 在不更换验证码的前提下延长注册验证码的有效期，返回延长后的状态
+// 与 GetRegisterCodeStatus 共用按邮箱的查询频率限制；验证码不存在或已过期时返回 UserVerificationCodeExpired
+func (uc *UserUsecase) ExtendRegisterCode(ctx context.Context, email string) (*CodeStatus, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.ExtendRegisterCode")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "extend_register_code",
+		"email":     email,
+	})
+
+	if err := ValidateEmailFormat(email); err != nil {
+		uc.log.WithContext(ctx).Warnf("Invalid email provided: %s, error_reason: %v", email, err)
+		return nil, err
+	}
+
+	resetAt := time.Now().Truncate(codeStatusQueryWindow).Add(codeStatusQueryWindow)
+	ok, err := uc.limiter.Allow(ctx, LimiterCodeStatus, email, func(ctx context.Context) (bool, error) {
+		return uc.codeRepo.CheckAndIncrCodeStatusLimit(ctx, email, codeStatusQueryLimit, resetAt)
+	})
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to check code status query limit for email: %s, error_reason: %v", email, err)
+		return nil, error_reason.ErrorUserDatabaseError("频率限制检查失败")
+	}
+	if !ok {
+		return nil, error_reason.ErrorUserTooManyRequests("请求过于频繁，请稍后再试")
+	}
+
+	if err := uc.codeRepo.ExtendVerificationCodeTTL(ctx, email, CodePurposeRegister, registerCodeExtension); err != nil {
+		if errors.Is(err, ErrVerificationCodeExpired) {
+			uc.log.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+			return nil, error_reason.ErrorUserVerificationCodeExpired("验证码不存在或已过期，请重新获取")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to extend verification code TTL for email: %s, error_reason: %v", email, err)
+		return nil, error_reason.ErrorUserDatabaseError("验证码延期失败")
+	}
+
+	ttl, err := uc.codeRepo.GetVerificationCodeTTL(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrVerificationCodeExpired) {
+			return nil, error_reason.ErrorUserVerificationCodeExpired("验证码不存在或已过期，请重新获取")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to get verification code TTL for email: %s, error_reason: %v", email, err)
+		return nil, error_reason.ErrorUserDatabaseError("验证码查询失败")
+	}
+
+	uc.log.WithContext(ctx).Infof("Extended verification code for email: %s, remaining: %s", email, ttl)
+	return &CodeStatus{Exists: true, RemainingSeconds: int32(ttl / time.Second)}, nil
+}
+
+// consumeVerificationCode 依次使用当前密钥和旧密钥计算的哈希尝试消费验证码，校验与删除由存储层原子完成
+// 成功时返回与存储一致的哈希
+func (uc *UserUsecase) consumeVerificationCode(ctx context.Context, email, purpose, code string) (string, error) {
+	for _, candidate := range uc.codeHasher.Candidates(email, code) {
+		ok, err := uc.codeRepo.ConsumeIfValid(ctx, email, purpose, candidate)
+		if err != nil {
+			if errors.Is(err, ErrVerificationCodeExpired) {
+				uc.log.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+				return "", error_reason.ErrorUserVerificationCodeExpired("验证码不存在或已过期")
+			}
+			uc.log.WithContext(ctx).Errorf("Failed to consume verification code for email: %s, error_reason: %v", email, err)
+			return "", error_reason.ErrorUserDatabaseError("验证码校验失败")
+		}
+		if ok {
+			return candidate, nil
+		}
+	}
+
+	uc.log.WithContext(ctx).Warnf("Invalid verification code for email: %s", email)
+	return "", error_reason.ErrorUserInvalidVerificationCode("验证码错误")
+}
+
+// Register 用户注册，captchaToken 为人机验证令牌，同一 IP 请求过多时必填
+func (uc *UserUsecase) Register(ctx context.Context, email, password, code, nickname, captchaToken string) (*User, error) {
 	ctx, span := tracing.StartSpan(ctx, "UserUsecase.Register")
 	defer span.End()
+	defer uc.slowOp.Track(ctx, "Register")()
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
 		"operation": "register",
@@ -220,41 +494,44 @@ func (uc *UserUsecase) Register(ctx context.Context, email, password, code, nick
 		return nil, error_reason.ErrorUserInvalidRequest("邮箱、密码和验证码为必填项")
 	}
 
-	// 验证验证码
-	storedCode, err := uc.codeRepo.GetVerificationCode(ctx, email)
-	if err != nil {
-		uc.log.WithContext(ctx).Warnf("Failed to get verification code for email: %s, error_reason: %v", email, err)
-		return nil, error_reason.ErrorUserInvalidVerificationCode("验证码无效")
+	// 验证邮箱格式
+	if err := ValidateEmailFormat(email); err != nil {
+		uc.log.WithContext(ctx).Warnf("Invalid email provided for registration: %s, error_reason: %v", email, err)
+		return nil, err
 	}
 
-	if storedCode.Code != code {
-		uc.log.WithContext(ctx).Warnf("Invalid verification code for email: %s", email)
-		return nil, error_reason.ErrorUserInvalidVerificationCode("验证码错误")
+	// 密码强度验证，在消费验证码之前完成，避免因密码不合规而浪费验证码
+	if len(password) < 6 {
+		uc.log.WithContext(ctx).Warnf("Password too short for email: %s", email)
+		return nil, error_reason.ErrorUserInvalidRequest("密码长度至少为6位")
 	}
 
-	if time.Now().After(storedCode.ExpiresAt) {
-		uc.log.WithContext(ctx).Warnf("Verification code expired for email: %s", email)
-		return nil, error_reason.ErrorUserVerificationCodeExpired("验证码已过期")
+	// 昵称可选，未填写时使用默认昵称
+	if nickname != "" {
+		if err := ValidateNickname(nickname, uc.authConfig.MaxNicknameWidth); err != nil {
+			uc.log.WithContext(ctx).Warnf("Invalid nickname provided for registration: %q, error_reason: %v", nickname, err)
+			return nil, err
+		}
 	}
 
-	// 密码强度验证
-	if len(password) < 6 {
-		uc.log.WithContext(ctx).Warnf("Password too short for email: %s", email)
-		return nil, error_reason.ErrorUserInvalidRequest("密码长度至少为6位")
+	// 同一 IP 注册请求过多时要求人机验证，在消费验证码之前完成
+	if err := uc.checkRegistrationCaptcha(ctx, captchaToken); err != nil {
+		return nil, err
 	}
 
-	// 删除验证码
-	err = uc.codeRepo.DeleteVerificationCode(ctx, email)
+	// 校验并消费验证码
+	codeHash, err := uc.consumeVerificationCode(ctx, email, CodePurposeRegister, code)
 	if err != nil {
-		uc.log.WithContext(ctx).Errorf("Failed to delete verification code for email: %s, error_reason: %v", email, err)
-		// 不返回错误，因为用户已经通过验证
+		return nil, err
 	}
+	// 验证码错误的请求无法得到发送时的哈希，只有校验通过后才能关联到同一次注册尝试
+	ctx = withRegistrationAttempt(ctx, registrationAttemptID(codeHash))
 
 	// 密码哈希
-	hashedPassword, err := hashPassword(password)
+	hashedPassword, err := uc.hashPassword(password)
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to hash password for email: %s, error_reason: %v", email, err)
-		return nil, error_reason.ErrorUserInternalError("密码加密失败")
+		return nil, error_reason.ErrorUserInternalError("密码加密失败").WithCause(tracing.WithStack(err))
 	}
 
 	// 生成用户ID
@@ -271,8 +548,9 @@ func (uc *UserUsecase) Register(ctx context.Context, email, password, code, nick
 		PasswordHash: hashedPassword,
 		Nickname:     nickname,
 		IsPremium:    0,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		// 注册前已通过邮箱验证码校验
+		EmailVerified: true,
+		// CreatedAt/UpdatedAt 由数据库填充，Create 成功后回写到 user
 	}
 
 	err = uc.userRepo.Create(ctx, user)
@@ -290,17 +568,26 @@ func (uc *UserUsecase) Register(ctx context.Context, email, password, code, nick
 	user.PasswordHash = ""
 
 	uc.log.WithContext(ctx).Infof("Successfully registered user with id: %d, email: %s", user.ID, email)
+
+	// 欢迎邮件尽力发送，失败不影响注册结果
+	if uc.emailConfig.WelcomeEmailEnabled {
+		if err := uc.SendWelcomeEmail(ctx, user); err != nil {
+			uc.log.WithContext(ctx).Warnf("Failed to send welcome email to user id: %d, error_reason: %v", user.ID, err)
+		}
+	}
 	return user, nil
 }
 
-// Login 用户登录
-func (uc *UserUsecase) Login(ctx context.Context, email, password string) (*TokenPair, error) {
+// Login 用户登录，rememberMe 为 true 时签发有效期更长的刷新令牌
+func (uc *UserUsecase) Login(ctx context.Context, email, password string, rememberMe bool) (*TokenPair, error) {
 	ctx, span := tracing.StartSpan(ctx, "UserUsecase.Login")
 	defer span.End()
+	defer uc.slowOp.Track(ctx, "Login")()
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
-		"operation": "login",
-		"email":     email,
+		"operation":   "login",
+		"email":       email,
+		"remember_me": rememberMe,
 	})
 
 	uc.log.WithContext(ctx).Infof("User login attempt with email: %s", email)
@@ -323,26 +610,44 @@ func (uc *UserUsecase) Login(ctx context.Context, email, password string) (*Toke
 	}
 
 	// 验证密码
-	if !checkPasswordHash(password, user.PasswordHash) {
+	matched, needsRehash := uc.checkPasswordHash(password, user.PasswordHash)
+	if !matched {
 		uc.log.WithContext(ctx).Warnf("Invalid password for user with email: %s", email)
 		return nil, error_reason.ErrorUserInvalidCredentials("用户名或密码错误")
 	}
+	if needsRehash {
+		uc.rehashPassword(ctx, user.ID, password)
+	}
 
-	// 生成令牌
-	accessToken, accessExpiresIn, err := generateAccessToken(user.ID)
-	if err != nil {
-		uc.log.WithContext(ctx).Errorf("Failed to generate access token for user id: %d, error_reason: %v", user.ID, err)
-		return nil, error_reason.ErrorUserInternalError("访问令牌生成失败")
+	// 在密码校验通过后再检查邮箱验证状态，避免向未持有密码的请求暴露账号是否存在
+	if uc.authConfig.RequireEmailVerification && !user.EmailVerified {
+		uc.log.WithContext(ctx).Warnf("Login rejected for unverified email, user id: %d", user.ID)
+		return nil, error_reason.ErrorUserEmailNotVerified("邮箱未验证，请先完成邮箱验证")
 	}
 
-	refreshToken, refreshExpiresIn, err := generateRefreshToken(user.ID)
+	// 生成刷新令牌，访问令牌在刷新令牌存储成功后签发并与之配对
+	refreshToken, refreshTokenID, refreshExpiresIn, err := generateRefreshToken(user.ID, uc.authConfig.refreshTokenTTL(rememberMe))
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to generate refresh token for user id: %d, error_reason: %v", user.ID, err)
-		return nil, error_reason.ErrorUserInternalError("刷新令牌生成失败")
+		return nil, error_reason.ErrorUserInternalError("刷新令牌生成失败").WithCause(tracing.WithStack(err))
+	}
+
+	// 限制同时登录的会话数时，先踢出最早的会话，为新会话腾出位置
+	maxSessions := uc.authConfig.MaxActiveSessions
+	if maxSessions > 0 {
+		evicted, err := uc.authRepo.EvictOldestSessions(ctx, user.ID, maxSessions-1)
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to evict oldest sessions for user id: %d, error_reason: %v", user.ID, err)
+			return nil, error_reason.ErrorUserDatabaseError("令牌存储失败")
+		}
+		if evicted > 0 {
+			uc.log.WithContext(ctx).Infof("Evicted %d oldest sessions for user id: %d", evicted, user.ID)
+		}
 	}
 
 	// 存储刷新令牌
-	refreshTokenExpiresAt := time.Now().Add(time.Duration(refreshExpiresIn) * time.Second)
+	now := time.Now()
+	refreshTokenExpiresAt := now.Add(time.Duration(refreshExpiresIn) * time.Second)
 
 	err = uc.authRepo.StoreRefreshToken(ctx, user.ID, refreshToken, refreshTokenExpiresAt)
 	if err != nil {
@@ -350,6 +655,23 @@ func (uc *UserUsecase) Login(ctx context.Context, email, password string) (*Toke
 		return nil, error_reason.ErrorUserDatabaseError("令牌存储失败")
 	}
 
+	if maxSessions > 0 {
+		err = uc.authRepo.TrackSession(ctx, user.ID, refreshToken, now, refreshTokenExpiresAt)
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to track session for user id: %d, error_reason: %v", user.ID, err)
+			return nil, error_reason.ErrorUserDatabaseError("令牌存储失败")
+		}
+	}
+	recordSessionIP(ctx, uc.authRepo, uc.log, user.ID, refreshToken, refreshTokenExpiresAt)
+
+	subject := newTokenSubject(user, uc.authConfig)
+	subject.Fingerprint = uc.authConfig.tokenFingerprint(ctx)
+	accessToken, accessExpiresIn, err := issuePairedAccessToken(ctx, uc.authRepo, uc.log, subject, refreshTokenID, refreshTokenExpiresAt)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to generate access token for user id: %d, error_reason: %v", user.ID, err)
+		return nil, error_reason.ErrorUserInternalError("访问令牌生成失败").WithCause(tracing.WithStack(err))
+	}
+
 	uc.log.WithContext(ctx).Infof("User login successful for user id: %d, email: %s", user.ID, email)
 	return &TokenPair{
 		AccessToken:      accessToken,
@@ -359,18 +681,88 @@ func (uc *UserUsecase) Login(ctx context.Context, email, password string) (*Toke
 	}, nil
 }
 
-// generateVerificationCode 生成6位数字验证码
-func generateVerificationCode() string {
-	// 生成真正的数字验证码
-	code := make([]byte, 6)
+// VerifyPassword 校验用户当前密码是否正确，不修改密码
+// 密码错误时返回 false 并累计失败次数，统计窗口内失败次数过多时直接拒绝校验
+func (uc *UserUsecase) VerifyPassword(ctx context.Context, userID int64, password string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.VerifyPassword")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "verify_password",
+		"user_id":   userID,
+	})
+
+	// 参数验证
+	if userID <= 0 {
+		uc.log.WithContext(ctx).Warnf("Invalid user id: %d", userID)
+		return false, error_reason.ErrorUserInvalidRequest("无效的用户ID")
+	}
+	if password == "" {
+		uc.log.WithContext(ctx).Warnf("Missing password for user id: %d", userID)
+		return false, error_reason.ErrorUserInvalidRequest("密码不能为空")
+	}
+
+	// 失败次数检查
+	failures, err := uc.authRepo.GetPasswordFailures(ctx, userID)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to get password failures for user id: %d, error_reason: %v", userID, err)
+		return false, error_reason.ErrorUserDatabaseError("频率限制检查失败")
+	}
+	if failures >= maxPasswordFailures {
+		uc.log.WithContext(ctx).Warnf("Too many password failures for user id: %d", userID)
+		return false, error_reason.ErrorUserLoginTooMany("密码错误次数过多，请稍后再试")
+	}
+
+	// 获取用户
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			uc.log.WithContext(ctx).Warnf("User not found with id: %d", userID)
+			return false, error_reason.ErrorUserNotFound("用户不存在")
+		}
+		uc.log.WithContext(ctx).Errorf("Failed to get user with id: %d, error_reason: %v", userID, err)
+		return false, error_reason.ErrorUserDatabaseError("用户查询失败")
+	}
+
+	// 验证密码
+	matched, needsRehash := uc.checkPasswordHash(password, user.PasswordHash)
+	if !matched {
+		if _, err := uc.authRepo.IncrPasswordFailures(ctx, userID, passwordFailureWindow); err != nil {
+			uc.log.WithContext(ctx).Errorf("Failed to record password failure for user id: %d, error_reason: %v", userID, err)
+			return false, error_reason.ErrorUserDatabaseError("频率限制检查失败")
+		}
+		uc.log.WithContext(ctx).Warnf("Invalid password for user id: %d", userID)
+		return false, nil
+	}
+
+	if err := uc.authRepo.ResetPasswordFailures(ctx, userID); err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to reset password failures for user id: %d, error_reason: %v", userID, err)
+	}
+	if needsRehash {
+		uc.rehashPassword(ctx, user.ID, password)
+	}
+	return true, nil
+}
+
+// generateVerificationCode 按配置的长度和字符集生成验证码，默认6位数字
+// rand.Int 在 [0, len(charset)) 内均匀取值，每个字符等概率出现，不存在取模偏差
+func generateVerificationCode(c CodeConfig) string {
+	charset := c.charset()
+	size := big.NewInt(int64(len(charset)))
+	code := make([]byte, c.length())
 	for i := range code {
-		n, _ := rand.Int(rand.Reader, big.NewInt(10))
-		code[i] = byte(n.Int64()) + '0'
+		n, _ := rand.Int(rand.Reader, size)
+		code[i] = charset[n.Int64()]
 	}
 	return string(code)
 }
 
-// hashPassword 使用bcrypt对密码进行哈希处理
+// VerificationCodeLength 返回配置的验证码长度，超过该长度的输入可直接判定为错误
+func (uc *UserUsecase) VerificationCodeLength() int {
+	return uc.emailConfig.Code.length()
+}
+
+// hashPassword 使用配置的算法对密码进行哈希处理
 //
 // 参数:
 //   - password: 明文密码
@@ -378,12 +770,11 @@ func generateVerificationCode() string {
 // 返回值:
 //   - string: 哈希后的密码
 //   - error_reason: 错误信息
-func hashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+func (uc *UserUsecase) hashPassword(password string) (string, error) {
+	return uc.passwordHasher.Hash(password)
 }
 
-// checkPasswordHash 验证密码是否与哈希值匹配
+// checkPasswordHash 按哈希值前缀识别算法并验证密码是否匹配
 //
 // 参数:
 //   - password: 明文密码
@@ -391,9 +782,28 @@ func hashPassword(password string) (string, error) {
 //
 // 返回值:
 //   - bool: 密码是否匹配
-func checkPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+//   - bool: 哈希算法与配置不一致，需要用配置的算法重新哈希
+func (uc *UserUsecase) checkPasswordHash(password, hash string) (bool, bool) {
+	hasher := passwordHasherFor(hash)
+	if hasher == nil || !hasher.Verify(password, hash) {
+		return false, false
+	}
+	return true, hasher.Scheme() != uc.passwordHasher.Scheme()
+}
+
+// rehashPassword 登录成功后用配置的算法重新哈希密码，逐步迁移旧算法的哈希
+// 迁移失败不影响本次登录，下次登录时会再次尝试
+func (uc *UserUsecase) rehashPassword(ctx context.Context, userID int64, password string) {
+	hashed, err := uc.hashPassword(password)
+	if err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to rehash password for user id: %d, error_reason: %v", userID, err)
+		return
+	}
+	if err := uc.userRepo.UpdatePasswordHash(ctx, userID, hashed); err != nil {
+		uc.log.WithContext(ctx).Warnf("Failed to update password hash for user id: %d, error_reason: %v", userID, err)
+		return
+	}
+	uc.log.WithContext(ctx).Infof("Migrated password hash to %s for user id: %d", uc.passwordHasher.Scheme(), userID)
 }
 
 // sendVerificationEmail 发送验证码邮件
@@ -407,49 +817,69 @@ func (uc *UserUsecase) sendVerificationEmail(ctx context.Context, email, code st
 		"code_length": len(code),
 	})
 
-	// 1. 从环境变量获取 API Key
-	apiKey := os.Getenv("SENDGRID_API_KEY")
-	if apiKey == "" {
-		uc.log.WithContext(ctx).Error("SENDGRID_API_KEY environment variable is not set")
-		return error_reason.ErrorUserInternalError("邮件服务配置错误")
-	}
+	message := uc.buildVerificationEmail(email, code)
 
-	// 检查是否为测试环境（API key以"test-"开头）
-	isTestMode := strings.HasPrefix(apiKey, "test-")
-	if isTestMode {
-		uc.log.WithContext(ctx).Infof("Test mode: skipping actual email send, email: %s, code: %s", email, code)
-		return nil
+	// 发送邮件，并记录发送结果
+	uc.log.WithContext(ctx).Infof("Sending verification email to: %s", email)
+	err := uc.emailSender.Send(ctx, message)
+	uc.recordEmailLog(ctx, EmailTypeRegisterCode, email, err)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to send email: %v", err)
+		return err
 	}
 
-	// 2. 使用配置中的发件人信息
-	fromEmail := mail.NewEmail(uc.emailConfig.SenderName, uc.emailConfig.SenderEmail)
+	uc.log.WithContext(ctx).Infof("Verification email sent successfully to: %s", email)
+	return nil
+}
+
+// codeEmailCopy 验证码邮件中随用途变化的文案
+type codeEmailCopy struct {
+	// action 验证码用于完成的操作，如"注册"
+	action string
+	// greeting HTML 正文中验证码前的说明
+	greeting string
+	// ignoreHint 非本人操作时的提示
+	ignoreHint string
+}
 
-	// 3. 提取邮箱的用户名部分作为收件人称呼
-	emailPrefix := strings.Split(email, "@")[0]
-	if len(emailPrefix) > 3 {
-		// 只显示邮箱前缀的前3个字符和后缀（例如：use***@example.com）
-		emailPrefix = emailPrefix[:3] + strings.Repeat("*", len(emailPrefix)-3)
+var (
+	// registerCodeEmailCopy 注册验证码邮件文案
+	registerCodeEmailCopy = codeEmailCopy{
+		action:     "注册",
+		greeting:   "感谢您注册我们的服务。请使用下面的验证码完成注册：",
+		ignoreHint: "如果您没有进行注册操作，请忽略此邮件",
+	}
+	// resetCodeEmailCopy 找回密码验证码邮件文案
+	resetCodeEmailCopy = codeEmailCopy{
+		action:     "重置密码",
+		greeting:   "我们收到了重置您账户密码的请求。请使用下面的验证码完成重置：",
+		ignoreHint: "如果您没有申请重置密码，请忽略此邮件，您的密码不会被修改",
 	}
+)
 
-	// 4. 定义收件人
-	toEmail := mail.NewEmail(emailPrefix, email)
+// buildVerificationEmail 构建注册验证码邮件
+func (uc *UserUsecase) buildVerificationEmail(email, code string) *EmailMessage {
+	return uc.buildCodeEmail(email, code, registerCodeEmailCopy)
+}
 
-	// 5. 定义邮件主题
+// buildCodeEmail 按用途文案构建验证码邮件
+func (uc *UserUsecase) buildCodeEmail(email, code string, wording codeEmailCopy) *EmailMessage {
+	// 1. 定义邮件主题
 	subject := "您的验证码 - 请在10分钟内使用"
 
-	// 6. 构建纯文本内容
+	// 2. 构建纯文本内容
 	plainTextContent := fmt.Sprintf(`您好！
 
-您的注册验证码是：%s
+您的%s验证码是：%s
 
 此验证码将在10分钟后失效。为了保障您的账户安全，请勿将验证码告知他人。
 
-如果您没有进行注册操作，请忽略此邮件。
+%s。
 
 感谢您的使用！
-`, code)
+`, wording.action, code, wording.ignoreHint)
 
-	// 7. 构建HTML内容（使用配置中的公司信息）
+	// 3. 构建HTML内容（使用配置中的公司信息）
 	htmlContent := fmt.Sprintf(`
 <!DOCTYPE html>
 <html>
@@ -486,7 +916,7 @@ func (uc *UserUsecase) sendVerificationEmail(ctx context.Context, email, code st
         <div class="content">
             <div class="greeting">
                 您好！<br>
-                感谢您注册我们的服务。请使用下面的验证码完成注册：
+                %s
             </div>
 
             <div class="code-box">
@@ -499,7 +929,7 @@ func (uc *UserUsecase) sendVerificationEmail(ctx context.Context, email, code st
                 <div class="warning-text">
                     • 验证码将在 <strong>10 分钟</strong> 后失效<br>
                     • 请勿将验证码告知他人<br>
-                    • 如果您没有进行注册操作，请忽略此邮件
+                    • %s
                 </div>
             </div>
         </div>
@@ -512,58 +942,81 @@ func (uc *UserUsecase) sendVerificationEmail(ctx context.Context, email, code st
     </div>
 </body>
 </html>
-`, code, uc.emailConfig.SupportEmail, uc.emailConfig.SupportEmail, uc.emailConfig.CompanyName)
-
-	// 8. 构造完整的邮件对象
-	message := mail.NewSingleEmail(
-		fromEmail,
-		subject,
-		toEmail,
-		plainTextContent,
-		htmlContent,
-	)
-
-	// 9. 创建 SendGrid 客户端
-	client := sendgrid.NewSendClient(apiKey)
-
-	// 10. 发送邮件
-	uc.log.WithContext(ctx).Infof("Sending verification email to: %s", email)
-	response, err := client.Send(message)
-
-	// 11. 处理响应和错误
-	if err != nil {
-		uc.log.WithContext(ctx).Errorf("Failed to send email: %v", err)
-		return error_reason.ErrorUserInternalError("邮件发送失败")
-	}
-
-	if response.StatusCode >= 200 && response.StatusCode < 300 {
-		uc.log.WithContext(ctx).Infof("Verification email sent successfully to: %s, status: %d", email, response.StatusCode)
-		return nil
-	} else {
-		uc.log.WithContext(ctx).Errorf("Failed to send email, status: %d, body: %s", response.StatusCode, response.Body)
-		return error_reason.ErrorUserInternalError("邮件发送失败")
+`, wording.greeting, html.EscapeString(code), wording.ignoreHint, uc.emailConfig.SupportEmail, uc.emailConfig.SupportEmail, uc.emailConfig.CompanyName)
+
+	// 4. 构造完整的邮件对象（使用配置中的发件人信息，收件人称呼使用脱敏后的邮箱前缀）
+	return &EmailMessage{
+		FromName:  uc.emailConfig.SenderName,
+		FromEmail: uc.emailConfig.SenderEmail,
+		ToName:    strings.Split(maskEmail(email), "@")[0],
+		ToEmail:   email,
+		Subject:   subject,
+		PlainText: plainTextContent,
+		HTML:      htmlContent,
 	}
 }
 
-// UpdateUser 更新用户信息
-func (uc *UserUsecase) UpdateUser(ctx context.Context, id int64, req *UpdateUserRequest) error {
+// UpdateUser 更新用户信息并返回更新后的用户
+// 所有字段校验通过后才写入，由 UpdateAndGet 在同一事务中用一条 UPDATE 写入全部字段，不会出现只更新部分字段的情况
+func (uc *UserUsecase) UpdateUser(ctx context.Context, id int64, req *UpdateUserRequest) (*User, error) {
 	uc.log.WithContext(ctx).Infof("Updating user with id: %d", id)
 
 	// 参数验证
 	if req == nil {
 		uc.log.WithContext(ctx).Warn("UpdateUser request is nil")
-		return error_reason.ErrorUserInvalidRequest("更新请求不能为空")
+		return nil, error_reason.ErrorUserInvalidRequest("更新请求不能为空")
+	}
+	if req.Nickname != nil {
+		if err := ValidateNickname(*req.Nickname, uc.authConfig.MaxNicknameWidth); err != nil {
+			uc.log.WithContext(ctx).Warnf("Invalid nickname provided for user id: %d, nickname: %q, error_reason: %v", id, *req.Nickname, err)
+			return nil, err
+		}
 	}
 
-	// 更新用户信息
-	err := uc.userRepo.Update(ctx, id, req)
+	// 更新用户信息并读回最新数据
+	user, err := uc.userRepo.UpdateAndGet(ctx, id, req)
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to update user with id: %d, error_reason: %v", id, err)
-		return error_reason.ErrorUserDatabaseError("用户更新失败")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, error_reason.ErrorUserNotFound("用户不存在")
+		}
+		return nil, error_reason.ErrorUserDatabaseError("用户更新失败")
 	}
 
 	uc.log.WithContext(ctx).Infof("Successfully updated user with id: %d", id)
-	return nil
+	return user, nil
+}
+
+// ListUsersRegisteredBetween 按注册时间升序分页查询 [from, to] 内注册的用户，用于注册批次分析和新用户引导邮件
+// 时间窗口缺失或 from 晚于 to 时返回参数错误
+func (uc *UserUsecase) ListUsersRegisteredBetween(ctx context.Context, from, to time.Time, page, pageSize int) ([]*User, PageInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.ListUsersRegisteredBetween")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_users_registered_between",
+		"from":      from,
+		"to":        to,
+	})
+
+	if from.IsZero() || to.IsZero() {
+		return nil, PageInfo{}, error_reason.ErrorUserInvalidRequest("注册时间范围不能为空")
+	}
+	if from.After(to) {
+		return nil, PageInfo{}, error_reason.ErrorUserInvalidRequest("开始时间不能晚于结束时间")
+	}
+	page, pageSize, err := Pagination{}.Normalize(page, pageSize)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	users, total, err := uc.userRepo.ListByCreatedBetween(ctx, from, to, page, pageSize)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to list users registered between %s and %s, error_reason: %v", from, to, err)
+		return nil, PageInfo{}, error_reason.ErrorUserDatabaseError("用户查询失败")
+	}
+
+	return users, NewPageInfo(page, pageSize, total), nil
 }
 
 // GetUserByID 根据ID获取用户信息
@@ -576,8 +1029,8 @@ func (uc *UserUsecase) GetUserByID(ctx context.Context, id int64) (*User, error)
 		return nil, error_reason.ErrorUserInvalidRequest("无效的用户ID")
 	}
 
-	// 获取用户信息
-	user, err := uc.userRepo.GetByID(ctx, id)
+	// 获取用户信息（不加载密码哈希）
+	user, err := uc.userRepo.GetByIDPublic(ctx, id)
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("Failed to get user with id: %d, error_reason: %v", id, err)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
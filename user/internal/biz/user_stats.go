@@ -0,0 +1,34 @@
+package biz
+
+import (
+	"context"
+
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+// UserStats 查询未删除的用户总数和付费用户数，用于定期采样用户数量指标
+func (uc *UserUsecase) UserStats(ctx context.Context) (*UserStats, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.UserStats")
+	defer span.End()
+
+	stats, err := uc.userRepo.Stats(ctx)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to query user stats, error_reason: %v", err)
+		return nil, error_reason.ErrorUserDatabaseError("查询用户统计失败")
+	}
+	return stats, nil
+}
+
+// ActiveSessions 查询当前未过期的刷新令牌（会话）数量，用于定期采样活跃会话数指标
+func (uc *UserUsecase) ActiveSessions(ctx context.Context) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.ActiveSessions")
+	defer span.End()
+
+	count, err := uc.authRepo.CountActiveSessions(ctx)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("Failed to count active sessions, error_reason: %v", err)
+		return 0, error_reason.ErrorUserServiceUnavailable("令牌服务暂不可用")
+	}
+	return count, nil
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
+	kerrors "github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
+
+	error_reason "user/api/error_reason"
 )
 
 // 模拟 UserRepository
@@ -35,11 +39,51 @@ func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*Use
 	return args.Get(0).(*User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDPublic(ctx context.Context, id int64) (*User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmailPublic(ctx context.Context, email string) (*User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(*User), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, id int64, req *UpdateUserRequest) error {
 	args := m.Called(ctx, id, req)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateAndGet(ctx context.Context, id int64, req *UpdateUserRequest) (*User, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePasswordHash(ctx context.Context, id int64, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindDuplicateEmails(ctx context.Context) ([]*DuplicateEmailGroup, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*DuplicateEmailGroup), args.Error(1)
+}
+
+func (m *MockUserRepository) SoftDelete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListByCreatedBetween(ctx context.Context, from, to time.Time, page, pageSize int) ([]*User, int64, error) {
+	args := m.Called(ctx, from, to, page, pageSize)
+	return args.Get(0).([]*User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) Stats(ctx context.Context) (*UserStats, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*UserStats), args.Error(1)
+}
+
 // 模拟 CodeRepository
 type MockCodeRepository struct {
 	mock.Mock
@@ -50,21 +94,61 @@ func (m *MockCodeRepository) StoreVerificationCode(ctx context.Context, email, c
 	return args.Error(0)
 }
 
+func (m *MockCodeRepository) StoreCode(ctx context.Context, email, purpose, code string, expiresAt time.Time) error {
+	args := m.Called(ctx, email, purpose, code, expiresAt)
+	return args.Error(0)
+}
+
 func (m *MockCodeRepository) GetVerificationCode(ctx context.Context, email string) (*VerificationCode, error) {
 	args := m.Called(ctx, email)
 	return args.Get(0).(*VerificationCode), args.Error(1)
 }
 
+func (m *MockCodeRepository) ConsumeIfValid(ctx context.Context, email, purpose, candidate string) (bool, error) {
+	args := m.Called(ctx, email, purpose, candidate)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockCodeRepository) DeleteVerificationCode(ctx context.Context, email string) error {
 	args := m.Called(ctx, email)
 	return args.Error(0)
 }
 
+func (m *MockCodeRepository) ExtendVerificationCodeTTL(ctx context.Context, email, purpose string, extra time.Duration) error {
+	args := m.Called(ctx, email, purpose, extra)
+	return args.Error(0)
+}
+
 func (m *MockCodeRepository) CheckAndSetSendRateLimit(ctx context.Context, email string, duration time.Duration) (bool, error) {
 	args := m.Called(ctx, email, duration)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockCodeRepository) CheckAndIncrDailySendLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error) {
+	args := m.Called(ctx, email, limit, resetAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCodeRepository) GetVerificationCodeTTL(ctx context.Context, email string) (time.Duration, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockCodeRepository) CheckAndIncrCodeStatusLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error) {
+	args := m.Called(ctx, email, limit, resetAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCodeRepository) CheckAndIncrRegistrationAttempts(ctx context.Context, ip string, limit int, resetAt time.Time) (bool, error) {
+	args := m.Called(ctx, ip, limit, resetAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCodeRepository) CheckAndIncrTestEmailSends(ctx context.Context, userID int64, limit int, resetAt time.Time) (bool, error) {
+	args := m.Called(ctx, userID, limit, resetAt)
+	return args.Bool(0), args.Error(1)
+}
+
 // 模拟 AuthRepository
 type MockAuthRepository struct {
 	mock.Mock
@@ -75,6 +159,81 @@ func (m *MockAuthRepository) StoreRefreshToken(ctx context.Context, userID int64
 	return args.Error(0)
 }
 
+func (m *MockAuthRepository) TrackSession(ctx context.Context, userID int64, refreshToken string, createdAt, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, refreshToken, createdAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) EvictOldestSessions(ctx context.Context, userID int64, keep int) (int, error) {
+	args := m.Called(ctx, userID, keep)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) PairAccessToken(ctx context.Context, refreshTokenID, accessTokenID string, expiresAt time.Time) error {
+	args := m.Called(ctx, refreshTokenID, accessTokenID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) GetPairedAccessTokenIDs(ctx context.Context, refreshTokenID string) ([]string, error) {
+	args := m.Called(ctx, refreshTokenID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAuthRepository) CountActiveSessions(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuthRepository) GetPasswordFailures(ctx context.Context, userID int64) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) IncrPasswordFailures(ctx context.Context, userID int64, window time.Duration) (int, error) {
+	args := m.Called(ctx, userID, window)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) ResetPasswordFailures(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) TouchRefreshToken(ctx context.Context, refreshToken string, usedAt time.Time, idleTimeout time.Duration) error {
+	args := m.Called(ctx, refreshToken, usedAt, idleTimeout)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) GetRefreshTokenLastUsed(ctx context.Context, refreshToken string) (time.Time, error) {
+	args := m.Called(ctx, refreshToken)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockAuthRepository) RecordSessionIP(ctx context.Context, userID int64, refreshToken, ip string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, refreshToken, ip, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) RevokeSessionsByIP(ctx context.Context, ip string) (int, error) {
+	args := m.Called(ctx, ip)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) GetTokensValidAfter(ctx context.Context) (time.Time, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockAuthRepository) SetTokensValidAfter(ctx context.Context, validAfter time.Time) error {
+	args := m.Called(ctx, validAfter)
+	return args.Error(0)
+}
+
+// allowTokenPairing 允许签发令牌时写入访问令牌配对记录，不关心配对细节的测试使用
+func allowTokenPairing(authRepo *MockAuthRepository) {
+	authRepo.On("PairAccessToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
 func (m *MockAuthRepository) GetUserIDByRefreshToken(ctx context.Context, refreshToken string) (int64, error) {
 	args := m.Called(ctx, refreshToken)
 	return args.Get(0).(int64), args.Error(1)
@@ -90,8 +249,47 @@ func (m *MockAuthRepository) DeleteAllRefreshTokens(ctx context.Context, userID
 	return args.Error(0)
 }
 
-func (m *MockAuthRepository) RefreshTokenAtomically(ctx context.Context, userID int64, oldToken, newToken string, expiresAt time.Time) error {
-	args := m.Called(ctx, userID, oldToken, newToken, expiresAt)
+func (m *MockAuthRepository) StoreRotatedTokenPair(ctx context.Context, oldToken string, pair *TokenPair, ttl time.Duration) error {
+	args := m.Called(ctx, oldToken, pair, ttl)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) GetRotatedTokenPair(ctx context.Context, oldToken string) (*TokenPair, error) {
+	args := m.Called(ctx, oldToken)
+	return args.Get(0).(*TokenPair), args.Error(1)
+}
+
+func (m *MockAuthRepository) VerifyAndRotate(ctx context.Context, oldToken, newToken string, userID int64, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, oldToken, newToken, userID, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+// 模拟 SnowflakeIDGenerator
+type MockSnowflakeGenerator struct {
+	mock.Mock
+}
+
+func (m *MockSnowflakeGenerator) GenerateID() int64 {
+	return time.Now().UnixNano()
+}
+
+// 模拟 EmailSender
+type MockEmailSender struct {
+	mock.Mock
+}
+
+func (m *MockEmailSender) Send(ctx context.Context, message *EmailMessage) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+// 模拟 EmailLogRepository
+type MockEmailLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailLogRepository) Create(ctx context.Context, emailLog *EmailLog) error {
+	args := m.Called(ctx, emailLog)
 	return args.Error(0)
 }
 
@@ -114,6 +312,20 @@ func getTestLogger() log.Logger {
 	return log.NewStdLogger(os.Stdout)
 }
 
+// 测试用验证码 HMAC 密钥
+const (
+	testCodeHMACSecret         = "test-code-hmac-secret-for-unit-testing-only"
+	testCodeHMACPreviousSecret = "test-code-hmac-previous-secret-for-unit-testing"
+)
+
+func newTestCodeHasher() *CodeHasher {
+	return NewCodeHasherWithSecrets(testCodeHMACSecret, "")
+}
+
+func newTestSlowOperationLogger() *SlowOperationLogger {
+	return NewSlowOperationLogger(NewSystemClock(), SlowOperationConfig{}, getTestLogger())
+}
+
 // TestUserUsecase_SendRegisterCode 测试发送注册验证码
 func TestUserUsecase_SendRegisterCode(t *testing.T) {
 	setupTestEnv()
@@ -131,7 +343,7 @@ func TestUserUsecase_SendRegisterCode(t *testing.T) {
 			email: "test@example.com",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository) {
 				// 用户不存在
-				userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+				userRepo.On("GetByEmailPublic", mock.Anything, "test@example.com").
 					Return((*User)(nil), gorm.ErrRecordNotFound)
 
 				// 频率限制检查通过
@@ -149,7 +361,7 @@ func TestUserUsecase_SendRegisterCode(t *testing.T) {
 			email: "frequent@example.com",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository) {
 				// 用户不存在
-				userRepo.On("GetByEmail", mock.Anything, "frequent@example.com").
+				userRepo.On("GetByEmailPublic", mock.Anything, "frequent@example.com").
 					Return((*User)(nil), gorm.ErrRecordNotFound)
 
 				// 频率限制检查失败
@@ -157,7 +369,7 @@ func TestUserUsecase_SendRegisterCode(t *testing.T) {
 					Return(false, nil)
 			},
 			wantErr:     true,
-			expectedErr: ErrTooManyRequests,
+			expectedErr: error_reason.ErrorUserTooManyRequests("请求过于频繁，请稍后再试"),
 		},
 		{
 			name:  "邮箱为空",
@@ -166,41 +378,50 @@ func TestUserUsecase_SendRegisterCode(t *testing.T) {
 				// 不调用任何方法
 			},
 			wantErr:     true,
-			expectedErr: errors.New("email is required"),
+			expectedErr: error_reason.ErrorUserInvalidEmail("邮箱不能为空"),
+		},
+		{
+			name:  "邮箱格式错误",
+			email: "not-an-email",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository) {
+				// 格式校验失败时不查询用户和频率限制
+			},
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserInvalidEmail("邮箱格式不正确"),
 		},
 		{
 			name:  "邮箱已注册",
 			email: "existing@example.com",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository) {
 				// 用户已存在
-				userRepo.On("GetByEmail", mock.Anything, "existing@example.com").
+				userRepo.On("GetByEmailPublic", mock.Anything, "existing@example.com").
 					Return(&User{Email: "existing@example.com"}, nil)
 			},
 			wantErr:     true,
-			expectedErr: ErrEmailAlreadyExists,
+			expectedErr: error_reason.ErrorUserEmailAlreadyExists("该邮箱已被注册"),
 		},
 		{
 			name:  "数据库错误",
 			email: "db-error_reason@example.com",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository) {
-				userRepo.On("GetByEmail", mock.Anything, "db-error_reason@example.com").
+				userRepo.On("GetByEmailPublic", mock.Anything, "db-error_reason@example.com").
 					Return((*User)(nil), errors.New("database error_reason"))
 			},
 			wantErr:     true,
-			expectedErr: errors.New("database error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("数据库查询失败"),
 		},
 		{
 			name:  "频率限制错误",
 			email: "rate-limit-error_reason@example.com",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository) {
-				userRepo.On("GetByEmail", mock.Anything, "rate-limit-error_reason@example.com").
+				userRepo.On("GetByEmailPublic", mock.Anything, "rate-limit-error_reason@example.com").
 					Return((*User)(nil), gorm.ErrRecordNotFound)
 
 				codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, "rate-limit-error_reason@example.com", 60*time.Second).
 					Return(false, errors.New("redis error_reason"))
 			},
 			wantErr:     true,
-			expectedErr: errors.New("redis error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("频率限制检查失败"),
 		},
 	}
 
@@ -216,17 +437,22 @@ func TestUserUsecase_SendRegisterCode(t *testing.T) {
 				tt.setupMocks(userRepo, codeRepo)
 			}
 
+			emailSender := new(MockEmailSender)
+			emailSender.On("Send", mock.Anything, mock.Anything).Return(nil).Maybe()
+			emailLogRepo := new(MockEmailLogRepository)
+			emailLogRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
 			// 创建 usecase
-			uc := NewUserUsecase(userRepo, codeRepo, authRepo, getTestLogger(), &MockSnowflakeGenerator{mock.Mock}{})
+			uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, emailSender, emailLogRepo, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
 
 			// 执行测试
-			err := uc.SendRegisterCode(context.Background(), tt.email)
+			err := uc.SendRegisterCode(context.Background(), tt.email, "")
 
 			// 验证结果
 			if tt.wantErr {
 				assert.Error(t, err)
 				if tt.expectedErr != nil {
-					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+					assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
 				}
 			} else {
 				assert.NoError(t, err)
@@ -239,16 +465,235 @@ func TestUserUsecase_SendRegisterCode(t *testing.T) {
 	}
 }
 
+// TestUserUsecase_SendRegisterCode_DailyLimit 测试每日发送上限
+func TestUserUsecase_SendRegisterCode_DailyLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		allowed     bool
+		limitErr    error
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:    "未超过每日上限",
+			limit:   10,
+			allowed: true,
+		},
+		{
+			name:        "超过每日上限",
+			limit:       10,
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserTooManyRequests("今日发送次数已达上限，请明天再试"),
+		},
+		{
+			name:        "每日上限检查失败",
+			limit:       10,
+			limitErr:    errors.New("redis error_reason"),
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserDatabaseError("频率限制检查失败"),
+		},
+		{
+			name:    "上限为0不限制",
+			limit:   0,
+			allowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := "daily@example.com"
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+
+			userRepo.On("GetByEmailPublic", mock.Anything, email).
+				Return((*User)(nil), gorm.ErrRecordNotFound)
+			codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, email, 60*time.Second).
+				Return(true, nil)
+			if tt.limit > 0 {
+				// 计数在次日零点重置
+				codeRepo.On("CheckAndIncrDailySendLimit", mock.Anything, email, tt.limit, mock.MatchedBy(func(resetAt time.Time) bool {
+					return resetAt.After(time.Now()) && resetAt.Equal(truncateToDay(resetAt))
+				})).Return(tt.allowed, tt.limitErr)
+			}
+			if !tt.wantErr {
+				codeRepo.On("StoreVerificationCode", mock.Anything, email, mock.Anything, mock.Anything).
+					Return(nil)
+			}
+
+			emailSender := new(MockEmailSender)
+			emailSender.On("Send", mock.Anything, mock.Anything).Return(nil).Maybe()
+			emailLogRepo := new(MockEmailLogRepository)
+			emailLogRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, emailLogRepo, EmailConfig{DailySendLimit: tt.limit}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			err := uc.SendRegisterCode(context.Background(), email, "")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
+				assert.Equal(t, kerrors.FromError(tt.expectedErr).Message, kerrors.FromError(err).Message)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			codeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_SendRegisterCode_EmailLog 测试发送验证码后记录邮件发送结果
+func TestUserUsecase_SendRegisterCode_EmailLog(t *testing.T) {
+	tests := []struct {
+		name       string
+		sendErr    error
+		logErr     error
+		wantErr    func(error) bool
+		wantStatus string
+	}{
+		{
+			name:       "发送成功记录sent",
+			wantStatus: EmailLogStatusSent,
+		},
+		{
+			name:       "发送失败记录failed及错误信息",
+			sendErr:    errors.New("sendgrid responded with status 503"),
+			wantErr:    error_reason.IsUserInternalError,
+			wantStatus: EmailLogStatusFailed,
+		},
+		{
+			name:       "并发发送数已满时返回服务繁忙",
+			sendErr:    ErrEmailSenderBusy,
+			wantErr:    error_reason.IsUserServiceUnavailable,
+			wantStatus: EmailLogStatusFailed,
+		},
+		{
+			name:       "记录写入失败不影响发送结果",
+			logErr:     errors.New("database error_reason"),
+			wantStatus: EmailLogStatusSent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := "register@example.com"
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+			emailSender := new(MockEmailSender)
+			emailLogRepo := new(MockEmailLogRepository)
+
+			userRepo.On("GetByEmailPublic", mock.Anything, email).
+				Return((*User)(nil), gorm.ErrRecordNotFound)
+			codeRepo.On("CheckAndSetSendRateLimit", mock.Anything, email, 60*time.Second).
+				Return(true, nil)
+			codeRepo.On("StoreVerificationCode", mock.Anything, email, mock.Anything, mock.Anything).
+				Return(nil)
+			emailSender.On("Send", mock.Anything, mock.MatchedBy(func(m *EmailMessage) bool {
+				return m.ToEmail == email
+			})).Return(tt.sendErr)
+
+			var captured *EmailLog
+			emailLogRepo.On("Create", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					captured = args.Get(1).(*EmailLog)
+				}).
+				Return(tt.logErr)
+
+			uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, emailLogRepo, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			err := uc.SendRegisterCode(context.Background(), email, "")
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			require.NotNil(t, captured)
+			assert.Equal(t, EmailTypeRegisterCode, captured.EmailType)
+			assert.Equal(t, "reg*****@example.com", captured.MaskedEmail)
+			assert.Equal(t, tt.wantStatus, captured.Status)
+			if tt.sendErr != nil {
+				assert.Equal(t, tt.sendErr.Error(), captured.ErrorMessage)
+			} else {
+				assert.Empty(t, captured.ErrorMessage)
+			}
+
+			emailSender.AssertExpectations(t)
+			emailLogRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// setNXCodeRepository 以互斥锁模拟 Redis SETNX 的原子语义，其余方法沿用 mock
+type setNXCodeRepository struct {
+	*MockCodeRepository
+	mu  sync.Mutex
+	set map[string]bool
+}
+
+func (r *setNXCodeRepository) CheckAndSetSendRateLimit(ctx context.Context, email string, duration time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.set[email] {
+		return false, nil
+	}
+	r.set[email] = true
+	return true, nil
+}
+
+// TestUserUsecase_SendRegisterCode_Concurrent 测试同一邮箱并发发送验证码时只有一个请求真正发出邮件
+func TestUserUsecase_SendRegisterCode_Concurrent(t *testing.T) {
+	const concurrency = 20
+	email := "burst@example.com"
+
+	userRepo := new(MockUserRepository)
+	codeRepo := &setNXCodeRepository{MockCodeRepository: new(MockCodeRepository), set: map[string]bool{}}
+	emailSender := new(MockEmailSender)
+	emailLogRepo := new(MockEmailLogRepository)
+
+	// 所有请求都能通过邮箱未注册检查，只靠限流器串行化
+	userRepo.On("GetByEmailPublic", mock.Anything, email).Return((*User)(nil), gorm.ErrRecordNotFound)
+	codeRepo.On("StoreVerificationCode", mock.Anything, email, mock.Anything, mock.Anything).Return(nil).Once()
+	emailSender.On("Send", mock.Anything, mock.Anything).Return(nil).Once()
+	emailLogRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+	uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, emailLogRepo, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+	start := make(chan struct{})
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = uc.SendRegisterCode(context.Background(), email, "")
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.True(t, error_reason.IsUserTooManyRequests(err), "unexpected error: %v", err)
+	}
+	assert.Equal(t, 1, succeeded)
+	emailSender.AssertNumberOfCalls(t, "Send", 1)
+	codeRepo.AssertNumberOfCalls(t, "StoreVerificationCode", 1)
+}
+
 // TestUserUsecase_Register 测试用户注册
 func TestUserUsecase_Register(t *testing.T) {
 	setupTestEnv()
 	defer cleanupTestEnv()
 
-	validCode := &VerificationCode{
-		Email:     "test@example.com",
-		Code:      "123456",
-		ExpiresAt: time.Now().Add(10 * time.Minute),
-	}
+	validHash := newTestCodeHasher().Hash("test@example.com", "123456")
 
 	tests := []struct {
 		name        string
@@ -267,18 +712,17 @@ func TestUserUsecase_Register(t *testing.T) {
 			code:     "123456",
 			nickname: "测试用户",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
-				// 获取验证码
-				codeRepo.On("GetVerificationCode", mock.Anything, "test@example.com").
-					Return(validCode, nil)
-
-				// 删除验证码
-				codeRepo.On("DeleteVerificationCode", mock.Anything, "test@example.com").
-					Return(nil)
+				// 校验并消费验证码
+				codeRepo.On("ConsumeIfValid", mock.Anything, "test@example.com", CodePurposeRegister, validHash).
+					Return(true, nil)
 
 				// 创建用户
 				userRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *User) bool {
-					return user.Email == "test@example.com" && user.Nickname == "测试用户"
-				})).Return(nil)
+					return user.Email == "test@example.com" && user.Nickname == "测试用户" && user.EmailVerified
+				})).Run(func(args mock.Arguments) {
+					// 模拟仓库回写数据库填充的创建时间
+					args.Get(1).(*User).CreatedAt = time.Now()
+				}).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -290,7 +734,7 @@ func TestUserUsecase_Register(t *testing.T) {
 			nickname:    "",
 			setupMocks:  func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {},
 			wantErr:     true,
-			expectedErr: errors.New("email, password and code are required"),
+			expectedErr: error_reason.ErrorUserInvalidRequest("邮箱、密码和验证码为必填项"),
 		},
 		{
 			name:     "无效验证码",
@@ -299,11 +743,11 @@ func TestUserUsecase_Register(t *testing.T) {
 			code:     "wrongcode",
 			nickname: "测试用户",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
-				codeRepo.On("GetVerificationCode", mock.Anything, "test@example.com").
-					Return(validCode, nil)
+				codeRepo.On("ConsumeIfValid", mock.Anything, "test@example.com", CodePurposeRegister, newTestCodeHasher().Hash("test@example.com", "wrongcode")).
+					Return(false, nil)
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidVerificationCode,
+			expectedErr: error_reason.ErrorUserInvalidVerificationCode("验证码错误"),
 		},
 		{
 			name:     "验证码过期",
@@ -312,29 +756,46 @@ func TestUserUsecase_Register(t *testing.T) {
 			code:     "123456",
 			nickname: "测试用户",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
-				expiredCode := &VerificationCode{
-					Email:     "test@example.com",
-					Code:      "123456",
-					ExpiresAt: time.Now().Add(-1 * time.Minute), // 已过期
-				}
-				codeRepo.On("GetVerificationCode", mock.Anything, "test@example.com").
-					Return(expiredCode, nil)
+				codeRepo.On("ConsumeIfValid", mock.Anything, "test@example.com", CodePurposeRegister, validHash).
+					Return(false, ErrVerificationCodeExpired)
+			},
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserVerificationCodeExpired("验证码不存在或已过期"),
+		},
+		{
+			name:     "消费验证码失败",
+			email:    "test@example.com",
+			password: "password123",
+			code:     "123456",
+			nickname: "测试用户",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+				codeRepo.On("ConsumeIfValid", mock.Anything, "test@example.com", CodePurposeRegister, validHash).
+					Return(false, errors.New("connection refused"))
 			},
 			wantErr:     true,
-			expectedErr: ErrVerificationCodeExpired,
+			expectedErr: error_reason.ErrorUserDatabaseError("验证码校验失败"),
 		},
 		{
-			name:     "密码太短",
+			name:     "密码太短时不消费验证码",
 			email:    "test@example.com",
 			password: "123",
 			code:     "123456",
 			nickname: "测试用户",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
-				codeRepo.On("GetVerificationCode", mock.Anything, "test@example.com").
-					Return(validCode, nil)
 			},
 			wantErr:     true,
-			expectedErr: errors.New("password must be at least 6 characters long"),
+			expectedErr: error_reason.ErrorUserInvalidRequest("密码长度至少为6位"),
+		},
+		{
+			name:     "昵称包含零宽字符时不消费验证码",
+			email:    "test@example.com",
+			password: "password123",
+			code:     "123456",
+			nickname: "测试\u200d用户",
+			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
+			},
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserInvalidNickname("昵称不能包含控制字符或不可见字符"),
 		},
 		{
 			name:     "邮箱已存在（唯一约束错误）",
@@ -343,18 +804,15 @@ func TestUserUsecase_Register(t *testing.T) {
 			code:     "123456",
 			nickname: "测试用户",
 			setupMocks: func(userRepo *MockUserRepository, codeRepo *MockCodeRepository, authRepo *MockAuthRepository) {
-				codeRepo.On("GetVerificationCode", mock.Anything, "existing@example.com").
-					Return(validCode, nil)
-
-				codeRepo.On("DeleteVerificationCode", mock.Anything, "existing@example.com").
-					Return(nil)
+				codeRepo.On("ConsumeIfValid", mock.Anything, "existing@example.com", CodePurposeRegister, newTestCodeHasher().Hash("existing@example.com", "123456")).
+					Return(true, nil)
 
 				// 模拟唯一约束错误（邮箱已存在）
 				userRepo.On("Create", mock.Anything, mock.Anything).
 					Return(errors.New("Duplicate entry 'existing@example.com' for key 'email'"))
 			},
 			wantErr:     true,
-			expectedErr: ErrEmailAlreadyExists,
+			expectedErr: error_reason.ErrorUserEmailAlreadyExists("该邮箱已被注册"),
 		},
 	}
 
@@ -371,16 +829,16 @@ func TestUserUsecase_Register(t *testing.T) {
 			}
 
 			// 创建 usecase
-			uc := NewUserUsecase(userRepo, codeRepo, authRepo, getTestLogger(), &MockSnowflakeGenerator{mock.Mock}{})
+			uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
 
 			// 执行测试
-			user, err := uc.Register(context.Background(), tt.email, tt.password, tt.code, tt.nickname)
+			user, err := uc.Register(context.Background(), tt.email, tt.password, tt.code, tt.nickname, "")
 
 			// 验证结果
 			if tt.wantErr {
 				assert.Error(t, err)
 				if tt.expectedErr != nil {
-					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+					assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
 				}
 				assert.Nil(t, user)
 			} else {
@@ -390,6 +848,8 @@ func TestUserUsecase_Register(t *testing.T) {
 				assert.Equal(t, tt.nickname, user.Nickname)
 				// 密码哈希不应该返回
 				assert.Equal(t, "", user.PasswordHash)
+				// 创建时间来自仓库回写，注册响应依赖该值
+				assert.False(t, user.CreatedAt.IsZero())
 			}
 
 			// 验证所有期望都被调用
@@ -406,7 +866,7 @@ func TestUserUsecase_Login(t *testing.T) {
 	defer cleanupTestEnv()
 
 	validPassword := "password123"
-	hashedPassword, _ := hashPassword(validPassword)
+	hashedPassword, _ := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
 
 	validUser := &User{
 		ID:           1,
@@ -442,7 +902,7 @@ func TestUserUsecase_Login(t *testing.T) {
 			password:    "",
 			setupMocks:  func(userRepo *MockUserRepository, authRepo *MockAuthRepository) {},
 			wantErr:     true,
-			expectedErr: errors.New("email and password are required"),
+			expectedErr: error_reason.ErrorUserInvalidRequest("邮箱和密码为必填项"),
 		},
 		{
 			name:     "用户不存在",
@@ -453,7 +913,7 @@ func TestUserUsecase_Login(t *testing.T) {
 					Return((*User)(nil), gorm.ErrRecordNotFound)
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidCredentials,
+			expectedErr: error_reason.ErrorUserInvalidCredentials("用户名或密码错误"),
 		},
 		{
 			name:     "密码错误",
@@ -464,7 +924,7 @@ func TestUserUsecase_Login(t *testing.T) {
 					Return(validUser, nil)
 			},
 			wantErr:     true,
-			expectedErr: ErrInvalidCredentials,
+			expectedErr: error_reason.ErrorUserInvalidCredentials("用户名或密码错误"),
 		},
 		{
 			name:     "数据库错误",
@@ -475,7 +935,7 @@ func TestUserUsecase_Login(t *testing.T) {
 					Return((*User)(nil), errors.New("database error_reason"))
 			},
 			wantErr:     true,
-			expectedErr: errors.New("database error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("用户查询失败"),
 		},
 		{
 			name:     "StoreRefreshToken失败",
@@ -489,7 +949,7 @@ func TestUserUsecase_Login(t *testing.T) {
 					Return(errors.New("redis error_reason"))
 			},
 			wantErr:     true,
-			expectedErr: errors.New("redis error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("令牌存储失败"),
 		},
 	}
 
@@ -499,6 +959,7 @@ func TestUserUsecase_Login(t *testing.T) {
 			userRepo := new(MockUserRepository)
 			codeRepo := new(MockCodeRepository)
 			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
 
 			// 设置 mock 期望
 			if tt.setupMocks != nil {
@@ -506,16 +967,16 @@ func TestUserUsecase_Login(t *testing.T) {
 			}
 
 			// 创建 usecase
-			uc := NewUserUsecase(userRepo, codeRepo, authRepo, getTestLogger(), &MockSnowflakeGenerator{mock.Mock}{})
+			uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
 
 			// 执行测试
-			tokenPair, err := uc.Login(context.Background(), tt.email, tt.password)
+			tokenPair, err := uc.Login(context.Background(), tt.email, tt.password, false)
 
 			// 验证结果
 			if tt.wantErr {
 				assert.Error(t, err)
 				if tt.expectedErr != nil {
-					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+					assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
 				}
 				assert.Nil(t, tokenPair)
 			} else {
@@ -541,46 +1002,405 @@ func TestUserUsecase_Login(t *testing.T) {
 	}
 }
 
-// TestGenerateVerificationCode 测试验证码生成
-func TestGenerateVerificationCode(t *testing.T) {
-	code1 := generateVerificationCode()
-	code2 := generateVerificationCode()
+// TestUserUsecase_Login_RememberMe 测试"记住我"登录签发更长有效期的刷新令牌
+func TestUserUsecase_Login_RememberMe(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
 
-	// 验证码应该是6位数字
-	assert.Equal(t, 6, len(code1))
-	assert.Equal(t, 6, len(code2))
+	validPassword := "password123"
+	hashedPassword, _ := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
 
-	// 验证码应该是数字
-	for _, c := range code1 {
-		assert.True(t, c >= '0' && c <= '9')
-	}
-	for _, c := range code2 {
-		assert.True(t, c >= '0' && c <= '9')
+	authConfig := AuthConfig{
+		RefreshTokenTTL:           7 * 24 * time.Hour,
+		RememberMeRefreshTokenTTL: 30 * 24 * time.Hour,
 	}
 
-	// 多次生成的验证码应该不同（虽然概率很小）
-	// 注意：这个测试在极少数情况下可能失败，但概率极低
-	if code1 != code2 {
-		t.Logf("验证码生成具有随机性: %s vs %s", code1, code2)
+	tests := []struct {
+		name       string
+		rememberMe bool
+		wantTTL    time.Duration
+	}{
+		{
+			name:       "记住我使用延长的有效期",
+			rememberMe: true,
+			wantTTL:    30 * 24 * time.Hour,
+		},
+		{
+			name:       "未勾选记住我使用标准有效期",
+			rememberMe: false,
+			wantTTL:    7 * 24 * time.Hour,
+		},
 	}
-}
 
-// TestHashPassword 测试密码哈希
-func TestHashPassword(t *testing.T) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+
+			userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+				Return(&User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword}, nil)
+
+			var storedExpiresAt time.Time
+			authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					storedExpiresAt = args.Get(3).(time.Time)
+				}).
+				Return(nil)
+
+			uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.Login(context.Background(), "test@example.com", validPassword, tt.rememberMe)
+			require.NoError(t, err)
+			require.NotNil(t, tokenPair)
+
+			// 响应中的有效期与配置一致
+			assert.Equal(t, int32(tt.wantTTL/time.Second), tokenPair.RefreshExpiresIn)
+
+			// 刷新令牌的 exp 为延长后的有效期
+			claims := &jwt.RegisteredClaims{}
+			_, err = jwt.ParseWithClaims(tokenPair.RefreshToken, claims, func(token *jwt.Token) (interface{}, error) {
+				return []byte("test-refresh-secret-key-for-unit-testing-only"), nil
+			})
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(tt.wantTTL), claims.ExpiresAt.Time, 2*time.Second)
+
+			// 存入 Redis 的过期时间与令牌 exp 一致
+			assert.WithinDuration(t, claims.ExpiresAt.Time, storedExpiresAt, time.Second)
+
+			userRepo.AssertExpectations(t)
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_Login_PasswordHashMigration 测试切换哈希算法后旧哈希仍可登录，并在登录成功时迁移到配置的算法
+func TestUserUsecase_Login_PasswordHashMigration(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	validPassword := "password123"
+	bcryptHash, err := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
+	require.NoError(t, err)
+	argon2idHash, err := newPasswordHasher(PasswordHashArgon2id).Hash(validPassword)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		scheme      string
+		storedHash  string
+		password    string
+		wantRehash  bool
+		rehashErr   error
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:       "argon2id配置下bcrypt用户登录并迁移",
+			scheme:     PasswordHashArgon2id,
+			storedHash: bcryptHash,
+			password:   validPassword,
+			wantRehash: true,
+		},
+		{
+			name:       "迁移失败不影响登录",
+			scheme:     PasswordHashArgon2id,
+			storedHash: bcryptHash,
+			password:   validPassword,
+			wantRehash: true,
+			rehashErr:  errors.New("database error_reason"),
+		},
+		{
+			name:       "argon2id用户登录无需迁移",
+			scheme:     PasswordHashArgon2id,
+			storedHash: argon2idHash,
+			password:   validPassword,
+		},
+		{
+			name:       "bcrypt配置下argon2id用户仍可登录并迁回bcrypt",
+			scheme:     PasswordHashBcrypt,
+			storedHash: argon2idHash,
+			password:   validPassword,
+			wantRehash: true,
+		},
+		{
+			name:        "argon2id用户密码错误",
+			scheme:      PasswordHashArgon2id,
+			storedHash:  argon2idHash,
+			password:    "wrongpassword",
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserInvalidCredentials("用户名或密码错误"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+
+			userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+				Return(&User{ID: 1, Email: "test@example.com", PasswordHash: tt.storedHash}, nil)
+			authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).
+				Return(nil).Maybe()
+
+			var rehashed string
+			if tt.wantRehash {
+				userRepo.On("UpdatePasswordHash", mock.Anything, int64(1), mock.Anything).
+					Run(func(args mock.Arguments) {
+						rehashed = args.String(2)
+					}).
+					Return(tt.rehashErr)
+			}
+
+			authConfig := AuthConfig{RefreshTokenTTL: 7 * 24 * time.Hour, PasswordHashScheme: tt.scheme}
+			uc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.Login(context.Background(), "test@example.com", tt.password, false)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, tokenPair)
+				assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, tokenPair)
+			}
+
+			if tt.wantRehash {
+				hasher := newPasswordHasher(tt.scheme)
+				assert.True(t, hasher.Owns(rehashed), "应迁移到配置的算法: %s", rehashed)
+				assert.True(t, hasher.Verify(tt.password, rehashed))
+			}
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_Login_MaxActiveSessions 测试登录时按会话上限踢出最早的会话
+func TestUserUsecase_Login_MaxActiveSessions(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	validPassword := "password123"
+	hashedPassword, _ := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
+
+	tests := []struct {
+		name        string
+		maxSessions int
+		setupMocks  func(*MockAuthRepository)
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:        "达到上限时先踢出最早的会话",
+			maxSessions: 2,
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("EvictOldestSessions", mock.Anything, int64(1), 1).Return(1, nil).Once()
+				authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).Return(nil).Once()
+				authRepo.On("TrackSession", mock.Anything, int64(1), mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:        "未配置上限时不记录会话",
+			maxSessions: 0,
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:        "踢出会话失败",
+			maxSessions: 2,
+			setupMocks: func(authRepo *MockAuthRepository) {
+				authRepo.On("EvictOldestSessions", mock.Anything, int64(1), 1).Return(0, errors.New("redis error")).Once()
+			},
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserDatabaseError("令牌存储失败"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+				Return(&User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword}, nil)
+			tt.setupMocks(authRepo)
+
+			authConfig := AuthConfig{RefreshTokenTTL: 7 * 24 * time.Hour, MaxActiveSessions: tt.maxSessions}
+			uc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.Login(context.Background(), "test@example.com", validPassword, false)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+				assert.Nil(t, tokenPair)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, tokenPair)
+			}
+			if tt.maxSessions == 0 {
+				authRepo.AssertNotCalled(t, "EvictOldestSessions", mock.Anything, mock.Anything, mock.Anything)
+				authRepo.AssertNotCalled(t, "TrackSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_Login_RequireEmailVerification 测试开启登录前邮箱验证时，邮箱未验证的账号不能登录
+func TestUserUsecase_Login_RequireEmailVerification(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	validPassword := "password123"
+	hashedPassword, _ := newPasswordHasher(PasswordHashBcrypt).Hash(validPassword)
+
+	tests := []struct {
+		name          string
+		require       bool
+		emailVerified bool
+		password      string
+		wantErr       func(error) bool
+	}{
+		{
+			name:     "开启时未验证邮箱的账号被拒绝",
+			require:  true,
+			password: validPassword,
+			wantErr:  error_reason.IsUserEmailNotVerified,
+		},
+		{
+			name:          "开启时已验证邮箱的账号正常登录",
+			require:       true,
+			emailVerified: true,
+			password:      validPassword,
+		},
+		{
+			name:     "关闭时未验证邮箱的账号正常登录",
+			password: validPassword,
+		},
+		{
+			name:     "密码错误时不暴露邮箱验证状态",
+			require:  true,
+			password: "wrongpassword",
+			wantErr:  error_reason.IsUserInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			allowTokenPairing(authRepo)
+			userRepo.On("GetByEmail", mock.Anything, "test@example.com").
+				Return(&User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword, EmailVerified: tt.emailVerified}, nil)
+			if tt.wantErr == nil {
+				authRepo.On("StoreRefreshToken", mock.Anything, int64(1), mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			authConfig := AuthConfig{RefreshTokenTTL: 7 * 24 * time.Hour, RequireEmailVerification: tt.require}
+			uc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, authConfig, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			tokenPair, err := uc.Login(context.Background(), "test@example.com", tt.password, false)
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				assert.Nil(t, tokenPair)
+				authRepo.AssertNotCalled(t, "StoreRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, tokenPair)
+			}
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGenerateVerificationCode 测试验证码生成
+func TestGenerateVerificationCode(t *testing.T) {
+	code1 := generateVerificationCode(CodeConfig{})
+	code2 := generateVerificationCode(CodeConfig{})
+
+	// 验证码应该是6位数字
+	assert.Equal(t, 6, len(code1))
+	assert.Equal(t, 6, len(code2))
+
+	// 验证码应该是数字
+	for _, c := range code1 {
+		assert.True(t, c >= '0' && c <= '9')
+	}
+	for _, c := range code2 {
+		assert.True(t, c >= '0' && c <= '9')
+	}
+
+	// 多次生成的验证码应该不同（虽然概率很小）
+	// 注意：这个测试在极少数情况下可能失败，但概率极低
+	if code1 != code2 {
+		t.Logf("验证码生成具有随机性: %s vs %s", code1, code2)
+	}
+}
+
+// TestGenerateVerificationCode_Config 测试按配置的长度和字符集生成验证码
+func TestGenerateVerificationCode_Config(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      CodeConfig
+		wantLength  int
+		wantCharset string
+	}{
+		{
+			name:        "未配置时为6位数字",
+			wantLength:  6,
+			wantCharset: "0123456789",
+		},
+		{
+			name:        "只配置长度",
+			config:      CodeConfig{Length: 4},
+			wantLength:  4,
+			wantCharset: "0123456789",
+		},
+		{
+			name:        "去掉易混淆字符的字母数字",
+			config:      CodeConfig{Length: 8, Charset: "ABCDEFGHJKMNPQRSTUVWXYZ23456789"},
+			wantLength:  8,
+			wantCharset: "ABCDEFGHJKMNPQRSTUVWXYZ23456789",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				code := generateVerificationCode(tt.config)
+				require.Len(t, code, tt.wantLength)
+				for _, c := range code {
+					assert.Contains(t, tt.wantCharset, string(c))
+				}
+			}
+		})
+	}
+}
+
+// TestHashPassword 测试密码哈希
+func TestHashPassword(t *testing.T) {
 	password := "password123"
+	uc := NewUserUsecase(new(MockUserRepository), new(MockCodeRepository), new(MockAuthRepository), &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
 
 	// 哈希密码
-	hashedPassword, err := hashPassword(password)
+	hashedPassword, err := uc.hashPassword(password)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, hashedPassword)
 	assert.NotEqual(t, password, hashedPassword)
 
 	// 验证密码正确
-	isValid := checkPasswordHash(password, hashedPassword)
+	isValid, needsRehash := uc.checkPasswordHash(password, hashedPassword)
 	assert.True(t, isValid)
+	assert.False(t, needsRehash)
 
 	// 验证错误密码
-	isValid = checkPasswordHash("wrongpassword", hashedPassword)
+	isValid, _ = uc.checkPasswordHash("wrongpassword", hashedPassword)
 	assert.False(t, isValid)
 }
 
@@ -636,8 +1456,13 @@ func TestUserUsecase_sendVerificationEmail(t *testing.T) {
 				tt.setupMock(authRepo)
 			}
 
+			emailSender := new(MockEmailSender)
+			emailSender.On("Send", mock.Anything, mock.Anything).Return(nil).Maybe()
+			emailLogRepo := new(MockEmailLogRepository)
+			emailLogRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
 			// 创建 usecase
-			uc := NewUserUsecase(userRepo, codeRepo, authRepo, getTestLogger(), &MockSnowflakeGenerator{mock.Mock}{})
+			uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, emailSender, emailLogRepo, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
 
 			// 执行测试（这里不会实际发送邮件，因为使用的是 test API key）
 			// 在实际测试中，你可能想要 Mock SendGrid 的 HTTP 请求
@@ -666,6 +1491,7 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 		nickname    *string
 		avatarURL   *string
 		setupMocks  func(*MockUserRepository)
+		wantUser    *User
 		wantErr     bool
 		expectedErr error
 	}{
@@ -675,12 +1501,13 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 			nickname:  stringPtr("新昵称"),
 			avatarURL: nil,
 			setupMocks: func(userRepo *MockUserRepository) {
-				userRepo.On("Update", mock.Anything, int64(1), &UpdateUserRequest{
+				userRepo.On("UpdateAndGet", mock.Anything, int64(1), &UpdateUserRequest{
 					Nickname:  stringPtr("新昵称"),
 					AvatarURL: nil,
-				}).Return(nil)
+				}).Return(&User{ID: 1, Nickname: "新昵称"}, nil)
 			},
-			wantErr: false,
+			wantUser: &User{ID: 1, Nickname: "新昵称"},
+			wantErr:  false,
 		},
 		{
 			name:      "成功更新头像",
@@ -688,12 +1515,68 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 			nickname:  nil,
 			avatarURL: stringPtr("https://example.com/avatar.jpg"),
 			setupMocks: func(userRepo *MockUserRepository) {
-				userRepo.On("Update", mock.Anything, int64(1), &UpdateUserRequest{
+				userRepo.On("UpdateAndGet", mock.Anything, int64(1), &UpdateUserRequest{
 					Nickname:  nil,
 					AvatarURL: stringPtr("https://example.com/avatar.jpg"),
-				}).Return(nil)
+				}).Return(&User{ID: 1, AvatarURL: "https://example.com/avatar.jpg"}, nil)
 			},
-			wantErr: false,
+			wantUser: &User{ID: 1, AvatarURL: "https://example.com/avatar.jpg"},
+			wantErr:  false,
+		},
+		{
+			name:     "用户不存在",
+			userID:   2,
+			nickname: stringPtr("新昵称"),
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("UpdateAndGet", mock.Anything, int64(2), mock.Anything).
+					Return((*User)(nil), gorm.ErrRecordNotFound)
+			},
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserNotFound("用户不存在"),
+		},
+		{
+			name:     "数据库错误",
+			userID:   1,
+			nickname: stringPtr("新昵称"),
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("UpdateAndGet", mock.Anything, int64(1), mock.Anything).
+					Return((*User)(nil), errors.New("db error"))
+			},
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserDatabaseError("用户更新失败"),
+		},
+		{
+			name:     "中文昵称在宽度限制内",
+			userID:   1,
+			nickname: stringPtr("一二三四五六七八九十一二三四五六"),
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("UpdateAndGet", mock.Anything, int64(1), mock.Anything).
+					Return(&User{ID: 1, Nickname: "一二三四五六七八九十一二三四五六"}, nil)
+			},
+			wantUser: &User{ID: 1, Nickname: "一二三四五六七八九十一二三四五六"},
+		},
+		{
+			name:        "昵称包含零宽字符",
+			userID:      1,
+			nickname:    stringPtr("新\u200b昵称"),
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserInvalidNickname("昵称不能包含控制字符或不可见字符"),
+		},
+		{
+			name:        "中文昵称超过宽度限制",
+			userID:      1,
+			nickname:    stringPtr("一二三四五六七八九十一二三四五六七"),
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserInvalidNickname("昵称过长"),
+		},
+		{
+			// 未设置 UpdateAndGet 期望，任何写入都会使 mock 失败
+			name:        "同时更新头像时昵称校验失败不写入任何字段",
+			userID:      1,
+			nickname:    stringPtr("新\u200b昵称"),
+			avatarURL:   stringPtr("https://example.com/avatar.jpg"),
+			wantErr:     true,
+			expectedErr: error_reason.ErrorUserInvalidNickname("昵称不能包含控制字符或不可见字符"),
 		},
 	}
 
@@ -710,7 +1593,7 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 			}
 
 			// 创建 usecase
-			uc := NewUserUsecase(userRepo, codeRepo, authRepo, getTestLogger(), &MockSnowflakeGenerator{mock.Mock}{})
+			uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
 
 			// 创建更新请求
 			req := &UpdateUserRequest{
@@ -719,16 +1602,18 @@ func TestUserUsecase_UpdateUser(t *testing.T) {
 			}
 
 			// 执行测试
-			err := uc.UpdateUser(context.Background(), tt.userID, req)
+			user, err := uc.UpdateUser(context.Background(), tt.userID, req)
 
 			// 验证结果
 			if tt.wantErr {
 				assert.Error(t, err)
+				assert.Nil(t, user)
 				if tt.expectedErr != nil {
-					assert.Contains(t, err.Error(), tt.expectedErr.Error())
+					assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
 				}
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.wantUser, user)
 			}
 
 			// 验证所有期望都被调用
@@ -755,18 +1640,9 @@ func TestUserUsecase_Register_Concurrent(t *testing.T) {
 		codeRepo := new(MockCodeRepository)
 		authRepo := new(MockAuthRepository)
 
-		validCode := &VerificationCode{
-			Email:     email,
-			Code:      code,
-			ExpiresAt: time.Now().Add(10 * time.Minute),
-		}
-
 		// 设置期望：第一个成功的请求，其他请求返回唯一约束错误
-		codeRepo.On("GetVerificationCode", mock.Anything, email).
-			Return(validCode, nil).Times(numGoroutines)
-
-		codeRepo.On("DeleteVerificationCode", mock.Anything, email).
-			Return(nil).Times(numGoroutines)
+		codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeRegister, newTestCodeHasher().Hash(email, code)).
+			Return(true, nil).Times(numGoroutines)
 
 		// 模拟第一个请求成功，其他请求失败
 		userRepo.On("Create", mock.Anything, mock.Anything).
@@ -777,13 +1653,13 @@ func TestUserUsecase_Register_Concurrent(t *testing.T) {
 			}).
 			Return(nil).Once()
 
-		uc := NewUserUsecase(userRepo, codeRepo, authRepo, getTestLogger(), &MockSnowflakeGenerator{mock.Mock}{})
+		uc := NewUserUsecase(userRepo, codeRepo, authRepo, &MockSnowflakeGenerator{}, &MockEmailSender{}, &MockEmailLogRepository{}, EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
 
 		// 启动并发请求
 		errChan := make(chan error, numGoroutines)
 		for i := 0; i < numGoroutines; i++ {
 			go func() {
-				_, err := uc.Register(context.Background(), email, password, code, nickname)
+				_, err := uc.Register(context.Background(), email, password, code, nickname, "")
 				errChan <- err
 			}()
 		}
@@ -797,7 +1673,7 @@ func TestUserUsecase_Register_Concurrent(t *testing.T) {
 			err := <-errChan
 			if err == nil {
 				successCount++
-			} else if errors.Is(err, ErrEmailAlreadyExists) {
+			} else if error_reason.IsUserEmailAlreadyExists(err) {
 				duplicateCount++
 			} else {
 				otherErrors++
@@ -867,3 +1743,370 @@ func TestIsUniqueConstraintError(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+// TestUserUsecase_GetRegisterCodeStatus 测试查询注册验证码状态
+func TestUserUsecase_GetRegisterCodeStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		allowed        bool
+		limitErr       error
+		ttl            time.Duration
+		ttlErr         error
+		expectedStatus *CodeStatus
+		expectedErr    error
+	}{
+		{
+			name:           "验证码存在",
+			email:          "status@example.com",
+			allowed:        true,
+			ttl:            90*time.Second + 500*time.Millisecond,
+			expectedStatus: &CodeStatus{Exists: true, RemainingSeconds: 90},
+		},
+		{
+			name:           "验证码不存在",
+			email:          "status@example.com",
+			allowed:        true,
+			ttlErr:         ErrVerificationCodeExpired,
+			expectedStatus: &CodeStatus{Exists: false},
+		},
+		{
+			name:        "查询过于频繁",
+			email:       "status@example.com",
+			expectedErr: error_reason.ErrorUserTooManyRequests("请求过于频繁，请稍后再试"),
+		},
+		{
+			name:        "频率限制检查失败",
+			email:       "status@example.com",
+			limitErr:    errors.New("redis error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("频率限制检查失败"),
+		},
+		{
+			name:        "读取有效期失败",
+			email:       "status@example.com",
+			allowed:     true,
+			ttlErr:      errors.New("redis error_reason"),
+			expectedErr: error_reason.ErrorUserDatabaseError("验证码查询失败"),
+		},
+		{
+			name:        "邮箱格式错误",
+			email:       "invalid-email",
+			expectedErr: error_reason.ErrorUserInvalidEmail("邮箱格式不正确"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeRepo := new(MockCodeRepository)
+			if tt.email != "invalid-email" {
+				codeRepo.On("CheckAndIncrCodeStatusLimit", mock.Anything, tt.email, codeStatusQueryLimit, mock.MatchedBy(func(resetAt time.Time) bool {
+					return resetAt.After(time.Now())
+				})).Return(tt.allowed, tt.limitErr)
+			}
+			if tt.allowed {
+				codeRepo.On("GetVerificationCodeTTL", mock.Anything, tt.email).Return(tt.ttl, tt.ttlErr)
+			}
+
+			uc := NewUserUsecase(new(MockUserRepository), codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, new(MockEmailSender), new(MockEmailLogRepository), EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			status, err := uc.GetRegisterCodeStatus(context.Background(), tt.email)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Nil(t, status)
+				assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
+				assert.Equal(t, kerrors.FromError(tt.expectedErr).Message, kerrors.FromError(err).Message)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, status)
+			}
+
+			codeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_ListUsersRegisteredBetween 测试按注册时间窗口分页查询用户，分页参数按默认值规范化，时间窗口无效时不查询
+func TestUserUsecase_ListUsersRegisteredBetween(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		from, to   time.Time
+		page       int
+		pageSize   int
+		setupMocks func(userRepo *MockUserRepository)
+		wantInfo   PageInfo
+		wantCount  int
+		wantErr    func(error) bool
+	}{
+		{
+			name: "按默认分页查询",
+			from: from,
+			to:   to,
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("ListByCreatedBetween", mock.Anything, from, to, 1, defaultPageSize).
+					Return([]*User{{ID: 1}, {ID: 2}}, int64(2), nil)
+			},
+			wantInfo:  NewPageInfo(1, defaultPageSize, 2),
+			wantCount: 2,
+		},
+		{
+			name:     "每页条数超过上限时截断",
+			from:     from,
+			to:       to,
+			page:     3,
+			pageSize: defaultMaxPageSize + 1,
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("ListByCreatedBetween", mock.Anything, from, to, 3, defaultMaxPageSize).
+					Return([]*User{}, int64(250), nil)
+			},
+			wantInfo: NewPageInfo(3, defaultMaxPageSize, 250),
+		},
+		{
+			name:       "开始时间晚于结束时间",
+			from:       to,
+			to:         from,
+			setupMocks: func(userRepo *MockUserRepository) {},
+			wantErr:    error_reason.IsUserInvalidRequest,
+		},
+		{
+			name:       "时间窗口为空",
+			to:         to,
+			setupMocks: func(userRepo *MockUserRepository) {},
+			wantErr:    error_reason.IsUserInvalidRequest,
+		},
+		{
+			name: "查询失败",
+			from: from,
+			to:   to,
+			setupMocks: func(userRepo *MockUserRepository) {
+				userRepo.On("ListByCreatedBetween", mock.Anything, from, to, 1, defaultPageSize).
+					Return([]*User(nil), int64(0), errors.New("database error"))
+			},
+			wantErr: error_reason.IsUserDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			tt.setupMocks(userRepo)
+
+			uc := NewUserUsecase(userRepo, new(MockCodeRepository), new(MockAuthRepository), &MockSnowflakeGenerator{}, new(MockEmailSender), new(MockEmailLogRepository), EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			users, info, err := uc.ListUsersRegisteredBetween(context.Background(), tt.from, tt.to, tt.page, tt.pageSize)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				assert.Nil(t, users)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, users, tt.wantCount)
+				assert.Equal(t, tt.wantInfo, info)
+			}
+			// 时间窗口无效的用例未设置期望，调用仓库会直接失败
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_ExtendRegisterCode 测试延长注册验证码有效期后返回新的剩余有效期，验证码不存在或频率超限时不延长
+func TestUserUsecase_ExtendRegisterCode(t *testing.T) {
+	email := "extend@example.com"
+
+	tests := []struct {
+		name           string
+		setupMocks     func(codeRepo *MockCodeRepository)
+		expectedStatus *CodeStatus
+		wantErr        func(error) bool
+	}{
+		{
+			name: "延长成功",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrCodeStatusLimit", mock.Anything, email, codeStatusQueryLimit, mock.Anything).Return(true, nil)
+				codeRepo.On("ExtendVerificationCodeTTL", mock.Anything, email, CodePurposeRegister, registerCodeExtension).Return(nil)
+				codeRepo.On("GetVerificationCodeTTL", mock.Anything, email).Return(7*time.Minute+500*time.Millisecond, nil)
+			},
+			expectedStatus: &CodeStatus{Exists: true, RemainingSeconds: 420},
+		},
+		{
+			name: "验证码不存在",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrCodeStatusLimit", mock.Anything, email, codeStatusQueryLimit, mock.Anything).Return(true, nil)
+				codeRepo.On("ExtendVerificationCodeTTL", mock.Anything, email, CodePurposeRegister, registerCodeExtension).Return(ErrVerificationCodeExpired)
+			},
+			wantErr: error_reason.IsUserVerificationCodeExpired,
+		},
+		{
+			name: "延长失败",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrCodeStatusLimit", mock.Anything, email, codeStatusQueryLimit, mock.Anything).Return(true, nil)
+				codeRepo.On("ExtendVerificationCodeTTL", mock.Anything, email, CodePurposeRegister, registerCodeExtension).Return(errors.New("redis error_reason"))
+			},
+			wantErr: error_reason.IsUserDatabaseError,
+		},
+		{
+			name: "请求过于频繁",
+			setupMocks: func(codeRepo *MockCodeRepository) {
+				codeRepo.On("CheckAndIncrCodeStatusLimit", mock.Anything, email, codeStatusQueryLimit, mock.Anything).Return(false, nil)
+			},
+			wantErr: error_reason.IsUserTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeRepo := new(MockCodeRepository)
+			tt.setupMocks(codeRepo)
+
+			uc := NewUserUsecase(new(MockUserRepository), codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, new(MockEmailSender), new(MockEmailLogRepository), EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			status, err := uc.ExtendRegisterCode(context.Background(), email)
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				assert.Nil(t, status)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, status)
+			}
+			codeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserUsecase_VerifyPassword 测试校验当前密码
+func TestUserUsecase_VerifyPassword(t *testing.T) {
+	hashed, err := newPasswordHasher(PasswordHashBcrypt).Hash("password123")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		password    string
+		failures    int
+		getUserErr  error
+		expected    bool
+		expectedErr error
+	}{
+		{
+			name:     "密码正确",
+			password: "password123",
+			expected: true,
+		},
+		{
+			name:     "密码错误",
+			password: "wrong-password",
+			expected: false,
+		},
+		{
+			name:        "失败次数过多",
+			password:    "password123",
+			failures:    maxPasswordFailures,
+			expectedErr: error_reason.ErrorUserLoginTooMany("密码错误次数过多，请稍后再试"),
+		},
+		{
+			name:        "用户不存在",
+			password:    "password123",
+			getUserErr:  gorm.ErrRecordNotFound,
+			expectedErr: error_reason.ErrorUserNotFound("用户不存在"),
+		},
+		{
+			name:        "密码为空",
+			expectedErr: error_reason.ErrorUserInvalidRequest("密码不能为空"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			authRepo := new(MockAuthRepository)
+			if tt.password != "" {
+				authRepo.On("GetPasswordFailures", mock.Anything, int64(1)).Return(tt.failures, nil)
+			}
+			if tt.password != "" && tt.failures < maxPasswordFailures {
+				if tt.getUserErr != nil {
+					userRepo.On("GetByID", mock.Anything, int64(1)).Return((*User)(nil), tt.getUserErr)
+				} else {
+					userRepo.On("GetByID", mock.Anything, int64(1)).Return(&User{ID: 1, PasswordHash: hashed}, nil)
+				}
+			}
+			if tt.getUserErr == nil && tt.expectedErr == nil {
+				if tt.expected {
+					authRepo.On("ResetPasswordFailures", mock.Anything, int64(1)).Return(nil)
+				} else {
+					authRepo.On("IncrPasswordFailures", mock.Anything, int64(1), passwordFailureWindow).Return(1, nil)
+				}
+			}
+
+			uc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, new(MockEmailSender), new(MockEmailLogRepository), EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+
+			ok, err := uc.VerifyPassword(context.Background(), 1, tt.password)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.False(t, ok)
+				assert.Equal(t, kerrors.Reason(tt.expectedErr), kerrors.Reason(err))
+				assert.Equal(t, kerrors.FromError(tt.expectedErr).Message, kerrors.FromError(err).Message)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, ok)
+			}
+
+			userRepo.AssertExpectations(t)
+			authRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// countingPasswordFailures 在内存中累计密码校验失败次数的认证数据访问实现，用于模拟连续失败
+type countingPasswordFailures struct {
+	*MockAuthRepository
+	failures int
+}
+
+func (r *countingPasswordFailures) GetPasswordFailures(ctx context.Context, userID int64) (int, error) {
+	return r.failures, nil
+}
+
+func (r *countingPasswordFailures) IncrPasswordFailures(ctx context.Context, userID int64, window time.Duration) (int, error) {
+	r.failures++
+	return r.failures, nil
+}
+
+func (r *countingPasswordFailures) ResetPasswordFailures(ctx context.Context, userID int64) error {
+	r.failures = 0
+	return nil
+}
+
+// TestUserUsecase_VerifyPassword_Throttling 测试连续校验失败达到上限后，即使密码正确也拒绝校验
+func TestUserUsecase_VerifyPassword_Throttling(t *testing.T) {
+	hashed, err := newPasswordHasher(PasswordHashBcrypt).Hash("password123")
+	require.NoError(t, err)
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&User{ID: 1, PasswordHash: hashed}, nil)
+	authRepo := &countingPasswordFailures{MockAuthRepository: new(MockAuthRepository)}
+
+	uc := NewUserUsecase(userRepo, new(MockCodeRepository), authRepo, &MockSnowflakeGenerator{}, new(MockEmailSender), new(MockEmailLogRepository), EmailConfig{}, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+	ctx := context.Background()
+
+	for i := 1; i <= maxPasswordFailures; i++ {
+		ok, err := uc.VerifyPassword(ctx, 1, "wrong-password")
+		require.NoError(t, err, "第 %d 次错误密码不应被拦截", i)
+		assert.False(t, ok)
+	}
+
+	ok, err := uc.VerifyPassword(ctx, 1, "password123")
+	assert.False(t, ok)
+	assert.True(t, error_reason.IsUserLoginTooMany(err), "失败次数达到上限后应拒绝校验")
+	userRepo.AssertNumberOfCalls(t, "GetByID", maxPasswordFailures)
+
+	authRepo.failures = 0
+	ok, err = uc.VerifyPassword(ctx, 1, "password123")
+	require.NoError(t, err)
+	assert.True(t, ok, "计数清零后应恢复校验")
+}
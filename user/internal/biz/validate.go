@@ -0,0 +1,172 @@
+package biz
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode"
+
+	error_reason "user/api/error_reason"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"golang.org/x/text/width"
+)
+
+const (
+	// maxEmailLength 邮箱最大长度（RFC 5321 规定为254个字符）
+	maxEmailLength = 254
+	// maxEmailLocalPartLength 邮箱 @ 前本地部分的最大长度（RFC 5321 规定为64个字符）
+	maxEmailLocalPartLength = 64
+	// defaultMaxNicknameWidth 未配置 auth.max_nickname_width 时昵称的最大显示宽度
+	defaultMaxNicknameWidth = 32
+	// maxNicknameCombiningMarks 单个字符后允许连续叠加的组合符号数，超过时视为刻意堆叠的异常显示
+	maxNicknameCombiningMarks = 2
+)
+
+// emailRegex 邮箱格式正则表达式
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// ValidateEmailFormat 校验邮箱格式，service 层和 usecase 共用同一套规则
+//
+// 参数:
+//   - email: 待验证的邮箱地址
+//
+// 返回值:
+//   - error: 验证失败时返回 USER_INVALID_EMAIL 错误，验证成功时返回 nil
+func ValidateEmailFormat(email string) error {
+	if email == "" {
+		return error_reason.ErrorUserInvalidEmail("邮箱不能为空")
+	}
+
+	if len(email) > maxEmailLength {
+		return error_reason.ErrorUserInvalidEmail("邮箱长度不能超过254个字符")
+	}
+
+	if local, _, found := strings.Cut(email, "@"); found && len(local) > maxEmailLocalPartLength {
+		return error_reason.ErrorUserInvalidEmail("邮箱用户名部分不能超过64个字符")
+	}
+
+	if !emailRegex.MatchString(email) {
+		return error_reason.ErrorUserInvalidEmail("邮箱格式不正确")
+	}
+
+	return nil
+}
+
+// NormalizeEmail 返回邮箱的规范化形式（去除首尾空白并转为小写），用于大小写不敏感的比较
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// invisibleNicknameRunes 不属于控制或格式字符、但显示为空白的字符（如韩文填充符），可用来伪造空昵称或仿冒他人
+var invisibleNicknameRunes = map[rune]bool{
+	'\u115F': true, // HANGUL CHOSEONG FILLER
+	'\u1160': true, // HANGUL JUNGSEONG FILLER
+	'\u3164': true, // HANGUL FILLER
+	'\uFFA0': true, // HALFWIDTH HANGUL FILLER
+}
+
+// ValidateNickname 校验昵称只包含可见字符，且显示宽度不超过 maxWidth（不大于 0 时使用默认值）
+// 拒绝控制字符、零宽和双向控制等格式字符以及堆叠的组合符号；宽度按显示列数计算，
+// 中日韩等全角字符计为 2，组合符号随前一个字符显示、不计宽度
+func ValidateNickname(nickname string, maxWidth int) error {
+	if maxWidth <= 0 {
+		maxWidth = defaultMaxNicknameWidth
+	}
+	if strings.TrimSpace(nickname) == "" {
+		return error_reason.ErrorUserInvalidNickname("昵称不能为空")
+	}
+
+	total, marks := 0, 0
+	for i, r := range nickname {
+		switch {
+		case r == unicode.ReplacementChar:
+			return error_reason.ErrorUserInvalidNickname("昵称包含无效字符")
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r), invisibleNicknameRunes[r]:
+			return error_reason.ErrorUserInvalidNickname("昵称不能包含控制字符或不可见字符")
+		case unicode.In(r, unicode.Mn, unicode.Me):
+			marks++
+			if i == 0 || marks > maxNicknameCombiningMarks {
+				return error_reason.ErrorUserInvalidNickname("昵称包含过多组合符号")
+			}
+			continue
+		}
+		marks = 0
+		total += runeDisplayWidth(r)
+	}
+
+	if total > maxWidth {
+		return error_reason.ErrorUserInvalidNickname("昵称过长，最多%d个字符宽度（中文等全角字符计为2）", maxWidth)
+	}
+	return nil
+}
+
+// runeDisplayWidth 返回字符的显示列数，东亚宽字符和全角字符为 2，其余为 1
+func runeDisplayWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// MetadataFieldViolations 错误 metadata 中携带字段级校验失败的 key，值为 FieldViolation 的 JSON 数组
+// 放在 metadata 中可经过错误增强中间件原样保留，由 server 层转换为 gRPC 的 BadRequest 详情和 HTTP 的 details 数组
+const MetadataFieldViolations = "field_violations"
+
+// FieldViolation 单个字段的校验失败
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ValidationError 收集多个字段的校验失败，一次性返回给客户端，避免逐个修正后反复提交
+type ValidationError struct {
+	first      *kerrors.Error
+	violations []FieldViolation
+}
+
+// Add 记录字段 field 的校验错误，err 为 nil 时忽略
+func (v *ValidationError) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+	e := kerrors.FromError(err)
+	if v.first == nil {
+		v.first = e
+	}
+	v.violations = append(v.violations, FieldViolation{Field: field, Description: e.Message})
+}
+
+// Err 没有校验失败时返回 nil，否则返回第一个失败字段的错误（保持原有的错误原因和信息），
+// 并在 metadata 中附带全部字段的校验失败
+func (v *ValidationError) Err() error {
+	if v.first == nil {
+		return nil
+	}
+	data, _ := json.Marshal(v.violations)
+	metadata := make(map[string]string, len(v.first.Metadata)+1)
+	for k, val := range v.first.Metadata {
+		metadata[k] = val
+	}
+	metadata[MetadataFieldViolations] = string(data)
+	return v.first.WithMetadata(metadata)
+}
+
+// FieldViolationsFromError 解析错误 metadata 中的字段级校验失败，没有时返回 nil
+func FieldViolationsFromError(err error) []FieldViolation {
+	e := kerrors.FromError(err)
+	if e == nil {
+		return nil
+	}
+	raw, ok := e.Metadata[MetadataFieldViolations]
+	if !ok {
+		return nil
+	}
+	var violations []FieldViolation
+	if json.Unmarshal([]byte(raw), &violations) != nil {
+		return nil
+	}
+	return violations
+}
@@ -0,0 +1,211 @@
+package biz
+
+import (
+	"strings"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	error_reason "user/api/error_reason"
+)
+
+// TestValidateEmailFormat 测试邮箱格式校验
+func TestValidateEmailFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		wantErr     bool
+		wantMessage string
+	}{
+		{
+			name:    "合法邮箱",
+			email:   "test@example.com",
+			wantErr: false,
+		},
+		{
+			name:    "带加号和子域名的合法邮箱",
+			email:   "test+tag@mail.example-domain.co.uk",
+			wantErr: false,
+		},
+		{
+			name:        "空邮箱",
+			email:       "",
+			wantErr:     true,
+			wantMessage: "邮箱不能为空",
+		},
+		{
+			name:        "总长度超过254个字符",
+			email:       strings.Repeat("a", 60) + "@" + strings.Repeat("b", 190) + ".com",
+			wantErr:     true,
+			wantMessage: "邮箱长度不能超过254个字符",
+		},
+		{
+			name:        "本地部分超过64个字符",
+			email:       strings.Repeat("a", 65) + "@example.com",
+			wantErr:     true,
+			wantMessage: "邮箱用户名部分不能超过64个字符",
+		},
+		{
+			name:        "缺少@",
+			email:       "test.example.com",
+			wantErr:     true,
+			wantMessage: "邮箱格式不正确",
+		},
+		{
+			name:        "缺少顶级域名",
+			email:       "test@example",
+			wantErr:     true,
+			wantMessage: "邮箱格式不正确",
+		},
+		{
+			name:        "包含空格",
+			email:       "te st@example.com",
+			wantErr:     true,
+			wantMessage: "邮箱格式不正确",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEmailFormat(tt.email)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, error_reason.IsUserInvalidEmail(err))
+			assert.Equal(t, tt.wantMessage, kerrors.FromError(err).Message)
+		})
+	}
+}
+
+// TestValidateNickname 测试昵称拒绝不可见字符，并按显示宽度而非字节或字符数限制长度
+func TestValidateNickname(t *testing.T) {
+	tests := []struct {
+		name        string
+		nickname    string
+		maxWidth    int
+		wantErr     bool
+		wantMessage string
+	}{
+		{
+			name:     "普通英文昵称",
+			nickname: "tester",
+		},
+		{
+			name:     "中文昵称在宽度限制内",
+			nickname: "测试用户",
+			maxWidth: 8,
+		},
+		{
+			name:     "带组合符号的昵称",
+			nickname: "Jose\u0301",
+			maxWidth: 4,
+		},
+		{
+			name:        "中文昵称超过宽度限制",
+			nickname:    "测试用户名",
+			maxWidth:    8,
+			wantErr:     true,
+			wantMessage: "昵称过长，最多8个字符宽度（中文等全角字符计为2）",
+		},
+		{
+			name:        "未配置时使用默认宽度",
+			nickname:    strings.Repeat("测", 17),
+			wantErr:     true,
+			wantMessage: "昵称过长，最多32个字符宽度（中文等全角字符计为2）",
+		},
+		{
+			name:        "包含零宽空格",
+			nickname:    "admin\u200b",
+			wantErr:     true,
+			wantMessage: "昵称不能包含控制字符或不可见字符",
+		},
+		{
+			name:        "包含零宽连接符",
+			nickname:    "ad\u200dmin",
+			wantErr:     true,
+			wantMessage: "昵称不能包含控制字符或不可见字符",
+		},
+		{
+			name:        "包含双向控制字符",
+			nickname:    "user\u202egnp.exe",
+			wantErr:     true,
+			wantMessage: "昵称不能包含控制字符或不可见字符",
+		},
+		{
+			name:        "包含换行",
+			nickname:    "line1\nline2",
+			wantErr:     true,
+			wantMessage: "昵称不能包含控制字符或不可见字符",
+		},
+		{
+			name:        "只有韩文填充符",
+			nickname:    "\u3164",
+			wantErr:     true,
+			wantMessage: "昵称不能包含控制字符或不可见字符",
+		},
+		{
+			name:        "堆叠组合符号",
+			nickname:    "a\u0301\u0302\u0303",
+			wantErr:     true,
+			wantMessage: "昵称包含过多组合符号",
+		},
+		{
+			name:        "以组合符号开头",
+			nickname:    "\u0301abc",
+			wantErr:     true,
+			wantMessage: "昵称包含过多组合符号",
+		},
+		{
+			name:        "只有空白",
+			nickname:    "   ",
+			wantErr:     true,
+			wantMessage: "昵称不能为空",
+		},
+		{
+			name:        "非法UTF-8",
+			nickname:    "ab\xffcd",
+			wantErr:     true,
+			wantMessage: "昵称包含无效字符",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNickname(tt.nickname, tt.maxWidth)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, error_reason.IsUserInvalidNickname(err), "unexpected error: %v", err)
+			assert.Equal(t, tt.wantMessage, kerrors.FromError(err).Message)
+		})
+	}
+}
+
+// TestValidationError 测试收集多个字段的校验失败，返回第一个失败字段的错误原因并在 metadata 中附带全部字段
+func TestValidationError(t *testing.T) {
+	var empty ValidationError
+	empty.Add("email", nil)
+	assert.NoError(t, empty.Err())
+
+	var v ValidationError
+	v.Add("password", error_reason.ErrorUserInvalidRequest("密码长度至少8位"))
+	v.Add("email", nil)
+	v.Add("email", error_reason.ErrorUserInvalidEmail("邮箱格式不正确"))
+
+	err := v.Err()
+	assert.True(t, error_reason.IsUserInvalidRequest(err))
+	assert.Equal(t, "密码长度至少8位", kerrors.FromError(err).Message)
+	assert.Equal(t, []FieldViolation{
+		{Field: "password", Description: "密码长度至少8位"},
+		{Field: "email", Description: "邮箱格式不正确"},
+	}, FieldViolationsFromError(err))
+
+	assert.Nil(t, FieldViolationsFromError(error_reason.ErrorUserInvalidEmail("邮箱格式不正确")))
+}
@@ -0,0 +1,134 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"user/internal/pkg/tracing"
+)
+
+const (
+	// welcomeEmailMeterName 欢迎邮件指标所属的 meter
+	welcomeEmailMeterName = "user/internal/biz"
+	// welcomeEmailSendsMetric 按结果（outcome 标签：success/failure）统计的欢迎邮件发送次数指标
+	welcomeEmailSendsMetric = "welcome_email_sends_total"
+)
+
+// newWelcomeEmailCounter 创建欢迎邮件发送结果计数器，meter 为空时使用全局 MeterProvider
+func newWelcomeEmailCounter(meter metric.Meter, logger log.Logger) metric.Int64Counter {
+	if meter == nil {
+		meter = otel.Meter(welcomeEmailMeterName)
+	}
+	counter, err := meter.Int64Counter(welcomeEmailSendsMetric,
+		metric.WithDescription("Number of welcome emails sent by outcome"))
+	if err != nil {
+		// 指标创建失败不影响发送本身
+		log.NewHelper(logger).Errorf("Failed to create welcome email counter, error_reason: %v", err)
+		return noop.Int64Counter{}
+	}
+	return counter
+}
+
+// SendWelcomeEmail 向用户发送欢迎邮件，注册成功后自动调用（需开启 email.welcome_email_enabled），也可用于补发
+// 发送结果写入邮件发送记录并累加 welcome_email_sends_total 指标
+func (uc *UserUsecase) SendWelcomeEmail(ctx context.Context, user *User) error {
+	ctx, span := tracing.StartSpan(ctx, "UserUsecase.SendWelcomeEmail")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "send_welcome_email",
+		"user_id":   user.ID,
+	})
+
+	message := uc.buildWelcomeEmail(user)
+
+	uc.log.WithContext(ctx).Infof("Sending welcome email to user id: %d", user.ID)
+	err := uc.emailSender.Send(ctx, message)
+	uc.recordEmailLog(ctx, EmailTypeWelcome, user.Email, err)
+
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	uc.welcomeEmails.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+	if err != nil {
+		return err
+	}
+
+	uc.log.WithContext(ctx).Infof("Welcome email sent successfully to user id: %d", user.ID)
+	return nil
+}
+
+// buildWelcomeEmail 构建欢迎邮件，与验证码邮件使用相同的发件人和公司信息
+func (uc *UserUsecase) buildWelcomeEmail(user *User) *EmailMessage {
+	appName := uc.emailConfig.AppName
+	subject := fmt.Sprintf("欢迎加入%s", appName)
+
+	plainTextContent := fmt.Sprintf(`%s，您好！
+
+欢迎加入%s，您的账号已注册成功，现在可以使用邮箱 %s 登录。
+
+如有任何问题，请联系 %s。
+
+感谢您的使用！
+`, user.Nickname, appName, user.Email, uc.emailConfig.SupportEmail)
+
+	// 昵称由用户填写，写入 HTML 前需要转义
+	htmlContent := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>欢迎加入</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', 'PingFang SC', 'Hiragino Sans GB', 'Microsoft YaHei', sans-serif; background-color: #f4f4f4; }
+        .container { max-width: 600px; margin: 40px auto; background-color: #ffffff; border-radius: 8px; overflow: hidden; box-shadow: 0 2px 10px rgba(0,0,0,0.1); }
+        .header { background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); padding: 40px 30px; text-align: center; color: white; }
+        .header h1 { font-size: 28px; margin-bottom: 10px; font-weight: 600; }
+        .content { padding: 40px 30px; font-size: 16px; color: #333; line-height: 1.6; }
+        .footer { background-color: #f8f9fa; padding: 25px 30px; text-align: center; color: #666; font-size: 13px; line-height: 1.6; }
+        .footer a { color: #667eea; text-decoration: none; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🎉 欢迎加入%s</h1>
+        </div>
+
+        <div class="content">
+            %s，您好！<br>
+            您的账号已注册成功，现在可以使用邮箱 <strong>%s</strong> 登录。
+        </div>
+
+        <div class="footer">
+            <p>此邮件由系统自动发送，请勿直接回复。</p>
+            <p>如有问题请联系 <a href="mailto:%s">%s</a></p>
+            <p style="margin-top: 15px; color: #999;">© 2025 %s. 保留所有权利。</p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(appName), html.EscapeString(user.Nickname), html.EscapeString(user.Email),
+		uc.emailConfig.SupportEmail, uc.emailConfig.SupportEmail, uc.emailConfig.CompanyName)
+
+	return &EmailMessage{
+		FromName:  uc.emailConfig.SenderName,
+		FromEmail: uc.emailConfig.SenderEmail,
+		ToName:    strings.Split(maskEmail(user.Email), "@")[0],
+		ToEmail:   user.Email,
+		Subject:   subject,
+		PlainText: plainTextContent,
+		HTML:      htmlContent,
+	}
+}
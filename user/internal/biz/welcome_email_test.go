@@ -0,0 +1,116 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// outcomeCounter 按 outcome 标签记录累加值的测试计数器
+type outcomeCounter struct {
+	noop.Int64Counter
+	counts map[string]int64
+}
+
+func (c *outcomeCounter) Add(_ context.Context, incr int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	outcome, _ := attrs.Value(attribute.Key("outcome"))
+	c.counts[outcome.AsString()] += incr
+}
+
+// TestUserUsecase_Register_WelcomeEmail 测试开启欢迎邮件时注册成功后发送，关闭时跳过，发送失败不影响注册
+func TestUserUsecase_Register_WelcomeEmail(t *testing.T) {
+	setupTestEnv()
+	defer cleanupTestEnv()
+
+	email := "welcome@example.com"
+	validHash := newTestCodeHasher().Hash(email, "123456")
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		sendErr     error
+		wantSent    bool
+		wantOutcome string
+	}{
+		{
+			name:        "开启时发送欢迎邮件",
+			enabled:     true,
+			wantSent:    true,
+			wantOutcome: "success",
+		},
+		{
+			name: "关闭时不发送",
+		},
+		{
+			name:        "发送失败不影响注册",
+			enabled:     true,
+			sendErr:     errors.New("sendgrid responded with status 503"),
+			wantSent:    true,
+			wantOutcome: "failure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			codeRepo := new(MockCodeRepository)
+			emailSender := new(MockEmailSender)
+			emailLogRepo := new(MockEmailLogRepository)
+
+			codeRepo.On("ConsumeIfValid", mock.Anything, email, CodePurposeRegister, validHash).Return(true, nil)
+			userRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			var sent *EmailMessage
+			var logged *EmailLog
+			if tt.wantSent {
+				emailSender.On("Send", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						sent = args.Get(1).(*EmailMessage)
+					}).
+					Return(tt.sendErr).Once()
+				emailLogRepo.On("Create", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						logged = args.Get(1).(*EmailLog)
+					}).
+					Return(nil).Once()
+			}
+
+			emailConfig := EmailConfig{AppName: "绘本", WelcomeEmailEnabled: tt.enabled}
+			uc := NewUserUsecase(userRepo, codeRepo, new(MockAuthRepository), &MockSnowflakeGenerator{}, emailSender, emailLogRepo, emailConfig, AuthConfig{}, newTestCodeHasher(), nil, newTestSlowOperationLogger(), getTestLogger())
+			counter := &outcomeCounter{counts: map[string]int64{}}
+			uc.welcomeEmails = counter
+
+			user, err := uc.Register(context.Background(), email, "password123", "123456", "<b>新人</b>", "")
+
+			require.NoError(t, err)
+			require.NotNil(t, user)
+			if tt.wantSent {
+				require.NotNil(t, sent)
+				assert.Equal(t, email, sent.ToEmail)
+				assert.Equal(t, "欢迎加入绘本", sent.Subject)
+				// 欢迎邮件不包含验证码内容
+				assert.NotContains(t, sent.PlainText, "验证码")
+				// 用户填写的昵称在 HTML 中被转义
+				assert.Contains(t, sent.HTML, "&lt;b&gt;新人&lt;/b&gt;")
+				assert.False(t, strings.Contains(sent.HTML, "<b>新人</b>"))
+				require.NotNil(t, logged)
+				assert.Equal(t, EmailTypeWelcome, logged.EmailType)
+				assert.Equal(t, map[string]int64{tt.wantOutcome: 1}, counter.counts)
+			} else {
+				emailSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+				assert.Empty(t, counter.counts)
+			}
+			emailSender.AssertExpectations(t)
+			emailLogRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -28,6 +28,10 @@ type Bootstrap struct {
 	Data          *Data                  `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
 	Trace         *Trace                 `protobuf:"bytes,3,opt,name=trace,proto3" json:"trace,omitempty"`
 	Email         *Email                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Auth          *Auth                  `protobuf:"bytes,5,opt,name=auth,proto3" json:"auth,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,6,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	Biz           *Biz                   `protobuf:"bytes,7,opt,name=biz,proto3" json:"biz,omitempty"`
+	Log           *Log                   `protobuf:"bytes,8,opt,name=log,proto3" json:"log,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,12 +94,46 @@ func (x *Bootstrap) GetEmail() *Email {
 	return nil
 }
 
+func (x *Bootstrap) GetAuth() *Auth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetBiz() *Biz {
+	if x != nil {
+		return x.Biz
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetLog() *Log {
+	if x != nil {
+		return x.Log
+	}
+	return nil
+}
+
 type Server struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
-	Grpc          *Server_GRPC           `protobuf:"bytes,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Http               *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
+	Grpc               *Server_GRPC           `protobuf:"bytes,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
+	EnableGreeter      bool                   `protobuf:"varint,3,opt,name=enable_greeter,json=enableGreeter,proto3" json:"enable_greeter,omitempty"`
+	InternetFacing     bool                   `protobuf:"varint,4,opt,name=internet_facing,json=internetFacing,proto3" json:"internet_facing,omitempty"`
+	AccountIdFormat    string                 `protobuf:"bytes,5,opt,name=account_id_format,json=accountIdFormat,proto3" json:"account_id_format,omitempty"`
+	ExposeErrorDetails bool                   `protobuf:"varint,6,opt,name=expose_error_details,json=exposeErrorDetails,proto3" json:"expose_error_details,omitempty"`
+	TrustedProxies     []string               `protobuf:"bytes,7,rep,name=trusted_proxies,json=trustedProxies,proto3" json:"trusted_proxies,omitempty"`
+	TraceContextPolicy string                 `protobuf:"bytes,8,opt,name=trace_context_policy,json=traceContextPolicy,proto3" json:"trace_context_policy,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Server) Reset() {
@@ -142,12 +180,57 @@ func (x *Server) GetGrpc() *Server_GRPC {
 	return nil
 }
 
+func (x *Server) GetEnableGreeter() bool {
+	if x != nil {
+		return x.EnableGreeter
+	}
+	return false
+}
+
+func (x *Server) GetInternetFacing() bool {
+	if x != nil {
+		return x.InternetFacing
+	}
+	return false
+}
+
+func (x *Server) GetAccountIdFormat() string {
+	if x != nil {
+		return x.AccountIdFormat
+	}
+	return ""
+}
+
+func (x *Server) GetExposeErrorDetails() bool {
+	if x != nil {
+		return x.ExposeErrorDetails
+	}
+	return false
+}
+
+func (x *Server) GetTrustedProxies() []string {
+	if x != nil {
+		return x.TrustedProxies
+	}
+	return nil
+}
+
+func (x *Server) GetTraceContextPolicy() string {
+	if x != nil {
+		return x.TraceContextPolicy
+	}
+	return ""
+}
+
 type Data struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Database      *Data_Database         `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
-	Redis         *Data_Redis            `protobuf:"bytes,2,opt,name=redis,proto3" json:"redis,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Database             *Data_Database         `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Redis                *Data_Redis            `protobuf:"bytes,2,opt,name=redis,proto3" json:"redis,omitempty"`
+	CacheDriver          string                 `protobuf:"bytes,3,opt,name=cache_driver,json=cacheDriver,proto3" json:"cache_driver,omitempty"`
+	MaxCodeTtl           *durationpb.Duration   `protobuf:"bytes,4,opt,name=max_code_ttl,json=maxCodeTtl,proto3" json:"max_code_ttl,omitempty"`
+	MaxPointTransactions int32                  `protobuf:"varint,5,opt,name=max_point_transactions,json=maxPointTransactions,proto3" json:"max_point_transactions,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *Data) Reset() {
@@ -194,6 +277,27 @@ func (x *Data) GetRedis() *Data_Redis {
 	return nil
 }
 
+func (x *Data) GetCacheDriver() string {
+	if x != nil {
+		return x.CacheDriver
+	}
+	return ""
+}
+
+func (x *Data) GetMaxCodeTtl() *durationpb.Duration {
+	if x != nil {
+		return x.MaxCodeTtl
+	}
+	return nil
+}
+
+func (x *Data) GetMaxPointTransactions() int32 {
+	if x != nil {
+		return x.MaxPointTransactions
+	}
+	return 0
+}
+
 type Trace struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
@@ -263,14 +367,24 @@ func (x *Trace) GetBatcher() string {
 }
 
 type Email struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SenderName    string                 `protobuf:"bytes,1,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`
-	SenderEmail   string                 `protobuf:"bytes,2,opt,name=sender_email,json=senderEmail,proto3" json:"sender_email,omitempty"`
-	SupportEmail  string                 `protobuf:"bytes,3,opt,name=support_email,json=supportEmail,proto3" json:"support_email,omitempty"`
-	CompanyName   string                 `protobuf:"bytes,4,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
-	AppName       string                 `protobuf:"bytes,5,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	SenderName            string                 `protobuf:"bytes,1,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`
+	SenderEmail           string                 `protobuf:"bytes,2,opt,name=sender_email,json=senderEmail,proto3" json:"sender_email,omitempty"`
+	SupportEmail          string                 `protobuf:"bytes,3,opt,name=support_email,json=supportEmail,proto3" json:"support_email,omitempty"`
+	CompanyName           string                 `protobuf:"bytes,4,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
+	AppName               string                 `protobuf:"bytes,5,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	DailySendLimit        int32                  `protobuf:"varint,6,opt,name=daily_send_limit,json=dailySendLimit,proto3" json:"daily_send_limit,omitempty"`
+	SendTimeout           *durationpb.Duration   `protobuf:"bytes,7,opt,name=send_timeout,json=sendTimeout,proto3" json:"send_timeout,omitempty"`
+	MaxConcurrentSends    int32                  `protobuf:"varint,8,opt,name=max_concurrent_sends,json=maxConcurrentSends,proto3" json:"max_concurrent_sends,omitempty"`
+	FailFastWhenSaturated bool                   `protobuf:"varint,9,opt,name=fail_fast_when_saturated,json=failFastWhenSaturated,proto3" json:"fail_fast_when_saturated,omitempty"`
+	Providers             []string               `protobuf:"bytes,10,rep,name=providers,proto3" json:"providers,omitempty"`
+	Smtp                  *Email_SMTP            `protobuf:"bytes,11,opt,name=smtp,proto3" json:"smtp,omitempty"`
+	WelcomeEmailEnabled   bool                   `protobuf:"varint,12,opt,name=welcome_email_enabled,json=welcomeEmailEnabled,proto3" json:"welcome_email_enabled,omitempty"`
+	SubjectPrefix         string                 `protobuf:"bytes,13,opt,name=subject_prefix,json=subjectPrefix,proto3" json:"subject_prefix,omitempty"`
+	CodeLength            int32                  `protobuf:"varint,14,opt,name=code_length,json=codeLength,proto3" json:"code_length,omitempty"`
+	CodeCharset           string                 `protobuf:"bytes,15,opt,name=code_charset,json=codeCharset,proto3" json:"code_charset,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *Email) Reset() {
@@ -338,18 +452,602 @@ func (x *Email) GetAppName() string {
 	return ""
 }
 
-type Server_HTTP struct {
+func (x *Email) GetDailySendLimit() int32 {
+	if x != nil {
+		return x.DailySendLimit
+	}
+	return 0
+}
+
+func (x *Email) GetSendTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.SendTimeout
+	}
+	return nil
+}
+
+func (x *Email) GetMaxConcurrentSends() int32 {
+	if x != nil {
+		return x.MaxConcurrentSends
+	}
+	return 0
+}
+
+func (x *Email) GetFailFastWhenSaturated() bool {
+	if x != nil {
+		return x.FailFastWhenSaturated
+	}
+	return false
+}
+
+func (x *Email) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *Email) GetSmtp() *Email_SMTP {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
+func (x *Email) GetWelcomeEmailEnabled() bool {
+	if x != nil {
+		return x.WelcomeEmailEnabled
+	}
+	return false
+}
+
+func (x *Email) GetSubjectPrefix() string {
+	if x != nil {
+		return x.SubjectPrefix
+	}
+	return ""
+}
+
+func (x *Email) GetCodeLength() int32 {
+	if x != nil {
+		return x.CodeLength
+	}
+	return 0
+}
+
+func (x *Email) GetCodeCharset() string {
+	if x != nil {
+		return x.CodeCharset
+	}
+	return ""
+}
+
+type Auth struct {
+	state                        protoimpl.MessageState `protogen:"open.v1"`
+	RefreshTokenTtl              *durationpb.Duration   `protobuf:"bytes,1,opt,name=refresh_token_ttl,json=refreshTokenTtl,proto3" json:"refresh_token_ttl,omitempty"`
+	RememberMeRefreshTokenTtl    *durationpb.Duration   `protobuf:"bytes,2,opt,name=remember_me_refresh_token_ttl,json=rememberMeRefreshTokenTtl,proto3" json:"remember_me_refresh_token_ttl,omitempty"`
+	AdminUserIds                 []int64                `protobuf:"varint,3,rep,packed,name=admin_user_ids,json=adminUserIds,proto3" json:"admin_user_ids,omitempty"`
+	EnrichAccessToken            bool                   `protobuf:"varint,4,opt,name=enrich_access_token,json=enrichAccessToken,proto3" json:"enrich_access_token,omitempty"`
+	MaxActiveSessions            int32                  `protobuf:"varint,5,opt,name=max_active_sessions,json=maxActiveSessions,proto3" json:"max_active_sessions,omitempty"`
+	CodeHmacSecret               string                 `protobuf:"bytes,6,opt,name=code_hmac_secret,json=codeHmacSecret,proto3" json:"code_hmac_secret,omitempty"`
+	CodeHmacPreviousSecret       string                 `protobuf:"bytes,7,opt,name=code_hmac_previous_secret,json=codeHmacPreviousSecret,proto3" json:"code_hmac_previous_secret,omitempty"`
+	RefreshRotationThreshold     float64                `protobuf:"fixed64,8,opt,name=refresh_rotation_threshold,json=refreshRotationThreshold,proto3" json:"refresh_rotation_threshold,omitempty"`
+	PasswordHashScheme           string                 `protobuf:"bytes,9,opt,name=password_hash_scheme,json=passwordHashScheme,proto3" json:"password_hash_scheme,omitempty"`
+	BindTokenToClient            bool                   `protobuf:"varint,10,opt,name=bind_token_to_client,json=bindTokenToClient,proto3" json:"bind_token_to_client,omitempty"`
+	RefreshGracePeriod           *durationpb.Duration   `protobuf:"bytes,11,opt,name=refresh_grace_period,json=refreshGracePeriod,proto3" json:"refresh_grace_period,omitempty"`
+	MinSigningKeyLength          int32                  `protobuf:"varint,12,opt,name=min_signing_key_length,json=minSigningKeyLength,proto3" json:"min_signing_key_length,omitempty"`
+	RegistrationCaptchaThreshold int32                  `protobuf:"varint,13,opt,name=registration_captcha_threshold,json=registrationCaptchaThreshold,proto3" json:"registration_captcha_threshold,omitempty"`
+	RegistrationCaptchaWindow    *durationpb.Duration   `protobuf:"bytes,14,opt,name=registration_captcha_window,json=registrationCaptchaWindow,proto3" json:"registration_captcha_window,omitempty"`
+	AccessRefreshThreshold       float64                `protobuf:"fixed64,15,opt,name=access_refresh_threshold,json=accessRefreshThreshold,proto3" json:"access_refresh_threshold,omitempty"`
+	RefreshIdleTimeout           *durationpb.Duration   `protobuf:"bytes,16,opt,name=refresh_idle_timeout,json=refreshIdleTimeout,proto3" json:"refresh_idle_timeout,omitempty"`
+	MaxNicknameWidth             int32                  `protobuf:"varint,17,opt,name=max_nickname_width,json=maxNicknameWidth,proto3" json:"max_nickname_width,omitempty"`
+	RequireEmailVerification     bool                   `protobuf:"varint,18,opt,name=require_email_verification,json=requireEmailVerification,proto3" json:"require_email_verification,omitempty"`
+	EnforceTokensValidAfter      bool                   `protobuf:"varint,19,opt,name=enforce_tokens_valid_after,json=enforceTokensValidAfter,proto3" json:"enforce_tokens_valid_after,omitempty"`
+	MaxPersonalTokens            int32                  `protobuf:"varint,20,opt,name=max_personal_tokens,json=maxPersonalTokens,proto3" json:"max_personal_tokens,omitempty"`
+	unknownFields                protoimpl.UnknownFields
+	sizeCache                    protoimpl.SizeCache
+}
+
+func (x *Auth) Reset() {
+	*x = Auth{}
+	mi := &file_conf_conf_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Auth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auth) ProtoMessage() {}
+
+func (x *Auth) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Auth.ProtoReflect.Descriptor instead.
+func (*Auth) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Auth) GetRefreshTokenTtl() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshTokenTtl
+	}
+	return nil
+}
+
+func (x *Auth) GetRememberMeRefreshTokenTtl() *durationpb.Duration {
+	if x != nil {
+		return x.RememberMeRefreshTokenTtl
+	}
+	return nil
+}
+
+func (x *Auth) GetAdminUserIds() []int64 {
+	if x != nil {
+		return x.AdminUserIds
+	}
+	return nil
+}
+
+func (x *Auth) GetEnrichAccessToken() bool {
+	if x != nil {
+		return x.EnrichAccessToken
+	}
+	return false
+}
+
+func (x *Auth) GetMaxActiveSessions() int32 {
+	if x != nil {
+		return x.MaxActiveSessions
+	}
+	return 0
+}
+
+func (x *Auth) GetCodeHmacSecret() string {
+	if x != nil {
+		return x.CodeHmacSecret
+	}
+	return ""
+}
+
+func (x *Auth) GetCodeHmacPreviousSecret() string {
+	if x != nil {
+		return x.CodeHmacPreviousSecret
+	}
+	return ""
+}
+
+func (x *Auth) GetRefreshRotationThreshold() float64 {
+	if x != nil {
+		return x.RefreshRotationThreshold
+	}
+	return 0
+}
+
+func (x *Auth) GetPasswordHashScheme() string {
+	if x != nil {
+		return x.PasswordHashScheme
+	}
+	return ""
+}
+
+func (x *Auth) GetBindTokenToClient() bool {
+	if x != nil {
+		return x.BindTokenToClient
+	}
+	return false
+}
+
+func (x *Auth) GetRefreshGracePeriod() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshGracePeriod
+	}
+	return nil
+}
+
+func (x *Auth) GetMinSigningKeyLength() int32 {
+	if x != nil {
+		return x.MinSigningKeyLength
+	}
+	return 0
+}
+
+func (x *Auth) GetRegistrationCaptchaThreshold() int32 {
+	if x != nil {
+		return x.RegistrationCaptchaThreshold
+	}
+	return 0
+}
+
+func (x *Auth) GetRegistrationCaptchaWindow() *durationpb.Duration {
+	if x != nil {
+		return x.RegistrationCaptchaWindow
+	}
+	return nil
+}
+
+func (x *Auth) GetAccessRefreshThreshold() float64 {
+	if x != nil {
+		return x.AccessRefreshThreshold
+	}
+	return 0
+}
+
+func (x *Auth) GetRefreshIdleTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshIdleTimeout
+	}
+	return nil
+}
+
+func (x *Auth) GetMaxNicknameWidth() int32 {
+	if x != nil {
+		return x.MaxNicknameWidth
+	}
+	return 0
+}
+
+func (x *Auth) GetRequireEmailVerification() bool {
+	if x != nil {
+		return x.RequireEmailVerification
+	}
+	return false
+}
+
+func (x *Auth) GetEnforceTokensValidAfter() bool {
+	if x != nil {
+		return x.EnforceTokensValidAfter
+	}
+	return false
+}
+
+func (x *Auth) GetMaxPersonalTokens() int32 {
+	if x != nil {
+		return x.MaxPersonalTokens
+	}
+	return 0
+}
+
+type Pagination struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DefaultPageSize int32                  `protobuf:"varint,1,opt,name=default_page_size,json=defaultPageSize,proto3" json:"default_page_size,omitempty"`
+	MaxPageSize     int32                  `protobuf:"varint,2,opt,name=max_page_size,json=maxPageSize,proto3" json:"max_page_size,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	mi := &file_conf_conf_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Pagination) GetDefaultPageSize() int32 {
+	if x != nil {
+		return x.DefaultPageSize
+	}
+	return 0
+}
+
+func (x *Pagination) GetMaxPageSize() int32 {
+	if x != nil {
+		return x.MaxPageSize
+	}
+	return 0
+}
+
+type Biz struct {
+	state                           protoimpl.MessageState `protogen:"open.v1"`
+	SlowOperationThreshold          *durationpb.Duration   `protobuf:"bytes,1,opt,name=slow_operation_threshold,json=slowOperationThreshold,proto3" json:"slow_operation_threshold,omitempty"`
+	PointDeletionPolicy             string                 `protobuf:"bytes,2,opt,name=point_deletion_policy,json=pointDeletionPolicy,proto3" json:"point_deletion_policy,omitempty"`
+	MaxTransactionDescriptionLength int32                  `protobuf:"varint,3,opt,name=max_transaction_description_length,json=maxTransactionDescriptionLength,proto3" json:"max_transaction_description_length,omitempty"`
+	PointTransactionRetention       *durationpb.Duration   `protobuf:"bytes,4,opt,name=point_transaction_retention,json=pointTransactionRetention,proto3" json:"point_transaction_retention,omitempty"`
+	PointArchiveBatchSize           int32                  `protobuf:"varint,5,opt,name=point_archive_batch_size,json=pointArchiveBatchSize,proto3" json:"point_archive_batch_size,omitempty"`
+	PointArchiveInterval            *durationpb.Duration   `protobuf:"bytes,6,opt,name=point_archive_interval,json=pointArchiveInterval,proto3" json:"point_archive_interval,omitempty"`
+	unknownFields                   protoimpl.UnknownFields
+	sizeCache                       protoimpl.SizeCache
+}
+
+func (x *Biz) Reset() {
+	*x = Biz{}
+	mi := &file_conf_conf_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Biz) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Biz) ProtoMessage() {}
+
+func (x *Biz) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Biz.ProtoReflect.Descriptor instead.
+func (*Biz) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Biz) GetSlowOperationThreshold() *durationpb.Duration {
+	if x != nil {
+		return x.SlowOperationThreshold
+	}
+	return nil
+}
+
+func (x *Biz) GetPointDeletionPolicy() string {
+	if x != nil {
+		return x.PointDeletionPolicy
+	}
+	return ""
+}
+
+func (x *Biz) GetMaxTransactionDescriptionLength() int32 {
+	if x != nil {
+		return x.MaxTransactionDescriptionLength
+	}
+	return 0
+}
+
+func (x *Biz) GetPointTransactionRetention() *durationpb.Duration {
+	if x != nil {
+		return x.PointTransactionRetention
+	}
+	return nil
+}
+
+func (x *Biz) GetPointArchiveBatchSize() int32 {
+	if x != nil {
+		return x.PointArchiveBatchSize
+	}
+	return 0
+}
+
+func (x *Biz) GetPointArchiveInterval() *durationpb.Duration {
+	if x != nil {
+		return x.PointArchiveInterval
+	}
+	return nil
+}
+
+type Log struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	InfoSampleRate int32                  `protobuf:"varint,1,opt,name=info_sample_rate,json=infoSampleRate,proto3" json:"info_sample_rate,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Log) Reset() {
+	*x = Log{}
+	mi := &file_conf_conf_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Log) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Log) ProtoMessage() {}
+
+func (x *Log) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Log.ProtoReflect.Descriptor instead.
+func (*Log) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Log) GetInfoSampleRate() int32 {
+	if x != nil {
+		return x.InfoSampleRate
+	}
+	return 0
+}
+
+type Server_SecurityHeaders struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	HstsEnabled           bool                   `protobuf:"varint,1,opt,name=hsts_enabled,json=hstsEnabled,proto3" json:"hsts_enabled,omitempty"`
+	HstsMaxAge            *durationpb.Duration   `protobuf:"bytes,2,opt,name=hsts_max_age,json=hstsMaxAge,proto3" json:"hsts_max_age,omitempty"`
+	HstsIncludeSubdomains bool                   `protobuf:"varint,3,opt,name=hsts_include_subdomains,json=hstsIncludeSubdomains,proto3" json:"hsts_include_subdomains,omitempty"`
+	ContentTypeNosniff    bool                   `protobuf:"varint,4,opt,name=content_type_nosniff,json=contentTypeNosniff,proto3" json:"content_type_nosniff,omitempty"`
+	FrameOptions          string                 `protobuf:"bytes,5,opt,name=frame_options,json=frameOptions,proto3" json:"frame_options,omitempty"`
+	ContentSecurityPolicy string                 `protobuf:"bytes,6,opt,name=content_security_policy,json=contentSecurityPolicy,proto3" json:"content_security_policy,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Server_SecurityHeaders) Reset() {
+	*x = Server_SecurityHeaders{}
+	mi := &file_conf_conf_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_SecurityHeaders) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_SecurityHeaders) ProtoMessage() {}
+
+func (x *Server_SecurityHeaders) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_SecurityHeaders.ProtoReflect.Descriptor instead.
+func (*Server_SecurityHeaders) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *Server_SecurityHeaders) GetHstsEnabled() bool {
+	if x != nil {
+		return x.HstsEnabled
+	}
+	return false
+}
+
+func (x *Server_SecurityHeaders) GetHstsMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.HstsMaxAge
+	}
+	return nil
+}
+
+func (x *Server_SecurityHeaders) GetHstsIncludeSubdomains() bool {
+	if x != nil {
+		return x.HstsIncludeSubdomains
+	}
+	return false
+}
+
+func (x *Server_SecurityHeaders) GetContentTypeNosniff() bool {
+	if x != nil {
+		return x.ContentTypeNosniff
+	}
+	return false
+}
+
+func (x *Server_SecurityHeaders) GetFrameOptions() string {
+	if x != nil {
+		return x.FrameOptions
+	}
+	return ""
+}
+
+func (x *Server_SecurityHeaders) GetContentSecurityPolicy() string {
+	if x != nil {
+		return x.ContentSecurityPolicy
+	}
+	return ""
+}
+
+type Server_Compression struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	MinSize       int32                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *Server_Compression) Reset() {
+	*x = Server_Compression{}
+	mi := &file_conf_conf_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Compression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Compression) ProtoMessage() {}
+
+func (x *Server_Compression) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Compression.ProtoReflect.Descriptor instead.
+func (*Server_Compression) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *Server_Compression) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Server_Compression) GetMinSize() int32 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+type Server_HTTP struct {
+	state           protoimpl.MessageState  `protogen:"open.v1"`
+	Network         string                  `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Addr            string                  `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Timeout         *durationpb.Duration    `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	SecurityHeaders *Server_SecurityHeaders `protobuf:"bytes,4,opt,name=security_headers,json=securityHeaders,proto3" json:"security_headers,omitempty"`
+	Compression     *Server_Compression     `protobuf:"bytes,5,opt,name=compression,proto3" json:"compression,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *Server_HTTP) Reset() {
 	*x = Server_HTTP{}
-	mi := &file_conf_conf_proto_msgTypes[5]
+	mi := &file_conf_conf_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -361,7 +1059,7 @@ func (x *Server_HTTP) String() string {
 func (*Server_HTTP) ProtoMessage() {}
 
 func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[5]
+	mi := &file_conf_conf_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,7 +1072,7 @@ func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Server_HTTP.ProtoReflect.Descriptor instead.
 func (*Server_HTTP) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{1, 0}
+	return file_conf_conf_proto_rawDescGZIP(), []int{1, 2}
 }
 
 func (x *Server_HTTP) GetNetwork() string {
@@ -398,6 +1096,20 @@ func (x *Server_HTTP) GetTimeout() *durationpb.Duration {
 	return nil
 }
 
+func (x *Server_HTTP) GetSecurityHeaders() *Server_SecurityHeaders {
+	if x != nil {
+		return x.SecurityHeaders
+	}
+	return nil
+}
+
+func (x *Server_HTTP) GetCompression() *Server_Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return nil
+}
+
 type Server_GRPC struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
@@ -409,7 +1121,7 @@ type Server_GRPC struct {
 
 func (x *Server_GRPC) Reset() {
 	*x = Server_GRPC{}
-	mi := &file_conf_conf_proto_msgTypes[6]
+	mi := &file_conf_conf_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -421,7 +1133,7 @@ func (x *Server_GRPC) String() string {
 func (*Server_GRPC) ProtoMessage() {}
 
 func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[6]
+	mi := &file_conf_conf_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -434,7 +1146,7 @@ func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Server_GRPC.ProtoReflect.Descriptor instead.
 func (*Server_GRPC) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{1, 1}
+	return file_conf_conf_proto_rawDescGZIP(), []int{1, 3}
 }
 
 func (x *Server_GRPC) GetNetwork() string {
@@ -472,7 +1184,7 @@ type Data_Database struct {
 
 func (x *Data_Database) Reset() {
 	*x = Data_Database{}
-	mi := &file_conf_conf_proto_msgTypes[7]
+	mi := &file_conf_conf_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -484,7 +1196,7 @@ func (x *Data_Database) String() string {
 func (*Data_Database) ProtoMessage() {}
 
 func (x *Data_Database) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[7]
+	mi := &file_conf_conf_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -549,13 +1261,14 @@ type Data_Redis struct {
 	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
 	ReadTimeout   *durationpb.Duration   `protobuf:"bytes,4,opt,name=read_timeout,json=readTimeout,proto3" json:"read_timeout,omitempty"`
 	WriteTimeout  *durationpb.Duration   `protobuf:"bytes,5,opt,name=write_timeout,json=writeTimeout,proto3" json:"write_timeout,omitempty"`
+	KeyPrefix     string                 `protobuf:"bytes,6,opt,name=key_prefix,json=keyPrefix,proto3" json:"key_prefix,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Data_Redis) Reset() {
 	*x = Data_Redis{}
-	mi := &file_conf_conf_proto_msgTypes[8]
+	mi := &file_conf_conf_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -567,7 +1280,7 @@ func (x *Data_Redis) String() string {
 func (*Data_Redis) ProtoMessage() {}
 
 func (x *Data_Redis) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[8]
+	mi := &file_conf_conf_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -618,56 +1331,205 @@ func (x *Data_Redis) GetWriteTimeout() *durationpb.Duration {
 	return nil
 }
 
+func (x *Data_Redis) GetKeyPrefix() string {
+	if x != nil {
+		return x.KeyPrefix
+	}
+	return ""
+}
+
+type Email_SMTP struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port          int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Email_SMTP) Reset() {
+	*x = Email_SMTP{}
+	mi := &file_conf_conf_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Email_SMTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Email_SMTP) ProtoMessage() {}
+
+func (x *Email_SMTP) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Email_SMTP.ProtoReflect.Descriptor instead.
+func (*Email_SMTP) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *Email_SMTP) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Email_SMTP) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Email_SMTP) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
 var File_conf_conf_proto protoreflect.FileDescriptor
 
 const file_conf_conf_proto_rawDesc = "" +
 	"\n" +
 	"\x0fconf/conf.proto\x12\n" +
-	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\xaf\x01\n" +
+	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\xd3\x02\n" +
 	"\tBootstrap\x12*\n" +
 	"\x06server\x18\x01 \x01(\v2\x12.kratos.api.ServerR\x06server\x12$\n" +
 	"\x04data\x18\x02 \x01(\v2\x10.kratos.api.DataR\x04data\x12'\n" +
 	"\x05trace\x18\x03 \x01(\v2\x11.kratos.api.TraceR\x05trace\x12'\n" +
-	"\x05email\x18\x04 \x01(\v2\x11.kratos.api.EmailR\x05email\"\xb8\x02\n" +
+	"\x05email\x18\x04 \x01(\v2\x11.kratos.api.EmailR\x05email\x12$\n" +
+	"\x04auth\x18\x05 \x01(\v2\x10.kratos.api.AuthR\x04auth\x126\n" +
+	"\n" +
+	"pagination\x18\x06 \x01(\v2\x16.kratos.api.PaginationR\n" +
+	"pagination\x12!\n" +
+	"\x03biz\x18\a \x01(\v2\x0f.kratos.api.BizR\x03biz\x12!\n" +
+	"\x03log\x18\b \x01(\v2\x0f.kratos.api.LogR\x03log\"\xd2\b\n" +
 	"\x06Server\x12+\n" +
 	"\x04http\x18\x01 \x01(\v2\x17.kratos.api.Server.HTTPR\x04http\x12+\n" +
-	"\x04grpc\x18\x02 \x01(\v2\x17.kratos.api.Server.GRPCR\x04grpc\x1ai\n" +
+	"\x04grpc\x18\x02 \x01(\v2\x17.kratos.api.Server.GRPCR\x04grpc\x12%\n" +
+	"\x0eenable_greeter\x18\x03 \x01(\bR\renableGreeter\x12'\n" +
+	"\x0finternet_facing\x18\x04 \x01(\bR\x0einternetFacing\x12*\n" +
+	"\x11account_id_format\x18\x05 \x01(\tR\x0faccountIdFormat\x120\n" +
+	"\x14expose_error_details\x18\x06 \x01(\bR\x12exposeErrorDetails\x12'\n" +
+	"\x0ftrusted_proxies\x18\a \x03(\tR\x0etrustedProxies\x120\n" +
+	"\x14trace_context_policy\x18\b \x01(\tR\x12traceContextPolicy\x1a\xb8\x02\n" +
+	"\x0fSecurityHeaders\x12!\n" +
+	"\fhsts_enabled\x18\x01 \x01(\bR\vhstsEnabled\x12;\n" +
+	"\fhsts_max_age\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"hstsMaxAge\x126\n" +
+	"\x17hsts_include_subdomains\x18\x03 \x01(\bR\x15hstsIncludeSubdomains\x120\n" +
+	"\x14content_type_nosniff\x18\x04 \x01(\bR\x12contentTypeNosniff\x12#\n" +
+	"\rframe_options\x18\x05 \x01(\tR\fframeOptions\x126\n" +
+	"\x17content_security_policy\x18\x06 \x01(\tR\x15contentSecurityPolicy\x1aB\n" +
+	"\vCompression\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x19\n" +
+	"\bmin_size\x18\x02 \x01(\x05R\aminSize\x1a\xfa\x01\n" +
 	"\x04HTTP\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1ai\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12M\n" +
+	"\x10security_headers\x18\x04 \x01(\v2\".kratos.api.Server.SecurityHeadersR\x0fsecurityHeaders\x12@\n" +
+	"\vcompression\x18\x05 \x01(\v2\x1e.kratos.api.Server.CompressionR\vcompression\x1ai\n" +
 	"\x04GRPC\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\xde\x03\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\x93\x05\n" +
 	"\x04Data\x125\n" +
 	"\bdatabase\x18\x01 \x01(\v2\x19.kratos.api.Data.DatabaseR\bdatabase\x12,\n" +
-	"\x05redis\x18\x02 \x01(\v2\x16.kratos.api.Data.RedisR\x05redis\x1a\x9e\x01\n" +
+	"\x05redis\x18\x02 \x01(\v2\x16.kratos.api.Data.RedisR\x05redis\x12!\n" +
+	"\fcache_driver\x18\x03 \x01(\tR\vcacheDriver\x12;\n" +
+	"\fmax_code_ttl\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"maxCodeTtl\x124\n" +
+	"\x16max_point_transactions\x18\x05 \x01(\x05R\x14maxPointTransactions\x1a\x9e\x01\n" +
 	"\bDatabase\x12\x16\n" +
 	"\x06driver\x18\x01 \x01(\tR\x06driver\x12\x12\n" +
 	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
 	"\x04port\x18\x03 \x01(\x05R\x04port\x12\x1a\n" +
 	"\bdatabase\x18\x04 \x01(\tR\bdatabase\x12\x1a\n" +
 	"\busername\x18\x05 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x06 \x01(\tR\bpassword\x1a\xcf\x01\n" +
+	"\bpassword\x18\x06 \x01(\tR\bpassword\x1a\xee\x01\n" +
 	"\x05Redis\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x12\x1a\n" +
 	"\bpassword\x18\x03 \x01(\tR\bpassword\x12<\n" +
 	"\fread_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\vreadTimeout\x12>\n" +
-	"\rwrite_timeout\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeout\"z\n" +
+	"\rwrite_timeout\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeout\x12\x1d\n" +
+	"\n" +
+	"key_prefix\x18\x06 \x01(\tR\tkeyPrefix\"z\n" +
 	"\x05Trace\x12\x1a\n" +
 	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12!\n" +
 	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x18\n" +
 	"\asampler\x18\x03 \x01(\x01R\asampler\x12\x18\n" +
-	"\abatcher\x18\x04 \x01(\tR\abatcher\"\xae\x01\n" +
+	"\abatcher\x18\x04 \x01(\tR\abatcher\"\xb6\x05\n" +
 	"\x05Email\x12\x1f\n" +
 	"\vsender_name\x18\x01 \x01(\tR\n" +
 	"senderName\x12!\n" +
 	"\fsender_email\x18\x02 \x01(\tR\vsenderEmail\x12#\n" +
 	"\rsupport_email\x18\x03 \x01(\tR\fsupportEmail\x12!\n" +
 	"\fcompany_name\x18\x04 \x01(\tR\vcompanyName\x12\x19\n" +
-	"\bapp_name\x18\x05 \x01(\tR\aappNameB\x19Z\x17user/internal/conf;confb\x06proto3"
+	"\bapp_name\x18\x05 \x01(\tR\aappName\x12(\n" +
+	"\x10daily_send_limit\x18\x06 \x01(\x05R\x0edailySendLimit\x12<\n" +
+	"\fsend_timeout\x18\a \x01(\v2\x19.google.protobuf.DurationR\vsendTimeout\x120\n" +
+	"\x14max_concurrent_sends\x18\b \x01(\x05R\x12maxConcurrentSends\x127\n" +
+	"\x18fail_fast_when_saturated\x18\t \x01(\bR\x15failFastWhenSaturated\x12\x1c\n" +
+	"\tproviders\x18\n" +
+	" \x03(\tR\tproviders\x12*\n" +
+	"\x04smtp\x18\v \x01(\v2\x16.kratos.api.Email.SMTPR\x04smtp\x122\n" +
+	"\x15welcome_email_enabled\x18\f \x01(\bR\x13welcomeEmailEnabled\x12%\n" +
+	"\x0esubject_prefix\x18\r \x01(\tR\rsubjectPrefix\x12\x1f\n" +
+	"\vcode_length\x18\x0e \x01(\x05R\n" +
+	"codeLength\x12!\n" +
+	"\fcode_charset\x18\x0f \x01(\tR\vcodeCharset\x1aJ\n" +
+	"\x04SMTP\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"\xb9\t\n" +
+	"\x04Auth\x12E\n" +
+	"\x11refresh_token_ttl\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x0frefreshTokenTtl\x12[\n" +
+	"\x1dremember_me_refresh_token_ttl\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x19rememberMeRefreshTokenTtl\x12$\n" +
+	"\x0eadmin_user_ids\x18\x03 \x03(\x03R\fadminUserIds\x12.\n" +
+	"\x13enrich_access_token\x18\x04 \x01(\bR\x11enrichAccessToken\x12.\n" +
+	"\x13max_active_sessions\x18\x05 \x01(\x05R\x11maxActiveSessions\x12(\n" +
+	"\x10code_hmac_secret\x18\x06 \x01(\tR\x0ecodeHmacSecret\x129\n" +
+	"\x19code_hmac_previous_secret\x18\a \x01(\tR\x16codeHmacPreviousSecret\x12<\n" +
+	"\x1arefresh_rotation_threshold\x18\b \x01(\x01R\x18refreshRotationThreshold\x120\n" +
+	"\x14password_hash_scheme\x18\t \x01(\tR\x12passwordHashScheme\x12/\n" +
+	"\x14bind_token_to_client\x18\n" +
+	" \x01(\bR\x11bindTokenToClient\x12K\n" +
+	"\x14refresh_grace_period\x18\v \x01(\v2\x19.google.protobuf.DurationR\x12refreshGracePeriod\x123\n" +
+	"\x16min_signing_key_length\x18\f \x01(\x05R\x13minSigningKeyLength\x12D\n" +
+	"\x1eregistration_captcha_threshold\x18\r \x01(\x05R\x1cregistrationCaptchaThreshold\x12Y\n" +
+	"\x1bregistration_captcha_window\x18\x0e \x01(\v2\x19.google.protobuf.DurationR\x19registrationCaptchaWindow\x128\n" +
+	"\x18access_refresh_threshold\x18\x0f \x01(\x01R\x16accessRefreshThreshold\x12K\n" +
+	"\x14refresh_idle_timeout\x18\x10 \x01(\v2\x19.google.protobuf.DurationR\x12refreshIdleTimeout\x12,\n" +
+	"\x12max_nickname_width\x18\x11 \x01(\x05R\x10maxNicknameWidth\x12<\n" +
+	"\x1arequire_email_verification\x18\x12 \x01(\bR\x18requireEmailVerification\x12;\n" +
+	"\x1aenforce_tokens_valid_after\x18\x13 \x01(\bR\x17enforceTokensValidAfter\x12.\n" +
+	"\x13max_personal_tokens\x18\x14 \x01(\x05R\x11maxPersonalTokens\"\\\n" +
+	"\n" +
+	"Pagination\x12*\n" +
+	"\x11default_page_size\x18\x01 \x01(\x05R\x0fdefaultPageSize\x12\"\n" +
+	"\rmax_page_size\x18\x02 \x01(\x05R\vmaxPageSize\"\xc0\x03\n" +
+	"\x03Biz\x12S\n" +
+	"\x18slow_operation_threshold\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x16slowOperationThreshold\x122\n" +
+	"\x15point_deletion_policy\x18\x02 \x01(\tR\x13pointDeletionPolicy\x12K\n" +
+	"\"max_transaction_description_length\x18\x03 \x01(\x05R\x1fmaxTransactionDescriptionLength\x12Y\n" +
+	"\x1bpoint_transaction_retention\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x19pointTransactionRetention\x127\n" +
+	"\x18point_archive_batch_size\x18\x05 \x01(\x05R\x15pointArchiveBatchSize\x12O\n" +
+	"\x16point_archive_interval\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x14pointArchiveInterval\"/\n" +
+	"\x03Log\x12(\n" +
+	"\x10info_sample_rate\x18\x01 \x01(\x05R\x0einfoSampleRateB\x19Z\x17user/internal/conf;confb\x06proto3"
 
 var (
 	file_conf_conf_proto_rawDescOnce sync.Once
@@ -681,37 +1543,62 @@ func file_conf_conf_proto_rawDescGZIP() []byte {
 	return file_conf_conf_proto_rawDescData
 }
 
-var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_conf_conf_proto_goTypes = []any{
-	(*Bootstrap)(nil),           // 0: kratos.api.Bootstrap
-	(*Server)(nil),              // 1: kratos.api.Server
-	(*Data)(nil),                // 2: kratos.api.Data
-	(*Trace)(nil),               // 3: kratos.api.Trace
-	(*Email)(nil),               // 4: kratos.api.Email
-	(*Server_HTTP)(nil),         // 5: kratos.api.Server.HTTP
-	(*Server_GRPC)(nil),         // 6: kratos.api.Server.GRPC
-	(*Data_Database)(nil),       // 7: kratos.api.Data.Database
-	(*Data_Redis)(nil),          // 8: kratos.api.Data.Redis
-	(*durationpb.Duration)(nil), // 9: google.protobuf.Duration
+	(*Bootstrap)(nil),              // 0: kratos.api.Bootstrap
+	(*Server)(nil),                 // 1: kratos.api.Server
+	(*Data)(nil),                   // 2: kratos.api.Data
+	(*Trace)(nil),                  // 3: kratos.api.Trace
+	(*Email)(nil),                  // 4: kratos.api.Email
+	(*Auth)(nil),                   // 5: kratos.api.Auth
+	(*Pagination)(nil),             // 6: kratos.api.Pagination
+	(*Biz)(nil),                    // 7: kratos.api.Biz
+	(*Log)(nil),                    // 8: kratos.api.Log
+	(*Server_SecurityHeaders)(nil), // 9: kratos.api.Server.SecurityHeaders
+	(*Server_Compression)(nil),     // 10: kratos.api.Server.Compression
+	(*Server_HTTP)(nil),            // 11: kratos.api.Server.HTTP
+	(*Server_GRPC)(nil),            // 12: kratos.api.Server.GRPC
+	(*Data_Database)(nil),          // 13: kratos.api.Data.Database
+	(*Data_Redis)(nil),             // 14: kratos.api.Data.Redis
+	(*Email_SMTP)(nil),             // 15: kratos.api.Email.SMTP
+	(*durationpb.Duration)(nil),    // 16: google.protobuf.Duration
 }
 var file_conf_conf_proto_depIdxs = []int32{
 	1,  // 0: kratos.api.Bootstrap.server:type_name -> kratos.api.Server
 	2,  // 1: kratos.api.Bootstrap.data:type_name -> kratos.api.Data
 	3,  // 2: kratos.api.Bootstrap.trace:type_name -> kratos.api.Trace
 	4,  // 3: kratos.api.Bootstrap.email:type_name -> kratos.api.Email
-	5,  // 4: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
-	6,  // 5: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
-	7,  // 6: kratos.api.Data.database:type_name -> kratos.api.Data.Database
-	8,  // 7: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
-	9,  // 8: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
-	9,  // 9: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
-	9,  // 10: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
-	9,  // 11: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	5,  // 4: kratos.api.Bootstrap.auth:type_name -> kratos.api.Auth
+	6,  // 5: kratos.api.Bootstrap.pagination:type_name -> kratos.api.Pagination
+	7,  // 6: kratos.api.Bootstrap.biz:type_name -> kratos.api.Biz
+	8,  // 7: kratos.api.Bootstrap.log:type_name -> kratos.api.Log
+	11, // 8: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
+	12, // 9: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
+	13, // 10: kratos.api.Data.database:type_name -> kratos.api.Data.Database
+	14, // 11: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
+	16, // 12: kratos.api.Data.max_code_ttl:type_name -> google.protobuf.Duration
+	16, // 13: kratos.api.Email.send_timeout:type_name -> google.protobuf.Duration
+	15, // 14: kratos.api.Email.smtp:type_name -> kratos.api.Email.SMTP
+	16, // 15: kratos.api.Auth.refresh_token_ttl:type_name -> google.protobuf.Duration
+	16, // 16: kratos.api.Auth.remember_me_refresh_token_ttl:type_name -> google.protobuf.Duration
+	16, // 17: kratos.api.Auth.refresh_grace_period:type_name -> google.protobuf.Duration
+	16, // 18: kratos.api.Auth.registration_captcha_window:type_name -> google.protobuf.Duration
+	16, // 19: kratos.api.Auth.refresh_idle_timeout:type_name -> google.protobuf.Duration
+	16, // 20: kratos.api.Biz.slow_operation_threshold:type_name -> google.protobuf.Duration
+	16, // 21: kratos.api.Biz.point_transaction_retention:type_name -> google.protobuf.Duration
+	16, // 22: kratos.api.Biz.point_archive_interval:type_name -> google.protobuf.Duration
+	16, // 23: kratos.api.Server.SecurityHeaders.hsts_max_age:type_name -> google.protobuf.Duration
+	16, // 24: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
+	9,  // 25: kratos.api.Server.HTTP.security_headers:type_name -> kratos.api.Server.SecurityHeaders
+	10, // 26: kratos.api.Server.HTTP.compression:type_name -> kratos.api.Server.Compression
+	16, // 27: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
+	16, // 28: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
+	16, // 29: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
+	30, // [30:30] is the sub-list for method output_type
+	30, // [30:30] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_conf_conf_proto_init() }
@@ -725,7 +1612,7 @@ func file_conf_conf_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_conf_conf_proto_rawDesc), len(file_conf_conf_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,63 @@
+package conf
+
+import (
+	"os"
+	"strings"
+)
+
+// 密钥类配置通过环境变量注入，避免写入配置文件
+const (
+	envCodeHMACSecret         = "CODE_HMAC_SECRET"
+	envCodeHMACPreviousSecret = "CODE_HMAC_PREVIOUS_SECRET"
+	envJWTAccessSecret        = "JWT_ACCESS_SECRET"
+	envJWTRefreshSecret       = "JWT_REFRESH_SECRET"
+)
+
+// minCodeHMACSecretLength 验证码 HMAC 密钥的最小字节数
+const minCodeHMACSecretLength = 32
+
+// defaultMinSigningKeyLength 未配置 auth.min_signing_key_length 时 JWT 签名密钥的最小字节数，HS256 至少需要 32 字节的熵
+const defaultMinSigningKeyLength = 32
+
+// testSecretPrefix 单元测试使用的密钥前缀，不受最小长度限制
+const testSecretPrefix = "test-"
+
+// ApplyEnvSecrets 使用环境变量覆盖配置中的密钥，应在 Validate 之前调用
+func ApplyEnvSecrets(bc *Bootstrap) {
+	secret, hasSecret := os.LookupEnv(envCodeHMACSecret)
+	previous, hasPrevious := os.LookupEnv(envCodeHMACPreviousSecret)
+	if !hasSecret && !hasPrevious {
+		return
+	}
+	if bc.Auth == nil {
+		bc.Auth = &Auth{}
+	}
+	if hasSecret {
+		bc.Auth.CodeHmacSecret = secret
+	}
+	if hasPrevious {
+		bc.Auth.CodeHmacPreviousSecret = previous
+	}
+}
+
+// ValidateSigningKeys 校验环境变量中的 JWT 签名密钥长度，不满足时拒绝启动，应在 Validate 之后调用
+// 以 "test-" 开头的密钥仅供单元测试使用，只要求非空
+func ValidateSigningKeys(bc *Bootstrap) error {
+	minLength := int(bc.GetAuth().GetMinSigningKeyLength())
+	if minLength <= 0 {
+		minLength = defaultMinSigningKeyLength
+	}
+
+	v := &validator{}
+	for _, env := range []string{envJWTAccessSecret, envJWTRefreshSecret} {
+		secret := os.Getenv(env)
+		switch {
+		case secret == "":
+			v.add("%s is required", env)
+		case strings.HasPrefix(secret, testSecretPrefix):
+		case len(secret) < minLength:
+			v.add("%s must be at least %d bytes, got %d", env, minLength, len(secret))
+		}
+	}
+	return v.err()
+}
@@ -0,0 +1,302 @@
+package conf
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// cacheDriverRedis/cacheDriverMemory data.cache_driver 允许的取值，空值等同于 redis
+const (
+	cacheDriverRedis  = "redis"
+	cacheDriverMemory = "memory"
+)
+
+// emailProviderSendGrid/emailProviderSMTP email.providers 允许的取值，未配置时只使用 sendgrid
+const (
+	emailProviderSendGrid = "sendgrid"
+	emailProviderSMTP     = "smtp"
+)
+
+// maxRefreshGracePeriod 刷新令牌宽限期上限，宽限期内被重放的旧令牌仍能换到新令牌，不宜过长
+const maxRefreshGracePeriod = time.Minute
+
+// Validate 校验启动配置的必填项和取值范围，一次性返回所有问题，应在依赖注入之前调用
+func Validate(bc *Bootstrap) error {
+	v := &validator{}
+
+	if bc.Server == nil {
+		v.add("server is required")
+	} else {
+		validateServer(v, bc.Server)
+	}
+	if bc.Data == nil {
+		v.add("data is required")
+	} else {
+		validateData(v, bc.Data)
+	}
+	validateCodeHMACSecret(v, bc.Auth)
+	if bc.Auth != nil {
+		v.nonNegative("auth.refresh_token_ttl", bc.Auth.RefreshTokenTtl)
+		v.nonNegative("auth.remember_me_refresh_token_ttl", bc.Auth.RememberMeRefreshTokenTtl)
+		if bc.Auth.RefreshTokenTtl != nil && bc.Auth.RememberMeRefreshTokenTtl != nil &&
+			bc.Auth.RememberMeRefreshTokenTtl.AsDuration() < bc.Auth.RefreshTokenTtl.AsDuration() {
+			v.add("auth.remember_me_refresh_token_ttl must not be shorter than auth.refresh_token_ttl")
+		}
+		if bc.Auth.RefreshRotationThreshold < 0 || bc.Auth.RefreshRotationThreshold > 1 {
+			v.add("auth.refresh_rotation_threshold must be between 0 and 1, got %g", bc.Auth.RefreshRotationThreshold)
+		}
+		if bc.Auth.AccessRefreshThreshold < 0 || bc.Auth.AccessRefreshThreshold > 1 {
+			v.add("auth.access_refresh_threshold must be between 0 and 1, got %g", bc.Auth.AccessRefreshThreshold)
+		}
+		v.nonNegative("auth.refresh_grace_period", bc.Auth.RefreshGracePeriod)
+		v.nonNegative("auth.refresh_idle_timeout", bc.Auth.RefreshIdleTimeout)
+		if bc.Auth.RefreshGracePeriod.AsDuration() > maxRefreshGracePeriod {
+			v.add("auth.refresh_grace_period must not exceed %s, got %s", maxRefreshGracePeriod, bc.Auth.RefreshGracePeriod.AsDuration())
+		}
+		if bc.Auth.MinSigningKeyLength < 0 {
+			v.add("auth.min_signing_key_length must not be negative, got %d", bc.Auth.MinSigningKeyLength)
+		}
+		if bc.Auth.RegistrationCaptchaThreshold < 0 {
+			v.add("auth.registration_captcha_threshold must not be negative, got %d", bc.Auth.RegistrationCaptchaThreshold)
+		}
+		v.nonNegative("auth.registration_captcha_window", bc.Auth.RegistrationCaptchaWindow)
+		if bc.Auth.MaxNicknameWidth < 0 {
+			v.add("auth.max_nickname_width must not be negative, got %d", bc.Auth.MaxNicknameWidth)
+		}
+		if bc.Auth.MaxActiveSessions < 0 {
+			v.add("auth.max_active_sessions must not be negative, got %d", bc.Auth.MaxActiveSessions)
+		}
+		if bc.Auth.MaxPersonalTokens < 0 {
+			v.add("auth.max_personal_tokens must not be negative, got %d", bc.Auth.MaxPersonalTokens)
+		}
+		switch bc.Auth.PasswordHashScheme {
+		case "", "bcrypt", "argon2id":
+		default:
+			v.add("auth.password_hash_scheme must be bcrypt or argon2id, got %q", bc.Auth.PasswordHashScheme)
+		}
+	}
+	if bc.Email != nil {
+		if bc.Email.DailySendLimit < 0 {
+			v.add("email.daily_send_limit must not be negative, got %d", bc.Email.DailySendLimit)
+		}
+		v.nonNegative("email.send_timeout", bc.Email.SendTimeout)
+		if bc.Email.MaxConcurrentSends < 0 {
+			v.add("email.max_concurrent_sends must not be negative, got %d", bc.Email.MaxConcurrentSends)
+		}
+		validateEmailProviders(v, bc.Email)
+		validateVerificationCode(v, bc.Email)
+	}
+	if bc.Pagination != nil {
+		if bc.Pagination.DefaultPageSize < 0 {
+			v.add("pagination.default_page_size must not be negative, got %d", bc.Pagination.DefaultPageSize)
+		}
+		if bc.Pagination.MaxPageSize < 0 {
+			v.add("pagination.max_page_size must not be negative, got %d", bc.Pagination.MaxPageSize)
+		}
+		if bc.Pagination.MaxPageSize > 0 && bc.Pagination.DefaultPageSize > bc.Pagination.MaxPageSize {
+			v.add("pagination.default_page_size (%d) must not exceed pagination.max_page_size (%d)",
+				bc.Pagination.DefaultPageSize, bc.Pagination.MaxPageSize)
+		}
+	}
+	if bc.Biz != nil {
+		v.nonNegative("biz.slow_operation_threshold", bc.Biz.SlowOperationThreshold)
+		switch bc.Biz.PointDeletionPolicy {
+		case "", "retain", "zero_out", "archive":
+		default:
+			v.add("biz.point_deletion_policy must be retain, zero_out or archive, got %q", bc.Biz.PointDeletionPolicy)
+		}
+		if bc.Biz.MaxTransactionDescriptionLength < 0 {
+			v.add("biz.max_transaction_description_length must not be negative, got %d", bc.Biz.MaxTransactionDescriptionLength)
+		}
+		v.nonNegative("biz.point_transaction_retention", bc.Biz.PointTransactionRetention)
+		v.nonNegative("biz.point_archive_interval", bc.Biz.PointArchiveInterval)
+		if bc.Biz.PointArchiveBatchSize < 0 {
+			v.add("biz.point_archive_batch_size must not be negative, got %d", bc.Biz.PointArchiveBatchSize)
+		}
+	}
+	if bc.Log != nil && bc.Log.InfoSampleRate < 0 {
+		v.add("log.info_sample_rate must not be negative, got %d", bc.Log.InfoSampleRate)
+	}
+
+	return v.err()
+}
+
+// validateCodeHMACSecret 校验验证码 HMAC 密钥，轮换期间的旧密钥同样需要满足长度要求
+func validateCodeHMACSecret(v *validator, auth *Auth) {
+	if auth == nil || auth.CodeHmacSecret == "" {
+		v.add("auth.code_hmac_secret is required, set it via %s", envCodeHMACSecret)
+		return
+	}
+	if len(auth.CodeHmacSecret) < minCodeHMACSecretLength {
+		v.add("auth.code_hmac_secret must be at least %d bytes, got %d", minCodeHMACSecretLength, len(auth.CodeHmacSecret))
+	}
+	if auth.CodeHmacPreviousSecret != "" && len(auth.CodeHmacPreviousSecret) < minCodeHMACSecretLength {
+		v.add("auth.code_hmac_previous_secret must be at least %d bytes, got %d", minCodeHMACSecretLength, len(auth.CodeHmacPreviousSecret))
+	}
+}
+
+// validateServer 校验服务监听配置
+func validateServer(v *validator, c *Server) {
+	if c.Http == nil || c.Http.Addr == "" {
+		v.add("server.http.addr is required")
+	}
+	if c.Http != nil {
+		v.positive("server.http.timeout", c.Http.Timeout)
+		if c.Http.SecurityHeaders != nil {
+			v.nonNegative("server.http.security_headers.hsts_max_age", c.Http.SecurityHeaders.HstsMaxAge)
+		}
+		if c.Http.Compression != nil && c.Http.Compression.MinSize < 0 {
+			v.add("server.http.compression.min_size must not be negative, got %d", c.Http.Compression.MinSize)
+		}
+	}
+	if c.Grpc == nil || c.Grpc.Addr == "" {
+		v.add("server.grpc.addr is required")
+	}
+	if c.Grpc != nil {
+		v.positive("server.grpc.timeout", c.Grpc.Timeout)
+	}
+	switch c.AccountIdFormat {
+	case "", "int64", "prefixed":
+	default:
+		v.add("server.account_id_format must be int64 or prefixed, got %q", c.AccountIdFormat)
+	}
+	if c.ExposeErrorDetails && c.InternetFacing {
+		v.add("server.expose_error_details must not be enabled when server.internet_facing is true")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			v.add("server.trusted_proxies must contain IP addresses or CIDRs, got %q", proxy)
+		}
+	}
+	switch c.TraceContextPolicy {
+	case "", "lenient", "warn", "strict":
+	default:
+		v.add("server.trace_context_policy must be lenient, warn or strict, got %q", c.TraceContextPolicy)
+	}
+}
+
+// validateData 校验数据库和缓存配置，数据库密码可由环境变量提供，不在此校验
+func validateData(v *validator, c *Data) {
+	if c.Database == nil {
+		v.add("data.database is required")
+	} else {
+		if c.Database.Host == "" {
+			v.add("data.database.host is required")
+		}
+		if c.Database.Port <= 0 || c.Database.Port > 65535 {
+			v.add("data.database.port must be between 1 and 65535, got %d", c.Database.Port)
+		}
+		if c.Database.Database == "" {
+			v.add("data.database.database is required")
+		}
+		if c.Database.Username == "" {
+			v.add("data.database.username is required")
+		}
+	}
+
+	switch c.CacheDriver {
+	case "", cacheDriverRedis:
+		if c.Redis == nil || c.Redis.Addr == "" {
+			v.add("data.redis.addr is required")
+		}
+	case cacheDriverMemory:
+	default:
+		v.add("data.cache_driver must be %q or %q, got %q", cacheDriverRedis, cacheDriverMemory, c.CacheDriver)
+	}
+	v.nonNegative("data.max_code_ttl", c.MaxCodeTtl)
+	if c.MaxPointTransactions < 0 {
+		v.add("data.max_point_transactions must not be negative, got %d", c.MaxPointTransactions)
+	}
+	if c.Redis != nil {
+		v.positive("data.redis.read_timeout", c.Redis.ReadTimeout)
+		v.positive("data.redis.write_timeout", c.Redis.WriteTimeout)
+	}
+}
+
+// validator 收集配置校验问题
+type validator struct {
+	problems []string
+}
+
+// validateEmailProviders 邮件服务商不能重复，使用 smtp 时必须配置 SMTP 服务器
+func validateEmailProviders(v *validator, c *Email) {
+	seen := make(map[string]bool, len(c.Providers))
+	for _, provider := range c.Providers {
+		switch provider {
+		case emailProviderSendGrid, emailProviderSMTP:
+		default:
+			v.add("email.providers must contain only sendgrid or smtp, got %q", provider)
+			continue
+		}
+		if seen[provider] {
+			v.add("email.providers must not contain duplicates, got %q twice", provider)
+		}
+		seen[provider] = true
+	}
+	if seen[emailProviderSMTP] && c.GetSmtp().GetHost() == "" {
+		v.add("email.smtp.host is required when email.providers contains smtp")
+	}
+	if port := c.GetSmtp().GetPort(); port < 0 || port > 65535 {
+		v.add("email.smtp.port must be between 0 and 65535, got %d", port)
+	}
+}
+
+// validateVerificationCode 验证码长度为 0（使用默认值）或 4-16 位，字符集至少包含 2 个不重复的字母或数字
+// 验证码会直接写入邮件 HTML，只允许字母和数字，与邮件预览接口的验证码规则一致
+func validateVerificationCode(v *validator, c *Email) {
+	if c.CodeLength != 0 && (c.CodeLength < 4 || c.CodeLength > 16) {
+		v.add("email.code_length must be 0 or between 4 and 16, got %d", c.CodeLength)
+	}
+	if c.CodeCharset == "" {
+		return
+	}
+	seen := make(map[rune]bool, len(c.CodeCharset))
+	for _, r := range c.CodeCharset {
+		if !isASCIIAlphanumeric(r) {
+			v.add("email.code_charset must contain only ASCII letters and digits, got %q", r)
+			return
+		}
+		if seen[r] {
+			v.add("email.code_charset must not contain duplicates, got %q twice", r)
+			return
+		}
+		seen[r] = true
+	}
+	if len(seen) < 2 {
+		v.add("email.code_charset must contain at least 2 characters, got %q", c.CodeCharset)
+	}
+}
+
+// isASCIIAlphanumeric 判断是否为 ASCII 字母或数字
+func isASCIIAlphanumeric(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func (v *validator) add(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// positive 已配置的时长必须大于0
+func (v *validator) positive(field string, d *durationpb.Duration) {
+	if d != nil && d.AsDuration() <= 0 {
+		v.add("%s must be positive, got %s", field, d.AsDuration())
+	}
+}
+
+// nonNegative 已配置的时长不能为负数
+func (v *validator) nonNegative(field string, d *durationpb.Duration) {
+	if d != nil && d.AsDuration() < 0 {
+		v.add("%s must not be negative, got %s", field, d.AsDuration())
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config: %s", strings.Join(v.problems, "; "))
+}
@@ -0,0 +1,425 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// validBootstrap 返回一份合法的配置
+func validBootstrap() *Bootstrap {
+	return &Bootstrap{
+		Server: &Server{
+			Http: &Server_HTTP{Addr: "0.0.0.0:8000", Timeout: durationpb.New(time.Second)},
+			Grpc: &Server_GRPC{Addr: "0.0.0.0:9000", Timeout: durationpb.New(time.Second)},
+		},
+		Data: &Data{
+			Database: &Data_Database{Host: "127.0.0.1", Port: 3306, Database: "user_service", Username: "root"},
+			Redis:    &Data_Redis{Addr: "127.0.0.1:6379", ReadTimeout: durationpb.New(200 * time.Millisecond)},
+		},
+		Auth: &Auth{
+			RefreshTokenTtl:           durationpb.New(7 * 24 * time.Hour),
+			RememberMeRefreshTokenTtl: durationpb.New(30 * 24 * time.Hour),
+			CodeHmacSecret:            strings.Repeat("s", minCodeHMACSecretLength),
+		},
+		Pagination: &Pagination{DefaultPageSize: 20, MaxPageSize: 100},
+	}
+}
+
+// TestValidate 测试启动配置校验
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(*Bootstrap)
+		wantProblems []string
+	}{
+		{
+			name:   "合法配置",
+			modify: func(bc *Bootstrap) {},
+		},
+		{
+			name: "缺少Redis地址",
+			modify: func(bc *Bootstrap) {
+				bc.Data.Redis.Addr = ""
+			},
+			wantProblems: []string{"data.redis.addr is required"},
+		},
+		{
+			name: "使用内存缓存时不要求Redis地址",
+			modify: func(bc *Bootstrap) {
+				bc.Data.CacheDriver = "memory"
+				bc.Data.Redis = nil
+			},
+		},
+		{
+			name: "刷新令牌有效期为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.RefreshTokenTtl = durationpb.New(-time.Hour)
+			},
+			wantProblems: []string{"auth.refresh_token_ttl must not be negative, got -1h0m0s"},
+		},
+		{
+			name: "刷新令牌轮换阈值超出范围",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.RefreshRotationThreshold = 1.5
+			},
+			wantProblems: []string{"auth.refresh_rotation_threshold must be between 0 and 1, got 1.5"},
+		},
+		{
+			name: "访问令牌提前刷新阈值超出范围",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.AccessRefreshThreshold = -0.1
+			},
+			wantProblems: []string{"auth.access_refresh_threshold must be between 0 and 1, got -0.1"},
+		},
+		{
+			name: "刷新令牌宽限期过长",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.RefreshGracePeriod = durationpb.New(5 * time.Minute)
+			},
+			wantProblems: []string{"auth.refresh_grace_period must not exceed 1m0s, got 5m0s"},
+		},
+		{
+			name: "签名密钥最小长度为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.MinSigningKeyLength = -1
+			},
+			wantProblems: []string{"auth.min_signing_key_length must not be negative, got -1"},
+		},
+		{
+			name: "注册人机验证阈值为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.RegistrationCaptchaThreshold = -1
+				bc.Auth.RegistrationCaptchaWindow = durationpb.New(-time.Hour)
+			},
+			wantProblems: []string{
+				"auth.registration_captcha_threshold must not be negative, got -1",
+				"auth.registration_captcha_window must not be negative, got -1h0m0s",
+			},
+		},
+		{
+			name: "会话上限为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.MaxActiveSessions = -1
+			},
+			wantProblems: []string{"auth.max_active_sessions must not be negative, got -1"},
+		},
+		{
+			name: "昵称最大宽度为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.MaxNicknameWidth = -1
+			},
+			wantProblems: []string{"auth.max_nickname_width must not be negative, got -1"},
+		},
+		{
+			name: "个人访问令牌上限为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.MaxPersonalTokens = -1
+			},
+			wantProblems: []string{"auth.max_personal_tokens must not be negative, got -1"},
+		},
+		{
+			name: "验证码有效期上限为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Data.MaxCodeTtl = durationpb.New(-time.Minute)
+			},
+			wantProblems: []string{"data.max_code_ttl must not be negative, got -1m0s"},
+		},
+		{
+			name: "点数流水查询上限为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Data.MaxPointTransactions = -1
+			},
+			wantProblems: []string{"data.max_point_transactions must not be negative, got -1"},
+		},
+		{
+			name: "未知的点数删除策略",
+			modify: func(bc *Bootstrap) {
+				bc.Biz = &Biz{PointDeletionPolicy: "delete"}
+			},
+			wantProblems: []string{`biz.point_deletion_policy must be retain, zero_out or archive, got "delete"`},
+		},
+		{
+			name: "流水描述长度上限为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Biz = &Biz{MaxTransactionDescriptionLength: -1}
+			},
+			wantProblems: []string{"biz.max_transaction_description_length must not be negative, got -1"},
+		},
+		{
+			name: "点数归档批次大小为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Biz = &Biz{PointArchiveBatchSize: -1}
+			},
+			wantProblems: []string{"biz.point_archive_batch_size must not be negative, got -1"},
+		},
+		{
+			name: "日志采样率为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Log = &Log{InfoSampleRate: -1}
+			},
+			wantProblems: []string{"log.info_sample_rate must not be negative, got -1"},
+		},
+		{
+			name: "不支持的密码哈希算法",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.PasswordHashScheme = "md5"
+			},
+			wantProblems: []string{`auth.password_hash_scheme must be bcrypt or argon2id, got "md5"`},
+		},
+		{
+			name: "不支持的账号ID格式",
+			modify: func(bc *Bootstrap) {
+				bc.Server.AccountIdFormat = "uuid"
+			},
+			wantProblems: []string{`server.account_id_format must be int64 or prefixed, got "uuid"`},
+		},
+		{
+			name: "面向公网时开启错误详情",
+			modify: func(bc *Bootstrap) {
+				bc.Server.InternetFacing = true
+				bc.Server.ExposeErrorDetails = true
+			},
+			wantProblems: []string{"server.expose_error_details must not be enabled when server.internet_facing is true"},
+		},
+		{
+			name: "可信代理格式不正确",
+			modify: func(bc *Bootstrap) {
+				bc.Server.TrustedProxies = []string{"10.0.0.0/8", "127.0.0.1", "nginx"}
+			},
+			wantProblems: []string{`server.trusted_proxies must contain IP addresses or CIDRs, got "nginx"`},
+		},
+		{
+			name: "追踪上下文策略不支持",
+			modify: func(bc *Bootstrap) {
+				bc.Server.TraceContextPolicy = "reject"
+			},
+			wantProblems: []string{`server.trace_context_policy must be lenient, warn or strict, got "reject"`},
+		},
+		{
+			name: "每日发送上限为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{DailySendLimit: -1}
+			},
+			wantProblems: []string{"email.daily_send_limit must not be negative, got -1"},
+		},
+		{
+			name: "邮件发送超时为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{SendTimeout: durationpb.New(-time.Second)}
+			},
+			wantProblems: []string{"email.send_timeout must not be negative, got -1s"},
+		},
+		{
+			name: "邮件并发发送上限为负数",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{MaxConcurrentSends: -1}
+			},
+			wantProblems: []string{"email.max_concurrent_sends must not be negative, got -1"},
+		},
+		{
+			name: "未知的邮件服务商",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{Providers: []string{"sendgrid", "mailgun"}}
+			},
+			wantProblems: []string{`email.providers must contain only sendgrid or smtp, got "mailgun"`},
+		},
+		{
+			name: "邮件服务商重复",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{Providers: []string{"sendgrid", "sendgrid"}}
+			},
+			wantProblems: []string{`email.providers must not contain duplicates, got "sendgrid" twice`},
+		},
+		{
+			name: "使用SMTP但未配置服务器",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{Providers: []string{"sendgrid", "smtp"}, Smtp: &Email_SMTP{Port: 70000}}
+			},
+			wantProblems: []string{
+				"email.smtp.host is required when email.providers contains smtp",
+				"email.smtp.port must be between 0 and 65535, got 70000",
+			},
+		},
+		{
+			name: "验证码长度超出范围",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{CodeLength: 3}
+			},
+			wantProblems: []string{"email.code_length must be 0 or between 4 and 16, got 3"},
+		},
+		{
+			name: "验证码字符集包含重复字符",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{CodeLength: 8, CodeCharset: "ABCA"}
+			},
+			wantProblems: []string{`email.code_charset must not contain duplicates, got 'A' twice`},
+		},
+		{
+			name: "验证码字符集包含HTML特殊字符",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{CodeCharset: "AB<>&"}
+			},
+			wantProblems: []string{`email.code_charset must contain only ASCII letters and digits, got '<'`},
+		},
+		{
+			name: "验证码字符集过短",
+			modify: func(bc *Bootstrap) {
+				bc.Email = &Email{CodeCharset: "7"}
+			},
+			wantProblems: []string{`email.code_charset must contain at least 2 characters, got "7"`},
+		},
+		{
+			name: "缺少验证码HMAC密钥",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.CodeHmacSecret = ""
+			},
+			wantProblems: []string{"auth.code_hmac_secret is required, set it via CODE_HMAC_SECRET"},
+		},
+		{
+			name: "未配置auth时同样要求验证码HMAC密钥",
+			modify: func(bc *Bootstrap) {
+				bc.Auth = nil
+			},
+			wantProblems: []string{"auth.code_hmac_secret is required, set it via CODE_HMAC_SECRET"},
+		},
+		{
+			name: "验证码HMAC密钥过短",
+			modify: func(bc *Bootstrap) {
+				bc.Auth.CodeHmacSecret = "short"
+				bc.Auth.CodeHmacPreviousSecret = "old"
+			},
+			wantProblems: []string{
+				"auth.code_hmac_secret must be at least 32 bytes, got 5",
+				"auth.code_hmac_previous_secret must be at least 32 bytes, got 3",
+			},
+		},
+		{
+			name: "多个问题一起返回",
+			modify: func(bc *Bootstrap) {
+				bc.Data.Redis.Addr = ""
+				bc.Auth.RefreshTokenTtl = durationpb.New(-time.Hour)
+				bc.Server.Http.Timeout = durationpb.New(0)
+				bc.Pagination.DefaultPageSize = 200
+			},
+			wantProblems: []string{
+				"server.http.timeout must be positive, got 0s",
+				"data.redis.addr is required",
+				"auth.refresh_token_ttl must not be negative, got -1h0m0s",
+				"pagination.default_page_size (200) must not exceed pagination.max_page_size (100)",
+			},
+		},
+		{
+			name: "未知的缓存驱动",
+			modify: func(bc *Bootstrap) {
+				bc.Data.CacheDriver = "memcached"
+			},
+			wantProblems: []string{`data.cache_driver must be "redis" or "memory", got "memcached"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := validBootstrap()
+			tt.modify(bc)
+
+			err := Validate(bc)
+
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			for _, problem := range tt.wantProblems {
+				assert.Contains(t, err.Error(), problem)
+			}
+			assert.Equal(t, len(tt.wantProblems), strings.Count(err.Error(), "; ")+1, "不应包含多余的问题")
+		})
+	}
+}
+
+// TestApplyEnvSecrets 测试环境变量覆盖配置中的密钥
+func TestApplyEnvSecrets(t *testing.T) {
+	current := strings.Repeat("c", minCodeHMACSecretLength)
+	previous := strings.Repeat("p", minCodeHMACSecretLength)
+	t.Setenv(envCodeHMACSecret, current)
+	t.Setenv(envCodeHMACPreviousSecret, previous)
+
+	bc := validBootstrap()
+	bc.Auth = nil
+	ApplyEnvSecrets(bc)
+
+	assert.Equal(t, current, bc.Auth.CodeHmacSecret)
+	assert.Equal(t, previous, bc.Auth.CodeHmacPreviousSecret)
+	assert.NoError(t, Validate(bc))
+}
+
+// TestValidateSigningKeys 测试 JWT 签名密钥长度校验
+func TestValidateSigningKeys(t *testing.T) {
+	adequate := strings.Repeat("k", defaultMinSigningKeyLength)
+
+	tests := []struct {
+		name          string
+		accessSecret  string
+		refreshSecret string
+		minLength     int32
+		wantProblems  []string
+	}{
+		{
+			name:          "密钥长度满足默认要求",
+			accessSecret:  adequate,
+			refreshSecret: adequate,
+		},
+		{
+			name:          "访问令牌密钥过短",
+			accessSecret:  "short-secret",
+			refreshSecret: adequate,
+			wantProblems:  []string{"JWT_ACCESS_SECRET must be at least 32 bytes, got 12"},
+		},
+		{
+			name:          "两个密钥都未设置",
+			accessSecret:  "",
+			refreshSecret: "",
+			wantProblems:  []string{"JWT_ACCESS_SECRET is required", "JWT_REFRESH_SECRET is required"},
+		},
+		{
+			name:          "按配置的最小长度校验",
+			accessSecret:  adequate,
+			refreshSecret: adequate,
+			minLength:     64,
+			wantProblems: []string{
+				"JWT_ACCESS_SECRET must be at least 64 bytes, got 32",
+				"JWT_REFRESH_SECRET must be at least 64 bytes, got 32",
+			},
+		},
+		{
+			name:          "单元测试密钥不受长度限制",
+			accessSecret:  "test-access",
+			refreshSecret: "test-refresh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envJWTAccessSecret, tt.accessSecret)
+			t.Setenv(envJWTRefreshSecret, tt.refreshSecret)
+			bc := validBootstrap()
+			bc.Auth.MinSigningKeyLength = tt.minLength
+
+			err := ValidateSigningKeys(bc)
+
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			for _, problem := range tt.wantProblems {
+				assert.Contains(t, err.Error(), problem)
+			}
+			assert.Equal(t, len(tt.wantProblems), strings.Count(err.Error(), "; ")+1, "不应包含多余的问题")
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	"user/internal/biz"
+	"user/internal/conf"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUserRepository_FindDuplicateEmails 测试按规范化邮箱查找重复账号
+func TestUserRepository_FindDuplicateEmails(t *testing.T) {
+	const query = "SELECT id, LOWER\\(email\\) AS normalized_email FROM `user` WHERE LOWER\\(email\\) IN \\(SELECT LOWER\\(email\\) FROM `user` WHERE `user`.`deleted_at` IS NULL GROUP BY LOWER\\(email\\) HAVING COUNT\\(\\*\\) > 1\\) AND `user`.`deleted_at` IS NULL ORDER BY normalized_email, id"
+
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		want    []*biz.DuplicateEmailGroup
+		wantErr bool
+	}{
+		{
+			name: "按邮箱分组返回",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "normalized_email"}).
+						AddRow(1, "a@example.com").
+						AddRow(5, "a@example.com").
+						AddRow(2, "b@example.com").
+						AddRow(3, "b@example.com").
+						AddRow(9, "b@example.com"))
+			},
+			want: []*biz.DuplicateEmailGroup{
+				{NormalizedEmail: "a@example.com", UserIDs: []int64{1, 5}},
+				{NormalizedEmail: "b@example.com", UserIDs: []int64{2, 3, 9}},
+			},
+		},
+		{
+			name: "没有重复",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "normalized_email"}))
+			},
+			want: []*biz.DuplicateEmailGroup{},
+		},
+		{
+			name: "查询失败",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			got, err := repo.FindDuplicateEmails(context.Background())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestMergeAccountsTransaction 测试合并账号时点数、流水和软删除在同一事务中完成
+func TestMergeAccountsTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "合并成功提交事务",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT \\* FROM `user_point` WHERE user_id = \\? ORDER BY `user_point`.`id` LIMIT \\? FOR UPDATE").
+					WithArgs(int64(2), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "current_points", "total_consumed", "created_at", "updated_at"}).
+						AddRow(20, 2, 30, 5, time.Now(), time.Now()))
+				mock.ExpectExec("INSERT INTO `user_point` .* ON DUPLICATE KEY UPDATE `current_points`=current_points \\+ VALUES\\(current_points\\),`total_consumed`=total_consumed \\+ VALUES\\(total_consumed\\),`version`=version \\+ 1$").
+					WithArgs(int64(1), uint32(30), uint32(5)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM `user_point` WHERE `user_point`.`id` = \\?").
+					WithArgs(int64(20)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE `point_transaction` SET `user_id`=\\?,`updated_at`=\\? WHERE user_id = \\?").
+					WithArgs(int64(1), sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("UPDATE `user` SET `deleted_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs(sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "被合并账号没有点数记录时只迁移流水",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT \\* FROM `user_point` WHERE user_id = \\?").
+					WithArgs(int64(2), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "current_points", "total_consumed", "created_at", "updated_at"}))
+				mock.ExpectExec("UPDATE `point_transaction` SET `user_id`=\\?").
+					WithArgs(int64(1), sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE `user` SET `deleted_at`=\\?").
+					WithArgs(sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "迁移流水失败回滚事务",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT \\* FROM `user_point` WHERE user_id = \\?").
+					WithArgs(int64(2), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "current_points", "total_consumed", "created_at", "updated_at"}).
+						AddRow(20, 2, 30, 5, time.Now(), time.Now()))
+				mock.ExpectExec("INSERT INTO `user_point`").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM `user_point`").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE `point_transaction` SET `user_id`=\\?").
+					WillReturnError(fmt.Errorf("lock wait timeout"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "账号已被删除时回滚事务",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT \\* FROM `user_point` WHERE user_id = \\?").
+					WithArgs(int64(2), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "current_points", "total_consumed", "created_at", "updated_at"}))
+				mock.ExpectExec("UPDATE `point_transaction` SET `user_id`=\\?").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE `user` SET `deleted_at`=\\?").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			userRepo := NewUserRepository(db, log.DefaultLogger)
+			pointRepo := NewUserPointRepository(db, log.DefaultLogger)
+			txnRepo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := NewTransaction(db).InTx(context.Background(), func(ctx context.Context) error {
+				if err := pointRepo.MergeInto(ctx, 2, 1); err != nil {
+					return err
+				}
+				if _, err := txnRepo.ReassignUser(ctx, 2, 1); err != nil {
+					return err
+				}
+				return userRepo.SoftDelete(ctx, 2)
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestDeleteUserTransaction 测试删除账号时软删除和点数处理策略在同一事务中完成
+func TestDeleteUserTransaction(t *testing.T) {
+	const softDeleteSQL = "UPDATE `user` SET `deleted_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL"
+	const lockPointSQL = "SELECT \\* FROM `user_point` WHERE user_id = \\? ORDER BY `user_point`.`id` LIMIT \\? FOR UPDATE"
+
+	tests := []struct {
+		name    string
+		policy  string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name:   "保留策略只软删除用户",
+			policy: biz.PointDeletionPolicyRetain,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(softDeleteSQL).
+					WithArgs(sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "未配置策略时保留点数",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(softDeleteSQL).
+					WithArgs(sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "清零策略清空余额并写入审计流水",
+			policy: biz.PointDeletionPolicyZeroOut,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(softDeleteSQL).
+					WithArgs(sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(lockPointSQL).
+					WithArgs(int64(2), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "current_points", "total_consumed", "version", "created_at", "updated_at"}).
+						AddRow(20, 2, 30, 5, 4, time.Now(), time.Now()))
+				mock.ExpectExec("UPDATE `user_point` SET `current_points`=\\?,`version`=version \\+ 1,`updated_at`=\\? WHERE id = \\?").
+					WithArgs(0, sqlmock.AnyArg(), int64(20)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `point_transaction` \\(`user_id`,`type`,`amount`,`related_book_id`,`description`,`metadata`\\) VALUES \\(\\?,\\?,\\?,\\?,\\?,CAST\\(\\? AS JSON\\)\\)").
+					WithArgs(int64(2), biz.TransactionTypeConsume, uint32(30), nil, "账号删除，点数清零", `{"source":"account_deletion"}`).
+					WillReturnResult(sqlmock.NewResult(100, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "清零策略余额为0时不写流水",
+			policy: biz.PointDeletionPolicyZeroOut,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(softDeleteSQL).
+					WithArgs(sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(lockPointSQL).
+					WithArgs(int64(2), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "current_points", "total_consumed", "version", "created_at", "updated_at"}).
+						AddRow(20, 2, 0, 5, 4, time.Now(), time.Now()))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "归档策略移动点数记录和流水",
+			policy: biz.PointDeletionPolicyArchive,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(softDeleteSQL).
+					WithArgs(sqlmock.AnyArg(), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `user_point_archive` .* FROM `user_point` WHERE `user_id` = \\?").
+					WithArgs(int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM `user_point` WHERE user_id = \\?").
+					WithArgs(int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `point_transaction_archive` .* FROM `point_transaction` WHERE `user_id` = \\?").
+					WithArgs(int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("DELETE FROM `point_transaction` WHERE user_id = \\?").
+					WithArgs(int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "写入审计流水失败回滚事务",
+			policy: biz.PointDeletionPolicyZeroOut,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(softDeleteSQL).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(lockPointSQL).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "current_points", "total_consumed", "version", "created_at", "updated_at"}).
+						AddRow(20, 2, 30, 5, 4, time.Now(), time.Now()))
+				mock.ExpectExec("UPDATE `user_point` SET `current_points`=\\?").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `point_transaction`").
+					WillReturnError(fmt.Errorf("lock wait timeout"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name:   "用户不存在时回滚事务",
+			policy: biz.PointDeletionPolicyZeroOut,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(softDeleteSQL).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			authRepo, cleanup := NewMemoryAuthRepository(log.DefaultLogger)
+			defer cleanup()
+			uc := biz.NewAccountMaintenanceUsecase(
+				NewUserRepository(db, log.DefaultLogger),
+				authRepo,
+				NewUserPointRepository(db, log.DefaultLogger),
+				NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger),
+				NewTransaction(db),
+				biz.AccountDeletionConfig{PointPolicy: tt.policy},
+				log.DefaultLogger,
+			)
+			tt.mockFn(mock)
+
+			err := uc.DeleteUser(context.Background(), 2)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,7 +2,10 @@ package data
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"user/internal/biz"
@@ -12,6 +15,24 @@ import (
 	"user/internal/pkg/tracing"
 )
 
+// rotateRefreshTokenLua 原子地校验并轮换刷新令牌：KEYS[1] 为旧令牌，KEYS[2] 为新令牌，ARGV 为用户ID和新令牌有效期（毫秒）
+// 旧令牌属于该用户时删除旧令牌、存储新令牌并返回1，属于其他用户返回0，不存在返回-1
+const rotateRefreshTokenLua = `
+local stored = redis.call("GET", KEYS[1])
+if not stored then
+	return -1
+end
+if stored ~= ARGV[1] then
+	return 0
+end
+redis.call("DEL", KEYS[1])
+redis.call("SET", KEYS[2], ARGV[1], "PX", ARGV[2])
+return 1
+`
+
+// rotateRefreshTokenScript 优先使用 EVALSHA 执行 rotateRefreshTokenLua，脚本未缓存时回退到 EVAL
+var rotateRefreshTokenScript = redis.NewScript(rotateRefreshTokenLua)
+
 // authRepository 实现 biz.AuthRepository 接口
 type authRepository struct {
 	data   *Data
@@ -38,7 +59,7 @@ func (r *authRepository) StoreRefreshToken(ctx context.Context, userID int64, re
 
 	r.logger.WithContext(ctx).Infof("Storing refresh token for user_id: %d", userID)
 
-	key := fmt.Sprintf("refresh_token:%s", refreshToken)
+	key := r.data.keys.refreshToken(refreshToken)
 	err := r.data.RedisClient().Set(ctx, key, userID, time.Until(expiresAt)).Err()
 	if err != nil {
 		r.logger.WithContext(ctx).Errorf("Failed to store refresh token for user_id: %d, error_reason: %v", userID, err)
@@ -49,6 +70,23 @@ func (r *authRepository) StoreRefreshToken(ctx context.Context, userID int64, re
 	return nil
 }
 
+// CountActiveSessions 用 SCAN 统计未过期的刷新令牌数量，Redis 不会返回已过期的 key
+func (r *authRepository) CountActiveSessions(ctx context.Context) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.CountActiveSessions")
+	defer span.End()
+
+	iter := r.data.RedisClient().Scan(ctx, 0, r.data.keys.refreshToken("*"), -1).Iterator()
+	var count int64
+	for iter.Next(ctx) {
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to scan refresh tokens, error_reason: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
 // GetUserIDByRefreshToken 根据刷新令牌获取用户ID
 func (r *authRepository) GetUserIDByRefreshToken(ctx context.Context, refreshToken string) (int64, error) {
 	ctx, span := tracing.StartSpan(ctx, "AuthRepository.GetUserIDByRefreshToken")
@@ -60,15 +98,15 @@ func (r *authRepository) GetUserIDByRefreshToken(ctx context.Context, refreshTok
 
 	r.logger.WithContext(ctx).Info("Getting user ID by refresh token")
 
-	key := fmt.Sprintf("refresh_token:%s", refreshToken)
+	key := r.data.keys.refreshToken(refreshToken)
 	val, err := r.data.RedisClient().Get(ctx, key).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			r.logger.WithContext(ctx).Warn("Refresh token not found")
-			return 0, fmt.Errorf("refresh token not found")
+			return 0, biz.ErrTokenNotFound
 		}
 		r.logger.WithContext(ctx).Errorf("Failed to get refresh token, error_reason: %v", err)
-		return 0, err
+		return 0, fmt.Errorf("get refresh token: %w", err)
 	}
 
 	r.logger.WithContext(ctx).Infof("Successfully retrieved user ID: %d by refresh token", val)
@@ -86,9 +124,8 @@ func (r *authRepository) DeleteRefreshToken(ctx context.Context, refreshToken st
 
 	r.logger.WithContext(ctx).Info("Deleting refresh token")
 
-	key := fmt.Sprintf("refresh_token:%s", refreshToken)
-	err := r.data.RedisClient().Del(ctx, key).Err()
-	if err != nil {
+	key := r.data.keys.refreshToken(refreshToken)
+	if err := r.data.RedisClient().Del(ctx, key).Err(); err != nil {
 		r.logger.WithContext(ctx).Errorf("Failed to delete refresh token, error_reason: %v", err)
 		return err
 	}
@@ -108,13 +145,34 @@ func (r *authRepository) DeleteAllRefreshTokens(ctx context.Context, userID int6
 
 	r.logger.WithContext(ctx).Infof("Deleting all refresh tokens for user_id: %d", userID)
 
-	pattern := "refresh_token:*"
+	pattern := r.data.keys.refreshToken("*")
 	iter := r.data.RedisClient().Scan(ctx, 0, pattern, -1).Iterator()
-	var keys []string
-	for iter.Next(ctx) {
+	var keys, failed []string
+	var firstErr error
+	for {
+		// 请求被取消或超时后立即停止扫描，不再发出新的 Redis 命令
+		if err := ctx.Err(); err != nil {
+			r.logger.WithContext(ctx).Warnf("Scan refresh tokens aborted for user_id: %d, error_reason: %v", userID, err)
+			return err
+		}
+		if !iter.Next(ctx) {
+			break
+		}
 		key := iter.Val()
-		val, err := r.data.RedisClient().Get(ctx, key).Int64()
-		if err == nil && val == userID {
+		val, err := r.data.RedisClient().Get(ctx, key).Result()
+		if err == redis.Nil {
+			// 扫描后到读取前已过期或被删除
+			continue
+		}
+		if err != nil {
+			// 无法判断归属的令牌可能属于该用户，记为失败而不是跳过，继续处理其余令牌
+			failed = append(failed, key)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if owner, err := strconv.ParseInt(val, 10, 64); err == nil && owner == userID {
 			keys = append(keys, key)
 		}
 	}
@@ -123,12 +181,25 @@ func (r *authRepository) DeleteAllRefreshTokens(ctx context.Context, userID int6
 		return err
 	}
 
+	var deleted int64
 	if len(keys) > 0 {
-		err := r.data.RedisClient().Del(ctx, keys...).Err()
+		n, err := r.data.RedisClient().Del(ctx, keys...).Result()
 		if err != nil {
-			r.logger.WithContext(ctx).Errorf("Failed to delete refresh tokens for user_id: %d, error_reason: %v", userID, err)
-			return err
+			failed = append(failed, keys...)
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			deleted = n
 		}
+	}
+
+	if len(failed) > 0 {
+		// 令牌 key 含令牌原文，日志只记录数量
+		r.logger.WithContext(ctx).Errorf("Incomplete refresh token revocation for user_id: %d, failed: %d, deleted: %d, error_reason: %v", userID, len(failed), deleted, firstErr)
+		return &biz.RevokeIncompleteError{FailedKeys: failed, Deleted: deleted, Cause: firstErr}
+	}
+	if len(keys) > 0 {
 		r.logger.WithContext(ctx).Infof("Successfully deleted %d refresh tokens for user_id: %d", len(keys), userID)
 	} else {
 		r.logger.WithContext(ctx).Infof("No refresh tokens found to delete for user_id: %d", userID)
@@ -137,9 +208,9 @@ func (r *authRepository) DeleteAllRefreshTokens(ctx context.Context, userID int6
 	return nil
 }
 
-// RefreshTokenAtomically 原子性地刷新令牌
-func (r *authRepository) RefreshTokenAtomically(ctx context.Context, userID int64, oldToken, newToken string, expiresAt time.Time) error {
-	ctx, span := tracing.StartSpan(ctx, "AuthRepository.RefreshTokenAtomically")
+// VerifyAndRotate 使用 Lua 脚本在 Redis 端原子地校验旧令牌归属并完成轮换，避免查询与轮换之间旧令牌被撤销
+func (r *authRepository) VerifyAndRotate(ctx context.Context, oldToken, newToken string, userID int64, ttl time.Duration) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.VerifyAndRotate")
 	defer span.End()
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
@@ -148,26 +219,385 @@ func (r *authRepository) RefreshTokenAtomically(ctx context.Context, userID int6
 		"new_token_length": len(newToken),
 	})
 
-	r.logger.WithContext(ctx).Infof("Atomically refreshing token for user_id: %d", userID)
+	if ttl < time.Millisecond {
+		return false, fmt.Errorf("invalid refresh token ttl: %v", ttl)
+	}
+
+	r.logger.WithContext(ctx).Infof("Atomically rotating refresh token for user_id: %d", userID)
+
+	keys := []string{r.data.keys.refreshToken(oldToken), r.data.keys.refreshToken(newToken)}
+	result, err := rotateRefreshTokenScript.Run(ctx, r.data.RedisClient(), keys, strconv.FormatInt(userID, 10), ttl.Milliseconds()).Int()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to rotate refresh token for user_id: %d, error_reason: %v", userID, err)
+		return false, err
+	}
+
+	switch result {
+	case 1:
+		r.logger.WithContext(ctx).Infof("Successfully rotated refresh token for user_id: %d", userID)
+		tracing.AddSpanEvent(ctx, "token_atomic_refresh_success", map[string]interface{}{
+			"user_id": userID,
+		})
+		return true, nil
+	case 0:
+		r.logger.WithContext(ctx).Warnf("Refresh token belongs to another user, expected user_id: %d", userID)
+		return false, nil
+	default:
+		r.logger.WithContext(ctx).Warnf("Refresh token revoked or already rotated for user_id: %d", userID)
+		return false, nil
+	}
+}
+
+// StoreRotatedTokenPair 以旧刷新令牌为 key 缓存轮换签发的令牌对，供宽限期内的重试使用
+func (r *authRepository) StoreRotatedTokenPair(ctx context.Context, oldToken string, pair *biz.TokenPair, ttl time.Duration) error {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.StoreRotatedTokenPair")
+	defer span.End()
+
+	val, err := json.Marshal(pair)
+	if err != nil {
+		return fmt.Errorf("marshal rotated token pair: %w", err)
+	}
+
+	key := r.data.keys.rotatedRefreshToken(oldToken)
+	if err := r.data.RedisClient().Set(ctx, key, val, ttl).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to store rotated token pair, error_reason: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetRotatedTokenPair 读取旧刷新令牌在宽限期内缓存的令牌对
+func (r *authRepository) GetRotatedTokenPair(ctx context.Context, oldToken string) (*biz.TokenPair, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.GetRotatedTokenPair")
+	defer span.End()
+
+	key := r.data.keys.rotatedRefreshToken(oldToken)
+	val, err := r.data.RedisClient().Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, biz.ErrTokenNotFound
+		}
+		r.logger.WithContext(ctx).Errorf("Failed to get rotated token pair, error_reason: %v", err)
+		return nil, fmt.Errorf("get rotated token pair: %w", err)
+	}
+
+	pair := &biz.TokenPair{}
+	if err := json.Unmarshal(val, pair); err != nil {
+		return nil, fmt.Errorf("unmarshal rotated token pair: %w", err)
+	}
+	return pair, nil
+}
+
+// TrackSession 将刷新令牌记入用户的会话索引，索引的过期时间不短于其中最晚过期的令牌
+func (r *authRepository) TrackSession(ctx context.Context, userID int64, refreshToken string, createdAt, expiresAt time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.TrackSession")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	key := r.data.keys.sessionIndex(userID)
+	err := r.data.RedisClient().ZAdd(ctx, key, &redis.Z{
+		Score:  float64(createdAt.UnixMilli()),
+		Member: refreshToken,
+	}).Err()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to track session for user_id: %d, error_reason: %v", userID, err)
+		return err
+	}
+
+	ttl, err := r.data.RedisClient().TTL(ctx, key).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get session index TTL for user_id: %d, error_reason: %v", userID, err)
+		return err
+	}
+	if newTTL := time.Until(expiresAt); ttl < newTTL {
+		if err := r.data.RedisClient().Expire(ctx, key, newTTL).Err(); err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to extend session index TTL for user_id: %d, error_reason: %v", userID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EvictOldestSessions 按创建时间从旧到新删除用户的会话，直到最多保留 keep 个
+// 已过期、已登出或已轮换的令牌不计入会话数，并顺带从索引中移除
+func (r *authRepository) EvictOldestSessions(ctx context.Context, userID int64, keep int) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.EvictOldestSessions")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+		"keep":    keep,
+	})
+
+	key := r.data.keys.sessionIndex(userID)
+	tokens, err := r.data.RedisClient().ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to list sessions for user_id: %d, error_reason: %v", userID, err)
+		return 0, err
+	}
+
+	var active, stale []string
+	for _, token := range tokens {
+		n, err := r.data.RedisClient().Exists(ctx, r.data.keys.refreshToken(token)).Result()
+		if err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to check session for user_id: %d, error_reason: %v", userID, err)
+			return 0, err
+		}
+		if n == 0 {
+			stale = append(stale, token)
+		} else {
+			active = append(active, token)
+		}
+	}
+
+	var evicted []string
+	if len(active) > keep {
+		evicted = active[:len(active)-keep]
+		keys := make([]string, 0, len(evicted))
+		for _, token := range evicted {
+			keys = append(keys, r.data.keys.refreshToken(token))
+		}
+		if err := r.data.RedisClient().Del(ctx, keys...).Err(); err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to evict sessions for user_id: %d, error_reason: %v", userID, err)
+			return 0, err
+		}
+	}
+
+	if members := append(stale, evicted...); len(members) > 0 {
+		args := make([]interface{}, 0, len(members))
+		for _, m := range members {
+			args = append(args, m)
+		}
+		if err := r.data.RedisClient().ZRem(ctx, key, args...).Err(); err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to clean session index for user_id: %d, error_reason: %v", userID, err)
+			return 0, err
+		}
+	}
+
+	if len(evicted) > 0 {
+		r.logger.WithContext(ctx).Infof("Evicted %d oldest sessions for user_id: %d", len(evicted), userID)
+	}
+	return len(evicted), nil
+}
 
-	pipe := r.data.RedisClient().Pipeline()
+// PairAccessToken 将访问令牌 jti 加入刷新令牌的令牌族，令牌族随刷新令牌一同过期
+func (r *authRepository) PairAccessToken(ctx context.Context, refreshTokenID, accessTokenID string, expiresAt time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.PairAccessToken")
+	defer span.End()
 
-	oldKey := fmt.Sprintf("refresh_token:%s", oldToken)
-	pipe.Del(ctx, oldKey)
+	key := r.data.keys.tokenFamily(refreshTokenID)
+	if err := r.data.RedisClient().SAdd(ctx, key, accessTokenID).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to pair access token with refresh token id: %s, error_reason: %v", refreshTokenID, err)
+		return err
+	}
+	if err := r.data.RedisClient().ExpireAt(ctx, key, expiresAt).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to set token family expiration for refresh token id: %s, error_reason: %v", refreshTokenID, err)
+		return err
+	}
+	return nil
+}
+
+// GetPairedAccessTokenIDs 返回刷新令牌的令牌族中所有访问令牌 jti
+func (r *authRepository) GetPairedAccessTokenIDs(ctx context.Context, refreshTokenID string) ([]string, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.GetPairedAccessTokenIDs")
+	defer span.End()
 
-	newKey := fmt.Sprintf("refresh_token:%s", newToken)
-	pipe.Set(ctx, newKey, userID, time.Until(expiresAt))
+	ids, err := r.data.RedisClient().SMembers(ctx, r.data.keys.tokenFamily(refreshTokenID)).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get token family for refresh token id: %s, error_reason: %v", refreshTokenID, err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetPasswordFailures 返回用户在统计窗口内的密码校验失败次数
+func (r *authRepository) GetPasswordFailures(ctx context.Context, userID int64) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.GetPasswordFailures")
+	defer span.End()
+
+	count, err := r.data.RedisClient().Get(ctx, r.data.keys.passwordFailures(userID)).Int()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		r.logger.WithContext(ctx).Errorf("Failed to get password failures for user_id: %d, error_reason: %v", userID, err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// IncrPasswordFailures 将用户的密码校验失败次数加一，每次失败都会重新设置过期时间
+func (r *authRepository) IncrPasswordFailures(ctx context.Context, userID int64, window time.Duration) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.IncrPasswordFailures")
+	defer span.End()
 
-	_, err := pipe.Exec(ctx)
+	key := r.data.keys.passwordFailures(userID)
+	count, err := r.data.RedisClient().Incr(ctx, key).Result()
 	if err != nil {
-		r.logger.WithContext(ctx).Errorf("Failed to refresh token atomically for user_id: %d, error_reason: %v", userID, err)
+		r.logger.WithContext(ctx).Errorf("Failed to increment password failures for user_id: %d, error_reason: %v", userID, err)
+		return 0, err
+	}
+	if err := r.data.RedisClient().Expire(ctx, key, window).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to set password failures expiration for user_id: %d, error_reason: %v", userID, err)
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// ResetPasswordFailures 清除用户的密码校验失败次数
+func (r *authRepository) ResetPasswordFailures(ctx context.Context, userID int64) error {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.ResetPasswordFailures")
+	defer span.End()
+
+	if err := r.data.RedisClient().Del(ctx, r.data.keys.passwordFailures(userID)).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to reset password failures for user_id: %d, error_reason: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// TouchRefreshToken 记录刷新令牌的最近使用时间，每次使用都会重新设置过期时间
+func (r *authRepository) TouchRefreshToken(ctx context.Context, refreshToken string, usedAt time.Time, idleTimeout time.Duration) error {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.TouchRefreshToken")
+	defer span.End()
+
+	key := r.data.keys.refreshTokenLastUsed(refreshToken)
+	if err := r.data.RedisClient().Set(ctx, key, usedAt.UnixMilli(), idleTimeout).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to record refresh token usage, error_reason: %v", err)
 		return err
 	}
+	return nil
+}
+
+// GetRefreshTokenLastUsed 返回刷新令牌的最近使用时间
+func (r *authRepository) GetRefreshTokenLastUsed(ctx context.Context, refreshToken string) (time.Time, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.GetRefreshTokenLastUsed")
+	defer span.End()
 
-	r.logger.WithContext(ctx).Infof("Successfully refreshed token atomically for user_id: %d", userID)
-	tracing.AddSpanEvent(ctx, "token_atomic_refresh_success", map[string]interface{}{
+	usedAt, err := r.data.RedisClient().Get(ctx, r.data.keys.refreshTokenLastUsed(refreshToken)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, biz.ErrTokenNotFound
+		}
+		r.logger.WithContext(ctx).Errorf("Failed to get refresh token last used time, error_reason: %v", err)
+		return time.Time{}, fmt.Errorf("get refresh token last used: %w", err)
+	}
+	return time.UnixMilli(usedAt), nil
+}
+
+// GetTokensValidAfter 返回全局令牌失效时间点，从未设置时返回零值
+func (r *authRepository) GetTokensValidAfter(ctx context.Context) (time.Time, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.GetTokensValidAfter")
+	defer span.End()
+
+	validAfter, err := r.data.RedisClient().Get(ctx, r.data.keys.tokensValidAfter()).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		r.logger.WithContext(ctx).Errorf("Failed to get tokens valid after, error_reason: %v", err)
+		return time.Time{}, fmt.Errorf("get tokens valid after: %w", err)
+	}
+	return time.UnixMilli(validAfter), nil
+}
+
+// SetTokensValidAfter 设置全局令牌失效时间点，key 不设过期时间
+func (r *authRepository) SetTokensValidAfter(ctx context.Context, validAfter time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.SetTokensValidAfter")
+	defer span.End()
+
+	if err := r.data.RedisClient().Set(ctx, r.data.keys.tokensValidAfter(), validAfter.UnixMilli(), 0).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to set tokens valid after, error_reason: %v", err)
+		return err
+	}
+	return nil
+}
+
+// RecordSessionIP 将刷新令牌签发时的客户端 IP 记入用户的会话 IP 表，表的过期时间不短于其中最晚过期的令牌
+func (r *authRepository) RecordSessionIP(ctx context.Context, userID int64, refreshToken, ip string, expiresAt time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.RecordSessionIP")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
 		"user_id": userID,
 	})
 
+	key := r.data.keys.sessionIPs(strconv.FormatInt(userID, 10))
+	if err := r.data.RedisClient().HSet(ctx, key, refreshToken, ip).Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to record session ip for user_id: %d, error_reason: %v", userID, err)
+		return err
+	}
+
+	ttl, err := r.data.RedisClient().TTL(ctx, key).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get session ip TTL for user_id: %d, error_reason: %v", userID, err)
+		return err
+	}
+	if newTTL := time.Until(expiresAt); ttl < newTTL {
+		if err := r.data.RedisClient().Expire(ctx, key, newTTL).Err(); err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to extend session ip TTL for user_id: %d, error_reason: %v", userID, err)
+			return err
+		}
+	}
 	return nil
 }
+
+// RevokeSessionsByIP 扫描所有用户的会话 IP 表，删除来源 IP 为 ip 的刷新令牌及其记录
+// 返回实际删除的刷新令牌数量，已过期或已登出的令牌只清理记录、不计入数量
+func (r *authRepository) RevokeSessionsByIP(ctx context.Context, ip string) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthRepository.RevokeSessionsByIP")
+	defer span.End()
+
+	iter := r.data.RedisClient().Scan(ctx, 0, r.data.keys.sessionIPs("*"), -1).Iterator()
+	revoked := 0
+	for {
+		// 请求被取消或超时后立即停止扫描，不再发出新的 Redis 命令
+		if err := ctx.Err(); err != nil {
+			r.logger.WithContext(ctx).Warnf("Scan session ips aborted for ip: %s, error_reason: %v", ip, err)
+			return revoked, err
+		}
+		if !iter.Next(ctx) {
+			break
+		}
+		key := iter.Val()
+		sessions, err := r.data.RedisClient().HGetAll(ctx, key).Result()
+		if err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to get session ips from key: %s, error_reason: %v", key, err)
+			return revoked, err
+		}
+
+		var tokens, tokenKeys []string
+		for token, sessionIP := range sessions {
+			if sessionIP == ip {
+				tokens = append(tokens, token)
+				tokenKeys = append(tokenKeys, r.data.keys.refreshToken(token))
+			}
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		sort.Strings(tokens)
+		sort.Strings(tokenKeys)
+
+		deleted, err := r.data.RedisClient().Del(ctx, tokenKeys...).Result()
+		if err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to delete refresh tokens for ip: %s, error_reason: %v", ip, err)
+			return revoked, err
+		}
+		revoked += int(deleted)
+		if err := r.data.RedisClient().HDel(ctx, key, tokens...).Err(); err != nil {
+			r.logger.WithContext(ctx).Warnf("Failed to remove revoked session ips from key: %s, error_reason: %v", key, err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to scan session ips for ip: %s, error_reason: %v", ip, err)
+		return revoked, err
+	}
+
+	r.logger.WithContext(ctx).Infof("Revoked %d refresh tokens for ip: %s", revoked, ip)
+	return revoked, nil
+}
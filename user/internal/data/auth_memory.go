@@ -0,0 +1,394 @@
+package data
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"user/internal/biz"
+	"user/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// memoryRefreshToken 内存中保存的刷新令牌
+type memoryRefreshToken struct {
+	userID    int64
+	createdAt time.Time
+	expiresAt time.Time
+	// lastUsedAt 最近使用时间，在 idleExpiresAt 之后视为没有使用记录
+	lastUsedAt    time.Time
+	idleExpiresAt time.Time
+	// ip 签发时的客户端 IP，未记录时为空
+	ip string
+}
+
+// memoryTokenFamily 内存中保存的令牌族，记录与同一刷新令牌配对的访问令牌 jti
+type memoryTokenFamily struct {
+	accessTokenIDs []string
+	expiresAt      time.Time
+}
+
+// memoryRotatedPair 内存中保存的已轮换刷新令牌在宽限期内的令牌对
+type memoryRotatedPair struct {
+	pair      biz.TokenPair
+	expiresAt time.Time
+}
+
+// memoryFailureCounter 内存中保存的密码校验失败计数
+type memoryFailureCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// memoryAuthRepository 基于内存的认证数据访问实现，所有操作由同一把锁保护
+type memoryAuthRepository struct {
+	mu       sync.Mutex
+	tokens   map[string]memoryRefreshToken
+	families map[string]memoryTokenFamily
+	rotated  map[string]memoryRotatedPair
+	failures map[int64]memoryFailureCounter
+	// tokensValidAfter 全局令牌失效时间点，从未设置时为零值
+	tokensValidAfter time.Time
+	now              func() time.Time
+	logger           *log.Helper
+}
+
+// NewMemoryAuthRepository 创建内存认证数据访问实例，返回的函数用于停止后台清理
+func NewMemoryAuthRepository(logger log.Logger) (biz.AuthRepository, func()) {
+	r := newMemoryAuthRepository(logger)
+	stop := runJanitor(memoryJanitorInterval, r.purgeExpired)
+	return r, stop
+}
+
+func newMemoryAuthRepository(logger log.Logger) *memoryAuthRepository {
+	return &memoryAuthRepository{
+		tokens:   make(map[string]memoryRefreshToken),
+		families: make(map[string]memoryTokenFamily),
+		rotated:  make(map[string]memoryRotatedPair),
+		failures: make(map[int64]memoryFailureCounter),
+		now:      time.Now,
+		logger:   log.NewHelper(logger),
+	}
+}
+
+// NewAuthRepositoryFromConfig 根据 cache_driver 配置选择刷新令牌存储实现
+func NewAuthRepositoryFromConfig(c *conf.Data, data *Data, logger log.Logger) (biz.AuthRepository, func()) {
+	if c.CacheDriver == CacheDriverMemory {
+		return NewMemoryAuthRepository(logger)
+	}
+	return NewAuthRepository(data, logger), func() {}
+}
+
+// get 获取未过期的令牌，已过期的令牌会被删除
+func (r *memoryAuthRepository) get(refreshToken string) (memoryRefreshToken, bool) {
+	token, ok := r.tokens[refreshToken]
+	if !ok {
+		return memoryRefreshToken{}, false
+	}
+	if !r.now().Before(token.expiresAt) {
+		delete(r.tokens, refreshToken)
+		return memoryRefreshToken{}, false
+	}
+	return token, true
+}
+
+// CountActiveSessions 统计未过期的刷新令牌数量，已过期但尚未被清理的令牌不计入
+func (r *memoryAuthRepository) CountActiveSessions(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var count int64
+	for _, token := range r.tokens {
+		if now.Before(token.expiresAt) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// purgeExpired 删除所有已过期的令牌、令牌族、轮换缓存和失败计数
+func (r *memoryAuthRepository) purgeExpired() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for key, token := range r.tokens {
+		if !now.Before(token.expiresAt) {
+			delete(r.tokens, key)
+		}
+	}
+	for key, family := range r.families {
+		if !now.Before(family.expiresAt) {
+			delete(r.families, key)
+		}
+	}
+	for key, rotated := range r.rotated {
+		if !now.Before(rotated.expiresAt) {
+			delete(r.rotated, key)
+		}
+	}
+	for userID, counter := range r.failures {
+		if !now.Before(counter.expiresAt) {
+			delete(r.failures, userID)
+		}
+	}
+}
+
+// StoreRefreshToken 存储刷新令牌
+func (r *memoryAuthRepository) StoreRefreshToken(ctx context.Context, userID int64, refreshToken string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[refreshToken] = memoryRefreshToken{userID: userID, createdAt: r.now(), expiresAt: expiresAt}
+	return nil
+}
+
+// GetUserIDByRefreshToken 根据刷新令牌获取用户ID
+func (r *memoryAuthRepository) GetUserIDByRefreshToken(ctx context.Context, refreshToken string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.get(refreshToken)
+	if !ok {
+		r.logger.WithContext(ctx).Warn("Refresh token not found")
+		return 0, biz.ErrTokenNotFound
+	}
+	return token.userID, nil
+}
+
+// DeleteRefreshToken 删除刷新令牌
+func (r *memoryAuthRepository) DeleteRefreshToken(ctx context.Context, refreshToken string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tokens, refreshToken)
+	return nil
+}
+
+// DeleteAllRefreshTokens 删除用户的所有刷新令牌
+func (r *memoryAuthRepository) DeleteAllRefreshTokens(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, token := range r.tokens {
+		if token.userID == userID {
+			delete(r.tokens, key)
+		}
+	}
+	return nil
+}
+
+// VerifyAndRotate 在同一把锁内校验旧令牌归属并完成轮换
+// 旧令牌已被轮换或不属于该用户时返回 false，保证并发刷新只有一个成功
+func (r *memoryAuthRepository) VerifyAndRotate(ctx context.Context, oldToken, newToken string, userID int64, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.get(oldToken)
+	if !ok || token.userID != userID {
+		r.logger.WithContext(ctx).Warnf("Refresh token already rotated or not found for user_id: %d", userID)
+		return false, nil
+	}
+
+	now := r.now()
+	delete(r.tokens, oldToken)
+	r.tokens[newToken] = memoryRefreshToken{userID: userID, createdAt: now, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// StoreRotatedTokenPair 以旧刷新令牌为 key 缓存轮换签发的令牌对
+func (r *memoryAuthRepository) StoreRotatedTokenPair(ctx context.Context, oldToken string, pair *biz.TokenPair, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rotated[oldToken] = memoryRotatedPair{pair: *pair, expiresAt: r.now().Add(ttl)}
+	return nil
+}
+
+// GetRotatedTokenPair 读取旧刷新令牌在宽限期内缓存的令牌对
+func (r *memoryAuthRepository) GetRotatedTokenPair(ctx context.Context, oldToken string) (*biz.TokenPair, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rotated, ok := r.rotated[oldToken]
+	if !ok || !r.now().Before(rotated.expiresAt) {
+		delete(r.rotated, oldToken)
+		return nil, biz.ErrTokenNotFound
+	}
+	pair := rotated.pair
+	return &pair, nil
+}
+
+// TrackSession 记录会话的创建时间，内存实现直接使用令牌表作为会话索引
+func (r *memoryAuthRepository) TrackSession(ctx context.Context, userID int64, refreshToken string, createdAt, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.get(refreshToken); ok && token.userID == userID {
+		token.createdAt = createdAt
+		r.tokens[refreshToken] = token
+	}
+	return nil
+}
+
+// EvictOldestSessions 按创建时间从旧到新删除用户的会话，直到最多保留 keep 个
+func (r *memoryAuthRepository) EvictOldestSessions(ctx context.Context, userID int64, keep int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sessions []string
+	for key := range r.tokens {
+		if token, ok := r.get(key); ok && token.userID == userID {
+			sessions = append(sessions, key)
+		}
+	}
+	if len(sessions) <= keep {
+		return 0, nil
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return r.tokens[sessions[i]].createdAt.Before(r.tokens[sessions[j]].createdAt)
+	})
+	evicted := sessions[:len(sessions)-keep]
+	for _, key := range evicted {
+		delete(r.tokens, key)
+	}
+	return len(evicted), nil
+}
+
+// PairAccessToken 将访问令牌 jti 加入刷新令牌的令牌族，令牌族随刷新令牌一同过期
+func (r *memoryAuthRepository) PairAccessToken(ctx context.Context, refreshTokenID, accessTokenID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	family := r.families[refreshTokenID]
+	if !r.now().Before(family.expiresAt) {
+		family = memoryTokenFamily{}
+	}
+	family.accessTokenIDs = append(family.accessTokenIDs, accessTokenID)
+	family.expiresAt = expiresAt
+	r.families[refreshTokenID] = family
+	return nil
+}
+
+// GetPairedAccessTokenIDs 返回刷新令牌的令牌族中所有访问令牌 jti
+func (r *memoryAuthRepository) GetPairedAccessTokenIDs(ctx context.Context, refreshTokenID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	family, ok := r.families[refreshTokenID]
+	if !ok || !r.now().Before(family.expiresAt) {
+		delete(r.families, refreshTokenID)
+		return []string{}, nil
+	}
+	return append([]string(nil), family.accessTokenIDs...), nil
+}
+
+// GetPasswordFailures 返回用户在统计窗口内的密码校验失败次数
+func (r *memoryAuthRepository) GetPasswordFailures(ctx context.Context, userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.failures[userID]
+	if !ok || !r.now().Before(counter.expiresAt) {
+		delete(r.failures, userID)
+		return 0, nil
+	}
+	return counter.count, nil
+}
+
+// IncrPasswordFailures 将用户的密码校验失败次数加一，每次失败都会重新设置过期时间
+func (r *memoryAuthRepository) IncrPasswordFailures(ctx context.Context, userID int64, window time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	counter := r.failures[userID]
+	if !now.Before(counter.expiresAt) {
+		counter = memoryFailureCounter{}
+	}
+	counter.count++
+	counter.expiresAt = now.Add(window)
+	r.failures[userID] = counter
+	return counter.count, nil
+}
+
+// ResetPasswordFailures 清除用户的密码校验失败次数
+func (r *memoryAuthRepository) ResetPasswordFailures(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.failures, userID)
+	return nil
+}
+
+// TouchRefreshToken 记录刷新令牌的最近使用时间，令牌不存在时不做任何操作
+func (r *memoryAuthRepository) TouchRefreshToken(ctx context.Context, refreshToken string, usedAt time.Time, idleTimeout time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.get(refreshToken); ok {
+		token.lastUsedAt = usedAt
+		token.idleExpiresAt = r.now().Add(idleTimeout)
+		r.tokens[refreshToken] = token
+	}
+	return nil
+}
+
+// GetRefreshTokenLastUsed 返回刷新令牌的最近使用时间
+func (r *memoryAuthRepository) GetRefreshTokenLastUsed(ctx context.Context, refreshToken string) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.get(refreshToken)
+	if !ok || token.lastUsedAt.IsZero() || !r.now().Before(token.idleExpiresAt) {
+		return time.Time{}, biz.ErrTokenNotFound
+	}
+	return token.lastUsedAt, nil
+}
+
+// RecordSessionIP 记录刷新令牌签发时的客户端 IP，令牌不存在或不属于 userID 时不做任何操作
+func (r *memoryAuthRepository) RecordSessionIP(ctx context.Context, userID int64, refreshToken, ip string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.get(refreshToken); ok && token.userID == userID {
+		token.ip = ip
+		r.tokens[refreshToken] = token
+	}
+	return nil
+}
+
+// RevokeSessionsByIP 删除所有来源 IP 为 ip 的刷新令牌
+func (r *memoryAuthRepository) RevokeSessionsByIP(ctx context.Context, ip string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revoked := 0
+	for key := range r.tokens {
+		if token, ok := r.get(key); ok && token.ip == ip {
+			delete(r.tokens, key)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+// GetTokensValidAfter 返回全局令牌失效时间点
+func (r *memoryAuthRepository) GetTokensValidAfter(ctx context.Context) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.tokensValidAfter, nil
+}
+
+// SetTokensValidAfter 设置全局令牌失效时间点
+func (r *memoryAuthRepository) SetTokensValidAfter(ctx context.Context, validAfter time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokensValidAfter = validAfter
+	return nil
+}
@@ -0,0 +1,362 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"user/internal/biz"
+)
+
+// newTestMemoryAuthRepository 创建使用可控时钟的内存认证仓库
+func newTestMemoryAuthRepository(now *time.Time) *memoryAuthRepository {
+	r := newMemoryAuthRepository(log.DefaultLogger)
+	r.now = func() time.Time { return *now }
+	return r
+}
+
+// TestMemoryAuthRepository_StoreGetDelete 测试刷新令牌的存储、获取、删除及过期
+func TestMemoryAuthRepository_StoreGetDelete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-a", now.Add(time.Hour)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-b", now.Add(time.Minute)))
+
+	userID, err := repo.GetUserIDByRefreshToken(ctx, "token-a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), userID)
+
+	require.NoError(t, repo.DeleteRefreshToken(ctx, "token-a"))
+	_, err = repo.GetUserIDByRefreshToken(ctx, "token-a")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound)
+
+	now = now.Add(2 * time.Minute)
+	_, err = repo.GetUserIDByRefreshToken(ctx, "token-b")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound, "过期令牌不应返回")
+}
+
+// TestMemoryAuthRepository_DeleteAllRefreshTokens 测试只删除指定用户的令牌
+func TestMemoryAuthRepository_DeleteAllRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-a", now.Add(time.Hour)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-b", now.Add(time.Hour)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 2, "token-c", now.Add(time.Hour)))
+
+	require.NoError(t, repo.DeleteAllRefreshTokens(ctx, 1))
+
+	_, err := repo.GetUserIDByRefreshToken(ctx, "token-a")
+	assert.Error(t, err)
+	_, err = repo.GetUserIDByRefreshToken(ctx, "token-b")
+	assert.Error(t, err)
+	userID, err := repo.GetUserIDByRefreshToken(ctx, "token-c")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), userID)
+}
+
+// TestMemoryAuthRepository_VerifyAndRotate 测试令牌轮换
+func TestMemoryAuthRepository_VerifyAndRotate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name     string
+		userID   int64
+		oldToken string
+		wantOK   bool
+	}{
+		{
+			name:     "轮换成功",
+			userID:   1,
+			oldToken: "old-token",
+			wantOK:   true,
+		},
+		{
+			name:     "旧令牌不存在",
+			userID:   1,
+			oldToken: "missing-token",
+		},
+		{
+			name:     "旧令牌属于其他用户",
+			userID:   2,
+			oldToken: "old-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := now
+			repo := newTestMemoryAuthRepository(&current)
+			require.NoError(t, repo.StoreRefreshToken(ctx, 1, "old-token", now.Add(time.Hour)))
+
+			ok, err := repo.VerifyAndRotate(ctx, tt.oldToken, "new-token", tt.userID, time.Hour)
+			require.NoError(t, err)
+
+			if !tt.wantOK {
+				assert.False(t, ok)
+				_, err = repo.GetUserIDByRefreshToken(ctx, "new-token")
+				assert.Error(t, err, "失败时不应存储新令牌")
+				_, err = repo.GetUserIDByRefreshToken(ctx, "old-token")
+				assert.NoError(t, err, "失败时旧令牌应保持有效")
+				return
+			}
+			assert.True(t, ok)
+			_, err = repo.GetUserIDByRefreshToken(ctx, "old-token")
+			assert.Error(t, err)
+			userID, err := repo.GetUserIDByRefreshToken(ctx, "new-token")
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), userID)
+		})
+	}
+}
+
+// TestMemoryAuthRepository_VerifyAndRotate_Concurrent 测试并发刷新同一令牌时只有一个成功，且用户始终保有一个有效令牌
+func TestMemoryAuthRepository_VerifyAndRotate_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "old-token", now.Add(time.Hour)))
+
+	const workers = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var winners []string
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			newToken := fmt.Sprintf("new-token-%d", i)
+			if ok, err := repo.VerifyAndRotate(ctx, "old-token", newToken, 1, time.Hour); err == nil && ok {
+				mu.Lock()
+				winners = append(winners, newToken)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, winners, 1, "同一旧令牌只能轮换一次")
+	assert.Len(t, repo.tokens, 1, "用户应只保有一个有效令牌")
+	userID, err := repo.GetUserIDByRefreshToken(ctx, winners[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), userID)
+}
+
+// TestMemoryAuthRepository_EvictOldestSessions 测试超过会话上限的登录会踢出最早的会话
+func TestMemoryAuthRepository_EvictOldestSessions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	const maxSessions = 2
+	// 按登录流程依次创建 max+1 个会话
+	for i := 1; i <= maxSessions+1; i++ {
+		_, err := repo.EvictOldestSessions(ctx, 1, maxSessions-1)
+		require.NoError(t, err)
+		token := fmt.Sprintf("token-%d", i)
+		require.NoError(t, repo.StoreRefreshToken(ctx, 1, token, now.Add(time.Hour)))
+		require.NoError(t, repo.TrackSession(ctx, 1, token, now, now.Add(time.Hour)))
+		now = now.Add(time.Minute)
+	}
+	require.NoError(t, repo.StoreRefreshToken(ctx, 2, "other-user-token", now.Add(time.Hour)))
+
+	_, err := repo.GetUserIDByRefreshToken(ctx, "token-1")
+	assert.Error(t, err, "最早的会话应被踢出")
+	for _, token := range []string{"token-2", "token-3", "other-user-token"} {
+		_, err := repo.GetUserIDByRefreshToken(ctx, token)
+		assert.NoError(t, err, token)
+	}
+
+	evicted, err := repo.EvictOldestSessions(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, evicted)
+	_, err = repo.GetUserIDByRefreshToken(ctx, "other-user-token")
+	assert.NoError(t, err, "不影响其他用户的会话")
+}
+
+// TestMemoryAuthRepository_CountActiveSessions 测试活跃会话数只统计未过期的令牌，过期令牌在清理前也不计入
+func TestMemoryAuthRepository_CountActiveSessions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-a", now.Add(time.Hour)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-b", now.Add(time.Minute)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 2, "token-c", now.Add(time.Hour)))
+	count, err := repo.CountActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// 轮换不改变会话数
+	rotated, err := repo.VerifyAndRotate(ctx, "token-a", "token-d", 1, time.Hour)
+	require.NoError(t, err)
+	require.True(t, rotated)
+	count, err = repo.CountActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	now = now.Add(2 * time.Minute)
+	count, err = repo.CountActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count, "过期的令牌不计入")
+
+	require.NoError(t, repo.DeleteRefreshToken(ctx, "token-c"))
+	count, err = repo.CountActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestMemoryAuthRepository_PairAccessToken 测试令牌族的记录、读取及过期
+func TestMemoryAuthRepository_PairAccessToken(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+	expiresAt := now.Add(time.Hour)
+
+	require.NoError(t, repo.PairAccessToken(ctx, "refresh-jti", "access-jti-1", expiresAt))
+	require.NoError(t, repo.PairAccessToken(ctx, "refresh-jti", "access-jti-2", expiresAt))
+	require.NoError(t, repo.PairAccessToken(ctx, "other-jti", "access-jti-3", expiresAt))
+
+	ids, err := repo.GetPairedAccessTokenIDs(ctx, "refresh-jti")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"access-jti-1", "access-jti-2"}, ids)
+
+	now = expiresAt
+	ids, err = repo.GetPairedAccessTokenIDs(ctx, "refresh-jti")
+	require.NoError(t, err)
+	assert.Empty(t, ids, "令牌族随刷新令牌过期")
+}
+
+// TestMemoryAuthRepository_PasswordFailures 测试密码校验失败次数的累计、过期和清除
+func TestMemoryAuthRepository_PasswordFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	for i := 1; i <= 3; i++ {
+		count, err := repo.IncrPasswordFailures(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+	count, err := repo.GetPasswordFailures(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = repo.GetPasswordFailures(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "不同用户互不影响")
+
+	now = now.Add(time.Minute)
+	count, err = repo.GetPasswordFailures(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "统计窗口过后计数清零")
+
+	_, err = repo.IncrPasswordFailures(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, repo.ResetPasswordFailures(ctx, 1))
+	count, err = repo.GetPasswordFailures(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "清除后计数为 0")
+}
+
+// TestMemoryAuthRepository_RotatedTokenPair 测试轮换结果只在宽限期内可读取
+func TestMemoryAuthRepository_RotatedTokenPair(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	_, err := repo.GetRotatedTokenPair(ctx, "old-token")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound)
+
+	pair := &biz.TokenPair{AccessToken: "access", RefreshToken: "new-token", RefreshTokenRotated: true}
+	require.NoError(t, repo.StoreRotatedTokenPair(ctx, "old-token", pair, 10*time.Second))
+
+	now = now.Add(5 * time.Second)
+	got, err := repo.GetRotatedTokenPair(ctx, "old-token")
+	require.NoError(t, err)
+	assert.Equal(t, pair, got)
+
+	now = now.Add(5 * time.Second)
+	_, err = repo.GetRotatedTokenPair(ctx, "old-token")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound, "超出宽限期后不应返回")
+}
+
+// TestMemoryAuthRepository_RefreshTokenLastUsed 测试最近使用时间只在空闲期内可读取
+func TestMemoryAuthRepository_RefreshTokenLastUsed(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-1", now.Add(7*24*time.Hour)))
+
+	_, err := repo.GetRefreshTokenLastUsed(ctx, "token-1")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound, "签发后未使用时没有记录")
+
+	usedAt := now
+	require.NoError(t, repo.TouchRefreshToken(ctx, "token-1", usedAt, time.Hour))
+	now = now.Add(30 * time.Minute)
+	got, err := repo.GetRefreshTokenLastUsed(ctx, "token-1")
+	require.NoError(t, err)
+	assert.Equal(t, usedAt, got)
+
+	now = now.Add(30 * time.Minute)
+	_, err = repo.GetRefreshTokenLastUsed(ctx, "token-1")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound, "超出空闲期后不应返回")
+
+	require.NoError(t, repo.TouchRefreshToken(ctx, "missing-token", now, time.Hour))
+	_, err = repo.GetRefreshTokenLastUsed(ctx, "missing-token")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound, "令牌不存在时不记录")
+}
+
+// TestMemoryAuthRepository_RevokeSessionsByIP 测试跨用户删除来源 IP 匹配的令牌，其他 IP 和未记录 IP 的令牌不受影响
+func TestMemoryAuthRepository_RevokeSessionsByIP(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	require.NoError(t, repo.StoreRefreshToken(ctx, 1, "token-a", now.Add(time.Hour)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 2, "token-b", now.Add(time.Hour)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 2, "token-c", now.Add(time.Hour)))
+	require.NoError(t, repo.StoreRefreshToken(ctx, 3, "token-d", now.Add(time.Hour)))
+	require.NoError(t, repo.RecordSessionIP(ctx, 1, "token-a", "1.2.3.4", now.Add(time.Hour)))
+	require.NoError(t, repo.RecordSessionIP(ctx, 2, "token-b", "1.2.3.4", now.Add(time.Hour)))
+	require.NoError(t, repo.RecordSessionIP(ctx, 2, "token-c", "5.6.7.8", now.Add(time.Hour)))
+
+	revoked, err := repo.RevokeSessionsByIP(ctx, "1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, 2, revoked)
+
+	for _, token := range []string{"token-a", "token-b"} {
+		_, err := repo.GetUserIDByRefreshToken(ctx, token)
+		assert.ErrorIs(t, err, biz.ErrTokenNotFound, token)
+	}
+	for _, token := range []string{"token-c", "token-d"} {
+		_, err := repo.GetUserIDByRefreshToken(ctx, token)
+		assert.NoError(t, err, token)
+	}
+}
+
+// TestMemoryAuthRepository_TokensValidAfter 测试全局令牌失效时间点从零值开始，设置后读回
+func TestMemoryAuthRepository_TokensValidAfter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryAuthRepository(&now)
+
+	got, err := repo.GetTokensValidAfter(ctx)
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	require.NoError(t, repo.SetTokensValidAfter(ctx, now))
+	got, err = repo.GetTokensValidAfter(ctx)
+	require.NoError(t, err)
+	assert.True(t, now.Equal(got))
+}
@@ -2,6 +2,8 @@ package data
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -9,8 +11,10 @@ import (
 	"user/internal/biz"
 
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
 	"github.com/go-redis/redismock/v8"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestAuthRepository_StoreRefreshToken 测试存储刷新令牌
@@ -87,6 +91,7 @@ func TestAuthRepository_GetUserIDByRefreshToken(t *testing.T) {
 		expectedID   int64
 		wantErr      bool
 		expectErrMsg string
+		wantNotFound bool
 	}{
 		{
 			name:  "成功获取用户ID",
@@ -108,6 +113,7 @@ func TestAuthRepository_GetUserIDByRefreshToken(t *testing.T) {
 			expectedID:   0,
 			wantErr:      true,
 			expectErrMsg: "refresh token not found",
+			wantNotFound: true,
 		},
 		{
 			name:  "Redis返回错误",
@@ -145,6 +151,11 @@ func TestAuthRepository_GetUserIDByRefreshToken(t *testing.T) {
 				if tt.expectErrMsg != "" {
 					assert.Contains(t, err.Error(), tt.expectErrMsg)
 				}
+				// 只有令牌不存在时返回 ErrTokenNotFound，连接错误保留原始错误链
+				assert.Equal(t, tt.wantNotFound, errors.Is(err, biz.ErrTokenNotFound))
+				if !tt.wantNotFound {
+					assert.ErrorIs(t, err, assert.AnError)
+				}
 				assert.Equal(t, int64(0), userID)
 			} else {
 				assert.NoError(t, err)
@@ -225,13 +236,16 @@ func TestAuthRepository_DeleteRefreshToken(t *testing.T) {
 	}
 }
 
-// TestAuthRepository_DeleteAllRefreshTokens 测试删除用户的所有刷新令牌
+// TestAuthRepository_DeleteAllRefreshTokens 测试删除用户的所有刷新令牌，部分令牌未能判断归属或删除时返回部分失败
 func TestAuthRepository_DeleteAllRefreshTokens(t *testing.T) {
 	tests := []struct {
 		name    string
 		userID  int64
 		mockFn  func(mock redismock.ClientMock)
 		wantErr bool
+		// wantIncomplete 非 nil 时期望返回部分失败，内容为未完成的令牌 key
+		wantIncomplete []string
+		wantDeleted    int64
 	}{
 		{
 			name:   "成功删除用户的所有刷新令牌",
@@ -272,17 +286,45 @@ func TestAuthRepository_DeleteAllRefreshTokens(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:   "GET操作出错但继续处理",
+			name:   "GET操作出错时报告部分失败",
 			userID: 789,
 			mockFn: func(mock redismock.ClientMock) {
-				// 模拟 SCAN 操作返回一些键
 				keys := []string{"refresh_token:token1"}
 				mock.ExpectScan(0, "refresh_token:*", -1).SetVal(keys, 0)
 
-				// 模拟 GET 操作出错（但实际实现中会忽略错误继续处理）
+				// 无法判断归属的令牌可能属于该用户，不能当作不匹配
 				mock.ExpectGet("refresh_token:token1").SetErr(assert.AnError)
 			},
-			wantErr: false, // 实际实现中GET错误不会导致整体失败
+			wantErr:        true,
+			wantIncomplete: []string{"refresh_token:token1"},
+		},
+		{
+			name:   "扫描中途GET出错时继续删除其余令牌",
+			userID: 789,
+			mockFn: func(mock redismock.ClientMock) {
+				keys := []string{"refresh_token:token1", "refresh_token:token2", "refresh_token:token3"}
+				mock.ExpectScan(0, "refresh_token:*", -1).SetVal(keys, 0)
+
+				mock.ExpectGet("refresh_token:token1").SetVal("789")
+				mock.ExpectGet("refresh_token:token2").SetErr(assert.AnError)
+				mock.ExpectGet("refresh_token:token3").SetVal("789")
+
+				mock.ExpectDel("refresh_token:token1", "refresh_token:token3").SetVal(2)
+			},
+			wantErr:        true,
+			wantIncomplete: []string{"refresh_token:token2"},
+			wantDeleted:    2,
+		},
+		{
+			name:   "扫描后令牌已过期",
+			userID: 789,
+			mockFn: func(mock redismock.ClientMock) {
+				keys := []string{"refresh_token:token1"}
+				mock.ExpectScan(0, "refresh_token:*", -1).SetVal(keys, 0)
+
+				mock.ExpectGet("refresh_token:token1").RedisNil()
+			},
+			wantErr: false,
 		},
 		{
 			name:   "DEL操作出错",
@@ -298,7 +340,8 @@ func TestAuthRepository_DeleteAllRefreshTokens(t *testing.T) {
 				// 模拟 DEL 操作出错
 				mock.ExpectDel("refresh_token:token1").RedisNil()
 			},
-			wantErr: true,
+			wantErr:        true,
+			wantIncomplete: []string{"refresh_token:token1"},
 		},
 		{
 			name:   "过滤不匹配的用户ID",
@@ -343,6 +386,14 @@ func TestAuthRepository_DeleteAllRefreshTokens(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
+			if tt.wantIncomplete != nil {
+				var incomplete *biz.RevokeIncompleteError
+				require.ErrorAs(t, err, &incomplete)
+				assert.ErrorIs(t, err, biz.ErrRevokeIncomplete)
+				assert.Error(t, incomplete.Cause)
+				assert.Equal(t, tt.wantIncomplete, incomplete.FailedKeys)
+				assert.Equal(t, tt.wantDeleted, incomplete.Deleted)
+			}
 
 			// 验证所有期望都被调用
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -350,63 +401,34 @@ func TestAuthRepository_DeleteAllRefreshTokens(t *testing.T) {
 	}
 }
 
-// TestAuthRepository_RefreshTokenAtomically 测试原子性地刷新令牌
-func TestAuthRepository_RefreshTokenAtomically(t *testing.T) {
+// TestAuthRepository_CountActiveSessions 测试按 SCAN 返回的刷新令牌 key 统计活跃会话数，跨多页扫描时累加
+func TestAuthRepository_CountActiveSessions(t *testing.T) {
 	tests := []struct {
-		name      string
-		userID    int64
-		oldToken  string
-		newToken  string
-		expiresAt time.Time
-		mockFn    func(mock redismock.ClientMock)
-		wantErr   bool
+		name    string
+		mockFn  func(mock redismock.ClientMock)
+		want    int64
+		wantErr bool
 	}{
 		{
-			name:      "成功原子性刷新令牌",
-			userID:    123,
-			oldToken:  "old_token",
-			newToken:  "new_token",
-			expiresAt: time.Now().Add(7 * 24 * time.Hour),
+			name: "多页扫描累加",
 			mockFn: func(mock redismock.ClientMock) {
-				// 模拟 DEL 操作删除旧令牌
-				oldKey := fmt.Sprintf("refresh_token:%s", "old_token")
-				mock.ExpectDel(oldKey).SetVal(1)
-
-				// 模拟 SET 操作存储新令牌
-				newKey := fmt.Sprintf("refresh_token:%s", "new_token")
-				mock.ExpectSet(newKey, int64(123), time.Until(time.Now().Add(7*24*time.Hour))).SetVal("OK")
+				mock.ExpectScan(0, "refresh_token:*", -1).SetVal([]string{"refresh_token:token1", "refresh_token:token2"}, 7)
+				mock.ExpectScan(7, "refresh_token:*", -1).SetVal([]string{"refresh_token:token3"}, 0)
 			},
-			wantErr: false,
+			want: 3,
 		},
 		{
-			name:      "删除旧令牌失败",
-			userID:    456,
-			oldToken:  "old_token_error",
-			newToken:  "new_token",
-			expiresAt: time.Now().Add(24 * time.Hour),
+			name: "令牌过期后不再计入",
 			mockFn: func(mock redismock.ClientMock) {
-				// 模拟 DEL 操作失败
-				oldKey := fmt.Sprintf("refresh_token:%s", "old_token_error")
-				mock.ExpectDel(oldKey).RedisNil()
-
-				// 不应该有 SET 操作
+				// Redis 在 TTL 到期后删除 key，SCAN 不再返回
+				mock.ExpectScan(0, "refresh_token:*", -1).SetVal([]string{}, 0)
 			},
-			wantErr: true,
+			want: 0,
 		},
 		{
-			name:      "存储新令牌失败",
-			userID:    789,
-			oldToken:  "old_token",
-			newToken:  "new_token_error",
-			expiresAt: time.Now().Add(12 * time.Hour),
+			name: "SCAN操作出错",
 			mockFn: func(mock redismock.ClientMock) {
-				// 模拟 DEL 操作成功删除旧令牌
-				oldKey := fmt.Sprintf("refresh_token:%s", "old_token")
-				mock.ExpectDel(oldKey).SetVal(1)
-
-				// 模拟 SET 操作失败
-				newKey := fmt.Sprintf("refresh_token:%s", "new_token_error")
-				mock.ExpectSet(newKey, int64(789), time.Until(time.Now().Add(12*time.Hour))).RedisNil()
+				mock.ExpectScan(0, "refresh_token:*", -1).SetErr(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -414,30 +436,122 @@ func TestAuthRepository_RefreshTokenAtomically(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// 创建 Redis mock
 			rds, mock := redismock.NewClientMock()
+			tt.mockFn(mock)
+			repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
 
-			// 创建 Data 结构体
-			data := &Data{
-				rds: rds,
-				db:  nil,
+			got, err := repo.CountActiveSessions(context.Background())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
 
-			// 创建 auth repository
-			repo := NewAuthRepository(data, log.DefaultLogger)
+// TestAuthRepository_DeleteAllRefreshTokens_Canceled 测试上下文已取消时立即返回且不再访问 Redis
+func TestAuthRepository_DeleteAllRefreshTokens_Canceled(t *testing.T) {
+	rds, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
 
-			// 设置 mock 期望
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := repo.DeleteAllRefreshTokens(ctx, 123)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	// 没有设置任何期望，若发出了 SCAN/GET/DEL 命令会返回非 context 错误
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAuthRepository_VerifyAndRotate 测试原子地校验并轮换刷新令牌
+func TestAuthRepository_VerifyAndRotate(t *testing.T) {
+	keys := []string{"refresh_token:old_token", "refresh_token:new_token"}
+	ttl := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		userID    int64
+		ttl       time.Duration
+		mockFn    func(mock redismock.ClientMock)
+		wantOK    bool
+		errSubstr string
+	}{
+		{
+			name:   "成功原子性刷新令牌",
+			userID: 123,
+			ttl:    ttl,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(rotateRefreshTokenScript.Hash(), keys, "123", ttl.Milliseconds()).SetVal(int64(1))
+			},
+			wantOK: true,
+		},
+		{
+			name:   "旧令牌已被撤销",
+			userID: 123,
+			ttl:    ttl,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(rotateRefreshTokenScript.Hash(), keys, "123", ttl.Milliseconds()).SetVal(int64(-1))
+			},
+			wantOK: false,
+		},
+		{
+			name:   "旧令牌属于其他用户",
+			userID: 456,
+			ttl:    ttl,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(rotateRefreshTokenScript.Hash(), keys, "456", ttl.Milliseconds()).SetVal(int64(0))
+			},
+			wantOK: false,
+		},
+		{
+			name:   "脚本未缓存时回退到EVAL",
+			userID: 123,
+			ttl:    ttl,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(rotateRefreshTokenScript.Hash(), keys, "123", ttl.Milliseconds()).SetErr(fmt.Errorf("NOSCRIPT No matching script"))
+				mock.ExpectEval(rotateRefreshTokenLua, keys, "123", ttl.Milliseconds()).SetVal(int64(1))
+			},
+			wantOK: true,
+		},
+		{
+			name:   "Redis连接错误",
+			userID: 123,
+			ttl:    ttl,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(rotateRefreshTokenScript.Hash(), keys, "123", ttl.Milliseconds()).SetErr(fmt.Errorf("connection refused"))
+			},
+			errSubstr: "connection refused",
+		},
+		{
+			name:      "有效期无效时不执行脚本",
+			userID:    123,
+			ttl:       0,
+			mockFn:    func(mock redismock.ClientMock) {},
+			errSubstr: "invalid refresh token ttl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rds, mock := redismock.NewClientMock()
+			repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
 			tt.mockFn(mock)
 
-			err := repo.RefreshTokenAtomically(context.Background(), tt.userID, tt.oldToken, tt.newToken, tt.expiresAt)
+			ok, err := repo.VerifyAndRotate(context.Background(), "old_token", "new_token", tt.userID, tt.ttl)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.errSubstr != "" {
+				assert.ErrorContains(t, err, tt.errSubstr)
 			} else {
 				assert.NoError(t, err)
 			}
-
-			// 验证所有期望都被调用
+			assert.Equal(t, tt.wantOK, ok)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
@@ -466,3 +580,350 @@ func TestAuthRepository_NewAuthRepository(t *testing.T) {
 	assert.Equal(t, data, authRepoImpl.data)
 	assert.NotNil(t, authRepoImpl.logger)
 }
+
+// TestAuthRepository_TrackSession 测试将刷新令牌记入会话索引
+func TestAuthRepository_TrackSession(t *testing.T) {
+	createdAt := time.UnixMilli(1700000000000)
+
+	tests := []struct {
+		name    string
+		mockFn  func(mock redismock.ClientMock)
+		wantErr bool
+	}{
+		{
+			name: "新索引设置过期时间",
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectZAdd("user_sessions:1", &redis.Z{Score: 1700000000000, Member: "token-1"}).SetVal(1)
+				mock.ExpectTTL("user_sessions:1").SetVal(-1)
+				// 过期时间由当前时间计算，只校验命令和键名
+				mock.CustomMatch(func(expected, actual []interface{}) error {
+					if len(actual) != 3 || actual[0] != "expire" || actual[1] != "user_sessions:1" {
+						return fmt.Errorf("unexpected command: %v", actual)
+					}
+					return nil
+				}).ExpectExpire("user_sessions:1", time.Hour).SetVal(true)
+			},
+		},
+		{
+			name: "索引剩余有效期更长时不缩短",
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectZAdd("user_sessions:1", &redis.Z{Score: 1700000000000, Member: "token-1"}).SetVal(1)
+				mock.ExpectTTL("user_sessions:1").SetVal(30 * 24 * time.Hour)
+			},
+		},
+		{
+			name: "写入索引失败",
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectZAdd("user_sessions:1", &redis.Z{Score: 1700000000000, Member: "token-1"}).SetErr(assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rds, mock := redismock.NewClientMock()
+			repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := repo.TrackSession(context.Background(), 1, "token-1", createdAt, time.Now().Add(time.Hour))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestAuthRepository_EvictOldestSessions 测试按创建时间踢出最早的会话并清理失效的索引项
+func TestAuthRepository_EvictOldestSessions(t *testing.T) {
+	tests := []struct {
+		name        string
+		keep        int
+		mockFn      func(mock redismock.ClientMock)
+		wantEvicted int
+		wantErr     bool
+	}{
+		{
+			name: "超过上限时踢出最早的会话",
+			keep: 1,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectZRange("user_sessions:1", 0, -1).SetVal([]string{"token-1", "token-2", "token-3"})
+				mock.ExpectExists("refresh_token:token-1").SetVal(1)
+				mock.ExpectExists("refresh_token:token-2").SetVal(0) // 已登出
+				mock.ExpectExists("refresh_token:token-3").SetVal(1)
+				mock.ExpectDel("refresh_token:token-1").SetVal(1)
+				mock.ExpectZRem("user_sessions:1", "token-2", "token-1").SetVal(2)
+			},
+			wantEvicted: 1,
+		},
+		{
+			name: "未超过上限时只清理失效索引",
+			keep: 2,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectZRange("user_sessions:1", 0, -1).SetVal([]string{"token-1", "token-2"})
+				mock.ExpectExists("refresh_token:token-1").SetVal(0)
+				mock.ExpectExists("refresh_token:token-2").SetVal(1)
+				mock.ExpectZRem("user_sessions:1", "token-1").SetVal(1)
+			},
+			wantEvicted: 0,
+		},
+		{
+			name: "读取索引失败",
+			keep: 1,
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectZRange("user_sessions:1", 0, -1).SetErr(assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rds, mock := redismock.NewClientMock()
+			repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			evicted, err := repo.EvictOldestSessions(context.Background(), 1, tt.keep)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantEvicted, evicted)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestAuthRepository_PairAccessToken 测试记录并读取刷新令牌的令牌族
+func TestAuthRepository_PairAccessToken(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: client}, log.DefaultLogger)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(7 * 24 * time.Hour)
+	key := "token_family:refresh-jti"
+
+	mock.ExpectSAdd(key, "access-jti-1").SetVal(1)
+	mock.ExpectExpireAt(key, expiresAt).SetVal(true)
+	mock.ExpectSAdd(key, "access-jti-2").SetVal(1)
+	mock.ExpectExpireAt(key, expiresAt).SetVal(true)
+	mock.ExpectSMembers(key).SetVal([]string{"access-jti-1", "access-jti-2"})
+	mock.ExpectSMembers("token_family:unknown-jti").SetVal([]string{})
+
+	require.NoError(t, repo.PairAccessToken(ctx, "refresh-jti", "access-jti-1", expiresAt))
+	require.NoError(t, repo.PairAccessToken(ctx, "refresh-jti", "access-jti-2", expiresAt))
+
+	ids, err := repo.GetPairedAccessTokenIDs(ctx, "refresh-jti")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"access-jti-1", "access-jti-2"}, ids)
+
+	ids, err = repo.GetPairedAccessTokenIDs(ctx, "unknown-jti")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAuthRepository_PairAccessToken_Error 测试写入令牌族失败时返回错误
+func TestAuthRepository_PairAccessToken_Error(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: client}, log.DefaultLogger)
+
+	mock.ExpectSAdd("token_family:refresh-jti", "access-jti").SetErr(fmt.Errorf("redis connection error_reason"))
+
+	err := repo.PairAccessToken(context.Background(), "refresh-jti", "access-jti", time.Now().Add(time.Hour))
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAuthRepository_PasswordFailures 测试密码校验失败次数的读取、累计和清除
+func TestAuthRepository_PasswordFailures(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: client}, log.DefaultLogger)
+	ctx := context.Background()
+	key := "password_failures:1"
+
+	mock.ExpectGet(key).RedisNil()
+	mock.ExpectIncr(key).SetVal(1)
+	mock.ExpectExpire(key, 15*time.Minute).SetVal(true)
+	mock.ExpectGet(key).SetVal("1")
+	mock.ExpectDel(key).SetVal(1)
+
+	count, err := repo.GetPasswordFailures(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "没有记录时应返回 0")
+
+	count, err = repo.IncrPasswordFailures(ctx, 1, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = repo.GetPasswordFailures(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.NoError(t, repo.ResetPasswordFailures(ctx, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAuthRepository_RotatedTokenPair 测试轮换结果以 JSON 缓存并可按旧令牌读取
+func TestAuthRepository_RotatedTokenPair(t *testing.T) {
+	pair := &biz.TokenPair{AccessToken: "access", AccessExpiresIn: 900, RefreshToken: "new_token", RefreshExpiresIn: 604800, RefreshTokenRotated: true}
+	encoded, err := json.Marshal(pair)
+	require.NoError(t, err)
+
+	rds, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
+
+	mock.ExpectSet("rotated_refresh_token:old_token", encoded, 10*time.Second).SetVal("OK")
+	mock.ExpectGet("rotated_refresh_token:old_token").SetVal(string(encoded))
+	mock.ExpectGet("rotated_refresh_token:expired_token").RedisNil()
+
+	require.NoError(t, repo.StoreRotatedTokenPair(context.Background(), "old_token", pair, 10*time.Second))
+
+	got, err := repo.GetRotatedTokenPair(context.Background(), "old_token")
+	require.NoError(t, err)
+	assert.Equal(t, pair, got)
+
+	_, err = repo.GetRotatedTokenPair(context.Background(), "expired_token")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAuthRepository_RefreshTokenLastUsed 测试最近使用时间以毫秒写入并随空闲超时过期
+func TestAuthRepository_RefreshTokenLastUsed(t *testing.T) {
+	rds, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
+	ctx := context.Background()
+	usedAt := time.UnixMilli(1700000000123)
+
+	mock.ExpectSet("refresh_token_last_used:token-1", usedAt.UnixMilli(), time.Hour).SetVal("OK")
+	mock.ExpectGet("refresh_token_last_used:token-1").SetVal("1700000000123")
+	mock.ExpectGet("refresh_token_last_used:idle-token").RedisNil()
+
+	require.NoError(t, repo.TouchRefreshToken(ctx, "token-1", usedAt, time.Hour))
+
+	got, err := repo.GetRefreshTokenLastUsed(ctx, "token-1")
+	require.NoError(t, err)
+	assert.True(t, usedAt.Equal(got))
+
+	_, err = repo.GetRefreshTokenLastUsed(ctx, "idle-token")
+	assert.ErrorIs(t, err, biz.ErrTokenNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAuthRepository_TokensValidAfter 测试全局令牌失效时间点以毫秒写入且不过期，从未设置时返回零值
+func TestAuthRepository_TokensValidAfter(t *testing.T) {
+	rds, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
+	ctx := context.Background()
+	validAfter := time.UnixMilli(1700000001000)
+
+	mock.ExpectGet("tokens_valid_after").RedisNil()
+	mock.ExpectSet("tokens_valid_after", validAfter.UnixMilli(), 0).SetVal("OK")
+	mock.ExpectGet("tokens_valid_after").SetVal("1700000001000")
+	mock.ExpectGet("tokens_valid_after").SetErr(errors.New("connection refused"))
+
+	got, err := repo.GetTokensValidAfter(ctx)
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	require.NoError(t, repo.SetTokensValidAfter(ctx, validAfter))
+
+	got, err = repo.GetTokensValidAfter(ctx)
+	require.NoError(t, err)
+	assert.True(t, validAfter.Equal(got))
+
+	_, err = repo.GetTokensValidAfter(ctx)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAuthRepository_RevokeSessionsByIP 测试跨用户删除来源 IP 匹配的刷新令牌，其他 IP 的会话不受影响
+func TestAuthRepository_RevokeSessionsByIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockFn      func(mock redismock.ClientMock)
+		wantRevoked int
+		wantErr     bool
+	}{
+		{
+			name: "删除多个用户中匹配的会话",
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectScan(0, "session_ips:*", -1).SetVal([]string{"session_ips:1", "session_ips:2", "session_ips:3"}, 0)
+				mock.ExpectHGetAll("session_ips:1").SetVal(map[string]string{"token-b": "1.2.3.4", "token-a": "1.2.3.4", "token-c": "5.6.7.8"})
+				mock.ExpectDel("refresh_token:token-a", "refresh_token:token-b").SetVal(2)
+				mock.ExpectHDel("session_ips:1", "token-a", "token-b").SetVal(2)
+				// 其他 IP 的用户不删除任何令牌
+				mock.ExpectHGetAll("session_ips:2").SetVal(map[string]string{"token-d": "5.6.7.8"})
+				// 令牌已登出时只清理记录
+				mock.ExpectHGetAll("session_ips:3").SetVal(map[string]string{"token-e": "1.2.3.4"})
+				mock.ExpectDel("refresh_token:token-e").SetVal(0)
+				mock.ExpectHDel("session_ips:3", "token-e").SetVal(1)
+			},
+			wantRevoked: 2,
+		},
+		{
+			name: "没有匹配的会话",
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectScan(0, "session_ips:*", -1).SetVal([]string{"session_ips:2"}, 0)
+				mock.ExpectHGetAll("session_ips:2").SetVal(map[string]string{"token-d": "5.6.7.8"})
+			},
+		},
+		{
+			name: "SCAN操作出错",
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectScan(0, "session_ips:*", -1).SetErr(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "DEL操作出错",
+			mockFn: func(mock redismock.ClientMock) {
+				mock.ExpectScan(0, "session_ips:*", -1).SetVal([]string{"session_ips:1"}, 0)
+				mock.ExpectHGetAll("session_ips:1").SetVal(map[string]string{"token-a": "1.2.3.4"})
+				mock.ExpectDel("refresh_token:token-a").SetErr(assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rds, mock := redismock.NewClientMock()
+			repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			revoked, err := repo.RevokeSessionsByIP(context.Background(), "1.2.3.4")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRevoked, revoked)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestAuthRepository_RecordSessionIP 测试会话 IP 写入用户的哈希表，并按刷新令牌过期时间延长有效期
+func TestAuthRepository_RecordSessionIP(t *testing.T) {
+	rds, mock := redismock.NewClientMock()
+	repo := NewAuthRepository(&Data{rds: rds}, log.DefaultLogger)
+
+	mock.ExpectHSet("session_ips:1", "token-1", "1.2.3.4").SetVal(1)
+	mock.ExpectTTL("session_ips:1").SetVal(30 * 24 * time.Hour)
+
+	err := repo.RecordSessionIP(context.Background(), 1, "token-1", "1.2.3.4", time.Now().Add(time.Hour))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -19,6 +19,33 @@ type RedisClient interface {
 	TTL(ctx context.Context, key string) *redis.DurationCmd
 }
 
+// defaultMaxCodeTTL 未配置 data.max_code_ttl 时验证码允许的最大剩余有效期
+const defaultMaxCodeTTL = 30 * time.Minute
+
+// maxCodeTTLOrDefault 返回配置的验证码最大有效期，未配置时使用默认值
+func maxCodeTTLOrDefault(ttl time.Duration) time.Duration {
+	if ttl > 0 {
+		return ttl
+	}
+	return defaultMaxCodeTTL
+}
+
+// consumeCodeLua 原子地校验并删除验证码：与候选哈希一致时删除并返回1，不一致返回0，不存在返回-1
+const consumeCodeLua = `
+local stored = redis.call("GET", KEYS[1])
+if not stored then
+	return -1
+end
+if stored ~= ARGV[1] then
+	return 0
+end
+redis.call("DEL", KEYS[1])
+return 1
+`
+
+// consumeCodeScript 优先使用 EVALSHA 执行 consumeCodeLua，脚本未缓存时回退到 EVAL
+var consumeCodeScript = redis.NewScript(consumeCodeLua)
+
 // codeRepository 验证码数据访问实现
 type codeRepository struct {
 	data   *Data
@@ -33,20 +60,30 @@ func NewCodeRepository(data *Data, logger log.Logger) biz.CodeRepository {
 	}
 }
 
-// StoreVerificationCode 存储验证码到Redis
+// StoreVerificationCode 存储注册验证码到Redis
 func (r *codeRepository) StoreVerificationCode(ctx context.Context, email, code string, expiresAt time.Time) error {
-	ctx, span := tracing.StartSpan(ctx, "CodeRepository.StoreVerificationCode")
+	return r.StoreCode(ctx, email, biz.CodePurposeRegister, code, expiresAt)
+}
+
+// StoreCode 按用途存储验证码到Redis，不同用途的验证码使用不同的 key，互不覆盖
+func (r *codeRepository) StoreCode(ctx context.Context, email, purpose, code string, expiresAt time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.StoreCode")
 	defer span.End()
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
 		"email":       email,
+		"purpose":     purpose,
 		"code_length": len(code),
 	})
 
-	r.logger.WithContext(ctx).Infof("Storing verification code for email: %s", email)
+	r.logger.WithContext(ctx).Infof("Storing verification code for email: %s, purpose: %s", email, purpose)
 
-	key := fmt.Sprintf("verification_code:%s", email)
+	key := r.data.keys.verificationCode(purpose, email)
 	expiration := time.Until(expiresAt)
+	if maxTTL := maxCodeTTLOrDefault(r.data.maxCodeTTL); expiration > maxTTL {
+		r.logger.WithContext(ctx).Warnf("Verification code TTL %s exceeds max %s for email: %s, clamped", expiration, maxTTL, email)
+		expiration = maxTTL
+	}
 
 	err := r.data.RedisClient().Set(ctx, key, code, expiration).Err()
 	if err != nil {
@@ -69,8 +106,17 @@ func (r *codeRepository) GetVerificationCode(ctx context.Context, email string)
 
 	r.logger.WithContext(ctx).Infof("Getting verification code for email: %s", email)
 
-	key := fmt.Sprintf("verification_code:%s", email)
-	code, err := r.data.RedisClient().Get(ctx, key).Result()
+	// GET 和 TTL 放在同一个事务中执行，避免两次调用之间 key 过期导致有效期为负
+	key := r.data.keys.verificationCode(biz.CodePurposeRegister, email)
+	pipe := r.data.RedisClient().TxPipeline()
+	getCmd := pipe.Get(ctx, key)
+	ttlCmd := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get verification code for email: %s, error_reason: %v", email, err)
+		return nil, err
+	}
+
+	code, err := getCmd.Result()
 	if err != nil {
 		if err == redis.Nil {
 			r.logger.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
@@ -80,11 +126,16 @@ func (r *codeRepository) GetVerificationCode(ctx context.Context, email string)
 		return nil, err
 	}
 
-	ttl, err := r.data.RedisClient().TTL(ctx, key).Result()
+	ttl, err := ttlCmd.Result()
 	if err != nil {
 		r.logger.WithContext(ctx).Errorf("Failed to get TTL for verification code of email: %s, error_reason: %v", email, err)
 		return nil, err
 	}
+	// key 不存在时 TTL 返回 -2，与 GET 未命中一样视为验证码不存在
+	if ttl == -2 {
+		r.logger.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+		return nil, fmt.Errorf("验证码不存在或已过期")
+	}
 
 	verificationCode := &biz.VerificationCode{
 		Email:     email,
@@ -107,7 +158,7 @@ func (r *codeRepository) DeleteVerificationCode(ctx context.Context, email strin
 
 	r.logger.WithContext(ctx).Infof("Deleting verification code for email: %s", email)
 
-	key := fmt.Sprintf("verification_code:%s", email)
+	key := r.data.keys.verificationCode(biz.CodePurposeRegister, email)
 	_, err := r.data.RedisClient().Del(ctx, key).Result()
 	if err != nil {
 		r.logger.WithContext(ctx).Errorf("Failed to delete verification code for email: %s, error_reason: %v", email, err)
@@ -118,6 +169,88 @@ func (r *codeRepository) DeleteVerificationCode(ctx context.Context, email strin
 	return nil
 }
 
+// ConsumeIfValid 使用 Lua 脚本在 Redis 端原子地比对并删除验证码，并发请求中只有一个能消费成功
+func (r *codeRepository) ConsumeIfValid(ctx context.Context, email, purpose, candidate string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.ConsumeIfValid")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email":   email,
+		"purpose": purpose,
+	})
+
+	r.logger.WithContext(ctx).Infof("Consuming verification code for email: %s, purpose: %s", email, purpose)
+
+	key := r.data.keys.verificationCode(purpose, email)
+	result, err := consumeCodeScript.Run(ctx, r.data.RedisClient(), []string{key}, candidate).Int()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to consume verification code for email: %s, error_reason: %v", email, err)
+		return false, err
+	}
+
+	switch result {
+	case 1:
+		r.logger.WithContext(ctx).Infof("Successfully consumed verification code for email: %s", email)
+		return true, nil
+	case 0:
+		r.logger.WithContext(ctx).Warnf("Verification code mismatch for email: %s", email)
+		return false, nil
+	default:
+		r.logger.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+		return false, biz.ErrVerificationCodeExpired
+	}
+}
+
+// ExtendVerificationCodeTTL 延长验证码有效期，验证码内容不变
+// 延长后的剩余有效期不超过配置的验证码最大有效期
+func (r *codeRepository) ExtendVerificationCodeTTL(ctx context.Context, email, purpose string, extra time.Duration) error {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.ExtendVerificationCodeTTL")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email":         email,
+		"purpose":       purpose,
+		"extra_seconds": extra.Seconds(),
+	})
+
+	r.logger.WithContext(ctx).Infof("Extending verification code TTL for email: %s, purpose: %s", email, purpose)
+
+	key := r.data.keys.verificationCode(purpose, email)
+	ttl, err := r.data.RedisClient().TTL(ctx, key).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get TTL for verification code of email: %s, error_reason: %v", email, err)
+		return err
+	}
+
+	// key 不存在时 TTL 返回 -2
+	if ttl == -2 {
+		r.logger.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+		return biz.ErrVerificationCodeExpired
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	newTTL := ttl + extra
+	if maxTTL := maxCodeTTLOrDefault(r.data.maxCodeTTL); newTTL > maxTTL {
+		newTTL = maxTTL
+	}
+
+	ok, err := r.data.RedisClient().Expire(ctx, key, newTTL).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to extend verification code TTL for email: %s, error_reason: %v", email, err)
+		return err
+	}
+	// 读取 TTL 与设置过期之间验证码可能已过期
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Verification code expired before extending for email: %s", email)
+		return biz.ErrVerificationCodeExpired
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully extended verification code TTL for email: %s, new ttl: %s", email, newTTL)
+	return nil
+}
+
 // CheckAndSetSendRateLimit 检查并设置发送频率限制
 // 如果在指定时间内已经发送过验证码，返回 false；否则设置限制并返回 true
 func (r *codeRepository) CheckAndSetSendRateLimit(ctx context.Context, email string, duration time.Duration) (bool, error) {
@@ -131,7 +264,7 @@ func (r *codeRepository) CheckAndSetSendRateLimit(ctx context.Context, email str
 
 	r.logger.WithContext(ctx).Infof("Checking send rate limit for email: %s", email)
 
-	key := fmt.Sprintf("rate_limit:send_code:%s", email)
+	key := r.data.keys.sendCodeRateLimit(email)
 	// SetNX 返回一个 bool 值表示是否成功设置，我们需要检查这个值
 	success, err := r.data.RedisClient().SetNX(ctx, key, time.Now().Unix(), duration).Result()
 	if err != nil {
@@ -147,3 +280,125 @@ func (r *codeRepository) CheckAndSetSendRateLimit(ctx context.Context, email str
 	r.logger.WithContext(ctx).Infof("Rate limit set successfully for email: %s", email)
 	return true, nil
 }
+
+// CheckAndIncrDailySendLimit 累加当天的验证码发送次数，计数 key 在 resetAt 时过期
+func (r *codeRepository) CheckAndIncrDailySendLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.CheckAndIncrDailySendLimit")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email": email,
+		"limit": limit,
+	})
+
+	ok, err := r.incrWithinLimit(ctx, r.data.keys.sendCodeDailyCount(email), limit, resetAt)
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to check daily send limit for email: %s, error_reason: %v", email, err)
+		return false, err
+	}
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Daily send limit exceeded for email: %s, limit: %d", email, limit)
+	}
+	return ok, nil
+}
+
+// CheckAndIncrCodeStatusLimit 累加验证码状态查询次数，计数 key 在 resetAt 时过期
+func (r *codeRepository) CheckAndIncrCodeStatusLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.CheckAndIncrCodeStatusLimit")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email": email,
+		"limit": limit,
+	})
+
+	ok, err := r.incrWithinLimit(ctx, r.data.keys.codeStatusQueryCount(email), limit, resetAt)
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to check code status query limit for email: %s, error_reason: %v", email, err)
+		return false, err
+	}
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Code status query limit exceeded for email: %s, limit: %d", email, limit)
+	}
+	return ok, nil
+}
+
+// CheckAndIncrRegistrationAttempts 累加客户端 IP 的注册请求次数，计数 key 在 resetAt 时过期
+func (r *codeRepository) CheckAndIncrRegistrationAttempts(ctx context.Context, ip string, limit int, resetAt time.Time) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.CheckAndIncrRegistrationAttempts")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"ip":    ip,
+		"limit": limit,
+	})
+
+	ok, err := r.incrWithinLimit(ctx, r.data.keys.registrationAttemptCount(ip), limit, resetAt)
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to count registration attempts for ip: %s, error_reason: %v", ip, err)
+		return false, err
+	}
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Registration attempts exceeded for ip: %s, limit: %d", ip, limit)
+	}
+	return ok, nil
+}
+
+// CheckAndIncrTestEmailSends 累加管理员发送诊断测试邮件的次数，计数 key 在 resetAt 时过期
+func (r *codeRepository) CheckAndIncrTestEmailSends(ctx context.Context, userID int64, limit int, resetAt time.Time) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.CheckAndIncrTestEmailSends")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+		"limit":   limit,
+	})
+
+	ok, err := r.incrWithinLimit(ctx, r.data.keys.testEmailSendCount(userID), limit, resetAt)
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to count test email sends for user id: %d, error_reason: %v", userID, err)
+		return false, err
+	}
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Test email sends exceeded for user id: %d, limit: %d", userID, limit)
+	}
+	return ok, nil
+}
+
+// incrWithinLimit 累加计数 key 并设置其在 resetAt 过期，返回累加后是否仍不超过 limit
+// 每次累加都会重新设置过期时间，避免 INCR 成功后设置过期失败导致计数永不清零
+func (r *codeRepository) incrWithinLimit(ctx context.Context, key string, limit int, resetAt time.Time) (bool, error) {
+	count, err := r.data.RedisClient().Incr(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if err := r.data.RedisClient().ExpireAt(ctx, key, resetAt).Err(); err != nil {
+		return false, err
+	}
+	return count <= int64(limit), nil
+}
+
+// GetVerificationCodeTTL 读取注册验证码的剩余有效期，不读取验证码内容
+func (r *codeRepository) GetVerificationCodeTTL(ctx context.Context, email string) (time.Duration, error) {
+	ctx, span := tracing.StartSpan(ctx, "CodeRepository.GetVerificationCodeTTL")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email": email,
+	})
+
+	ttl, err := r.data.RedisClient().TTL(ctx, r.data.keys.verificationCode(biz.CodePurposeRegister, email)).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get TTL for verification code of email: %s, error_reason: %v", email, err)
+		return 0, err
+	}
+
+	// key 不存在时 TTL 返回 -2
+	if ttl == -2 {
+		return 0, biz.ErrVerificationCodeExpired
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	return ttl, nil
+}
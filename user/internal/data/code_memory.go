@@ -0,0 +1,248 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"user/internal/biz"
+	"user/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// memoryCodeRepository 基于内存的验证码数据访问实现，key 格式与 Redis 实现一致
+type memoryCodeRepository struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+	logger  *log.Helper
+	// maxTTL 验证码剩余有效期的上限
+	maxTTL time.Duration
+}
+
+// NewMemoryCodeRepository 创建内存验证码数据访问实例，maxTTL 为 0 时使用默认上限，返回的函数用于停止后台清理
+func NewMemoryCodeRepository(maxTTL time.Duration, logger log.Logger) (biz.CodeRepository, func()) {
+	r := newMemoryCodeRepository(logger)
+	r.maxTTL = maxCodeTTLOrDefault(maxTTL)
+	stop := runJanitor(memoryJanitorInterval, r.purgeExpired)
+	return r, stop
+}
+
+func newMemoryCodeRepository(logger log.Logger) *memoryCodeRepository {
+	return &memoryCodeRepository{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+		logger:  log.NewHelper(logger),
+		maxTTL:  defaultMaxCodeTTL,
+	}
+}
+
+// NewCodeRepositoryFromConfig 根据 cache_driver 配置选择验证码存储实现
+func NewCodeRepositoryFromConfig(c *conf.Data, data *Data, logger log.Logger) (biz.CodeRepository, func()) {
+	if c.CacheDriver == CacheDriverMemory {
+		return NewMemoryCodeRepository(c.GetMaxCodeTtl().AsDuration(), logger)
+	}
+	return NewCodeRepository(data, logger), func() {}
+}
+
+// get 获取未过期的条目，已过期的条目会被删除
+func (r *memoryCodeRepository) get(key string) (memoryEntry, bool) {
+	entry, ok := r.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if entry.expired(r.now()) {
+		delete(r.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// purgeExpired 删除所有已过期的条目
+func (r *memoryCodeRepository) purgeExpired() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for key, entry := range r.entries {
+		if entry.expired(now) {
+			delete(r.entries, key)
+		}
+	}
+}
+
+// StoreVerificationCode 存储注册验证码
+func (r *memoryCodeRepository) StoreVerificationCode(ctx context.Context, email, code string, expiresAt time.Time) error {
+	return r.StoreCode(ctx, email, biz.CodePurposeRegister, code, expiresAt)
+}
+
+// StoreCode 按用途存储验证码
+func (r *memoryCodeRepository) StoreCode(ctx context.Context, email, purpose, code string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if maxExpiresAt := r.now().Add(r.maxTTL); expiresAt.After(maxExpiresAt) {
+		r.logger.WithContext(ctx).Warnf("Verification code TTL exceeds max %s for email: %s, clamped", r.maxTTL, email)
+		expiresAt = maxExpiresAt
+	}
+	r.entries[verificationCodeKey(purpose, email)] = memoryEntry{value: code, expiresAt: expiresAt}
+	return nil
+}
+
+// GetVerificationCode 获取验证码
+func (r *memoryCodeRepository) GetVerificationCode(ctx context.Context, email string) (*biz.VerificationCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.get(verificationCodeKey(biz.CodePurposeRegister, email))
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+		return nil, fmt.Errorf("验证码不存在或已过期")
+	}
+
+	return &biz.VerificationCode{
+		Email:     email,
+		Code:      entry.value,
+		ExpiresAt: entry.expiresAt,
+	}, nil
+}
+
+// DeleteVerificationCode 删除验证码
+func (r *memoryCodeRepository) DeleteVerificationCode(ctx context.Context, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, verificationCodeKey(biz.CodePurposeRegister, email))
+	return nil
+}
+
+// ConsumeIfValid 在锁内比对并删除验证码，不一致时保留验证码
+func (r *memoryCodeRepository) ConsumeIfValid(ctx context.Context, email, purpose, candidate string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := verificationCodeKey(purpose, email)
+	entry, ok := r.get(key)
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+		return false, biz.ErrVerificationCodeExpired
+	}
+	if entry.value != candidate {
+		return false, nil
+	}
+
+	delete(r.entries, key)
+	return true, nil
+}
+
+// ExtendVerificationCodeTTL 延长验证码有效期，延长后的剩余有效期不超过 maxTTL
+func (r *memoryCodeRepository) ExtendVerificationCodeTTL(ctx context.Context, email, purpose string, extra time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := verificationCodeKey(purpose, email)
+	entry, ok := r.get(key)
+	if !ok {
+		r.logger.WithContext(ctx).Warnf("Verification code not found or expired for email: %s", email)
+		return biz.ErrVerificationCodeExpired
+	}
+
+	now := r.now()
+	newTTL := entry.expiresAt.Sub(now) + extra
+	if newTTL > r.maxTTL {
+		newTTL = r.maxTTL
+	}
+	entry.expiresAt = now.Add(newTTL)
+	r.entries[key] = entry
+	return nil
+}
+
+// CheckAndSetSendRateLimit 检查并设置发送频率限制
+// 如果在指定时间内已经发送过验证码，返回 false；否则设置限制并返回 true
+func (r *memoryCodeRepository) CheckAndSetSendRateLimit(ctx context.Context, email string, duration time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := sendCodeRateLimitKey(email)
+	if _, ok := r.get(key); ok {
+		r.logger.WithContext(ctx).Warnf("Rate limit exceeded for email: %s", email)
+		return false, nil
+	}
+
+	now := r.now()
+	r.entries[key] = memoryEntry{value: strconv.FormatInt(now.Unix(), 10), expiresAt: now.Add(duration)}
+	return true, nil
+}
+
+// CheckAndIncrDailySendLimit 累加当天的验证码发送次数，计数在 resetAt 时清零
+func (r *memoryCodeRepository) CheckAndIncrDailySendLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.incrWithinLimit(sendCodeDailyCountKey(email), limit, resetAt) {
+		r.logger.WithContext(ctx).Warnf("Daily send limit exceeded for email: %s, limit: %d", email, limit)
+		return false, nil
+	}
+	return true, nil
+}
+
+// CheckAndIncrCodeStatusLimit 累加验证码状态查询次数，计数在 resetAt 时清零
+func (r *memoryCodeRepository) CheckAndIncrCodeStatusLimit(ctx context.Context, email string, limit int, resetAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.incrWithinLimit(codeStatusQueryCountKey(email), limit, resetAt) {
+		r.logger.WithContext(ctx).Warnf("Code status query limit exceeded for email: %s, limit: %d", email, limit)
+		return false, nil
+	}
+	return true, nil
+}
+
+// CheckAndIncrRegistrationAttempts 累加客户端 IP 的注册请求次数，计数在 resetAt 时清零
+func (r *memoryCodeRepository) CheckAndIncrRegistrationAttempts(ctx context.Context, ip string, limit int, resetAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.incrWithinLimit(registrationAttemptCountKey(ip), limit, resetAt) {
+		r.logger.WithContext(ctx).Warnf("Registration attempts exceeded for ip: %s, limit: %d", ip, limit)
+		return false, nil
+	}
+	return true, nil
+}
+
+// CheckAndIncrTestEmailSends 累加管理员发送诊断测试邮件的次数，计数在 resetAt 时清零
+func (r *memoryCodeRepository) CheckAndIncrTestEmailSends(ctx context.Context, userID int64, limit int, resetAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.incrWithinLimit(testEmailSendCountKey(userID), limit, resetAt) {
+		r.logger.WithContext(ctx).Warnf("Test email sends exceeded for user id: %d, limit: %d", userID, limit)
+		return false, nil
+	}
+	return true, nil
+}
+
+// incrWithinLimit 累加计数并设置其在 resetAt 过期，返回累加后是否仍不超过 limit，调用方需持有锁
+func (r *memoryCodeRepository) incrWithinLimit(key string, limit int, resetAt time.Time) bool {
+	var count int64
+	if entry, ok := r.get(key); ok {
+		count, _ = strconv.ParseInt(entry.value, 10, 64)
+	}
+	count++
+	r.entries[key] = memoryEntry{value: strconv.FormatInt(count, 10), expiresAt: resetAt}
+	return count <= int64(limit)
+}
+
+// GetVerificationCodeTTL 返回注册验证码的剩余有效期
+func (r *memoryCodeRepository) GetVerificationCodeTTL(ctx context.Context, email string) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.get(verificationCodeKey(biz.CodePurposeRegister, email))
+	if !ok {
+		return 0, biz.ErrVerificationCodeExpired
+	}
+	return entry.expiresAt.Sub(r.now()), nil
+}
@@ -0,0 +1,278 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMemoryCodeRepository 创建使用可控时钟的内存验证码仓库
+func newTestMemoryCodeRepository(now *time.Time) *memoryCodeRepository {
+	r := newMemoryCodeRepository(log.DefaultLogger)
+	r.now = func() time.Time { return *now }
+	return r
+}
+
+// TestMemoryCodeRepository_StoreGetDelete 测试验证码的存储、获取和删除
+func TestMemoryCodeRepository_StoreGetDelete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+
+	expiresAt := now.Add(10 * time.Minute)
+	require.NoError(t, repo.StoreVerificationCode(ctx, "test@example.com", "123456", expiresAt))
+
+	code, err := repo.GetVerificationCode(ctx, "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "test@example.com", code.Email)
+	assert.Equal(t, "123456", code.Code)
+	assert.Equal(t, expiresAt, code.ExpiresAt)
+
+	require.NoError(t, repo.DeleteVerificationCode(ctx, "test@example.com"))
+	_, err = repo.GetVerificationCode(ctx, "test@example.com")
+	assert.Error(t, err)
+}
+
+// TestMemoryCodeRepository_Expiry 测试验证码过期及后台清理
+func TestMemoryCodeRepository_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+
+	require.NoError(t, repo.StoreVerificationCode(ctx, "a@example.com", "111111", now.Add(time.Minute)))
+	require.NoError(t, repo.StoreVerificationCode(ctx, "b@example.com", "222222", now.Add(time.Hour)))
+
+	now = now.Add(2 * time.Minute)
+
+	_, err := repo.GetVerificationCode(ctx, "a@example.com")
+	assert.Error(t, err, "过期验证码不应返回")
+
+	repo.purgeExpired()
+	assert.Len(t, repo.entries, 1)
+
+	code, err := repo.GetVerificationCode(ctx, "b@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "222222", code.Code)
+}
+
+// TestMemoryCodeRepository_ExtendVerificationCodeTTL 测试延长验证码有效期
+func TestMemoryCodeRepository_ExtendVerificationCodeTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name          string
+		storedTTL     time.Duration
+		extra         time.Duration
+		wantExpiresAt time.Time
+		wantErr       error
+	}{
+		{
+			name:          "正常延期",
+			storedTTL:     5 * time.Minute,
+			extra:         5 * time.Minute,
+			wantExpiresAt: now.Add(10 * time.Minute),
+		},
+		{
+			name:          "延期后不超过最大有效期",
+			storedTTL:     25 * time.Minute,
+			extra:         20 * time.Minute,
+			wantExpiresAt: now.Add(defaultMaxCodeTTL),
+		},
+		{
+			name:    "验证码不存在",
+			extra:   5 * time.Minute,
+			wantErr: biz.ErrVerificationCodeExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := now
+			repo := newTestMemoryCodeRepository(&current)
+			if tt.storedTTL > 0 {
+				require.NoError(t, repo.StoreVerificationCode(ctx, "test@example.com", "123456", now.Add(tt.storedTTL)))
+			}
+
+			err := repo.ExtendVerificationCodeTTL(ctx, "test@example.com", biz.CodePurposeRegister, tt.extra)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			code, err := repo.GetVerificationCode(ctx, "test@example.com")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExpiresAt, code.ExpiresAt)
+		})
+	}
+}
+
+// TestMemoryCodeRepository_MaxCodeTTL 测试存储和延期验证码时有效期不超过配置的上限
+func TestMemoryCodeRepository_MaxCodeTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+	repo.maxTTL = 15 * time.Minute
+
+	require.NoError(t, repo.StoreVerificationCode(ctx, "test@example.com", "123456", now.Add(24*time.Hour)))
+	code, err := repo.GetVerificationCode(ctx, "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), code.ExpiresAt, "存储时超过上限应按上限设置")
+
+	now = now.Add(5 * time.Minute)
+	require.NoError(t, repo.ExtendVerificationCodeTTL(ctx, "test@example.com", biz.CodePurposeRegister, time.Hour))
+	code, err = repo.GetVerificationCode(ctx, "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), code.ExpiresAt, "延期时超过上限应按上限设置")
+}
+
+// TestMemoryCodeRepository_CheckAndSetSendRateLimit 测试发送频率限制
+func TestMemoryCodeRepository_CheckAndSetSendRateLimit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+
+	ok, err := repo.CheckAndSetSendRateLimit(ctx, "test@example.com", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "首次发送应允许")
+
+	ok, err = repo.CheckAndSetSendRateLimit(ctx, "test@example.com", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "限制期内再次发送应拒绝")
+
+	ok, err = repo.CheckAndSetSendRateLimit(ctx, "other@example.com", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "不同邮箱互不影响")
+
+	now = now.Add(time.Minute)
+	ok, err = repo.CheckAndSetSendRateLimit(ctx, "test@example.com", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "限制过期后应允许再次发送")
+}
+
+// TestMemoryCodeRepository_CheckAndIncrDailySendLimit 测试每日发送上限
+func TestMemoryCodeRepository_CheckAndIncrDailySendLimit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+	resetAt := now.Add(time.Hour)
+
+	for i := 1; i <= 3; i++ {
+		ok, err := repo.CheckAndIncrDailySendLimit(ctx, "test@example.com", 3, resetAt)
+		require.NoError(t, err)
+		assert.True(t, ok, "第 %d 次发送应允许", i)
+	}
+
+	ok, err := repo.CheckAndIncrDailySendLimit(ctx, "test@example.com", 3, resetAt)
+	require.NoError(t, err)
+	assert.False(t, ok, "超过每日上限应拒绝")
+
+	ok, err = repo.CheckAndIncrDailySendLimit(ctx, "other@example.com", 3, resetAt)
+	require.NoError(t, err)
+	assert.True(t, ok, "不同邮箱互不影响")
+
+	now = resetAt
+	ok, err = repo.CheckAndIncrDailySendLimit(ctx, "test@example.com", 3, resetAt.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok, "计数重置后应允许再次发送")
+}
+
+// TestMemoryCodeRepository_GetVerificationCodeTTL 测试读取验证码剩余有效期
+func TestMemoryCodeRepository_GetVerificationCodeTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+
+	_, err := repo.GetVerificationCodeTTL(ctx, "test@example.com")
+	assert.ErrorIs(t, err, biz.ErrVerificationCodeExpired)
+
+	require.NoError(t, repo.StoreVerificationCode(ctx, "test@example.com", "123456", now.Add(5*time.Minute)))
+	now = now.Add(time.Minute)
+	ttl, err := repo.GetVerificationCodeTTL(ctx, "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 4*time.Minute, ttl)
+
+	now = now.Add(5 * time.Minute)
+	_, err = repo.GetVerificationCodeTTL(ctx, "test@example.com")
+	assert.ErrorIs(t, err, biz.ErrVerificationCodeExpired)
+}
+
+// TestMemoryCodeRepository_ConsumeIfValid 测试校验一致时消费验证码，不一致时保留
+func TestMemoryCodeRepository_ConsumeIfValid(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+
+	require.NoError(t, repo.StoreVerificationCode(ctx, "test@example.com", "hashed", now.Add(5*time.Minute)))
+
+	ok, err := repo.ConsumeIfValid(ctx, "test@example.com", biz.CodePurposeRegister, "wrong")
+	require.NoError(t, err)
+	assert.False(t, ok, "不一致时不应消费")
+
+	ok, err = repo.ConsumeIfValid(ctx, "test@example.com", biz.CodePurposeRegister, "hashed")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = repo.ConsumeIfValid(ctx, "test@example.com", biz.CodePurposeRegister, "hashed")
+	assert.ErrorIs(t, err, biz.ErrVerificationCodeExpired, "验证码只能消费一次")
+
+	require.NoError(t, repo.StoreVerificationCode(ctx, "test@example.com", "hashed", now.Add(time.Minute)))
+	now = now.Add(2 * time.Minute)
+	_, err = repo.ConsumeIfValid(ctx, "test@example.com", biz.CodePurposeRegister, "hashed")
+	assert.ErrorIs(t, err, biz.ErrVerificationCodeExpired, "过期验证码不能消费")
+}
+
+// TestMemoryCodeRepository_StoreCode 测试不同用途的验证码分开存储，互不覆盖
+func TestMemoryCodeRepository_StoreCode(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+
+	require.NoError(t, repo.StoreVerificationCode(ctx, "test@example.com", "register-hash", now.Add(5*time.Minute)))
+	require.NoError(t, repo.StoreCode(ctx, "test@example.com", biz.CodePurposeResetPassword, "reset-hash", now.Add(5*time.Minute)))
+
+	ok, err := repo.ConsumeIfValid(ctx, "test@example.com", biz.CodePurposeResetPassword, "register-hash")
+	require.NoError(t, err)
+	assert.False(t, ok, "注册验证码不能用于找回密码")
+
+	ok, err = repo.ConsumeIfValid(ctx, "test@example.com", biz.CodePurposeResetPassword, "reset-hash")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.ConsumeIfValid(ctx, "test@example.com", biz.CodePurposeRegister, "register-hash")
+	require.NoError(t, err)
+	assert.True(t, ok, "消费找回密码验证码不影响注册验证码")
+}
+
+// TestMemoryCodeRepository_CheckAndIncrRegistrationAttempts 测试按客户端 IP 统计注册请求次数
+func TestMemoryCodeRepository_CheckAndIncrRegistrationAttempts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	repo := newTestMemoryCodeRepository(&now)
+	resetAt := now.Add(time.Hour)
+
+	for i := 1; i <= 2; i++ {
+		ok, err := repo.CheckAndIncrRegistrationAttempts(ctx, "1.2.3.4", 2, resetAt)
+		require.NoError(t, err)
+		assert.True(t, ok, "第 %d 次请求应在阈值内", i)
+	}
+
+	ok, err := repo.CheckAndIncrRegistrationAttempts(ctx, "1.2.3.4", 2, resetAt)
+	require.NoError(t, err)
+	assert.False(t, ok, "超过阈值应返回 false")
+
+	ok, err = repo.CheckAndIncrRegistrationAttempts(ctx, "5.6.7.8", 2, resetAt)
+	require.NoError(t, err)
+	assert.True(t, ok, "不同 IP 互不影响")
+
+	now = resetAt
+	ok, err = repo.CheckAndIncrRegistrationAttempts(ctx, "1.2.3.4", 2, resetAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok, "计数重置后应在阈值内")
+}
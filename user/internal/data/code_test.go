@@ -126,8 +126,10 @@ func TestDataRepository_GetVerificationCode(t *testing.T) {
 			email: "test@example.com",
 			setupMock: func(mock redismock.ClientMock) {
 				key := "verification_code:test@example.com"
+				mock.ExpectTxPipeline()
 				mock.ExpectGet(key).SetVal("123456")
 				mock.ExpectTTL(key).SetVal(10 * time.Minute)
+				mock.ExpectTxPipelineExec()
 			},
 			wantCode: &biz.VerificationCode{
 				Email: "test@example.com",
@@ -140,6 +142,8 @@ func TestDataRepository_GetVerificationCode(t *testing.T) {
 			email: "nonexistent@example.com",
 			setupMock: func(mock redismock.ClientMock) {
 				key := "verification_code:nonexistent@example.com"
+				mock.ExpectTxPipeline()
+				// 管道中命令出错时 redismock 不再匹配后续命令
 				mock.ExpectGet(key).SetErr(redis.Nil)
 			},
 			wantCode:    nil,
@@ -151,6 +155,7 @@ func TestDataRepository_GetVerificationCode(t *testing.T) {
 			email: "test@example.com",
 			setupMock: func(mock redismock.ClientMock) {
 				key := "verification_code:test@example.com"
+				mock.ExpectTxPipeline()
 				mock.ExpectGet(key).SetErr(fmt.Errorf("connection error_reason"))
 			},
 			wantCode:    nil,
@@ -162,6 +167,7 @@ func TestDataRepository_GetVerificationCode(t *testing.T) {
 			email: "test@example.com",
 			setupMock: func(mock redismock.ClientMock) {
 				key := "verification_code:test@example.com"
+				mock.ExpectTxPipeline()
 				mock.ExpectGet(key).SetVal("123456")
 				mock.ExpectTTL(key).SetErr(fmt.Errorf("ttl error_reason"))
 			},
@@ -169,13 +175,29 @@ func TestDataRepository_GetVerificationCode(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "ttl error_reason",
 		},
+		{
+			name:  "读取值后 key 过期视为不存在",
+			email: "test@example.com",
+			setupMock: func(mock redismock.ClientMock) {
+				key := "verification_code:test@example.com"
+				mock.ExpectTxPipeline()
+				mock.ExpectGet(key).SetVal("123456")
+				mock.ExpectTTL(key).SetVal(time.Duration(-2))
+				mock.ExpectTxPipelineExec()
+			},
+			wantCode:    nil,
+			wantErr:     true,
+			expectedErr: "验证码不存在或已过期",
+		},
 		{
 			name:  "空邮箱验证码",
 			email: "",
 			setupMock: func(mock redismock.ClientMock) {
 				key := "verification_code:"
+				mock.ExpectTxPipeline()
 				mock.ExpectGet(key).SetVal("123456")
 				mock.ExpectTTL(key).SetVal(10 * time.Minute)
+				mock.ExpectTxPipelineExec()
 			},
 			wantCode: &biz.VerificationCode{
 				Email: "",
@@ -188,8 +210,10 @@ func TestDataRepository_GetVerificationCode(t *testing.T) {
 			email: "test+tag@example-domain.co.uk",
 			setupMock: func(mock redismock.ClientMock) {
 				key := "verification_code:test+tag@example-domain.co.uk"
+				mock.ExpectTxPipeline()
 				mock.ExpectGet(key).SetVal("123456")
 				mock.ExpectTTL(key).SetVal(10 * time.Minute)
+				mock.ExpectTxPipelineExec()
 			},
 			wantCode: &biz.VerificationCode{
 				Email: "test+tag@example-domain.co.uk",
@@ -336,6 +360,355 @@ func TestDataRepository_DeleteVerificationCode(t *testing.T) {
 	}
 }
 
+// TestDataRepository_ConsumeIfValid 测试原子校验并消费验证码
+func TestDataRepository_ConsumeIfValid(t *testing.T) {
+	key := "verification_code:test@example.com"
+	keys := []string{key}
+
+	tests := []struct {
+		name      string
+		setupMock func(redismock.ClientMock)
+		wantValid bool
+		wantErr   error
+		errSubstr string
+	}{
+		{
+			name: "验证码一致时消费成功",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(consumeCodeScript.Hash(), keys, "hashed").SetVal(int64(1))
+			},
+			wantValid: true,
+		},
+		{
+			name: "验证码不一致时不删除",
+			setupMock: func(mock redismock.ClientMock) {
+				// 脚本返回0表示未删除，之后不应再有任何 DEL 调用
+				mock.ExpectEvalSha(consumeCodeScript.Hash(), keys, "hashed").SetVal(int64(0))
+			},
+			wantValid: false,
+		},
+		{
+			name: "验证码不存在或已过期",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(consumeCodeScript.Hash(), keys, "hashed").SetVal(int64(-1))
+			},
+			wantErr: biz.ErrVerificationCodeExpired,
+		},
+		{
+			name: "脚本未缓存时回退到EVAL",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(consumeCodeScript.Hash(), keys, "hashed").SetErr(fmt.Errorf("NOSCRIPT No matching script"))
+				mock.ExpectEval(consumeCodeLua, keys, "hashed").SetVal(int64(1))
+			},
+			wantValid: true,
+		},
+		{
+			name: "Redis连接错误",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(consumeCodeScript.Hash(), keys, "hashed").SetErr(fmt.Errorf("connection error_reason"))
+			},
+			errSubstr: "connection error_reason",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+			repo := NewCodeRepository(&Data{rds: client}, log.DefaultLogger)
+
+			valid, err := repo.ConsumeIfValid(context.Background(), "test@example.com", biz.CodePurposeRegister, "hashed")
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.errSubstr != "":
+				assert.ErrorContains(t, err, tt.errSubstr)
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantValid, valid)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestDataRepository_ExtendVerificationCodeTTL 测试延长验证码有效期
+func TestDataRepository_ExtendVerificationCodeTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		purpose     string
+		extra       time.Duration
+		setupMock   func(redismock.ClientMock)
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:    "成功延长已存在的验证码",
+			email:   "test@example.com",
+			purpose: biz.CodePurposeRegister,
+			extra:   5 * time.Minute,
+			setupMock: func(mock redismock.ClientMock) {
+				key := "verification_code:test@example.com"
+				mock.ExpectTTL(key).SetVal(3 * time.Minute)
+				mock.ExpectExpire(key, 8*time.Minute).SetVal(true)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "非注册用途使用独立key",
+			email:   "test@example.com",
+			purpose: "change_email",
+			extra:   5 * time.Minute,
+			setupMock: func(mock redismock.ClientMock) {
+				key := "verification_code:change_email:test@example.com"
+				mock.ExpectTTL(key).SetVal(time.Minute)
+				mock.ExpectExpire(key, 6*time.Minute).SetVal(true)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "延长后超过上限时按上限设置",
+			email:   "test@example.com",
+			purpose: biz.CodePurposeRegister,
+			extra:   time.Hour,
+			setupMock: func(mock redismock.ClientMock) {
+				key := "verification_code:test@example.com"
+				mock.ExpectTTL(key).SetVal(9 * time.Minute)
+				mock.ExpectExpire(key, defaultMaxCodeTTL).SetVal(true)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "验证码不存在",
+			email:   "nonexistent@example.com",
+			purpose: biz.CodePurposeRegister,
+			extra:   5 * time.Minute,
+			setupMock: func(mock redismock.ClientMock) {
+				key := "verification_code:nonexistent@example.com"
+				mock.ExpectTTL(key).SetVal(time.Duration(-2))
+			},
+			wantErr:     true,
+			expectedErr: biz.ErrVerificationCodeExpired,
+		},
+		{
+			name:    "设置过期时间前验证码已过期",
+			email:   "test@example.com",
+			purpose: biz.CodePurposeRegister,
+			extra:   5 * time.Minute,
+			setupMock: func(mock redismock.ClientMock) {
+				key := "verification_code:test@example.com"
+				mock.ExpectTTL(key).SetVal(time.Second)
+				mock.ExpectExpire(key, 5*time.Minute+time.Second).SetVal(false)
+			},
+			wantErr:     true,
+			expectedErr: biz.ErrVerificationCodeExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			repo := NewCodeRepository(&Data{rds: client}, log.DefaultLogger)
+
+			err := repo.ExtendVerificationCodeTTL(context.Background(), tt.email, tt.purpose, tt.extra)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestDataRepository_CheckAndIncrDailySendLimit 测试每日发送上限：超过上限的发送被拒绝，计数过期后重新计数
+func TestDataRepository_CheckAndIncrDailySendLimit(t *testing.T) {
+	const (
+		email = "test@example.com"
+		key   = "rate_limit:send_code_daily:test@example.com"
+		limit = 10
+	)
+	resetAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)
+
+	t.Run("第 limit+1 次发送被拒绝", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		for i := 1; i <= limit+1; i++ {
+			mock.ExpectIncr(key).SetVal(int64(i))
+			mock.ExpectExpireAt(key, resetAt).SetVal(true)
+		}
+
+		repo := NewCodeRepository(&Data{rds: client}, log.DefaultLogger)
+		for i := 1; i <= limit; i++ {
+			ok, err := repo.CheckAndIncrDailySendLimit(context.Background(), email, limit, resetAt)
+			require.NoError(t, err)
+			assert.True(t, ok, "第 %d 次发送应允许", i)
+		}
+
+		ok, err := repo.CheckAndIncrDailySendLimit(context.Background(), email, limit, resetAt)
+		require.NoError(t, err)
+		assert.False(t, ok, "超过每日上限应拒绝")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("计数过期后重新计数", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		mock.ExpectIncr(key).SetVal(limit + 1)
+		mock.ExpectExpireAt(key, resetAt).SetVal(true)
+		// 计数 key 到 resetAt 过期后，INCR 从 1 重新开始
+		nextResetAt := resetAt.AddDate(0, 0, 1)
+		mock.ExpectIncr(key).SetVal(1)
+		mock.ExpectExpireAt(key, nextResetAt).SetVal(true)
+
+		repo := NewCodeRepository(&Data{rds: client}, log.DefaultLogger)
+		ok, err := repo.CheckAndIncrDailySendLimit(context.Background(), email, limit, resetAt)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.CheckAndIncrDailySendLimit(context.Background(), email, limit, nextResetAt)
+		require.NoError(t, err)
+		assert.True(t, ok, "过期后应允许再次发送")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Redis错误", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		mock.ExpectIncr(key).SetErr(fmt.Errorf("redis connection error_reason"))
+
+		repo := NewCodeRepository(&Data{rds: client}, log.DefaultLogger)
+		ok, err := repo.CheckAndIncrDailySendLimit(context.Background(), email, limit, resetAt)
+		assert.Error(t, err)
+		assert.False(t, ok)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestDataRepository_MaxCodeTTL 测试存储和延期验证码时有效期不超过配置的上限
+func TestDataRepository_MaxCodeTTL(t *testing.T) {
+	const (
+		email  = "test@example.com"
+		key    = "verification_code:test@example.com"
+		maxTTL = 15 * time.Minute
+	)
+
+	t.Run("存储时超过上限按上限设置", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		mock.ExpectSet(key, "123456", maxTTL).SetVal("OK")
+
+		repo := NewCodeRepository(&Data{rds: client, maxCodeTTL: maxTTL}, log.DefaultLogger)
+		err := repo.StoreVerificationCode(context.Background(), email, "123456", time.Now().Add(24*time.Hour))
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("延期时超过上限按上限设置", func(t *testing.T) {
+		client, mock := redismock.NewClientMock()
+		mock.ExpectTTL(key).SetVal(10 * time.Minute)
+		mock.ExpectExpire(key, maxTTL).SetVal(true)
+
+		repo := NewCodeRepository(&Data{rds: client, maxCodeTTL: maxTTL}, log.DefaultLogger)
+		err := repo.ExtendVerificationCodeTTL(context.Background(), email, biz.CodePurposeRegister, 10*time.Minute)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestDataRepository_GetVerificationCodeTTL 测试读取验证码剩余有效期
+func TestDataRepository_GetVerificationCodeTTL(t *testing.T) {
+	const (
+		email = "test@example.com"
+		key   = "verification_code:test@example.com"
+	)
+
+	tests := []struct {
+		name        string
+		setupMock   func(mock redismock.ClientMock)
+		expectedTTL time.Duration
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name: "验证码存在",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectTTL(key).SetVal(90 * time.Second)
+			},
+			expectedTTL: 90 * time.Second,
+		},
+		{
+			name: "验证码不存在或已过期",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectTTL(key).SetVal(-2)
+			},
+			expectedErr: biz.ErrVerificationCodeExpired,
+			wantErr:     true,
+		},
+		{
+			name: "Redis错误",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectTTL(key).SetErr(fmt.Errorf("redis connection error_reason"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			repo := NewCodeRepository(&Data{rds: client}, log.DefaultLogger)
+			ttl, err := repo.GetVerificationCodeTTL(context.Background(), email)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedTTL, ttl)
+				assert.Greater(t, ttl, time.Duration(0))
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestDataRepository_CheckAndIncrCodeStatusLimit 测试验证码状态查询的频率限制
+func TestDataRepository_CheckAndIncrCodeStatusLimit(t *testing.T) {
+	const (
+		email = "test@example.com"
+		key   = "rate_limit:code_status:test@example.com"
+		limit = 5
+	)
+	resetAt := time.Date(2024, 1, 1, 0, 1, 0, 0, time.Local)
+
+	client, mock := redismock.NewClientMock()
+	for i := 1; i <= limit+1; i++ {
+		mock.ExpectIncr(key).SetVal(int64(i))
+		mock.ExpectExpireAt(key, resetAt).SetVal(true)
+	}
+
+	repo := NewCodeRepository(&Data{rds: client}, log.DefaultLogger)
+	for i := 1; i <= limit; i++ {
+		ok, err := repo.CheckAndIncrCodeStatusLimit(context.Background(), email, limit, resetAt)
+		require.NoError(t, err)
+		assert.True(t, ok, "第 %d 次查询应允许", i)
+	}
+
+	ok, err := repo.CheckAndIncrCodeStatusLimit(context.Background(), email, limit, resetAt)
+	require.NoError(t, err)
+	assert.False(t, ok, "超过查询上限应拒绝")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // TestDataRepository_Integration 测试完整的验证码生命周期
 func TestDataRepository_Integration(t *testing.T) {
 	client, mock := redismock.NewClientMock()
@@ -360,8 +733,10 @@ func TestDataRepository_Integration(t *testing.T) {
 	assert.NoError(t, err)
 
 	// 2. 获取验证码
+	mock.ExpectTxPipeline()
 	mock.ExpectGet(key).SetVal(code)
 	mock.ExpectTTL(key).SetVal(-1 * time.Second) // -1表示永不过期
+	mock.ExpectTxPipelineExec()
 	storedCode, err := repo.GetVerificationCode(context.Background(), email)
 	assert.NoError(t, err)
 	assert.Equal(t, email, storedCode.Email)
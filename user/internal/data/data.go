@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"os"
+	"time"
+	"user/internal/biz"
 	"user/internal/conf"
 
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/extra/redisotel/v8"
 	"github.com/go-redis/redis/v8"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/google/wire"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -20,35 +26,52 @@ var ProviderSet = wire.NewSet(
 	NewDB,
 	NewRedis,
 	NewUserRepository,
-	NewCodeRepository,
-	NewAuthRepository,
+	NewCodeRepositoryFromConfig,
+	NewAuthRepositoryFromConfig,
+	NewEmailSender,
+	NewEmailLogRepository,
+	NewPointTransactionRepository,
+	NewUserPointRepository,
+	NewPersonalTokenRepository,
+	NewTransaction,
 )
 
 // Data .
 type Data struct {
-	rds *redis.Client
-	db  *gorm.DB
+	rds  *redis.Client
+	db   *gorm.DB
+	keys redisKeys
+	// maxCodeTTL 验证码剩余有效期的上限，0 表示使用默认值
+	maxCodeTTL time.Duration
 }
 
 // NewData .
 func NewData(c *conf.Data, logger log.Logger) (*Data, func(), error) {
-	// 从环境变量获取Redis密码，优先级最高
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	if redisPassword == "" && c.Redis.Password != "" {
-		redisPassword = c.Redis.Password
-	}
-
-	// 初始化Redis客户端
-	rds := redis.NewClient(&redis.Options{
-		Addr:     c.Redis.Addr,
-		Password: redisPassword,
-	})
-
-	// 测试Redis连接
-	_, err := rds.Ping(context.Background()).Result()
-	if err != nil {
-		log.NewHelper(logger).Errorf("Failed to connect to Redis: %v", err)
-		return nil, nil, err
+	var rds *redis.Client
+	if c.CacheDriver == CacheDriverMemory {
+		// 使用内存存储时不连接Redis
+		log.NewHelper(logger).Warn("Using in-memory cache driver, data will be lost on restart")
+	} else {
+		// 从环境变量获取Redis密码，优先级最高
+		redisPassword := os.Getenv("REDIS_PASSWORD")
+		if redisPassword == "" && c.Redis.Password != "" {
+			redisPassword = c.Redis.Password
+		}
+
+		// 初始化Redis客户端
+		rds = redis.NewClient(&redis.Options{
+			Addr:     c.Redis.Addr,
+			Password: redisPassword,
+		})
+		// 每条 Redis 命令生成一个子 span，记录命令内容
+		rds.AddHook(redisotel.NewTracingHook())
+
+		// 测试Redis连接
+		_, err := rds.Ping(context.Background()).Result()
+		if err != nil {
+			log.NewHelper(logger).Errorf("Failed to connect to Redis: %v", err)
+			return nil, nil, err
+		}
 	}
 
 	// 从环境变量获取数据库密码，优先级最高
@@ -78,6 +101,12 @@ func NewData(c *conf.Data, logger log.Logger) (*Data, func(), error) {
 		return nil, nil, err
 	}
 
+	// 每条 SQL 生成一个子 span，记录执行的语句
+	if err := instrumentDB(db, c.Database.Database); err != nil {
+		log.NewHelper(logger).Errorf("Failed to register GORM tracing plugin: %v", err)
+		return nil, nil, err
+	}
+
 	// 测试MySQL连接
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -92,13 +121,18 @@ func NewData(c *conf.Data, logger log.Logger) (*Data, func(), error) {
 	}
 
 	d := &Data{
-		rds: rds,
-		db:  db,
+		rds:  rds,
+		db:   db,
+		keys: redisKeys{prefix: c.Redis.GetKeyPrefix()},
+
+		maxCodeTTL: c.GetMaxCodeTtl().AsDuration(),
 	}
 
 	cleanup := func() {
 		log.NewHelper(logger).Info("closing the data resources")
-		_ = rds.Close()
+		if rds != nil {
+			_ = rds.Close()
+		}
 		_ = sqlDB.Close()
 	}
 	return d, cleanup, nil
@@ -123,3 +157,93 @@ func NewDB(data *Data) *gorm.DB {
 func NewRedis(data *Data) *redis.Client {
 	return data.rds
 }
+
+// instrumentDB 为 GORM 注册 OpenTelemetry 插件，使用全局 TracerProvider
+func instrumentDB(db *gorm.DB, dbName string) error {
+	return db.Use(otelgorm.NewPlugin(otelgorm.WithDBName(dbName)))
+}
+
+// contextTxKey 事务在 context 中的 key
+type contextTxKey struct{}
+
+// transaction 基于 GORM 的事务实现，事务对象通过 context 传递给各 repository
+type transaction struct {
+	db *gorm.DB
+}
+
+// NewTransaction 创建事务管理实例
+func NewTransaction(db *gorm.DB) biz.Transaction {
+	return &transaction{db: db}
+}
+
+// InTx 在同一个数据库事务中执行 fn，fn 返回错误时回滚
+func (t *transaction) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, contextTxKey{}, tx))
+	})
+}
+
+// InTxWithRetry 在事务中执行 fn，遇到死锁或锁等待超时时回滚并重试，每次重试前等待带随机抖动的递增时间
+// 已处于外层事务中时不重试，锁冲突会使整个外层事务回滚，应由外层决定是否重试
+func (t *transaction) InTxWithRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(contextTxKey{}).(*gorm.DB); ok {
+		return t.InTx(ctx, fn)
+	}
+
+	var err error
+	for attempt := 1; attempt <= txMaxAttempts; attempt++ {
+		err = t.InTx(ctx, fn)
+		if !isLockConflictError(err) {
+			return err
+		}
+		if attempt == txMaxAttempts {
+			break
+		}
+
+		wait := time.Duration(attempt)*txRetryBaseWait + time.Duration(rand.Int63n(int64(txRetryBaseWait)))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return fmt.Errorf("%w after %d attempts: %v", biz.ErrTxRetriesExhausted, txMaxAttempts, err)
+}
+
+// dbFromContext 如果 context 中存在事务则使用事务，否则使用默认连接
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(contextTxKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
+
+// mysqlErrDuplicateEntry MySQL 唯一键冲突的错误码
+const mysqlErrDuplicateEntry = 1062
+
+// isDuplicateKeyError 判断错误是否为唯一键冲突
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// MySQL 锁冲突的错误码，事务已被回滚或可安全回滚后重试
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrDeadlock        = 1213
+)
+
+// 锁冲突时事务的重试参数
+const (
+	txMaxAttempts   = 3
+	txRetryBaseWait = 10 * time.Millisecond
+)
+
+// isLockConflictError 判断错误是否为死锁或锁等待超时
+func isLockConflictError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+}
@@ -0,0 +1,302 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"user/internal/biz"
+	"user/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"gorm.io/gorm"
+	"user/internal/pkg/tracing"
+)
+
+// defaultEmailSendTimeout 未配置 email.send_timeout 时单次发送邮件的超时时间
+const defaultEmailSendTimeout = 10 * time.Second
+
+// sendGridEmailSender 基于 SendGrid 的邮件发送实现
+type sendGridEmailSender struct {
+	logger *log.Helper
+}
+
+// 邮件服务商名称，对应 email.providers 的取值，也作为服务商发送结果指标的 provider 标签
+const (
+	EmailProviderSendGrid = "sendgrid"
+	EmailProviderSMTP     = "smtp"
+)
+
+// NewEmailSender 创建邮件发送实例，每个服务商的每次发送都受 email.send_timeout 限制
+// 配置了多个 email.providers 时按顺序尝试，当前服务商失败时改用下一个
+// 配置了 email.max_concurrent_sends 时限制同时进行的发送数，等待名额的时间不计入单次发送超时
+func NewEmailSender(c *conf.Email, logger log.Logger) biz.EmailSender {
+	timeout := c.GetSendTimeout().AsDuration()
+	if timeout <= 0 {
+		timeout = defaultEmailSendTimeout
+	}
+	names := c.GetProviders()
+	if len(names) == 0 {
+		names = []string{EmailProviderSendGrid}
+	}
+	providers := make([]EmailProvider, 0, len(names))
+	for _, name := range names {
+		var sender biz.EmailSender
+		switch name {
+		case EmailProviderSMTP:
+			smtpConf := c.GetSmtp()
+			sender = newSMTPEmailSender(smtpConf.GetHost(), int(smtpConf.GetPort()), smtpConf.GetUsername(), logger)
+		default:
+			sender = &sendGridEmailSender{logger: log.NewHelper(logger)}
+		}
+		providers = append(providers, EmailProvider{Name: name, Sender: newTimeoutEmailSender(sender, timeout)})
+	}
+
+	var sender biz.EmailSender = providers[0].Sender
+	if len(providers) > 1 {
+		sender = NewMultiSender(providers, nil, logger)
+	}
+	if limit := int(c.GetMaxConcurrentSends()); limit > 0 {
+		sender = newLimitedEmailSender(sender, limit, c.GetFailFastWhenSaturated())
+	}
+	if prefix := c.GetSubjectPrefix(); prefix != "" {
+		sender = newSubjectPrefixEmailSender(sender, prefix)
+	}
+	return sender
+}
+
+const (
+	// emailMeterName 邮件发送指标所属的 meter
+	emailMeterName = "user/internal/data"
+	// emailProviderSendsMetric 按服务商和结果统计的邮件发送次数指标
+	emailProviderSendsMetric = "email_provider_sends_total"
+)
+
+// EmailProvider 带名称的邮件服务商，名称用于日志和指标
+type EmailProvider struct {
+	Name   string
+	Sender biz.EmailSender
+}
+
+// MultiSender 按顺序尝试多个邮件服务商，当前服务商故障或暂时不可用时改用下一个，避免单个服务商故障导致无法注册
+// 服务商拒绝邮件本身（biz.ErrEmailRejected）时不再尝试后续服务商，避免同一封无效邮件在每个服务商都被拒绝一次
+// 每次尝试都按服务商和结果（success/failure）累加指标，指标通过全局 MeterProvider 上报
+type MultiSender struct {
+	providers []EmailProvider
+	sends     metric.Int64Counter
+	logger    *log.Helper
+}
+
+// NewMultiSender 创建多服务商邮件发送实例，meter 为空时使用全局 MeterProvider
+func NewMultiSender(providers []EmailProvider, meter metric.Meter, logger log.Logger) *MultiSender {
+	if meter == nil {
+		meter = otel.Meter(emailMeterName)
+	}
+	helper := log.NewHelper(logger)
+	sends, err := meter.Int64Counter(emailProviderSendsMetric,
+		metric.WithDescription("Number of email send attempts by provider and outcome"))
+	if err != nil {
+		// 指标创建失败不影响发送本身
+		helper.Errorf("Failed to create email provider send counter, error_reason: %v", err)
+		sends = noop.Int64Counter{}
+	}
+	return &MultiSender{providers: providers, sends: sends, logger: helper}
+}
+
+// Send 依次通过各服务商发送邮件，任一服务商成功即返回
+// 邮件被拒绝或调用方的 context 已取消、超时时不再尝试后续服务商；全部失败时返回各服务商的错误
+func (s *MultiSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	var errs []error
+	for i, provider := range s.providers {
+		err := provider.Sender.Send(ctx, message)
+		if err == nil {
+			s.record(ctx, provider.Name, "success")
+			if i > 0 {
+				s.logger.WithContext(ctx).Warnf("Email sent via fallback provider: %s, to: %s", provider.Name, message.ToEmail)
+			}
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+		if errors.Is(err, biz.ErrEmailRejected) {
+			s.record(ctx, provider.Name, "rejected")
+			s.logger.WithContext(ctx).Warnf("Email rejected by provider: %s, to: %s, not falling back, error_reason: %v", provider.Name, message.ToEmail, err)
+			break
+		}
+		s.record(ctx, provider.Name, "failure")
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			s.logger.WithContext(ctx).Warnf("Email send aborted after provider: %s, error_reason: %v", provider.Name, ctxErr)
+			break
+		}
+		if i < len(s.providers)-1 {
+			s.logger.WithContext(ctx).Warnf("Email provider %s failed, falling back to %s, error_reason: %v", provider.Name, s.providers[i+1].Name, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *MultiSender) record(ctx context.Context, provider, outcome string) {
+	s.sends.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("provider", provider),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// limitedEmailSender 用信号量限制同时进行的发送数，达到上限时按配置等待空闲名额或立即失败
+type limitedEmailSender struct {
+	next     biz.EmailSender
+	sem      chan struct{}
+	failFast bool
+}
+
+func newLimitedEmailSender(next biz.EmailSender, limit int, failFast bool) *limitedEmailSender {
+	return &limitedEmailSender{next: next, sem: make(chan struct{}, limit), failFast: failFast}
+}
+
+// Send 取得发送名额后发送邮件；等待名额时 context 取消或超时则返回 context 的错误
+func (s *limitedEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	if s.failFast {
+		select {
+		case s.sem <- struct{}{}:
+		default:
+			return biz.ErrEmailSenderBusy
+		}
+	} else {
+		select {
+		case s.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer func() { <-s.sem }()
+	return s.next.Send(ctx, message)
+}
+
+// timeoutEmailSender 为每次发送设置超时，邮件服务商响应过慢时尽快失败，避免阻塞发送验证码请求
+type timeoutEmailSender struct {
+	next    biz.EmailSender
+	timeout time.Duration
+}
+
+func newTimeoutEmailSender(next biz.EmailSender, timeout time.Duration) *timeoutEmailSender {
+	return &timeoutEmailSender{next: next, timeout: timeout}
+}
+
+// Send 在带超时的 context 中发送邮件
+func (s *timeoutEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.next.Send(ctx, message)
+}
+
+// subjectPrefixEmailSender 在邮件标题前加上配置的前缀，用于区分非生产环境发出的邮件
+type subjectPrefixEmailSender struct {
+	next   biz.EmailSender
+	prefix string
+}
+
+func newSubjectPrefixEmailSender(next biz.EmailSender, prefix string) *subjectPrefixEmailSender {
+	return &subjectPrefixEmailSender{next: next, prefix: prefix}
+}
+
+// Send 复制邮件并在标题前加上前缀后发送，不修改调用方的邮件内容
+func (s *subjectPrefixEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	prefixed := *message
+	prefixed.Subject = s.prefix + message.Subject
+	return s.next.Send(ctx, &prefixed)
+}
+
+// Send 通过 SendGrid 发送邮件
+func (s *sendGridEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	ctx, span := tracing.StartSpan(ctx, "EmailSender.Send")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"to_email": message.ToEmail,
+		"subject":  message.Subject,
+	})
+
+	// 从环境变量获取 API Key
+	apiKey := os.Getenv("SENDGRID_API_KEY")
+	if apiKey == "" {
+		s.logger.WithContext(ctx).Error("SENDGRID_API_KEY environment variable is not set")
+		return errors.New("SENDGRID_API_KEY is not set")
+	}
+
+	// 检查是否为测试环境（API key以"test-"开头）
+	if strings.HasPrefix(apiKey, "test-") {
+		s.logger.WithContext(ctx).Infof("Test mode: skipping actual email send, email: %s", message.ToEmail)
+		return nil
+	}
+
+	email := mail.NewSingleEmail(
+		mail.NewEmail(message.FromName, message.FromEmail),
+		message.Subject,
+		mail.NewEmail(message.ToName, message.ToEmail),
+		message.PlainText,
+		message.HTML,
+	)
+
+	response, err := sendgrid.NewSendClient(apiKey).SendWithContext(ctx, email)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to send email to: %s, error_reason: %v", message.ToEmail, err)
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		s.logger.WithContext(ctx).Errorf("Failed to send email, status: %d, body: %s", response.StatusCode, response.Body)
+		return sendGridStatusError(response.StatusCode)
+	}
+
+	s.logger.WithContext(ctx).Infof("Email sent successfully to: %s, status: %d", message.ToEmail, response.StatusCode)
+	return nil
+}
+
+// sendGridStatusError 将 SendGrid 的非 2xx 状态码转换为错误
+// 400（收件人或内容无效）和 413（邮件过大）是邮件本身的问题，包装 biz.ErrEmailRejected；
+// 其余状态码（认证失败、限流、服务端错误）视为服务商问题，可改用其他服务商
+func sendGridStatusError(status int) error {
+	if status == http.StatusBadRequest || status == http.StatusRequestEntityTooLarge {
+		return fmt.Errorf("sendgrid responded with status %d: %w", status, biz.ErrEmailRejected)
+	}
+	return fmt.Errorf("sendgrid responded with status %d", status)
+}
+
+// emailLogRepository 邮件发送记录数据访问实现
+type emailLogRepository struct {
+	db     *gorm.DB
+	logger *log.Helper
+}
+
+// NewEmailLogRepository 创建邮件发送记录数据访问实例
+func NewEmailLogRepository(db *gorm.DB, logger log.Logger) biz.EmailLogRepository {
+	return &emailLogRepository{db: db, logger: log.NewHelper(logger)}
+}
+
+// Create 写入一条邮件发送记录
+func (r *emailLogRepository) Create(ctx context.Context, emailLog *biz.EmailLog) error {
+	ctx, span := tracing.StartSpan(ctx, "EmailLogRepository.Create")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email_type": emailLog.EmailType,
+		"status":     emailLog.Status,
+	})
+
+	err := r.db.WithContext(ctx).Create(emailLog).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to create email log, type: %s, error_reason: %v", emailLog.EmailType, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package data
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"user/internal/pkg/tracing"
+)
+
+// defaultSMTPPort 未配置 email.smtp.port 时使用的 SMTP 提交端口
+const defaultSMTPPort = 587
+
+// smtpEmailSender 基于 SMTP 的邮件发送实现，通常作为 SendGrid 不可用时的备用服务商
+type smtpEmailSender struct {
+	host     string
+	port     int
+	username string
+	logger   *log.Helper
+}
+
+func newSMTPEmailSender(host string, port int, username string, logger log.Logger) *smtpEmailSender {
+	if port <= 0 {
+		port = defaultSMTPPort
+	}
+	return &smtpEmailSender{host: host, port: port, username: username, logger: log.NewHelper(logger)}
+}
+
+// Send 通过 SMTP 发送邮件，服务器支持时使用 STARTTLS，配置了用户名时使用 SMTP_PASSWORD 认证
+func (s *smtpEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	ctx, span := tracing.StartSpan(ctx, "SMTPEmailSender.Send")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"to_email": message.ToEmail,
+		"subject":  message.Subject,
+	})
+
+	if s.host == "" {
+		return errors.New("smtp host is not configured")
+	}
+
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to connect to smtp server: %s, error_reason: %v", addr, err)
+		return err
+	}
+	// net/smtp 不感知 context，用连接的截止时间限制整个会话
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		s.logger.WithContext(ctx).Errorf("Failed to start smtp session with: %s, error_reason: %v", addr, err)
+		return err
+	}
+	defer client.Close()
+
+	if err := s.deliver(client, message); err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to send email via smtp to: %s, error_reason: %v", message.ToEmail, err)
+		return err
+	}
+
+	s.logger.WithContext(ctx).Infof("Email sent successfully via smtp to: %s", message.ToEmail)
+	return nil
+}
+
+// deliver 在已建立的 SMTP 会话中完成加密、认证和投递
+func (s *smtpEmailSender) deliver(client *smtp.Client, message *biz.EmailMessage) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if s.username != "" {
+		auth := smtp.PlainAuth("", s.username, os.Getenv("SMTP_PASSWORD"), s.host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+	if err := client.Mail(message.FromEmail); err != nil {
+		return fmt.Errorf("mail from: %w", err)
+	}
+	if err := client.Rcpt(message.ToEmail); err != nil {
+		return fmt.Errorf("rcpt to: %w", smtpRejectionError(err))
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	if err := writeMIMEMessage(w, message); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("data: %w", smtpRejectionError(err))
+	}
+	return client.Quit()
+}
+
+// smtpRejectionError 服务器在收件人或邮件内容阶段返回 5xx 永久性错误（如收件人不存在、邮件被拒收）时包装 biz.ErrEmailRejected
+// 4xx 临时性错误和连接错误原样返回，可改用其他服务商
+func smtpRejectionError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 && protoErr.Code < 600 {
+		return fmt.Errorf("%w: %w", biz.ErrEmailRejected, err)
+	}
+	return err
+}
+
+// writeMIMEMessage 将邮件编码为同时包含纯文本和 HTML 的 multipart/alternative 消息
+func writeMIMEMessage(w io.Writer, message *biz.EmailMessage) error {
+	body := &strings.Builder{}
+	parts := multipart.NewWriter(body)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", message.PlainText},
+		{"text/html; charset=UTF-8", message.HTML},
+	} {
+		pw, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return err
+		}
+		if _, err := pw.Write([]byte(part.content)); err != nil {
+			return err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return err
+	}
+
+	header := &strings.Builder{}
+	fmt.Fprintf(header, "From: %s\r\n", formatAddress(message.FromName, message.FromEmail))
+	fmt.Fprintf(header, "To: %s\r\n", formatAddress(message.ToName, message.ToEmail))
+	fmt.Fprintf(header, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", message.Subject))
+	fmt.Fprintf(header, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(header, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+
+	if _, err := w.Write([]byte(header.String())); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte(body.String()))
+	return err
+}
+
+// formatAddress 组装带显示名称的邮件地址，名称按 RFC 2047 编码
+func formatAddress(name, email string) string {
+	if name == "" {
+		return "<" + email + ">"
+	}
+	return mime.QEncoding.Encode("UTF-8", name) + " <" + email + ">"
+}
@@ -0,0 +1,488 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"testing"
+	"time"
+	"user/internal/biz"
+	"user/internal/conf"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// TestEmailLogRepository_Create 测试写入邮件发送记录
+func TestEmailLogRepository_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		emailLog *biz.EmailLog
+		mockFn   func(sqlmock.Sqlmock)
+		wantErr  bool
+	}{
+		{
+			name: "发送成功写入sent记录",
+			emailLog: &biz.EmailLog{
+				EmailType:   biz.EmailTypeRegisterCode,
+				MaskedEmail: "tes*@example.com",
+				Status:      biz.EmailLogStatusSent,
+				CreatedAt:   time.Now(),
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `email_log`").
+					WithArgs(
+						biz.EmailTypeRegisterCode,
+						"tes*@example.com",
+						biz.EmailLogStatusSent,
+						"", // error_message
+						sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "发送失败写入failed记录及错误信息",
+			emailLog: &biz.EmailLog{
+				EmailType:    biz.EmailTypeRegisterCode,
+				MaskedEmail:  "tes*@example.com",
+				Status:       biz.EmailLogStatusFailed,
+				ErrorMessage: "sendgrid responded with status 503",
+				CreatedAt:    time.Now(),
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `email_log`").
+					WithArgs(
+						biz.EmailTypeRegisterCode,
+						"tes*@example.com",
+						biz.EmailLogStatusFailed,
+						"sendgrid responded with status 503",
+						sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "写入失败",
+			emailLog: &biz.EmailLog{
+				EmailType:   biz.EmailTypeRegisterCode,
+				MaskedEmail: "tes*@example.com",
+				Status:      biz.EmailLogStatusSent,
+				CreatedAt:   time.Now(),
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `email_log`").
+					WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewEmailLogRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := repo.Create(context.Background(), tt.emailLog)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotZero(t, tt.emailLog.ID)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// slowEmailSender 模拟响应缓慢的邮件服务商，发送耗时 delay，context 取消时提前返回
+type slowEmailSender struct {
+	delay time.Duration
+}
+
+func (s *slowEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	select {
+	case <-time.After(s.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TestTimeoutEmailSender_Send 测试发送耗时超过配置的超时时间时被取消
+func TestTimeoutEmailSender_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		timeout time.Duration
+		wantErr error
+	}{
+		{
+			name:    "超时内完成发送",
+			delay:   10 * time.Millisecond,
+			timeout: time.Second,
+		},
+		{
+			name:    "发送过慢时按超时取消",
+			delay:   time.Minute,
+			timeout: 50 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := newTimeoutEmailSender(&slowEmailSender{delay: tt.delay}, tt.timeout)
+
+			start := time.Now()
+			err := sender.Send(context.Background(), &biz.EmailMessage{ToEmail: "test@example.com"})
+			elapsed := time.Since(start)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.GreaterOrEqual(t, elapsed, tt.timeout)
+				assert.Less(t, elapsed, tt.timeout+time.Second, "应在超时后尽快返回")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestNewEmailSender_Timeout 测试发送超时取自配置，未配置时使用默认值
+func TestNewEmailSender_Timeout(t *testing.T) {
+	sender := NewEmailSender(&conf.Email{SendTimeout: durationpb.New(3 * time.Second)}, log.DefaultLogger)
+	assert.Equal(t, 3*time.Second, sender.(*timeoutEmailSender).timeout)
+
+	sender = NewEmailSender(nil, log.DefaultLogger)
+	assert.Equal(t, defaultEmailSendTimeout, sender.(*timeoutEmailSender).timeout)
+}
+
+// blockingEmailSender 发送时先通知 started，直到 release 收到信号才返回
+type blockingEmailSender struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	s.started <- struct{}{}
+	<-s.release
+	return nil
+}
+
+// TestLimitedEmailSender_Send 测试并发发送数达到上限时，后续发送等待空闲名额或按配置立即失败
+func TestLimitedEmailSender_Send(t *testing.T) {
+	tests := []struct {
+		name     string
+		failFast bool
+		timeout  time.Duration
+		wantErr  error
+	}{
+		{
+			name: "达到上限时等待其他发送完成",
+		},
+		{
+			name:     "达到上限时立即失败",
+			failFast: true,
+			wantErr:  biz.ErrEmailSenderBusy,
+		},
+		{
+			name:    "等待名额时请求超时",
+			timeout: 50 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &blockingEmailSender{started: make(chan struct{}, 3), release: make(chan struct{}, 3)}
+			sender := newLimitedEmailSender(next, 2, tt.failFast)
+			message := &biz.EmailMessage{ToEmail: "test@example.com"}
+
+			// 占满两个名额
+			for i := 0; i < 2; i++ {
+				go func() { _ = sender.Send(context.Background(), message) }()
+				<-next.started
+			}
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			done := make(chan error, 1)
+			go func() { done <- sender.Send(ctx, message) }()
+
+			if tt.wantErr != nil {
+				select {
+				case err := <-done:
+					assert.ErrorIs(t, err, tt.wantErr)
+				case <-time.After(time.Second):
+					t.Fatal("第三个发送应返回错误")
+				}
+				assert.Empty(t, next.started, "未取得名额时不应发送")
+				next.release <- struct{}{}
+				next.release <- struct{}{}
+				return
+			}
+
+			select {
+			case <-next.started:
+				t.Fatal("达到上限时第三个发送不应开始")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			// 一个发送完成后第三个发送取得名额
+			next.release <- struct{}{}
+			select {
+			case <-next.started:
+			case <-time.After(time.Second):
+				t.Fatal("名额释放后第三个发送应开始")
+			}
+			next.release <- struct{}{}
+			next.release <- struct{}{}
+			assert.NoError(t, <-done)
+		})
+	}
+}
+
+// TestNewEmailSender_ConcurrencyLimit 测试配置并发上限时包装信号量，等待名额不计入单次发送超时
+func TestNewEmailSender_ConcurrencyLimit(t *testing.T) {
+	sender := NewEmailSender(&conf.Email{MaxConcurrentSends: 2, FailFastWhenSaturated: true}, log.DefaultLogger)
+	limited, ok := sender.(*limitedEmailSender)
+	if assert.True(t, ok) {
+		assert.Equal(t, 2, cap(limited.sem))
+		assert.True(t, limited.failFast)
+		assert.IsType(t, &timeoutEmailSender{}, limited.next)
+	}
+}
+
+// stubEmailSender 返回固定错误并记录调用次数的邮件服务商
+type stubEmailSender struct {
+	err   error
+	calls int
+}
+
+func (s *stubEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	s.calls++
+	return s.err
+}
+
+// fakeSendCounter 按 provider/outcome 标签记录累加值的测试计数器
+type fakeSendCounter struct {
+	noop.Int64Counter
+	counts map[string]int64
+}
+
+func (c *fakeSendCounter) Add(_ context.Context, incr int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	provider, _ := attrs.Value(attribute.Key("provider"))
+	outcome, _ := attrs.Value(attribute.Key("outcome"))
+	c.counts[provider.AsString()+"/"+outcome.AsString()] += incr
+}
+
+// fakeSendMeter 返回 fakeSendCounter 的测试 meter
+type fakeSendMeter struct {
+	noop.Meter
+	counter *fakeSendCounter
+}
+
+func (m *fakeSendMeter) Int64Counter(string, ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return m.counter, nil
+}
+
+// TestMultiSender_Send 测试主服务商故障或暂时不可用时改用备用服务商，邮件被拒绝时不改用，并按服务商和结果计数
+func TestMultiSender_Send(t *testing.T) {
+	sendErr := errors.New("sendgrid responded with status 503")
+
+	tests := []struct {
+		name          string
+		primaryErr    error
+		secondaryErr  error
+		cancelCtx     bool
+		wantErr       bool
+		wantSecondary int
+		wantCounts    map[string]int64
+	}{
+		{
+			name:          "主服务商成功时不使用备用服务商",
+			wantSecondary: 0,
+			wantCounts:    map[string]int64{"sendgrid/success": 1},
+		},
+		{
+			name:          "主服务商失败时改用备用服务商",
+			primaryErr:    sendErr,
+			wantSecondary: 1,
+			wantCounts:    map[string]int64{"sendgrid/failure": 1, "smtp/success": 1},
+		},
+		{
+			name:          "全部服务商失败",
+			primaryErr:    sendErr,
+			secondaryErr:  errors.New("connection refused"),
+			wantErr:       true,
+			wantSecondary: 1,
+			wantCounts:    map[string]int64{"sendgrid/failure": 1, "smtp/failure": 1},
+		},
+		{
+			name:          "收件人地址无效时不改用备用服务商",
+			primaryErr:    smtpRejectionError(&textproto.Error{Code: 550, Msg: "5.1.1 no such user"}),
+			wantErr:       true,
+			wantSecondary: 0,
+			wantCounts:    map[string]int64{"sendgrid/rejected": 1},
+		},
+		{
+			name:          "邮件被拒收时不改用备用服务商",
+			primaryErr:    sendGridStatusError(http.StatusBadRequest),
+			wantErr:       true,
+			wantSecondary: 0,
+			wantCounts:    map[string]int64{"sendgrid/rejected": 1},
+		},
+		{
+			name:          "服务商认证失败时改用备用服务商",
+			primaryErr:    sendGridStatusError(http.StatusUnauthorized),
+			wantSecondary: 1,
+			wantCounts:    map[string]int64{"sendgrid/failure": 1, "smtp/success": 1},
+		},
+		{
+			name:          "临时性错误时改用备用服务商",
+			primaryErr:    smtpRejectionError(&textproto.Error{Code: 421, Msg: "4.7.0 try again later"}),
+			wantSecondary: 1,
+			wantCounts:    map[string]int64{"sendgrid/failure": 1, "smtp/success": 1},
+		},
+		{
+			name:          "请求已取消时不再尝试备用服务商",
+			primaryErr:    context.Canceled,
+			cancelCtx:     true,
+			wantErr:       true,
+			wantSecondary: 0,
+			wantCounts:    map[string]int64{"sendgrid/failure": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &stubEmailSender{err: tt.primaryErr}
+			secondary := &stubEmailSender{err: tt.secondaryErr}
+			counter := &fakeSendCounter{counts: map[string]int64{}}
+			sender := NewMultiSender([]EmailProvider{
+				{Name: EmailProviderSendGrid, Sender: primary},
+				{Name: EmailProviderSMTP, Sender: secondary},
+			}, &fakeSendMeter{counter: counter}, log.DefaultLogger)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelCtx {
+				cancel()
+			}
+			err := sender.Send(ctx, &biz.EmailMessage{ToEmail: "test@example.com"})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.primaryErr != nil {
+					assert.ErrorIs(t, err, tt.primaryErr)
+				}
+				assert.Equal(t, tt.wantCounts["sendgrid/rejected"] > 0, errors.Is(err, biz.ErrEmailRejected))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, 1, primary.calls)
+			assert.Equal(t, tt.wantSecondary, secondary.calls)
+			assert.Equal(t, tt.wantCounts, counter.counts)
+		})
+	}
+}
+
+// TestNewEmailSender_Providers 测试配置多个服务商时按配置顺序组装，每个服务商单独计算超时
+func TestNewEmailSender_Providers(t *testing.T) {
+	sender := NewEmailSender(&conf.Email{
+		Providers:   []string{"smtp", "sendgrid"},
+		Smtp:        &conf.Email_SMTP{Host: "smtp.example.com"},
+		SendTimeout: durationpb.New(3 * time.Second),
+	}, log.DefaultLogger)
+
+	multi, ok := sender.(*MultiSender)
+	require.True(t, ok, "多个服务商时应使用 MultiSender")
+	require.Len(t, multi.providers, 2)
+	assert.Equal(t, EmailProviderSMTP, multi.providers[0].Name)
+	assert.Equal(t, EmailProviderSendGrid, multi.providers[1].Name)
+	for _, provider := range multi.providers {
+		assert.Equal(t, 3*time.Second, provider.Sender.(*timeoutEmailSender).timeout)
+	}
+	smtpSender := multi.providers[0].Sender.(*timeoutEmailSender).next.(*smtpEmailSender)
+	assert.Equal(t, "smtp.example.com", smtpSender.host)
+	assert.Equal(t, defaultSMTPPort, smtpSender.port)
+}
+
+// recordingEmailSender 记录收到的邮件标题
+type recordingEmailSender struct {
+	subjects []string
+}
+
+func (s *recordingEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	s.subjects = append(s.subjects, message.Subject)
+	return nil
+}
+
+// TestNewEmailSender_SubjectPrefix 测试配置标题前缀时加在验证码邮件标题前且不修改原邮件，未配置时标题不变
+func TestNewEmailSender_SubjectPrefix(t *testing.T) {
+	const subject = "您的验证码 - 请在10分钟内使用"
+
+	tests := []struct {
+		name        string
+		prefix      string
+		wantSubject string
+	}{
+		{
+			name:        "配置前缀",
+			prefix:      "[STAGING] ",
+			wantSubject: "[STAGING] 您的验证码 - 请在10分钟内使用",
+		},
+		{
+			name:        "前缀为空",
+			wantSubject: subject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := NewEmailSender(&conf.Email{SubjectPrefix: tt.prefix}, log.DefaultLogger)
+			next := &recordingEmailSender{}
+			switch s := sender.(type) {
+			case *subjectPrefixEmailSender:
+				require.NotEmpty(t, tt.prefix, "未配置前缀时不应包装")
+				s.next = next
+			case *timeoutEmailSender:
+				require.Empty(t, tt.prefix, "配置前缀时应包装")
+				s.next = next
+			default:
+				t.Fatalf("unexpected sender type %T", sender)
+			}
+
+			message := &biz.EmailMessage{ToEmail: "user@example.com", Subject: subject}
+			require.NoError(t, sender.Send(context.Background(), message))
+
+			assert.Equal(t, []string{tt.wantSubject}, next.subjects)
+			assert.Equal(t, subject, message.Subject, "不应修改调用方的邮件")
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"fmt"
+
+	"user/internal/biz"
+)
+
+// redisKeys 统一构造 Redis key，所有 key 都带上配置的命名空间前缀，
+// 使多个环境共用同一个 Redis 实例时互不冲突；前缀为空时与原有 key 格式一致
+type redisKeys struct {
+	prefix string
+}
+
+// refreshToken 刷新令牌 key，值为用户ID；传入 "*" 可得到 SCAN 使用的匹配模式
+func (k redisKeys) refreshToken(token string) string {
+	return k.prefix + "refresh_token:" + token
+}
+
+// rotatedRefreshToken 已轮换刷新令牌的宽限期缓存 key，值为轮换签发的令牌对（JSON）
+func (k redisKeys) rotatedRefreshToken(token string) string {
+	return k.prefix + "rotated_refresh_token:" + token
+}
+
+// refreshTokenLastUsed 刷新令牌最近使用时间 key，值为使用时间（毫秒），在会话空闲超时后过期
+func (k redisKeys) refreshTokenLastUsed(token string) string {
+	return k.prefix + "refresh_token_last_used:" + token
+}
+
+// sessionIndex 用户会话索引的 key，有序集合的成员为刷新令牌、分数为创建时间（毫秒）
+func (k redisKeys) sessionIndex(userID int64) string {
+	return k.prefix + fmt.Sprintf("user_sessions:%d", userID)
+}
+
+// sessionIPs 用户会话来源 IP 的 key，哈希的字段为刷新令牌、值为签发时的客户端 IP；传入 "*" 可得到 SCAN 使用的匹配模式
+func (k redisKeys) sessionIPs(userID string) string {
+	return k.prefix + "session_ips:" + userID
+}
+
+// tokenFamily 令牌族 key，集合成员为与该刷新令牌配对签发的访问令牌 jti
+func (k redisKeys) tokenFamily(refreshTokenID string) string {
+	return k.prefix + "token_family:" + refreshTokenID
+}
+
+// tokensValidAfter 全局令牌失效时间点 key，值为时间点（毫秒），不过期
+func (k redisKeys) tokensValidAfter() string {
+	return k.prefix + "tokens_valid_after"
+}
+
+// passwordFailures 用户密码校验失败次数计数 key
+func (k redisKeys) passwordFailures(userID int64) string {
+	return k.prefix + fmt.Sprintf("password_failures:%d", userID)
+}
+
+// verificationCode 验证码 key
+func (k redisKeys) verificationCode(purpose, email string) string {
+	return k.prefix + verificationCodeKey(purpose, email)
+}
+
+// sendCodeRateLimit 验证码发送频率限制 key
+func (k redisKeys) sendCodeRateLimit(email string) string {
+	return k.prefix + sendCodeRateLimitKey(email)
+}
+
+// sendCodeDailyCount 验证码每日发送次数计数 key
+func (k redisKeys) sendCodeDailyCount(email string) string {
+	return k.prefix + sendCodeDailyCountKey(email)
+}
+
+// codeStatusQueryCount 验证码状态查询次数计数 key
+func (k redisKeys) codeStatusQueryCount(email string) string {
+	return k.prefix + codeStatusQueryCountKey(email)
+}
+
+// registrationAttemptCount 按客户端 IP 统计注册请求次数的计数 key
+func (k redisKeys) registrationAttemptCount(ip string) string {
+	return k.prefix + registrationAttemptCountKey(ip)
+}
+
+// testEmailSendCount 管理员发送诊断测试邮件次数的计数 key
+func (k redisKeys) testEmailSendCount(userID int64) string {
+	return k.prefix + testEmailSendCountKey(userID)
+}
+
+// verificationCodeKey 生成不带前缀的验证码 key，注册用途沿用原有 key 格式
+func verificationCodeKey(purpose, email string) string {
+	if purpose == "" || purpose == biz.CodePurposeRegister {
+		return fmt.Sprintf("verification_code:%s", email)
+	}
+	return fmt.Sprintf("verification_code:%s:%s", purpose, email)
+}
+
+// sendCodeRateLimitKey 生成不带前缀的发送频率限制 key
+func sendCodeRateLimitKey(email string) string {
+	return fmt.Sprintf("rate_limit:send_code:%s", email)
+}
+
+// sendCodeDailyCountKey 生成不带前缀的每日发送次数计数 key
+func sendCodeDailyCountKey(email string) string {
+	return fmt.Sprintf("rate_limit:send_code_daily:%s", email)
+}
+
+// codeStatusQueryCountKey 生成不带前缀的验证码状态查询次数计数 key
+func codeStatusQueryCountKey(email string) string {
+	return fmt.Sprintf("rate_limit:code_status:%s", email)
+}
+
+// registrationAttemptCountKey 生成不带前缀的注册请求次数计数 key
+func registrationAttemptCountKey(ip string) string {
+	return fmt.Sprintf("rate_limit:register_ip:%s", ip)
+}
+
+// testEmailSendCountKey 生成不带前缀的诊断测试邮件发送次数计数 key
+func testEmailSendCountKey(userID int64) string {
+	return fmt.Sprintf("rate_limit:test_email:%d", userID)
+}
@@ -0,0 +1,127 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRedisKeys_Prefix 测试配置前缀后刷新令牌和验证码的存取删除都使用带前缀的 key
+func TestRedisKeys_Prefix(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	// 验证码有效期需在 defaultMaxCodeTTL 以内，否则会被截断
+	codeExpiresAt := time.Now().Add(10 * time.Minute)
+
+	tests := []struct {
+		name   string
+		prefix string
+		mockFn func(mock redismock.ClientMock, prefix string)
+		run    func(ctx context.Context, data *Data) error
+	}{
+		{
+			name:   "存储刷新令牌",
+			prefix: "staging:",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectSet(prefix+"refresh_token:token-1", int64(1), time.Until(expiresAt)).SetVal("OK")
+			},
+			run: func(ctx context.Context, data *Data) error {
+				return NewAuthRepository(data, log.DefaultLogger).StoreRefreshToken(ctx, 1, "token-1", expiresAt)
+			},
+		},
+		{
+			name:   "读取刷新令牌",
+			prefix: "staging:",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectGet(prefix + "refresh_token:token-1").SetVal("1")
+			},
+			run: func(ctx context.Context, data *Data) error {
+				_, err := NewAuthRepository(data, log.DefaultLogger).GetUserIDByRefreshToken(ctx, "token-1")
+				return err
+			},
+		},
+		{
+			name:   "删除刷新令牌",
+			prefix: "staging:",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectDel(prefix + "refresh_token:token-1").SetVal(1)
+			},
+			run: func(ctx context.Context, data *Data) error {
+				return NewAuthRepository(data, log.DefaultLogger).DeleteRefreshToken(ctx, "token-1")
+			},
+		},
+		{
+			name:   "删除用户全部刷新令牌只扫描本环境的 key",
+			prefix: "staging:",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectScan(0, prefix+"refresh_token:*", -1).SetVal([]string{prefix + "refresh_token:token-1"}, 0)
+				mock.ExpectGet(prefix + "refresh_token:token-1").SetVal("1")
+				mock.ExpectDel(prefix + "refresh_token:token-1").SetVal(1)
+			},
+			run: func(ctx context.Context, data *Data) error {
+				return NewAuthRepository(data, log.DefaultLogger).DeleteAllRefreshTokens(ctx, 1)
+			},
+		},
+		{
+			name:   "存储验证码",
+			prefix: "prod:",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectSet(prefix+"verification_code:test@example.com", "hashed", time.Until(codeExpiresAt)).SetVal("OK")
+			},
+			run: func(ctx context.Context, data *Data) error {
+				return NewCodeRepository(data, log.DefaultLogger).StoreVerificationCode(ctx, "test@example.com", "hashed", codeExpiresAt)
+			},
+		},
+		{
+			name:   "读取验证码",
+			prefix: "prod:",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectTxPipeline()
+				mock.ExpectGet(prefix + "verification_code:test@example.com").SetVal("hashed")
+				mock.ExpectTTL(prefix + "verification_code:test@example.com").SetVal(time.Minute)
+				mock.ExpectTxPipelineExec()
+			},
+			run: func(ctx context.Context, data *Data) error {
+				_, err := NewCodeRepository(data, log.DefaultLogger).GetVerificationCode(ctx, "test@example.com")
+				return err
+			},
+		},
+		{
+			name:   "删除验证码",
+			prefix: "prod:",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectDel(prefix + "verification_code:test@example.com").SetVal(1)
+			},
+			run: func(ctx context.Context, data *Data) error {
+				return NewCodeRepository(data, log.DefaultLogger).DeleteVerificationCode(ctx, "test@example.com")
+			},
+		},
+		{
+			name:   "未配置前缀时沿用原有 key",
+			prefix: "",
+			mockFn: func(mock redismock.ClientMock, prefix string) {
+				mock.ExpectGet("refresh_token:token-1").SetVal("1")
+			},
+			run: func(ctx context.Context, data *Data) error {
+				_, err := NewAuthRepository(data, log.DefaultLogger).GetUserIDByRefreshToken(ctx, "token-1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rds, mock := redismock.NewClientMock()
+			data := &Data{rds: rds, keys: redisKeys{prefix: tt.prefix}}
+			tt.mockFn(mock, tt.prefix)
+
+			err := tt.run(context.Background(), data)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package data
+
+import (
+	"time"
+)
+
+// CacheDriverMemory 使用进程内存代替 Redis 存储验证码和令牌，仅用于本地开发和测试
+const CacheDriverMemory = "memory"
+
+// memoryJanitorInterval 内存存储清理过期数据的间隔
+const memoryJanitorInterval = time.Minute
+
+// memoryEntry 带过期时间的内存存储条目
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// expired 判断条目在 now 时是否已过期
+func (e memoryEntry) expired(now time.Time) bool {
+	return !now.Before(e.expiresAt)
+}
+
+// runJanitor 在后台定期调用 purge 清理过期数据，返回停止函数
+func runJanitor(interval time.Duration, purge func()) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				purge()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,169 @@
+package data
+
+import (
+	"context"
+	"time"
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"user/internal/pkg/tracing"
+)
+
+// personalTokenRepository 个人访问令牌数据访问实现
+type personalTokenRepository struct {
+	db     *gorm.DB
+	logger *log.Helper
+}
+
+// NewPersonalTokenRepository 创建个人访问令牌数据访问实例
+func NewPersonalTokenRepository(db *gorm.DB, logger log.Logger) biz.PersonalTokenRepository {
+	return &personalTokenRepository{db: db, logger: log.NewHelper(logger)}
+}
+
+// Create 保存新令牌，只写入令牌哈希
+func (r *personalTokenRepository) Create(ctx context.Context, token *biz.PersonalToken) error {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenRepository.Create")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": token.UserID,
+	})
+
+	if err := dbFromContext(ctx, r.db).Create(token).Error; err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to create personal token for user id: %d, error_reason: %v", token.UserID, err)
+		return err
+	}
+	return nil
+}
+
+// CreateWithinLimit 在事务中锁定用户行后统计未撤销的令牌数，未达到 limit 时保存新令牌
+// 以用户行作为同一用户并发创建的串行点，避免并发请求同时通过数量检查；已处于外层事务时直接加入
+func (r *personalTokenRepository) CreateWithinLimit(ctx context.Context, token *biz.PersonalToken, limit int) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenRepository.CreateWithinLimit")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": token.UserID,
+		"limit":   limit,
+	})
+
+	created := false
+	run := func(tx *gorm.DB) error {
+		var user biz.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", token.UserID).Take(&user).Error; err != nil {
+			return err
+		}
+		var active int64
+		if err := tx.Model(&biz.PersonalToken{}).Where("user_id = ? AND revoked_at IS NULL", token.UserID).Count(&active).Error; err != nil {
+			return err
+		}
+		if active >= int64(limit) {
+			return nil
+		}
+		if err := tx.Create(token).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	}
+
+	var err error
+	if tx, ok := ctx.Value(contextTxKey{}).(*gorm.DB); ok {
+		err = run(tx)
+	} else {
+		err = r.db.WithContext(ctx).Transaction(run)
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to create personal token within limit for user id: %d, error_reason: %v", token.UserID, err)
+		return false, err
+	}
+	return created, nil
+}
+
+// ListByUserID 按创建时间倒序返回用户未撤销的令牌
+func (r *personalTokenRepository) ListByUserID(ctx context.Context, userID int64) ([]*biz.PersonalToken, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenRepository.ListByUserID")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	var tokens []*biz.PersonalToken
+	err := dbFromContext(ctx, r.db).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC, id DESC").
+		Find(&tokens).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to list personal tokens for user id: %d, error_reason: %v", userID, err)
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// GetByHash 按令牌哈希查询未撤销的令牌，利用唯一索引 uk_token_hash
+func (r *personalTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*biz.PersonalToken, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenRepository.GetByHash")
+	defer span.End()
+
+	var token biz.PersonalToken
+	err := dbFromContext(ctx, r.db).
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Revoke 按用户ID和令牌ID条件撤销令牌，已撤销的令牌不重复更新
+func (r *personalTokenRepository) Revoke(ctx context.Context, userID, tokenID int64, revokedAt time.Time) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenRepository.Revoke")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id":  userID,
+		"token_id": tokenID,
+	})
+
+	result := dbFromContext(ctx, r.db).Model(&biz.PersonalToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to revoke personal token id: %d, error_reason: %v", tokenID, result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Rotate 按用户ID和令牌ID条件替换令牌哈希，单条更新语句保证旧哈希立即失效
+func (r *personalTokenRepository) Rotate(ctx context.Context, userID, tokenID int64, tokenHash string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenRepository.Rotate")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id":  userID,
+		"token_id": tokenID,
+	})
+
+	result := dbFromContext(ctx, r.db).Model(&biz.PersonalToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("token_hash", tokenHash)
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to rotate personal token id: %d, error_reason: %v", tokenID, result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TouchLastUsed 更新令牌的最近使用时间
+func (r *personalTokenRepository) TouchLastUsed(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, "PersonalTokenRepository.TouchLastUsed")
+	defer span.End()
+
+	return dbFromContext(ctx, r.db).Model(&biz.PersonalToken{}).
+		Where("id = ?", tokenID).
+		Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,283 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"user/internal/biz"
+)
+
+// TestPersonalTokenRepository_Create 测试创建令牌时只写入哈希
+func TestPersonalTokenRepository_Create(t *testing.T) {
+	const plaintext = "pat_0123456789abcdef"
+	hash := biz.HashPersonalToken(plaintext)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "创建成功",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `personal_token` \\(`user_id`,`name`,`token_hash`,`scopes`,`last_used_at`,`revoked_at`,`created_at`\\)").
+					WithArgs(1, "ci", hash, "user:read", nil, nil, createdAt).
+					WillReturnResult(sqlmock.NewResult(10, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "数据库错误",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `personal_token`").
+					WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPersonalTokenRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			token := &biz.PersonalToken{UserID: 1, Name: "ci", TokenHash: hash, Scopes: "user:read", CreatedAt: createdAt}
+			err := repo.Create(context.Background(), token)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(10), token.ID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPersonalTokenRepository_CreateWithinLimit 测试锁定用户行后统计未撤销的令牌数，达到上限时不写入
+func TestPersonalTokenRepository_CreateWithinLimit(t *testing.T) {
+	const lockUserQuery = "SELECT `id` FROM `user` WHERE id = \\? AND `user`.`deleted_at` IS NULL LIMIT \\? FOR UPDATE"
+	const countQuery = "SELECT count\\(\\*\\) FROM `personal_token` WHERE user_id = \\? AND revoked_at IS NULL"
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		mockFn      func(sqlmock.Sqlmock)
+		wantCreated bool
+		wantErr     bool
+	}{
+		{
+			name: "未达到上限时写入",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockUserQuery).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(countQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectExec("INSERT INTO `personal_token`").
+					WithArgs(1, "ci", "hash", "user:read", nil, nil, createdAt).
+					WillReturnResult(sqlmock.NewResult(10, 1))
+				mock.ExpectCommit()
+			},
+			wantCreated: true,
+		},
+		{
+			name: "达到上限时不写入",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockUserQuery).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(countQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "用户不存在时回滚",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockUserQuery).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPersonalTokenRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			token := &biz.PersonalToken{UserID: 1, Name: "ci", TokenHash: "hash", Scopes: "user:read", CreatedAt: createdAt}
+			created, err := repo.CreateWithinLimit(context.Background(), token, 3)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCreated, created)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPersonalTokenRepository_ListByUserID 测试只列出未撤销的令牌
+func TestPersonalTokenRepository_ListByUserID(t *testing.T) {
+	db, mock := setupTestDB(t)
+	repo := NewPersonalTokenRepository(db, log.DefaultLogger)
+	columns := []string{"id", "user_id", "name", "token_hash", "scopes", "last_used_at", "revoked_at", "created_at", "updated_at"}
+
+	mock.ExpectQuery("SELECT \\* FROM `personal_token` WHERE user_id = \\? AND revoked_at IS NULL ORDER BY created_at DESC, id DESC").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(11, 1, "deploy", "hash2", "points:read", nil, nil, time.Now(), time.Now()).
+			AddRow(10, 1, "ci", "hash1", "user:read", time.Now(), nil, time.Now(), time.Now()))
+
+	tokens, err := repo.ListByUserID(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, int64(11), tokens[0].ID)
+	assert.Equal(t, []string{"user:read"}, tokens[1].ScopeList())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPersonalTokenRepository_GetByHash 测试按哈希查询未撤销的令牌
+func TestPersonalTokenRepository_GetByHash(t *testing.T) {
+	columns := []string{"id", "user_id", "name", "token_hash", "scopes", "last_used_at", "revoked_at", "created_at", "updated_at"}
+	selectSQL := "SELECT \\* FROM `personal_token` WHERE token_hash = \\? AND revoked_at IS NULL ORDER BY `personal_token`.`id` LIMIT \\?"
+
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "令牌存在",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs("hash1", 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, "ci", "hash1", "user:read", nil, nil, time.Now(), time.Now()))
+			},
+		},
+		{
+			name: "令牌不存在或已撤销",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs("hash1", 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPersonalTokenRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			token, err := repo.GetByHash(context.Background(), "hash1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(10), token.ID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPersonalTokenRepository_Revoke 测试撤销令牌按用户ID条件更新，未匹配任何行时返回 false
+func TestPersonalTokenRepository_Revoke(t *testing.T) {
+	revokedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updateSQL := "UPDATE `personal_token` SET `revoked_at`=\\?,`updated_at`=\\? WHERE id = \\? AND user_id = \\? AND revoked_at IS NULL"
+
+	tests := []struct {
+		name        string
+		mockFn      func(sqlmock.Sqlmock)
+		wantRevoked bool
+		wantErr     bool
+	}{
+		{
+			name: "撤销成功",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(revokedAt, sqlmock.AnyArg(), 10, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantRevoked: true,
+		},
+		{
+			name: "令牌不存在、属于其他用户或已撤销",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(revokedAt, sqlmock.AnyArg(), 10, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "数据库错误",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPersonalTokenRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			revoked, err := repo.Revoke(context.Background(), 1, 10, revokedAt)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantRevoked, revoked)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPersonalTokenRepository_Rotate 测试轮换令牌在一条更新语句中替换哈希
+func TestPersonalTokenRepository_Rotate(t *testing.T) {
+	db, mock := setupTestDB(t)
+	repo := NewPersonalTokenRepository(db, log.DefaultLogger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `personal_token` SET `token_hash`=\\?,`updated_at`=\\? WHERE id = \\? AND user_id = \\? AND revoked_at IS NULL").
+		WithArgs("new-hash", sqlmock.AnyArg(), 10, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rotated, err := repo.Rotate(context.Background(), 1, 10, "new-hash")
+
+	require.NoError(t, err)
+	assert.True(t, rotated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,597 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"user/internal/conf"
+	"user/internal/pkg/tracing"
+)
+
+// pointInsertBatchSize 批量写入时每条 INSERT 语句包含的行数
+const pointInsertBatchSize = 100
+
+// pointVersionMaxAttempts 乐观锁更新点数时的最大尝试次数
+const pointVersionMaxAttempts = 3
+
+// defaultMaxPointTransactions 未配置 data.max_point_transactions 时单次查询返回流水的最大条数
+const defaultMaxPointTransactions = 1000
+
+// userPointRepository 用户点数数据访问实现
+type userPointRepository struct {
+	db     *gorm.DB
+	logger *log.Helper
+}
+
+// NewUserPointRepository 创建用户点数数据访问实例
+func NewUserPointRepository(db *gorm.DB, logger log.Logger) biz.UserPointRepository {
+	return &userPointRepository{db: db, logger: log.NewHelper(logger)}
+}
+
+// AddPointsBatch 批量增加用户点数，利用唯一索引 uk_user_id 实现不存在则创建、存在则累加并将版本号加一
+func (r *userPointRepository) AddPointsBatch(ctx context.Context, points []*biz.UserPoint) error {
+	ctx, span := tracing.StartSpan(ctx, "UserPointRepository.AddPointsBatch")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"count": len(points),
+	})
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	err := dbFromContext(ctx, r.db).
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "current_points"}, Value: gorm.Expr("current_points + VALUES(current_points)")},
+				// 与 UpdateWithVersion 共用乐观锁版本号，避免其读取后被本次累加的余额被覆盖
+				{Column: clause.Column{Name: "version"}, Value: gorm.Expr("version + 1")},
+			},
+		}).
+		CreateInBatches(points, pointInsertBatchSize).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to add points in batch, count: %d, error_reason: %v", len(points), err)
+		return err
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully added points in batch, count: %d", len(points))
+	return nil
+}
+
+// MergeInto 将 fromUserID 的点数余额和累计消耗累加到 toUserID（不存在时创建，存在时版本号加一），并删除 fromUserID 的点数记录
+func (r *userPointRepository) MergeInto(ctx context.Context, fromUserID, toUserID int64) error {
+	ctx, span := tracing.StartSpan(ctx, "UserPointRepository.MergeInto")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"from_user_id": fromUserID,
+		"to_user_id":   toUserID,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	var from biz.UserPoint
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", fromUserID).First(&from).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.logger.WithContext(ctx).Infof("No points to merge for user: %d", fromUserID)
+			return nil
+		}
+		r.logger.WithContext(ctx).Errorf("Failed to get points for user: %d, error_reason: %v", fromUserID, err)
+		return err
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "current_points"}, Value: gorm.Expr("current_points + VALUES(current_points)")},
+			{Column: clause.Column{Name: "total_consumed"}, Value: gorm.Expr("total_consumed + VALUES(total_consumed)")},
+			{Column: clause.Column{Name: "version"}, Value: gorm.Expr("version + 1")},
+		},
+	}).Create(&biz.UserPoint{
+		UserID:        toUserID,
+		CurrentPoints: from.CurrentPoints,
+		TotalConsumed: from.TotalConsumed,
+	}).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to merge points from user: %d into user: %d, error_reason: %v", fromUserID, toUserID, err)
+		return err
+	}
+
+	err = db.Delete(&biz.UserPoint{}, from.ID).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to delete points of merged user: %d, error_reason: %v", fromUserID, err)
+		return err
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully merged points from user: %d into user: %d", fromUserID, toUserID)
+	return nil
+}
+
+// GetOrCreate 获取用户点数记录，不存在时创建余额为0的记录
+// 依赖 user_id 唯一索引保证只创建一条，并发创建导致唯一键冲突时重新读取已存在的记录
+func (r *userPointRepository) GetOrCreate(ctx context.Context, userID int64) (*biz.UserPoint, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserPointRepository.GetOrCreate")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	var point biz.UserPoint
+	err := db.Where("user_id = ?", userID).First(&point).Error
+	if err == nil {
+		return &point, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.logger.WithContext(ctx).Errorf("Failed to get points for user: %d, error_reason: %v", userID, err)
+		return nil, err
+	}
+
+	point = biz.UserPoint{UserID: userID}
+	err = db.Create(&point).Error
+	if err == nil {
+		r.logger.WithContext(ctx).Infof("Created empty point record for user: %d", userID)
+		return &point, nil
+	}
+	if !isDuplicateKeyError(err) {
+		r.logger.WithContext(ctx).Errorf("Failed to create point record for user: %d, error_reason: %v", userID, err)
+		return nil, err
+	}
+
+	// 其他请求已创建该记录；使用锁定读读取最新提交的数据，避免在事务中读到快照
+	r.logger.WithContext(ctx).Infof("Point record for user: %d created concurrently, reloading", userID)
+	point = biz.UserPoint{}
+	err = db.Clauses(clause.Locking{Strength: "SHARE"}).Where("user_id = ?", userID).First(&point).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to reload points for user: %d, error_reason: %v", userID, err)
+		return nil, err
+	}
+	return &point, nil
+}
+
+// UpdateWithVersion 使用 version 列实现乐观锁：读取记录后按原版本号条件更新并将版本号加一，影响行数为0说明已被并发修改，重新读取后重试
+// 已处于事务中时只尝试一次，冲突时直接返回 ErrPointVersionConflict：可重复读事务内重新读取仍得到同一快照，应由调用方开启新事务重试
+func (r *userPointRepository) UpdateWithVersion(ctx context.Context, userID int64, apply func(point *biz.UserPoint) error) (*biz.UserPoint, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserPointRepository.UpdateWithVersion")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	maxAttempts := pointVersionMaxAttempts
+	if _, ok := ctx.Value(contextTxKey{}).(*gorm.DB); ok {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var point biz.UserPoint
+		err := db.Where("user_id = ?", userID).First(&point).Error
+		if err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to get points for user: %d, error_reason: %v", userID, err)
+			return nil, err
+		}
+
+		version := point.Version
+		if err := apply(&point); err != nil {
+			return nil, err
+		}
+
+		result := db.Model(&biz.UserPoint{}).
+			Where("user_id = ? AND version = ?", userID, version).
+			Updates(map[string]interface{}{
+				"current_points": point.CurrentPoints,
+				"total_consumed": point.TotalConsumed,
+				"version":        gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to update points for user: %d, error_reason: %v", userID, result.Error)
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			point.Version = version + 1
+			r.logger.WithContext(ctx).Infof("Successfully updated points for user: %d, version: %d", userID, point.Version)
+			return &point, nil
+		}
+
+		r.logger.WithContext(ctx).Warnf("Point version conflict for user: %d, version: %d, attempt: %d", userID, version, attempt)
+	}
+
+	r.logger.WithContext(ctx).Errorf("Point version conflict retries exhausted for user: %d", userID)
+	return nil, biz.ErrPointVersionConflict
+}
+
+// Consume 以余额充足为条件原子地扣减 current_points 并累加 total_consumed，两列始终在同一条更新语句中变化
+// 更新未命中时重新读取记录，区分记录不存在和余额不足
+func (r *userPointRepository) Consume(ctx context.Context, userID int64, amount uint32) (*biz.UserPoint, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserPointRepository.Consume")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+		"amount":  amount,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	result := db.Model(&biz.UserPoint{}).
+		Where("user_id = ? AND current_points >= ?", userID, amount).
+		Updates(map[string]interface{}{
+			"current_points": gorm.Expr("current_points - ?", amount),
+			"total_consumed": gorm.Expr("total_consumed + ?", amount),
+			"version":        gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to consume points for user: %d, error_reason: %v", userID, result.Error)
+		return nil, result.Error
+	}
+
+	var point biz.UserPoint
+	if err := db.Where("user_id = ?", userID).First(&point).Error; err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get points for user: %d, error_reason: %v", userID, err)
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		r.logger.WithContext(ctx).Warnf("Insufficient points for user: %d, balance: %d, amount: %d", userID, point.CurrentPoints, amount)
+		return nil, biz.ErrInsufficientPoints
+	}
+	span.SetAttributes(
+		attribute.Int64("balance_before", int64(point.CurrentPoints)+int64(amount)),
+		attribute.Int64("balance_after", int64(point.CurrentPoints)),
+	)
+
+	r.logger.WithContext(ctx).Infof("Consumed %d points for user: %d, balance: %d, total consumed: %d", amount, userID, point.CurrentPoints, point.TotalConsumed)
+	return &point, nil
+}
+
+// archiveUserPointSQL 将用户点数记录复制到归档表
+const archiveUserPointSQL = "INSERT INTO `user_point_archive` (`user_id`, `current_points`, `total_consumed`, `created_at`, `archived_at`) " +
+	"SELECT `user_id`, `current_points`, `total_consumed`, `created_at`, NOW() FROM `user_point` WHERE `user_id` = ?"
+
+// ZeroOut 锁定用户点数记录并清零余额，返回清零前的余额，累计消耗不变；需在事务中调用
+func (r *userPointRepository) ZeroOut(ctx context.Context, userID int64) (uint32, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserPointRepository.ZeroOut")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	var point biz.UserPoint
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&point).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.logger.WithContext(ctx).Infof("No points to zero out for user: %d", userID)
+			return 0, nil
+		}
+		r.logger.WithContext(ctx).Errorf("Failed to get points for user: %d, error_reason: %v", userID, err)
+		return 0, err
+	}
+	if point.CurrentPoints == 0 {
+		return 0, nil
+	}
+
+	err = db.Model(&biz.UserPoint{}).Where("id = ?", point.ID).Updates(map[string]interface{}{
+		"current_points": 0,
+		"version":        gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to zero out points for user: %d, error_reason: %v", userID, err)
+		return 0, err
+	}
+
+	r.logger.WithContext(ctx).Infof("Zeroed out %d points for user: %d", point.CurrentPoints, userID)
+	return point.CurrentPoints, nil
+}
+
+// Archive 将用户点数记录复制到 user_point_archive 后删除原记录，需在事务中调用
+func (r *userPointRepository) Archive(ctx context.Context, userID int64) error {
+	ctx, span := tracing.StartSpan(ctx, "UserPointRepository.Archive")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	if err := db.Exec(archiveUserPointSQL, userID).Error; err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to archive points for user: %d, error_reason: %v", userID, err)
+		return err
+	}
+	if err := db.Where("user_id = ?", userID).Delete(&biz.UserPoint{}).Error; err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to delete archived points for user: %d, error_reason: %v", userID, err)
+		return err
+	}
+
+	r.logger.WithContext(ctx).Infof("Archived points for user: %d", userID)
+	return nil
+}
+
+// pointTransactionRepository 点数流水数据访问实现
+type pointTransactionRepository struct {
+	db *gorm.DB
+	// maxResults 单次查询返回流水的最大条数，防止内部调用一次拉取全部流水
+	maxResults int
+	logger     *log.Helper
+}
+
+// NewPointTransactionRepository 创建点数流水数据访问实例
+func NewPointTransactionRepository(db *gorm.DB, c *conf.Data, logger log.Logger) biz.PointTransactionRepository {
+	maxResults := int(c.GetMaxPointTransactions())
+	if maxResults <= 0 {
+		maxResults = defaultMaxPointTransactions
+	}
+	return &pointTransactionRepository{db: db, maxResults: maxResults, logger: log.NewHelper(logger)}
+}
+
+// GetByUserID 按创建时间倒序分页查询用户流水
+func (r *pointTransactionRepository) GetByUserID(ctx context.Context, userID int64, page, pageSize int) ([]*biz.PointTransaction, int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.GetByUserID")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id":   userID,
+		"page":      page,
+		"page_size": pageSize,
+	})
+
+	txns, total, err := r.findPage(ctx, page, pageSize, "user_id = ?", userID)
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to list point transactions for user: %d, error_reason: %v", userID, err)
+		return nil, 0, err
+	}
+
+	return txns, total, nil
+}
+
+// GetByRelatedBookID 按创建时间倒序分页查询关联某绘本的流水（跨用户）
+func (r *pointTransactionRepository) GetByRelatedBookID(ctx context.Context, bookID int64, page, pageSize int) ([]*biz.PointTransaction, int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.GetByRelatedBookID")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"book_id":   bookID,
+		"page":      page,
+		"page_size": pageSize,
+	})
+
+	txns, total, err := r.findPage(ctx, page, pageSize, "related_book_id = ?", bookID)
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to list point transactions for book: %d, error_reason: %v", bookID, err)
+		return nil, 0, err
+	}
+
+	return txns, total, nil
+}
+
+// GetByMetadata 按创建时间倒序分页查询元数据字段 key 等于 value 的流水（跨用户）
+// key 作为 JSON 路径参数绑定，不拼接进 SQL
+func (r *pointTransactionRepository) GetByMetadata(ctx context.Context, key string, value interface{}, page, pageSize int) ([]*biz.PointTransaction, int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.GetByMetadata")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"metadata_key": key,
+		"page":         page,
+		"page_size":    pageSize,
+	})
+
+	txns, total, err := r.findPage(ctx, page, pageSize, datatypes.JSONQuery("metadata").Equals(value, key))
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to list point transactions by metadata %s=%v, error_reason: %v", key, value, err)
+		return nil, 0, err
+	}
+
+	return txns, total, nil
+}
+
+// findPage 按条件统计总数并按创建时间倒序查询指定页，总数为0时不再查询明细
+// pageSize 为0（不分页）或超过 maxResults 时按 maxResults 截断并记录警告
+func (r *pointTransactionRepository) findPage(ctx context.Context, page, pageSize int, query interface{}, args ...interface{}) ([]*biz.PointTransaction, int64, error) {
+	capped := pageSize <= 0 || pageSize > r.maxResults
+	if capped {
+		pageSize = r.maxResults
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	var total int64
+	err := r.db.WithContext(ctx).Model(&biz.PointTransaction{}).Where(query, args...).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	txns := make([]*biz.PointTransaction, 0)
+	if total == 0 {
+		return txns, 0, nil
+	}
+
+	err = r.db.WithContext(ctx).
+		Where(query, args...).
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&txns).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if capped && int64((page-1)*pageSize+len(txns)) < total {
+		r.logger.WithContext(ctx).Warnf("Point transaction query truncated to %d rows, total: %d", r.maxResults, total)
+	}
+	return txns, total, nil
+}
+
+// CreateBatch 在事务中分批写入流水，任意一批失败则整体回滚
+func (r *pointTransactionRepository) CreateBatch(ctx context.Context, txns []*biz.PointTransaction) error {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.CreateBatch")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"count": len(txns),
+	})
+
+	if len(txns) == 0 {
+		return nil
+	}
+
+	// CreateInBatches 在多批次时自行开启事务（已处于外部事务中时使用 SAVEPOINT），任意一批失败整体回滚
+	err := dbFromContext(ctx, r.db).CreateInBatches(txns, pointInsertBatchSize).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to create point transactions in batch, count: %d, error_reason: %v", len(txns), err)
+		return err
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully created point transactions in batch, count: %d", len(txns))
+	return nil
+}
+
+// ReassignUser 将 fromUserID 的所有流水改为归属 toUserID，返回受影响的条数
+func (r *pointTransactionRepository) ReassignUser(ctx context.Context, fromUserID, toUserID int64) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.ReassignUser")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"from_user_id": fromUserID,
+		"to_user_id":   toUserID,
+	})
+
+	result := dbFromContext(ctx, r.db).Model(&biz.PointTransaction{}).
+		Where("user_id = ?", fromUserID).
+		Update("user_id", toUserID)
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to reassign point transactions from user: %d to user: %d, error_reason: %v", fromUserID, toUserID, result.Error)
+		return 0, result.Error
+	}
+
+	r.logger.WithContext(ctx).Infof("Reassigned %d point transactions from user: %d to user: %d", result.RowsAffected, fromUserID, toUserID)
+	return result.RowsAffected, nil
+}
+
+// archivePointTransactionsSQL 将用户的所有流水复制到归档表，保留原流水ID
+const archivePointTransactionsSQL = "INSERT INTO `point_transaction_archive` (`id`, `user_id`, `type`, `amount`, `related_book_id`, `description`, `metadata`, `created_at`, `archived_at`) " +
+	"SELECT `id`, `user_id`, `type`, `amount`, `related_book_id`, `description`, `metadata`, `created_at`, NOW() FROM `point_transaction` WHERE `user_id` = ?"
+
+// Archive 将用户的所有流水复制到 point_transaction_archive 后删除原记录，返回归档的条数；需在事务中调用
+func (r *pointTransactionRepository) Archive(ctx context.Context, userID int64) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.Archive")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	if err := db.Exec(archivePointTransactionsSQL, userID).Error; err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to archive point transactions for user: %d, error_reason: %v", userID, err)
+		return 0, err
+	}
+	result := db.Where("user_id = ?", userID).Delete(&biz.PointTransaction{})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to delete archived point transactions for user: %d, error_reason: %v", userID, result.Error)
+		return 0, result.Error
+	}
+
+	r.logger.WithContext(ctx).Infof("Archived %d point transactions for user: %d", result.RowsAffected, userID)
+	return result.RowsAffected, nil
+}
+
+// archivePointTransactionsByIDSQL 将指定ID的流水复制到归档表，保留原流水ID
+const archivePointTransactionsByIDSQL = "INSERT INTO `point_transaction_archive` (`id`, `user_id`, `type`, `amount`, `related_book_id`, `description`, `metadata`, `created_at`, `archived_at`) " +
+	"SELECT `id`, `user_id`, `type`, `amount`, `related_book_id`, `description`, `metadata`, `created_at`, NOW() FROM `point_transaction` WHERE `id` IN ?"
+
+// ArchiveBefore 按ID升序锁定最多 limit 条创建时间早于 before 的流水，复制到 point_transaction_archive 后删除原记录，
+// 返回归档的条数；需在事务中调用
+func (r *pointTransactionRepository) ArchiveBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.ArchiveBefore")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"before": before,
+		"limit":  limit,
+	})
+
+	db := dbFromContext(ctx, r.db)
+
+	var ids []int64
+	err := db.Model(&biz.PointTransaction{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("created_at < ?", before).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to select point transactions created before: %v, error_reason: %v", before, err)
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := db.Exec(archivePointTransactionsByIDSQL, ids).Error; err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to archive %d point transactions created before: %v, error_reason: %v", len(ids), before, err)
+		return 0, err
+	}
+	result := db.Where("id IN ?", ids).Delete(&biz.PointTransaction{})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to delete %d archived point transactions, error_reason: %v", len(ids), result.Error)
+		return 0, result.Error
+	}
+
+	r.logger.WithContext(ctx).Infof("Archived %d point transactions created before: %v", result.RowsAffected, before)
+	return result.RowsAffected, nil
+}
+
+// dailyFlowRow 每日点数净变化的查询结果
+type dailyFlowRow struct {
+	Day       time.Time
+	NetChange int64
+}
+
+// DailyFlow 按天汇总用户在 [from, to) 内的点数净变化，只返回有流水的日期，按日期升序
+func (r *pointTransactionRepository) DailyFlow(ctx context.Context, userID int64, from, to time.Time) ([]biz.DailyFlow, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointTransactionRepository.DailyFlow")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": userID,
+		"from":    from,
+		"to":      to,
+	})
+
+	// amount 为无符号列，取负前需要转换为有符号数
+	var rows []dailyFlowRow
+	err := r.db.WithContext(ctx).Model(&biz.PointTransaction{}).
+		Select("DATE(created_at) AS day, SUM(CASE WHEN type = ? THEN CAST(amount AS SIGNED) ELSE -CAST(amount AS SIGNED) END) AS net_change", biz.TransactionTypeRecharge).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Group("DATE(created_at)").
+		Order("day").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to query daily point flow for user: %d, error_reason: %v", userID, err)
+		return nil, err
+	}
+
+	flows := make([]biz.DailyFlow, 0, len(rows))
+	for _, row := range rows {
+		flows = append(flows, biz.DailyFlow{Date: row.Day, NetChange: row.NetChange})
+	}
+	return flows, nil
+}
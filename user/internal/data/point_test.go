@@ -0,0 +1,1075 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kratos/kratos/v2/log"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"user/internal/biz"
+	"user/internal/conf"
+)
+
+// TestPointTransactionRepository_GetByUserID 测试分页查询用户点数流水
+func TestPointTransactionRepository_GetByUserID(t *testing.T) {
+	columns := []string{"id", "user_id", "type", "amount", "related_book_id", "description", "created_at", "updated_at"}
+
+	tests := []struct {
+		name      string
+		userID    int64
+		page      int
+		pageSize  int
+		mockFn    func(sqlmock.Sqlmock)
+		wantCount int
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name:     "成功查询第二页",
+			userID:   1,
+			page:     2,
+			pageSize: 2,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE user_id = \\?").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(3))
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE user_id = \\? ORDER BY created_at DESC, id DESC LIMIT \\? OFFSET \\?").
+					WithArgs(1, 2, 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "RECHARGE", 100, nil, "充值", time.Now(), time.Now()))
+			},
+			wantCount: 1,
+			wantTotal: 3,
+		},
+		{
+			name:     "没有流水时不查询明细",
+			userID:   2,
+			page:     1,
+			pageSize: 20,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE user_id = \\?").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+			},
+			wantCount: 0,
+			wantTotal: 0,
+		},
+		{
+			name:     "数据库错误",
+			userID:   3,
+			page:     1,
+			pageSize: 20,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE user_id = \\?").
+					WithArgs(3).
+					WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			txns, total, err := repo.GetByUserID(context.Background(), tt.userID, tt.page, tt.pageSize)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, txns, tt.wantCount)
+				assert.Equal(t, tt.wantTotal, total)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPointTransactionRepository_MaxResults 测试每页条数过大或不分页时按配置的上限截断
+func TestPointTransactionRepository_MaxResults(t *testing.T) {
+	columns := []string{"id", "user_id", "type", "amount", "related_book_id", "description", "created_at", "updated_at"}
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(columns).
+			AddRow(2, 1, "RECHARGE", 100, nil, "充值", time.Now(), time.Now()).
+			AddRow(1, 1, "RECHARGE", 100, nil, "充值", time.Now(), time.Now())
+	}
+
+	tests := []struct {
+		name      string
+		page      int
+		pageSize  int
+		mockFn    func(sqlmock.Sqlmock)
+		wantCount int
+	}{
+		{
+			name:     "超大的每页条数按上限截断",
+			page:     1,
+			pageSize: 1 << 30,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE user_id = \\? ORDER BY created_at DESC, id DESC LIMIT \\?$").
+					WithArgs(1, 2).
+					WillReturnRows(rows())
+			},
+			wantCount: 2,
+		},
+		{
+			name:     "不分页时按上限截断",
+			page:     0,
+			pageSize: 0,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE user_id = \\? ORDER BY created_at DESC, id DESC LIMIT \\?$").
+					WithArgs(1, 2).
+					WillReturnRows(rows())
+			},
+			wantCount: 2,
+		},
+		{
+			name:     "截断后按上限计算偏移",
+			page:     2,
+			pageSize: 1 << 30,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE user_id = \\? ORDER BY created_at DESC, id DESC LIMIT \\? OFFSET \\?").
+					WithArgs(1, 2, 2).
+					WillReturnRows(rows())
+			},
+			wantCount: 2,
+		},
+		{
+			name:     "未超过上限时不截断",
+			page:     1,
+			pageSize: 1,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE user_id = \\? ORDER BY created_at DESC, id DESC LIMIT \\?$").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, "RECHARGE", 100, nil, "充值", time.Now(), time.Now()))
+			},
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPointTransactionRepository(db, &conf.Data{MaxPointTransactions: 2}, log.DefaultLogger)
+			mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE user_id = \\?").
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1000000))
+			tt.mockFn(mock)
+
+			txns, total, err := repo.GetByUserID(context.Background(), 1, tt.page, tt.pageSize)
+
+			assert.NoError(t, err)
+			assert.Len(t, txns, tt.wantCount)
+			// 总数不受截断影响，调用方可据此判断结果是否完整
+			assert.Equal(t, int64(1000000), total)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPointTransactionRepository_CreateBatch 测试批量写入点数流水
+func TestPointTransactionRepository_CreateBatch(t *testing.T) {
+	// 生成超过一个批次的流水，触发多条 INSERT
+	newTxns := func(n int) []*biz.PointTransaction {
+		txns := make([]*biz.PointTransaction, n)
+		for i := range txns {
+			txns[i] = &biz.PointTransaction{
+				UserID:      int64(i + 1),
+				Type:        biz.TransactionTypeRecharge,
+				Amount:      100,
+				Description: "活动赠送",
+			}
+		}
+		return txns
+	}
+
+	tests := []struct {
+		name    string
+		txns    []*biz.PointTransaction
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "单批多行写入",
+			txns: newTxns(3),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `point_transaction` .* VALUES \\(.*\\),\\(.*\\),\\(.*\\)$").
+					WillReturnResult(sqlmock.NewResult(1, 3))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "超过批次大小时分多条语句写入",
+			txns: newTxns(pointInsertBatchSize + 50),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `point_transaction`").
+					WillReturnResult(sqlmock.NewResult(1, pointInsertBatchSize))
+				mock.ExpectExec("INSERT INTO `point_transaction`").
+					WillReturnResult(sqlmock.NewResult(pointInsertBatchSize+1, 50))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "中间批次失败时回滚",
+			txns: newTxns(pointInsertBatchSize + 50),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `point_transaction`").
+					WillReturnResult(sqlmock.NewResult(1, pointInsertBatchSize))
+				mock.ExpectExec("INSERT INTO `point_transaction`").
+					WillReturnError(fmt.Errorf("deadlock found"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name:    "空列表不访问数据库",
+			txns:    nil,
+			mockFn:  func(mock sqlmock.Sqlmock) {},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := repo.CreateBatch(context.Background(), tt.txns)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserPointRepository_AddPointsBatch 测试批量增加用户点数
+func TestUserPointRepository_AddPointsBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "不存在则创建存在则累加并增加版本号",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point` .* ON DUPLICATE KEY UPDATE `current_points`=current_points \\+ VALUES\\(current_points\\),`version`=version \\+ 1$").
+					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "数据库错误",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point`").
+					WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserPointRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := repo.AddPointsBatch(context.Background(), []*biz.UserPoint{
+				{UserID: 1, CurrentPoints: 100},
+				{UserID: 2, CurrentPoints: 100},
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserPointRepository_GetOrCreate 测试获取或创建用户点数记录
+func TestUserPointRepository_GetOrCreate(t *testing.T) {
+	columns := []string{"id", "user_id", "current_points", "total_consumed", "created_at", "updated_at"}
+	selectSQL := "SELECT \\* FROM `user_point` WHERE user_id = \\? ORDER BY `user_point`.`id` LIMIT \\?"
+
+	tests := []struct {
+		name       string
+		mockFn     func(sqlmock.Sqlmock)
+		wantPoints uint32
+		wantErr    bool
+	}{
+		{
+			name: "记录已存在直接返回",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 300, 20, time.Now(), time.Now()))
+			},
+			wantPoints: 300,
+		},
+		{
+			name: "记录不存在时创建",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point`").
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectCommit()
+			},
+			wantPoints: 0,
+		},
+		{
+			name: "并发创建时唯一键冲突则重新读取",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point`").
+					WillReturnError(&mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'uk_user_id'"})
+				mock.ExpectRollback()
+				mock.ExpectQuery(selectSQL+" FOR SHARE").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(12, 1, 50, 0, time.Now(), time.Now()))
+			},
+			wantPoints: 50,
+		},
+		{
+			name: "查询失败",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "创建失败且非唯一键冲突",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point`").
+					WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserPointRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			point, err := repo.GetOrCreate(context.Background(), 1)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), point.UserID)
+				assert.Equal(t, tt.wantPoints, point.CurrentPoints)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserPointRepository_UpdateWithVersion 测试按版本号条件更新点数，版本冲突时重新读取并重试
+func TestUserPointRepository_UpdateWithVersion(t *testing.T) {
+	columns := []string{"id", "user_id", "current_points", "total_consumed", "version", "created_at", "updated_at"}
+	selectSQL := "SELECT \\* FROM `user_point` WHERE user_id = \\? ORDER BY `user_point`.`id` LIMIT \\?"
+	updateSQL := "UPDATE `user_point` SET `current_points`=\\?,`total_consumed`=\\?,`version`=version \\+ 1,`updated_at`=\\? WHERE user_id = \\? AND version = \\?"
+
+	// consume 消耗30点
+	consume := func(point *biz.UserPoint) error {
+		if point.CurrentPoints < 30 {
+			return fmt.Errorf("insufficient points")
+		}
+		point.CurrentPoints -= 30
+		point.TotalConsumed += 30
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		mockFn      func(sqlmock.Sqlmock)
+		wantPoints  uint32
+		wantVersion uint32
+		wantErr     error
+		errSubstr   string
+		// inTx 在事务中调用
+		inTx bool
+	}{
+		{
+			name: "版本号一致时更新成功",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 100, 0, 3, time.Now(), time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(70, 30, sqlmock.AnyArg(), 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantPoints:  70,
+			wantVersion: 4,
+		},
+		{
+			name: "版本冲突时重新读取后重试",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 100, 0, 3, time.Now(), time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(70, 30, sqlmock.AnyArg(), 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				// 并发请求已先消耗了20点
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 80, 20, 4, time.Now(), time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(50, 50, sqlmock.AnyArg(), 1, 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantPoints:  50,
+			wantVersion: 5,
+		},
+		{
+			name: "重试耗尽返回版本冲突",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				for version := 3; version < 3+pointVersionMaxAttempts; version++ {
+					mock.ExpectQuery(selectSQL).
+						WithArgs(1, 1).
+						WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 100, 0, version, time.Now(), time.Now()))
+					mock.ExpectBegin()
+					mock.ExpectExec(updateSQL).
+						WithArgs(70, 30, sqlmock.AnyArg(), 1, version).
+						WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectCommit()
+				}
+			},
+			wantErr: biz.ErrPointVersionConflict,
+		},
+		{
+			name: "事务中版本冲突时不重试",
+			inTx: true,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 100, 0, 3, time.Now(), time.Now()))
+				mock.ExpectExec(updateSQL).
+					WithArgs(70, 30, sqlmock.AnyArg(), 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: biz.ErrPointVersionConflict,
+		},
+		{
+			name: "余额计算失败时不更新",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 10, 0, 3, time.Now(), time.Now()))
+			},
+			errSubstr: "insufficient points",
+		},
+		{
+			name: "记录不存在",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserPointRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			var point *biz.UserPoint
+			var err error
+			if tt.inTx {
+				err = NewTransaction(db).InTx(context.Background(), func(ctx context.Context) error {
+					point, err = repo.UpdateWithVersion(ctx, 1, consume)
+					return err
+				})
+			} else {
+				point, err = repo.UpdateWithVersion(context.Background(), 1, consume)
+			}
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.errSubstr != "":
+				assert.ErrorContains(t, err, tt.errSubstr)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantPoints, point.CurrentPoints)
+				assert.Equal(t, tt.wantVersion, point.Version)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserPointRepository_Consume 测试消耗点数时余额扣减和累计消耗在同一条更新语句中变化
+func TestUserPointRepository_Consume(t *testing.T) {
+	columns := []string{"id", "user_id", "current_points", "total_consumed", "version", "created_at", "updated_at"}
+	updateSQL := "UPDATE `user_point` SET `current_points`=current_points - \\?,`total_consumed`=total_consumed \\+ \\?,`version`=version \\+ 1,`updated_at`=\\? WHERE user_id = \\? AND current_points >= \\?"
+	selectSQL := "SELECT \\* FROM `user_point` WHERE user_id = \\? ORDER BY `user_point`.`id` LIMIT \\?"
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		mockFn       func(sqlmock.Sqlmock)
+		wantPoints   uint32
+		wantConsumed uint32
+		wantErr      error
+	}{
+		{
+			name: "余额充足时同时更新余额和累计消耗",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(30, 30, sqlmock.AnyArg(), 1, 30).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 70, 50, 4, time.Now(), time.Now()))
+			},
+			wantPoints:   70,
+			wantConsumed: 50,
+		},
+		{
+			name: "余额不足时不修改",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(30, 30, sqlmock.AnyArg(), 1, 30).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 1, 10, 20, 3, time.Now(), time.Now()))
+			},
+			wantErr: biz.ErrInsufficientPoints,
+		},
+		{
+			name: "记录不存在",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(30, 30, sqlmock.AnyArg(), 1, 30).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name: "更新失败",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateSQL).
+					WithArgs(30, 30, sqlmock.AnyArg(), 1, 30).
+					WillReturnError(dbErr)
+				mock.ExpectRollback()
+			},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserPointRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			point, err := repo.Consume(context.Background(), 1, 30)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, point)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantPoints, point.CurrentPoints)
+				assert.Equal(t, tt.wantConsumed, point.TotalConsumed)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPointTransactionRepository_GetByRelatedBookID 测试按绘本分页查询点数流水
+func TestPointTransactionRepository_GetByRelatedBookID(t *testing.T) {
+	columns := []string{"id", "user_id", "type", "amount", "related_book_id", "description", "created_at", "updated_at"}
+
+	tests := []struct {
+		name      string
+		bookID    int64
+		page      int
+		pageSize  int
+		mockFn    func(sqlmock.Sqlmock)
+		wantCount int
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name:     "按绘本过滤并分页",
+			bookID:   42,
+			page:     3,
+			pageSize: 10,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE related_book_id = \\?").
+					WithArgs(42).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(22))
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE related_book_id = \\? ORDER BY created_at DESC, id DESC LIMIT \\? OFFSET \\?").
+					WithArgs(42, 10, 20).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(21, 1, "CONSUME", 5, 42, "生成绘本", time.Now(), time.Now()).
+						AddRow(22, 2, "CONSUME", 5, 42, "生成绘本", time.Now(), time.Now()))
+			},
+			wantCount: 2,
+			wantTotal: 22,
+		},
+		{
+			name:     "绘本没有流水",
+			bookID:   43,
+			page:     1,
+			pageSize: 10,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE related_book_id = \\?").
+					WithArgs(43).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+			},
+			wantCount: 0,
+			wantTotal: 0,
+		},
+		{
+			name:     "查询明细失败",
+			bookID:   44,
+			page:     1,
+			pageSize: 10,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE related_book_id = \\?").
+					WithArgs(44).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE related_book_id = \\?").
+					WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			txns, total, err := repo.GetByRelatedBookID(context.Background(), tt.bookID, tt.page, tt.pageSize)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, txns, tt.wantCount)
+				assert.Equal(t, tt.wantTotal, total)
+				for _, txn := range txns {
+					assert.Equal(t, tt.bookID, *txn.RelatedBookID)
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPointTransactionRepository_CreateBatch_Metadata 测试写入带结构化元数据的流水
+func TestPointTransactionRepository_CreateBatch_Metadata(t *testing.T) {
+	db, mock := setupTestDB(t)
+	repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+
+	metadata := datatypes.JSON(`{"source":"promo","promo_id":"spring-2024","operator_id":7}`)
+	// 有元数据时以 JSON 写入，为空时写入 NULL
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `point_transaction` \\(`user_id`,`type`,`amount`,`related_book_id`,`description`,`metadata`\\) VALUES \\(\\?,\\?,\\?,\\?,\\?,CAST\\(\\? AS JSON\\)\\),\\(\\?,\\?,\\?,\\?,\\?,NULL\\)").
+		WithArgs(1, "RECHARGE", 100, nil, "春季活动赠送", string(metadata), 2, "RECHARGE", 50, nil, "充值").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err := repo.CreateBatch(context.Background(), []*biz.PointTransaction{
+		{UserID: 1, Type: "RECHARGE", Amount: 100, Description: "春季活动赠送", Metadata: metadata},
+		{UserID: 2, Type: "RECHARGE", Amount: 50, Description: "充值"},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPointTransactionRepository_GetByMetadata 测试按元数据字段分页查询流水
+func TestPointTransactionRepository_GetByMetadata(t *testing.T) {
+	columns := []string{"id", "user_id", "type", "amount", "related_book_id", "description", "metadata", "created_at", "updated_at"}
+
+	tests := []struct {
+		name      string
+		key       string
+		value     interface{}
+		mockFn    func(sqlmock.Sqlmock)
+		wantCount int
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name:  "按活动ID过滤并分页",
+			key:   biz.TransactionMetadataPromoID,
+			value: "spring-2024",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE JSON_EXTRACT\\(`metadata`,\\?\\) = \\?").
+					WithArgs("$.promo_id", "spring-2024").
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(12))
+				mock.ExpectQuery("SELECT \\* FROM `point_transaction` WHERE JSON_EXTRACT\\(`metadata`,\\?\\) = \\? ORDER BY created_at DESC, id DESC LIMIT \\? OFFSET \\?").
+					WithArgs("$.promo_id", "spring-2024", 10, 10).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(11, 1, "RECHARGE", 100, nil, "春季活动赠送", `{"promo_id":"spring-2024"}`, time.Now(), time.Now()).
+						AddRow(12, 2, "RECHARGE", 100, nil, "春季活动赠送", `{"promo_id":"spring-2024"}`, time.Now(), time.Now()))
+			},
+			wantCount: 2,
+			wantTotal: 12,
+		},
+		{
+			name:  "没有匹配的流水",
+			key:   biz.TransactionMetadataOperatorID,
+			value: 7,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE JSON_EXTRACT\\(`metadata`,\\?\\) = \\?").
+					WithArgs("$.operator_id", 7).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+			},
+			wantCount: 0,
+			wantTotal: 0,
+		},
+		{
+			name:  "数据库错误",
+			key:   biz.TransactionMetadataSource,
+			value: "promo",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction` WHERE JSON_EXTRACT\\(`metadata`,\\?\\) = \\?").
+					WithArgs("$.source", "promo").
+					WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			page := 2
+			txns, total, err := repo.GetByMetadata(context.Background(), tt.key, tt.value, page, 10)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, txns, tt.wantCount)
+				assert.Equal(t, tt.wantTotal, total)
+				for _, txn := range txns {
+					assert.JSONEq(t, `{"promo_id":"spring-2024"}`, string(txn.Metadata))
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPointTransactionRepository_DailyFlow 测试按天分组汇总点数净变化
+func TestPointTransactionRepository_DailyFlow(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	query := "SELECT DATE\\(created_at\\) AS day, SUM\\(CASE WHEN type = \\? THEN CAST\\(amount AS SIGNED\\) ELSE -CAST\\(amount AS SIGNED\\) END\\) AS net_change FROM `point_transaction` " +
+		"WHERE user_id = \\? AND created_at >= \\? AND created_at < \\? GROUP BY DATE\\(created_at\\) ORDER BY day"
+
+	tests := []struct {
+		name     string
+		mockFn   func(sqlmock.Sqlmock)
+		wantFlow []biz.DailyFlow
+		wantErr  bool
+	}{
+		{
+			name: "按天汇总",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("RECHARGE", 1, from, to).
+					WillReturnRows(sqlmock.NewRows([]string{"day", "net_change"}).
+						AddRow(from, 100).
+						AddRow(from.AddDate(0, 0, 3), -25))
+			},
+			wantFlow: []biz.DailyFlow{
+				{Date: from, NetChange: 100},
+				{Date: from.AddDate(0, 0, 3), NetChange: -25},
+			},
+		},
+		{
+			name: "没有流水",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("RECHARGE", 1, from, to).
+					WillReturnRows(sqlmock.NewRows([]string{"day", "net_change"}))
+			},
+			wantFlow: []biz.DailyFlow{},
+		},
+		{
+			name: "查询失败",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			flows, err := repo.DailyFlow(context.Background(), 1, from, to)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantFlow, flows)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestTransaction_InTxWithRetry 测试充值事务遇到死锁时重试
+func TestTransaction_InTxWithRetry(t *testing.T) {
+	deadlock := &mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}
+	lockWaitTimeout := &mysqldriver.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}
+
+	tests := []struct {
+		name          string
+		mockFn        func(sqlmock.Sqlmock)
+		wantErr       bool
+		wantExhausted bool
+	}{
+		{
+			name: "死锁后重试成功",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point`").WillReturnError(deadlock)
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point`").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `point_transaction`").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "重试次数用尽",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				for i := 0; i < txMaxAttempts; i++ {
+					mock.ExpectBegin()
+					mock.ExpectExec("INSERT INTO `user_point`").WillReturnResult(sqlmock.NewResult(1, 1))
+					mock.ExpectExec("INSERT INTO `point_transaction`").WillReturnError(lockWaitTimeout)
+					mock.ExpectRollback()
+				}
+			},
+			wantErr:       true,
+			wantExhausted: true,
+		},
+		{
+			name: "非锁冲突错误不重试",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `user_point`").WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			pointRepo := NewUserPointRepository(db, log.DefaultLogger)
+			txnRepo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			attempts := 0
+			err := NewTransaction(db).InTxWithRetry(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if err := pointRepo.AddPointsBatch(ctx, []*biz.UserPoint{{UserID: 1, CurrentPoints: 100}}); err != nil {
+					return err
+				}
+				return txnRepo.CreateBatch(ctx, []*biz.PointTransaction{{UserID: 1, Type: biz.TransactionTypeRecharge, Amount: 100}})
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, attempts, "死锁后应重试一次")
+			}
+			if tt.wantExhausted {
+				assert.ErrorIs(t, err, biz.ErrTxRetriesExhausted)
+				assert.Equal(t, txMaxAttempts, attempts)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPointTransactionRepository_ArchiveBefore 测试按创建时间分批锁定流水，在同一事务中复制到归档表后删除
+func TestPointTransactionRepository_ArchiveBefore(t *testing.T) {
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	selectSQL := "SELECT `id` FROM `point_transaction` WHERE created_at < \\? ORDER BY id LIMIT \\? FOR UPDATE"
+	insertSQL := "INSERT INTO `point_transaction_archive` \\(`id`, `user_id`, `type`, `amount`, `related_book_id`, `description`, `metadata`, `created_at`, `archived_at`\\) " +
+		"SELECT .* FROM `point_transaction` WHERE `id` IN \\(\\?,\\?\\)"
+	deleteSQL := "DELETE FROM `point_transaction` WHERE id IN \\(\\?,\\?\\)"
+
+	tests := []struct {
+		name         string
+		mockFn       func(sqlmock.Sqlmock)
+		wantArchived int64
+		wantErr      bool
+	}{
+		{
+			name: "移动一批流水",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(before, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+				mock.ExpectExec(insertSQL).
+					WithArgs(int64(10), int64(11)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(deleteSQL).
+					WithArgs(int64(10), int64(11)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			wantArchived: 2,
+		},
+		{
+			name: "没有超过保留期的流水",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(before, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "复制失败回滚事务",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(before, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+				mock.ExpectExec(insertSQL).
+					WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "删除失败回滚事务",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectSQL).
+					WithArgs(before, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+				mock.ExpectExec(insertSQL).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(deleteSQL).
+					WillReturnError(fmt.Errorf("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			var archived int64
+			err := NewTransaction(db).InTx(context.Background(), func(ctx context.Context) error {
+				var err error
+				archived, err = repo.ArchiveBefore(ctx, before, 2)
+				return err
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantArchived, archived)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
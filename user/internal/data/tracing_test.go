@@ -0,0 +1,70 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"user/internal/conf"
+	"user/internal/pkg/tracing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestInstrumentDB_ChildSpan 测试仓库调用会在业务 span 下产生携带 SQL 语句的数据库子 span
+func TestInstrumentDB_ChildSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	db, mock := setupTestDB(t)
+	require.NoError(t, instrumentDB(db, "user_service"))
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `point_transaction`").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	repo := NewPointTransactionRepository(db, &conf.Data{}, log.DefaultLogger)
+
+	ctx, span := tracing.StartSpan(context.Background(), "PointUsecase.ListTransactions")
+	_, _, err := repo.GetByUserID(ctx, 1, 1, 10)
+	span.End()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	spans := recorder.Ended()
+	byID := make(map[string]sdktrace.ReadOnlySpan, len(spans))
+	for _, s := range spans {
+		byID[s.SpanContext().SpanID().String()] = s
+	}
+
+	var dbSpan sdktrace.ReadOnlySpan
+	for _, s := range spans {
+		for _, kv := range s.Attributes() {
+			if kv.Key == attribute.Key("db.statement") {
+				dbSpan = s
+			}
+		}
+	}
+	require.NotNil(t, dbSpan, "应产生记录 SQL 语句的数据库 span")
+	assert.Equal(t, span.SpanContext().TraceID(), dbSpan.SpanContext().TraceID())
+
+	// 数据库 span 的父级是仓库 span，仓库 span 的父级是业务 span
+	repoSpan, ok := byID[dbSpan.Parent().SpanID().String()]
+	require.True(t, ok)
+	assert.Equal(t, "PointTransactionRepository.GetByUserID", repoSpan.Name())
+	assert.Equal(t, span.SpanContext().SpanID(), repoSpan.Parent().SpanID())
+
+	for _, kv := range dbSpan.Attributes() {
+		if kv.Key == attribute.Key("db.statement") {
+			assert.Contains(t, kv.Value.AsString(), "point_transaction")
+		}
+	}
+}
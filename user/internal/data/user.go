@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"time"
 	"user/internal/biz"
 
 	"github.com/go-kratos/kratos/v2/log"
@@ -9,12 +10,16 @@ import (
 	"user/internal/pkg/tracing"
 )
 
+// userPublicColumns 用户表的非敏感字段，不包含 password_hash
+var userPublicColumns = []string{"id", "email", "nickname", "avatar_url", "is_premium", "created_at", "updated_at"}
+
 // userRepository 用户数据访问实现
 type userRepository struct {
 	db     *gorm.DB
 	logger *log.Helper
 }
 
+// Update 按请求中非 nil 的字段部分更新用户资料，在事务上下文中调用时加入该事务
 func (r *userRepository) Update(ctx context.Context, id int64, req *biz.UpdateUserRequest) error {
 	ctx, span := tracing.StartSpan(ctx, "UserRepository.Update")
 	defer span.End()
@@ -27,29 +32,117 @@ func (r *userRepository) Update(ctx context.Context, id int64, req *biz.UpdateUs
 
 	r.logger.WithContext(ctx).Infof("Updating user with id: %d", id)
 
-	updates := make(map[string]interface{})
+	updates := userUpdates(req)
+	if len(updates) == 0 {
+		r.logger.WithContext(ctx).Infof("No fields to update for user id: %d", id)
+		return nil
+	}
+
+	db := dbFromContext(ctx, r.db)
+	result := db.Model(&biz.User{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to update user with id: %d, error_reason: %v", id, result.Error)
+		return result.Error
+	}
+	if err := ensureUserUpdated(db, id, result.RowsAffected); err != nil {
+		r.logger.WithContext(ctx).Warnf("Failed to update user with id: %d, error_reason: %v", id, err)
+		return err
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully updated user with id: %d", id)
+	return nil
+}
 
+// UpdateAndGet 部分更新用户资料并在同一事务中读回更新后的非敏感字段
+// MySQL 不支持 RETURNING，因此在事务内重新查询一次，避免读到其他写入造成的不一致
+func (r *userRepository) UpdateAndGet(ctx context.Context, id int64, req *biz.UpdateUserRequest) (*biz.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.UpdateAndGet")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id":        id,
+		"has_nickname":   req.Nickname != nil,
+		"has_avatar_url": req.AvatarURL != nil,
+	})
+
+	r.logger.WithContext(ctx).Infof("Updating and getting user with id: %d", id)
+
+	var u biz.User
+	run := func(tx *gorm.DB) error {
+		if updates := userUpdates(req); len(updates) > 0 {
+			if err := tx.Model(&biz.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Select(userPublicColumns).Where("id = ?", id).First(&u).Error
+	}
+
+	// 已处于外层事务时直接加入，否则单独开启事务
+	var err error
+	if tx, ok := ctx.Value(contextTxKey{}).(*gorm.DB); ok {
+		err = run(tx)
+	} else {
+		err = r.db.WithContext(ctx).Transaction(run)
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to update and get user with id: %d, error_reason: %v", id, err)
+		return nil, err
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully updated and got user with id: %d", id)
+	return &u, nil
+}
+
+// userUpdates 将更新请求中非 nil 的字段转换为列名到值的映射
+func userUpdates(req *biz.UpdateUserRequest) map[string]interface{} {
+	updates := make(map[string]interface{})
 	if req.Nickname != nil {
 		updates["nickname"] = *req.Nickname
 	}
-
 	if req.AvatarURL != nil {
 		updates["avatar_url"] = *req.AvatarURL
 	}
+	return updates
+}
 
-	if len(updates) == 0 {
-		r.logger.WithContext(ctx).Infof("No fields to update for user id: %d", id)
-		return nil
-	}
+// UpdatePasswordHash 更新用户的密码哈希
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, id int64, passwordHash string) error {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.UpdatePasswordHash")
+	defer span.End()
 
-	err := r.db.WithContext(ctx).Model(&biz.User{}).Where("id = ?", id).Updates(updates).Error
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": id,
+	})
 
-	if err != nil {
-		r.logger.WithContext(ctx).Errorf("Failed to update user with id: %d, error_reason: %v", id, err)
+	db := dbFromContext(ctx, r.db)
+	result := db.Model(&biz.User{}).Where("id = ?", id).Update("password_hash", passwordHash)
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to update password hash for user id: %d, error_reason: %v", id, result.Error)
+		return result.Error
+	}
+	if err := ensureUserUpdated(db, id, result.RowsAffected); err != nil {
+		r.logger.WithContext(ctx).Warnf("Failed to update password hash for user id: %d, error_reason: %v", id, err)
 		return err
 	}
 
-	r.logger.WithContext(ctx).Infof("Successfully updated user with id: %d", id)
+	r.logger.WithContext(ctx).Infof("Successfully updated password hash for user id: %d", id)
+	return nil
+}
+
+// ensureUserUpdated 检查按用户ID更新的影响行数，用户不存在（或已软删除）时返回 gorm.ErrRecordNotFound
+// GORM 在未匹配任何行时不返回错误；MySQL 的影响行数只统计值实际变化的行，
+// 同一秒内写入相同的值时也为0，因此影响行数为0时再确认用户是否存在
+func ensureUserUpdated(db *gorm.DB, id int64, rowsAffected int64) error {
+	if rowsAffected > 0 {
+		return nil
+	}
+	var count int64
+	if err := db.Model(&biz.User{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
 	return nil
 }
 
@@ -73,6 +166,13 @@ func (r *userRepository) Create(ctx context.Context, user *biz.User) error {
 		return err
 	}
 
+	// created_at/updated_at 由数据库默认值填充，MySQL 不支持 RETURNING，插入后回读以返回数据库中的实际值
+	err = r.db.WithContext(ctx).Select("created_at", "updated_at").Take(user).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to reload timestamps for user id: %d, error_reason: %v", user.ID, err)
+		return err
+	}
+
 	r.logger.WithContext(ctx).Infof("Successfully created user with id: %d, email: %s", user.ID, user.Email)
 	return nil
 }
@@ -116,3 +216,168 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*biz.Use
 	r.logger.WithContext(ctx).Infof("Successfully retrieved user with id: %d, email: %s", u.ID, email)
 	return &u, nil
 }
+
+// GetByIDPublic 根据ID获取用户的非敏感字段
+func (r *userRepository) GetByIDPublic(ctx context.Context, id int64) (*biz.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.GetByIDPublic")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": id,
+	})
+
+	r.logger.WithContext(ctx).Infof("Getting public user info with id: %d", id)
+	var u biz.User
+	err := r.db.WithContext(ctx).Select(userPublicColumns).Where("id = ?", id).First(&u).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get public user info with id: %d, error_reason: %v", id, err)
+		return nil, err
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully retrieved public user info with id: %d", id)
+	return &u, nil
+}
+
+// GetByEmailPublic 根据邮箱获取用户的非敏感字段
+func (r *userRepository) GetByEmailPublic(ctx context.Context, email string) (*biz.User, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.GetByEmailPublic")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"email": email,
+	})
+
+	r.logger.WithContext(ctx).Infof("Getting public user info with email: %s", email)
+	var u biz.User
+	err := r.db.WithContext(ctx).Select(userPublicColumns).Where("email = ?", email).First(&u).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get public user info with email: %s, error_reason: %v", email, err)
+		return nil, err
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully retrieved public user info with id: %d, email: %s", u.ID, email)
+	return &u, nil
+}
+
+// duplicateEmailRow 重复邮箱查询的结果行
+type duplicateEmailRow struct {
+	ID              int64
+	NormalizedEmail string
+}
+
+// FindDuplicateEmails 查找规范化（小写）后邮箱相同的未删除账号，按邮箱和ID升序分组
+func (r *userRepository) FindDuplicateEmails(ctx context.Context) ([]*biz.DuplicateEmailGroup, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.FindDuplicateEmails")
+	defer span.End()
+
+	r.logger.WithContext(ctx).Info("Finding duplicate emails")
+
+	duplicated := r.db.Model(&biz.User{}).
+		Select("LOWER(email)").
+		Group("LOWER(email)").
+		Having("COUNT(*) > 1")
+
+	var rows []duplicateEmailRow
+	err := r.db.WithContext(ctx).Model(&biz.User{}).
+		Select("id, LOWER(email) AS normalized_email").
+		Where("LOWER(email) IN (?)", duplicated).
+		Order("normalized_email, id").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to find duplicate emails, error_reason: %v", err)
+		return nil, err
+	}
+
+	groups := make([]*biz.DuplicateEmailGroup, 0)
+	for _, row := range rows {
+		if len(groups) == 0 || groups[len(groups)-1].NormalizedEmail != row.NormalizedEmail {
+			groups = append(groups, &biz.DuplicateEmailGroup{NormalizedEmail: row.NormalizedEmail})
+		}
+		last := groups[len(groups)-1]
+		last.UserIDs = append(last.UserIDs, row.ID)
+	}
+
+	r.logger.WithContext(ctx).Infof("Found %d duplicate email groups", len(groups))
+	return groups, nil
+}
+
+// SoftDelete 软删除用户，已删除的用户不再被查询到
+func (r *userRepository) SoftDelete(ctx context.Context, id int64) error {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.SoftDelete")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"user_id": id,
+	})
+
+	r.logger.WithContext(ctx).Infof("Soft deleting user with id: %d", id)
+
+	result := dbFromContext(ctx, r.db).Where("id = ?", id).Delete(&biz.User{})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to soft delete user with id: %d, error_reason: %v", id, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		r.logger.WithContext(ctx).Warnf("User not found or already deleted, id: %d", id)
+		return gorm.ErrRecordNotFound
+	}
+
+	r.logger.WithContext(ctx).Infof("Successfully soft deleted user with id: %d", id)
+	return nil
+}
+
+// ListByCreatedBetween 按注册时间升序分页查询 [from, to] 内注册的未删除用户，总数为0时不再查询明细
+func (r *userRepository) ListByCreatedBetween(ctx context.Context, from, to time.Time, page, pageSize int) ([]*biz.User, int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.ListByCreatedBetween")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"from":      from,
+		"to":        to,
+		"page":      page,
+		"page_size": pageSize,
+	})
+
+	var total int64
+	err := r.db.WithContext(ctx).Model(&biz.User{}).
+		Where("created_at BETWEEN ? AND ?", from, to).
+		Count(&total).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to count users created between %s and %s, error_reason: %v", from, to, err)
+		return nil, 0, err
+	}
+
+	users := make([]*biz.User, 0)
+	if total == 0 {
+		return users, 0, nil
+	}
+
+	err = r.db.WithContext(ctx).Select(userPublicColumns).
+		Where("created_at BETWEEN ? AND ?", from, to).
+		Order("created_at, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&users).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to list users created between %s and %s, error_reason: %v", from, to, err)
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// Stats 在一条查询中统计未删除的用户总数和付费用户数
+func (r *userRepository) Stats(ctx context.Context) (*biz.UserStats, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserRepository.Stats")
+	defer span.End()
+
+	var stats biz.UserStats
+	err := r.db.WithContext(ctx).Model(&biz.User{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(is_premium = 1), 0) AS premium").
+		Scan(&stats).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to query user stats, error_reason: %v", err)
+		return nil, err
+	}
+	return &stats, nil
+}
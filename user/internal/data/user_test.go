@@ -17,6 +17,8 @@ import (
 
 // TestUserRepository_Create 测试用户创建功能
 func TestUserRepository_Create(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name    string
 		user    *biz.User
@@ -26,10 +28,11 @@ func TestUserRepository_Create(t *testing.T) {
 		{
 			name: "成功创建用户",
 			user: &biz.User{
-				Email:        "test@example.com",
-				PasswordHash: "hashed_password",
-				Nickname:     "测试用户",
-				IsPremium:    0,
+				Email:         "test@example.com",
+				PasswordHash:  "hashed_password",
+				Nickname:      "测试用户",
+				IsPremium:     0,
+				EmailVerified: true,
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
@@ -38,21 +41,29 @@ func TestUserRepository_Create(t *testing.T) {
 						"test@example.com",
 						"hashed_password",
 						"测试用户",
-						"", // avatar_url
-						0,  // is_premium
+						"",   // avatar_url
+						0,    // is_premium
+						true, // email_verified
+						nil,  // deleted_at
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
+				// 回读数据库填充的时间戳
+				mock.ExpectQuery("SELECT `created_at`,`updated_at` FROM `user`").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
+						AddRow(createdAt, createdAt))
 			},
 			wantErr: false,
 		},
 		{
 			name: "创建用户失败 - 邮箱已存在",
 			user: &biz.User{
-				Email:        "existing@example.com",
-				PasswordHash: "hashed_password",
-				Nickname:     "测试用户",
-				IsPremium:    0,
+				Email:         "existing@example.com",
+				PasswordHash:  "hashed_password",
+				Nickname:      "测试用户",
+				IsPremium:     0,
+				EmailVerified: true,
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
@@ -61,8 +72,10 @@ func TestUserRepository_Create(t *testing.T) {
 						"existing@example.com",
 						"hashed_password",
 						"测试用户",
-						"", // avatar_url
-						0,  // is_premium
+						"",   // avatar_url
+						0,    // is_premium
+						true, // email_verified
+						nil,  // deleted_at
 					).
 					WillReturnError(fmt.Errorf("duplicate entry"))
 				mock.ExpectRollback()
@@ -84,6 +97,7 @@ func TestUserRepository_Create(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotZero(t, tt.user.ID)
+				assert.True(t, createdAt.Equal(tt.user.CreatedAt), "应返回数据库填充的创建时间")
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -107,7 +121,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 			mockFn: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "nickname", "avatar_url", "is_premium", "created_at", "updated_at"}).
 					AddRow(1, "test@example.com", "hashed_password", "测试用户", "", 0, time.Now(), time.Now())
-				mock.ExpectQuery("SELECT \\* FROM `user` WHERE id = \\? ORDER BY `user`.`id` LIMIT \\?").
+				mock.ExpectQuery("SELECT \\* FROM `user` WHERE id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
 					WithArgs(1, 1).
 					WillReturnRows(rows)
 			},
@@ -124,7 +138,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 			name:   "用户不存在",
 			userID: 999,
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT \\* FROM `user` WHERE id = \\? ORDER BY `user`.`id` LIMIT \\?").
+				mock.ExpectQuery("SELECT \\* FROM `user` WHERE id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
 					WithArgs(999, 1).
 					WillReturnError(gorm.ErrRecordNotFound)
 			},
@@ -177,7 +191,7 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 			mockFn: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "nickname", "avatar_url", "is_premium", "created_at", "updated_at"}).
 					AddRow(1, "test@example.com", "hashed_password", "测试用户", "", 0, time.Now(), time.Now())
-				mock.ExpectQuery("SELECT \\* FROM `user` WHERE email = \\? ORDER BY `user`.`id` LIMIT \\?").
+				mock.ExpectQuery("SELECT \\* FROM `user` WHERE email = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
 					WithArgs("test@example.com", 1).
 					WillReturnRows(rows)
 			},
@@ -194,7 +208,7 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 			name:  "用户不存在",
 			email: "nonexistent@example.com",
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT \\* FROM `user` WHERE email = \\? ORDER BY `user`.`id` LIMIT \\?").
+				mock.ExpectQuery("SELECT \\* FROM `user` WHERE email = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
 					WithArgs("nonexistent@example.com", 1).
 					WillReturnError(gorm.ErrRecordNotFound)
 			},
@@ -231,6 +245,128 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 	}
 }
 
+// publicUserQuery 只查询非敏感字段的 SQL，不包含 password_hash
+const publicUserQuery = "^SELECT `id`,`email`,`nickname`,`avatar_url`,`is_premium`,`created_at`,`updated_at` FROM `user` WHERE "
+
+// TestUserRepository_GetByIDPublic 测试根据ID获取用户非敏感字段
+func TestUserRepository_GetByIDPublic(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int64
+		mockFn    func(sqlmock.Sqlmock)
+		wantErr   bool
+		expectErr string
+	}{
+		{
+			name:   "成功获取用户且不查询密码哈希",
+			userID: 1,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "email", "nickname", "avatar_url", "is_premium", "created_at", "updated_at"}).
+					AddRow(1, "test@example.com", "测试用户", "", 0, time.Now(), time.Now())
+				mock.ExpectQuery(publicUserQuery+"id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs(1, 1).
+					WillReturnRows(rows)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "用户不存在",
+			userID: 999,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(publicUserQuery+"id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs(999, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantErr:   true,
+			expectErr: "record not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			user, err := repo.GetByIDPublic(context.Background(), tt.userID)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.expectErr != "" {
+					assert.Contains(t, err.Error(), tt.expectErr)
+				}
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.userID, user.ID)
+				assert.Equal(t, "test@example.com", user.Email)
+				assert.Empty(t, user.PasswordHash)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserRepository_GetByEmailPublic 测试根据邮箱获取用户非敏感字段
+func TestUserRepository_GetByEmailPublic(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		mockFn    func(sqlmock.Sqlmock)
+		wantErr   bool
+		expectErr string
+	}{
+		{
+			name:  "成功获取用户且不查询密码哈希",
+			email: "test@example.com",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "email", "nickname", "avatar_url", "is_premium", "created_at", "updated_at"}).
+					AddRow(1, "test@example.com", "测试用户", "", 0, time.Now(), time.Now())
+				mock.ExpectQuery(publicUserQuery+"email = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs("test@example.com", 1).
+					WillReturnRows(rows)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "用户不存在",
+			email: "nonexistent@example.com",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(publicUserQuery+"email = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs("nonexistent@example.com", 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantErr:   true,
+			expectErr: "record not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			user, err := repo.GetByEmailPublic(context.Background(), tt.email)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.expectErr != "" {
+					assert.Contains(t, err.Error(), tt.expectErr)
+				}
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.email, user.Email)
+				assert.Empty(t, user.PasswordHash)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 // setupTestDB 设置测试数据库
 func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	sqlDB, mock, err := sqlmock.New()
@@ -260,6 +396,8 @@ func TestUserRepository_Update(t *testing.T) {
 		req     *biz.UpdateUserRequest
 		mockFn  func(sqlmock.Sqlmock)
 		wantErr bool
+		// wantErrIs 期望返回的具体错误，为 nil 时只检查是否出错
+		wantErrIs error
 	}{
 		{
 			name:   "成功更新昵称",
@@ -269,7 +407,7 @@ func TestUserRepository_Update(t *testing.T) {
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\?").
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
 					WithArgs("新昵称", sqlmock.AnyArg(), 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
@@ -284,7 +422,7 @@ func TestUserRepository_Update(t *testing.T) {
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`updated_at`=\\? WHERE id = \\?").
+				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
 					WithArgs("https://example.com/avatar.jpg", sqlmock.AnyArg(), 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
@@ -300,7 +438,7 @@ func TestUserRepository_Update(t *testing.T) {
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`nickname`=\\?,`updated_at`=\\? WHERE id = \\?").
+				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
 					WithArgs("https://example.com/avatar.jpg", "新昵称", sqlmock.AnyArg(), 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
@@ -316,7 +454,7 @@ func TestUserRepository_Update(t *testing.T) {
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`updated_at`=\\? WHERE id = \\?").
+				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
 					WithArgs("", sqlmock.AnyArg(), 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
@@ -342,13 +480,50 @@ func TestUserRepository_Update(t *testing.T) {
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\?").
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
 					WithArgs("不存在的用户", sqlmock.AnyArg(), 999).
 					WillReturnError(gorm.ErrRecordNotFound)
 				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
+		{
+			name:   "更新用户失败 - 未匹配任何行",
+			userID: 999,
+			req: &biz.UpdateUserRequest{
+				Nickname: stringPtr("不存在的用户"),
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("不存在的用户", sqlmock.AnyArg(), 999).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user` WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs(999).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+			},
+			wantErr:   true,
+			wantErrIs: gorm.ErrRecordNotFound,
+		},
+		{
+			name:   "值未变化时影响行数为0但用户存在",
+			userID: 1,
+			req: &biz.UpdateUserRequest{
+				Nickname: stringPtr("原昵称"),
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("原昵称", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user` WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+			},
+			wantErr: false,
+		},
 		{
 			name:   "更新用户失败 - 数据库错误",
 			userID: 1,
@@ -357,7 +532,7 @@ func TestUserRepository_Update(t *testing.T) {
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\?").
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
 					WithArgs("测试昵称", sqlmock.AnyArg(), 1).
 					WillReturnError(fmt.Errorf("database connection error_reason"))
 				mock.ExpectRollback()
@@ -374,13 +549,452 @@ func TestUserRepository_Update(t *testing.T) {
 
 			err := repo.Update(context.Background(), tt.userID, tt.req)
 
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			} else if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserRepository_UpdatePasswordHash 测试更新密码哈希
+func TestUserRepository_UpdatePasswordHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "成功更新密码哈希",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `password_hash`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("$argon2id$hash", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "数据库错误",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `password_hash`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("$argon2id$hash", sqlmock.AnyArg(), 1).
+					WillReturnError(fmt.Errorf("database connection error_reason"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := repo.UpdatePasswordHash(context.Background(), 1, "$argon2id$hash")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserRepository_UpdateInTx 测试在事务中更新资料和密码哈希时共用同一个事务
+func TestUserRepository_UpdateInTx(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "同一事务内提交",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("新昵称", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE `user` SET `password_hash`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("$argon2id$hash", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "后续更新失败时一起回滚",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("新昵称", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE `user` SET `password_hash`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("$argon2id$hash", sqlmock.AnyArg(), 1).
+					WillReturnError(fmt.Errorf("database connection error_reason"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := NewTransaction(db).InTx(context.Background(), func(ctx context.Context) error {
+				if err := repo.Update(ctx, 1, &biz.UpdateUserRequest{Nickname: stringPtr("新昵称")}); err != nil {
+					return err
+				}
+				return repo.UpdatePasswordHash(ctx, 1, "$argon2id$hash")
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserRepository_UpdateAndGet 测试更新用户后在同一事务中读回最新数据
+func TestUserRepository_UpdateAndGet(t *testing.T) {
+	publicColumns := []string{"id", "email", "nickname", "avatar_url", "is_premium", "created_at", "updated_at"}
+	updatedAt := time.Now()
+
+	tests := []struct {
+		name      string
+		req       *biz.UpdateUserRequest
+		mockFn    func(sqlmock.Sqlmock)
+		wantUser  *biz.User
+		wantErr   bool
+		expectErr string
+	}{
+		{
+			name: "返回的用户包含更新后的字段",
+			req: &biz.UpdateUserRequest{
+				Nickname:  stringPtr("新昵称"),
+				AvatarURL: stringPtr("https://example.com/new.jpg"),
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("https://example.com/new.jpg", "新昵称", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(publicUserQuery+"id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(publicColumns).
+						AddRow(1, "test@example.com", "新昵称", "https://example.com/new.jpg", 0, updatedAt, updatedAt))
+				mock.ExpectCommit()
+			},
+			wantUser: &biz.User{
+				ID:        1,
+				Email:     "test@example.com",
+				Nickname:  "新昵称",
+				AvatarURL: "https://example.com/new.jpg",
+				CreatedAt: updatedAt,
+				UpdatedAt: updatedAt,
+			},
+		},
+		{
+			name: "没有需要更新的字段时只读取用户",
+			req:  &biz.UpdateUserRequest{},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(publicUserQuery+"id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(publicColumns).
+						AddRow(1, "test@example.com", "旧昵称", "", 0, updatedAt, updatedAt))
+				mock.ExpectCommit()
+			},
+			wantUser: &biz.User{
+				ID:        1,
+				Email:     "test@example.com",
+				Nickname:  "旧昵称",
+				CreatedAt: updatedAt,
+				UpdatedAt: updatedAt,
+			},
+		},
+		{
+			name: "用户不存在时回滚",
+			req:  &biz.UpdateUserRequest{Nickname: stringPtr("新昵称")},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("新昵称", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(publicUserQuery+"id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(publicColumns))
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			expectErr: "record not found",
+		},
+		{
+			name: "更新失败时回滚且不读取",
+			req:  &biz.UpdateUserRequest{Nickname: stringPtr("新昵称")},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("新昵称", sqlmock.AnyArg(), 1).
+					WillReturnError(fmt.Errorf("database connection error_reason"))
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			expectErr: "database connection error_reason",
+		},
+		{
+			name: "多个字段更新后读取失败时整体回滚",
+			req: &biz.UpdateUserRequest{
+				Nickname:  stringPtr("新昵称"),
+				AvatarURL: stringPtr("https://example.com/new.jpg"),
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `avatar_url`=\\?,`nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs("https://example.com/new.jpg", "新昵称", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(publicUserQuery+"id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+					WithArgs(1, 1).
+					WillReturnError(fmt.Errorf("read timeout"))
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			expectErr: "read timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			user, err := repo.UpdateAndGet(context.Background(), 1, tt.req)
+
 			if tt.wantErr {
 				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr)
+				assert.Nil(t, user)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.wantUser, user)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUserRepository_UpdateAndGetInTx 测试在外层事务中调用时加入该事务而不是另开事务
+func TestUserRepository_UpdateAndGetInTx(t *testing.T) {
+	db, mock := setupTestDB(t)
+	repo := NewUserRepository(db, log.DefaultLogger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `user` SET `nickname`=\\?,`updated_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+		WithArgs("新昵称", sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(publicUserQuery+"id = \\? AND `user`.`deleted_at` IS NULL ORDER BY `user`.`id` LIMIT \\?").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "nickname"}).AddRow(1, "test@example.com", "新昵称"))
+	mock.ExpectCommit()
+
+	var user *biz.User
+	err := NewTransaction(db).InTx(context.Background(), func(ctx context.Context) error {
+		var err error
+		user, err = repo.UpdateAndGet(ctx, 1, &biz.UpdateUserRequest{Nickname: stringPtr("新昵称")})
+		return err
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "新昵称", user.Nickname)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_SoftDelete 测试软删除用户
+func TestUserRepository_SoftDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockFn  func(sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "成功软删除",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `deleted_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "用户不存在或已删除",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `deleted_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name: "数据库错误",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `user` SET `deleted_at`=\\? WHERE id = \\? AND `user`.`deleted_at` IS NULL").
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnError(fmt.Errorf("database connection error_reason"))
+				mock.ExpectRollback()
+			},
+			wantErr: fmt.Errorf("database connection error_reason"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			err := repo.SoftDelete(context.Background(), 1)
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
 
+// 辅助函数
+func stringPtr(s string) *string {
+	return &s
+}
+
+// TestUserRepository_ListByCreatedBetween 测试按注册时间窗口分页查询用户
+func TestUserRepository_ListByCreatedBetween(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+	columns := []string{"id", "email", "nickname", "avatar_url", "is_premium", "created_at", "updated_at"}
+
+	tests := []struct {
+		name      string
+		page      int
+		pageSize  int
+		mockFn    func(sqlmock.Sqlmock)
+		wantIDs   []int64
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name:     "按时间窗口过滤并分页",
+			page:     2,
+			pageSize: 2,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user` WHERE \\(created_at BETWEEN \\? AND \\?\\) AND `user`.`deleted_at` IS NULL").
+					WithArgs(from, to).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(5))
+				mock.ExpectQuery("SELECT `id`,`email`,`nickname`,`avatar_url`,`is_premium`,`created_at`,`updated_at` FROM `user` "+
+					"WHERE \\(created_at BETWEEN \\? AND \\?\\) AND `user`.`deleted_at` IS NULL ORDER BY created_at, id LIMIT \\? OFFSET \\?").
+					WithArgs(from, to, 2, 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(3, "c@example.com", "c", "", 0, from.Add(48*time.Hour), from.Add(48*time.Hour)).
+						AddRow(4, "d@example.com", "d", "", 0, from.Add(72*time.Hour), from.Add(72*time.Hour)))
+			},
+			wantIDs:   []int64{3, 4},
+			wantTotal: 5,
+		},
+		{
+			name:     "时间窗口内没有用户",
+			page:     1,
+			pageSize: 20,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user` WHERE \\(created_at BETWEEN \\? AND \\?\\)").
+					WithArgs(from, to).
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+			},
+			wantIDs:   []int64{},
+			wantTotal: 0,
+		},
+		{
+			name:     "统计总数失败",
+			page:     1,
+			pageSize: 20,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user`").
+					WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "查询明细失败",
+			page:     1,
+			pageSize: 20,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `user`").
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+				mock.ExpectQuery("SELECT `id`,`email`").
+					WillReturnError(fmt.Errorf("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			repo := NewUserRepository(db, log.DefaultLogger)
+			tt.mockFn(mock)
+
+			users, total, err := repo.ListByCreatedBetween(context.Background(), from, to, tt.page, tt.pageSize)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTotal, total)
+				ids := make([]int64, 0, len(users))
+				for _, u := range users {
+					ids = append(ids, u.ID)
+					assert.Empty(t, u.PasswordHash)
+				}
+				assert.Equal(t, tt.wantIDs, ids)
+			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
+
+// TestUserRepository_Stats 测试在一条查询中统计未删除的用户总数和付费用户数
+func TestUserRepository_Stats(t *testing.T) {
+	db, mock := setupTestDB(t)
+	repo := NewUserRepository(db, log.DefaultLogger)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS total, COALESCE\\(SUM\\(is_premium = 1\\), 0\\) AS premium FROM `user` WHERE `user`.`deleted_at` IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "premium"}).AddRow(120, 7))
+
+	stats, err := repo.Stats(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &biz.UserStats{Total: 120, Premium: 7}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,58 @@
+package accountid
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Prefix 对外账号ID的前缀
+const Prefix = "usr_"
+
+// alphabet base62 字符表
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrInvalid 对外账号ID格式无效
+var ErrInvalid = errors.New("invalid account id")
+
+// Encode 将内部 int64 用户ID编码为带前缀的 base62 字符串，如 usr_2LKcb1
+// 只对正数有意义，非正数编码结果无法被 Decode 解析
+func Encode(id int64) string {
+	if id <= 0 {
+		return Prefix + "0"
+	}
+	var buf [11]byte // int64 最大值的 base62 表示为 11 位
+	i := len(buf)
+	for n := uint64(id); n > 0; n /= 62 {
+		i--
+		buf[i] = alphabet[n%62]
+	}
+	return Prefix + string(buf[i:])
+}
+
+// Decode 将 Encode 生成的字符串还原为内部用户ID
+// 缺少前缀、包含非 base62 字符、有前导零、超出 int64 范围或结果不为正数时返回 ErrInvalid
+func Decode(s string) (int64, error) {
+	body, ok := strings.CutPrefix(s, Prefix)
+	if !ok || body == "" || body[0] == '0' {
+		return 0, ErrInvalid
+	}
+
+	var n uint64
+	for i := 0; i < len(body); i++ {
+		d := strings.IndexByte(alphabet, body[i])
+		if d < 0 {
+			return 0, ErrInvalid
+		}
+		if n > (math.MaxInt64-uint64(d))/62 {
+			return 0, ErrInvalid
+		}
+		n = n*62 + uint64(d)
+	}
+	return int64(n), nil
+}
+
+// IsEncoded 判断字符串是否为带前缀的对外账号ID形式，不校验内容是否有效
+func IsEncoded(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
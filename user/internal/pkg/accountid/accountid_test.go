@@ -0,0 +1,65 @@
+package accountid
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncodeDecode_RoundTrip 测试编码后的账号ID可以还原为原始ID
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		id   int64
+	}{
+		{name: "最小ID", id: 1},
+		{name: "进位边界", id: 62},
+		{name: "雪花ID", id: 1790000000000000001},
+		{name: "int64 最大值", id: math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := Encode(tt.id)
+			assert.True(t, IsEncoded(encoded))
+
+			id, err := Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
+
+// TestEncode 测试编码结果的格式
+func TestEncode(t *testing.T) {
+	assert.Equal(t, "usr_1", Encode(1))
+	assert.Equal(t, "usr_10", Encode(62))
+	assert.Equal(t, "usr_AzL8n0Y58m7", Encode(math.MaxInt64))
+}
+
+// TestDecode_Invalid 测试无效的账号ID被拒绝
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "空字符串", input: ""},
+		{name: "缺少前缀", input: "2LKcb1"},
+		{name: "前缀错误", input: "acc_2LKcb1"},
+		{name: "只有前缀", input: "usr_"},
+		{name: "包含非法字符", input: "usr_2LK-b1"},
+		{name: "前导零", input: "usr_01"},
+		{name: "零值", input: "usr_0"},
+		{name: "超出 int64 范围", input: "usr_AzL8n0Y58m8"},
+		{name: "十进制ID", input: "123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.input)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+}
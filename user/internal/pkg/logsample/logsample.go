@@ -0,0 +1,31 @@
+package logsample
+
+import (
+	"sync/atomic"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// sampler 对 Warn 以下级别的日志按 1/rate 采样，Warn 及以上级别始终输出
+type sampler struct {
+	next  log.Logger
+	rate  uint64
+	count uint64
+}
+
+// NewLogger 返回按 1/rate 采样 Debug/Info 日志的 Logger，rate 不大于1时原样返回 next
+// 应包在最内层（log.With 之前），避免多一层调用导致 caller 字段偏移
+func NewLogger(next log.Logger, rate int) log.Logger {
+	if rate <= 1 {
+		return next
+	}
+	return &sampler{next: next, rate: uint64(rate)}
+}
+
+// Log 实现 log.Logger，每 rate 条 Debug/Info 日志输出第一条
+func (s *sampler) Log(level log.Level, keyvals ...interface{}) error {
+	if level < log.LevelWarn && (atomic.AddUint64(&s.count, 1)-1)%s.rate != 0 {
+		return nil
+	}
+	return s.next.Log(level, keyvals...)
+}
@@ -0,0 +1,96 @@
+package logsample
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingLogger 按级别统计收到的日志条数
+type countingLogger struct {
+	mu     sync.Mutex
+	counts map[log.Level]int
+}
+
+func (l *countingLogger) Log(level log.Level, keyvals ...interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.counts[level]++
+	return nil
+}
+
+// TestNewLogger 测试 Info 日志按比例采样而 Warn/Error 日志全部输出
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      int
+		wantInfo  int
+		wantWarn  int
+		wantError int
+	}{
+		{
+			name:      "每10条Info输出1条",
+			rate:      10,
+			wantInfo:  100,
+			wantWarn:  50,
+			wantError: 50,
+		},
+		{
+			name:      "采样率为0时不采样",
+			rate:      0,
+			wantInfo:  1000,
+			wantWarn:  50,
+			wantError: 50,
+		},
+		{
+			name:      "采样率为1时不采样",
+			rate:      1,
+			wantInfo:  1000,
+			wantWarn:  50,
+			wantError: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &countingLogger{counts: make(map[log.Level]int)}
+			helper := log.NewHelper(NewLogger(counter, tt.rate))
+
+			for i := 0; i < 1000; i++ {
+				helper.Infof("Getting user with id: %d", i)
+				if i%20 == 0 {
+					helper.Warn("slow operation")
+					helper.Errorf("Failed to get user with id: %d", i)
+				}
+			}
+
+			assert.Equal(t, tt.wantInfo, counter.counts[log.LevelInfo])
+			assert.Equal(t, tt.wantWarn, counter.counts[log.LevelWarn])
+			assert.Equal(t, tt.wantError, counter.counts[log.LevelError])
+		})
+	}
+}
+
+// TestNewLogger_Concurrent 测试并发调用时采样计数准确
+func TestNewLogger_Concurrent(t *testing.T) {
+	counter := &countingLogger{counts: make(map[log.Level]int)}
+	logger := NewLogger(counter, 10)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_ = logger.Log(log.LevelInfo, "msg", "get")
+				_ = logger.Log(log.LevelError, "msg", "failed")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, counter.counts[log.LevelInfo])
+	assert.Equal(t, 1000, counter.counts[log.LevelError])
+}
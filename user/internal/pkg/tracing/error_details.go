@@ -0,0 +1,28 @@
+package tracing
+
+import "runtime/debug"
+
+// 错误详情在错误响应 metadata 中的 key，仅在开启 server.expose_error_details 时写入
+const (
+	MetadataErrorDetail = "error_detail" // 错误根因
+	MetadataErrorStack  = "error_stack"  // 根因产生时的调用栈
+)
+
+// stackError 记录创建时调用栈的错误
+type stackError struct {
+	err   error
+	stack string
+}
+
+// WithStack 为错误记录当前调用栈，配合 errors.Error.WithCause 使用，
+// 开启 server.expose_error_details 时调用栈会出现在错误响应中
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, stack: string(debug.Stack())}
+}
+
+func (e *stackError) Error() string { return e.err.Error() }
+
+func (e *stackError) Unwrap() error { return e.err }
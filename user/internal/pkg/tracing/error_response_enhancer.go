@@ -11,8 +11,8 @@ import (
 )
 
 // ErrorResponseEnhancer 错误响应增强中间件
-// 自动为错误响应添加 traceid 和 spanid 到 metadata 中
-func ErrorResponseEnhancer() middleware.Middleware {
+// 自动为错误响应添加 traceid 和 spanid 到 metadata 中，exposeDetails 为 true 时附带错误根因和调用栈
+func ErrorResponseEnhancer(exposeDetails bool) middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
 			// 执行正常的业务逻辑
@@ -24,35 +24,21 @@ func ErrorResponseEnhancer() middleware.Middleware {
 			}
 
 			// 获取当前追踪信息
+			var traceID, spanID string
 			span := trace.SpanFromContext(ctx)
 			if span != nil {
-				// 获取 traceid 和 spanid
-				traceID := span.SpanContext().TraceID().String()
-				spanID := span.SpanContext().SpanID().String()
-
-				// 创建包含追踪信息的 metadata
-				metadata := map[string]string{
-					"traceid": traceID,
-					"spanid":  spanID,
-				}
-
-				// 将追踪信息添加到错误中
-				// 先转换为 Kratos 错误类型，然后添加 metadata
-				kratosErr := errors.FromError(err)
-				if kratosErr != nil {
-					enhancedErr := kratosErr.WithMetadata(metadata)
-					return reply, enhancedErr
-				}
+				traceID = span.SpanContext().TraceID().String()
+				spanID = span.SpanContext().SpanID().String()
 			}
 
-			return reply, err
+			return reply, enhanceError(err, traceID, spanID, exposeDetails)
 		}
 	}
 }
 
 // HTTPErrorResponseEnhancer HTTP 错误响应增强中间件
-// 专门处理 HTTP 错误响应，确保在 HTTP 响应中包含追踪信息
-func HTTPErrorResponseEnhancer() middleware.Middleware {
+// 专门处理 HTTP 错误响应，确保在 HTTP 响应中包含追踪信息，exposeDetails 为 true 时附带错误根因和调用栈
+func HTTPErrorResponseEnhancer(exposeDetails bool) middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
 			// 执行正常的业务逻辑
@@ -64,10 +50,11 @@ func HTTPErrorResponseEnhancer() middleware.Middleware {
 			}
 
 			// 尝试从 HTTP 请求上下文中获取追踪信息
+			var traceID, spanID string
 			if httpReq, ok := http.RequestFromServerContext(ctx); ok {
 				// 从请求头中获取追踪信息（如果存在）
-				traceID := httpReq.Header.Get("X-Trace-ID")
-				spanID := httpReq.Header.Get("X-Span-ID")
+				traceID = httpReq.Header.Get("X-Trace-ID")
+				spanID = httpReq.Header.Get("X-Span-ID")
 
 				if traceID == "" || spanID == "" {
 					// 如果头信息中没有，尝试从 OpenTelemetry 上下文中获取
@@ -77,29 +64,16 @@ func HTTPErrorResponseEnhancer() middleware.Middleware {
 						spanID = span.SpanContext().SpanID().String()
 					}
 				}
-
-				// 如果获取到有效的追踪信息，添加到错误中
-				if traceID != "" && spanID != "" {
-					metadata := map[string]string{
-						"traceid": traceID,
-						"spanid":  spanID,
-					}
-					// 转换为 Kratos 错误类型，然后添加 metadata
-					kratosErr := errors.FromError(err)
-					if kratosErr != nil {
-						err = kratosErr.WithMetadata(metadata)
-					}
-				}
 			}
 
-			return reply, err
+			return reply, enhanceError(err, traceID, spanID, exposeDetails)
 		}
 	}
 }
 
 // GRPCErrorResponseEnhancer gRPC 错误响应增强中间件
-// 专门处理 gRPC 错误响应
-func GRPCErrorResponseEnhancer() middleware.Middleware {
+// 专门处理 gRPC 错误响应，exposeDetails 为 true 时附带错误根因和调用栈
+func GRPCErrorResponseEnhancer(exposeDetails bool) middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (interface{}, error) {
 			// 执行正常的业务逻辑
@@ -111,29 +85,49 @@ func GRPCErrorResponseEnhancer() middleware.Middleware {
 			}
 
 			// 从 OpenTelemetry 上下文中获取追踪信息
+			var traceID, spanID string
 			span := trace.SpanFromContext(ctx)
 			if span != nil {
-				traceID := span.SpanContext().TraceID().String()
-				spanID := span.SpanContext().SpanID().String()
+				traceID = span.SpanContext().TraceID().String()
+				spanID = span.SpanContext().SpanID().String()
+			}
 
-				// 创建包含追踪信息的 metadata
-				metadata := map[string]string{
-					"traceid": traceID,
-					"spanid":  spanID,
-				}
+			return reply, enhanceError(err, traceID, spanID, exposeDetails)
+		}
+	}
+}
 
-				// 将追踪信息添加到错误中
-				// 先转换为 Kratos 错误类型，然后添加 metadata
-				kratosErr := errors.FromError(err)
-				if kratosErr != nil {
-					enhancedErr := kratosErr.WithMetadata(metadata)
-					return reply, enhancedErr
-				}
-			}
+// enhanceError 将追踪信息写入错误的 metadata，追踪信息无论是否开启错误详情都会写入
+// exposeDetails 为 true 时为服务端错误（5xx）附带根因和调用栈，为 false 时清除这两项，避免泄露内部信息
+func enhanceError(err error, traceID, spanID string, exposeDetails bool) error {
+	// 先转换为 Kratos 错误类型，再合并 metadata
+	kratosErr := errors.FromError(err)
+	if kratosErr == nil {
+		return err
+	}
 
-			return reply, err
+	metadata := make(map[string]string, len(kratosErr.Metadata)+4)
+	for k, v := range kratosErr.Metadata {
+		if k == MetadataErrorDetail || k == MetadataErrorStack {
+			continue
 		}
+		metadata[k] = v
 	}
+	if traceID != "" && spanID != "" {
+		metadata["traceid"] = traceID
+		metadata["spanid"] = spanID
+	}
+	if exposeDetails && kratosErr.Code >= 500 {
+		if cause := kratosErr.Unwrap(); cause != nil {
+			metadata[MetadataErrorDetail] = cause.Error()
+			var stackErr *stackError
+			if errors.As(cause, &stackErr) {
+				metadata[MetadataErrorStack] = stackErr.stack
+			}
+		}
+	}
+
+	return kratosErr.WithMetadata(metadata)
 }
 
 // ExtractTraceInfoFromError 从错误中提取追踪信息
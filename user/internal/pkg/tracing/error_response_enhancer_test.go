@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	error_reason "user/api/error_reason"
+)
+
+// newTestSpanContext 创建带有效追踪信息的 context
+func newTestSpanContext(t *testing.T) context.Context {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+// TestGRPCErrorResponseEnhancer_ErrorDetails 测试错误详情仅在开启时出现，追踪信息始终存在
+func TestGRPCErrorResponseEnhancer_ErrorDetails(t *testing.T) {
+	cause := fmt.Errorf("signing key unavailable")
+
+	tests := []struct {
+		name          string
+		exposeDetails bool
+		err           error
+		wantDetail    bool
+	}{
+		{
+			name:          "开启时附带根因和调用栈",
+			exposeDetails: true,
+			err:           error_reason.ErrorUserInternalError("访问令牌生成失败").WithCause(WithStack(cause)),
+			wantDetail:    true,
+		},
+		{
+			name:          "关闭时不附带根因",
+			exposeDetails: false,
+			err:           error_reason.ErrorUserInternalError("访问令牌生成失败").WithCause(WithStack(cause)),
+		},
+		{
+			name:          "关闭时清除已有的错误详情",
+			exposeDetails: false,
+			err: error_reason.ErrorUserInternalError("访问令牌生成失败").WithMetadata(map[string]string{
+				MetadataErrorDetail: "leaked",
+				MetadataErrorStack:  "leaked",
+			}),
+		},
+		{
+			name:          "客户端错误不附带根因",
+			exposeDetails: true,
+			err:           error_reason.ErrorUserInvalidRequest("请求参数无效").WithCause(cause),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := GRPCErrorResponseEnhancer(tt.exposeDetails)(func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tt.err
+			})
+
+			_, err := handler(newTestSpanContext(t), nil)
+
+			e := errors.FromError(err)
+			require.NotNil(t, e)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", e.Metadata["traceid"], "追踪信息应始终存在")
+			assert.Equal(t, "00f067aa0ba902b7", e.Metadata["spanid"])
+			if tt.wantDetail {
+				assert.Equal(t, "signing key unavailable", e.Metadata[MetadataErrorDetail])
+				assert.Contains(t, e.Metadata[MetadataErrorStack], "TestGRPCErrorResponseEnhancer_ErrorDetails")
+			} else {
+				assert.NotContains(t, e.Metadata, MetadataErrorDetail)
+				assert.NotContains(t, e.Metadata, MetadataErrorStack)
+			}
+		})
+	}
+}
+
+// TestHTTPErrorResponseEnhancer_ErrorDetails 测试没有 HTTP 请求上下文时仍按配置处理错误详情
+func TestHTTPErrorResponseEnhancer_ErrorDetails(t *testing.T) {
+	err := error_reason.ErrorUserInternalError("邮件发送失败").WithCause(WithStack(fmt.Errorf("smtp timeout")))
+
+	for _, expose := range []bool{true, false} {
+		handler := HTTPErrorResponseEnhancer(expose)(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, err
+		})
+
+		_, got := handler(context.Background(), nil)
+
+		e := errors.FromError(got)
+		require.NotNil(t, e)
+		_, hasDetail := e.Metadata[MetadataErrorDetail]
+		assert.Equal(t, expose, hasDetail, "expose_error_details=%v", expose)
+	}
+}
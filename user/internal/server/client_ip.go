@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"google.golang.org/grpc/peer"
+	"user/internal/biz"
+	"user/internal/conf"
+)
+
+const (
+	// realIPHeader 网关写入的客户端真实 IP 请求头
+	realIPHeader = "X-Real-IP"
+	// forwardedForHeader 代理逐跳追加的来源 IP 列表请求头，最左侧为原始客户端
+	forwardedForHeader = "X-Forwarded-For"
+)
+
+// headerGetter 按名称读取请求头，兼容 net/http.Header 和 kratos transport.Header
+type headerGetter interface {
+	Get(key string) string
+}
+
+// TrustedProxies 可信反向代理列表，只有对端地址属于该列表时才采用请求头中的客户端 IP
+type TrustedProxies struct {
+	nets     []*net.IPNet
+	trustAll bool
+}
+
+// NewTrustedProxies 根据 server.trusted_proxies 创建可信代理列表
+// 未配置时兼容原有行为：网关模式信任所有对端（服务只能经网关访问），公网模式不信任任何对端
+// 格式不正确的条目已由 conf.Validate 在启动时拒绝，这里直接跳过
+func NewTrustedProxies(c *conf.Server) *TrustedProxies {
+	if len(c.TrustedProxies) == 0 {
+		return &TrustedProxies{trustAll: !c.InternetFacing}
+	}
+	proxies := &TrustedProxies{}
+	for _, entry := range c.TrustedProxies {
+		if ipNet := parseProxy(entry); ipNet != nil {
+			proxies.nets = append(proxies.nets, ipNet)
+		}
+	}
+	return proxies
+}
+
+// parseProxy 将 IP 或 CIDR 解析为网段，单个 IP 视为只包含自身的网段
+func parseProxy(entry string) *net.IPNet {
+	if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+		return ipNet
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// trusts 判断 ip 是否为可信代理
+func (p *TrustedProxies) trusts(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if p.trustAll {
+		return true
+	}
+	for _, ipNet := range p.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP 返回客户端 IP：对端不是可信代理时只使用对端地址，请求头可被伪造
+// 对端可信时依次采用 X-Real-IP、X-Forwarded-For 中从右往左第一个非可信代理的地址，都没有时使用对端地址
+func clientIP(remoteAddr string, header headerGetter, proxies *TrustedProxies) string {
+	peerIP := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		peerIP = host
+	}
+	if !proxies.trusts(net.ParseIP(peerIP)) {
+		return peerIP
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(header.Get(realIPHeader))); ip != nil {
+		return ip.String()
+	}
+	if forwarded := header.Get(forwardedForHeader); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		client := ""
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				// 格式不正确的条目之前的内容不可信，使用已经确认的最后一跳
+				break
+			}
+			client = ip.String()
+			if !proxies.trusts(ip) {
+				break
+			}
+		}
+		if client != "" {
+			return client
+		}
+	}
+	return peerIP
+}
+
+// GRPCClientIP 将 gRPC 调用的客户端 IP 写入 context，使注册频率统计和会话 IP 记录与 HTTP 请求一致
+// 对端地址来自连接，可信代理转发时从 x-real-ip / x-forwarded-for 元数据读取
+func GRPCClientIP(proxies *TrustedProxies) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			p, ok := peer.FromContext(ctx)
+			if !ok || p.Addr == nil {
+				return handler(ctx, req)
+			}
+			var header headerGetter = emptyHeader{}
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				header = tr.RequestHeader()
+			}
+			return handler(biz.WithClientIP(ctx, clientIP(p.Addr.String(), header, proxies)), req)
+		}
+	}
+}
+
+// emptyHeader 不包含任何请求头
+type emptyHeader struct{}
+
+func (emptyHeader) Get(string) string { return "" }
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"net"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/peer"
+	"user/internal/biz"
+	"user/internal/conf"
+)
+
+// TestClientIP 测试按可信代理列表从请求头或对端地址解析客户端 IP
+func TestClientIP(t *testing.T) {
+	proxies := NewTrustedProxies(&conf.Server{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"}})
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		realIP       string
+		forwardedFor string
+		wantIP       string
+	}{
+		{
+			name:       "直连使用对端地址",
+			remoteAddr: "203.0.113.7:5678",
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:         "经过一层可信代理使用X-Forwarded-For",
+			remoteAddr:   "10.0.0.2:5678",
+			forwardedFor: "203.0.113.7",
+			wantIP:       "203.0.113.7",
+		},
+		{
+			name:       "可信代理写入的X-Real-IP",
+			remoteAddr: "192.0.2.1:5678",
+			realIP:     "203.0.113.7",
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:         "多层代理跳过可信代理，忽略客户端伪造的最左侧地址",
+			remoteAddr:   "10.0.0.2:5678",
+			forwardedFor: "198.51.100.1, 203.0.113.7, 10.0.0.3",
+			wantIP:       "203.0.113.7",
+		},
+		{
+			name:         "非可信来源伪造的X-Forwarded-For被忽略",
+			remoteAddr:   "203.0.113.7:5678",
+			forwardedFor: "198.51.100.1",
+			wantIP:       "203.0.113.7",
+		},
+		{
+			name:       "非可信来源伪造的X-Real-IP被忽略",
+			remoteAddr: "203.0.113.7:5678",
+			realIP:     "198.51.100.1",
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:         "X-Forwarded-For包含非法条目时使用已确认的最后一跳",
+			remoteAddr:   "10.0.0.2:5678",
+			forwardedFor: "198.51.100.1, unknown, 10.0.0.3",
+			wantIP:       "10.0.0.3",
+		},
+		{
+			name:       "可信代理未携带请求头时使用对端地址",
+			remoteAddr: "10.0.0.2:5678",
+			wantIP:     "10.0.0.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := nethttp.Header{}
+			if tt.realIP != "" {
+				header.Set(realIPHeader, tt.realIP)
+			}
+			if tt.forwardedFor != "" {
+				header.Set(forwardedForHeader, tt.forwardedFor)
+			}
+
+			assert.Equal(t, tt.wantIP, clientIP(tt.remoteAddr, header, proxies))
+		})
+	}
+}
+
+// TestNewTrustedProxies 测试未配置可信代理时按部署模式决定是否信任请求头
+func TestNewTrustedProxies(t *testing.T) {
+	header := nethttp.Header{}
+	header.Set(forwardedForHeader, "198.51.100.1")
+
+	gateway := NewTrustedProxies(&conf.Server{InternetFacing: false})
+	assert.Equal(t, "198.51.100.1", clientIP("203.0.113.7:5678", header, gateway))
+
+	internetFacing := NewTrustedProxies(&conf.Server{InternetFacing: true})
+	assert.Equal(t, "203.0.113.7", clientIP("203.0.113.7:5678", header, internetFacing))
+}
+
+// grpcTransport 测试用的 gRPC 服务端 transport
+type grpcTransport struct {
+	transport.Transporter
+	header headerCarrier
+}
+
+func (t grpcTransport) RequestHeader() transport.Header { return t.header }
+
+// headerCarrier 测试用的请求元数据
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string      { return h[key] }
+func (h headerCarrier) Set(key, value string)      { h[key] = value }
+func (h headerCarrier) Add(key, value string)      { h[key] = value }
+func (h headerCarrier) Keys() []string             { return nil }
+func (h headerCarrier) Values(key string) []string { return []string{h[key]} }
+
+// TestGRPCClientIP 测试 gRPC 调用按连接对端和可信代理元数据写入客户端 IP
+func TestGRPCClientIP(t *testing.T) {
+	proxies := NewTrustedProxies(&conf.Server{TrustedProxies: []string{"10.0.0.0/8"}})
+	call := func(ctx context.Context) string {
+		var got string
+		handler := GRPCClientIP(proxies)(func(ctx context.Context, req interface{}) (interface{}, error) {
+			got = biz.ClientIPFromContext(ctx)
+			return nil, nil
+		})
+		_, _ = handler(ctx, nil)
+		return got
+	}
+	withPeer := func(addr string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 5678}})
+	}
+
+	assert.Equal(t, "203.0.113.7", call(withPeer("203.0.113.7")))
+
+	ctx := transport.NewServerContext(withPeer("10.0.0.2"), grpcTransport{header: headerCarrier{realIPHeader: "203.0.113.7"}})
+	assert.Equal(t, "203.0.113.7", call(ctx))
+
+	ctx = transport.NewServerContext(withPeer("198.51.100.1"), grpcTransport{header: headerCarrier{realIPHeader: "203.0.113.7"}})
+	assert.Equal(t, "198.51.100.1", call(ctx))
+
+	assert.Empty(t, call(context.Background()))
+}
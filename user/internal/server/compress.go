@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+
+	"user/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// defaultCompressionMinSize 默认压缩阈值，小于该字节数的响应不压缩
+const defaultCompressionMinSize = 1024
+
+// compressedContentTypes 本身已压缩的内容类型，再次压缩没有收益
+var compressedContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+}
+
+// Compression gzip 响应压缩过滤器
+// 客户端声明支持 gzip 且响应体不小于阈值时压缩，已压缩的内容类型或已设置 Content-Encoding 的响应原样返回
+// 响应体达到阈值前先缓冲，达到后边压缩边写出，不在内存中保留完整响应；处理函数调用 Flush 时立即决定是否压缩并刷新到客户端
+func Compression(c *conf.Server_Compression) http.FilterFunc {
+	minSize := defaultCompressionMinSize
+	if c.GetMinSize() > 0 {
+		minSize = int(c.GetMinSize())
+	}
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{ResponseWriter: w, status: nethttp.StatusOK, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptsGzip 判断 Accept-Encoding 是否允许 gzip（忽略 q=0 的声明）
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		// 内容编码名称大小写不敏感
+		coding, params, _ := strings.Cut(strings.ToLower(strings.TrimSpace(part)), ";")
+		coding = strings.TrimSpace(coding)
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isCompressedContentType 判断内容类型是否已经是压缩格式
+func isCompressedContentType(contentType string) bool {
+	for _, prefix := range compressedContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressResponseWriter 缓冲响应体直到可以决定是否压缩，决定后按 gzip 或原样流式写出
+type compressResponseWriter struct {
+	nethttp.ResponseWriter
+	status  int
+	minSize int
+	buf     bytes.Buffer
+	// started 是否已写出响应头，之后的写入不再缓冲
+	started bool
+	// gz 决定压缩后的 gzip 输出，不压缩时为空
+	gz *gzip.Writer
+}
+
+// WriteHeader 记录状态码，延迟到决定是否压缩时写出
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if !w.started {
+		w.status = status
+	}
+}
+
+// Write 未决定前写入缓冲区，缓冲达到阈值时开始压缩输出
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	if w.started {
+		if w.gz != nil {
+			return w.gz.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	n, _ := w.buf.Write(p)
+	if w.buf.Len() >= w.minSize {
+		if err := w.start(w.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// Flush 将已写入的内容刷新到客户端；未达到阈值的流式响应也按内容类型压缩，因为此时无法得知最终大小
+func (w *compressResponseWriter) Flush() {
+	if !w.started {
+		if err := w.start(w.compressible()); err != nil {
+			return
+		}
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(nethttp.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 返回底层 ResponseWriter，供 http.ResponseController 使用
+func (w *compressResponseWriter) Unwrap() nethttp.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// compressible 根据已设置的响应头判断是否可以压缩
+func (w *compressResponseWriter) compressible() bool {
+	header := w.ResponseWriter.Header()
+	return header.Get("Content-Encoding") == "" && !isCompressedContentType(header.Get("Content-Type"))
+}
+
+// start 写出响应头和已缓冲的内容，之后的写入直接输出
+func (w *compressResponseWriter) start(compress bool) error {
+	w.started = true
+	if compress {
+		header := w.ResponseWriter.Header()
+		header.Set("Content-Encoding", "gzip")
+		// 压缩后长度未知，以分块方式传输
+		header.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(w.status)
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+		_, err := w.gz.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return err
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// close 处理结束后写出未达到阈值的响应，或结束 gzip 输出
+func (w *compressResponseWriter) close() {
+	if !w.started {
+		// 整个响应都小于阈值，原样写出
+		_ = w.start(false)
+		return
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
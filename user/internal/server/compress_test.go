@@ -0,0 +1,205 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"user/internal/biz"
+	"user/internal/conf"
+	"user/internal/service"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompression 测试 gzip 响应压缩
+func TestCompression(t *testing.T) {
+	// 构造一个足够大的 JSON 响应
+	items := make([]map[string]interface{}, 200)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": i, "type": "RECHARGE", "description": "活动赠送"}
+	}
+	largeBody, err := json.Marshal(map[string]interface{}{"transactions": items})
+	require.NoError(t, err)
+
+	newServer := func() *http.Server {
+		srv := http.NewServer(http.Filter(Compression(&conf.Server_Compression{Enabled: true, MinSize: 1024})))
+		srv.HandleFunc("/large", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(largeBody)
+		})
+		srv.HandleFunc("/small", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		})
+		srv.HandleFunc("/image", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(largeBody)
+		})
+		return srv
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		wantGzip       bool
+	}{
+		{
+			name:           "客户端支持gzip时压缩大响应",
+			path:           "/large",
+			acceptEncoding: "gzip, deflate, br",
+			wantGzip:       true,
+		},
+		{
+			name:           "客户端未声明gzip时不压缩",
+			path:           "/large",
+			acceptEncoding: "",
+			wantGzip:       false,
+		},
+		{
+			name:           "客户端显式拒绝gzip时不压缩",
+			path:           "/large",
+			acceptEncoding: "gzip;q=0",
+			wantGzip:       false,
+		},
+		{
+			name:           "小于阈值的响应不压缩",
+			path:           "/small",
+			acceptEncoding: "gzip",
+			wantGzip:       false,
+		},
+		{
+			name:           "已压缩的内容类型不重复压缩",
+			path:           "/image",
+			acceptEncoding: "gzip",
+			wantGzip:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(nethttp.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			newServer().ServeHTTP(rec, req)
+
+			assert.Equal(t, nethttp.StatusOK, rec.Code)
+			assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+
+			if !tt.wantGzip {
+				assert.Empty(t, rec.Header().Get("Content-Encoding"))
+				return
+			}
+
+			assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+			assert.Less(t, rec.Body.Len(), len(largeBody))
+
+			gz, err := gzip.NewReader(rec.Body)
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			assert.Equal(t, string(largeBody), string(decoded))
+		})
+	}
+}
+
+// TestAcceptsGzip 测试 Accept-Encoding 解析
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("deflate, gzip;q=0.8"))
+	assert.True(t, acceptsGzip("*"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("br, deflate"))
+	assert.False(t, acceptsGzip(strings.ToUpper("gzip;q=0")))
+}
+
+// blockingTransactionRepository 第一页立即返回，之后的页等待 release 关闭，用于观察第一页是否在导出结束前送达客户端
+type blockingTransactionRepository struct {
+	biz.PointTransactionRepository
+	firstPage []*biz.PointTransaction
+	release   chan struct{}
+}
+
+func (r *blockingTransactionRepository) GetByUserID(ctx context.Context, userID int64, page, pageSize int) ([]*biz.PointTransaction, int64, error) {
+	total := int64(len(r.firstPage) + 1)
+	if page == 1 {
+		return r.firstPage, total, nil
+	}
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	return []*biz.PointTransaction{{ID: total, Type: biz.TransactionTypeRecharge, Amount: 1}}, total, nil
+}
+
+// TestCompression_StreamingExport 测试经过压缩过滤器的 CSV 导出仍逐页刷新到客户端，不等整个导出完成
+func TestCompression_StreamingExport(t *testing.T) {
+	firstPage := make([]*biz.PointTransaction, 500)
+	for i := range firstPage {
+		firstPage[i] = &biz.PointTransaction{ID: int64(i + 1), Type: biz.TransactionTypeRecharge, Amount: 10, Description: "活动赠送"}
+	}
+	repo := &blockingTransactionRepository{firstPage: firstPage, release: make(chan struct{})}
+	slowOp := biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger)
+	uc := biz.NewPointUsecase(nil, repo, nil, biz.Pagination{}, biz.PointConfig{}, slowOp, log.DefaultLogger)
+	svc := service.NewPointService(uc, biz.AuthConfig{}, log.DefaultLogger)
+
+	srv := http.NewServer(http.Filter(Compression(&conf.Server_Compression{Enabled: true, MinSize: 1024})))
+	srv.Route("/").GET("/v1/points/transactions/export", svc.ExportTransactionsCSV)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	req, err := nethttp.NewRequest(nethttp.MethodGet, ts.URL+"/v1/points/transactions/export", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("X-User-ID", "1")
+	client := &nethttp.Client{Transport: &nethttp.Transport{DisableCompression: true}}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, nethttp.StatusOK, resp.StatusCode)
+	require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+
+	gz, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	lines := bufio.NewScanner(gz)
+
+	// 第二页仍在等待时，第一页必须已经可以解压读取
+	firstPageRead := make(chan int, 1)
+	go func() {
+		count := 0
+		for count < len(firstPage)+1 && lines.Scan() {
+			count++
+		}
+		firstPageRead <- count
+	}()
+	select {
+	case count := <-firstPageRead:
+		assert.Equal(t, len(firstPage)+1, count, "表头和第一页流水")
+	case <-time.After(5 * time.Second):
+		close(repo.release)
+		t.Fatal("first page was not flushed to the client before the export finished")
+	}
+
+	close(repo.release)
+	rest := 0
+	for lines.Scan() {
+		rest++
+	}
+	require.NoError(t, lines.Err())
+	assert.Equal(t, 1, rest)
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	stdjson "encoding/json"
+	nethttp "net/http"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/encoding/json"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+)
+
+// FieldViolationDetails 将错误 metadata 中的字段级校验失败转换为 gRPC 状态的 BadRequest 详情
+// 需作为最外层拦截器注册（grpc.UnaryInterceptor），在 Kratos 中间件处理完错误之后执行；
+// Kratos 客户端仍可从 ErrorInfo 详情中解析出原有的错误原因和 metadata
+func FieldViolationDetails(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	reply, err := handler(ctx, req)
+	if err == nil {
+		return reply, nil
+	}
+	violations := biz.FieldViolationsFromError(err)
+	if len(violations) == 0 {
+		return reply, err
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	st, detailErr := errors.FromError(err).GRPCStatus().WithDetails(badRequest)
+	if detailErr != nil {
+		return reply, err
+	}
+	return reply, st.Err()
+}
+
+// fieldViolationErrorResponse 带字段级校验失败的 HTTP 错误响应，在 Kratos 默认错误结构上增加 details 数组
+type fieldViolationErrorResponse struct {
+	Code     int32                `json:"code"`
+	Reason   string               `json:"reason"`
+	Message  string               `json:"message"`
+	Metadata map[string]string    `json:"metadata"`
+	Details  []biz.FieldViolation `json:"details"`
+}
+
+// FieldViolationErrorEncoder 错误带有字段级校验失败时，将其从 metadata 移到响应的 details 数组中
+// 其他错误和非 JSON 编码的请求保持默认行为
+func FieldViolationErrorEncoder(w nethttp.ResponseWriter, r *nethttp.Request, err error) {
+	violations := biz.FieldViolationsFromError(err)
+	codec, _ := http.CodecForRequest(r, "Accept")
+	if len(violations) == 0 || codec.Name() != json.Name {
+		http.DefaultErrorEncoder(w, r, err)
+		return
+	}
+
+	se := errors.FromError(err)
+	metadata := make(map[string]string, len(se.Metadata))
+	for k, v := range se.Metadata {
+		if k != biz.MetadataFieldViolations {
+			metadata[k] = v
+		}
+	}
+	body, err := stdjson.Marshal(&fieldViolationErrorResponse{
+		Code:     se.Code,
+		Reason:   se.Reason,
+		Message:  se.Message,
+		Metadata: metadata,
+		Details:  violations,
+	})
+	if err != nil {
+		w.WriteHeader(nethttp.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(se.Code))
+	_, _ = w.Write(body)
+}
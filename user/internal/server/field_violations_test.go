@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	stdjson "encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	error_reason "user/api/error_reason"
+	"user/internal/biz"
+	tracingpkg "user/internal/pkg/tracing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newMultiFieldValidationError 构造两个字段同时校验失败的错误
+func newMultiFieldValidationError() error {
+	var v biz.ValidationError
+	v.Add("password", error_reason.ErrorUserInvalidRequest("密码长度至少8位"))
+	v.Add("email", error_reason.ErrorUserInvalidEmail("邮箱格式不正确"))
+	return v.Err()
+}
+
+// TestFieldViolationDetails 测试多字段校验失败经过错误增强中间件后，每个字段都写入 gRPC 状态的 BadRequest 详情
+func TestFieldViolationDetails(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return tracingpkg.GRPCErrorResponseEnhancer(false)(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, newMultiFieldValidationError()
+		})(ctx, req)
+	}
+
+	_, err := FieldViolationDetails(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	var badRequest *errdetails.BadRequest
+	var errorInfo *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			badRequest = d
+		case *errdetails.ErrorInfo:
+			errorInfo = d
+		}
+	}
+	require.NotNil(t, badRequest)
+	require.Len(t, badRequest.FieldViolations, 2)
+	assert.Equal(t, "password", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, "密码长度至少8位", badRequest.FieldViolations[0].Description)
+	assert.Equal(t, "email", badRequest.FieldViolations[1].Field)
+	assert.Equal(t, "邮箱格式不正确", badRequest.FieldViolations[1].Description)
+	// Kratos 客户端依赖 ErrorInfo 还原错误原因
+	require.NotNil(t, errorInfo)
+	assert.Equal(t, error_reason.UserErrorReason_USER_INVALID_REQUEST.String(), errorInfo.Reason)
+	assert.True(t, error_reason.IsUserInvalidRequest(errors.FromError(err)))
+}
+
+// TestFieldViolationDetails_Passthrough 测试不带字段级校验失败的错误原样返回
+func TestFieldViolationDetails_Passthrough(t *testing.T) {
+	want := error_reason.ErrorUserNotFound("用户不存在")
+	_, err := FieldViolationDetails(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, want
+	})
+	assert.Same(t, want, err)
+}
+
+// TestFieldViolationErrorEncoder 测试多字段校验失败输出到 HTTP 错误响应的 details 数组
+func TestFieldViolationErrorEncoder(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantDetails []biz.FieldViolation
+	}{
+		{
+			name:       "多字段校验失败",
+			err:        newMultiFieldValidationError(),
+			wantStatus: nethttp.StatusBadRequest,
+			wantDetails: []biz.FieldViolation{
+				{Field: "password", Description: "密码长度至少8位"},
+				{Field: "email", Description: "邮箱格式不正确"},
+			},
+		},
+		{
+			name:       "普通错误保持默认响应",
+			err:        error_reason.ErrorUserNotFound("用户不存在"),
+			wantStatus: nethttp.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(nethttp.MethodPost, "/v1/auth/register", nil)
+			req.Header.Set("Accept", "application/json")
+			rec := httptest.NewRecorder()
+
+			FieldViolationErrorEncoder(rec, req, tt.err)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var body struct {
+				Reason   string               `json:"reason"`
+				Metadata map[string]string    `json:"metadata"`
+				Details  []biz.FieldViolation `json:"details"`
+			}
+			require.NoError(t, stdjson.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, errors.FromError(tt.err).Reason, body.Reason)
+			assert.Equal(t, tt.wantDetails, body.Details)
+			assert.NotContains(t, body.Metadata, biz.MetadataFieldViolations)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	nethttp "net/http"
+
+	"github.com/go-kratos/kratos/v2/encoding/json"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/protobuf/proto"
+)
+
+// fieldsQueryParam 响应字段筛选（sparse fieldsets）使用的查询参数
+const fieldsQueryParam = "fields"
+
+// SparseFieldsResponseEncoder 请求带有 fields 参数时，JSON 响应中省略未填充的字段
+// 默认编码会输出全部字段的零值，服务层清除的未选字段因此仍会出现在响应里；
+// 未带 fields 参数或非 JSON 编码的请求保持默认行为
+func SparseFieldsResponseEncoder(w nethttp.ResponseWriter, r *nethttp.Request, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok || r.URL.Query().Get(fieldsQueryParam) == "" {
+		return http.DefaultResponseEncoder(w, r, v)
+	}
+	codec, _ := http.CodecForRequest(r, "Accept")
+	if codec.Name() != json.Name {
+		return http.DefaultResponseEncoder(w, r, v)
+	}
+
+	opts := json.MarshalOptions
+	opts.EmitUnpopulated = false
+	data, err := opts.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "user/api/user/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSparseFieldsResponseEncoder 测试带 fields 参数时省略未填充字段，否则保持默认的全量输出
+func TestSparseFieldsResponseEncoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		wantKeys []string
+	}{
+		{
+			name:     "指定 fields 时只输出已填充字段",
+			target:   "/v1/user/profile?fields=id,nickname",
+			wantKeys: []string{"id", "nickname"},
+		},
+		{
+			name:     "未指定 fields 时输出全部字段",
+			target:   "/v1/user/profile",
+			wantKeys: []string{"id", "publicId", "email", "nickname", "avatarUrl", "isPremium", "createdAt", "updatedAt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(nethttp.MethodGet, tt.target, nil)
+
+			err := SparseFieldsResponseEncoder(w, r, &v1.GetCurrentUserResponse{Id: 1, Nickname: "tester"})
+
+			require.NoError(t, err)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			keys := make([]string, 0, len(body))
+			for key := range body {
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, tt.wantKeys, keys)
+			assert.Equal(t, "1", body["id"])
+			assert.Equal(t, "tester", body["nickname"])
+		})
+	}
+}
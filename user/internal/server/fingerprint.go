@@ -0,0 +1,23 @@
+package server
+
+import (
+	nethttp "net/http"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// ClientFingerprint 计算客户端指纹并与客户端 IP 一同写入请求 context，供签发和校验访问令牌时绑定客户端、按 IP 统计注册请求
+// 只有对端为可信代理时才采用请求头中的客户端 IP，否则请求头可被伪造，只使用连接的对端地址
+// 需要排在 UserIdentity 之前，使进程内校验令牌时能读到指纹
+func ClientFingerprint(proxies *TrustedProxies) http.FilterFunc {
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			ip := clientIP(r.RemoteAddr, r.Header, proxies)
+			ctx := biz.WithClientIP(r.Context(), ip)
+			ctx = biz.WithClientFingerprint(ctx, biz.ClientFingerprint(ip, r.UserAgent()))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
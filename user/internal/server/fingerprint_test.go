@@ -0,0 +1,61 @@
+package server
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"user/internal/biz"
+	"user/internal/conf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClientFingerprint 测试不同部署模式下计算客户端指纹及写入 context 的 IP
+func TestClientFingerprint(t *testing.T) {
+	tests := []struct {
+		name           string
+		internetFacing bool
+		realIP         string
+		wantIP         string
+	}{
+		{
+			name:           "网关模式使用X-Real-IP",
+			internetFacing: false,
+			realIP:         "5.6.7.8",
+			wantIP:         "5.6.7.8",
+		},
+		{
+			name:           "网关模式缺少X-Real-IP时使用对端地址",
+			internetFacing: false,
+			wantIP:         "1.2.3.4",
+		},
+		{
+			name:           "公网模式忽略伪造的X-Real-IP",
+			internetFacing: true,
+			realIP:         "5.6.7.8",
+			wantIP:         "1.2.3.4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, gotIP string
+			handler := ClientFingerprint(NewTrustedProxies(&conf.Server{InternetFacing: tt.internetFacing}))(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				got = biz.ClientFingerprintFromContext(r.Context())
+				gotIP = biz.ClientIPFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/v1/users/me", nil)
+			req.RemoteAddr = "1.2.3.4:5678"
+			req.Header.Set("User-Agent", "test-agent")
+			if tt.realIP != "" {
+				req.Header.Set(realIPHeader, tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, biz.ClientFingerprint(tt.wantIP, "test-agent"), got)
+			assert.Equal(t, tt.wantIP, gotIP)
+		})
+	}
+}
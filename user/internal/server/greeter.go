@@ -0,0 +1,28 @@
+//go:build !nogreeter
+
+package server
+
+import (
+	helloworldv1 "user/api/helloworld/v1"
+	"user/internal/conf"
+	"user/internal/service"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// registerGreeterHTTP 配置开启时注册示例 Greeter HTTP 接口
+// Greeter 不在依赖注入图中，使用 nogreeter 构建标签时由 greeter_disabled.go 替代
+func registerGreeterHTTP(c *conf.Server, srv *http.Server, logger log.Logger) {
+	if greeterService := service.NewGreeterService(c, logger); greeterService != nil {
+		helloworldv1.RegisterGreeterHTTPServer(srv, greeterService)
+	}
+}
+
+// registerGreeterGRPC 配置开启时注册示例 Greeter gRPC 接口
+func registerGreeterGRPC(c *conf.Server, srv *grpc.Server, logger log.Logger) {
+	if greeterService := service.NewGreeterService(c, logger); greeterService != nil {
+		helloworldv1.RegisterGreeterServer(srv, greeterService)
+	}
+}
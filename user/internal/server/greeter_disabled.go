@@ -0,0 +1,25 @@
+//go:build nogreeter
+
+package server
+
+import (
+	"user/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// registerGreeterHTTP 使用 nogreeter 构建时 Greeter 未编译进二进制，忽略 enable_greeter 配置
+func registerGreeterHTTP(c *conf.Server, srv *http.Server, logger log.Logger) {
+	if c.GetEnableGreeter() {
+		log.NewHelper(logger).Warn("enable_greeter is set but the binary was built with the nogreeter tag, Greeter HTTP endpoints are not registered")
+	}
+}
+
+// registerGreeterGRPC 使用 nogreeter 构建时 Greeter 未编译进二进制，忽略 enable_greeter 配置
+func registerGreeterGRPC(c *conf.Server, srv *grpc.Server, logger log.Logger) {
+	if c.GetEnableGreeter() {
+		log.NewHelper(logger).Warn("enable_greeter is set but the binary was built with the nogreeter tag, Greeter gRPC service is not registered")
+	}
+}
@@ -0,0 +1,35 @@
+//go:build nogreeter
+
+package server
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"user/internal/conf"
+	"user/internal/service"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGreeterCompiledOut 测试使用 nogreeter 构建时即使配置开启也不注册 Greeter，其余服务正常注册
+func TestGreeterCompiledOut(t *testing.T) {
+	c := &conf.Server{
+		Http:          &conf.Server_HTTP{},
+		Grpc:          &conf.Server_GRPC{},
+		EnableGreeter: true,
+	}
+
+	httpSrv := NewHTTPServer(c, &service.AuthService{}, &service.UserService{}, &service.PointService{}, log.DefaultLogger)
+	rec := httptest.NewRecorder()
+	httpSrv.ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/helloworld/kratos", nil))
+	assert.Equal(t, nethttp.StatusNotFound, rec.Code)
+
+	grpcSrv := NewGRPCServer(c, &service.AuthService{}, &service.UserService{}, &service.PointService{}, log.DefaultLogger)
+	_, registered := grpcSrv.GetServiceInfo()["helloworld.v1.Greeter"]
+	assert.False(t, registered)
+	_, authRegistered := grpcSrv.GetServiceInfo()["auth.v1.AuthService"]
+	assert.True(t, authRegistered)
+}
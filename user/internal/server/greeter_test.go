@@ -0,0 +1,55 @@
+//go:build !nogreeter
+
+package server
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"user/internal/conf"
+	"user/internal/service"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGreeterRegistration 测试 Greeter 接口按配置注册
+func TestGreeterRegistration(t *testing.T) {
+	tests := []struct {
+		name          string
+		enableGreeter bool
+		wantStatus    int
+	}{
+		{
+			name:          "开启时注册Greeter",
+			enableGreeter: true,
+			wantStatus:    nethttp.StatusOK,
+		},
+		{
+			name:          "关闭时不注册Greeter",
+			enableGreeter: false,
+			wantStatus:    nethttp.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &conf.Server{
+				Http:          &conf.Server_HTTP{},
+				Grpc:          &conf.Server_GRPC{},
+				EnableGreeter: tt.enableGreeter,
+			}
+			httpSrv := NewHTTPServer(c, &service.AuthService{}, &service.UserService{}, &service.PointService{}, log.DefaultLogger)
+			rec := httptest.NewRecorder()
+			httpSrv.ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/helloworld/kratos", nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			grpcSrv := NewGRPCServer(c, &service.AuthService{}, &service.UserService{}, &service.PointService{}, log.DefaultLogger)
+			_, registered := grpcSrv.GetServiceInfo()["helloworld.v1.Greeter"]
+			assert.Equal(t, tt.enableGreeter, registered)
+			_, authRegistered := grpcSrv.GetServiceInfo()["auth.v1.AuthService"]
+			assert.True(t, authRegistered)
+		})
+	}
+}
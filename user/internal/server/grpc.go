@@ -2,6 +2,7 @@ package server
 
 import (
 	authv1 "user/api/auth/v1"
+	pointv1 "user/api/point/v1"
 	userv1 "user/api/user/v1"
 	"user/internal/conf"
 	tracingpkg "user/internal/pkg/tracing"
@@ -11,16 +12,25 @@ import (
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
 	"github.com/go-kratos/kratos/v2/middleware/tracing"
 	"github.com/go-kratos/kratos/v2/transport/grpc"
+	ggrpc "google.golang.org/grpc"
 )
 
 // NewGRPCServer new a gRPC server.
-func NewGRPCServer(c *conf.Server, authService *service.AuthService, userService *service.UserService, logger log.Logger) *grpc.Server {
+func NewGRPCServer(c *conf.Server, authService *service.AuthService, userService *service.UserService, pointService *service.PointService, logger log.Logger) *grpc.Server {
 	var opts = []grpc.ServerOption{
 		grpc.Middleware(
+			// 排在 recovery 之前，panic 恢复成的 5xx 同样计入耗时指标
+			RequestLatency(nil, logger),
 			recovery.Recovery(),
 			tracing.Server(),
-			tracingpkg.GRPCErrorResponseEnhancer(), // 添加错误响应增强中间件
+			tracingpkg.GRPCErrorResponseEnhancer(c.ExposeErrorDetails), // 添加错误响应增强中间件
+			// 客户端 IP 用于注册频率统计和会话 IP 记录
+			GRPCClientIP(NewTrustedProxies(c)),
+			// 按配置拒绝或记录缺少追踪上下文的请求
+			RequireTraceContext(c.TraceContextPolicy, logger),
 		),
+		// 字段级校验失败转换为 BadRequest 详情，需在全部中间件之外执行
+		grpc.Options(ggrpc.UnaryInterceptor(FieldViolationDetails)),
 	}
 	if c.Grpc.Network != "" {
 		opts = append(opts, grpc.Network(c.Grpc.Network))
@@ -34,5 +44,8 @@ func NewGRPCServer(c *conf.Server, authService *service.AuthService, userService
 	srv := grpc.NewServer(opts...)
 	authv1.RegisterAuthServiceServer(srv, authService)
 	userv1.RegisterUserServiceServer(srv, userService)
+	pointv1.RegisterPointServiceServer(srv, pointService)
+	// 示例 Greeter 接口仅在配置开启时注册，生产环境应关闭
+	registerGreeterGRPC(c, srv, logger)
 	return srv
 }
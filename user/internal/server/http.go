@@ -2,6 +2,7 @@ package server
 
 import (
 	authv1 "user/api/auth/v1"
+	pointv1 "user/api/point/v1"
 	userv1 "user/api/user/v1"
 	"user/internal/conf"
 	tracingpkg "user/internal/pkg/tracing"
@@ -14,13 +15,21 @@ import (
 )
 
 // NewHTTPServer new an HTTP server.
-func NewHTTPServer(c *conf.Server, authService *service.AuthService, userService *service.UserService, logger log.Logger) *http.Server {
+func NewHTTPServer(c *conf.Server, authService *service.AuthService, userService *service.UserService, pointService *service.PointService, logger log.Logger) *http.Server {
 	var opts = []http.ServerOption{
 		http.Middleware(
+			// 排在 recovery 之前，panic 恢复成的 5xx 同样计入耗时指标
+			RequestLatency(nil, logger),
 			recovery.Recovery(),
 			tracing.Server(),
-			tracingpkg.HTTPErrorResponseEnhancer(), // 添加错误响应增强中间件
+			tracingpkg.HTTPErrorResponseEnhancer(c.ExposeErrorDetails), // 添加错误响应增强中间件
+			// 按配置拒绝或记录缺少追踪上下文的请求
+			RequireTraceContext(c.TraceContextPolicy, logger),
 		),
+		// 支持 fields 查询参数按需返回字段
+		http.ResponseEncoder(SparseFieldsResponseEncoder),
+		// 字段级校验失败输出到错误响应的 details 数组
+		http.ErrorEncoder(FieldViolationErrorEncoder),
 	}
 	if c.Http.Network != "" {
 		opts = append(opts, http.Network(c.Http.Network))
@@ -31,8 +40,40 @@ func NewHTTPServer(c *conf.Server, authService *service.AuthService, userService
 	if c.Http.Timeout != nil {
 		opts = append(opts, http.Timeout(c.Http.Timeout.AsDuration()))
 	}
+	// http.Filter 会覆盖之前设置的过滤器，需要一次性传入
+	var filters []http.FilterFunc
+	// 客户端指纹用于访问令牌绑定（auth.bind_token_to_client），需在校验令牌之前计算
+	filters = append(filters, ClientFingerprint(NewTrustedProxies(c)))
+	// 直接面向公网时不信任客户端传入的 X-User-ID，改为在进程内校验访问令牌或个人访问令牌
+	filters = append(filters, UserIdentity(c.InternetFacing, authService.ValidateAccessToken, userService.ValidatePersonalToken))
+	if c.Http.SecurityHeaders != nil {
+		filters = append(filters, SecurityHeaders(c.Http.SecurityHeaders))
+	}
+	if c.Http.Compression.GetEnabled() {
+		filters = append(filters, Compression(c.Http.Compression))
+	}
+	opts = append(opts, http.Filter(filters...))
 	srv := http.NewServer(opts...)
 	authv1.RegisterAuthServiceHTTPServer(srv, authService)
 	userv1.RegisterUserServiceHTTPServer(srv, userService)
+	pointv1.RegisterPointServiceHTTPServer(srv, pointService)
+	// CSV 导出需要流式写响应，不经过 proto 定义，直接注册路由
+	srv.Route("/").GET("/v1/points/transactions/export", pointService.ExportTransactionsCSV)
+	// 用户数据导出返回完整的 biz.UserExport JSON，不经过 proto 定义，直接注册路由
+	srv.Route("/").GET("/v1/user/data-export", userService.ExportUserData)
+	// 按注册时间查询用户只用于管理员做注册批次分析，返回 JSON 分页结果，不经过 proto 定义，直接注册路由
+	srv.Route("/").GET("/v1/admin/users/registered", userService.ListRegisteredUsers)
+	// 重复账号的查询、合并和账号删除只用于管理员维护历史数据，不经过 proto 定义，直接注册路由
+	srv.Route("/").GET("/v1/admin/users/duplicate-emails", userService.ListDuplicateEmails)
+	srv.Route("/").POST("/v1/admin/users/merge", userService.MergeAccounts)
+	srv.Route("/").DELETE("/v1/admin/users/{id}", userService.DeleteAccount)
+	// 网关令牌校验只需状态码和用户ID响应头，不经过 proto 定义，直接注册路由
+	srv.Route("/").POST("/v1/auth/verify", authService.VerifyToken)
+	// 测试邮件只用于管理员排查邮件投递，返回诊断结果，不经过 proto 定义，直接注册路由
+	srv.Route("/").POST("/debug/test-email", userService.SendTestEmail)
+	// 邮件预览只用于管理员调整邮件模板，返回渲染结果，不经过 proto 定义，直接注册路由
+	srv.Route("/").GET("/debug/email-preview", userService.PreviewEmail)
+	// 示例 Greeter 接口仅在配置开启时注册，生产环境应关闭
+	registerGreeterHTTP(c, srv, logger)
 	return srv
 }
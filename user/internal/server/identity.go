@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// userIDHeader 网关校验 JWT 后写入的用户ID请求头，service.ExtractUserID 从中读取当前用户
+const userIDHeader = "X-User-ID"
+
+// TokenValidator 校验访问令牌并返回用户ID
+type TokenValidator func(ctx context.Context, accessToken string) (int64, error)
+
+// PersonalTokenValidator 校验个人访问令牌是否具有 requiredScope，返回令牌所属用户ID
+type PersonalTokenValidator func(ctx context.Context, plaintext, requiredScope string) (int64, error)
+
+// personalTokenRoutes 允许使用个人访问令牌的接口及所需权限范围，键为 "方法 路径"
+// 未列出的接口（包括令牌管理和管理员接口）只接受会话访问令牌，避免个人访问令牌创建新令牌或越权
+var personalTokenRoutes = map[string]string{
+	nethttp.MethodGet + " /v1/user/profile":               biz.PersonalTokenScopeUserRead,
+	nethttp.MethodPut + " /v1/user/profile":               biz.PersonalTokenScopeUserWrite,
+	nethttp.MethodGet + " /v1/points/transactions":        biz.PersonalTokenScopePointsRead,
+	nethttp.MethodGet + " /v1/points/transactions/export": biz.PersonalTokenScopePointsRead,
+}
+
+// UserIdentity 用户身份过滤器
+// 部署在网关之后（trusted）时 X-User-ID 由网关写入，原样保留；
+// 直接面向公网（untrusted）时客户端可以伪造该请求头，因此一律删除，只在 Bearer 令牌校验通过后重新写入。
+// 带 PersonalTokenPrefix 前缀的 Bearer 令牌按个人访问令牌校验，接口须在 personalTokenRoutes 中且令牌具有对应权限范围
+func UserIdentity(internetFacing bool, validate TokenValidator, validatePersonal PersonalTokenValidator) http.FilterFunc {
+	return func(next nethttp.Handler) nethttp.Handler {
+		if !internetFacing {
+			return next
+		}
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			r.Header.Del(userIDHeader)
+			if token, ok := bearerToken(r); ok {
+				if userID, ok := authenticate(r, token, validate, validatePersonal); ok {
+					r.Header.Set(userIDHeader, strconv.FormatInt(userID, 10))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// authenticate 按令牌类型校验 Bearer 令牌，返回用户ID
+func authenticate(r *nethttp.Request, token string, validate TokenValidator, validatePersonal PersonalTokenValidator) (int64, bool) {
+	if !strings.HasPrefix(token, biz.PersonalTokenPrefix) {
+		userID, err := validate(r.Context(), token)
+		return userID, err == nil
+	}
+	scope, ok := personalTokenRoutes[r.Method+" "+r.URL.Path]
+	if !ok || validatePersonal == nil {
+		return 0, false
+	}
+	userID, err := validatePersonal(r.Context(), token, scope)
+	return userID, err == nil
+}
+
+// bearerToken 从 Authorization 请求头中取出 Bearer 令牌
+func bearerToken(r *nethttp.Request) (string, bool) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(auth[len(prefix):]), true
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	userv1 "user/api/user/v1"
+	"user/internal/biz"
+	"user/internal/service"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestUserIdentity 测试不同部署模式下对 X-User-ID 请求头的处理
+func TestUserIdentity(t *testing.T) {
+	validate := func(ctx context.Context, token string) (int64, error) {
+		if token == "valid-token" {
+			return 42, nil
+		}
+		return 0, errors.New("invalid token")
+	}
+
+	tests := []struct {
+		name           string
+		internetFacing bool
+		userIDHeader   string
+		authorization  string
+		wantUserID     string
+	}{
+		{
+			name:           "公网模式删除伪造的X-User-ID",
+			internetFacing: true,
+			userIDHeader:   "1",
+			wantUserID:     "",
+		},
+		{
+			name:           "公网模式使用令牌中的用户ID",
+			internetFacing: true,
+			userIDHeader:   "1",
+			authorization:  "Bearer valid-token",
+			wantUserID:     "42",
+		},
+		{
+			name:           "公网模式令牌无效时不设置用户ID",
+			internetFacing: true,
+			userIDHeader:   "1",
+			authorization:  "Bearer forged-token",
+			wantUserID:     "",
+		},
+		{
+			name:           "网关模式保留X-User-ID",
+			internetFacing: false,
+			userIDHeader:   "1",
+			authorization:  "Bearer valid-token",
+			wantUserID:     "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID string
+			handler := UserIdentity(tt.internetFacing, validate, nil)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				gotUserID = r.Header.Get("X-User-ID")
+			}))
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/v1/users/me", nil)
+			req.Header.Set("X-User-ID", tt.userIDHeader)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantUserID, gotUserID)
+		})
+	}
+}
+
+// identityTokenRepository 按哈希返回固定个人访问令牌的令牌数据访问
+type identityTokenRepository struct {
+	biz.PersonalTokenRepository
+	tokens map[string]*biz.PersonalToken
+}
+
+func (r *identityTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*biz.PersonalToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return token, nil
+}
+
+func (r *identityTokenRepository) TouchLastUsed(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	return nil
+}
+
+// identityUserRepository 只返回一个固定用户的用户数据访问
+type identityUserRepository struct {
+	biz.UserRepository
+	user *biz.User
+}
+
+func (r *identityUserRepository) GetByIDPublic(ctx context.Context, id int64) (*biz.User, error) {
+	if id != r.user.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.user, nil
+}
+
+// TestUserIdentity_PersonalToken 测试公网模式下个人访问令牌经过滤器认证后可以访问其权限范围内的接口，
+// 缺少权限范围、未开放个人访问令牌的接口以及无效令牌均返回 401
+func TestUserIdentity_PersonalToken(t *testing.T) {
+	readToken := biz.PersonalTokenPrefix + strings.Repeat("a", 64)
+	pointsToken := biz.PersonalTokenPrefix + strings.Repeat("b", 64)
+	tokenRepo := &identityTokenRepository{tokens: map[string]*biz.PersonalToken{
+		biz.HashPersonalToken(readToken):   {ID: 1, UserID: 42, Scopes: biz.PersonalTokenScopeUserRead},
+		biz.HashPersonalToken(pointsToken): {ID: 2, UserID: 42, Scopes: biz.PersonalTokenScopePointsRead},
+	}}
+	userRepo := &identityUserRepository{user: &biz.User{ID: 42, Email: "pat@example.com", Nickname: "令牌用户"}}
+
+	personalTokens := biz.NewPersonalTokenUsecase(tokenRepo, biz.NewSystemClock(), biz.AuthConfig{}, log.DefaultLogger)
+	userUsecase := biz.NewUserUsecase(userRepo, nil, nil, nil, nil, nil, biz.EmailConfig{}, biz.AuthConfig{}, nil, nil, nil, log.DefaultLogger)
+	userService := service.NewUserService(userUsecase, nil, personalTokens, nil, nil, biz.AuthConfig{}, log.DefaultLogger)
+	rejectAccessToken := func(ctx context.Context, token string) (int64, error) {
+		return 0, errors.New("invalid token")
+	}
+
+	srv := http.NewServer(http.Filter(UserIdentity(true, rejectAccessToken, userService.ValidatePersonalToken)))
+	userv1.RegisterUserServiceHTTPServer(srv, userService)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "具有权限范围的令牌读取资料",
+			method:     nethttp.MethodGet,
+			path:       "/v1/user/profile",
+			token:      readToken,
+			wantStatus: nethttp.StatusOK,
+		},
+		{
+			name:       "缺少权限范围的令牌读取资料",
+			method:     nethttp.MethodGet,
+			path:       "/v1/user/profile",
+			token:      pointsToken,
+			wantStatus: nethttp.StatusUnauthorized,
+		},
+		{
+			name:       "令牌管理接口不接受个人访问令牌",
+			method:     nethttp.MethodGet,
+			path:       "/v1/user/tokens",
+			token:      readToken,
+			wantStatus: nethttp.StatusUnauthorized,
+		},
+		{
+			name:       "未知的个人访问令牌",
+			method:     nethttp.MethodGet,
+			path:       "/v1/user/profile",
+			token:      biz.PersonalTokenPrefix + strings.Repeat("c", 64),
+			wantStatus: nethttp.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus == nethttp.StatusOK {
+				assert.Contains(t, rec.Body.String(), "pat@example.com")
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	// requestLatencyMeterName 请求耗时指标所属的 meter
+	requestLatencyMeterName = "user/internal/server"
+	// requestLatencyMetric 请求耗时直方图指标，单位为秒
+	requestLatencyMetric = "server_request_duration_seconds"
+)
+
+// requestLatencyBuckets 直方图分桶边界（秒），覆盖 SLO 关注的 5ms~5s 区间
+var requestLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// RequestLatency 按接口记录请求耗时到直方图指标，HTTP 和 gRPC 服务共用
+// 标签为传输类型（kind）、接口（method）和状态码类别（status_class，如 2xx、4xx、5xx），供 SLO 看板计算分位数
+// meter 为空时使用全局 MeterProvider，未配置导出器时为空操作
+func RequestLatency(meter metric.Meter, logger log.Logger) middleware.Middleware {
+	if meter == nil {
+		meter = otel.Meter(requestLatencyMeterName)
+	}
+	histogram, err := meter.Float64Histogram(requestLatencyMetric,
+		metric.WithUnit("s"),
+		metric.WithDescription("Server request latency by method and status class"),
+		metric.WithExplicitBucketBoundaries(requestLatencyBuckets...))
+	if err != nil {
+		// 指标创建失败不影响请求处理
+		log.NewHelper(logger).Errorf("Failed to create request latency histogram, error_reason: %v", err)
+		histogram = noop.Float64Histogram{}
+	}
+
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			var kind, method string
+			if info, ok := transport.FromServerContext(ctx); ok {
+				kind = info.Kind().String()
+				method = info.Operation()
+			}
+
+			start := time.Now()
+			reply, err := handler(ctx, req)
+			histogram.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+				attribute.String("kind", kind),
+				attribute.String("method", method),
+				attribute.String("status_class", statusClass(err)),
+			))
+			return reply, err
+		}
+	}
+}
+
+// statusClass 返回错误对应的 HTTP 状态码类别，gRPC 错误同样按 Kratos 错误的 HTTP 状态码归类
+func statusClass(err error) string {
+	if err == nil {
+		return "2xx"
+	}
+	return fmt.Sprintf("%dxx", errors.FromError(err).Code/100)
+}
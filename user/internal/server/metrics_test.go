@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// latencyObservation 测试直方图记录的一次观测
+type latencyObservation struct {
+	value float64
+	attrs attribute.Set
+}
+
+// fakeHistogram 记录所有观测的测试直方图
+type fakeHistogram struct {
+	noop.Float64Histogram
+	observations []latencyObservation
+}
+
+func (h *fakeHistogram) Record(_ context.Context, value float64, opts ...metric.RecordOption) {
+	h.observations = append(h.observations, latencyObservation{value: value, attrs: metric.NewRecordConfig(opts).Attributes()})
+}
+
+// fakeMeter 返回 fakeHistogram 的测试 meter
+type fakeMeter struct {
+	noop.Meter
+	histogram *fakeHistogram
+}
+
+func (m *fakeMeter) Float64Histogram(string, ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return m.histogram, nil
+}
+
+// fakeTransport 只提供传输类型和接口名的测试 Transporter
+type fakeTransport struct {
+	transport.Transporter
+	kind      transport.Kind
+	operation string
+}
+
+func (t *fakeTransport) Kind() transport.Kind { return t.kind }
+
+func (t *fakeTransport) Operation() string { return t.operation }
+
+// TestRequestLatency 测试处理请求后按接口和状态码类别记录一次耗时观测
+func TestRequestLatency(t *testing.T) {
+	tests := []struct {
+		name            string
+		kind            transport.Kind
+		err             error
+		wantStatusClass string
+	}{
+		{
+			name:            "HTTP请求成功",
+			kind:            transport.KindHTTP,
+			wantStatusClass: "2xx",
+		},
+		{
+			name:            "业务错误按HTTP状态码归类",
+			kind:            transport.KindHTTP,
+			err:             kerrors.NotFound("USER_NOT_FOUND", "用户不存在"),
+			wantStatusClass: "4xx",
+		},
+		{
+			name:            "gRPC未知错误归为5xx",
+			kind:            transport.KindGRPC,
+			err:             errors.New("boom"),
+			wantStatusClass: "5xx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			histogram := &fakeHistogram{}
+			handler := RequestLatency(&fakeMeter{histogram: histogram}, log.DefaultLogger)(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return "reply", tt.err
+				})
+
+			ctx := transport.NewServerContext(context.Background(), &fakeTransport{
+				kind:      tt.kind,
+				operation: "/api.user.v1.UserService/GetCurrentUser",
+			})
+			reply, err := handler(ctx, nil)
+
+			assert.Equal(t, "reply", reply)
+			assert.Equal(t, tt.err, err)
+			require.Len(t, histogram.observations, 1)
+			observation := histogram.observations[0]
+			assert.GreaterOrEqual(t, observation.value, 0.0)
+			assert.Equal(t, attribute.NewSet(
+				attribute.String("kind", tt.kind.String()),
+				attribute.String("method", "/api.user.v1.UserService/GetCurrentUser"),
+				attribute.String("status_class", tt.wantStatusClass),
+			), observation.attrs)
+		})
+	}
+}
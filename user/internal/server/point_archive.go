@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"user/internal/biz"
+)
+
+var _ transport.Server = (*PointArchiveJob)(nil)
+
+// PointArchiveJob 定时将超过保留期的点数流水移入归档表，随应用启动和停止
+// 未配置 biz.point_transaction_retention 时不执行任何操作
+type PointArchiveJob struct {
+	uc       *biz.PointUsecase
+	interval time.Duration
+	done     chan struct{}
+	log      *log.Helper
+}
+
+// NewPointArchiveJob 创建点数流水定时归档任务
+func NewPointArchiveJob(uc *biz.PointUsecase, logger log.Logger) *PointArchiveJob {
+	return &PointArchiveJob{
+		uc:       uc,
+		interval: uc.ArchiveInterval(),
+		done:     make(chan struct{}),
+		log:      log.NewHelper(logger),
+	}
+}
+
+// Start 按配置的间隔执行归档，直到 Stop 被调用或 ctx 结束
+func (j *PointArchiveJob) Start(ctx context.Context) error {
+	if !j.uc.ArchivingEnabled() {
+		j.log.Info("Point transaction archiving disabled, retention not configured")
+		return nil
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.done:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Stop 停止定时归档，正在执行的批次完成后退出
+func (j *PointArchiveJob) Stop(context.Context) error {
+	select {
+	case <-j.done:
+	default:
+		close(j.done)
+	}
+	return nil
+}
+
+// run 执行一次归档，失败只记录日志，等待下一次执行
+func (j *PointArchiveJob) run(ctx context.Context) {
+	archived, err := j.uc.ArchiveExpiredTransactions(ctx)
+	if err != nil {
+		j.log.WithContext(ctx).Errorf("Scheduled point transaction archiving failed, archived: %d, error_reason: %v", archived, err)
+		return
+	}
+	j.log.WithContext(ctx).Infof("Scheduled point transaction archiving archived %d transactions", archived)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"user/internal/biz"
+)
+
+// archiveRecordingRepository 记录每次归档调用的流水数据访问
+type archiveRecordingRepository struct {
+	biz.PointTransactionRepository
+	calls chan time.Time
+}
+
+func (r *archiveRecordingRepository) ArchiveBefore(_ context.Context, before time.Time, _ int) (int64, error) {
+	r.calls <- before
+	return 0, nil
+}
+
+// directTransaction 直接执行回调的事务
+type directTransaction struct{}
+
+func (directTransaction) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (directTransaction) InTxWithRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestPointArchiveJob(config biz.PointConfig) (*PointArchiveJob, *archiveRecordingRepository) {
+	repo := &archiveRecordingRepository{calls: make(chan time.Time, 16)}
+	slowOp := biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger)
+	uc := biz.NewPointUsecase(nil, repo, directTransaction{}, biz.Pagination{}, config, slowOp, log.DefaultLogger)
+	return NewPointArchiveJob(uc, log.DefaultLogger), repo
+}
+
+// TestPointArchiveJob 测试定时归档按间隔执行，Stop 后 Start 返回；未配置保留期时不执行
+func TestPointArchiveJob(t *testing.T) {
+	t.Run("按间隔执行归档", func(t *testing.T) {
+		job, repo := newTestPointArchiveJob(biz.PointConfig{TransactionRetention: time.Hour, ArchiveInterval: 10 * time.Millisecond})
+
+		done := make(chan error, 1)
+		go func() { done <- job.Start(context.Background()) }()
+
+		select {
+		case before := <-repo.calls:
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Second)
+		case <-time.After(time.Second):
+			t.Fatal("归档任务未执行")
+		}
+
+		assert.NoError(t, job.Stop(context.Background()))
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Stop 后 Start 未返回")
+		}
+		// 重复 Stop 不会 panic
+		assert.NoError(t, job.Stop(context.Background()))
+	})
+
+	t.Run("未配置保留期时不执行", func(t *testing.T) {
+		job, repo := newTestPointArchiveJob(biz.PointConfig{ArchiveInterval: time.Millisecond})
+
+		assert.NoError(t, job.Start(context.Background()))
+		assert.Empty(t, repo.calls)
+		assert.NoError(t, job.Stop(context.Background()))
+	})
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"user/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// TestSecurityHeaders 测试安全响应头按配置设置
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *conf.Server_SecurityHeaders
+		wantHeaders map[string]string
+	}{
+		{
+			name: "全部开启",
+			config: &conf.Server_SecurityHeaders{
+				HstsEnabled:           true,
+				HstsMaxAge:            durationpb.New(24 * time.Hour),
+				HstsIncludeSubdomains: true,
+				ContentTypeNosniff:    true,
+				FrameOptions:          "DENY",
+				ContentSecurityPolicy: "default-src 'none'",
+			},
+			wantHeaders: map[string]string{
+				"Strict-Transport-Security": "max-age=86400; includeSubDomains",
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Content-Security-Policy":   "default-src 'none'",
+			},
+		},
+		{
+			name: "HSTS 使用默认有效期",
+			config: &conf.Server_SecurityHeaders{
+				HstsEnabled: true,
+			},
+			wantHeaders: map[string]string{
+				"Strict-Transport-Security": "max-age=31536000",
+				"X-Content-Type-Options":    "",
+				"X-Frame-Options":           "",
+				"Content-Security-Policy":   "",
+			},
+		},
+		{
+			name: "开发环境关闭 HSTS",
+			config: &conf.Server_SecurityHeaders{
+				ContentTypeNosniff: true,
+				FrameOptions:       "SAMEORIGIN",
+			},
+			wantHeaders: map[string]string{
+				"Strict-Transport-Security": "",
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "SAMEORIGIN",
+				"Content-Security-Policy":   "",
+			},
+		},
+		{
+			name:   "全部关闭",
+			config: &conf.Server_SecurityHeaders{},
+			wantHeaders: map[string]string{
+				"Strict-Transport-Security": "",
+				"X-Content-Type-Options":    "",
+				"X-Frame-Options":           "",
+				"Content-Security-Policy":   "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := http.NewServer(http.Filter(SecurityHeaders(tt.config)))
+			srv.HandleFunc("/ping", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				w.WriteHeader(nethttp.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/ping", nil))
+			for key, want := range tt.wantHeaders {
+				assert.Equal(t, want, rec.Header().Get(key), "header: %s", key)
+			}
+
+			// 未匹配路由同样带上已开启的安全头（net/http 的 404 自带 nosniff，只校验已开启项）
+			rec = httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/not-found", nil))
+			assert.Equal(t, nethttp.StatusNotFound, rec.Code)
+			for key, want := range tt.wantHeaders {
+				if want != "" {
+					assert.Equal(t, want, rec.Header().Get(key), "header: %s", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	error_reason "user/api/error_reason"
+)
+
+// server.trace_context_policy 的取值，空值等同于 lenient
+const (
+	// TraceContextPolicyLenient 不检查请求是否携带追踪上下文
+	TraceContextPolicyLenient = "lenient"
+	// TraceContextPolicyWarn 缺少追踪上下文时记录警告，请求照常处理
+	TraceContextPolicyWarn = "warn"
+	// TraceContextPolicyStrict 拒绝缺少追踪上下文的请求
+	TraceContextPolicyStrict = "strict"
+)
+
+// RequireTraceContext 检查请求是否携带有效的 W3C traceparent，保证所有流量可以端到端追踪，HTTP 和 gRPC 服务共用
+// 按 policy 决定缺少时的处理：lenient（默认）不检查，warn 记录警告，strict 返回 USER_INVALID_REQUEST
+// 只校验请求头中的上下文，不依赖 tracing.Server 是否已为请求创建新的根 span
+func RequireTraceContext(policy string, logger log.Logger) middleware.Middleware {
+	helper := log.NewHelper(logger)
+	return func(handler middleware.Handler) middleware.Handler {
+		if policy != TraceContextPolicyWarn && policy != TraceContextPolicyStrict {
+			return handler
+		}
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok || hasTraceContext(ctx, tr.RequestHeader()) {
+				return handler(ctx, req)
+			}
+			if policy == TraceContextPolicyStrict {
+				helper.WithContext(ctx).Warnf("Rejected request without trace context, operation: %s", tr.Operation())
+				return nil, error_reason.ErrorUserInvalidRequest("缺少链路追踪上下文")
+			}
+			helper.WithContext(ctx).Warnf("Request without trace context, operation: %s", tr.Operation())
+			return handler(ctx, req)
+		}
+	}
+}
+
+// hasTraceContext 判断请求头中是否携带有效的 W3C 追踪上下文
+func hasTraceContext(ctx context.Context, header transport.Header) bool {
+	remote := propagation.TraceContext{}.Extract(ctx, header)
+	spanContext := trace.SpanContextFromContext(remote)
+	return spanContext.IsValid() && spanContext.IsRemote()
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/stretchr/testify/assert"
+	error_reason "user/api/error_reason"
+)
+
+// traceTransport 测试用的携带请求头和接口名的 transport
+type traceTransport struct {
+	transport.Transporter
+	header headerCarrier
+}
+
+func (t traceTransport) RequestHeader() transport.Header { return t.header }
+
+func (t traceTransport) Operation() string { return "/user.v1.User/GetProfile" }
+
+// TestRequireTraceContext 测试严格模式拒绝缺少追踪上下文的请求，携带有效 traceparent 的请求放行；警告和宽松模式均放行
+func TestRequireTraceContext(t *testing.T) {
+	const validTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name    string
+		policy  string
+		header  headerCarrier
+		wantErr bool
+	}{
+		{name: "严格模式缺少追踪头时拒绝", policy: TraceContextPolicyStrict, header: headerCarrier{}, wantErr: true},
+		{name: "严格模式追踪头格式不正确时拒绝", policy: TraceContextPolicyStrict, header: headerCarrier{"traceparent": "00-invalid"}, wantErr: true},
+		{name: "严格模式携带有效追踪头时放行", policy: TraceContextPolicyStrict, header: headerCarrier{"traceparent": validTraceparent}},
+		{name: "警告模式缺少追踪头时放行", policy: TraceContextPolicyWarn, header: headerCarrier{}},
+		{name: "宽松模式缺少追踪头时放行", policy: TraceContextPolicyLenient, header: headerCarrier{}},
+		{name: "未配置时按宽松模式放行", policy: "", header: headerCarrier{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := RequireTraceContext(tt.policy, log.DefaultLogger)(func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "ok", nil
+			})
+
+			ctx := transport.NewServerContext(context.Background(), traceTransport{header: tt.header})
+			reply, err := handler(ctx, nil)
+			if tt.wantErr {
+				assert.True(t, error_reason.IsUserInvalidRequest(err))
+				assert.False(t, called)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "ok", reply)
+			assert.True(t, called)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"user/internal/biz"
+)
+
+const (
+	// userStatsMeterName 用户数量指标的 meter 名称
+	userStatsMeterName = "user/internal/server"
+	// registeredUsersMetric 未删除的用户总数指标
+	registeredUsersMetric = "registered_users"
+	// premiumUsersMetric 未删除的付费用户数指标
+	premiumUsersMetric = "premium_users"
+	// activeSessionsMetric 未过期的刷新令牌（会话）数量指标
+	activeSessionsMetric = "active_sessions"
+	// userStatsSampleInterval 采样用户数量的间隔，统计查询需要扫描 user 表，不宜过于频繁
+	userStatsSampleInterval = 5 * time.Minute
+)
+
+var _ transport.Server = (*UserStatsSampler)(nil)
+
+// UserStatsSampler 定期查询用户总数、付费用户数和活跃会话数，通过 gauge 指标上报最近一次采样结果
+// 尚未采样成功时不上报，避免导出 0 值被误认为用户数骤降
+// 活跃会话数直接统计存储中未过期的刷新令牌，自然过期的令牌不会被计入，多实例部署时各实例上报的是同一个值
+type UserStatsSampler struct {
+	uc              *biz.UserUsecase
+	interval        time.Duration
+	total           atomic.Int64
+	premium         atomic.Int64
+	sampled         atomic.Bool
+	sessions        atomic.Int64
+	sessionsSampled atomic.Bool
+	done            chan struct{}
+	log             *log.Helper
+}
+
+// NewUserStatsSampler 创建用户数量采样任务，meter 为空时使用全局 MeterProvider
+func NewUserStatsSampler(uc *biz.UserUsecase, logger log.Logger) *UserStatsSampler {
+	s := &UserStatsSampler{
+		uc:       uc,
+		interval: userStatsSampleInterval,
+		done:     make(chan struct{}),
+		log:      log.NewHelper(logger),
+	}
+	s.registerGauges(nil)
+	return s
+}
+
+// registerGauges 注册用户数量和活跃会话数 gauge，回调读取最近一次采样结果；指标创建失败不影响服务启动
+func (s *UserStatsSampler) registerGauges(meter metric.Meter) {
+	if meter == nil {
+		meter = otel.Meter(userStatsMeterName)
+	}
+	gauges := []struct {
+		name        string
+		description string
+		value       *atomic.Int64
+		sampled     *atomic.Bool
+	}{
+		{registeredUsersMetric, "Number of registered users, sampled periodically", &s.total, &s.sampled},
+		{premiumUsersMetric, "Number of premium users, sampled periodically", &s.premium, &s.sampled},
+		{activeSessionsMetric, "Number of unexpired refresh tokens (sessions), sampled periodically", &s.sessions, &s.sessionsSampled},
+	}
+	for _, g := range gauges {
+		value, sampled := g.value, g.sampled
+		_, err := meter.Int64ObservableGauge(g.name,
+			metric.WithDescription(g.description),
+			metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+				if sampled.Load() {
+					o.Observe(value.Load())
+				}
+				return nil
+			}))
+		if err != nil {
+			s.log.Errorf("Failed to create %s gauge, error_reason: %v", g.name, err)
+		}
+	}
+}
+
+// Start 立即采样一次，之后按间隔采样，直到 Stop 被调用或 ctx 结束
+func (s *UserStatsSampler) Start(ctx context.Context) error {
+	s.sample(ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.sample(ctx)
+		case <-s.done:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Stop 停止采样
+func (s *UserStatsSampler) Stop(context.Context) error {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	return nil
+}
+
+// sample 查询并保存用户数量和活跃会话数，两者互不影响，失败时各自保留上一次的采样结果
+func (s *UserStatsSampler) sample(ctx context.Context) {
+	if stats, err := s.uc.UserStats(ctx); err != nil {
+		s.log.WithContext(ctx).Warnf("Failed to sample user stats, error_reason: %v", err)
+	} else {
+		s.total.Store(stats.Total)
+		s.premium.Store(stats.Premium)
+		s.sampled.Store(true)
+	}
+
+	if sessions, err := s.uc.ActiveSessions(ctx); err != nil {
+		s.log.WithContext(ctx).Warnf("Failed to sample active sessions, error_reason: %v", err)
+	} else {
+		s.sessions.Store(sessions)
+		s.sessionsSampled.Store(true)
+	}
+}
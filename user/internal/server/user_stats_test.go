@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"user/internal/biz"
+)
+
+// statsUserRepository 返回固定统计结果的用户数据访问
+type statsUserRepository struct {
+	biz.UserRepository
+	stats *biz.UserStats
+	err   error
+}
+
+func (r *statsUserRepository) Stats(context.Context) (*biz.UserStats, error) {
+	return r.stats, r.err
+}
+
+// statsAuthRepository 返回固定活跃会话数的认证数据访问
+type statsAuthRepository struct {
+	biz.AuthRepository
+	sessions int64
+	err      error
+}
+
+func (r *statsAuthRepository) CountActiveSessions(context.Context) (int64, error) {
+	return r.sessions, r.err
+}
+
+// gaugeMeter 记录 observable gauge 回调的测试 meter
+type gaugeMeter struct {
+	noop.Meter
+	callbacks map[string]metric.Int64Callback
+}
+
+func (m *gaugeMeter) Int64ObservableGauge(name string, opts ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	callbacks := metric.NewInt64ObservableGaugeConfig(opts...).Callbacks()
+	if len(callbacks) == 1 {
+		m.callbacks[name] = callbacks[0]
+	}
+	return noop.Int64ObservableGauge{}, nil
+}
+
+// gaugeObserver 记录观测值的测试 observer
+type gaugeObserver struct {
+	noop.Int64Observer
+	values []int64
+}
+
+func (o *gaugeObserver) Observe(value int64, _ ...metric.ObserveOption) {
+	o.values = append(o.values, value)
+}
+
+// observe 执行指定 gauge 的回调并返回观测值
+func (m *gaugeMeter) observe(t *testing.T, name string) []int64 {
+	callback, ok := m.callbacks[name]
+	require.True(t, ok, "gauge %s not registered", name)
+	o := &gaugeObserver{}
+	require.NoError(t, callback(context.Background(), o))
+	return o.values
+}
+
+// TestUserStatsSampler 测试采样成功后 gauge 上报用户数量和活跃会话数，采样失败时保留上一次结果
+func TestUserStatsSampler(t *testing.T) {
+	repo := &statsUserRepository{stats: &biz.UserStats{Total: 120, Premium: 7}}
+	authRepo := &statsAuthRepository{sessions: 42}
+	uc := biz.NewUserUsecase(repo, nil, authRepo, nil, nil, nil, biz.EmailConfig{}, biz.AuthConfig{}, nil, nil, nil, log.DefaultLogger)
+	sampler := NewUserStatsSampler(uc, log.DefaultLogger)
+	meter := &gaugeMeter{callbacks: map[string]metric.Int64Callback{}}
+	sampler.registerGauges(meter)
+
+	// 尚未采样时不上报
+	assert.Empty(t, meter.observe(t, registeredUsersMetric))
+	assert.Empty(t, meter.observe(t, activeSessionsMetric))
+
+	sampler.sample(context.Background())
+	assert.Equal(t, []int64{120}, meter.observe(t, registeredUsersMetric))
+	assert.Equal(t, []int64{7}, meter.observe(t, premiumUsersMetric))
+	assert.Equal(t, []int64{42}, meter.observe(t, activeSessionsMetric))
+
+	// 令牌过期后下一次采样上报减少后的数量
+	authRepo.sessions = 41
+	sampler.sample(context.Background())
+	assert.Equal(t, []int64{41}, meter.observe(t, activeSessionsMetric))
+
+	repo.stats, repo.err = nil, errors.New("connection refused")
+	authRepo.err = errors.New("connection refused")
+	sampler.sample(context.Background())
+	assert.Equal(t, []int64{120}, meter.observe(t, registeredUsersMetric))
+	assert.Equal(t, []int64{41}, meter.observe(t, activeSessionsMetric))
+}
+
+// TestUserStatsSampler_ActiveSessionsIndependent 测试用户数量采样失败不影响活跃会话数上报
+func TestUserStatsSampler_ActiveSessionsIndependent(t *testing.T) {
+	repo := &statsUserRepository{err: errors.New("connection refused")}
+	uc := biz.NewUserUsecase(repo, nil, &statsAuthRepository{sessions: 3}, nil, nil, nil, biz.EmailConfig{}, biz.AuthConfig{}, nil, nil, nil, log.DefaultLogger)
+	sampler := NewUserStatsSampler(uc, log.DefaultLogger)
+	meter := &gaugeMeter{callbacks: map[string]metric.Int64Callback{}}
+	sampler.registerGauges(meter)
+
+	sampler.sample(context.Background())
+	assert.Empty(t, meter.observe(t, registeredUsersMetric))
+	assert.Equal(t, []int64{3}, meter.observe(t, activeSessionsMetric))
+}
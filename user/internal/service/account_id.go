@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strconv"
+
+	"user/internal/conf"
+	"user/internal/pkg/accountid"
+)
+
+// AccountIDFormatPrefixed server.account_id_format 取该值时对外返回编码后的用户ID
+const AccountIDFormatPrefixed = "prefixed"
+
+// AccountIDFormatter 按配置的格式对外暴露用户ID，内部始终使用 int64
+type AccountIDFormatter struct {
+	prefixed bool
+}
+
+// NewAccountIDFormatter 根据 server.account_id_format 创建用户ID格式化器，未配置时返回原始 int64 ID
+func NewAccountIDFormatter(c *conf.Server) *AccountIDFormatter {
+	return &AccountIDFormatter{prefixed: c.GetAccountIdFormat() == AccountIDFormatPrefixed}
+}
+
+// Format 返回响应中 id 和 public_id 两个字段的值，两种格式只填充其中一个，避免编码后仍泄露原始ID
+func (f *AccountIDFormatter) Format(id int64) (int64, string) {
+	if f != nil && f.prefixed {
+		return 0, accountid.Encode(id)
+	}
+	return id, ""
+}
+
+// ParseAccountID 解析入站的用户ID引用，同时接受十进制 int64 和 usr_ 前缀的编码形式
+func ParseAccountID(s string) (int64, error) {
+	if accountid.IsEncoded(s) {
+		return accountid.Decode(s)
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+// duplicateEmailGroup 规范化后邮箱相同的一组账号
+type duplicateEmailGroup struct {
+	NormalizedEmail string   `json:"normalized_email"`
+	UserIDs         []int64  `json:"user_ids,omitempty"`
+	PublicIDs       []string `json:"public_ids,omitempty"`
+}
+
+// duplicateEmailsResponse 重复邮箱查询结果
+type duplicateEmailsResponse struct {
+	Groups []*duplicateEmailGroup `json:"groups"`
+}
+
+// mergeAccountsRequest 合并重复账号请求体，ID 同时接受十进制和 usr_ 前缀的编码形式
+type mergeAccountsRequest struct {
+	KeepID   string `json:"keep_id"`
+	RemoveID string `json:"remove_id"`
+}
+
+// accountMaintenanceResponse 账号维护操作的结果
+type accountMaintenanceResponse struct {
+	Success bool `json:"success"`
+}
+
+// ListDuplicateEmails 管理员查询邮箱仅大小写不同的重复账号，用于确定需要合并的账号
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *UserService) ListDuplicateEmails(ctx http.Context) error {
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.listDuplicateEmails(c)
+	})
+	out, err := h(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return ctx.Result(200, out)
+}
+
+// listDuplicateEmails 校验管理员身份后查询重复邮箱
+func (s *UserService) listDuplicateEmails(ctx context.Context) (interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.ListDuplicateEmails")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_duplicate_emails",
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.maintenance.FindDuplicateEmails(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ListDuplicateEmails failed for admin: %d, error_reason: %v", adminID, err)
+		return nil, err
+	}
+
+	reply := &duplicateEmailsResponse{Groups: make([]*duplicateEmailGroup, 0, len(groups))}
+	for _, group := range groups {
+		g := &duplicateEmailGroup{NormalizedEmail: group.NormalizedEmail}
+		for _, userID := range group.UserIDs {
+			id, publicID := s.accountIDs.Format(userID)
+			if publicID != "" {
+				g.PublicIDs = append(g.PublicIDs, publicID)
+			} else {
+				g.UserIDs = append(g.UserIDs, id)
+			}
+		}
+		reply.Groups = append(reply.Groups, g)
+	}
+	return reply, nil
+}
+
+// MergeAccounts 管理员将 remove_id 账号的点数和流水合并到 keep_id 账号，并软删除 remove_id 账号
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *UserService) MergeAccounts(ctx http.Context) error {
+	var req mergeAccountsRequest
+	if err := ctx.Bind(&req); err != nil {
+		return error_reason.ErrorUserInvalidRequest("请求体格式错误")
+	}
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.mergeAccounts(c, req.KeepID, req.RemoveID)
+	})
+	out, err := h(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return ctx.Result(200, out)
+}
+
+// mergeAccounts 校验管理员身份和账号ID后合并账号
+func (s *UserService) mergeAccounts(ctx context.Context, keepIDStr, removeIDStr string) (interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.MergeAccounts")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "merge_accounts",
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	keepID, err := ParseAccountID(keepIDStr)
+	if err != nil {
+		return nil, error_reason.ErrorUserInvalidRequest("keep_id 格式无效")
+	}
+	removeID, err := ParseAccountID(removeIDStr)
+	if err != nil {
+		return nil, error_reason.ErrorUserInvalidRequest("remove_id 格式无效")
+	}
+
+	if err := s.maintenance.MergeDuplicateAccounts(ctx, keepID, removeID); err != nil {
+		s.logger.WithContext(ctx).Errorf("MergeAccounts failed for admin: %d, error_reason: %v", adminID, err)
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Admin %d merged account %d into %d", adminID, removeID, keepID)
+	return &accountMaintenanceResponse{Success: true}, nil
+}
+
+// DeleteAccount 管理员删除指定账号，点数按 biz.point_deletion_policy 配置的策略处理
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *UserService) DeleteAccount(ctx http.Context) error {
+	userIDStr := ctx.Vars().Get("id")
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.deleteAccount(c, userIDStr)
+	})
+	out, err := h(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return ctx.Result(200, out)
+}
+
+// deleteAccount 校验管理员身份和账号ID后删除账号
+func (s *UserService) deleteAccount(ctx context.Context, userIDStr string) (interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.DeleteAccount")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "delete_account",
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := ParseAccountID(userIDStr)
+	if err != nil {
+		return nil, error_reason.ErrorUserInvalidRequest("用户ID格式无效")
+	}
+
+	if err := s.maintenance.DeleteUser(ctx, userID); err != nil {
+		s.logger.WithContext(ctx).Errorf("DeleteAccount failed for admin: %d, error_reason: %v", adminID, err)
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Admin %d deleted account %d", adminID, userID)
+	return &accountMaintenanceResponse{Success: true}, nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// maintenanceUserRepository 记录软删除的用户数据访问
+type maintenanceUserRepository struct {
+	biz.UserRepository
+	users   map[int64]*biz.User
+	deleted []int64
+}
+
+func (r *maintenanceUserRepository) GetByIDPublic(ctx context.Context, id int64) (*biz.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (r *maintenanceUserRepository) SoftDelete(ctx context.Context, id int64) error {
+	if _, ok := r.users[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.users, id)
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func (r *maintenanceUserRepository) FindDuplicateEmails(ctx context.Context) ([]*biz.DuplicateEmailGroup, error) {
+	return []*biz.DuplicateEmailGroup{{NormalizedEmail: "dup@example.com", UserIDs: []int64{10, 11}}}, nil
+}
+
+// maintenanceAuthRepository 忽略令牌吊销
+type maintenanceAuthRepository struct {
+	biz.AuthRepository
+}
+
+func (maintenanceAuthRepository) DeleteAllRefreshTokens(ctx context.Context, userID int64) error {
+	return nil
+}
+
+// maintenancePointRepository 记录点数合并
+type maintenancePointRepository struct {
+	biz.UserPointRepository
+	merged [][2]int64
+}
+
+func (r *maintenancePointRepository) MergeInto(ctx context.Context, fromUserID, toUserID int64) error {
+	r.merged = append(r.merged, [2]int64{fromUserID, toUserID})
+	return nil
+}
+
+// maintenanceTransactionRepository 忽略流水迁移
+type maintenanceTransactionRepository struct {
+	biz.PointTransactionRepository
+}
+
+func (maintenanceTransactionRepository) ReassignUser(ctx context.Context, fromUserID, toUserID int64) (int64, error) {
+	return 0, nil
+}
+
+// directTransaction 直接执行 fn 的事务
+type directTransaction struct{}
+
+func (directTransaction) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (directTransaction) InTxWithRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// newMaintenanceTestServer 创建注册了账号维护路由的 HTTP 服务，用户 1 为管理员
+func newMaintenanceTestServer(userRepo *maintenanceUserRepository, pointRepo *maintenancePointRepository) *http.Server {
+	authConfig := biz.AuthConfig{AdminUserIDs: []int64{1}}
+	maintenance := biz.NewAccountMaintenanceUsecase(userRepo, maintenanceAuthRepository{}, pointRepo, maintenanceTransactionRepository{}, directTransaction{}, biz.AccountDeletionConfig{}, log.DefaultLogger)
+	svc := NewUserService(nil, nil, nil, maintenance, nil, authConfig, log.DefaultLogger)
+
+	srv := http.NewServer()
+	srv.Route("/").GET("/v1/admin/users/duplicate-emails", svc.ListDuplicateEmails)
+	srv.Route("/").POST("/v1/admin/users/merge", svc.MergeAccounts)
+	srv.Route("/").DELETE("/v1/admin/users/{id}", svc.DeleteAccount)
+	return srv
+}
+
+// TestUserService_ListDuplicateEmails 测试管理员查询重复邮箱，非管理员被拒绝
+func TestUserService_ListDuplicateEmails(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		wantStatus int
+	}{
+		{
+			name:       "管理员查询成功",
+			userID:     "1",
+			wantStatus: nethttp.StatusOK,
+		},
+		{
+			name:       "非管理员",
+			userID:     "2",
+			wantStatus: nethttp.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newMaintenanceTestServer(&maintenanceUserRepository{}, &maintenancePointRepository{})
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/v1/admin/users/duplicate-emails", nil)
+			req.Header.Set("X-User-ID", tt.userID)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != nethttp.StatusOK {
+				return
+			}
+			var resp duplicateEmailsResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.Len(t, resp.Groups, 1)
+			assert.Equal(t, "dup@example.com", resp.Groups[0].NormalizedEmail)
+			assert.Equal(t, []int64{10, 11}, resp.Groups[0].UserIDs)
+		})
+	}
+}
+
+// TestUserService_MergeAccounts 测试管理员合并重复账号，非管理员和格式错误的ID不执行合并
+func TestUserService_MergeAccounts(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		body       string
+		wantStatus int
+		wantMerged bool
+	}{
+		{
+			name:       "管理员合并成功",
+			userID:     "1",
+			body:       `{"keep_id":"10","remove_id":"11"}`,
+			wantStatus: nethttp.StatusOK,
+			wantMerged: true,
+		},
+		{
+			name:       "非管理员",
+			userID:     "2",
+			body:       `{"keep_id":"10","remove_id":"11"}`,
+			wantStatus: nethttp.StatusForbidden,
+		},
+		{
+			name:       "ID格式无效",
+			userID:     "1",
+			body:       `{"keep_id":"abc","remove_id":"11"}`,
+			wantStatus: nethttp.StatusBadRequest,
+		},
+		{
+			name:       "合并到自身",
+			userID:     "1",
+			body:       `{"keep_id":"10","remove_id":"10"}`,
+			wantStatus: nethttp.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &maintenanceUserRepository{users: map[int64]*biz.User{
+				10: {ID: 10, Email: "dup@example.com"},
+				11: {ID: 11, Email: "Dup@example.com"},
+			}}
+			pointRepo := &maintenancePointRepository{}
+			srv := newMaintenanceTestServer(userRepo, pointRepo)
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/v1/admin/users/merge", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", tt.userID)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantMerged {
+				assert.Empty(t, pointRepo.merged)
+				assert.Empty(t, userRepo.deleted)
+				return
+			}
+			assert.Equal(t, [][2]int64{{11, 10}}, pointRepo.merged)
+			assert.Equal(t, []int64{11}, userRepo.deleted)
+		})
+	}
+}
+
+// TestUserService_DeleteAccount 测试管理员删除账号，非管理员被拒绝，不存在的账号返回 404
+func TestUserService_DeleteAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		target      string
+		wantStatus  int
+		wantDeleted []int64
+	}{
+		{
+			name:        "管理员删除成功",
+			userID:      "1",
+			target:      "10",
+			wantStatus:  nethttp.StatusOK,
+			wantDeleted: []int64{10},
+		},
+		{
+			name:       "非管理员",
+			userID:     "2",
+			target:     "10",
+			wantStatus: nethttp.StatusForbidden,
+		},
+		{
+			name:       "用户不存在",
+			userID:     "1",
+			target:     "99",
+			wantStatus: nethttp.StatusNotFound,
+		},
+		{
+			name:       "ID格式无效",
+			userID:     "1",
+			target:     "abc",
+			wantStatus: nethttp.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &maintenanceUserRepository{users: map[int64]*biz.User{10: {ID: 10}}}
+			srv := newMaintenanceTestServer(userRepo, &maintenancePointRepository{})
+
+			req := httptest.NewRequest(nethttp.MethodDelete, "/v1/admin/users/"+tt.target, nil)
+			req.Header.Set("X-User-ID", tt.userID)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantDeleted, userRepo.deleted)
+		})
+	}
+}
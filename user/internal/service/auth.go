@@ -2,14 +2,14 @@ package service
 
 import (
 	"context"
-	"regexp"
 
 	v1 "user/api/auth/v1"
 	"user/internal/biz"
 
 	"github.com/go-kratos/kratos/v2/log"
-	"user/internal/pkg/tracing"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
 )
 
 // AuthService 实现 AuthService 接口
@@ -18,37 +18,10 @@ type AuthService struct {
 
 	authUsecase *biz.AuthUsecase
 	userUsecase *biz.UserUsecase
+	accountIDs  *AccountIDFormatter
 	logger      *log.Helper
 }
 
-// emailRegex 邮箱格式正则表达式
-var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-
-// validateEmail 验证邮箱格式
-//
-// 参数:
-//   - email: 待验证的邮箱地址
-//
-// 返回值:
-//   - error: 验证失败时返回错误，验证成功时返回 nil
-func validateEmail(email string) error {
-	if email == "" {
-		return error_reason.ErrorUserInvalidEmail("邮箱不能为空")
-	}
-
-	// 检查邮箱长度（最大254字符是RFC 5321规定的）
-	if len(email) > 254 {
-		return error_reason.ErrorUserInvalidEmail("邮箱长度不能超过254个字符")
-	}
-
-	// 检查邮箱格式
-	if !emailRegex.MatchString(email) {
-		return error_reason.ErrorUserInvalidEmail("邮箱格式不正确")
-	}
-
-	return nil
-}
-
 // validatePassword 验证密码格式
 //
 // 参数:
@@ -99,10 +72,11 @@ func validatePassword(password string) error {
 }
 
 // NewAuthService 创建 AuthService 实例
-func NewAuthService(authUsecase *biz.AuthUsecase, userUsecase *biz.UserUsecase, logger log.Logger) *AuthService {
+func NewAuthService(authUsecase *biz.AuthUsecase, userUsecase *biz.UserUsecase, accountIDs *AccountIDFormatter, logger log.Logger) *AuthService {
 	return &AuthService{
 		authUsecase: authUsecase,
 		userUsecase: userUsecase,
+		accountIDs:  accountIDs,
 		logger:      log.NewHelper(logger),
 	}
 }
@@ -114,18 +88,18 @@ func (s *AuthService) SendRegisterCode(ctx context.Context, req *v1.SendRegister
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
 		"operation": "send_register_code",
-		"email": req.Email,
+		"email":     req.Email,
 	})
 
 	s.logger.WithContext(ctx).Infof("Received SendRegisterCode request for email: %s", req.Email)
 
 	// 验证邮箱格式
-	if err := validateEmail(req.Email); err != nil {
+	if err := biz.ValidateEmailFormat(req.Email); err != nil {
 		s.logger.WithContext(ctx).Warnf("Invalid email format: %s, error: %v", req.Email, err)
 		return nil, err
 	}
 
-	err := s.userUsecase.SendRegisterCode(ctx, req.Email)
+	err := s.userUsecase.SendRegisterCode(ctx, req.Email, req.CaptchaToken)
 	if err != nil {
 		s.logger.WithContext(ctx).Errorf("SendRegisterCode failed: %v", err)
 		return nil, err
@@ -138,6 +112,53 @@ func (s *AuthService) SendRegisterCode(ctx context.Context, req *v1.SendRegister
 	}, nil
 }
 
+// GetRegisterCodeStatus 查询注册验证码的剩余有效期，用于前端倒计时
+func (s *AuthService) GetRegisterCodeStatus(ctx context.Context, req *v1.GetRegisterCodeStatusRequest) (*v1.GetRegisterCodeStatusResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthService.GetRegisterCodeStatus")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "get_register_code_status",
+		"email":     req.Email,
+	})
+
+	s.logger.WithContext(ctx).Infof("Received GetRegisterCodeStatus request for email: %s", req.Email)
+
+	status, err := s.userUsecase.GetRegisterCodeStatus(ctx, req.Email)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("GetRegisterCodeStatus failed: %v", err)
+		return nil, err
+	}
+
+	return &v1.GetRegisterCodeStatusResponse{
+		Exists:           status.Exists,
+		RemainingSeconds: status.RemainingSeconds,
+	}, nil
+}
+
+// ExtendRegisterCode 延长注册验证码的有效期，验证码本身不变
+func (s *AuthService) ExtendRegisterCode(ctx context.Context, req *v1.ExtendRegisterCodeRequest) (*v1.ExtendRegisterCodeResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthService.ExtendRegisterCode")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "extend_register_code",
+		"email":     req.Email,
+	})
+
+	s.logger.WithContext(ctx).Infof("Received ExtendRegisterCode request for email: %s", req.Email)
+
+	status, err := s.userUsecase.ExtendRegisterCode(ctx, req.Email)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ExtendRegisterCode failed: %v", err)
+		return nil, err
+	}
+
+	return &v1.ExtendRegisterCodeResponse{
+		RemainingSeconds: status.RemainingSeconds,
+	}, nil
+}
+
 // Register 用户注册
 func (s *AuthService) Register(ctx context.Context, req *v1.RegisterRequest) (*v1.RegisterResponse, error) {
 	ctx, span := tracing.StartSpan(ctx, "AuthService.Register")
@@ -145,29 +166,39 @@ func (s *AuthService) Register(ctx context.Context, req *v1.RegisterRequest) (*v
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
 		"operation": "register",
-		"email": req.Email,
-		"nickname": req.Nickname,
+		"email":     req.Email,
+		"nickname":  req.Nickname,
 	})
 
 	s.logger.WithContext(ctx).Infof("Received Register request for email: %s", req.Email)
 
-	// 验证密码格式
-	if err := validatePassword(req.Password); err != nil {
-		s.logger.WithContext(ctx).Warnf("Invalid password format: %s, error: %v", req.Password, err)
+	// 一次校验全部字段，错误中附带每个字段的校验失败，返回的错误原因与第一个失败字段一致
+	var violations biz.ValidationError
+	violations.Add("password", validatePassword(req.Password))
+	violations.Add("email", biz.ValidateEmailFormat(req.Email))
+	// 超长验证码不可能正确，在访问存储前直接拒绝，避免无效的哈希计算和试探
+	if len(req.Code) > s.userUsecase.VerificationCodeLength() {
+		violations.Add("code", error_reason.ErrorUserInvalidVerificationCode("验证码错误"))
+	}
+	if err := violations.Err(); err != nil {
+		s.logger.WithContext(ctx).Warnf("Invalid register request for email: %s, error: %v", req.Email, err)
 		return nil, err
 	}
 
-	user, err := s.userUsecase.Register(ctx, req.Email, req.Password, req.Code, req.Nickname)
+	user, err := s.userUsecase.Register(ctx, req.Email, req.Password, req.Code, req.Nickname, req.CaptchaToken)
 	if err != nil {
 		s.logger.WithContext(ctx).Errorf("Register failed: %v", err)
 		return nil, err
 	}
 
 	s.logger.WithContext(ctx).Infof("Register completed successfully for user id: %d", user.ID)
+	id, publicID := s.accountIDs.Format(user.ID)
 	return &v1.RegisterResponse{
-		Id:       user.ID,
-		Email:    user.Email,
-		Nickname: user.Nickname,
+		Id:        id,
+		PublicId:  publicID,
+		Email:     user.Email,
+		Nickname:  user.Nickname,
+		CreatedAt: timestamppb.New(user.CreatedAt),
 	}, nil
 }
 
@@ -178,12 +209,12 @@ func (s *AuthService) Login(ctx context.Context, req *v1.LoginRequest) (*v1.Logi
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
 		"operation": "login",
-		"email": req.Email,
+		"email":     req.Email,
 	})
 
 	s.logger.WithContext(ctx).Infof("Received Login request for email: %s", req.Email)
 
-	tokenPair, err := s.userUsecase.Login(ctx, req.Email, req.Password)
+	tokenPair, err := s.userUsecase.Login(ctx, req.Email, req.Password, req.RememberMe)
 	if err != nil {
 		s.logger.WithContext(ctx).Errorf("Login failed: %v", err)
 		return nil, err
@@ -204,7 +235,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *v1.RefreshTokenRequ
 	defer span.End()
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
-		"operation": "refresh_token",
+		"operation":    "refresh_token",
 		"token_length": len(req.RefreshToken),
 	})
 
@@ -218,18 +249,26 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *v1.RefreshTokenRequ
 
 	s.logger.WithContext(ctx).Info("RefreshToken completed successfully")
 	return &v1.RefreshTokenResponse{
-		AccessToken:     tokenPair.AccessToken,
-		AccessExpiresIn: tokenPair.AccessExpiresIn,
+		AccessToken:         tokenPair.AccessToken,
+		AccessExpiresIn:     tokenPair.AccessExpiresIn,
+		RefreshToken:        tokenPair.RefreshToken,
+		RefreshExpiresIn:    tokenPair.RefreshExpiresIn,
+		RefreshTokenRotated: tokenPair.RefreshTokenRotated,
 	}, nil
 }
 
+// ValidateAccessToken 校验访问令牌并返回用户ID，供未部署网关时的进程内鉴权使用
+func (s *AuthService) ValidateAccessToken(ctx context.Context, accessToken string) (int64, error) {
+	return s.authUsecase.ValidateToken(ctx, accessToken)
+}
+
 // Logout 用户登出
 func (s *AuthService) Logout(ctx context.Context, req *v1.LogoutRequest) (*v1.LogoutResponse, error) {
 	ctx, span := tracing.StartSpan(ctx, "AuthService.Logout")
 	defer span.End()
 
 	tracing.AddSpanTags(ctx, map[string]interface{}{
-		"operation": "logout",
+		"operation":    "logout",
 		"token_length": len(req.RefreshToken),
 	})
 
@@ -247,3 +286,25 @@ func (s *AuthService) Logout(ctx context.Context, req *v1.LogoutRequest) (*v1.Lo
 		Message: "登出成功",
 	}, nil
 }
+
+// GetTokenExpiry 查询访问令牌剩余有效期及是否应提前刷新
+func (s *AuthService) GetTokenExpiry(ctx context.Context, req *v1.GetTokenExpiryRequest) (*v1.GetTokenExpiryResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthService.GetTokenExpiry")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":    "get_token_expiry",
+		"token_length": len(req.AccessToken),
+	})
+
+	expiry, err := s.authUsecase.TokenExpiry(ctx, req.AccessToken)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("GetTokenExpiry failed: %v", err)
+		return nil, err
+	}
+
+	return &v1.GetTokenExpiryResponse{
+		ExpiresIn:     expiry.ExpiresIn,
+		ShouldRefresh: expiry.ShouldRefresh,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	v1 "user/api/auth/v1"
+	error_reason "user/api/error_reason"
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// consumeRecordingCodeRepository 只实现 ConsumeIfValid 并记录调用次数，调用其他方法会 panic
+type consumeRecordingCodeRepository struct {
+	biz.CodeRepository
+	consumeCalls int
+}
+
+func (r *consumeRecordingCodeRepository) ConsumeIfValid(ctx context.Context, email, purpose, candidate string) (bool, error) {
+	r.consumeCalls++
+	return false, nil
+}
+
+// TestAuthService_Register_CodeLength 测试超过配置长度的验证码在访问存储前被拒绝，长度正确的错误验证码仍走正常校验流程
+func TestAuthService_Register_CodeLength(t *testing.T) {
+	tests := []struct {
+		name             string
+		codeConfig       biz.CodeConfig
+		code             string
+		wantConsumeCalls bool
+	}{
+		{
+			name: "超长验证码直接拒绝",
+			code: "1234567890123456789012345678901234567890",
+		},
+		{
+			name: "比验证码位数多一位",
+			code: "1234567",
+		},
+		{
+			name:             "长度正确的错误验证码",
+			code:             "000000",
+			wantConsumeCalls: true,
+		},
+		{
+			name:             "按配置的验证码长度判断",
+			codeConfig:       biz.CodeConfig{Length: 8},
+			code:             "00000000",
+			wantConsumeCalls: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeRepo := &consumeRecordingCodeRepository{}
+			userUsecase := biz.NewUserUsecase(nil, codeRepo, nil, nil, nil, nil, biz.EmailConfig{Code: tt.codeConfig}, biz.AuthConfig{},
+				biz.NewCodeHasherWithSecrets("test-code-hmac-secret-for-unit-testing-only", ""), nil,
+				biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger), log.DefaultLogger)
+			svc := NewAuthService(nil, userUsecase, nil, log.DefaultLogger)
+
+			_, err := svc.Register(context.Background(), &v1.RegisterRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+				Code:     tt.code,
+			})
+
+			assert.True(t, error_reason.IsUserInvalidVerificationCode(err), "unexpected error: %v", err)
+			assert.Equal(t, tt.wantConsumeCalls, codeRepo.consumeCalls > 0)
+		})
+	}
+}
+
+// TestAuthService_Register_FieldViolations 测试多个字段同时不合法时，错误中附带每个字段的校验失败
+func TestAuthService_Register_FieldViolations(t *testing.T) {
+	userUsecase := biz.NewUserUsecase(nil, nil, nil, nil, nil, nil, biz.EmailConfig{}, biz.AuthConfig{}, nil, nil,
+		biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger), log.DefaultLogger)
+	svc := NewAuthService(nil, userUsecase, nil, log.DefaultLogger)
+
+	_, err := svc.Register(context.Background(), &v1.RegisterRequest{
+		Email:    "not-an-email",
+		Password: "short",
+		Code:     "1234567",
+	})
+
+	// 错误原因与第一个失败字段一致
+	assert.True(t, error_reason.IsUserInvalidRequest(err), "unexpected error: %v", err)
+	violations := biz.FieldViolationsFromError(err)
+	fields := make([]string, 0, len(violations))
+	for _, v := range violations {
+		fields = append(fields, v.Field)
+	}
+	assert.Equal(t, []string{"password", "email", "code"}, fields)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+
+	error_reason "user/api/error_reason"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"user/internal/pkg/tracing"
+)
+
+// verifiedUserIDHeader 校验通过后写入响应的用户ID头，供网关转发给上游服务
+const verifiedUserIDHeader = "X-User-ID"
+
+// VerifyToken 供边缘网关校验 Bearer 访问令牌，有效时返回 200 并在响应头 X-User-ID 中带上用户ID，无效时返回 401
+// 不返回令牌声明；不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *AuthService) VerifyToken(ctx http.Context) error {
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.verifyToken(c, ctx.Request())
+	})
+	out, err := h(ctx, nil)
+	if err != nil {
+		return err
+	}
+	ctx.Response().Header().Set(verifiedUserIDHeader, strconv.FormatInt(out.(int64), 10))
+	ctx.Response().WriteHeader(nethttp.StatusOK)
+	return nil
+}
+
+// verifyToken 从 Authorization 请求头取出访问令牌并校验，返回令牌所属的用户ID
+func (s *AuthService) verifyToken(ctx context.Context, r *nethttp.Request) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthService.VerifyToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "verify_token",
+	})
+
+	token, ok := parseBearerToken(r.Header.Get("Authorization"))
+	if !ok {
+		s.logger.WithContext(ctx).Warn("VerifyToken missing bearer token")
+		return 0, error_reason.ErrorUserInvalidToken("缺少访问令牌")
+	}
+
+	userID, err := s.authUsecase.ValidateToken(ctx, token)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("VerifyToken failed, error_reason: %v", err)
+		return 0, err
+	}
+	return userID, nil
+}
+
+// parseBearerToken 解析 Authorization 请求头中的 Bearer 令牌，前缀不区分大小写
+func parseBearerToken(auth string) (string, bool) {
+	const prefix = "Bearer "
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(auth[len(prefix):])
+	return token, token != ""
+}
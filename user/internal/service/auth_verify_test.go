@@ -0,0 +1,78 @@
+package service
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuthService_VerifyToken 测试有效令牌返回 200 并带用户ID响应头，无效令牌返回 401 且不带该响应头
+func TestAuthService_VerifyToken(t *testing.T) {
+	const secret = "test-access-secret-key-for-unit-testing-only"
+	t.Setenv("JWT_ACCESS_SECRET", secret)
+
+	signToken := func(key string) string {
+		claims := &biz.AccessClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "123",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantUserID    string
+	}{
+		{
+			name:          "有效令牌",
+			authorization: "Bearer " + signToken(secret),
+			wantStatus:    nethttp.StatusOK,
+			wantUserID:    "123",
+		},
+		{
+			name:          "签名错误的令牌",
+			authorization: "Bearer " + signToken("test-another-secret"),
+			wantStatus:    nethttp.StatusUnauthorized,
+		},
+		{
+			name:       "缺少令牌",
+			wantStatus: nethttp.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := biz.NewAuthUsecase(nil, nil, biz.AuthConfig{}, biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger), log.DefaultLogger)
+			svc := NewAuthService(uc, nil, nil, log.DefaultLogger)
+
+			srv := http.NewServer()
+			srv.Route("/").POST("/v1/auth/verify", svc.VerifyToken)
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/v1/auth/verify", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantUserID, rec.Header().Get("X-User-ID"))
+		})
+	}
+}
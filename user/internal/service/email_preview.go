@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"user/internal/pkg/tracing"
+)
+
+// emailPreviewResponse 渲染后的邮件内容
+type emailPreviewResponse struct {
+	Subject   string `json:"subject"`
+	HTML      string `json:"html"`
+	PlainText string `json:"plain_text"`
+}
+
+// PreviewEmail 管理员按邮件类型预览渲染后的邮件标题、HTML 和纯文本内容，不发送邮件
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *UserService) PreviewEmail(ctx http.Context) error {
+	query := ctx.Request().URL.Query()
+	emailType, lang, code := query.Get("type"), query.Get("lang"), query.Get("code")
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.previewEmail(c, emailType, lang, code)
+	})
+	out, err := h(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return ctx.Result(200, out)
+}
+
+// previewEmail 校验管理员身份后渲染邮件
+func (s *UserService) previewEmail(ctx context.Context, emailType, lang, code string) (interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.PreviewEmail")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation":  "preview_email",
+		"email_type": emailType,
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.userUsecase.PreviewEmail(ctx, emailType, lang, code)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("PreviewEmail failed for user: %d, error_reason: %v", adminID, err)
+		return nil, err
+	}
+	return &emailPreviewResponse{
+		Subject:   message.Subject,
+		HTML:      message.HTML,
+		PlainText: message.PlainText,
+	}, nil
+}
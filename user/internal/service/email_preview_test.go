@@ -0,0 +1,78 @@
+package service
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserService_PreviewEmail 测试管理员预览的验证码邮件包含指定验证码，不支持的语言返回 400，非管理员被拒绝
+func TestUserService_PreviewEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		query      string
+		wantStatus int
+	}{
+		{
+			name:       "管理员预览验证码邮件",
+			userID:     "1",
+			query:      "type=register_code&lang=zh&code=654321",
+			wantStatus: nethttp.StatusOK,
+		},
+		{
+			name:       "使用 type=register 别名",
+			userID:     "1",
+			query:      "type=register&lang=zh&code=654321",
+			wantStatus: nethttp.StatusOK,
+		},
+		{
+			name:       "不支持的语言",
+			userID:     "1",
+			query:      "type=register_code&lang=en&code=654321",
+			wantStatus: nethttp.StatusBadRequest,
+		},
+		{
+			name:       "非管理员",
+			userID:     "2",
+			query:      "type=register_code&code=654321",
+			wantStatus: nethttp.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingEmailSender{}
+			authConfig := biz.AuthConfig{AdminUserIDs: []int64{1}}
+			slowOp := biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger)
+			uc := biz.NewUserUsecase(nil, nil, nil, nil, sender, nil, biz.EmailConfig{}, authConfig, nil, nil, slowOp, log.DefaultLogger)
+			svc := NewUserService(uc, nil, nil, nil, nil, authConfig, log.DefaultLogger)
+
+			srv := http.NewServer()
+			srv.Route("/").GET("/debug/email-preview", svc.PreviewEmail)
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/debug/email-preview?"+tt.query, nil)
+			req.Header.Set("X-User-ID", tt.userID)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Empty(t, sender.sent, "预览不发送邮件")
+			if tt.wantStatus != nethttp.StatusOK {
+				return
+			}
+			var resp emailPreviewResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Contains(t, resp.HTML, "654321")
+			assert.Contains(t, resp.PlainText, "654321")
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package service
 
 import (
+	error_reason "user/api/error_reason"
+	biz "user/internal/biz"
+
 	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // ErrorMessageMap 错误消息映射
@@ -32,6 +37,12 @@ var ErrorMessageMap = map[string]string{
 	"USER_INTERNAL_ERROR":      "服务内部错误",
 	"USER_SERVICE_UNAVAILABLE": "用户服务暂时不可用",
 
+	"USER_PERMISSION_DENIED":             "无权访问该资源",
+	"USER_CAPTCHA_REQUIRED":              "请完成人机验证后重试",
+	"USER_EMAIL_NOT_VERIFIED":            "请先完成邮箱验证后再登录",
+	"USER_PERSONAL_TOKEN_LIMIT_EXCEEDED": "个人访问令牌数已达上限，请撤销不再使用的令牌后重试",
+	"USER_SESSION_REVOKE_INCOMPLETE":     "密码已重置，但部分设备未能退出登录，请稍后重新找回密码",
+
 	// AuthService 错误消息
 	"AUTH_INVALID_CREDENTIALS":   "用户名或密码错误",
 	"AUTH_TOKEN_INVALID":         "访问令牌无效",
@@ -72,6 +83,58 @@ func GetFriendlyErrorMessage(reason string) string {
 	return "操作失败，请稍后重试"
 }
 
+// ErrorReasonInfo 错误原因及其映射，用于生成客户端 SDK 和文档
+type ErrorReasonInfo struct {
+	Reason         string // 错误原因，如 USER_INVALID_TOKEN
+	HTTPStatus     int    // HTTP 状态码
+	BusinessCode   string // 业务错误码，没有对应的业务错误时为空
+	DefaultMessage string // 默认的用户友好错误消息
+}
+
+// reasonBizErrors 错误原因对应的业务层错误，业务错误码通过 ErrorMapping 查得
+var reasonBizErrors = map[string]error{
+	"USER_INVALID_TOKEN":             biz.ErrInvalidToken,
+	"USER_TOKEN_EXPIRED":             biz.ErrTokenExpired,
+	"USER_INVALID_CREDENTIALS":       biz.ErrInvalidCredentials,
+	"USER_INVALID_VERIFICATION_CODE": biz.ErrInvalidVerificationCode,
+	"USER_VERIFICATION_CODE_EXPIRED": biz.ErrVerificationCodeExpired,
+	"USER_EMAIL_ALREADY_EXISTS":      biz.ErrEmailAlreadyExists,
+	"USER_TOO_MANY_REQUESTS":         biz.ErrTooManyRequests,
+}
+
+// ListErrorReasons 按 proto 中的定义顺序列出所有错误原因
+// HTTP 状态码取自枚举值的 errors.code，未设置时使用枚举的 errors.default_code；
+// 业务错误码取自 ErrorMapping，默认消息取自 ErrorMessageMap
+func ListErrorReasons() []ErrorReasonInfo {
+	enums := []protoreflect.EnumDescriptor{
+		error_reason.UserErrorReason(0).Descriptor(),
+		error_reason.AuthErrorReason(0).Descriptor(),
+		error_reason.SystemErrorReason(0).Descriptor(),
+	}
+
+	var infos []ErrorReasonInfo
+	for _, enum := range enums {
+		defaultCode := proto.GetExtension(enum.Options(), errors.E_DefaultCode).(int32)
+		values := enum.Values()
+		for i := 0; i < values.Len(); i++ {
+			value := values.Get(i)
+			status := defaultCode
+			if code := proto.GetExtension(value.Options(), errors.E_Code).(int32); code != 0 {
+				status = code
+			}
+
+			reason := string(value.Name())
+			infos = append(infos, ErrorReasonInfo{
+				Reason:         reason,
+				HTTPStatus:     int(status),
+				BusinessCode:   ErrorMapping[reasonBizErrors[reason]],
+				DefaultMessage: GetFriendlyErrorMessage(reason),
+			})
+		}
+	}
+	return infos
+}
+
 // StandardErrorResponse 标准错误响应结构
 type StandardErrorResponse struct {
 	Code    int                    `json:"code"`              // HTTP状态码
@@ -103,7 +166,7 @@ func NewStandardErrorResponse(err error) *StandardErrorResponse {
 		message = e.Message
 	}
 
-	return &StandardErrorResponse{
+	resp := &StandardErrorResponse{
 		Code:    int(e.Code),
 		Reason:  e.Reason,
 		Message: message,
@@ -112,6 +175,11 @@ func NewStandardErrorResponse(err error) *StandardErrorResponse {
 			"timestamp":  getCurrentTime(),
 		},
 	}
+	// 字段级校验失败放入错误详情
+	if violations := biz.FieldViolationsFromError(err); len(violations) > 0 {
+		resp.Details = map[string]interface{}{biz.MetadataFieldViolations: violations}
+	}
+	return resp
 }
 
 // Helper functions (在实际项目中可能需要从上下文或请求中获取)
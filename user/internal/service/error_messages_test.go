@@ -0,0 +1,65 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListErrorReasons 测试错误原因列表包含正确的映射且没有重复
+func TestListErrorReasons(t *testing.T) {
+	infos := ListErrorReasons()
+	require.NotEmpty(t, infos)
+
+	byReason := make(map[string]ErrorReasonInfo, len(infos))
+	for _, info := range infos {
+		_, duplicated := byReason[info.Reason]
+		assert.False(t, duplicated, "错误原因重复: %s", info.Reason)
+		byReason[info.Reason] = info
+	}
+
+	tests := []struct {
+		name   string
+		reason string
+		want   ErrorReasonInfo
+	}{
+		{
+			name:   "访问令牌无效",
+			reason: "USER_INVALID_TOKEN",
+			want: ErrorReasonInfo{
+				Reason:         "USER_INVALID_TOKEN",
+				HTTPStatus:     http.StatusUnauthorized,
+				BusinessCode:   USER_ERR_TOKEN_INVALID,
+				DefaultMessage: "访问令牌无效，请重新登录",
+			},
+		},
+		{
+			name:   "没有业务错误码的原因",
+			reason: "USER_PERMISSION_DENIED",
+			want: ErrorReasonInfo{
+				Reason:         "USER_PERMISSION_DENIED",
+				HTTPStatus:     http.StatusForbidden,
+				DefaultMessage: "无权访问该资源",
+			},
+		},
+		{
+			name:   "认证服务错误原因",
+			reason: "AUTH_TOKEN_EXPIRED",
+			want: ErrorReasonInfo{
+				Reason:         "AUTH_TOKEN_EXPIRED",
+				HTTPStatus:     http.StatusUnauthorized,
+				DefaultMessage: "访问令牌已过期",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := byReason[tt.reason]
+			require.True(t, ok, "列表中缺少 %s", tt.reason)
+			assert.Equal(t, tt.want, info)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldWhitelist 允许通过 fields 查询参数选择的响应字段（proto 字段名）
+type fieldWhitelist map[string]struct{}
+
+func newFieldWhitelist(names ...string) fieldWhitelist {
+	w := make(fieldWhitelist, len(names))
+	for _, name := range names {
+		w[name] = struct{}{}
+	}
+	return w
+}
+
+// currentUserFields GetCurrentUser 可选择的字段
+var currentUserFields = newFieldWhitelist("id", "public_id", "email", "nickname", "avatar_url", "is_premium", "created_at", "updated_at")
+
+// pointTransactionFields ListTransactions 中每条流水可选择的字段
+var pointTransactionFields = newFieldWhitelist("id", "type", "amount", "related_book_id", "description", "created_at")
+
+// parse 解析逗号分隔的字段列表，忽略空白和不在白名单中的字段名
+// 没有任何有效字段时返回 nil，表示返回全部字段
+func (w fieldWhitelist) parse(fields string) map[string]struct{} {
+	var selected map[string]struct{}
+	for _, name := range strings.Split(fields, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := w[name]; !ok {
+			continue
+		}
+		if selected == nil {
+			selected = make(map[string]struct{})
+		}
+		selected[name] = struct{}{}
+	}
+	return selected
+}
+
+// selectFields 清除消息中未被选中的字段，selected 为 nil 时保持原样
+func selectFields(msg proto.Message, selected map[string]struct{}) {
+	if selected == nil {
+		return
+	}
+	m := msg.ProtoReflect()
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if _, ok := selected[string(fd.Name())]; !ok {
+			m.Clear(fd)
+		}
+		return true
+	})
+}
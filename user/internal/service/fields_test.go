@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	v1 "user/api/user/v1"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestSelectFields 测试按 fields 参数只保留白名单中被选中的字段
+func TestSelectFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		want   *v1.GetCurrentUserResponse
+	}{
+		{
+			name:   "只返回 id 和 nickname",
+			fields: "id,nickname",
+			want:   &v1.GetCurrentUserResponse{Id: 1, Nickname: "tester"},
+		},
+		{
+			name:   "忽略空白和未知字段",
+			fields: " id , password_hash,,nickname ",
+			want:   &v1.GetCurrentUserResponse{Id: 1, Nickname: "tester"},
+		},
+		{
+			name:   "没有有效字段时返回全部",
+			fields: "password_hash",
+			want:   newTestCurrentUserResponse(),
+		},
+		{
+			name:   "未指定字段时返回全部",
+			fields: "",
+			want:   newTestCurrentUserResponse(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply := newTestCurrentUserResponse()
+			selectFields(reply, currentUserFields.parse(tt.fields))
+			assert.Equal(t, tt.want.String(), reply.String())
+		})
+	}
+}
+
+func newTestCurrentUserResponse() *v1.GetCurrentUserResponse {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &v1.GetCurrentUserResponse{
+		Id:        1,
+		PublicId:  "usr_abc",
+		Email:     "test@example.com",
+		Nickname:  "tester",
+		AvatarUrl: "https://example.com/a.png",
+		IsPremium: true,
+		CreatedAt: timestamppb.New(created),
+		UpdatedAt: timestamppb.New(created),
+	}
+}
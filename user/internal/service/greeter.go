@@ -0,0 +1,34 @@
+//go:build !nogreeter
+
+package service
+
+import (
+	"context"
+
+	v1 "user/api/helloworld/v1"
+	"user/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// GreeterService 示例 Greeter 服务，仅用于本地演示
+type GreeterService struct {
+	v1.UnimplementedGreeterServer
+
+	logger *log.Helper
+}
+
+// NewGreeterService 创建 GreeterService 实例，配置未开启时返回 nil，由 server 跳过注册
+// 使用 nogreeter 构建标签编译时整个 Greeter 服务不会被编译进二进制
+func NewGreeterService(c *conf.Server, logger log.Logger) *GreeterService {
+	if !c.GetEnableGreeter() {
+		return nil
+	}
+	return &GreeterService{logger: log.NewHelper(logger)}
+}
+
+// SayHello 返回问候语
+func (s *GreeterService) SayHello(ctx context.Context, req *v1.HelloRequest) (*v1.HelloReply, error) {
+	s.logger.WithContext(ctx).Infof("SayHello received: %s", req.Name)
+	return &v1.HelloReply{Message: "Hello " + req.Name}, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+
+	v1 "user/api/user/v1"
+	"user/internal/biz"
+	"user/internal/pkg/tracing"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ValidatePersonalToken 校验个人访问令牌及其权限范围，返回令牌所属用户ID，供 server.UserIdentity 在进程内认证
+func (s *UserService) ValidatePersonalToken(ctx context.Context, plaintext, requiredScope string) (int64, error) {
+	token, err := s.personalTokens.ValidatePersonalToken(ctx, plaintext, requiredScope)
+	if err != nil {
+		return 0, err
+	}
+	return token.UserID, nil
+}
+
+// CreatePersonalToken 为当前用户创建个人访问令牌
+func (s *UserService) CreatePersonalToken(ctx context.Context, req *v1.CreatePersonalTokenRequest) (*v1.CreatePersonalTokenResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.CreatePersonalToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "create_personal_token",
+	})
+
+	userID, err := ExtractUserID(ctx, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("CreatePersonalToken authentication failed: %v", err)
+		return nil, err
+	}
+
+	token, plaintext, err := s.personalTokens.CreatePersonalToken(ctx, userID, req.Name, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CreatePersonalTokenResponse{
+		Token:     toPersonalTokenReply(token),
+		Plaintext: plaintext,
+	}, nil
+}
+
+// ListPersonalTokens 列出当前用户未撤销的个人访问令牌
+func (s *UserService) ListPersonalTokens(ctx context.Context, req *v1.ListPersonalTokensRequest) (*v1.ListPersonalTokensResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.ListPersonalTokens")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_personal_tokens",
+	})
+
+	userID, err := ExtractUserID(ctx, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ListPersonalTokens authentication failed: %v", err)
+		return nil, err
+	}
+
+	tokens, err := s.personalTokens.ListPersonalTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*v1.PersonalToken, 0, len(tokens))
+	for _, token := range tokens {
+		items = append(items, toPersonalTokenReply(token))
+	}
+	return &v1.ListPersonalTokensResponse{Tokens: items}, nil
+}
+
+// RevokePersonalToken 撤销当前用户的个人访问令牌
+func (s *UserService) RevokePersonalToken(ctx context.Context, req *v1.RevokePersonalTokenRequest) (*v1.RevokePersonalTokenResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.RevokePersonalToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "revoke_personal_token",
+		"token_id":  req.Id,
+	})
+
+	userID, err := ExtractUserID(ctx, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("RevokePersonalToken authentication failed: %v", err)
+		return nil, err
+	}
+
+	if err := s.personalTokens.RevokePersonalToken(ctx, userID, req.Id); err != nil {
+		return nil, err
+	}
+	return &v1.RevokePersonalTokenResponse{}, nil
+}
+
+// RotatePersonalToken 重新生成当前用户的个人访问令牌
+func (s *UserService) RotatePersonalToken(ctx context.Context, req *v1.RotatePersonalTokenRequest) (*v1.RotatePersonalTokenResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.RotatePersonalToken")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "rotate_personal_token",
+		"token_id":  req.Id,
+	})
+
+	userID, err := ExtractUserID(ctx, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("RotatePersonalToken authentication failed: %v", err)
+		return nil, err
+	}
+
+	plaintext, err := s.personalTokens.RotatePersonalToken(ctx, userID, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RotatePersonalTokenResponse{Plaintext: plaintext}, nil
+}
+
+// toPersonalTokenReply 将令牌记录转换为响应结构，不包含令牌哈希
+func toPersonalTokenReply(token *biz.PersonalToken) *v1.PersonalToken {
+	reply := &v1.PersonalToken{
+		Id:        token.ID,
+		Name:      token.Name,
+		Scopes:    token.ScopeList(),
+		CreatedAt: timestamppb.New(token.CreatedAt),
+	}
+	if token.LastUsedAt != nil {
+		reply.LastUsedAt = timestamppb.New(*token.LastUsedAt)
+	}
+	return reply
+}
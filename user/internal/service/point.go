@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+
+	v1 "user/api/point/v1"
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"user/internal/pkg/tracing"
+)
+
+// PointService 实现 PointService 接口
+type PointService struct {
+	v1.UnimplementedPointServiceServer
+
+	pointUsecase *biz.PointUsecase
+	authConfig   biz.AuthConfig
+	logger       *log.Helper
+}
+
+// NewPointService 创建 PointService 实例
+func NewPointService(pointUsecase *biz.PointUsecase, authConfig biz.AuthConfig, logger log.Logger) *PointService {
+	return &PointService{
+		pointUsecase: pointUsecase,
+		authConfig:   authConfig,
+		logger:       log.NewHelper(logger),
+	}
+}
+
+// ListTransactions 获取当前用户点数流水
+func (s *PointService) ListTransactions(ctx context.Context, req *v1.ListTransactionsRequest) (*v1.ListTransactionsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointService.ListTransactions")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_transactions",
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	})
+
+	userID, err := ExtractUserID(ctx, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ListTransactions authentication failed: %v", err)
+		return nil, err
+	}
+
+	txns, pageInfo, err := s.pointUsecase.ListTransactions(ctx, userID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ListTransactions failed: %v", err)
+		return nil, err
+	}
+
+	selected := pointTransactionFields.parse(req.Fields)
+	items := make([]*v1.PointTransaction, 0, len(txns))
+	for _, txn := range txns {
+		item := toPointTransactionReply(txn)
+		selectFields(item, selected)
+		items = append(items, item)
+	}
+
+	return &v1.ListTransactionsResponse{
+		Transactions: items,
+		Pagination:   toPaginationReply(pageInfo),
+	}, nil
+}
+
+// ListBookTransactions 按绘本查询点数流水（跨用户，仅管理员）
+func (s *PointService) ListBookTransactions(ctx context.Context, req *v1.ListBookTransactionsRequest) (*v1.ListTransactionsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointService.ListBookTransactions")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_book_transactions",
+		"book_id":   req.BookId,
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	})
+
+	if _, err := RequireAdmin(ctx, s.authConfig, s.logger); err != nil {
+		s.logger.WithContext(ctx).Errorf("ListBookTransactions authorization failed: %v", err)
+		return nil, err
+	}
+
+	txns, pageInfo, err := s.pointUsecase.ListTransactionsByBook(ctx, req.BookId, int(req.Page), int(req.PageSize))
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ListBookTransactions failed: %v", err)
+		return nil, err
+	}
+
+	items := make([]*v1.PointTransaction, 0, len(txns))
+	for _, txn := range txns {
+		items = append(items, toPointTransactionReply(txn))
+	}
+
+	return &v1.ListTransactionsResponse{
+		Transactions: items,
+		Pagination:   toPaginationReply(pageInfo),
+	}, nil
+}
+
+// ListSourceTransactions 按流水来源查询点数流水（跨用户，仅管理员）
+func (s *PointService) ListSourceTransactions(ctx context.Context, req *v1.ListSourceTransactionsRequest) (*v1.ListTransactionsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointService.ListSourceTransactions")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_source_transactions",
+		"source":    req.Source,
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	})
+
+	if _, err := RequireAdmin(ctx, s.authConfig, s.logger); err != nil {
+		s.logger.WithContext(ctx).Errorf("ListSourceTransactions authorization failed: %v", err)
+		return nil, err
+	}
+
+	txns, pageInfo, err := s.pointUsecase.ListTransactionsBySource(ctx, req.Source, int(req.Page), int(req.PageSize))
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ListSourceTransactions failed: %v", err)
+		return nil, err
+	}
+
+	items := make([]*v1.PointTransaction, 0, len(txns))
+	for _, txn := range txns {
+		items = append(items, toPointTransactionReply(txn))
+	}
+
+	return &v1.ListTransactionsResponse{
+		Transactions: items,
+		Pagination:   toPaginationReply(pageInfo),
+	}, nil
+}
+
+// AdjustPoints 管理员调整用户点数余额，调整记录以当前管理员为操作人写入流水
+func (s *PointService) AdjustPoints(ctx context.Context, req *v1.AdjustPointsRequest) (*v1.AdjustPointsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointService.AdjustPoints")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "adjust_points",
+		"user_id":   req.UserId,
+		"delta":     req.Delta,
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("AdjustPoints authorization failed: %v", err)
+		return nil, err
+	}
+
+	point, err := s.pointUsecase.AdjustPoints(ctx, adminID, req.UserId, req.Delta, req.Description)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("AdjustPoints failed: %v", err)
+		return nil, err
+	}
+
+	return &v1.AdjustPointsResponse{
+		CurrentPoints: point.CurrentPoints,
+		TotalConsumed: point.TotalConsumed,
+	}, nil
+}
+
+// ArchiveTransactions 立即将超过保留期的点数流水移入归档表，仅管理员可调用
+func (s *PointService) ArchiveTransactions(ctx context.Context, req *v1.ArchiveTransactionsRequest) (*v1.ArchiveTransactionsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "PointService.ArchiveTransactions")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "archive_transactions",
+	})
+
+	if _, err := RequireAdmin(ctx, s.authConfig, s.logger); err != nil {
+		s.logger.WithContext(ctx).Errorf("ArchiveTransactions authorization failed: %v", err)
+		return nil, err
+	}
+
+	archived, err := s.pointUsecase.ArchiveExpiredTransactions(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ArchiveTransactions failed, archived: %d, error_reason: %v", archived, err)
+		return nil, err
+	}
+
+	return &v1.ArchiveTransactionsResponse{Archived: archived}, nil
+}
+
+// toPointTransactionReply 将点数流水转换为响应结构
+func toPointTransactionReply(txn *biz.PointTransaction) *v1.PointTransaction {
+	reply := &v1.PointTransaction{
+		Id:          txn.ID,
+		Type:        txn.Type,
+		Amount:      txn.Amount,
+		Description: txn.Description,
+		CreatedAt:   timestamppb.New(txn.CreatedAt),
+	}
+	if txn.RelatedBookID != nil {
+		reply.RelatedBookId = *txn.RelatedBookID
+	}
+	return reply
+}
+
+// toPaginationReply 将分页元信息转换为响应结构
+func toPaginationReply(info biz.PageInfo) *v1.Pagination {
+	return &v1.Pagination{
+		Page:       int32(info.Page),
+		PageSize:   int32(info.PageSize),
+		Total:      info.Total,
+		TotalPages: int32(info.TotalPages),
+		HasNext:    info.HasNext,
+		HasPrev:    info.HasPrev,
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	nethttp "net/http"
+	"strconv"
+	"time"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"user/internal/pkg/tracing"
+)
+
+// transactionCSVHeader 导出点数流水 CSV 的表头
+var transactionCSVHeader = []string{"id", "type", "amount", "related_book_id", "description", "created_at"}
+
+// ExportTransactionsCSV 以 CSV 格式流式下载当前用户的全部点数流水
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *PointService) ExportTransactionsCSV(ctx http.Context) error {
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return nil, s.exportTransactionsCSV(c, ctx.Response())
+	})
+	_, err := h(ctx, nil)
+	return err
+}
+
+// exportTransactionsCSV 校验用户身份后逐页写出流水，写出第一行之前的错误交给框架编码为错误响应
+func (s *PointService) exportTransactionsCSV(ctx context.Context, w http.ResponseWriter) error {
+	ctx, span := tracing.StartSpan(ctx, "PointService.ExportTransactionsCSV")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "export_transactions_csv",
+	})
+
+	userID, err := ExtractUserID(ctx, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ExportTransactionsCSV authentication failed: %v", err)
+		return err
+	}
+
+	out := newTransactionCSVWriter(w)
+	err = s.pointUsecase.ExportTransactions(ctx, userID, func(txns []*biz.PointTransaction) error {
+		return out.write(txns)
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ExportTransactionsCSV failed for user: %d, error_reason: %v", userID, err)
+		// 响应头已发出时无法再返回错误响应，只能中断输出
+		if out.started {
+			return nil
+		}
+		return err
+	}
+	// 没有流水时也输出表头
+	return out.write(nil)
+}
+
+// transactionCSVWriter 首次写入时发送响应头和表头，每页写完后立即刷新，避免在内存中累积
+type transactionCSVWriter struct {
+	w       http.ResponseWriter
+	csv     *csv.Writer
+	started bool
+}
+
+func newTransactionCSVWriter(w http.ResponseWriter) *transactionCSVWriter {
+	return &transactionCSVWriter{w: w, csv: csv.NewWriter(w)}
+}
+
+func (t *transactionCSVWriter) write(txns []*biz.PointTransaction) error {
+	if !t.started {
+		t.started = true
+		t.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		t.w.Header().Set("Content-Disposition", `attachment; filename="point_transactions.csv"`)
+		if err := t.csv.Write(transactionCSVHeader); err != nil {
+			return err
+		}
+	}
+
+	for _, txn := range txns {
+		if err := t.csv.Write(transactionCSVRecord(txn)); err != nil {
+			return err
+		}
+	}
+	t.csv.Flush()
+	if err := t.csv.Error(); err != nil {
+		return err
+	}
+	if f, ok := t.w.(nethttp.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// transactionCSVRecord 将点数流水转换为一行 CSV，未关联绘本时 related_book_id 为空
+func transactionCSVRecord(txn *biz.PointTransaction) []string {
+	relatedBookID := ""
+	if txn.RelatedBookID != nil {
+		relatedBookID = strconv.FormatInt(*txn.RelatedBookID, 10)
+	}
+	return []string{
+		strconv.FormatInt(txn.ID, 10),
+		txn.Type,
+		strconv.FormatUint(uint64(txn.Amount), 10),
+		relatedBookID,
+		txn.Description,
+		txn.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPointTransactionRepository 只实现 GetByUserID 的流水仓库，按页返回固定数据
+type stubPointTransactionRepository struct {
+	biz.PointTransactionRepository
+	txns []*biz.PointTransaction
+}
+
+func (r *stubPointTransactionRepository) GetByUserID(ctx context.Context, userID int64, page, pageSize int) ([]*biz.PointTransaction, int64, error) {
+	start := (page - 1) * pageSize
+	if start >= len(r.txns) {
+		return nil, int64(len(r.txns)), nil
+	}
+	end := start + pageSize
+	if end > len(r.txns) {
+		end = len(r.txns)
+	}
+	return r.txns[start:end], int64(len(r.txns)), nil
+}
+
+// TestPointService_ExportTransactionsCSV 测试导出的 CSV 包含表头且每条流水一行
+func TestPointService_ExportTransactionsCSV(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	bookID := int64(42)
+	fixtures := []*biz.PointTransaction{
+		{ID: 3, UserID: 1, Type: biz.TransactionTypeConsume, Amount: 10, RelatedBookID: &bookID, Description: "生成绘本", CreatedAt: createdAt},
+		{ID: 2, UserID: 1, Type: biz.TransactionTypeRecharge, Amount: 100, Description: "充值, 活动赠送", CreatedAt: createdAt},
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		txns        []*biz.PointTransaction
+		wantStatus  int
+		wantRecords [][]string
+	}{
+		{
+			name:       "导出全部流水",
+			userID:     "1",
+			txns:       fixtures,
+			wantStatus: nethttp.StatusOK,
+			wantRecords: [][]string{
+				transactionCSVHeader,
+				{"3", "CONSUME", "10", "42", "生成绘本", "2024-05-01T08:30:00Z"},
+				{"2", "RECHARGE", "100", "", "充值, 活动赠送", "2024-05-01T08:30:00Z"},
+			},
+		},
+		{
+			name:        "没有流水时只输出表头",
+			userID:      "1",
+			wantStatus:  nethttp.StatusOK,
+			wantRecords: [][]string{transactionCSVHeader},
+		},
+		{
+			name:       "未认证",
+			txns:       fixtures,
+			wantStatus: nethttp.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txnRepo := &stubPointTransactionRepository{txns: tt.txns}
+			uc := biz.NewPointUsecase(nil, txnRepo, nil, biz.Pagination{}, biz.PointConfig{}, biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger), log.DefaultLogger)
+			svc := NewPointService(uc, biz.AuthConfig{}, log.DefaultLogger)
+
+			srv := http.NewServer()
+			srv.Route("/").GET("/v1/points/transactions/export", svc.ExportTransactionsCSV)
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/v1/points/transactions/export", nil)
+			if tt.userID != "" {
+				req.Header.Set("X-User-ID", tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantRecords == nil {
+				return
+			}
+			assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+			records, err := csv.NewReader(rec.Body).ReadAll()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRecords, records)
+		})
+	}
+}
@@ -6,4 +6,6 @@ import "github.com/google/wire"
 var ProviderSet = wire.NewSet(
 	NewAuthService,
 	NewUserService,
+	NewPointService,
+	NewAccountIDFormatter,
 )
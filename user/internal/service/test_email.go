@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+// sendTestEmailRequest 诊断测试邮件请求体
+type sendTestEmailRequest struct {
+	Email string `json:"email"`
+}
+
+// sendTestEmailResponse 诊断测试邮件的投递结果
+type sendTestEmailResponse struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SendTestEmail 管理员使用实际配置的邮件服务向指定地址发送测试邮件，返回服务商处理结果和耗时
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *UserService) SendTestEmail(ctx http.Context) error {
+	var req sendTestEmailRequest
+	if err := ctx.Bind(&req); err != nil {
+		return error_reason.ErrorUserInvalidRequest("请求体格式错误")
+	}
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.sendTestEmail(c, req.Email)
+	})
+	out, err := h(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return ctx.Result(200, out)
+}
+
+// sendTestEmail 校验管理员身份后发送测试邮件
+func (s *UserService) sendTestEmail(ctx context.Context, email string) (interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.SendTestEmail")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "send_test_email",
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.userUsecase.SendTestEmail(ctx, adminID, email)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("SendTestEmail failed for user: %d, error_reason: %v", adminID, err)
+		return nil, err
+	}
+	return &sendTestEmailResponse{
+		Status:    result.Status,
+		LatencyMs: result.Latency.Milliseconds(),
+		Error:     result.Error,
+	}, nil
+}
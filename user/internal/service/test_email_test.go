@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"user/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTestEmailCodeRepository 只实现测试邮件限流的验证码仓库，始终放行
+type stubTestEmailCodeRepository struct {
+	biz.CodeRepository
+}
+
+func (stubTestEmailCodeRepository) CheckAndIncrTestEmailSends(ctx context.Context, userID int64, limit int, resetAt time.Time) (bool, error) {
+	return true, nil
+}
+
+// stubEmailLogRepository 忽略写入的邮件发送记录
+type stubEmailLogRepository struct{}
+
+func (stubEmailLogRepository) Create(ctx context.Context, emailLog *biz.EmailLog) error {
+	return nil
+}
+
+// recordingEmailSender 记录收件人并模拟服务商耗时的邮件发送器
+type recordingEmailSender struct {
+	delay time.Duration
+	sent  []string
+}
+
+func (s *recordingEmailSender) Send(ctx context.Context, message *biz.EmailMessage) error {
+	time.Sleep(s.delay)
+	s.sent = append(s.sent, message.ToEmail)
+	return nil
+}
+
+// TestUserService_SendTestEmail 测试管理员发送测试邮件返回服务商结果和耗时，非管理员和未认证请求被拒绝且不发送
+func TestUserService_SendTestEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		wantStatus int
+		wantSent   bool
+	}{
+		{
+			name:       "管理员发送成功",
+			userID:     "1",
+			wantStatus: nethttp.StatusOK,
+			wantSent:   true,
+		},
+		{
+			name:       "非管理员",
+			userID:     "2",
+			wantStatus: nethttp.StatusForbidden,
+		},
+		{
+			name:       "未认证",
+			wantStatus: nethttp.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingEmailSender{delay: 5 * time.Millisecond}
+			authConfig := biz.AuthConfig{AdminUserIDs: []int64{1}}
+			slowOp := biz.NewSlowOperationLogger(biz.NewSystemClock(), biz.SlowOperationConfig{}, log.DefaultLogger)
+			uc := biz.NewUserUsecase(nil, stubTestEmailCodeRepository{}, nil, nil, sender, stubEmailLogRepository{}, biz.EmailConfig{}, authConfig, nil, nil, slowOp, log.DefaultLogger)
+			svc := NewUserService(uc, nil, nil, nil, nil, authConfig, log.DefaultLogger)
+
+			srv := http.NewServer()
+			srv.Route("/").POST("/debug/test-email", svc.SendTestEmail)
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/debug/test-email", strings.NewReader(`{"email":"ops@example.com"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.userID != "" {
+				req.Header.Set("X-User-ID", tt.userID)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if !tt.wantSent {
+				assert.Empty(t, sender.sent)
+				return
+			}
+			assert.Equal(t, []string{"ops@example.com"}, sender.sent)
+			var resp sendTestEmailResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, biz.EmailLogStatusSent, resp.Status)
+			assert.GreaterOrEqual(t, resp.LatencyMs, int64(5))
+			assert.Empty(t, resp.Error)
+		})
+	}
+}
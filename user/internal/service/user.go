@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"strconv"
+	"time"
 
 	v1 "user/api/user/v1"
 	"user/internal/biz"
@@ -10,11 +10,11 @@ import (
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/transport/http"
 	"google.golang.org/protobuf/types/known/timestamppb"
-	"user/internal/pkg/tracing"
 	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
 )
 
-// ExtractUserID 从 HTTP 请求上下文中提取用户ID（由Nginx JWT校验后设置）
+// ExtractUserID 从 HTTP 请求上下文中提取用户ID（由Nginx JWT校验后设置；面向公网部署时由 server.UserIdentity 校验令牌后设置）
 func ExtractUserID(ctx context.Context, logger *log.Helper) (int64, error) {
 	ctx, span := tracing.StartSpan(ctx, "Service.ExtractUserID")
 	defer span.End()
@@ -37,8 +37,8 @@ func ExtractUserID(ctx context.Context, logger *log.Helper) (int64, error) {
 		return 0, error_reason.ErrorUserInvalidToken("用户认证信息缺失")
 	}
 
-	// 解析用户ID
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	// 解析用户ID，网关也可能透传编码后的对外ID
+	userID, err := ParseAccountID(userIDStr)
 	if err != nil {
 		logger.WithContext(ctx).Warnf("Invalid X-User-ID format: %s", userIDStr)
 		return 0, error_reason.ErrorUserInvalidToken("用户ID格式无效")
@@ -48,19 +48,44 @@ func ExtractUserID(ctx context.Context, logger *log.Helper) (int64, error) {
 	return userID, nil
 }
 
+// RequireAdmin 提取当前用户ID并校验其为管理员，非管理员返回 USER_PERMISSION_DENIED
+func RequireAdmin(ctx context.Context, authConfig biz.AuthConfig, logger *log.Helper) (int64, error) {
+	userID, err := ExtractUserID(ctx, logger)
+	if err != nil {
+		return 0, err
+	}
+
+	if !authConfig.IsAdmin(userID) {
+		logger.WithContext(ctx).Warnf("Non-admin user attempted admin operation, userID: %d", userID)
+		return 0, error_reason.ErrorUserPermissionDenied("无权访问该资源")
+	}
+
+	return userID, nil
+}
+
 // UserService 实现 UserService 接口
 type UserService struct {
 	v1.UnimplementedUserServiceServer
 
-	userUsecase *biz.UserUsecase
-	logger      *log.Helper
+	userUsecase    *biz.UserUsecase
+	exporter       *biz.UserDataExportUsecase
+	personalTokens *biz.PersonalTokenUsecase
+	maintenance    *biz.AccountMaintenanceUsecase
+	accountIDs     *AccountIDFormatter
+	authConfig     biz.AuthConfig
+	logger         *log.Helper
 }
 
 // NewUserService 创建 UserService 实例
-func NewUserService(userUsecase *biz.UserUsecase, logger log.Logger) *UserService {
+func NewUserService(userUsecase *biz.UserUsecase, exporter *biz.UserDataExportUsecase, personalTokens *biz.PersonalTokenUsecase, maintenance *biz.AccountMaintenanceUsecase, accountIDs *AccountIDFormatter, authConfig biz.AuthConfig, logger log.Logger) *UserService {
 	return &UserService{
-		userUsecase: userUsecase,
-		logger:      log.NewHelper(logger),
+		userUsecase:    userUsecase,
+		exporter:       exporter,
+		personalTokens: personalTokens,
+		maintenance:    maintenance,
+		accountIDs:     accountIDs,
+		authConfig:     authConfig,
+		logger:         log.NewHelper(logger),
 	}
 }
 
@@ -88,15 +113,19 @@ func (s *UserService) GetCurrentUser(ctx context.Context, req *v1.GetCurrentUser
 	}
 
 	s.logger.WithContext(ctx).Infof("Successfully retrieved current user with id: %d", user.ID)
-	return &v1.GetCurrentUserResponse{
-		Id:        user.ID,
+	id, publicID := s.accountIDs.Format(user.ID)
+	reply := &v1.GetCurrentUserResponse{
+		Id:        id,
+		PublicId:  publicID,
 		Email:     user.Email,
 		Nickname:  user.Nickname,
 		AvatarUrl: user.AvatarURL,
 		IsPremium: user.IsPremium == 1,
 		CreatedAt: timestamppb.New(user.CreatedAt),
 		UpdatedAt: timestamppb.New(user.UpdatedAt),
-	}, nil
+	}
+	selectFields(reply, currentUserFields.parse(req.Fields))
+	return reply, nil
 }
 
 // UpdateCurrentUser 更新当前用户资料
@@ -117,25 +146,24 @@ func (s *UserService) UpdateCurrentUser(ctx context.Context, req *v1.UpdateCurre
 	}
 
 	updateReq := &biz.UpdateUserRequest{
-		Nickname:  &req.Nickname,
 		AvatarURL: &req.AvatarUrl,
 	}
-
-	err = s.userUsecase.UpdateUser(ctx, userID, updateReq)
-	if err != nil {
-		s.logger.WithContext(ctx).Errorf("UpdateCurrentUser failed: %v", err)
-		return &v1.UpdateCurrentUserResponse{}, nil
+	// 未填写昵称时保留原昵称，只更新头像
+	if req.Nickname != "" {
+		updateReq.Nickname = &req.Nickname
 	}
 
-	user, err := s.userUsecase.GetUserByID(ctx, userID)
+	user, err := s.userUsecase.UpdateUser(ctx, userID, updateReq)
 	if err != nil {
-		s.logger.WithContext(ctx).Errorf("Failed to get updated user info: %v", err)
+		s.logger.WithContext(ctx).Errorf("UpdateCurrentUser failed: %v", err)
 		return &v1.UpdateCurrentUserResponse{}, nil
 	}
 
 	s.logger.WithContext(ctx).Infof("Successfully updated current user with id: %d", user.ID)
+	id, publicID := s.accountIDs.Format(user.ID)
 	return &v1.UpdateCurrentUserResponse{
-		Id:        user.ID,
+		Id:        id,
+		PublicId:  publicID,
 		Email:     user.Email,
 		Nickname:  user.Nickname,
 		AvatarUrl: user.AvatarURL,
@@ -144,3 +172,78 @@ func (s *UserService) UpdateCurrentUser(ctx context.Context, req *v1.UpdateCurre
 		UpdatedAt: timestamppb.New(user.UpdatedAt),
 	}, nil
 }
+
+// ListErrorReasons 列出所有错误原因及其映射（仅管理员）
+func (s *UserService) ListErrorReasons(ctx context.Context, req *v1.ListErrorReasonsRequest) (*v1.ListErrorReasonsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.ListErrorReasons")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_error_reasons",
+	})
+
+	if _, err := RequireAdmin(ctx, s.authConfig, s.logger); err != nil {
+		s.logger.WithContext(ctx).Errorf("ListErrorReasons authorization failed: %v", err)
+		return nil, err
+	}
+
+	infos := ListErrorReasons()
+	reasons := make([]*v1.ErrorReasonInfo, 0, len(infos))
+	for _, info := range infos {
+		reasons = append(reasons, &v1.ErrorReasonInfo{
+			Reason:         info.Reason,
+			HttpStatus:     int32(info.HTTPStatus),
+			BusinessCode:   info.BusinessCode,
+			DefaultMessage: info.DefaultMessage,
+		})
+	}
+	return &v1.ListErrorReasonsResponse{Reasons: reasons}, nil
+}
+
+// RevokeSessionsByIP 撤销所有用户中来源 IP 为指定地址的会话（仅管理员）
+func (s *UserService) RevokeSessionsByIP(ctx context.Context, req *v1.RevokeSessionsByIPRequest) (*v1.RevokeSessionsByIPResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.RevokeSessionsByIP")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "revoke_sessions_by_ip",
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("RevokeSessionsByIP authorization failed: %v", err)
+		return nil, err
+	}
+
+	revoked, err := s.userUsecase.RevokeSessionsByIP(ctx, req.Ip)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Admin %d revoked %d sessions for ip: %s", adminID, revoked, req.Ip)
+	return &v1.RevokeSessionsByIPResponse{Revoked: int32(revoked)}, nil
+}
+
+// RevokeAllTokens 使当前时间之前签发的所有访问令牌和刷新令牌失效（仅管理员）
+func (s *UserService) RevokeAllTokens(ctx context.Context, req *v1.RevokeAllTokensRequest) (*v1.RevokeAllTokensResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.RevokeAllTokens")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "revoke_all_tokens",
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("RevokeAllTokens authorization failed: %v", err)
+		return nil, err
+	}
+
+	validAfter, err := s.userUsecase.RevokeAllTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Admin %d revoked all tokens issued before %s", adminID, validAfter.Format(time.RFC3339))
+	return &v1.RevokeAllTokensResponse{TokensValidAfter: timestamppb.New(validAfter)}, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"user/internal/pkg/tracing"
+)
+
+// ExportUserData 以 JSON 附件下载当前用户的全部数据，处理数据主体访问请求
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *UserService) ExportUserData(ctx http.Context) error {
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.exportUserData(c)
+	})
+	out, err := h(ctx, nil)
+	if err != nil {
+		return err
+	}
+	ctx.Response().Header().Set("Content-Disposition", `attachment; filename="user_data.json"`)
+	return ctx.Result(200, out)
+}
+
+// exportUserData 校验用户身份后汇总导出数据
+func (s *UserService) exportUserData(ctx context.Context) (interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.ExportUserData")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "export_user_data",
+	})
+
+	userID, err := ExtractUserID(ctx, s.logger)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ExportUserData authentication failed: %v", err)
+		return nil, err
+	}
+
+	export, err := s.exporter.ExportUserData(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ExportUserData failed for user: %d, error_reason: %v", userID, err)
+		return nil, err
+	}
+	return export, nil
+}
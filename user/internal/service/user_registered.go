@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	error_reason "user/api/error_reason"
+	"user/internal/pkg/tracing"
+)
+
+// registeredUser 按注册时间查询返回的用户，只含非敏感字段
+type registeredUser struct {
+	ID        int64     `json:"id,omitempty"`
+	PublicID  string    `json:"public_id,omitempty"`
+	Email     string    `json:"email"`
+	Nickname  string    `json:"nickname"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// registeredUsersResponse 按注册时间查询用户的分页结果
+type registeredUsersResponse struct {
+	Users      []*registeredUser `json:"users"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	Total      int64             `json:"total"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// ListRegisteredUsers 管理员分页查询在指定时间窗口内注册的用户，from、to 为 RFC 3339 时间
+// 不在 proto 中定义，由 server 通过 Route 直接注册为 HTTP 处理函数
+func (s *UserService) ListRegisteredUsers(ctx http.Context) error {
+	query := ctx.Request().URL.Query()
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return s.listRegisteredUsers(c, query.Get("from"), query.Get("to"), query.Get("page"), query.Get("page_size"))
+	})
+	out, err := h(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return ctx.Result(200, out)
+}
+
+// listRegisteredUsers 校验管理员身份和查询参数后查询用户
+func (s *UserService) listRegisteredUsers(ctx context.Context, fromStr, toStr, pageStr, pageSizeStr string) (interface{}, error) {
+	ctx, span := tracing.StartSpan(ctx, "UserService.ListRegisteredUsers")
+	defer span.End()
+
+	tracing.AddSpanTags(ctx, map[string]interface{}{
+		"operation": "list_registered_users",
+		"from":      fromStr,
+		"to":        toStr,
+	})
+
+	adminID, err := RequireAdmin(ctx, s.authConfig, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		return nil, error_reason.ErrorUserInvalidRequest("from 必须为 RFC 3339 格式的时间")
+	}
+	to, err := time.Parse(time.RFC3339, toStr)
+	if err != nil {
+		return nil, error_reason.ErrorUserInvalidRequest("to 必须为 RFC 3339 格式的时间")
+	}
+	page, err := parseOptionalInt(pageStr)
+	if err != nil {
+		return nil, error_reason.ErrorUserInvalidRequest("页码必须为正整数")
+	}
+	pageSize, err := parseOptionalInt(pageSizeStr)
+	if err != nil {
+		return nil, error_reason.ErrorUserInvalidRequest("每页条数必须为正整数")
+	}
+
+	users, info, err := s.userUsecase.ListUsersRegisteredBetween(ctx, from, to, page, pageSize)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("ListRegisteredUsers failed for admin: %d, error_reason: %v", adminID, err)
+		return nil, err
+	}
+
+	reply := &registeredUsersResponse{
+		Users:      make([]*registeredUser, 0, len(users)),
+		Page:       info.Page,
+		PageSize:   info.PageSize,
+		Total:      info.Total,
+		TotalPages: info.TotalPages,
+	}
+	for _, user := range users {
+		id, publicID := s.accountIDs.Format(user.ID)
+		reply.Users = append(reply.Users, &registeredUser{
+			ID:        id,
+			PublicID:  publicID,
+			Email:     user.Email,
+			Nickname:  user.Nickname,
+			CreatedAt: user.CreatedAt,
+		})
+	}
+	return reply, nil
+}
+
+// parseOptionalInt 解析可选的整数查询参数，未传时返回 0
+func parseOptionalInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}